@@ -0,0 +1,103 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// thresholdbench implements a command that runs the DKG and signing protocols of the threshold signature schemes in
+// this module across a sweep of party counts and thresholds, and reports the time, the allocations, and the bytes of
+// serialized messages that every party sends in every round as CSV or JSON. The main goal of this tool is to compare
+// the protocols on the hardware that will run them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type config struct {
+	protocols  []string
+	ns         []int
+	ts         []int
+	iterations int
+	format     string
+	output     string
+}
+
+func main() {
+	cfg, err := parseCmdArgs()
+	if err != nil {
+		panic(err)
+	}
+
+	measurements, err := Run(cfg.protocols, cfg.ns, cfg.ts, cfg.iterations, os.Stderr)
+	if err != nil {
+		panic(err)
+	}
+
+	var w io.Writer = os.Stdout
+	if cfg.output != "" {
+		f, err := os.Create(cfg.output)
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := Write(w, cfg.format, measurements); err != nil {
+		panic(err)
+	}
+}
+
+func parseCmdArgs() (*config, error) {
+	pFlag := flag.String("protocols", strings.Join(ProtocolNames(), ","), "The comma-separated protocols to run.")
+	nFlag := flag.String("n", "2,3,5", "The comma-separated numbers of parties.")
+	tFlag := flag.String("t", "", "The comma-separated numbers of parties that sign. Every number from 2 to n if empty.")
+	iFlag := flag.Int("iterations", 1, "The number of runs that every measurement averages.")
+	fFlag := flag.String("format", "csv", "The output format, csv or json.")
+	oFlag := flag.String("o", "", "The path of the output file. The standard output if empty.")
+	flag.Parse()
+
+	ns, err := parseInts(*nFlag)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the numbers of parties %v", err)
+	}
+	ts, err := parseInts(*tFlag)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the thresholds %v", err)
+	}
+	if *iFlag < 1 {
+		return nil, fmt.Errorf("the number of iterations must be positive")
+	}
+	if *fFlag != "csv" && *fFlag != "json" {
+		return nil, fmt.Errorf("unknown format %s", *fFlag)
+	}
+	return &config{
+		protocols:  strings.Split(*pFlag, ","),
+		ns:         ns,
+		ts:         ts,
+		iterations: *iFlag,
+		format:     *fFlag,
+		output:     *oFlag,
+	}, nil
+}
+
+// parseInts parses a comma-separated list of integers, in which the empty string is the empty list
+func parseInts(list string) ([]int, error) {
+	if list == "" {
+		return nil, nil
+	}
+	var values []int
+	for _, field := range strings.Split(list, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,173 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/protocol"
+)
+
+// requireTraffic checks that every party sent something in the phase
+func requireTraffic(t *testing.T, m *Measurement, parties int) {
+	require.NotEmpty(t, m.Traffic)
+	sent := make(map[uint32]int)
+	for _, rt := range m.Traffic {
+		require.NotEmpty(t, rt.Round)
+		sent[rt.Party] += rt.Bytes
+	}
+	require.Len(t, sent, parties)
+	for party, bytes := range sent {
+		require.Positive(t, bytes, "party %d sent nothing", party)
+	}
+}
+
+func TestRun(t *testing.T) {
+	measurements, err := Run([]string{"tschnorr", "cetsm2", "dkls"}, []int{2, 3}, nil, 2, io.Discard)
+	require.NoError(t, err)
+
+	// the n-of-n protocols skip n=3 t=2, and dkls only runs with two parties
+	var configs []string
+	for _, m := range measurements {
+		require.Equal(t, 2, m.Iterations)
+		require.Positive(t, m.NsPerOp)
+		require.Positive(t, m.AllocsPerOp)
+		require.Positive(t, m.AllocBytesPerOp)
+		require.Equal(t, m.N, m.T)
+		requireTraffic(t, m, m.N)
+		configs = append(configs, m.Protocol+"/"+m.Phase+"/"+string(rune('0'+m.N)))
+	}
+	require.Equal(t, []string{
+		"tschnorr/dkg/2", "tschnorr/sign/2", "tschnorr/dkg/3", "tschnorr/sign/3",
+		"cetsm2/dkg/2", "cetsm2/sign/2", "cetsm2/dkg/3", "cetsm2/sign/3",
+		"dkls/dkg/2", "dkls/sign/2",
+	}, configs)
+
+	// tschnorr sends a commitment and a proof in the DKG, and adds a partial signature in signing
+	require.Equal(t, []string{"1", "1", "2", "2"}, rounds(measurements[0]))
+	require.Equal(t, []string{"1", "1", "2", "2", "3", "3"}, rounds(measurements[1]))
+}
+
+func rounds(m *Measurement) []string {
+	var result []string
+	for _, rt := range m.Traffic {
+		result = append(result, rt.Round)
+	}
+	return result
+}
+
+func TestRunAllProtocols(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+	measurements, err := Run(ProtocolNames(), []int{2}, []int{2}, 1, io.Discard)
+	require.NoError(t, err)
+	require.Len(t, measurements, 2*len(ProtocolNames()))
+	for _, m := range measurements {
+		requireTraffic(t, m, 2)
+	}
+}
+
+func TestRunThreshold(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+	measurements, err := Run([]string{"gg20", "crtsm2"}, []int{3}, []int{2}, 1, io.Discard)
+	require.NoError(t, err)
+	require.Len(t, measurements, 4)
+	for i := 0; i < len(measurements); i += 2 {
+		requireTraffic(t, measurements[i], 3)
+		requireTraffic(t, measurements[i+1], 2)
+	}
+
+	// the threshold DKG of crtsm2 ends with the dealing of the shares
+	require.Equal(t, "crtsm2", measurements[2].Protocol)
+	require.Contains(t, rounds(measurements[2]), "deal-1")
+	require.Contains(t, rounds(measurements[2]), "deal-2")
+}
+
+func TestRunErrors(t *testing.T) {
+	_, err := Run([]string{"unknown"}, []int{2}, nil, 1, io.Discard)
+	require.Error(t, err)
+	_, err = Run([]string{"tschnorr"}, []int{2}, nil, 0, io.Discard)
+	require.Error(t, err)
+}
+
+func TestWrite(t *testing.T) {
+	measurements := []*Measurement{
+		{
+			Protocol: "tschnorr", N: 2, T: 2, Phase: phaseDkg, Iterations: 1, NsPerOp: 10, AllocsPerOp: 2, AllocBytesPerOp: 3,
+			Traffic: []*RoundTraffic{{Round: "1", Party: 1, Bytes: 32}, {Round: "1", Party: 2, Bytes: 33}},
+		},
+		{Protocol: "tschnorr", N: 2, T: 2, Phase: phaseSign, Iterations: 1, NsPerOp: 20},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, "csv", measurements))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"protocol", "n", "t", "phase", "iterations", "ns_per_op", "allocs_per_op", "alloc_bytes_per_op", "round", "party", "bytes"},
+		{"tschnorr", "2", "2", "dkg", "1", "10", "2", "3", "1", "1", "32"},
+		{"tschnorr", "2", "2", "dkg", "1", "10", "2", "3", "1", "2", "33"},
+		{"tschnorr", "2", "2", "sign", "1", "20", "0", "0", "", "", ""},
+	}, records)
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, "json", measurements))
+	var decoded []*Measurement
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, measurements, decoded)
+
+	require.Error(t, Write(&buf, "xml", measurements))
+}
+
+func TestRoute(t *testing.T) {
+	tr := newTraffic()
+	outputs := map[uint32]*protocol.Message{
+		1: {Payloads: map[string][]byte{broadcastKey: make([]byte, 10)}, Metadata: map[string]string{roundKey: "a"}},
+		2: {Payloads: map[string][]byte{"1": make([]byte, 3), "3": make([]byte, 4)}},
+		3: nil,
+	}
+	inputs, err := route(outputs, 7, tr)
+	require.NoError(t, err)
+	require.Len(t, inputs[1].Payloads, 1)
+	require.Len(t, inputs[1].Payloads["2"], 3)
+	require.Len(t, inputs[2].Payloads, 1)
+	require.Len(t, inputs[2].Payloads["1"], 10)
+	require.Len(t, inputs[3].Payloads, 2)
+	require.Len(t, inputs[3].Payloads["1"], 10)
+	require.Len(t, inputs[3].Payloads["2"], 4)
+
+	// the broadcast counts once for each of the two recipients
+	traffic := tr.average(1)
+	require.ElementsMatch(t, []*RoundTraffic{
+		{Round: "a", Party: 1, Bytes: 20},
+		{Round: "7", Party: 2, Bytes: 7},
+	}, traffic)
+
+	outputs[2].Payloads["4"] = nil
+	_, err = route(outputs, 7, tr)
+	require.Error(t, err)
+}
+
+func TestParseInts(t *testing.T) {
+	values, err := parseInts("2, 3,5")
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 3, 5}, values)
+	values, err = parseInts("")
+	require.NoError(t, err)
+	require.Empty(t, values)
+	_, err = parseInts("2,x")
+	require.Error(t, err)
+}
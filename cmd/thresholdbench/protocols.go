@@ -0,0 +1,158 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/core/protocol"
+)
+
+const (
+	broadcastKey = "broadcast"
+	roundKey     = "round"
+)
+
+// The message that every protocol signs
+var message = []byte("thresholdbench message")
+
+// session is one run of a protocol by a fixed set of parties, whose phases are measured separately
+type session interface {
+	// dkg runs the DKG of every party and counts the messages they send
+	dkg(t *traffic) error
+	// sign runs the signing protocol of every signer and counts the messages they send
+	sign(t *traffic) error
+	// verify checks the signature outside of the measurements
+	verify() error
+}
+
+// protocolBench creates the sessions of a protocol
+type protocolBench struct {
+	name string
+	// supports reports whether the protocol runs with n parties of which t sign
+	supports func(n int, t int) bool
+	// newSession sets up n parties of which t sign, outside of the measurements
+	newSession func(n int, t int) (session, error)
+}
+
+var protocolBenches = map[string]*protocolBench{
+	"crtsm2":   {name: "crtsm2", supports: tOfN, newSession: newCrtSm2Session},
+	"cetsm2":   {name: "cetsm2", supports: nOfN, newSession: newCetSm2Session},
+	"lnr18":    {name: "lnr18", supports: nOfN, newSession: newLnr18Session},
+	"tschnorr": {name: "tschnorr", supports: nOfN, newSession: newTSchnorrSession},
+	"dkls":     {name: "dkls", supports: twoOfTwo, newSession: newDklsSession},
+	"gg20":     {name: "gg20", supports: tOfN, newSession: newGg20Session},
+}
+
+// ProtocolNames returns the names of the protocols that Run accepts
+func ProtocolNames() []string {
+	names := make([]string, 0, len(protocolBenches))
+	for name := range protocolBenches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// nOfN is for the protocols in which every party signs
+func nOfN(n int, t int) bool { return n >= 2 && t == n }
+
+// twoOfTwo is for the two-party protocols
+func twoOfTwo(n int, t int) bool { return n == 2 && t == 2 }
+
+// tOfN is for the protocols in which any t of the n parties sign
+func tOfN(n int, t int) bool { return t >= 2 && t <= n }
+
+// runIterators runs the protocol iterators of all parties in lockstep. Following the conventions of the tsm2
+// participants, broadcast payloads are delivered to every other party and the other payloads to the party whose
+// identifier is their key, in both cases keyed by the identifier of the sender.
+func runIterators(iterators map[uint32]protocol.Iterator, t *traffic) error {
+	var inputs map[uint32]*protocol.Message
+	for step := 1; ; step++ {
+		outputs := make(map[uint32]*protocol.Message, len(iterators))
+		finished := 0
+		for id, iterator := range iterators {
+			output, err := iterator.Next(inputs[id])
+			if err == protocol.ErrProtocolFinished {
+				finished++
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "party %d in step %d", id, step)
+			}
+			outputs[id] = output
+		}
+		if finished == len(iterators) {
+			return nil
+		}
+		if finished != 0 {
+			return fmt.Errorf("the parties finished at different steps")
+		}
+		var err error
+		if inputs, err = route(outputs, step, t); err != nil {
+			return err
+		}
+	}
+}
+
+// route delivers the outputs of a step and counts their bytes
+func route(outputs map[uint32]*protocol.Message, step int, t *traffic) (map[uint32]*protocol.Message, error) {
+	inputs := make(map[uint32]*protocol.Message, len(outputs))
+	for id := range outputs {
+		inputs[id] = &protocol.Message{Version: protocol.Version1, Payloads: map[string][]byte{}}
+	}
+	for from, output := range outputs {
+		if output == nil {
+			continue
+		}
+		round := roundLabel(output, step)
+		sender := strconv.FormatUint(uint64(from), 10)
+		for key, payload := range output.Payloads {
+			if key == broadcastKey {
+				for to := range outputs {
+					if to != from {
+						inputs[to].Payloads[sender] = payload
+						t.add(round, from, len(payload))
+					}
+				}
+				continue
+			}
+			to, err := strconv.ParseUint(key, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("party %d sent a payload to %s", from, key)
+			}
+			input, ok := inputs[uint32(to)]
+			if !ok {
+				return nil, fmt.Errorf("party %d sent a payload to the unknown party %d", from, to)
+			}
+			input.Payloads[sender] = payload
+			t.add(round, from, len(payload))
+		}
+	}
+	return inputs, nil
+}
+
+// roundLabel names the round of a message after its metadata, or after the step that produced it
+func roundLabel(msg *protocol.Message, step int) string {
+	if round, ok := msg.Metadata[roundKey]; ok {
+		return round
+	}
+	return strconv.Itoa(step)
+}
+
+// messageSize is the number of bytes of the payloads of a message
+func messageSize(msg *protocol.Message) int {
+	size := 0
+	for _, payload := range msg.Payloads {
+		size += len(payload)
+	}
+	return size
+}
@@ -0,0 +1,256 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	phaseDkg  = "dkg"
+	phaseSign = "sign"
+)
+
+// Measurement is the cost of one phase of a protocol run by N parties of which T sign, averaged over the iterations
+type Measurement struct {
+	Protocol        string          `json:"protocol"`
+	N               int             `json:"n"`
+	T               int             `json:"t"`
+	Phase           string          `json:"phase"`
+	Iterations      int             `json:"iterations"`
+	NsPerOp         int64           `json:"ns_per_op"`
+	AllocsPerOp     uint64          `json:"allocs_per_op"`
+	AllocBytesPerOp uint64          `json:"alloc_bytes_per_op"`
+	Traffic         []*RoundTraffic `json:"traffic"`
+}
+
+// RoundTraffic is the number of bytes of serialized messages that a party sends in a round.
+// A broadcast counts once for every recipient, as it would over point-to-point channels.
+type RoundTraffic struct {
+	Round string `json:"round"`
+	Party uint32 `json:"party"`
+	Bytes int    `json:"bytes"`
+}
+
+// traffic accumulates the bytes that every party sends in every round, in the order in which the rounds first occur
+type traffic struct {
+	mu     sync.Mutex
+	rounds []string
+	bytes  map[string]map[uint32]int
+}
+
+func newTraffic() *traffic {
+	return &traffic{bytes: make(map[string]map[uint32]int)}
+}
+
+// add counts n bytes that party sent in round
+func (t *traffic) add(round string, party uint32, n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.bytes[round]; !ok {
+		t.rounds = append(t.rounds, round)
+		t.bytes[round] = make(map[uint32]int)
+	}
+	t.bytes[round][party] += n
+}
+
+// merge adds the traffic of other, whose rounds follow those of t
+func (t *traffic) merge(other *traffic) {
+	for _, round := range other.rounds {
+		for party, n := range other.bytes[round] {
+			t.add(round, party, n)
+		}
+	}
+}
+
+// average returns the traffic of every party in every round divided by iterations
+func (t *traffic) average(iterations int) []*RoundTraffic {
+	var result []*RoundTraffic
+	for _, round := range t.rounds {
+		parties := make([]uint32, 0, len(t.bytes[round]))
+		for party := range t.bytes[round] {
+			parties = append(parties, party)
+		}
+		sort.Slice(parties, func(i, j int) bool { return parties[i] < parties[j] })
+		for _, party := range parties {
+			result = append(result, &RoundTraffic{
+				Round: round,
+				Party: party,
+				Bytes: t.bytes[round][party] / iterations,
+			})
+		}
+	}
+	return result
+}
+
+// cost accumulates the time and the allocations of a phase over the iterations
+type cost struct {
+	elapsed    time.Duration
+	allocs     uint64
+	allocBytes uint64
+}
+
+// measure runs f and adds its time and allocations to c
+func (c *cost) measure(f func() error) error {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	err := f()
+	c.elapsed += time.Since(start)
+	runtime.ReadMemStats(&after)
+	c.allocs += after.Mallocs - before.Mallocs
+	c.allocBytes += after.TotalAlloc - before.TotalAlloc
+	return err
+}
+
+// Run measures the DKG and the signing of every protocol for every number of parties in ns and every number of signers
+// in ts, or every number of signers from 2 to n if ts is empty. The combinations that a protocol does not support are
+// skipped. Progress is logged to log.
+func Run(protocols []string, ns []int, ts []int, iterations int, log io.Writer) ([]*Measurement, error) {
+	if iterations < 1 {
+		return nil, fmt.Errorf("the number of iterations must be positive")
+	}
+	benches := make([]*protocolBench, 0, len(protocols))
+	for _, name := range protocols {
+		bench, ok := protocolBenches[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown protocol %s", name)
+		}
+		benches = append(benches, bench)
+	}
+
+	var measurements []*Measurement
+	for _, bench := range benches {
+		for _, n := range ns {
+			signers := ts
+			if len(signers) == 0 {
+				for t := 2; t <= n; t++ {
+					signers = append(signers, t)
+				}
+			}
+			for _, t := range signers {
+				if !bench.supports(n, t) {
+					continue
+				}
+				_, _ = fmt.Fprintf(log, "running %s with n=%d t=%d\n", bench.name, n, t)
+				results, err := runBench(bench, n, t, iterations)
+				if err != nil {
+					return nil, errors.Wrapf(err, "%s with n=%d t=%d", bench.name, n, t)
+				}
+				measurements = append(measurements, results...)
+			}
+		}
+	}
+	return measurements, nil
+}
+
+// runBench measures the phases of a protocol run by n parties of which t sign
+func runBench(bench *protocolBench, n int, t int, iterations int) ([]*Measurement, error) {
+	var dkgCost, signCost cost
+	dkgTraffic, signTraffic := newTraffic(), newTraffic()
+	for i := 0; i < iterations; i++ {
+		s, err := bench.newSession(n, t)
+		if err != nil {
+			return nil, errors.Wrap(err, "setup")
+		}
+		if err = dkgCost.measure(func() error { return s.dkg(dkgTraffic) }); err != nil {
+			return nil, errors.Wrap(err, "dkg")
+		}
+		if err = signCost.measure(func() error { return s.sign(signTraffic) }); err != nil {
+			return nil, errors.Wrap(err, "sign")
+		}
+		if err = s.verify(); err != nil {
+			return nil, errors.Wrap(err, "verifying the signature")
+		}
+	}
+
+	newMeasurement := func(phase string, c *cost, tr *traffic) *Measurement {
+		return &Measurement{
+			Protocol:        bench.name,
+			N:               n,
+			T:               t,
+			Phase:           phase,
+			Iterations:      iterations,
+			NsPerOp:         c.elapsed.Nanoseconds() / int64(iterations),
+			AllocsPerOp:     c.allocs / uint64(iterations),
+			AllocBytesPerOp: c.allocBytes / uint64(iterations),
+			Traffic:         tr.average(iterations),
+		}
+	}
+	return []*Measurement{
+		newMeasurement(phaseDkg, &dkgCost, dkgTraffic),
+		newMeasurement(phaseSign, &signCost, signTraffic),
+	}, nil
+}
+
+// Write writes the measurements in the given format, csv or json
+func Write(w io.Writer, format string, measurements []*Measurement) error {
+	switch format {
+	case "csv":
+		return WriteCSV(w, measurements)
+	case "json":
+		return WriteJSON(w, measurements)
+	default:
+		return fmt.Errorf("unknown format %s", format)
+	}
+}
+
+// WriteJSON writes the measurements as a JSON array
+func WriteJSON(w io.Writer, measurements []*Measurement) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(measurements)
+}
+
+// WriteCSV writes one row for every round and party of every measurement, repeating the time and allocations of the
+// phase on each of its rows
+func WriteCSV(w io.Writer, measurements []*Measurement) error {
+	writer := csv.NewWriter(w)
+	header := []string{
+		"protocol", "n", "t", "phase", "iterations", "ns_per_op", "allocs_per_op", "alloc_bytes_per_op",
+		"round", "party", "bytes",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, m := range measurements {
+		phase := []string{
+			m.Protocol,
+			strconv.Itoa(m.N),
+			strconv.Itoa(m.T),
+			m.Phase,
+			strconv.Itoa(m.Iterations),
+			strconv.FormatInt(m.NsPerOp, 10),
+			strconv.FormatUint(m.AllocsPerOp, 10),
+			strconv.FormatUint(m.AllocBytesPerOp, 10),
+		}
+		if len(m.Traffic) == 0 {
+			if err := writer.Write(append(phase, "", "", "")); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, rt := range m.Traffic {
+			row := append(append([]string{}, phase...), rt.Round, strconv.FormatUint(uint64(rt.Party), 10), strconv.Itoa(rt.Bytes))
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,346 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/coinbase/kryptology/pkg/core"
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/protocol"
+	"github.com/coinbase/kryptology/pkg/paillier"
+	v1 "github.com/coinbase/kryptology/pkg/sharing/v1"
+	dkls "github.com/coinbase/kryptology/pkg/tecdsa/dkls/v1"
+	gg20 "github.com/coinbase/kryptology/pkg/tecdsa/gg20/participant"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
+)
+
+// The identifiers under which the traffic of the two DKLs18 parties is reported
+const (
+	aliceId = 1
+	bobId   = 2
+)
+
+// dklsSession runs the two DKLs18 parties, Alice and Bob
+type dklsSession struct {
+	curve     *curves.Curve
+	aliceDkg  *dkls.AliceDkg
+	bobDkg    *dkls.BobDkg
+	signature *curves.EcdsaSignature
+}
+
+func newDklsSession(_ int, _ int) (session, error) {
+	return &dklsSession{curve: curves.K256()}, nil
+}
+
+func (s *dklsSession) dkg(t *traffic) error {
+	s.aliceDkg = dkls.NewAliceDkg(s.curve, protocol.Version1)
+	s.bobDkg = dkls.NewBobDkg(s.curve, protocol.Version1)
+	// Bob starts the DKG
+	return runTwoParties(s.bobDkg, bobId, s.aliceDkg, aliceId, t)
+}
+
+func (s *dklsSession) sign(t *traffic) error {
+	aliceResult, err := s.aliceDkg.Result(protocol.Version1)
+	if err != nil {
+		return err
+	}
+	bobResult, err := s.bobDkg.Result(protocol.Version1)
+	if err != nil {
+		return err
+	}
+	aliceSign, err := dkls.NewAliceSign(s.curve, sha3.New256(), message, aliceResult, protocol.Version1)
+	if err != nil {
+		return err
+	}
+	bobSign, err := dkls.NewBobSign(s.curve, sha3.New256(), message, bobResult, protocol.Version1)
+	if err != nil {
+		return err
+	}
+	// Alice starts the signing
+	if err = runTwoParties(aliceSign, aliceId, bobSign, bobId, t); err != nil {
+		return err
+	}
+	result, err := bobSign.Result(protocol.Version1)
+	if err != nil {
+		return err
+	}
+	s.signature, err = dkls.DecodeSignature(result)
+	return err
+}
+
+func (s *dklsSession) verify() error {
+	digest := sha3.Sum256(message)
+	return verifyEcdsa(s.curve, s.aliceDkg.Alice.Output().PublicKey, digest[:], s.signature)
+}
+
+// runTwoParties runs the iterators of a two-party protocol, which alternate starting with first, and counts the
+// messages that each of them sends
+func runTwoParties(first protocol.Iterator, firstId uint32, second protocol.Iterator, secondId uint32, t *traffic) error {
+	var msg *protocol.Message
+	firstErr, secondErr := error(nil), error(nil)
+	for step := 1; firstErr != protocol.ErrProtocolFinished || secondErr != protocol.ErrProtocolFinished; step++ {
+		msg, firstErr = first.Next(msg)
+		if firstErr != nil && firstErr != protocol.ErrProtocolFinished {
+			return errors.Wrapf(firstErr, "party %d in step %d", firstId, step)
+		}
+		if msg != nil {
+			t.add(roundLabel(msg, step), firstId, messageSize(msg))
+		}
+
+		msg, secondErr = second.Next(msg)
+		if secondErr != nil && secondErr != protocol.ErrProtocolFinished {
+			return errors.Wrapf(secondErr, "party %d in step %d", secondId, step)
+		}
+		if msg != nil {
+			t.add(roundLabel(msg, step), secondId, messageSize(msg))
+		}
+	}
+	return nil
+}
+
+// gg20Session runs the GG20 participants, of which the first t sign. Their messages are counted in their JSON encoding.
+type gg20Session struct {
+	n, t      int
+	dkgs      map[uint32]*gg20.DkgParticipant
+	results   map[uint32]*gg20.DkgResult
+	hash      []byte
+	signature *curves.EcdsaSignature
+}
+
+func newGg20Session(n int, t int) (session, error) {
+	curve := btcec.S256()
+	s := &gg20Session{n: n, t: t, dkgs: make(map[uint32]*gg20.DkgParticipant, n)}
+	for _, id := range parties.Range(n) {
+		p, err := gg20.NewDkgParticipant(curve, id)
+		if err != nil {
+			return nil, err
+		}
+		s.dkgs[id] = p
+	}
+	hash, err := core.Hash(message, curve)
+	if err != nil {
+		return nil, err
+	}
+	s.hash = hash.Bytes()
+	return s, nil
+}
+
+func (s *gg20Session) dkg(t *traffic) error {
+	var err error
+	r1Bcast := make(map[uint32]*gg20.DkgRound1Bcast, s.n)
+	for id, p := range s.dkgs {
+		if r1Bcast[id], err = p.DkgRound1(uint32(s.t), uint32(s.n)); err != nil {
+			return errors.Wrapf(err, "party %d in round 1", id)
+		}
+		if err = countJSON(t, "1", id, s.n-1, r1Bcast[id]); err != nil {
+			return err
+		}
+	}
+
+	decommitments := make(map[uint32]*core.Witness, s.n)
+	r2P2p := make(map[uint32]map[uint32]*gg20.DkgRound2P2PSend, s.n)
+	for id, p := range s.dkgs {
+		bcast, p2p, err := p.DkgRound2(r1Bcast)
+		if err != nil {
+			return errors.Wrapf(err, "party %d in round 2", id)
+		}
+		decommitments[id], r2P2p[id] = bcast.Di, p2p
+		if err = countJSON(t, "2", id, s.n-1, bcast); err != nil {
+			return err
+		}
+		if err = countDirectJSON(t, "2", id, p2p); err != nil {
+			return err
+		}
+	}
+
+	r3Bcast := make(map[uint32]paillier.PsfProof, s.n)
+	for id, p := range s.dkgs {
+		shares := make(map[uint32]*v1.ShamirShare, s.n-1)
+		for from, p2p := range directTo(r2P2p, id) {
+			shares[from] = p2p.Xij
+		}
+		if r3Bcast[id], err = p.DkgRound3(decommitments, shares); err != nil {
+			return errors.Wrapf(err, "party %d in round 3", id)
+		}
+		if err = countJSON(t, "3", id, s.n-1, r3Bcast[id]); err != nil {
+			return err
+		}
+	}
+
+	s.results = make(map[uint32]*gg20.DkgResult, s.n)
+	for id, p := range s.dkgs {
+		if s.results[id], err = p.DkgRound4(r3Bcast); err != nil {
+			return errors.Wrapf(err, "party %d in round 4", id)
+		}
+	}
+	return nil
+}
+
+func (s *gg20Session) sign(t *traffic) error {
+	cosigners := parties.Range(s.t)
+	signers := make(map[uint32]*gg20.Signer, s.t)
+	for _, id := range cosigners {
+		info, err := s.results[id].SignerData(id)
+		if err != nil {
+			return err
+		}
+		if signers[id], err = gg20.NewSigner(info, cosigners); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	r1Bcast := make(map[uint32]*gg20.Round1Bcast, s.t)
+	r1P2p := make(map[uint32]map[uint32]*gg20.Round1P2PSend, s.t)
+	for id, signer := range signers {
+		if r1Bcast[id], r1P2p[id], err = signer.SignRound1(); err != nil {
+			return errors.Wrapf(err, "party %d in round 1", id)
+		}
+		if err = countJSON(t, "1", id, s.t-1, r1Bcast[id]); err != nil {
+			return err
+		}
+		if err = countDirectJSON(t, "1", id, r1P2p[id]); err != nil {
+			return err
+		}
+	}
+
+	r2P2p := make(map[uint32]map[uint32]*gg20.P2PSend, s.t)
+	for id, signer := range signers {
+		if r2P2p[id], err = signer.SignRound2(others(r1Bcast, id), directTo(r1P2p, id)); err != nil {
+			return errors.Wrapf(err, "party %d in round 2", id)
+		}
+		if err = countDirectJSON(t, "2", id, r2P2p[id]); err != nil {
+			return err
+		}
+	}
+
+	r3Bcast := make(map[uint32]*gg20.Round3Bcast, s.t)
+	for id, signer := range signers {
+		if r3Bcast[id], err = signer.SignRound3(directTo(r2P2p, id)); err != nil {
+			return errors.Wrapf(err, "party %d in round 3", id)
+		}
+		if err = countJSON(t, "3", id, s.t-1, r3Bcast[id]); err != nil {
+			return err
+		}
+	}
+
+	r4Bcast := make(map[uint32]*gg20.Round4Bcast, s.t)
+	for id, signer := range signers {
+		if r4Bcast[id], err = signer.SignRound4(others(r3Bcast, id)); err != nil {
+			return errors.Wrapf(err, "party %d in round 4", id)
+		}
+		if err = countJSON(t, "4", id, s.t-1, r4Bcast[id]); err != nil {
+			return err
+		}
+	}
+
+	r5Bcast := make(map[uint32]*gg20.Round5Bcast, s.t)
+	r5P2p := make(map[uint32]map[uint32]*gg20.Round5P2PSend, s.t)
+	for id, signer := range signers {
+		if r5Bcast[id], r5P2p[id], err = signer.SignRound5(others(r4Bcast, id)); err != nil {
+			return errors.Wrapf(err, "party %d in round 5", id)
+		}
+		if err = countJSON(t, "5", id, s.t-1, r5Bcast[id]); err != nil {
+			return err
+		}
+		if err = countDirectJSON(t, "5", id, r5P2p[id]); err != nil {
+			return err
+		}
+	}
+
+	r6Bcast := make(map[uint32]*gg20.Round6FullBcast, s.t)
+	for id, signer := range signers {
+		if r6Bcast[id], err = signer.SignRound6Full(s.hash, others(r5Bcast, id), directTo(r5P2p, id)); err != nil {
+			return errors.Wrapf(err, "party %d in round 6", id)
+		}
+		if err = countJSON(t, "6", id, s.t-1, r6Bcast[id]); err != nil {
+			return err
+		}
+	}
+
+	for id, signer := range signers {
+		if s.signature, err = signer.SignOutput(others(r6Bcast, id)); err != nil {
+			return errors.Wrapf(err, "party %d in the output round", id)
+		}
+	}
+	return nil
+}
+
+func (s *gg20Session) verify() error {
+	if !curves.VerifyEcdsa(s.results[1].VerificationKey, s.hash, s.signature) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// others returns the values of every party but id
+func others[T any](values map[uint32]T, id uint32) map[uint32]T {
+	result := make(map[uint32]T, len(values))
+	for from, value := range values {
+		if from != id {
+			result[from] = value
+		}
+	}
+	return result
+}
+
+// directTo returns the values that every other party sent to id
+func directTo[T any](values map[uint32]map[uint32]T, id uint32) map[uint32]T {
+	result := make(map[uint32]T, len(values))
+	for from, sent := range values {
+		if value, ok := sent[id]; ok && from != id {
+			result[from] = value
+		}
+	}
+	return result
+}
+
+// countJSON counts the JSON encoding of a value that party sends to each of recipients parties
+func countJSON(t *traffic, round string, party uint32, recipients int, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrapf(err, "encoding the round %s message of party %d", round, party)
+	}
+	t.add(round, party, len(data)*recipients)
+	return nil
+}
+
+// countDirectJSON counts the JSON encodings of the values that party sends to each recipient
+func countDirectJSON[T any](t *traffic, round string, party uint32, values map[uint32]T) error {
+	for _, value := range values {
+		if err := countJSON(t, round, party, 1, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyEcdsa checks an ECDSA signature of digest under the public key pk
+func verifyEcdsa(curve *curves.Curve, pk curves.Point, digest []byte, signature *curves.EcdsaSignature) error {
+	ec, err := curve.ToEllipticCurve()
+	if err != nil {
+		return err
+	}
+	uncompressed := pk.ToAffineUncompressed()
+	size := (len(uncompressed) - 1) / 2
+	publicKey := &curves.EcPoint{
+		Curve: ec,
+		X:     new(big.Int).SetBytes(uncompressed[1 : 1+size]),
+		Y:     new(big.Int).SetBytes(uncompressed[1+size:]),
+	}
+	if !curves.VerifyEcdsa(publicKey, digest, signature) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
@@ -0,0 +1,415 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/protocol"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	mta_ot "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/ot"
+	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/sign_offline"
+	cetsm2 "github.com/coinbase/kryptology/pkg/tsm2/cetsm2/participant"
+	cetsm2verify "github.com/coinbase/kryptology/pkg/tsm2/cetsm2/verify"
+	lnr18 "github.com/coinbase/kryptology/pkg/tsm2/crtsm2/lnr18/participant"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/mta"
+	crtsm2 "github.com/coinbase/kryptology/pkg/tsm2/crtsm2/participant"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/tschnorr"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/verify"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
+)
+
+// The session id that the MtA setups and the proofs derive their session ids from
+var benchSessionId = []byte("thresholdbench")
+
+// crtSm2Session runs the CRT-SM2 participants, whose DKG starts with the setup of their OT-based MtA instances.
+// When not every party signs, the DKG ends with the Feldman dealing of x_i, gamma_i and d_i, and only the first t
+// parties sign.
+type crtSm2Session struct {
+	curve        *curves.Curve
+	signers      []uint32
+	participants map[uint32]*crtsm2.Participant[*mta_ot.Round1Output, *mta_ot.Round2Output]
+	dkgs         map[uint32]protocol.Iterator
+	signs        map[uint32]protocol.Iterator
+}
+
+func newCrtSm2Session(n int, t int) (session, error) {
+	curve := curves.SM2()
+	ids := parties.Range(n)
+	s := &crtSm2Session{
+		curve:        curve,
+		signers:      ids[:t],
+		participants: make(map[uint32]*crtsm2.Participant[*mta_ot.Round1Output, *mta_ot.Round2Output], n),
+	}
+	for _, id := range ids {
+		p, err := crtsm2.NewParticipantWithBackend[*mta_ot.Round1Output, *mta_ot.Round2Output](curve, id, ids, nil, mta.NewOT(curve), benchSessionId)
+		if err != nil {
+			return nil, err
+		}
+		if t < n {
+			if err = p.SetThreshold(t - 1); err != nil {
+				return nil, err
+			}
+		}
+		s.participants[id] = p
+	}
+	return s, nil
+}
+
+func (s *crtSm2Session) dkg(t *traffic) error {
+	s.dkgs = make(map[uint32]protocol.Iterator, len(s.participants))
+	for id, p := range s.participants {
+		s.dkgs[id] = crtsm2.NewDkg(p, protocol.Version1)
+	}
+	return runIterators(s.dkgs, t)
+}
+
+func (s *crtSm2Session) sign(t *traffic) error {
+	s.signs = make(map[uint32]protocol.Iterator, len(s.signers))
+	for _, id := range s.signers {
+		p := s.participants[id]
+		if err := p.SetSigners(s.signers); err != nil {
+			return err
+		}
+		s.signs[id] = crtsm2.NewSign(p, message, protocol.Version1)
+	}
+	return runIterators(s.signs, t)
+}
+
+func (s *crtSm2Session) verify() error {
+	id := s.signers[0]
+	result, err := s.dkgs[id].Result(protocol.Version1)
+	if err != nil {
+		return err
+	}
+	output, err := crtsm2.DecodeDkgOutput(result)
+	if err != nil {
+		return err
+	}
+	if result, err = s.signs[id].Result(protocol.Version1); err != nil {
+		return err
+	}
+	signature, err := crtsm2.DecodeSignature(result)
+	if err != nil {
+		return err
+	}
+	return verify.Verify(s.curve, nil, output.Q, sm2.DefaultSignerId, message, signature.R, signature.S)
+}
+
+// cetSm2Session runs the Onion-SM2 participants concurrently over an in-process network
+type cetSm2Session struct {
+	curve        *curves.Curve
+	participants map[uint32]*cetsm2.Participant
+	network      *network
+	jointPk      curves.Point
+	signature    *cetsm2.Signature
+}
+
+func newCetSm2Session(n int, _ int) (session, error) {
+	curve := curves.SM2()
+	s := &cetSm2Session{
+		curve:        curve,
+		participants: make(map[uint32]*cetsm2.Participant, n),
+		network:      newNetwork(),
+	}
+	ids := parties.Range(n)
+	for _, id := range ids {
+		p, err := cetsm2.NewParticipant(curve, id, ids, nil, &endpoint{network: s.network, id: id}, benchSessionId)
+		if err != nil {
+			return nil, err
+		}
+		s.participants[id] = p
+	}
+	return s, nil
+}
+
+func (s *cetSm2Session) dkg(t *traffic) error {
+	s.network.traffic = t
+	jointPks, err := runConcurrently(s.participants, s.network, (*cetsm2.Participant).DKG)
+	if err != nil {
+		return err
+	}
+	s.jointPk = jointPks[s.participants[1].Id()]
+	return nil
+}
+
+func (s *cetSm2Session) sign(t *traffic) error {
+	s.network.traffic = t
+	signatures, err := runConcurrently(s.participants, s.network, func(p *cetsm2.Participant) (*cetsm2.Signature, error) {
+		return p.Sign(message)
+	})
+	if err != nil {
+		return err
+	}
+	s.signature = signatures[s.participants[1].Id()]
+	return nil
+}
+
+func (s *cetSm2Session) verify() error {
+	return cetsm2verify.Verify(s.curve, nil, s.jointPk, sm2.DefaultSignerId, message, s.signature.R, s.signature.S)
+}
+
+// runConcurrently runs f for every participant in its own goroutine and closes the network as soon as one of them
+// fails, so that the others stop waiting for it
+func runConcurrently[T any](participants map[uint32]*cetsm2.Participant, net *network, f func(*cetsm2.Participant) (T, error)) (map[uint32]T, error) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	outputs := make(map[uint32]T, len(participants))
+	var firstErr error
+	for id, p := range participants {
+		wg.Add(1)
+		go func(id uint32, p *cetsm2.Participant) {
+			defer wg.Done()
+			output, err := f(p)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = errors.Wrapf(err, "party %d", id)
+				}
+				net.close()
+				return
+			}
+			outputs[id] = output
+		}(id, p)
+	}
+	wg.Wait()
+	return outputs, firstErr
+}
+
+// network delivers the messages of the in-process cetsm2 participants, one queue per ordered pair, and counts their
+// bytes
+type network struct {
+	mu      sync.Mutex
+	queues  map[[2]uint32]chan *protocol.Message
+	closed  chan struct{}
+	traffic *traffic
+}
+
+func newNetwork() *network {
+	return &network{queues: map[[2]uint32]chan *protocol.Message{}, closed: make(chan struct{})}
+}
+
+func (n *network) queue(from, to uint32) chan *protocol.Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	q, ok := n.queues[[2]uint32{from, to}]
+	if !ok {
+		q = make(chan *protocol.Message, 64)
+		n.queues[[2]uint32{from, to}] = q
+	}
+	return q
+}
+
+// close makes every pending and future Receive fail
+func (n *network) close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	select {
+	case <-n.closed:
+	default:
+		close(n.closed)
+	}
+}
+
+// endpoint is the cetsm2 Transport of a single party
+type endpoint struct {
+	*network
+	id uint32
+}
+
+func (e *endpoint) Send(to uint32, msg *protocol.Message) error {
+	e.traffic.add(msg.Metadata[roundKey], e.id, messageSize(msg))
+	select {
+	case e.queue(e.id, to) <- msg:
+		return nil
+	case <-e.closed:
+		return fmt.Errorf("network closed")
+	}
+}
+
+func (e *endpoint) Receive(from uint32) (*protocol.Message, error) {
+	select {
+	case msg := <-e.queue(from, e.id):
+		return msg, nil
+	case <-e.closed:
+		return nil, fmt.Errorf("network closed")
+	}
+}
+
+// lnr18Session runs the LNR18 participants. As they take their MtA instances ready-made, the DKG first runs the setups
+// of the OT-based instances of every ordered pair, whose rounds are counted as setup-1, setup-2 and so on.
+type lnr18Session struct {
+	curve        *curves.Curve
+	ids          []uint32
+	participants map[uint32]*lnr18.Participant[*mta_ot.Round1Output, *mta_ot.Round2Output]
+	signs        map[uint32]protocol.Iterator
+}
+
+func newLnr18Session(n int, _ int) (session, error) {
+	return &lnr18Session{curve: curves.K256(), ids: parties.Range(n)}, nil
+}
+
+func (s *lnr18Session) dkg(t *traffic) error {
+	senders, receivers, err := setupMtA(s.curve, s.ids, t)
+	if err != nil {
+		return err
+	}
+	s.participants = make(map[uint32]*lnr18.Participant[*mta_ot.Round1Output, *mta_ot.Round2Output], len(s.ids))
+	dkgs := make(map[uint32]protocol.Iterator, len(s.ids))
+	for _, id := range s.ids {
+		p, err := lnr18.NewParticipant(s.curve, id, s.ids, senders[id], receivers[id], benchSessionId)
+		if err != nil {
+			return err
+		}
+		s.participants[id] = p
+		dkgs[id] = lnr18.NewDkg(p, protocol.Version1)
+	}
+	return runIterators(dkgs, t)
+}
+
+func (s *lnr18Session) sign(t *traffic) error {
+	digest := sha256.Sum256(message)
+	s.signs = make(map[uint32]protocol.Iterator, len(s.participants))
+	for id, p := range s.participants {
+		s.signs[id] = lnr18.NewSign(p, digest[:], protocol.Version1)
+	}
+	return runIterators(s.signs, t)
+}
+
+func (s *lnr18Session) verify() error {
+	id := s.ids[0]
+	result, err := s.signs[id].Result(protocol.Version1)
+	if err != nil {
+		return err
+	}
+	signature, err := lnr18.DecodeSignature(result)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256(message)
+	return verifyEcdsa(s.curve, s.participants[id].DKGOutput().Q, digest[:], signature)
+}
+
+// setupMtA runs the setups of the MtA instances of every ordered pair of parties, in which the first party is the
+// sender, and counts the bytes that each party sends in each round over all of its instances
+func setupMtA(curve *curves.Curve, ids []uint32, t *traffic) (map[uint32]map[uint32]sign_offline.MTASender[*mta_ot.Round1Output, *mta_ot.Round2Output], map[uint32]map[uint32]sign_offline.MTAReceiver[*mta_ot.Round1Output, *mta_ot.Round2Output], error) {
+	backend := mta.NewOT(curve)
+	senders := make(map[uint32]map[uint32]sign_offline.MTASender[*mta_ot.Round1Output, *mta_ot.Round2Output], len(ids))
+	receivers := make(map[uint32]map[uint32]sign_offline.MTAReceiver[*mta_ot.Round1Output, *mta_ot.Round2Output], len(ids))
+	for _, id := range ids {
+		senders[id] = make(map[uint32]sign_offline.MTASender[*mta_ot.Round1Output, *mta_ot.Round2Output], len(ids)-1)
+		receivers[id] = make(map[uint32]sign_offline.MTAReceiver[*mta_ot.Round1Output, *mta_ot.Round2Output], len(ids)-1)
+	}
+	for _, i := range ids {
+		for _, j := range ids {
+			if i == j {
+				continue
+			}
+			uniqueSessionId := mta.SessionId(benchSessionId, i, j)
+			senderSetup, err := backend.NewSenderSetup(uniqueSessionId)
+			if err != nil {
+				return nil, nil, err
+			}
+			receiverSetup, err := backend.NewReceiverSetup(uniqueSessionId)
+			if err != nil {
+				return nil, nil, err
+			}
+			var toSender, toReceiver []byte
+			for round := 1; round <= backend.Rounds(); round++ {
+				label := fmt.Sprintf("setup-%d", round)
+				fromReceiver, err := receiverSetup.Next(toReceiver)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "receiver %d in round %d of the MtA setup", j, round)
+				}
+				fromSender, err := senderSetup.Next(toSender)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "sender %d in round %d of the MtA setup", i, round)
+				}
+				if fromReceiver != nil {
+					t.add(label, j, len(fromReceiver))
+				}
+				if fromSender != nil {
+					t.add(label, i, len(fromSender))
+				}
+				toSender, toReceiver = fromReceiver, fromSender
+			}
+			if senderSetup.Sender() == nil || receiverSetup.Receiver() == nil {
+				return nil, nil, fmt.Errorf("the MtA setup of parties %d and %d did not complete", i, j)
+			}
+			senders[i][j] = senderSetup.Sender()
+			receivers[j][i] = receiverSetup.Receiver()
+		}
+	}
+	return senders, receivers, nil
+}
+
+// tSchnorrSession runs the threshold Schnorr scheme. The scheme simulates every party in a single struct, so the
+// traffic is that of the values each party would send: its commitment, then its decommitted proof, and in signing its
+// partial signature.
+type tSchnorrSession struct {
+	curve  *curves.Curve
+	ids    []uint32
+	scheme *tschnorr.Scheme
+}
+
+func newTSchnorrSession(n int, _ int) (session, error) {
+	curve := curves.K256()
+	ids := parties.Range(n)
+	scheme, err := tschnorr.NewScheme(curve, ids)
+	if err != nil {
+		return nil, err
+	}
+	return &tSchnorrSession{curve: curve, ids: ids, scheme: scheme}, nil
+}
+
+func (s *tSchnorrSession) dkg(t *traffic) error {
+	if err := s.scheme.DKG(); err != nil {
+		return err
+	}
+	for _, id := range s.ids {
+		proof, err := s.scheme.QProofs[id].MarshalBinary()
+		if err != nil {
+			return err
+		}
+		s.broadcast(t, "1", id, len(s.scheme.QCommitments[id]))
+		s.broadcast(t, "2", id, len(proof)+len(s.scheme.QProofSessionIds[id]))
+	}
+	return nil
+}
+
+func (s *tSchnorrSession) sign(t *traffic) error {
+	if err := s.scheme.DS(); err != nil {
+		return err
+	}
+	partialSignatureSize := len(s.curve.Scalar.Bytes())
+	for _, id := range s.ids {
+		proof, err := s.scheme.RProofs[id].MarshalBinary()
+		if err != nil {
+			return err
+		}
+		s.broadcast(t, "1", id, len(s.scheme.RCommitments[id]))
+		s.broadcast(t, "2", id, len(proof)+len(s.scheme.RProofSessionIds[id]))
+		s.broadcast(t, "3", id, partialSignatureSize)
+	}
+	return nil
+}
+
+// verify has nothing to check, as DS only succeeds once every party verified the signature
+func (s *tSchnorrSession) verify() error {
+	return nil
+}
+
+// broadcast counts a payload of size bytes that party sends to every other party
+func (s *tSchnorrSession) broadcast(t *traffic, round string, party uint32, size int) {
+	t.add(round, party, size*(len(s.ids)-1))
+}
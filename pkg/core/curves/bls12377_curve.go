@@ -4,47 +4,38 @@
 // SPDX-License-Identifier: Apache-2.0
 //
 
-// NOTE that the bls curves are NOT constant time. There is an open issue to address it: https://github.com/coinbase/kryptology/issues/233
-
 package curves
 
 import (
-	"crypto/rand"
-	"crypto/sha256"
-	"crypto/subtle"
 	"fmt"
 	"io"
 	"math/big"
 
-	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
 	"golang.org/x/crypto/sha3"
 
-	"github.com/coinbase/kryptology/pkg/core"
+	"github.com/coinbase/kryptology/internal"
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/bls12377"
 )
 
 // See 'r' = https://eprint.iacr.org/2018/962.pdf Figure 16
 var bls12377modulus = bhex("12ab655e9a2ca55660b44d1e5c37b00159aa76fed00000010a11800000000001")
-var g1Inf = bls12377.G1Affine{X: [6]uint64{}, Y: [6]uint64{}}
-var g2Inf = bls12377.G2Affine{
-	X: bls12377.E2{A0: [6]uint64{}, A1: [6]uint64{}},
-	Y: bls12377.E2{A0: [6]uint64{}, A1: [6]uint64{}},
-}
 
 type ScalarBls12377 struct {
-	value *big.Int
+	Value *native.Field
 	point Point
 }
 
 type PointBls12377G1 struct {
-	value *bls12377.G1Affine
+	Value *bls12377.G1
 }
 
 type PointBls12377G2 struct {
-	value *bls12377.G2Affine
+	Value *bls12377.G2
 }
 
 type ScalarBls12377Gt struct {
-	value *bls12377.GT
+	Value *bls12377.Gt
 }
 
 func (s *ScalarBls12377) Random(reader io.Reader) Scalar {
@@ -57,54 +48,57 @@ func (s *ScalarBls12377) Random(reader io.Reader) Scalar {
 }
 
 func (s *ScalarBls12377) Hash(bytes []byte) Scalar {
-	xmd, err := expandMsgXmd(sha256.New(), bytes, []byte("BLS12377_XMD:SHA-256_SSWU_RO_"), 48)
-	if err != nil {
-		return nil
-	}
-	v := new(big.Int).SetBytes(xmd)
+	dst := []byte("BLS12377_XMD:SHA-256_SSWU_RO_")
+	xmd := native.ExpandMsgXmd(native.EllipticPointHasherSha256(), bytes, dst, 48)
+	var t [64]byte
+	copy(t[:48], internal.ReverseScalarBytes(xmd))
+
 	return &ScalarBls12377{
-		value: v.Mod(v, bls12377modulus),
+		Value: bls12377.Bls12377FqNew().SetBytesWide(&t),
 		point: s.point,
 	}
 }
 
 func (s *ScalarBls12377) Zero() Scalar {
 	return &ScalarBls12377{
-		value: big.NewInt(0),
+		Value: bls12377.Bls12377FqNew().SetZero(),
 		point: s.point,
 	}
 }
 
 func (s *ScalarBls12377) One() Scalar {
 	return &ScalarBls12377{
-		value: big.NewInt(1),
+		Value: bls12377.Bls12377FqNew().SetOne(),
 		point: s.point,
 	}
 }
 
 func (s *ScalarBls12377) IsZero() bool {
-	return subtle.ConstantTimeCompare(s.value.Bytes(), []byte{}) == 1
+	return s.Value.IsZero() == 1
 }
 
 func (s *ScalarBls12377) IsOne() bool {
-	return subtle.ConstantTimeCompare(s.value.Bytes(), []byte{1}) == 1
+	return s.Value.IsOne() == 1
 }
 
 func (s *ScalarBls12377) IsOdd() bool {
-	return s.value.Bit(0) == 1
+	bytes := s.Value.Bytes()
+	return bytes[0]&1 == 1
 }
 
 func (s *ScalarBls12377) IsEven() bool {
-	return s.value.Bit(0) == 0
+	bytes := s.Value.Bytes()
+	return bytes[0]&1 == 0
 }
 
 func (s *ScalarBls12377) New(value int) Scalar {
+	t := bls12377.Bls12377FqNew()
 	v := big.NewInt(int64(value))
 	if value < 0 {
-		v.Mod(v, bls12377modulus)
+		v.Mod(v, t.Params.BiModulus)
 	}
 	return &ScalarBls12377{
-		value: v,
+		Value: t.SetBigInt(v),
 		point: s.point,
 	}
 }
@@ -112,7 +106,7 @@ func (s *ScalarBls12377) New(value int) Scalar {
 func (s *ScalarBls12377) Cmp(rhs Scalar) int {
 	r, ok := rhs.(*ScalarBls12377)
 	if ok {
-		return s.value.Cmp(r.value)
+		return s.Value.Cmp(r.Value)
 	} else {
 		return -2
 	}
@@ -120,36 +114,46 @@ func (s *ScalarBls12377) Cmp(rhs Scalar) int {
 
 func (s *ScalarBls12377) Square() Scalar {
 	return &ScalarBls12377{
-		value: new(big.Int).Exp(s.value, big.NewInt(2), bls12377modulus),
+		Value: bls12377.Bls12377FqNew().Square(s.Value),
 		point: s.point,
 	}
 }
 
 func (s *ScalarBls12377) Double() Scalar {
-	v := new(big.Int).Add(s.value, s.value)
+	v := bls12377.Bls12377FqNew().Double(s.Value)
 	return &ScalarBls12377{
-		value: v.Mod(v, bls12377modulus),
+		Value: v,
 		point: s.point,
 	}
 }
 
 func (s *ScalarBls12377) Invert() (Scalar, error) {
+	value, wasInverted := bls12377.Bls12377FqNew().Invert(s.Value)
+	if !wasInverted {
+		return nil, fmt.Errorf("inverse doesn't exist")
+	}
 	return &ScalarBls12377{
-		value: new(big.Int).ModInverse(s.value, bls12377modulus),
+		Value: value,
 		point: s.point,
 	}, nil
 }
 
 func (s *ScalarBls12377) Sqrt() (Scalar, error) {
+	value, wasSquare := bls12377.Bls12377FqNew().Sqrt(s.Value)
+	if !wasSquare {
+		return nil, fmt.Errorf("not a square")
+	}
 	return &ScalarBls12377{
-		value: new(big.Int).ModSqrt(s.value, bls12377modulus),
+		Value: value,
 		point: s.point,
 	}, nil
 }
 
 func (s *ScalarBls12377) Cube() Scalar {
+	value := bls12377.Bls12377FqNew().Square(s.Value)
+	value.Mul(value, s.Value)
 	return &ScalarBls12377{
-		value: new(big.Int).Exp(s.value, big.NewInt(3), bls12377modulus),
+		Value: value,
 		point: s.point,
 	}
 }
@@ -157,9 +161,8 @@ func (s *ScalarBls12377) Cube() Scalar {
 func (s *ScalarBls12377) Add(rhs Scalar) Scalar {
 	r, ok := rhs.(*ScalarBls12377)
 	if ok {
-		v := new(big.Int).Add(s.value, r.value)
 		return &ScalarBls12377{
-			value: v.Mod(v, bls12377modulus),
+			Value: bls12377.Bls12377FqNew().Add(s.Value, r.Value),
 			point: s.point,
 		}
 	} else {
@@ -170,9 +173,8 @@ func (s *ScalarBls12377) Add(rhs Scalar) Scalar {
 func (s *ScalarBls12377) Sub(rhs Scalar) Scalar {
 	r, ok := rhs.(*ScalarBls12377)
 	if ok {
-		v := new(big.Int).Sub(s.value, r.value)
 		return &ScalarBls12377{
-			value: v.Mod(v, bls12377modulus),
+			Value: bls12377.Bls12377FqNew().Sub(s.Value, r.Value),
 			point: s.point,
 		}
 	} else {
@@ -183,9 +185,8 @@ func (s *ScalarBls12377) Sub(rhs Scalar) Scalar {
 func (s *ScalarBls12377) Mul(rhs Scalar) Scalar {
 	r, ok := rhs.(*ScalarBls12377)
 	if ok {
-		v := new(big.Int).Mul(s.value, r.value)
 		return &ScalarBls12377{
-			value: v.Mod(v, bls12377modulus),
+			Value: bls12377.Bls12377FqNew().Mul(s.Value, r.Value),
 			point: s.point,
 		}
 	} else {
@@ -200,10 +201,13 @@ func (s *ScalarBls12377) MulAdd(y, z Scalar) Scalar {
 func (s *ScalarBls12377) Div(rhs Scalar) Scalar {
 	r, ok := rhs.(*ScalarBls12377)
 	if ok {
-		v := new(big.Int).ModInverse(r.value, bls12377modulus)
-		v.Mul(v, s.value)
+		v, wasInverted := bls12377.Bls12377FqNew().Invert(r.Value)
+		if !wasInverted {
+			return nil
+		}
+		v.Mul(v, s.Value)
 		return &ScalarBls12377{
-			value: v.Mod(v, bls12377modulus),
+			Value: v,
 			point: s.point,
 		}
 	} else {
@@ -212,9 +216,8 @@ func (s *ScalarBls12377) Div(rhs Scalar) Scalar {
 }
 
 func (s *ScalarBls12377) Neg() Scalar {
-	z := new(big.Int).Neg(s.value)
 	return &ScalarBls12377{
-		value: z.Mod(z, bls12377modulus),
+		Value: bls12377.Bls12377FqNew().Neg(s.Value),
 		point: s.point,
 	}
 }
@@ -223,34 +226,33 @@ func (s *ScalarBls12377) SetBigInt(v *big.Int) (Scalar, error) {
 	if v == nil {
 		return nil, fmt.Errorf("invalid value")
 	}
-	t := new(big.Int).Mod(v, bls12377modulus)
-	if t.Cmp(v) != 0 {
-		return nil, fmt.Errorf("invalid value")
-	}
 	return &ScalarBls12377{
-		value: t,
+		Value: bls12377.Bls12377FqNew().SetBigInt(v),
 		point: s.point,
 	}, nil
 }
 
 func (s *ScalarBls12377) BigInt() *big.Int {
-	return new(big.Int).Set(s.value)
+	return s.Value.BigInt()
 }
 
 func (s *ScalarBls12377) Bytes() []byte {
-	var out [32]byte
-	return s.value.FillBytes(out[:])
+	t := s.Value.Bytes()
+	return internal.ReverseScalarBytes(t[:])
 }
 
 func (s *ScalarBls12377) SetBytes(bytes []byte) (Scalar, error) {
-	value := new(big.Int).SetBytes(bytes)
-	t := new(big.Int).Mod(value, bls12377modulus)
-	if t.Cmp(value) != 0 {
-		return nil, fmt.Errorf("invalid byte sequence")
+	if len(bytes) != 32 {
+		return nil, fmt.Errorf("invalid length")
+	}
+	var seq [32]byte
+	copy(seq[:], internal.ReverseScalarBytes(bytes))
+	value, err := bls12377.Bls12377FqNew().SetBytes(&seq)
+	if err != nil {
+		return nil, err
 	}
 	return &ScalarBls12377{
-		value: t,
-		point: s.point,
+		value, s.point,
 	}, nil
 }
 
@@ -259,10 +261,8 @@ func (s *ScalarBls12377) SetBytesWide(bytes []byte) (Scalar, error) {
 		return nil, fmt.Errorf("invalid byte sequence")
 	}
 	value := new(big.Int).SetBytes(bytes)
-	t := new(big.Int).Mod(value, bls12377modulus)
 	return &ScalarBls12377{
-		value: t,
-		point: s.point,
+		bls12377.Bls12377FqNew().SetBigInt(value.Mod(value, bls12377modulus)), s.point,
 	}, nil
 }
 
@@ -272,20 +272,20 @@ func (s *ScalarBls12377) Point() Point {
 
 func (s *ScalarBls12377) Clone() Scalar {
 	return &ScalarBls12377{
-		value: new(big.Int).Set(s.value),
+		Value: bls12377.Bls12377FqNew().Set(s.Value),
 		point: s.point,
 	}
 }
 
 func (s *ScalarBls12377) SetPoint(p Point) PairingScalar {
 	return &ScalarBls12377{
-		value: new(big.Int).Set(s.value),
+		Value: bls12377.Bls12377FqNew().Set(s.Value),
 		point: p,
 	}
 }
 
 func (s *ScalarBls12377) Order() *big.Int {
-	return bls12377modulus
+	return s.Value.Params.BiModulus
 }
 
 func (s *ScalarBls12377) MarshalBinary() ([]byte, error) {
@@ -301,7 +301,7 @@ func (s *ScalarBls12377) UnmarshalBinary(input []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid scalar")
 	}
-	s.value = ss.value
+	s.Value = ss.Value
 	s.point = ss.point
 	return nil
 }
@@ -319,7 +319,7 @@ func (s *ScalarBls12377) UnmarshalText(input []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid scalar")
 	}
-	s.value = ss.value
+	s.Value = ss.Value
 	s.point = ss.point
 	return nil
 }
@@ -337,7 +337,7 @@ func (s *ScalarBls12377) UnmarshalJSON(input []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid type")
 	}
-	s.value = S.value
+	s.Value = S.Value
 	return nil
 }
 
@@ -349,62 +349,49 @@ func (p *PointBls12377G1) Random(reader io.Reader) Point {
 
 func (p *PointBls12377G1) Hash(bytes []byte) Point {
 	var domain = []byte("BLS12377G1_XMD:SHA-256_SVDW_RO_")
-	pt, err := bls12377.HashToCurveG1Svdw(bytes, domain)
-	if err != nil {
-		return nil
-	}
-	return &PointBls12377G1{value: &pt}
+	pt := new(bls12377.G1).Hash(native.EllipticPointHasherSha256(), bytes, domain)
+	return &PointBls12377G1{Value: pt}
 }
 
 func (p *PointBls12377G1) Identity() Point {
-	t := bls12377.G1Affine{}
 	return &PointBls12377G1{
-		value: t.Set(&g1Inf),
+		Value: new(bls12377.G1).Identity(),
 	}
 }
 
 func (p *PointBls12377G1) Generator() Point {
-	t := bls12377.G1Affine{}
-	_, _, g1Aff, _ := bls12377.Generators()
 	return &PointBls12377G1{
-		value: t.Set(&g1Aff),
+		Value: new(bls12377.G1).Generator(),
 	}
 }
 
 func (p *PointBls12377G1) IsIdentity() bool {
-	return p.value.IsInfinity()
+	return p.Value.IsIdentity() == 1
 }
 
 func (p *PointBls12377G1) IsNegative() bool {
 	// According to https://github.com/zcash/librustzcash/blob/6e0364cd42a2b3d2b958a54771ef51a8db79dd29/pairing/src/bls12_381/README.md#serialization
 	// This bit represents the sign of the `y` coordinate which is what we want
-
-	return (p.value.Bytes()[0]>>5)&1 == 1
+	return (p.Value.ToCompressed()[0]>>5)&1 == 1
 }
 
 func (p *PointBls12377G1) IsOnCurve() bool {
-	return p.value.IsOnCurve()
+	return p.Value.IsOnCurve() == 1
 }
 
 func (p *PointBls12377G1) Double() Point {
-	t := &bls12377.G1Jac{}
-	t.FromAffine(p.value)
-	t.DoubleAssign()
-	value := bls12377.G1Affine{}
-	return &PointBls12377G1{value.FromJacobian(t)}
+	return &PointBls12377G1{new(bls12377.G1).Double(p.Value)}
 }
 
 func (p *PointBls12377G1) Scalar() Scalar {
 	return &ScalarBls12377{
-		value: new(big.Int),
+		Value: bls12377.Bls12377FqNew(),
 		point: new(PointBls12377G1),
 	}
 }
 
 func (p *PointBls12377G1) Neg() Point {
-	value := &bls12377.G1Affine{}
-	value.Neg(p.value)
-	return &PointBls12377G1{value}
+	return &PointBls12377G1{new(bls12377.G1).Neg(p.Value)}
 }
 
 func (p *PointBls12377G1) Add(rhs Point) Point {
@@ -413,8 +400,7 @@ func (p *PointBls12377G1) Add(rhs Point) Point {
 	}
 	r, ok := rhs.(*PointBls12377G1)
 	if ok {
-		value := &bls12377.G1Affine{}
-		return &PointBls12377G1{value.Add(p.value, r.value)}
+		return &PointBls12377G1{new(bls12377.G1).Add(p.Value, r.Value)}
 	} else {
 		return nil
 	}
@@ -426,8 +412,7 @@ func (p *PointBls12377G1) Sub(rhs Point) Point {
 	}
 	r, ok := rhs.(*PointBls12377G1)
 	if ok {
-		value := &bls12377.G1Affine{}
-		return &PointBls12377G1{value.Sub(p.value, r.value)}
+		return &PointBls12377G1{new(bls12377.G1).Sub(p.Value, r.Value)}
 	} else {
 		return nil
 	}
@@ -439,8 +424,7 @@ func (p *PointBls12377G1) Mul(rhs Scalar) Point {
 	}
 	r, ok := rhs.(*ScalarBls12377)
 	if ok {
-		value := &bls12377.G1Affine{}
-		return &PointBls12377G1{value.ScalarMultiplication(p.value, r.value)}
+		return &PointBls12377G1{new(bls12377.G1).Mul(p.Value, r.Value)}
 	} else {
 		return nil
 	}
@@ -449,22 +433,14 @@ func (p *PointBls12377G1) Mul(rhs Scalar) Point {
 func (p *PointBls12377G1) Equal(rhs Point) bool {
 	r, ok := rhs.(*PointBls12377G1)
 	if ok {
-		return p.value.Equal(r.value)
+		return p.Value.Equal(r.Value) == 1
 	} else {
 		return false
 	}
 }
 
 func (p *PointBls12377G1) Set(x, y *big.Int) (Point, error) {
-	if x.Cmp(core.Zero) == 0 &&
-		y.Cmp(core.Zero) == 0 {
-		return p.Identity(), nil
-	}
-	var data [96]byte
-	x.FillBytes(data[:48])
-	y.FillBytes(data[48:])
-	value := &bls12377.G1Affine{}
-	_, err := value.SetBytes(data[:])
+	value, err := new(bls12377.G1).SetBigInt(x, y)
 	if err != nil {
 		return nil, fmt.Errorf("invalid coordinates")
 	}
@@ -472,21 +448,19 @@ func (p *PointBls12377G1) Set(x, y *big.Int) (Point, error) {
 }
 
 func (p *PointBls12377G1) ToAffineCompressed() []byte {
-	v := p.value.Bytes()
-	return v[:]
+	out := p.Value.ToCompressed()
+	return out[:]
 }
 
 func (p *PointBls12377G1) ToAffineUncompressed() []byte {
-	v := p.value.RawBytes()
-	return v[:]
+	out := p.Value.ToUncompressed()
+	return out[:]
 }
 
 func (p *PointBls12377G1) FromAffineCompressed(bytes []byte) (Point, error) {
-	if len(bytes) != bls12377.SizeOfG1AffineCompressed {
-		return nil, fmt.Errorf("invalid point")
-	}
-	value := &bls12377.G1Affine{}
-	_, err := value.SetBytes(bytes)
+	var b [bls12377.FieldBytes]byte
+	copy(b[:], bytes)
+	value, err := new(bls12377.G1).FromCompressed(&b)
 	if err != nil {
 		return nil, err
 	}
@@ -494,11 +468,9 @@ func (p *PointBls12377G1) FromAffineCompressed(bytes []byte) (Point, error) {
 }
 
 func (p *PointBls12377G1) FromAffineUncompressed(bytes []byte) (Point, error) {
-	if len(bytes) != bls12377.SizeOfG1AffineUncompressed {
-		return nil, fmt.Errorf("invalid point")
-	}
-	value := &bls12377.G1Affine{}
-	_, err := value.SetBytes(bytes)
+	var b [96]byte
+	copy(b[:], bytes)
+	value, err := new(bls12377.G1).FromUncompressed(&b)
 	if err != nil {
 		return nil, err
 	}
@@ -510,15 +482,27 @@ func (p *PointBls12377G1) CurveName() string {
 }
 
 func (p *PointBls12377G1) SumOfProducts(points []Point, scalars []Scalar) Point {
-	nScalars := make([]*big.Int, len(scalars))
+	nPoints := make([]*bls12377.G1, len(points))
+	nScalars := make([]*native.Field, len(scalars))
+	for i, pt := range points {
+		pp, ok := pt.(*PointBls12377G1)
+		if !ok {
+			return nil
+		}
+		nPoints[i] = pp.Value
+	}
 	for i, sc := range scalars {
 		s, ok := sc.(*ScalarBls12377)
 		if !ok {
 			return nil
 		}
-		nScalars[i] = s.value
+		nScalars[i] = s.Value
+	}
+	value, err := new(bls12377.G1).SumOfProducts(nPoints, nScalars)
+	if err != nil {
+		return nil
 	}
-	return sumOfProductsPippenger(points, nScalars)
+	return &PointBls12377G1{value}
 }
 
 func (p *PointBls12377G1) OtherGroup() PairingPoint {
@@ -530,20 +514,12 @@ func (p *PointBls12377G1) Pairing(rhs PairingPoint) Scalar {
 	if !ok {
 		return nil
 	}
-	if !p.value.IsInSubGroup() ||
-		!pt.value.IsInSubGroup() {
-		return nil
-	}
-	value := bls12377.GT{}
-	if p.value.IsInfinity() || pt.value.IsInfinity() {
-		return &ScalarBls12377Gt{&value}
-	}
-	value, err := bls12377.Pair([]bls12377.G1Affine{*p.value}, []bls12377.G2Affine{*pt.value})
-	if err != nil {
-		return nil
-	}
+	e := new(bls12377.Engine)
+	e.AddPair(p.Value, pt.Value)
+
+	value := e.Result()
 
-	return &ScalarBls12377Gt{&value}
+	return &ScalarBls12377Gt{value}
 }
 
 func (p *PointBls12377G1) MultiPairing(points ...PairingPoint) Scalar {
@@ -551,13 +527,11 @@ func (p *PointBls12377G1) MultiPairing(points ...PairingPoint) Scalar {
 }
 
 func (p *PointBls12377G1) X() *big.Int {
-	b := p.value.RawBytes()
-	return new(big.Int).SetBytes(b[:48])
+	return p.Value.GetX().BigInt()
 }
 
 func (p *PointBls12377G1) Y() *big.Int {
-	b := p.value.RawBytes()
-	return new(big.Int).SetBytes(b[48:])
+	return p.Value.GetY().BigInt()
 }
 
 func (p *PointBls12377G1) Modulus() *big.Int {
@@ -577,7 +551,7 @@ func (p *PointBls12377G1) UnmarshalBinary(input []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid point")
 	}
-	p.value = ppt.value
+	p.Value = ppt.Value
 	return nil
 }
 
@@ -594,7 +568,7 @@ func (p *PointBls12377G1) UnmarshalText(input []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid point")
 	}
-	p.value = ppt.value
+	p.Value = ppt.Value
 	return nil
 }
 
@@ -611,7 +585,7 @@ func (p *PointBls12377G1) UnmarshalJSON(input []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid type")
 	}
-	p.value = P.value
+	p.Value = P.Value
 	return nil
 }
 
@@ -623,61 +597,49 @@ func (p *PointBls12377G2) Random(reader io.Reader) Point {
 
 func (p *PointBls12377G2) Hash(bytes []byte) Point {
 	var domain = []byte("BLS12377G2_XMD:SHA-256_SVDW_RO_")
-	pt, err := bls12377.HashToCurveG2Svdw(bytes, domain)
-	if err != nil {
-		return nil
-	}
-	return &PointBls12377G2{value: &pt}
+	pt := new(bls12377.G2).Hash(native.EllipticPointHasherSha256(), bytes, domain)
+	return &PointBls12377G2{Value: pt}
 }
 
 func (p *PointBls12377G2) Identity() Point {
-	t := bls12377.G2Affine{}
 	return &PointBls12377G2{
-		value: t.Set(&g2Inf),
+		Value: new(bls12377.G2).Identity(),
 	}
 }
 
 func (p *PointBls12377G2) Generator() Point {
-	t := bls12377.G2Affine{}
-	_, _, _, g2Aff := bls12377.Generators()
 	return &PointBls12377G2{
-		value: t.Set(&g2Aff),
+		Value: new(bls12377.G2).Generator(),
 	}
 }
 
 func (p *PointBls12377G2) IsIdentity() bool {
-	return p.value.IsInfinity()
+	return p.Value.IsIdentity() == 1
 }
 
 func (p *PointBls12377G2) IsNegative() bool {
 	// According to https://github.com/zcash/librustzcash/blob/6e0364cd42a2b3d2b958a54771ef51a8db79dd29/pairing/src/bls12_381/README.md#serialization
 	// This bit represents the sign of the `y` coordinate which is what we want
-	return (p.value.Bytes()[0]>>5)&1 == 1
+	return (p.Value.ToCompressed()[0]>>5)&1 == 1
 }
 
 func (p *PointBls12377G2) IsOnCurve() bool {
-	return p.value.IsOnCurve()
+	return p.Value.IsOnCurve() == 1
 }
 
 func (p *PointBls12377G2) Double() Point {
-	t := &bls12377.G2Jac{}
-	t.FromAffine(p.value)
-	t.DoubleAssign()
-	value := bls12377.G2Affine{}
-	return &PointBls12377G2{value.FromJacobian(t)}
+	return &PointBls12377G2{new(bls12377.G2).Double(p.Value)}
 }
 
 func (p *PointBls12377G2) Scalar() Scalar {
 	return &ScalarBls12377{
-		value: new(big.Int),
+		Value: bls12377.Bls12377FqNew(),
 		point: new(PointBls12377G2),
 	}
 }
 
 func (p *PointBls12377G2) Neg() Point {
-	value := &bls12377.G2Affine{}
-	value.Neg(p.value)
-	return &PointBls12377G2{value}
+	return &PointBls12377G2{new(bls12377.G2).Neg(p.Value)}
 }
 
 func (p *PointBls12377G2) Add(rhs Point) Point {
@@ -686,8 +648,7 @@ func (p *PointBls12377G2) Add(rhs Point) Point {
 	}
 	r, ok := rhs.(*PointBls12377G2)
 	if ok {
-		value := &bls12377.G2Affine{}
-		return &PointBls12377G2{value.Add(p.value, r.value)}
+		return &PointBls12377G2{new(bls12377.G2).Add(p.Value, r.Value)}
 	} else {
 		return nil
 	}
@@ -699,8 +660,7 @@ func (p *PointBls12377G2) Sub(rhs Point) Point {
 	}
 	r, ok := rhs.(*PointBls12377G2)
 	if ok {
-		value := &bls12377.G2Affine{}
-		return &PointBls12377G2{value.Sub(p.value, r.value)}
+		return &PointBls12377G2{new(bls12377.G2).Sub(p.Value, r.Value)}
 	} else {
 		return nil
 	}
@@ -712,8 +672,7 @@ func (p *PointBls12377G2) Mul(rhs Scalar) Point {
 	}
 	r, ok := rhs.(*ScalarBls12377)
 	if ok {
-		value := &bls12377.G2Affine{}
-		return &PointBls12377G2{value.ScalarMultiplication(p.value, r.value)}
+		return &PointBls12377G2{new(bls12377.G2).Mul(p.Value, r.Value)}
 	} else {
 		return nil
 	}
@@ -722,22 +681,14 @@ func (p *PointBls12377G2) Mul(rhs Scalar) Point {
 func (p *PointBls12377G2) Equal(rhs Point) bool {
 	r, ok := rhs.(*PointBls12377G2)
 	if ok {
-		return p.value.Equal(r.value)
+		return p.Value.Equal(r.Value) == 1
 	} else {
 		return false
 	}
 }
 
 func (p *PointBls12377G2) Set(x, y *big.Int) (Point, error) {
-	if x.Cmp(core.Zero) == 0 &&
-		y.Cmp(core.Zero) == 0 {
-		return p.Identity(), nil
-	}
-	var data [192]byte
-	x.FillBytes(data[:96])
-	y.FillBytes(data[96:])
-	value := &bls12377.G2Affine{}
-	_, err := value.SetBytes(data[:])
+	value, err := new(bls12377.G2).SetBigInt(x, y)
 	if err != nil {
 		return nil, fmt.Errorf("invalid coordinates")
 	}
@@ -745,21 +696,19 @@ func (p *PointBls12377G2) Set(x, y *big.Int) (Point, error) {
 }
 
 func (p *PointBls12377G2) ToAffineCompressed() []byte {
-	v := p.value.Bytes()
-	return v[:]
+	out := p.Value.ToCompressed()
+	return out[:]
 }
 
 func (p *PointBls12377G2) ToAffineUncompressed() []byte {
-	v := p.value.RawBytes()
-	return v[:]
+	out := p.Value.ToUncompressed()
+	return out[:]
 }
 
 func (p *PointBls12377G2) FromAffineCompressed(bytes []byte) (Point, error) {
-	if len(bytes) != bls12377.SizeOfG2AffineCompressed {
-		return nil, fmt.Errorf("invalid point")
-	}
-	value := &bls12377.G2Affine{}
-	_, err := value.SetBytes(bytes)
+	var b [bls12377.WideFieldBytes]byte
+	copy(b[:], bytes)
+	value, err := new(bls12377.G2).FromCompressed(&b)
 	if err != nil {
 		return nil, err
 	}
@@ -767,11 +716,9 @@ func (p *PointBls12377G2) FromAffineCompressed(bytes []byte) (Point, error) {
 }
 
 func (p *PointBls12377G2) FromAffineUncompressed(bytes []byte) (Point, error) {
-	if len(bytes) != bls12377.SizeOfG2AffineUncompressed {
-		return nil, fmt.Errorf("invalid point")
-	}
-	value := &bls12377.G2Affine{}
-	_, err := value.SetBytes(bytes)
+	var b [bls12377.DoubleWideFieldBytes]byte
+	copy(b[:], bytes)
+	value, err := new(bls12377.G2).FromUncompressed(&b)
 	if err != nil {
 		return nil, err
 	}
@@ -783,15 +730,27 @@ func (p *PointBls12377G2) CurveName() string {
 }
 
 func (p *PointBls12377G2) SumOfProducts(points []Point, scalars []Scalar) Point {
-	nScalars := make([]*big.Int, len(scalars))
+	nPoints := make([]*bls12377.G2, len(points))
+	nScalars := make([]*native.Field, len(scalars))
+	for i, pt := range points {
+		pp, ok := pt.(*PointBls12377G2)
+		if !ok {
+			return nil
+		}
+		nPoints[i] = pp.Value
+	}
 	for i, sc := range scalars {
 		s, ok := sc.(*ScalarBls12377)
 		if !ok {
 			return nil
 		}
-		nScalars[i] = s.value
+		nScalars[i] = s.Value
 	}
-	return sumOfProductsPippenger(points, nScalars)
+	value, err := new(bls12377.G2).SumOfProducts(nPoints, nScalars)
+	if err != nil {
+		return nil
+	}
+	return &PointBls12377G2{value}
 }
 
 func (p *PointBls12377G2) OtherGroup() PairingPoint {
@@ -803,20 +762,12 @@ func (p *PointBls12377G2) Pairing(rhs PairingPoint) Scalar {
 	if !ok {
 		return nil
 	}
-	if !p.value.IsInSubGroup() ||
-		!pt.value.IsInSubGroup() {
-		return nil
-	}
-	value := bls12377.GT{}
-	if p.value.IsInfinity() || pt.value.IsInfinity() {
-		return &ScalarBls12377Gt{&value}
-	}
-	value, err := bls12377.Pair([]bls12377.G1Affine{*pt.value}, []bls12377.G2Affine{*p.value})
-	if err != nil {
-		return nil
-	}
+	e := new(bls12377.Engine)
+	e.AddPair(pt.Value, p.Value)
 
-	return &ScalarBls12377Gt{&value}
+	value := e.Result()
+
+	return &ScalarBls12377Gt{value}
 }
 
 func (p *PointBls12377G2) MultiPairing(points ...PairingPoint) Scalar {
@@ -824,13 +775,13 @@ func (p *PointBls12377G2) MultiPairing(points ...PairingPoint) Scalar {
 }
 
 func (p *PointBls12377G2) X() *big.Int {
-	b := p.value.RawBytes()
-	return new(big.Int).SetBytes(b[:96])
+	x := p.Value.ToUncompressed()
+	return new(big.Int).SetBytes(x[:bls12377.WideFieldBytes])
 }
 
 func (p *PointBls12377G2) Y() *big.Int {
-	b := p.value.RawBytes()
-	return new(big.Int).SetBytes(b[96:])
+	y := p.Value.ToUncompressed()
+	return new(big.Int).SetBytes(y[bls12377.WideFieldBytes:])
 }
 
 func (p *PointBls12377G2) Modulus() *big.Int {
@@ -850,7 +801,7 @@ func (p *PointBls12377G2) UnmarshalBinary(input []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid point")
 	}
-	p.value = ppt.value
+	p.Value = ppt.Value
 	return nil
 }
 
@@ -867,7 +818,7 @@ func (p *PointBls12377G2) UnmarshalText(input []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid point")
 	}
-	p.value = ppt.value
+	p.Value = ppt.Value
 	return nil
 }
 
@@ -884,7 +835,7 @@ func (p *PointBls12377G2) UnmarshalJSON(input []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid type")
 	}
-	p.value = P.value
+	p.Value = P.Value
 	return nil
 }
 
@@ -892,53 +843,31 @@ func multiPairingBls12377(points ...PairingPoint) Scalar {
 	if len(points)%2 != 0 {
 		return nil
 	}
-	g1Arr := make([]bls12377.G1Affine, 0, len(points)/2)
-	g2Arr := make([]bls12377.G2Affine, 0, len(points)/2)
 	valid := true
+	eng := new(bls12377.Engine)
 	for i := 0; i < len(points); i += 2 {
 		pt1, ok := points[i].(*PointBls12377G1)
 		valid = valid && ok
 		pt2, ok := points[i+1].(*PointBls12377G2)
 		valid = valid && ok
 		if valid {
-			valid = valid && pt1.value.IsInSubGroup()
-			valid = valid && pt2.value.IsInSubGroup()
-		}
-		if valid {
-			g1Arr = append(g1Arr, *pt1.value)
-			g2Arr = append(g2Arr, *pt2.value)
+			eng.AddPair(pt1.Value, pt2.Value)
 		}
 	}
 	if !valid {
 		return nil
 	}
 
-	value, err := bls12377.Pair(g1Arr, g2Arr)
-	if err != nil {
-		return nil
-	}
-
-	return &ScalarBls12377Gt{&value}
+	value := eng.Result()
+	return &ScalarBls12377Gt{value}
 }
 
 func (s *ScalarBls12377Gt) Random(reader io.Reader) Scalar {
-	const width = 48
-	offset := 0
-	var data [bls12377.SizeOfGT]byte
-	for i := 0; i < 12; i++ {
-		tv, err := rand.Int(reader, bls12377modulus)
-		if err != nil {
-			return nil
-		}
-		tv.FillBytes(data[offset*width : (offset+1)*width])
-		offset++
-	}
-	value := bls12377.GT{}
-	err := value.SetBytes(data[:])
+	value, err := new(bls12377.Gt).Random(reader)
 	if err != nil {
 		return nil
 	}
-	return &ScalarBls12377Gt{&value}
+	return &ScalarBls12377Gt{value}
 }
 
 func (s *ScalarBls12377Gt) Hash(bytes []byte) Scalar {
@@ -954,32 +883,19 @@ func (s *ScalarBls12377Gt) Hash(bytes []byte) Scalar {
 }
 
 func (s *ScalarBls12377Gt) Zero() Scalar {
-	var t [bls12377.SizeOfGT]byte
-	value := bls12377.GT{}
-	err := value.SetBytes(t[:])
-	if err != nil {
-		return nil
-	}
-	return &ScalarBls12377Gt{&value}
+	return &ScalarBls12377Gt{new(bls12377.Gt)}
 }
 
 func (s *ScalarBls12377Gt) One() Scalar {
-	value := bls12377.GT{}
-	return &ScalarBls12377Gt{value.SetOne()}
+	return &ScalarBls12377Gt{new(bls12377.Gt).SetOne()}
 }
 
 func (s *ScalarBls12377Gt) IsZero() bool {
-	r := byte(0)
-	b := s.value.Bytes()
-	for _, i := range b {
-		r |= i
-	}
-	return r == 0
+	return s.Value.IsZero() == 1
 }
 
 func (s *ScalarBls12377Gt) IsOne() bool {
-	o := bls12377.GT{}
-	return s.value.Equal(o.SetOne())
+	return s.Value.IsOne() == 1
 }
 
 func (s *ScalarBls12377Gt) MarshalBinary() ([]byte, error) {
@@ -995,7 +911,7 @@ func (s *ScalarBls12377Gt) UnmarshalBinary(input []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid scalar")
 	}
-	s.value = ss.value
+	s.Value = ss.Value
 	return nil
 }
 
@@ -1012,7 +928,7 @@ func (s *ScalarBls12377Gt) UnmarshalText(input []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid scalar")
 	}
-	s.value = ss.value
+	s.Value = ss.Value
 	return nil
 }
 
@@ -1029,38 +945,37 @@ func (s *ScalarBls12377Gt) UnmarshalJSON(input []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid type")
 	}
-	s.value = S.value
+	s.Value = S.Value
 	return nil
 }
 
 func (s *ScalarBls12377Gt) IsOdd() bool {
-	data := s.value.Bytes()
+	data := s.Value.Bytes()
 	return data[len(data)-1]&1 == 1
 }
 
 func (s *ScalarBls12377Gt) IsEven() bool {
-	data := s.value.Bytes()
+	data := s.Value.Bytes()
 	return data[len(data)-1]&1 == 0
 }
 
 func (s *ScalarBls12377Gt) New(input int) Scalar {
-	var data [576]byte
-	data[3] = byte(input >> 24 & 0xFF)
-	data[2] = byte(input >> 16 & 0xFF)
-	data[1] = byte(input >> 8 & 0xFF)
-	data[0] = byte(input & 0xFF)
-
-	value := bls12377.GT{}
-	err := value.SetBytes(data[:])
-	if err != nil {
+	var data [bls12377.GtFieldBytes]byte
+	data[bls12377.GtFieldBytes-4] = byte(input >> 24 & 0xFF)
+	data[bls12377.GtFieldBytes-3] = byte(input >> 16 & 0xFF)
+	data[bls12377.GtFieldBytes-2] = byte(input >> 8 & 0xFF)
+	data[bls12377.GtFieldBytes-1] = byte(input & 0xFF)
+
+	value, isCanonical := new(bls12377.Gt).SetBytes(&data)
+	if isCanonical != 1 {
 		return nil
 	}
-	return &ScalarBls12377Gt{&value}
+	return &ScalarBls12377Gt{value}
 }
 
 func (s *ScalarBls12377Gt) Cmp(rhs Scalar) int {
 	r, ok := rhs.(*ScalarBls12377Gt)
-	if ok && s.value.Equal(r.value) {
+	if ok && s.Value.Equal(r.Value) == 1 {
 		return 0
 	} else {
 		return -2
@@ -1068,23 +983,24 @@ func (s *ScalarBls12377Gt) Cmp(rhs Scalar) int {
 }
 
 func (s *ScalarBls12377Gt) Square() Scalar {
-	value := bls12377.GT{}
 	return &ScalarBls12377Gt{
-		value.Square(s.value),
+		new(bls12377.Gt).Square(s.Value),
 	}
 }
 
 func (s *ScalarBls12377Gt) Double() Scalar {
-	value := &bls12377.GT{}
 	return &ScalarBls12377Gt{
-		value.Add(s.value, s.value),
+		new(bls12377.Gt).Double(s.Value),
 	}
 }
 
 func (s *ScalarBls12377Gt) Invert() (Scalar, error) {
-	value := &bls12377.GT{}
+	value, wasInverted := new(bls12377.Gt).Invert(s.Value)
+	if wasInverted != 1 {
+		return nil, fmt.Errorf("not invertible")
+	}
 	return &ScalarBls12377Gt{
-		value.Inverse(s.value),
+		value,
 	}, nil
 }
 
@@ -1094,9 +1010,8 @@ func (s *ScalarBls12377Gt) Sqrt() (Scalar, error) {
 }
 
 func (s *ScalarBls12377Gt) Cube() Scalar {
-	value := &bls12377.GT{}
-	value.Square(s.value)
-	value.Mul(value, s.value)
+	value := new(bls12377.Gt).Square(s.Value)
+	value.Add(value, s.Value)
 	return &ScalarBls12377Gt{
 		value,
 	}
@@ -1105,9 +1020,8 @@ func (s *ScalarBls12377Gt) Cube() Scalar {
 func (s *ScalarBls12377Gt) Add(rhs Scalar) Scalar {
 	r, ok := rhs.(*ScalarBls12377Gt)
 	if ok {
-		value := &bls12377.GT{}
 		return &ScalarBls12377Gt{
-			value.Add(s.value, r.value),
+			new(bls12377.Gt).Add(s.Value, r.Value),
 		}
 	} else {
 		return nil
@@ -1117,9 +1031,8 @@ func (s *ScalarBls12377Gt) Add(rhs Scalar) Scalar {
 func (s *ScalarBls12377Gt) Sub(rhs Scalar) Scalar {
 	r, ok := rhs.(*ScalarBls12377Gt)
 	if ok {
-		value := &bls12377.GT{}
 		return &ScalarBls12377Gt{
-			value.Sub(s.value, r.value),
+			new(bls12377.Gt).Sub(s.Value, r.Value),
 		}
 	} else {
 		return nil
@@ -1129,9 +1042,8 @@ func (s *ScalarBls12377Gt) Sub(rhs Scalar) Scalar {
 func (s *ScalarBls12377Gt) Mul(rhs Scalar) Scalar {
 	r, ok := rhs.(*ScalarBls12377Gt)
 	if ok {
-		value := &bls12377.GT{}
 		return &ScalarBls12377Gt{
-			value.Mul(s.value, r.value),
+			new(bls12377.Gt).Add(s.Value, r.Value),
 		}
 	} else {
 		return nil
@@ -1145,11 +1057,8 @@ func (s *ScalarBls12377Gt) MulAdd(y, z Scalar) Scalar {
 func (s *ScalarBls12377Gt) Div(rhs Scalar) Scalar {
 	r, ok := rhs.(*ScalarBls12377Gt)
 	if ok {
-		value := &bls12377.GT{}
-		value.Inverse(r.value)
-		value.Mul(value, s.value)
 		return &ScalarBls12377Gt{
-			value,
+			new(bls12377.Gt).Sub(s.Value, r.Value),
 		}
 	} else {
 		return nil
@@ -1157,68 +1066,64 @@ func (s *ScalarBls12377Gt) Div(rhs Scalar) Scalar {
 }
 
 func (s *ScalarBls12377Gt) Neg() Scalar {
-	sValue := &bls12377.GT{}
-	sValue.SetOne()
-	value := &bls12377.GT{}
-	value.SetOne()
-	value.Sub(value, sValue)
 	return &ScalarBls12377Gt{
-		value.Sub(value, s.value),
+		new(bls12377.Gt).Neg(s.Value),
 	}
 }
 
 func (s *ScalarBls12377Gt) SetBigInt(v *big.Int) (Scalar, error) {
-	var bytes [576]byte
+	var bytes [bls12377.GtFieldBytes]byte
 	v.FillBytes(bytes[:])
 	return s.SetBytes(bytes[:])
 }
 
 func (s *ScalarBls12377Gt) BigInt() *big.Int {
-	b := s.value.Bytes()
-	return new(big.Int).SetBytes(b[:])
+	bytes := s.Value.Bytes()
+	return new(big.Int).SetBytes(bytes[:])
 }
 
 func (s *ScalarBls12377Gt) Point() Point {
-	p := &PointBls12377G1{}
-	return p.Identity()
+	return &PointBls12377G1{Value: new(bls12377.G1).Identity()}
 }
 
 func (s *ScalarBls12377Gt) Bytes() []byte {
-	b := s.value.Bytes()
-	return b[:]
+	bytes := s.Value.Bytes()
+	return bytes[:]
 }
 
 func (s *ScalarBls12377Gt) SetBytes(bytes []byte) (Scalar, error) {
-	value := &bls12377.GT{}
-	err := value.SetBytes(bytes)
-	if err != nil {
-		return nil, err
+	var b [bls12377.GtFieldBytes]byte
+	copy(b[:], bytes)
+	ss, isCanonical := new(bls12377.Gt).SetBytes(&b)
+	if isCanonical == 0 {
+		return nil, fmt.Errorf("invalid bytes")
 	}
-	return &ScalarBls12377Gt{value}, nil
+	return &ScalarBls12377Gt{ss}, nil
 }
 
 func (s *ScalarBls12377Gt) SetBytesWide(bytes []byte) (Scalar, error) {
 	l := len(bytes)
-	if l != 1152 {
+	if l != bls12377.GtFieldBytes*2 {
 		return nil, fmt.Errorf("invalid byte sequence")
 	}
-	value := &bls12377.GT{}
-	err := value.SetBytes(bytes[:l/2])
-	if err != nil {
-		return nil, err
+	var b [bls12377.GtFieldBytes]byte
+	copy(b[:], bytes[:bls12377.GtFieldBytes])
+
+	value, isCanonical := new(bls12377.Gt).SetBytes(&b)
+	if isCanonical == 0 {
+		return nil, fmt.Errorf("invalid byte sequence")
 	}
-	value2 := &bls12377.GT{}
-	err = value2.SetBytes(bytes[l/2:])
-	if err != nil {
-		return nil, err
+	copy(b[:], bytes[bls12377.GtFieldBytes:])
+	value2, isCanonical := new(bls12377.Gt).SetBytes(&b)
+	if isCanonical == 0 {
+		return nil, fmt.Errorf("invalid byte sequence")
 	}
 	value.Add(value, value2)
 	return &ScalarBls12377Gt{value}, nil
 }
 
 func (s *ScalarBls12377Gt) Clone() Scalar {
-	value := &bls12377.GT{}
 	return &ScalarBls12377Gt{
-		value.Set(s.value),
+		Value: new(bls12377.Gt).Set(s.Value),
 	}
 }
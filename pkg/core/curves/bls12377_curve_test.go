@@ -11,8 +11,9 @@ import (
 	"math/big"
 	"testing"
 
-	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
 	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves/native/bls12377"
 )
 
 func TestScalarBls12377G1Random(t *testing.T) {
@@ -21,7 +22,7 @@ func TestScalarBls12377G1Random(t *testing.T) {
 	s, ok := sc.(*ScalarBls12377)
 	require.True(t, ok)
 	expected, _ := new(big.Int).SetString("022a7db6fad5d5ff49108230818187de316bd0b3e5e96f190397bbb9f28e7a8b", 16)
-	require.Equal(t, s.value, expected)
+	require.Equal(t, s.Value.BigInt(), expected)
 	// Try 10 random values
 	for i := 0; i < 10; i++ {
 		sc := bls12377g1.Scalar.Random(crand.Reader)
@@ -38,7 +39,7 @@ func TestScalarBls12377G1Hash(t *testing.T) {
 	s, ok := sc.(*ScalarBls12377)
 	require.True(t, ok)
 	expected, _ := new(big.Int).SetString("0c043edae82bf279180b9353139711c1fda5fa64a1f085b80760edaee8f0baca", 16)
-	require.Equal(t, s.value, expected)
+	require.Equal(t, s.Value.BigInt(), expected)
 }
 
 func TestScalarBls12377G1Zero(t *testing.T) {
@@ -259,8 +260,7 @@ func TestPointBls12377G2Generator(t *testing.T) {
 	sc := bls12377G2.Point.Generator()
 	s, ok := sc.(*PointBls12377G2)
 	require.True(t, ok)
-	_, _, _, g2Aff := bls12377.Generators()
-	require.True(t, s.value.Equal(&g2Aff))
+	require.Equal(t, 1, s.Value.Equal(new(bls12377.G2).Generator()))
 }
 
 func TestPointBls12377G2Set(t *testing.T) {
@@ -268,8 +268,7 @@ func TestPointBls12377G2Set(t *testing.T) {
 	iden, err := bls12377G2.Point.Set(big.NewInt(0), big.NewInt(0))
 	require.NoError(t, err)
 	require.True(t, iden.IsIdentity())
-	_, _, _, g2Aff := bls12377.Generators()
-	generator := g2Aff.Bytes()
+	generator := new(bls12377.G2).Generator().ToUncompressed()
 	_, err = bls12377G2.Point.Set(new(big.Int).SetBytes(generator[:96]), new(big.Int).SetBytes(generator[96:]))
 	require.NoError(t, err)
 }
@@ -400,8 +399,7 @@ func TestPointBls12377G1Generator(t *testing.T) {
 	sc := bls12377G1.Point.Generator()
 	s, ok := sc.(*PointBls12377G1)
 	require.True(t, ok)
-	_, _, g1Aff, _ := bls12377.Generators()
-	require.True(t, s.value.Equal(&g1Aff))
+	require.Equal(t, 1, s.Value.Equal(new(bls12377.G1).Generator()))
 }
 
 func TestPointBls12377G1Set(t *testing.T) {
@@ -409,8 +407,7 @@ func TestPointBls12377G1Set(t *testing.T) {
 	iden, err := bls12377G1.Point.Set(big.NewInt(0), big.NewInt(0))
 	require.NoError(t, err)
 	require.True(t, iden.IsIdentity())
-	_, _, g1Aff, _ := bls12377.Generators()
-	generator := g1Aff.Bytes()
+	generator := new(bls12377.G1).Generator().ToUncompressed()
 	_, err = bls12377G1.Point.Set(new(big.Int).SetBytes(generator[:48]), new(big.Int).SetBytes(generator[48:]))
 	require.NoError(t, err)
 }
@@ -515,3 +512,28 @@ func TestPointBls12377G1SumOfProducts(t *testing.T) {
 	require.NotNil(t, rhs)
 	require.True(t, lhs.Equal(rhs))
 }
+
+func TestPointBls12377Pairing(t *testing.T) {
+	g1 := BLS12377G1().Point.Generator().(PairingPoint)
+	g2 := BLS12377G2().Point.Generator().(PairingPoint)
+	a := BLS12377G1().Scalar.Random(crand.Reader)
+	b := BLS12377G1().Scalar.Random(crand.Reader)
+
+	aG1 := g1.Mul(a).(PairingPoint)
+	bG2 := g2.Mul(b).(PairingPoint)
+	lhs := aG1.Pairing(bG2)
+	require.NotNil(t, lhs)
+	rhs := g1.Mul(a.Mul(b)).(PairingPoint).Pairing(g2)
+	require.Equal(t, 0, lhs.Cmp(rhs))
+	require.Equal(t, 0, bG2.Pairing(aG1).Cmp(lhs))
+
+	// e(aG1, bG2) * e(-abG1, G2) = 1
+	negAB := g1.Mul(a.Mul(b)).Neg().(PairingPoint)
+	require.True(t, g1.MultiPairing(aG1, bG2, negAB, g2).IsOne())
+	require.Nil(t, g1.MultiPairing(aG1))
+	require.Nil(t, g1.MultiPairing(bG2, aG1))
+
+	gt := new(ScalarBls12377Gt).New(1)
+	require.True(t, gt.IsOne())
+	require.True(t, g1.Pairing(BLS12377G2().Point.Identity().(PairingPoint)).IsOne())
+}
@@ -16,6 +16,7 @@ import (
 	"math/big"
 	"sync"
 
+	"github.com/coinbase/kryptology/pkg/core/curves/native/bls12377"
 	"github.com/coinbase/kryptology/pkg/core/curves/native/bls12381"
 )
 
@@ -38,11 +39,23 @@ var (
 	p256Initonce sync.Once
 	p256         Curve
 
+	p384Initonce sync.Once
+	p384         Curve
+
+	p521Initonce sync.Once
+	p521         Curve
+
 	ed25519Initonce sync.Once
 	ed25519         Curve
 
 	pallasInitonce sync.Once
 	pallas         Curve
+
+	vestaInitonce sync.Once
+	vesta         Curve
+
+	sm2Initonce sync.Once
+	sm2         Curve
 )
 
 const (
@@ -51,11 +64,15 @@ const (
 	BLS12381G2Name = "BLS12381G2"
 	BLS12831Name   = "BLS12831"
 	P256Name       = "P-256"
+	P384Name       = "P-384"
+	P521Name       = "P-521"
 	ED25519Name    = "ed25519"
 	PallasName     = "pallas"
+	VestaName      = "vesta"
 	BLS12377G1Name = "BLS12377G1"
 	BLS12377G2Name = "BLS12377G2"
 	BLS12377Name   = "BLS12377"
+	SM2Name        = "sm2p256v1"
 )
 
 const scalarBytes = 32
@@ -149,15 +166,16 @@ func unmarshalScalar(input []byte) (*Curve, []byte, error) {
 }
 
 func scalarMarshalBinary(scalar Scalar) ([]byte, error) {
-	// All scalars are 32 bytes long
-	// The last 32 bytes are the actual value
+	// Most scalars are 32 bytes long, P-384 and P-521 scalars are longer
+	// The last bytes are the actual value
 	// The first remaining bytes are the curve name
 	// separated by a colon
 	name := []byte(scalar.Point().CurveName())
-	output := make([]byte, len(name)+1+scalarBytes)
+	value := scalar.Bytes()
+	output := make([]byte, len(name)+1+len(value))
 	copy(output[:len(name)], name)
 	output[len(name)] = byte(':')
-	copy(output[len(name)+1:], scalar.Bytes())
+	copy(output[len(name)+1:], value)
 	return output, nil
 }
 
@@ -176,15 +194,16 @@ func scalarUnmarshalBinary(input []byte) (Scalar, error) {
 }
 
 func scalarMarshalText(scalar Scalar) ([]byte, error) {
-	// All scalars are 32 bytes long
+	// Most scalars are 32 bytes long, P-384 and P-521 scalars are longer
 	// For text encoding we put the curve name first for readability
 	// separated by a colon, then the hex encoding of the scalar
 	// which avoids the base64 weakness with strict mode or not
 	name := []byte(scalar.Point().CurveName())
-	output := make([]byte, len(name)+1+scalarBytes*2)
+	value := scalar.Bytes()
+	output := make([]byte, len(name)+1+len(value)*2)
 	copy(output[:len(name)], name)
 	output[len(name)] = byte(':')
-	_ = hex.Encode(output[len(name)+1:], scalar.Bytes())
+	_ = hex.Encode(output[len(name)+1:], value)
 	return output, nil
 }
 
@@ -196,12 +215,12 @@ func scalarUnmarshalText(input []byte) (Scalar, error) {
 	if err != nil {
 		return nil, err
 	}
-	var t [scalarBytes]byte
-	_, err = hex.Decode(t[:], data)
+	t := make([]byte, hex.DecodedLen(len(data)))
+	_, err = hex.Decode(t, data)
 	if err != nil {
 		return nil, err
 	}
-	return curve.Scalar.SetBytes(t[:])
+	return curve.Scalar.SetBytes(t)
 }
 
 func scalarMarshalJson(scalar Scalar) ([]byte, error) {
@@ -402,16 +421,24 @@ func (c Curve) ToEllipticCurve() (elliptic.Curve, error) {
 		return nil, err
 	case P256Name:
 		return NistP256Curve(), nil
+	case P384Name:
+		return NistP384Curve(), nil
+	case P521Name:
+		return NistP521Curve(), nil
 	case ED25519Name:
 		return nil, err
 	case PallasName:
 		return nil, err
+	case VestaName:
+		return nil, err
 	case BLS12377G1Name:
 		return nil, err
 	case BLS12377G2Name:
 		return nil, err
 	case BLS12377Name:
 		return nil, err
+	case SM2Name:
+		return SM2P256Curve(), nil
 	default:
 		return nil, err
 	}
@@ -468,16 +495,24 @@ func GetCurveByName(name string) *Curve {
 		return BLS12381G1()
 	case P256Name:
 		return P256()
+	case P384Name:
+		return P384()
+	case P521Name:
+		return P521()
 	case ED25519Name:
 		return ED25519()
 	case PallasName:
 		return PALLAS()
+	case VestaName:
+		return VESTA()
 	case BLS12377G1Name:
 		return BLS12377G1()
 	case BLS12377G2Name:
 		return BLS12377G2()
 	case BLS12377Name:
 		return BLS12377G1()
+	case SM2Name:
+		return SM2()
 	default:
 		return nil
 	}
@@ -558,7 +593,7 @@ func BLS12377G1() *Curve {
 func bls12377g1Init() {
 	bls12377g1 = Curve{
 		Scalar: &ScalarBls12377{
-			value: new(big.Int),
+			Value: bls12377.Bls12377FqNew(),
 			point: new(PointBls12377G1),
 		},
 		Point: new(PointBls12377G1).Identity(),
@@ -575,7 +610,7 @@ func BLS12377G2() *Curve {
 func bls12377g2Init() {
 	bls12377g2 = Curve{
 		Scalar: &ScalarBls12377{
-			value: new(big.Int),
+			Value: bls12377.Bls12377FqNew(),
 			point: new(PointBls12377G2),
 		},
 		Point: new(PointBls12377G2).Identity(),
@@ -610,6 +645,34 @@ func p256Init() {
 	}
 }
 
+// P384 returns the NIST P-384 curve from FIPS 186-4
+func P384() *Curve {
+	p384Initonce.Do(p384Init)
+	return &p384
+}
+
+func p384Init() {
+	p384 = Curve{
+		Scalar: new(ScalarP384).Zero(),
+		Point:  new(PointP384).Identity(),
+		Name:   P384Name,
+	}
+}
+
+// P521 returns the NIST P-521 curve from FIPS 186-4
+func P521() *Curve {
+	p521Initonce.Do(p521Init)
+	return &p521
+}
+
+func p521Init() {
+	p521 = Curve{
+		Scalar: new(ScalarP521).Zero(),
+		Point:  new(PointP521).Identity(),
+		Name:   P521Name,
+	}
+}
+
 func ED25519() *Curve {
 	ed25519Initonce.Do(ed25519Init)
 	return &ed25519
@@ -636,6 +699,34 @@ func pallasInit() {
 	}
 }
 
+// VESTA returns the curve that forms the Pasta cycle with PALLAS: the scalar field of each is the base field of the other
+func VESTA() *Curve {
+	vestaInitonce.Do(vestaInit)
+	return &vesta
+}
+
+func vestaInit() {
+	vesta = Curve{
+		Scalar: new(ScalarVesta).Zero(),
+		Point:  new(PointVesta).Identity(),
+		Name:   VestaName,
+	}
+}
+
+// SM2 returns the SM2 recommended curve from GB/T 32918.5
+func SM2() *Curve {
+	sm2Initonce.Do(sm2Init)
+	return &sm2
+}
+
+func sm2Init() {
+	sm2 = Curve{
+		Scalar: new(ScalarSM2).Zero(),
+		Point:  new(PointSM2).Identity(),
+		Name:   SM2Name,
+	}
+}
+
 // https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-11#appendix-G.2.1
 func osswu3mod4(u *big.Int, p *sswuParams) (x, y *big.Int) {
 	params := p.Params
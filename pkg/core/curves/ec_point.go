@@ -81,26 +81,45 @@ func (a *EcPoint) UnmarshalJSON(bytes []byte) error {
 }
 
 func (a *EcPoint) MarshalBinary() ([]byte, error) {
-	result := [65]byte{}
 	if code, ok := curveNameToId[a.Curve.Params().Name]; ok {
+		size := binaryCoordinateSize(a.Curve)
+		result := make([]byte, 1+2*size)
 		result[0] = code
-		a.X.FillBytes(result[1:33])
-		a.Y.FillBytes(result[33:65])
-		return result[:], nil
+		a.X.FillBytes(result[1 : 1+size])
+		a.Y.FillBytes(result[1+size:])
+		return result, nil
 	}
 	return nil, fmt.Errorf("unknown curve serialized")
 }
 
 func (a *EcPoint) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("invalid length")
+	}
 	if mapper, ok := curveIdToName[data[0]]; ok {
-		a.Curve = mapper()
-		a.X = new(big.Int).SetBytes(data[1:33])
-		a.Y = new(big.Int).SetBytes(data[33:65])
+		curve := mapper()
+		size := binaryCoordinateSize(curve)
+		if len(data) < 1+2*size {
+			return fmt.Errorf("invalid length")
+		}
+		a.Curve = curve
+		a.X = new(big.Int).SetBytes(data[1 : 1+size])
+		a.Y = new(big.Int).SetBytes(data[1+size : 1+2*size])
 		return nil
 	}
 	return fmt.Errorf("unknown curve deserialized")
 }
 
+// binaryCoordinateSize is the width of each coordinate in the binary encoding.
+// Curves up to 256 bits always use 32 bytes to keep the original 65 byte format.
+func binaryCoordinateSize(curve elliptic.Curve) int {
+	size := internal.CalcFieldSize(curve)
+	if size < 32 {
+		return 32
+	}
+	return size
+}
+
 func (a EcPoint) IsValid() bool {
 	return a.IsOnCurve() || a.IsIdentity()
 }
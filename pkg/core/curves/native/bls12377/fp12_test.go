@@ -0,0 +1,85 @@
+package bls12377
+
+import (
+	crand "crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFp6Arithmetic(t *testing.T) {
+	var one fp6
+	one.SetOne()
+	require.Equal(t, 1, one.IsOne())
+
+	for i := 0; i < 10; i++ {
+		a, err := new(fp6).Random(crand.Reader)
+		require.NoError(t, err)
+		b, err := new(fp6).Random(crand.Reader)
+		require.NoError(t, err)
+
+		var c, d fp6
+		c.Square(a)
+		d.Mul(a, a)
+		require.Equal(t, 1, c.Equal(&d))
+
+		c.Mul(a, b)
+		d.Mul(b, a)
+		require.Equal(t, 1, c.Equal(&d))
+
+		_, wasInverted := c.Invert(a)
+		require.Equal(t, 1, wasInverted)
+		c.Mul(&c, a)
+		require.Equal(t, 1, c.IsOne())
+
+		// Frobenius is a^p, so applying it six times is the identity
+		c.Set(a)
+		for j := 0; j < 6; j++ {
+			c.FrobeniusMap(&c)
+		}
+		require.Equal(t, 1, c.Equal(a))
+	}
+}
+
+func TestFp12Arithmetic(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		a, err := new(fp12).Random(crand.Reader)
+		require.NoError(t, err)
+		b, err := new(fp12).Random(crand.Reader)
+		require.NoError(t, err)
+
+		var c, d fp12
+		c.Square(a)
+		d.Mul(a, a)
+		require.Equal(t, 1, c.Equal(&d))
+
+		c.Mul(a, b)
+		d.Mul(b, a)
+		require.Equal(t, 1, c.Equal(&d))
+
+		_, wasInverted := c.Invert(a)
+		require.Equal(t, 1, wasInverted)
+		c.Mul(&c, a)
+		require.Equal(t, 1, c.IsOne())
+
+		// Frobenius is a^p, so applying it twelve times is the identity
+		c.Set(a)
+		for j := 0; j < 12; j++ {
+			c.FrobeniusMap(&c)
+		}
+		require.Equal(t, 1, c.Equal(a))
+
+		// The sparse multiplication used by the line functions
+		// agrees with a full multiplication
+		x, _ := new(fp2).Random(crand.Reader)
+		y, _ := new(fp2).Random(crand.Reader)
+		z, _ := new(fp2).Random(crand.Reader)
+		var s fp12
+		s.A.A.Set(x)
+		s.B.A.Set(y)
+		s.B.B.Set(z)
+		c.MulByADE(a, x, y, z)
+		d.Mul(a, &s)
+		require.Equal(t, 1, c.Equal(&d))
+	}
+}
@@ -0,0 +1,343 @@
+package bls12377
+
+import (
+	"io"
+)
+
+// fp2 is a point in p^2
+type fp2 struct {
+	A, B fp
+}
+
+// Set copies a into fp2
+func (f *fp2) Set(a *fp2) *fp2 {
+	f.A.Set(&a.A)
+	f.B.Set(&a.B)
+	return f
+}
+
+// SetZero fp2 = 0
+func (f *fp2) SetZero() *fp2 {
+	f.A.SetZero()
+	f.B.SetZero()
+	return f
+}
+
+// SetOne fp2 to the multiplicative identity element
+func (f *fp2) SetOne() *fp2 {
+	f.A.SetOne()
+	f.B.SetZero()
+	return f
+}
+
+// SetFp creates an element from a lower field
+func (f *fp2) SetFp(a *fp) *fp2 {
+	f.A.Set(a)
+	f.B.SetZero()
+	return f
+}
+
+// Random generates a random field element
+func (f *fp2) Random(reader io.Reader) (*fp2, error) {
+	a, err := new(fp).Random(reader)
+	if err != nil {
+		return nil, err
+	}
+	b, err := new(fp).Random(reader)
+	if err != nil {
+		return nil, err
+	}
+	f.A = *a
+	f.B = *b
+	return f, nil
+}
+
+// IsZero returns 1 if fp2 == 0, 0 otherwise
+func (f *fp2) IsZero() int {
+	return f.A.IsZero() & f.B.IsZero()
+}
+
+// IsOne returns 1 if fp2 == 1, 0 otherwise
+func (f *fp2) IsOne() int {
+	return f.A.IsOne() & f.B.IsZero()
+}
+
+// Equal returns 1 if f == rhs, 0 otherwise
+func (f *fp2) Equal(rhs *fp2) int {
+	return f.A.Equal(&rhs.A) & f.B.Equal(&rhs.B)
+}
+
+// LexicographicallyLargest returns 1 if
+// this element is strictly lexicographically larger than its negation
+// 0 otherwise
+func (f *fp2) LexicographicallyLargest() int {
+	// If this element's B coefficient is lexicographically largest
+	// then it is lexicographically largest. Otherwise, in the event
+	// the B coefficient is zero and the A coefficient is
+	// lexicographically largest, then this element is lexicographically
+	// largest.
+
+	return f.B.LexicographicallyLargest() |
+		f.B.IsZero()&f.A.LexicographicallyLargest()
+}
+
+// Sgn0 returns the lowest bit value
+func (f *fp2) Sgn0() int {
+	// if A = 0 return B.Sgn0  else A.Sgn0
+	a := f.A.IsZero()
+	t := f.B.Sgn0() & a
+	a = -a + 1
+	t |= f.A.Sgn0() & a
+	return t
+}
+
+// FrobeniusMap raises this element to p.
+func (f *fp2) FrobeniusMap(a *fp2) *fp2 {
+	// This is always just a conjugation. If you're curious why, here's
+	// an article about it: https://alicebob.cryptoland.net/the-frobenius-endomorphism-with-finite-fields/
+	return f.Conjugate(a)
+}
+
+// Conjugate computes the conjugation of this element
+func (f *fp2) Conjugate(a *fp2) *fp2 {
+	f.A.Set(&a.A)
+	f.B.Neg(&a.B)
+	return f
+}
+
+// MulByNonResidue computes the following:
+// multiply a + bu by u, getting
+// au + bu^2
+// and because u^2 = -5, we get
+// -5b + au
+func (f *fp2) MulByNonResidue(a *fp2) *fp2 {
+	var aa, bb fp
+	aa.mulBy5(&a.B)
+	aa.Neg(&aa)
+	bb.Set(&a.A)
+	f.A.Set(&aa)
+	f.B.Set(&bb)
+	return f
+}
+
+// Square computes the square of this element
+func (f *fp2) Square(arg *fp2) *fp2 {
+	var a, b, c, v0 fp
+
+	// Complex squaring:
+	//
+	// v0  = a * b
+	// a' = (a + b) * (a + \beta*b) - v0 - \beta * v0
+	// b' = 2 * v0
+	//
+	// In BLS12-377's F_{p^2}, our \beta is -5, so we
+	// can modify this formula:
+	//
+	// a' = (a + b) * (a - 5b) + 4 * v0
+	// b' = 2 * v0
+	v0.Mul(&arg.A, &arg.B)
+	a.Add(&arg.A, &arg.B)
+	b.mulBy5(&arg.B)
+	b.Sub(&arg.A, &b)
+	c.Double(&v0)
+
+	a.Mul(&a, &b)
+	a.Add(&a, &c)
+	a.Add(&a, &c)
+	f.A.Set(&a)
+	f.B.Set(&c)
+	return f
+}
+
+// Add performs field addition
+func (f *fp2) Add(arg1, arg2 *fp2) *fp2 {
+	f.A.Add(&arg1.A, &arg2.A)
+	f.B.Add(&arg1.B, &arg2.B)
+	return f
+}
+
+// Double doubles specified element
+func (f *fp2) Double(a *fp2) *fp2 {
+	f.A.Double(&a.A)
+	f.B.Double(&a.B)
+	return f
+}
+
+// Sub performs field subtraction
+func (f *fp2) Sub(arg1, arg2 *fp2) *fp2 {
+	f.A.Sub(&arg1.A, &arg2.A)
+	f.B.Sub(&arg1.B, &arg2.B)
+	return f
+}
+
+// Mul computes Karatsuba multiplication
+func (f *fp2) Mul(arg1, arg2 *fp2) *fp2 {
+	var v0, v1, t, a, b fp
+
+	// Karatsuba multiplication:
+	//
+	// v0  = a0 * b0
+	// v1  = a1 * b1
+	// c0 = v0 + \beta * v1
+	// c1 = (a0 + a1) * (b0 + b1) - v0 - v1
+	//
+	// In BLS12-377's F_{p^2}, our \beta is -5, so we
+	// can modify this formula:
+	//
+	// c0 = v0 - 5 * v1
+	v0.Mul(&arg1.A, &arg2.A)
+	v1.Mul(&arg1.B, &arg2.B)
+
+	a.mulBy5(&v1)
+	a.Sub(&v0, &a)
+	b.Add(&arg1.A, &arg1.B)
+	t.Add(&arg2.A, &arg2.B)
+	b.Mul(&b, &t)
+	b.Sub(&b, &v0)
+	b.Sub(&b, &v1)
+	f.A.Set(&a)
+	f.B.Set(&b)
+	return f
+}
+
+func (f *fp2) Mul0(arg1 *fp2, arg2 *fp) *fp2 {
+	f.A.Mul(&arg1.A, arg2)
+	f.B.Mul(&arg1.B, arg2)
+	return f
+}
+
+// MulBy3b returns 3 * b * arg
+func (f *fp2) MulBy3b(arg *fp2) *fp2 {
+	return f.Mul(arg, &curveG23B)
+}
+
+// Neg performs field negation
+func (f *fp2) Neg(a *fp2) *fp2 {
+	f.A.Neg(&a.A)
+	f.B.Neg(&a.B)
+	return f
+}
+
+// Sqrt performs field square root
+func (f *fp2) Sqrt(a *fp2) (*fp2, int) {
+	// Algorithm 10, https://eprint.iacr.org/2012/685.pdf
+	// with constant time modifications. The nonsquare c is u,
+	// d = c^((p - 1) / 2), e = (d * c)^-1 and f = (d * c)^2 = 5.
+	var b, x0, x1, res, t fp2
+	var s, n, fiveX0 fp
+
+	// b = a^((p - 1) / 4)
+	b.pow(a, &[Limbs]uint64{
+		0x2142300000000000,
+		0x05c2d7510c000000,
+		0xc7bcd88bee825200,
+		0xc688b67cc03d44e3,
+		0xb18ec1701b28524e,
+		0x006b8e9185f1443a,
+	})
+	b.norm(&n)
+	e1 := n.IsOne()
+
+	// x0 = b^2 * a lies in fp when norm(b) = 1,
+	// otherwise x0 * f does
+	x0.Square(&b)
+	x0.Mul(&x0, a)
+	fiveX0.mulBy5(&x0.A)
+	s.CMove(&fiveX0, &x0.A, e1)
+	_, _ = s.Sqrt(&s)
+
+	x1.Conjugate(&b)
+	x1.Mul0(&x1, &s)
+	res.Mul(&x1, &fp2{
+		B: fp{
+			0xb808d0c529b417d3,
+			0x990ec6027c53dba3,
+			0xe0543cf6b3ef02dd,
+			0x2cb660d9ce2c51b1,
+			0x403085782b4db08c,
+			0x0044e3a38500e2ac,
+		},
+	})
+	res.CMove(&res, &x1, e1)
+
+	// is the result^2 = a
+	t.Square(&res)
+	e2 := t.Equal(a)
+	f.CMove(f, &res, e2)
+	return f, e2
+}
+
+// norm computes a^2 + 5b^2, the norm of a + bu
+func (f *fp2) norm(out *fp) *fp {
+	var t fp
+	t.Square(&f.B)
+	t.mulBy5(&t)
+	out.Square(&f.A)
+	return out.Add(out, &t)
+}
+
+// Legendre returns 1 if f is a nonzero square,
+// -1 if f is a nonsquare and 0 if f is zero
+func (f *fp2) Legendre() int {
+	var n fp
+	return f.norm(&n).Legendre()
+}
+
+// Invert computes the multiplicative inverse of this field
+// element, returning the original value of fp2
+// in the case that this element is zero.
+func (f *fp2) Invert(arg *fp2) (*fp2, int) {
+	// We wish to find the multiplicative inverse of a nonzero
+	// element a + bu in fp2. We leverage an identity
+	//
+	// (a + bu)(a - bu) = a^2 + 5b^2
+	//
+	// which holds because u^2 = -5. This can be rewritten as
+	//
+	// (a + bu)(a - bu)/(a^2 + 5b^2) = 1
+	//
+	// because a^2 + 5b^2 = 0 has no nonzero solutions for (a, b).
+	// This gives that (a - bu)/(a^2 + 5b^2) is the inverse
+	// of (a + bu). Importantly, this can be computing using
+	// only a single inversion in fp.
+	var a, b, t fp
+	arg.norm(&a)
+	_, wasInverted := t.Invert(&a)
+	// a * t
+	a.Mul(&arg.A, &t)
+	// b * -t
+	b.Neg(&t)
+	b.Mul(&b, &arg.B)
+	f.A.CMove(&f.A, &a, wasInverted)
+	f.B.CMove(&f.B, &b, wasInverted)
+	return f, wasInverted
+}
+
+// CMove performs conditional select.
+// selects arg1 if choice == 0 and arg2 if choice == 1
+func (f *fp2) CMove(arg1, arg2 *fp2, choice int) *fp2 {
+	f.A.CMove(&arg1.A, &arg2.A, choice)
+	f.B.CMove(&arg1.B, &arg2.B, choice)
+	return f
+}
+
+// CNeg conditionally negates a if choice == 1
+func (f *fp2) CNeg(a *fp2, choice int) *fp2 {
+	var t fp2
+	t.Neg(a)
+	return f.CMove(f, &t, choice)
+}
+
+func (f *fp2) pow(base *fp2, exp *[Limbs]uint64) *fp2 {
+	res := (&fp2{}).SetOne()
+	tmp := (&fp2{}).SetZero()
+
+	for i := len(exp) - 1; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			res.Square(res)
+			tmp.Mul(res, base)
+			res.CMove(res, tmp, int(exp[i]>>j)&1)
+		}
+	}
+	return f.Set(res)
+}
@@ -0,0 +1,103 @@
+package bls12377
+
+import (
+	crand "crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFp2Arithmetic(t *testing.T) {
+	for i := 0; i < 25; i++ {
+		a, err := new(fp2).Random(crand.Reader)
+		require.NoError(t, err)
+		b, err := new(fp2).Random(crand.Reader)
+		require.NoError(t, err)
+
+		var c, d fp2
+		c.Add(a, b)
+		c.Sub(&c, b)
+		require.Equal(t, 1, c.Equal(a))
+
+		c.Square(a)
+		d.Mul(a, a)
+		require.Equal(t, 1, c.Equal(&d))
+
+		c.Double(a)
+		d.Add(a, a)
+		require.Equal(t, 1, c.Equal(&d))
+
+		c.Mul(a, b)
+		d.Mul(b, a)
+		require.Equal(t, 1, c.Equal(&d))
+
+		c.Neg(a)
+		c.Add(&c, a)
+		require.Equal(t, 1, c.IsZero())
+
+		// multiplying by u
+		c.MulByNonResidue(a)
+		d.Mul(a, &fp2{B: r})
+		require.Equal(t, 1, c.Equal(&d))
+	}
+}
+
+func TestFp2NonResidue(t *testing.T) {
+	// u^2 = -5
+	var u, five fp2
+	u.B.SetOne()
+	u.Square(&u)
+	five.A.SetUint64(5)
+	five.Neg(&five)
+	require.Equal(t, 1, u.Equal(&five))
+}
+
+func TestFp2Invert(t *testing.T) {
+	var z fp2
+	_, wasInverted := z.Invert(&z)
+	require.Equal(t, 0, wasInverted)
+
+	for i := 0; i < 25; i++ {
+		a, err := new(fp2).Random(crand.Reader)
+		require.NoError(t, err)
+		var b fp2
+		_, wasInverted = b.Invert(a)
+		require.Equal(t, 1, wasInverted)
+		b.Mul(&b, a)
+		require.Equal(t, 1, b.IsOne())
+	}
+}
+
+func TestFp2Sqrt(t *testing.T) {
+	var a, sq, res fp2
+	a.A.SetUint64(3)
+	a.B.SetUint64(7)
+	require.Equal(t, -1, a.Legendre())
+	_, wasSquare := res.Sqrt(&a)
+	require.Equal(t, 0, wasSquare)
+
+	sq.Square(&a)
+	require.Equal(t, 1, sq.Legendre())
+	_, wasSquare = res.Sqrt(&sq)
+	require.Equal(t, 1, wasSquare)
+	require.Equal(t, 1, res.Equal(&a))
+
+	for i := 0; i < 25; i++ {
+		b, err := new(fp2).Random(crand.Reader)
+		require.NoError(t, err)
+		sq.Square(b)
+		_, wasSquare = res.Sqrt(&sq)
+		require.Equal(t, 1, wasSquare)
+		res.Square(&res)
+		require.Equal(t, 1, res.Equal(&sq))
+	}
+}
+
+func TestFp2LexicographicallyLargest(t *testing.T) {
+	require.Equal(t, 0, new(fp2).SetZero().LexicographicallyLargest())
+	require.Equal(t, 0, new(fp2).SetOne().LexicographicallyLargest())
+
+	var a fp2
+	a.Neg(new(fp2).SetOne())
+	require.Equal(t, 1, a.LexicographicallyLargest())
+}
@@ -0,0 +1,181 @@
+package bls12377
+
+import (
+	crand "crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFpSetOne(t *testing.T) {
+	var f fp
+	f.SetOne()
+	require.NotNil(t, f)
+	require.Equal(t, f, r)
+	require.Equal(t, 1, f.IsOne())
+}
+
+func TestFpSetUint64(t *testing.T) {
+	var act fp
+	act.SetUint64(1 << 60)
+	require.Equal(t, uint64(1<<60), act.BigInt().Uint64())
+}
+
+func TestFpAddSubNeg(t *testing.T) {
+	p := new(big.Int).SetBytes(fpModulusBytes())
+	for i := 0; i < 25; i++ {
+		a, err := new(fp).Random(crand.Reader)
+		require.NoError(t, err)
+		b, err := new(fp).Random(crand.Reader)
+		require.NoError(t, err)
+
+		var c fp
+		c.Add(a, b)
+		e := new(big.Int).Add(a.BigInt(), b.BigInt())
+		require.Equal(t, 0, e.Mod(e, p).Cmp(c.BigInt()))
+
+		c.Sub(a, b)
+		e.Sub(a.BigInt(), b.BigInt())
+		require.Equal(t, 0, e.Mod(e, p).Cmp(c.BigInt()))
+
+		c.Neg(a)
+		c.Add(&c, a)
+		require.Equal(t, 1, c.IsZero())
+
+		c.Double(a)
+		e.Lsh(a.BigInt(), 1)
+		require.Equal(t, 0, e.Mod(e, p).Cmp(c.BigInt()))
+	}
+}
+
+func TestFpMulSquare(t *testing.T) {
+	p := new(big.Int).SetBytes(fpModulusBytes())
+	for i := 0; i < 25; i++ {
+		a, err := new(fp).Random(crand.Reader)
+		require.NoError(t, err)
+		b, err := new(fp).Random(crand.Reader)
+		require.NoError(t, err)
+
+		var c fp
+		c.Mul(a, b)
+		e := new(big.Int).Mul(a.BigInt(), b.BigInt())
+		require.Equal(t, 0, e.Mod(e, p).Cmp(c.BigInt()))
+
+		c.Square(a)
+		e.Mul(a.BigInt(), a.BigInt())
+		require.Equal(t, 0, e.Mod(e, p).Cmp(c.BigInt()))
+
+		c.mulBy5(a)
+		e.Mul(a.BigInt(), big.NewInt(5))
+		require.Equal(t, 0, e.Mod(e, p).Cmp(c.BigInt()))
+	}
+}
+
+func TestFpInvert(t *testing.T) {
+	var z fp
+	_, wasInverted := z.Invert(&z)
+	require.Equal(t, 0, wasInverted)
+
+	for i := 0; i < 25; i++ {
+		a, err := new(fp).Random(crand.Reader)
+		require.NoError(t, err)
+		var b fp
+		_, wasInverted = b.Invert(a)
+		require.Equal(t, 1, wasInverted)
+		b.Mul(&b, a)
+		require.Equal(t, 1, b.IsOne())
+	}
+}
+
+func TestFpSqrt(t *testing.T) {
+	var tv, res fp
+	tv.SetUint64(4)
+	_, wasSquare := res.Sqrt(&tv)
+	require.Equal(t, 1, wasSquare)
+	require.Equal(t, uint64(2), res.BigInt().Uint64())
+
+	e, _ := new(big.Int).SetString("61134141799337779744243169579317764548490943457438569789767076791016838392692895365021181670618017873462480451586", 10)
+	tv.SetUint64(12)
+	_, wasSquare = res.Sqrt(&tv)
+	require.Equal(t, 1, wasSquare)
+	require.Equal(t, 0, e.Cmp(res.BigInt()))
+
+	// 5 generates the 2-adic subgroup so it's not a square
+	tv.SetUint64(5)
+	_, wasSquare = res.Sqrt(&tv)
+	require.Equal(t, 0, wasSquare)
+	require.Equal(t, -1, tv.Legendre())
+
+	for i := 0; i < 25; i++ {
+		a, err := new(fp).Random(crand.Reader)
+		require.NoError(t, err)
+		tv.Square(a)
+		require.Equal(t, 1, tv.Legendre())
+		_, wasSquare = res.Sqrt(&tv)
+		require.Equal(t, 1, wasSquare)
+		res.Square(&res)
+		require.Equal(t, 1, res.Equal(&tv))
+	}
+	tv.SetZero()
+	require.Equal(t, 0, tv.Legendre())
+}
+
+func TestFpBytes(t *testing.T) {
+	for i := 0; i < 25; i++ {
+		a, err := new(fp).Random(crand.Reader)
+		require.NoError(t, err)
+		seq := a.Bytes()
+		b, isCanonical := new(fp).SetBytes(&seq)
+		require.Equal(t, 1, isCanonical)
+		require.Equal(t, 1, a.Equal(b))
+	}
+
+	// The modulus itself isn't a canonical encoding
+	var seq [FieldBytes]byte
+	m := fpModulusBytes()
+	for i := range m {
+		seq[i] = m[len(m)-1-i]
+	}
+	_, isCanonical := new(fp).SetBytes(&seq)
+	require.Equal(t, 0, isCanonical)
+}
+
+func TestFpBigInt(t *testing.T) {
+	for i := 0; i < 25; i++ {
+		a, err := new(fp).Random(crand.Reader)
+		require.NoError(t, err)
+		b := new(fp).SetBigInt(a.BigInt())
+		require.Equal(t, 1, a.Equal(b))
+	}
+}
+
+func TestFpLexicographicallyLargest(t *testing.T) {
+	require.Equal(t, 0, new(fp).SetZero().LexicographicallyLargest())
+	require.Equal(t, 0, new(fp).SetOne().LexicographicallyLargest())
+
+	var a fp
+	a.Neg(new(fp).SetOne())
+	require.Equal(t, 1, a.LexicographicallyLargest())
+}
+
+func TestFpCMove(t *testing.T) {
+	var a, b, c fp
+	a.SetUint64(3)
+	b.SetUint64(7)
+	c.CMove(&a, &b, 1)
+	require.Equal(t, 1, c.Equal(&b))
+	c.CMove(&a, &b, 0)
+	require.Equal(t, 1, c.Equal(&a))
+}
+
+// fpModulusBytes returns p big-endian
+func fpModulusBytes() []byte {
+	var out [FieldBytes]byte
+	for i, l := range modulus {
+		for j := 0; j < 8; j++ {
+			out[FieldBytes-1-8*i-j] = byte(l >> (8 * j))
+		}
+	}
+	return out[:]
+}
@@ -0,0 +1,72 @@
+package bls12377
+
+import (
+	crand "crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFqSetOne(t *testing.T) {
+	fq := Bls12377FqNew().SetOne()
+	require.NotNil(t, fq)
+	require.Equal(t, fq.Value, getBls12377FqParams().R)
+}
+
+func TestFqArithmetic(t *testing.T) {
+	q := getBls12377FqParams().BiModulus
+	for i := 0; i < 25; i++ {
+		var wide [64]byte
+		_, _ = crand.Read(wide[:])
+		a := Bls12377FqNew().SetBytesWide(&wide)
+		_, _ = crand.Read(wide[:])
+		b := Bls12377FqNew().SetBytesWide(&wide)
+
+		e := new(big.Int).Add(a.BigInt(), b.BigInt())
+		require.Equal(t, 0, e.Mod(e, q).Cmp(Bls12377FqNew().Add(a, b).BigInt()))
+
+		e.Sub(a.BigInt(), b.BigInt())
+		require.Equal(t, 0, e.Mod(e, q).Cmp(Bls12377FqNew().Sub(a, b).BigInt()))
+
+		e.Mul(a.BigInt(), b.BigInt())
+		require.Equal(t, 0, e.Mod(e, q).Cmp(Bls12377FqNew().Mul(a, b).BigInt()))
+
+		e.Mul(a.BigInt(), a.BigInt())
+		require.Equal(t, 0, e.Mod(e, q).Cmp(Bls12377FqNew().Square(a).BigInt()))
+
+		e.Neg(a.BigInt())
+		require.Equal(t, 0, e.Mod(e, q).Cmp(Bls12377FqNew().Neg(a).BigInt()))
+
+		c, inverted := Bls12377FqNew().Invert(a)
+		require.True(t, inverted)
+		require.Equal(t, 1, c.Mul(c, a).IsOne())
+	}
+}
+
+func TestFqSqrt(t *testing.T) {
+	// Matches math/big so scalar encodings stay stable
+	q := getBls12377FqParams().BiModulus
+	for _, v := range []uint64{4, 9, 12, 1 << 60} {
+		e := new(big.Int).ModSqrt(new(big.Int).SetUint64(v), q)
+		require.NotNil(t, e)
+		a, wasSquare := Bls12377FqNew().Sqrt(Bls12377FqNew().SetUint64(v))
+		require.True(t, wasSquare)
+		require.Equal(t, 0, e.Cmp(a.BigInt()))
+	}
+
+	_, wasSquare := Bls12377FqNew().Sqrt(Bls12377FqNew().SetUint64(11))
+	require.False(t, wasSquare)
+}
+
+func TestFqBytes(t *testing.T) {
+	for i := 0; i < 25; i++ {
+		var wide [64]byte
+		_, _ = crand.Read(wide[:])
+		a := Bls12377FqNew().SetBytesWide(&wide)
+		seq := a.Bytes()
+		b, err := Bls12377FqNew().SetBytes(&seq)
+		require.NoError(t, err)
+		require.Equal(t, 1, a.Equal(b))
+	}
+}
@@ -0,0 +1,152 @@
+package bls12377
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+)
+
+func TestG1IsOnCurve(t *testing.T) {
+	require.Equal(t, 1, new(G1).Identity().IsOnCurve())
+	require.Equal(t, 1, new(G1).Generator().IsOnCurve())
+
+	var z fp
+	z.SetUint64(0xdeadbeef)
+	gen := new(G1).Generator()
+	test := G1{
+		x: *(gen.x.Mul(&gen.x, &z)),
+		y: *(gen.y.Mul(&gen.y, &z)),
+		z: z,
+	}
+	require.Equal(t, 1, test.IsOnCurve())
+	test.x = z
+	require.Equal(t, 0, test.IsOnCurve())
+}
+
+func TestG1Arithmetic(t *testing.T) {
+	g := new(G1).Generator()
+	a := new(G1).Double(g)
+	b := new(G1).Add(g, g)
+	require.Equal(t, 1, a.Equal(b))
+
+	a.Add(a, g)
+	b.Mul(g, Bls12377FqNew().SetUint64(3))
+	require.Equal(t, 1, a.Equal(b))
+
+	a.Sub(a, a)
+	require.Equal(t, 1, a.IsIdentity())
+
+	a.Neg(g)
+	a.Add(a, g)
+	require.Equal(t, 1, a.IsIdentity())
+
+	a.Add(g, new(G1).Identity())
+	require.Equal(t, 1, a.Equal(g))
+}
+
+func TestG1Mul(t *testing.T) {
+	e, _ := hex.DecodeString("a0cd5fb44e329b5cf273dd48d54cd8e913eccfb95bea95d3520c7a27e9bef173a30f7ba09077f0f7eb22ef9fb1106bf8")
+	var expected [FieldBytes]byte
+	copy(expected[:], e)
+
+	a := new(G1).Mul(new(G1).Generator(), Bls12377FqNew().SetUint64(0xdeadbeefcafebabe))
+	require.Equal(t, expected, a.ToCompressed())
+
+	// the group order annihilates the generator
+	a.multiply(new(G1).Generator(), &fqModulusBytes)
+	require.Equal(t, 1, a.IsIdentity())
+}
+
+func TestG1MulByX(t *testing.T) {
+	x := Bls12377FqNew().SetUint64(paramX)
+	for i := 0; i < 5; i++ {
+		a, err := new(G1).Random(crand.Reader)
+		require.NoError(t, err)
+		b := new(G1).MulByX(a)
+		c := new(G1).Mul(a, x)
+		require.Equal(t, 1, b.Equal(c))
+	}
+}
+
+func TestG1ClearCofactor(t *testing.T) {
+	a := new(G1)
+	// (3, sqrt(28)) is on the curve but not in the subgroup
+	a.x.SetUint64(3)
+	_, wasSquare := a.y.Sqrt(new(fp).SetUint64(28))
+	require.Equal(t, 1, wasSquare)
+	a.z.SetOne()
+	require.Equal(t, 1, a.IsOnCurve())
+	require.Equal(t, 0, a.InCorrectSubgroup())
+	a.ClearCofactor(a)
+	require.Equal(t, 1, a.IsOnCurve())
+	require.Equal(t, 1, a.InCorrectSubgroup())
+	require.Equal(t, 0, a.IsIdentity())
+}
+
+func TestG1Hash(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-BLS12377G1_XMD:SHA-256_SVDW_RO_")
+	tests := []struct {
+		input, expected string
+	}{
+		{"", "80bc386f2d7ea1e5f5141c4b0abbbdce60c86a2c51f7fcef71110d358912a9dcc7b870749a28dc406511311150dbde80"},
+		{"abc", "a0a3203d5e166d928c07996525fd24ac19883a4ee1357464fbd8080fc1242f6228834aaee4d916b98be61fb61b3e8ce5"},
+		{"abcdef0123456789", "80117f8158d98e263dd061c0d5a7d19705be0badc2eadc6d784bf619166ac00faa692c7d03df307fb7aa76bcca57b8e0"},
+	}
+
+	pt := new(G1).Identity()
+	for _, tst := range tests {
+		e, _ := hex.DecodeString(tst.expected)
+		var b [FieldBytes]byte
+		copy(b[:], e)
+		pt.Hash(native.EllipticPointHasherSha256(), []byte(tst.input), dst)
+		require.Equal(t, 1, pt.InCorrectSubgroup())
+		require.Equal(t, b, pt.ToCompressed())
+	}
+}
+
+func TestG1Serialization(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		a, err := new(G1).Random(crand.Reader)
+		require.NoError(t, err)
+
+		c := a.ToCompressed()
+		b, err := new(G1).FromCompressed(&c)
+		require.NoError(t, err)
+		require.Equal(t, 1, a.Equal(b))
+
+		u := a.ToUncompressed()
+		b, err = new(G1).FromUncompressed(&u)
+		require.NoError(t, err)
+		require.Equal(t, 1, a.Equal(b))
+
+		x, y := a.BigInt()
+		b, err = new(G1).SetBigInt(x, y)
+		require.NoError(t, err)
+		require.Equal(t, 1, a.Equal(b))
+	}
+
+	c := new(G1).Identity().ToCompressed()
+	b, err := new(G1).FromCompressed(&c)
+	require.NoError(t, err)
+	require.Equal(t, 1, b.IsIdentity())
+}
+
+func TestG1SumOfProducts(t *testing.T) {
+	points := make([]*G1, 5)
+	scalars := make([]*native.Field, 5)
+	expected := new(G1).Identity()
+	for i := range points {
+		var wide [native.WideFieldBytes]byte
+		_, _ = crand.Read(wide[:])
+		points[i], _ = new(G1).Random(crand.Reader)
+		scalars[i] = Bls12377FqNew().SetBytesWide(&wide)
+		expected.Add(expected, new(G1).Mul(points[i], scalars[i]))
+	}
+	actual, err := new(G1).SumOfProducts(points, scalars)
+	require.NoError(t, err)
+	require.Equal(t, 1, expected.Equal(actual))
+}
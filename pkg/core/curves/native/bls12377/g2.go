@@ -0,0 +1,824 @@
+package bls12377
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/internal"
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+)
+
+var (
+	g2x = fp2{
+		A: fp{
+			0x68904082f268725b,
+			0x668f2ea74f45328b,
+			0xebca7a65802be84f,
+			0x1e1850f4c1ada3e6,
+			0x830dc22d588ef1e9,
+			0x01862a81767c0982,
+		},
+		B: fp{
+			0x5f02a915c91c7f39,
+			0xf8c553ba388da2a7,
+			0xd51a416dbd198850,
+			0xe943c6f38ae3073a,
+			0xffe24aa8259a4981,
+			0x011853391e73dfdd,
+		},
+	}
+	g2y = fp2{
+		A: fp{
+			0xd5b19b897881430f,
+			0x05be9118a5b371ed,
+			0x6063f91f86c131ee,
+			0x3244a61be8f4ec19,
+			0xa02e425b9f9a3a12,
+			0x018af8c04f3360d2,
+		},
+		B: fp{
+			0x57601ac71a5b96f5,
+			0xe99acc1714f2440e,
+			0x2339612f10118ea9,
+			0x8321e68a3b1cd722,
+			0x2b543b050cc74917,
+			0x00590182b396c112,
+		},
+	}
+	curveG2B = fp2{
+		A: fp{
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+		},
+		B: fp{
+			0x8072266666666685,
+			0x8df55926899999a9,
+			0x7fe4561ad64f34cf,
+			0xb95da6d8b6e4f01b,
+			0x4b747cccfc142743,
+			0x0039c3fa70f49f43,
+		},
+	}
+	curveG23B = fp2{
+		A: fp{
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+		},
+		B: fp{
+			0x815673333333338f,
+			0xa9e00b739cccccfc,
+			0x7fad025082ed9e6e,
+			0x2c18f48a24aed052,
+			0xe25d7666f43c75cb,
+			0x00ad4bef52ddddc9,
+		},
+	}
+	psiCoeffX = fp2{
+		A: fp{
+			0x5892506da58478da,
+			0x133366940ac2a74b,
+			0x9b64a150cdf726cf,
+			0x5cc426090a9c587e,
+			0x5cf848adfdcd640c,
+			0x004702bf3ac02380,
+		},
+		B: fp{
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+		},
+	}
+	psiCoeffY = fp2{
+		A: fp{
+			0x982c13d9d084771f,
+			0xfd49de0c6da34a32,
+			0x61a530d183ab0e53,
+			0xdf8fe44106dd9879,
+			0x40f29b58d88472bc,
+			0x0158723199046d5d,
+		},
+		B: fp{
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+			0x0000000000000000,
+		},
+	}
+	svdwG2MapZ = fp2{
+		A: fp{
+			0x823ac00000000099,
+			0xc5cabdc0b000004f,
+			0x7f75ae862f8c080d,
+			0x9ed4423b9278b089,
+			0x79467000ec64c452,
+			0x0120d3e434c71c50,
+		},
+		B: fp{
+			0x823ac00000000099,
+			0xc5cabdc0b000004f,
+			0x7f75ae862f8c080d,
+			0x9ed4423b9278b089,
+			0x79467000ec64c452,
+			0x0120d3e434c71c50,
+		},
+	}
+	svdwG2MapC1 = fp2{
+		A: fp{
+			0x1320fffffffff7ac,
+			0x155b44203ffffbac,
+			0x3d124a86aab45f40,
+			0x55c0e43c06f91c12,
+			0x1c74197750ca1fca,
+			0x0102b0420acf1cc5,
+		},
+		B: fp{
+			0x860e266666666555,
+			0x3076982d8999990b,
+			0xbedfbd6deb49b4b4,
+			0xaffad64793ddb626,
+			0xe55da84bfc8d3114,
+			0x015490be36f08877,
+		},
+	}
+	svdwG2MapC2 = fp2{
+		A: fp{
+			0x8166ffffffffffb4,
+			0x28a04fc1bfffffd8,
+			0xcfbed9d4c53e9ff9,
+			0x3da74bdbb73e3182,
+			0x267a4adfc01e4274,
+			0x0046b330f17efa4d,
+		},
+		B: fp{
+			0x8166ffffffffffb4,
+			0x28a04fc1bfffffd8,
+			0xcfbed9d4c53e9ff9,
+			0x3da74bdbb73e3182,
+			0x267a4adfc01e4274,
+			0x0046b330f17efa4d,
+		},
+	}
+	svdwG2MapC3 = fp2{
+		A: fp{
+			0xe07058c9ac758105,
+			0xcdd8321d90b2ad87,
+			0xe3e3e86b68f436e3,
+			0x20c0833a9b96a8a3,
+			0xfe217be22d7322a8,
+			0x0030c6187d1ef812,
+		},
+		B: fp{
+			0xd9f6bc849848ff5f,
+			0x4e84abe9ef387e3e,
+			0xe085529ac10f8b0a,
+			0x22c5ac6b8f0d618b,
+			0x5b0d640b94c92c19,
+			0x012ac7e91ce6eb22,
+		},
+	}
+	svdwG2MapC4 = fp2{
+		A: fp{
+			0xe293612f684bd93d,
+			0xecc2d3909f684b6a,
+			0x6410321c40c2e58d,
+			0x3150e06af0cbe974,
+			0x77edbf949d7e9be6,
+			0x00b712ad18a9f8d0,
+		},
+		B: fp{
+			0x32779d6480f2b910,
+			0x58070cf6cae758b9,
+			0x41d4c7d57a787858,
+			0x79a0e8a4d431ae2f,
+			0xbcb6ec4390d37cc5,
+			0x00855a8a4559a713,
+		},
+	}
+	// thirdRootOne is a primitive cube root of unity in fp
+	thirdRootOne = fp{
+		0xdacd106da5847973,
+		0xd8fe2454bac2a79a,
+		0x1ada4fd6fd832edc,
+		0xfb9868449d150908,
+		0xd63eb8aeea32285e,
+		0x0167d6a36f873fd0,
+	}
+)
+
+// G2 is a point in g2
+type G2 struct {
+	x, y, z fp2
+}
+
+// Random creates a random point on the curve
+// from the specified reader
+func (g2 *G2) Random(reader io.Reader) (*G2, error) {
+	var seed [native.WideFieldBytes]byte
+	n, err := reader.Read(seed[:])
+
+	if err != nil {
+		return nil, errors.Wrap(err, "random could not read from stream")
+	}
+	if n != native.WideFieldBytes {
+		return nil, fmt.Errorf("insufficient bytes read %d when %d are needed", n, WideFieldBytes)
+	}
+	dst := []byte("BLS12377G2_XMD:SHA-256_SVDW_RO_")
+	return g2.Hash(native.EllipticPointHasherSha256(), seed[:], dst), nil
+}
+
+// Hash uses the hasher to map bytes to a valid point
+func (g2 *G2) Hash(hash *native.EllipticPointHasher, msg, dst []byte) *G2 {
+	var u []byte
+	var u0, u1 fp2
+	var q0, q1 G2
+
+	switch hash.Type() {
+	case native.XMD:
+		u = native.ExpandMsgXmd(hash, msg, dst, 256)
+	case native.XOF:
+		u = native.ExpandMsgXof(hash, msg, dst, 256)
+	}
+
+	var buf [96]byte
+	copy(buf[:64], internal.ReverseScalarBytes(u[:64]))
+	u0.A.SetBytesWide(&buf)
+	copy(buf[:64], internal.ReverseScalarBytes(u[64:128]))
+	u0.B.SetBytesWide(&buf)
+	copy(buf[:64], internal.ReverseScalarBytes(u[128:192]))
+	u1.A.SetBytesWide(&buf)
+	copy(buf[:64], internal.ReverseScalarBytes(u[192:]))
+	u1.B.SetBytesWide(&buf)
+
+	q0.svdw(&u0)
+	q1.svdw(&u1)
+	g2.Add(&q0, &q1)
+	return g2.ClearCofactor(g2)
+}
+
+// Identity returns the identity point
+func (g2 *G2) Identity() *G2 {
+	g2.x.SetZero()
+	g2.y.SetOne()
+	g2.z.SetZero()
+	return g2
+}
+
+// Generator returns the base point
+func (g2 *G2) Generator() *G2 {
+	g2.x.Set(&g2x)
+	g2.y.Set(&g2y)
+	g2.z.SetOne()
+	return g2
+}
+
+// IsIdentity returns true if this point is at infinity
+func (g2 *G2) IsIdentity() int {
+	return g2.z.IsZero()
+}
+
+// IsOnCurve determines if this point represents a valid curve point
+func (g2 *G2) IsOnCurve() int {
+	// Y^2 Z = X^3 + b Z^3
+	var lhs, rhs, t fp2
+	lhs.Square(&g2.y)
+	lhs.Mul(&lhs, &g2.z)
+
+	rhs.Square(&g2.x)
+	rhs.Mul(&rhs, &g2.x)
+	t.Square(&g2.z)
+	t.Mul(&t, &g2.z)
+	t.Mul(&t, &curveG2B)
+	rhs.Add(&rhs, &t)
+
+	return lhs.Equal(&rhs)
+}
+
+// InCorrectSubgroup returns 1 if the point is torsion free, 0 otherwise
+func (g2 *G2) InCorrectSubgroup() int {
+	var t G2
+	t.multiply(g2, &fqModulusBytes)
+	return t.IsIdentity()
+}
+
+// Add adds this point to another point.
+func (g2 *G2) Add(arg1, arg2 *G2) *G2 {
+	// Algorithm 7, https://eprint.iacr.org/2015/1060.pdf
+	var t0, t1, t2, t3, t4, x3, y3, z3 fp2
+
+	t0.Mul(&arg1.x, &arg2.x)
+	t1.Mul(&arg1.y, &arg2.y)
+	t2.Mul(&arg1.z, &arg2.z)
+	t3.Add(&arg1.x, &arg1.y)
+	t4.Add(&arg2.x, &arg2.y)
+	t3.Mul(&t3, &t4)
+	t4.Add(&t0, &t1)
+	t3.Sub(&t3, &t4)
+	t4.Add(&arg1.y, &arg1.z)
+	x3.Add(&arg2.y, &arg2.z)
+	t4.Mul(&t4, &x3)
+	x3.Add(&t1, &t2)
+	t4.Sub(&t4, &x3)
+	x3.Add(&arg1.x, &arg1.z)
+	y3.Add(&arg2.x, &arg2.z)
+	x3.Mul(&x3, &y3)
+	y3.Add(&t0, &t2)
+	y3.Sub(&x3, &y3)
+	x3.Double(&t0)
+	t0.Add(&t0, &x3)
+	t2.MulBy3b(&t2)
+	z3.Add(&t1, &t2)
+	t1.Sub(&t1, &t2)
+	y3.MulBy3b(&y3)
+	x3.Mul(&t4, &y3)
+	t2.Mul(&t3, &t1)
+	x3.Sub(&t2, &x3)
+	y3.Mul(&y3, &t0)
+	t1.Mul(&t1, &z3)
+	y3.Add(&t1, &y3)
+	t0.Mul(&t0, &t3)
+	z3.Mul(&z3, &t4)
+	z3.Add(&z3, &t0)
+
+	g2.x.Set(&x3)
+	g2.y.Set(&y3)
+	g2.z.Set(&z3)
+	return g2
+}
+
+// Sub subtracts the two points
+func (g2 *G2) Sub(arg1, arg2 *G2) *G2 {
+	var t G2
+	t.Neg(arg2)
+	return g2.Add(arg1, &t)
+}
+
+// Double this point
+func (g2 *G2) Double(a *G2) *G2 {
+	// Algorithm 9, https://eprint.iacr.org/2015/1060.pdf
+	var t0, t1, t2, x3, y3, z3 fp2
+
+	t0.Square(&a.y)
+	z3.Double(&t0)
+	z3.Double(&z3)
+	z3.Double(&z3)
+	t1.Mul(&a.y, &a.z)
+	t2.Square(&a.z)
+	t2.MulBy3b(&t2)
+	x3.Mul(&t2, &z3)
+	y3.Add(&t0, &t2)
+	z3.Mul(&t1, &z3)
+	t1.Double(&t2)
+	t2.Add(&t2, &t1)
+	t0.Sub(&t0, &t2)
+	y3.Mul(&t0, &y3)
+	y3.Add(&y3, &x3)
+	t1.Mul(&a.x, &a.y)
+	x3.Mul(&t0, &t1)
+	x3.Double(&x3)
+
+	e := a.IsIdentity()
+	g2.x.CMove(&x3, t0.SetZero(), e)
+	g2.z.CMove(&z3, &t0, e)
+	g2.y.CMove(&y3, t0.SetOne(), e)
+	return g2
+}
+
+// Mul multiplies this point by the input scalar
+func (g2 *G2) Mul(a *G2, s *native.Field) *G2 {
+	bytes := s.Bytes()
+	return g2.multiply(a, &bytes)
+}
+
+func (g2 *G2) multiply(a *G2, bytes *[native.FieldBytes]byte) *G2 {
+	var p G2
+	precomputed := [16]*G2{}
+	precomputed[0] = new(G2).Identity()
+	precomputed[1] = new(G2).Set(a)
+	for i := 2; i < 16; i += 2 {
+		precomputed[i] = new(G2).Double(precomputed[i>>1])
+		precomputed[i+1] = new(G2).Add(precomputed[i], a)
+	}
+	p.Identity()
+	for i := 0; i < 256; i += 4 {
+		// Brouwer / windowing method. window size of 4.
+		for j := 0; j < 4; j++ {
+			p.Double(&p)
+		}
+		window := bytes[32-1-i>>3] >> (4 - i&0x04) & 0x0F
+		p.Add(&p, precomputed[window])
+	}
+	return g2.Set(&p)
+}
+
+// MulByX multiplies by BLS X using double and add
+func (g2 *G2) MulByX(a *G2) *G2 {
+	var s, t, r G2
+	r.Identity()
+	t.Set(a)
+
+	for x := paramX; x != 0; x >>= 1 {
+		s.Add(&r, &t)
+		r.CMove(&r, &s, int(x&1))
+		t.Double(&t)
+	}
+	return g2.Set(&r)
+}
+
+// ClearCofactor using [Budroni-Pintore](https://ia.cr/2017/419).
+// This computes [z^2 - z - 1]P + psi([z - 1]P) - phi(2P)
+// where z is the parameter of BLS12-377 and phi maps (x, y) to (wx, y)
+// for a cube root of unity w.
+func (g2 *G2) ClearCofactor(a *G2) *G2 {
+	var xg, xxg, pt, t G2
+
+	xg.MulByX(a)
+	xxg.MulByX(&xg)
+
+	pt.Sub(&xxg, &xg)
+	pt.Sub(&pt, a)
+
+	t.Sub(&xg, a)
+	t.psi(&t)
+	pt.Add(&pt, &t)
+
+	t.Double(a)
+	t.x.Mul0(&t.x, &thirdRootOne)
+	pt.Sub(&pt, &t)
+	return g2.Set(&pt)
+}
+
+// Neg negates this point
+func (g2 *G2) Neg(a *G2) *G2 {
+	g2.Set(a)
+	g2.y.CNeg(&a.y, -(a.IsIdentity() - 1))
+	return g2
+}
+
+// Set copies a into g2
+func (g2 *G2) Set(a *G2) *G2 {
+	g2.x.Set(&a.x)
+	g2.y.Set(&a.y)
+	g2.z.Set(&a.z)
+	return g2
+}
+
+// BigInt returns the x and y as big.Ints in affine
+func (g2 *G2) BigInt() (x, y *big.Int) {
+	out := g2.ToUncompressed()
+	x = new(big.Int).SetBytes(out[:WideFieldBytes])
+	y = new(big.Int).SetBytes(out[WideFieldBytes:])
+	return
+}
+
+// SetBigInt creates a point from affine x, y
+// and returns the point if it is on the curve
+func (g2 *G2) SetBigInt(x, y *big.Int) (*G2, error) {
+	var tt [DoubleWideFieldBytes]byte
+
+	if len(x.Bytes()) == 0 && len(y.Bytes()) == 0 {
+		return g2.Identity(), nil
+	}
+	x.FillBytes(tt[:WideFieldBytes])
+	y.FillBytes(tt[WideFieldBytes:])
+
+	return g2.FromUncompressed(&tt)
+}
+
+// ToCompressed serializes this element into compressed form.
+func (g2 *G2) ToCompressed() [WideFieldBytes]byte {
+	var out [WideFieldBytes]byte
+	var t G2
+	t.ToAffine(g2)
+	xABytes := t.x.A.Bytes()
+	xBBytes := t.x.B.Bytes()
+	copy(out[:FieldBytes], internal.ReverseScalarBytes(xBBytes[:]))
+	copy(out[FieldBytes:], internal.ReverseScalarBytes(xABytes[:]))
+	isInfinity := byte(g2.IsIdentity())
+	// Compressed flag
+	out[0] |= 1 << 7
+	// Is infinity
+	out[0] |= (1 << 6) & -isInfinity
+	// Sign of y only set if not infinity
+	out[0] |= (byte(t.y.LexicographicallyLargest()) << 5) & (isInfinity - 1)
+	return out
+}
+
+// FromCompressed deserializes this element from compressed form.
+func (g2 *G2) FromCompressed(input *[WideFieldBytes]byte) (*G2, error) {
+	var xFp, yFp fp2
+	var xA, xB [FieldBytes]byte
+	var p G2
+	compressedFlag := int((input[0] >> 7) & 1)
+	infinityFlag := int((input[0] >> 6) & 1)
+	sortFlag := int((input[0] >> 5) & 1)
+
+	if compressedFlag != 1 {
+		return nil, errors.New("compressed flag must be set")
+	}
+
+	if infinityFlag == 1 {
+		return g2.Identity(), nil
+	}
+
+	copy(xB[:], internal.ReverseScalarBytes(input[:FieldBytes]))
+	copy(xA[:], internal.ReverseScalarBytes(input[FieldBytes:]))
+	// Mask away the flag bits
+	xB[FieldBytes-1] &= 0x1F
+	_, validA := xFp.A.SetBytes(&xA)
+	_, validB := xFp.B.SetBytes(&xB)
+
+	if validA&validB != 1 {
+		return nil, errors.New("invalid bytes - not in field")
+	}
+
+	// Recover a y-coordinate given x by y = sqrt(x^3 + 1/u)
+	yFp.Square(&xFp)
+	yFp.Mul(&yFp, &xFp)
+	yFp.Add(&yFp, &curveG2B)
+
+	_, wasSquare := yFp.Sqrt(&yFp)
+	if wasSquare != 1 {
+		return nil, errors.New("point is not on the curve")
+	}
+
+	yFp.CNeg(&yFp, yFp.LexicographicallyLargest()^sortFlag)
+	p.x.Set(&xFp)
+	p.y.Set(&yFp)
+	p.z.SetOne()
+	if p.InCorrectSubgroup() == 0 {
+		return nil, errors.New("point is not in correct subgroup")
+	}
+	return g2.Set(&p), nil
+}
+
+// ToUncompressed serializes this element into uncompressed form.
+func (g2 *G2) ToUncompressed() [DoubleWideFieldBytes]byte {
+	var out [DoubleWideFieldBytes]byte
+	var t G2
+	t.ToAffine(g2)
+	bytes := t.x.B.Bytes()
+	copy(out[:FieldBytes], internal.ReverseScalarBytes(bytes[:]))
+	bytes = t.x.A.Bytes()
+	copy(out[FieldBytes:WideFieldBytes], internal.ReverseScalarBytes(bytes[:]))
+	bytes = t.y.B.Bytes()
+	copy(out[WideFieldBytes:WideFieldBytes+FieldBytes], internal.ReverseScalarBytes(bytes[:]))
+	bytes = t.y.A.Bytes()
+	copy(out[WideFieldBytes+FieldBytes:], internal.ReverseScalarBytes(bytes[:]))
+	isInfinity := byte(g2.IsIdentity())
+	out[0] |= (1 << 6) & -isInfinity
+	return out
+}
+
+// FromUncompressed deserializes this element from uncompressed form.
+func (g2 *G2) FromUncompressed(input *[DoubleWideFieldBytes]byte) (*G2, error) {
+	var a, b fp
+	var t [FieldBytes]byte
+	var p G2
+	infinityFlag := int((input[0] >> 6) & 1)
+
+	if infinityFlag == 1 {
+		return g2.Identity(), nil
+	}
+
+	copy(t[:], internal.ReverseScalarBytes(input[:FieldBytes]))
+	// Mask away top bits
+	t[FieldBytes-1] &= 0x1F
+
+	_, valid := b.SetBytes(&t)
+	if valid == 0 {
+		return nil, errors.New("invalid bytes - x.B not in field")
+	}
+	copy(t[:], internal.ReverseScalarBytes(input[FieldBytes:WideFieldBytes]))
+	_, valid = a.SetBytes(&t)
+	if valid == 0 {
+		return nil, errors.New("invalid bytes - x.A not in field")
+	}
+
+	p.x.B.Set(&b)
+	p.x.A.Set(&a)
+
+	copy(t[:], internal.ReverseScalarBytes(input[WideFieldBytes:WideFieldBytes+FieldBytes]))
+	_, valid = b.SetBytes(&t)
+	if valid == 0 {
+		return nil, errors.New("invalid bytes - y.B not in field")
+	}
+	copy(t[:], internal.ReverseScalarBytes(input[FieldBytes+WideFieldBytes:]))
+	_, valid = a.SetBytes(&t)
+	if valid == 0 {
+		return nil, errors.New("invalid bytes - y.A not in field")
+	}
+
+	p.y.B.Set(&b)
+	p.y.A.Set(&a)
+	p.z.SetOne()
+
+	if p.IsOnCurve() == 0 {
+		return nil, errors.New("point is not on the curve")
+	}
+	if p.InCorrectSubgroup() == 0 {
+		return nil, errors.New("point is not in correct subgroup")
+	}
+	return g2.Set(&p), nil
+}
+
+// ToAffine converts the point into affine coordinates
+func (g2 *G2) ToAffine(a *G2) *G2 {
+	var wasInverted int
+	var zero, x, y, z fp2
+	_, wasInverted = z.Invert(&a.z)
+	x.Mul(&a.x, &z)
+	y.Mul(&a.y, &z)
+
+	g2.x.CMove(&zero, &x, wasInverted)
+	g2.y.CMove(&zero, &y, wasInverted)
+	g2.z.CMove(&zero, z.SetOne(), wasInverted)
+	return g2
+}
+
+// GetX returns the affine X coordinate
+func (g2 *G2) GetX() *fp2 {
+	var t G2
+	t.ToAffine(g2)
+	return &t.x
+}
+
+// GetY returns the affine Y coordinate
+func (g2 *G2) GetY() *fp2 {
+	var t G2
+	t.ToAffine(g2)
+	return &t.y
+}
+
+// Equal returns 1 if the two points are equal 0 otherwise.
+func (g2 *G2) Equal(rhs *G2) int {
+	var x1, x2, y1, y2 fp2
+	var e1, e2 int
+
+	// This technique avoids inversions
+	x1.Mul(&g2.x, &rhs.z)
+	x2.Mul(&rhs.x, &g2.z)
+
+	y1.Mul(&g2.y, &rhs.z)
+	y2.Mul(&rhs.y, &g2.z)
+
+	e1 = g2.z.IsZero()
+	e2 = rhs.z.IsZero()
+
+	// Both at infinity or coordinates are the same
+	return (e1 & e2) | (^e1 & ^e2)&x1.Equal(&x2)&y1.Equal(&y2)
+}
+
+// CMove sets g2 = arg1 if choice == 0 and g2 = arg2 if choice == 1
+func (g2 *G2) CMove(arg1, arg2 *G2, choice int) *G2 {
+	g2.x.CMove(&arg1.x, &arg2.x, choice)
+	g2.y.CMove(&arg1.y, &arg2.y, choice)
+	g2.z.CMove(&arg1.z, &arg2.z, choice)
+	return g2
+}
+
+// SumOfProducts computes the multi-exponentiation for the specified
+// points and scalars and stores the result in `g2`.
+// Returns an error if the lengths of the arguments is not equal.
+func (g2 *G2) SumOfProducts(points []*G2, scalars []*native.Field) (*G2, error) {
+	const Upper = 256
+	const W = 4
+	const Windows = Upper / W // careful--use ceiling division in case this doesn't divide evenly
+	var sum G2
+	if len(points) != len(scalars) {
+		return nil, fmt.Errorf("length mismatch")
+	}
+
+	bucketSize := 1 << W
+	windows := make([]G2, Windows)
+	bytes := make([][32]byte, len(scalars))
+	buckets := make([]G2, bucketSize)
+	for i := 0; i < len(windows); i++ {
+		windows[i].Identity()
+	}
+
+	for i, scalar := range scalars {
+		bytes[i] = scalar.Bytes()
+	}
+
+	for j := 0; j < len(windows); j++ {
+		for i := 0; i < bucketSize; i++ {
+			buckets[i].Identity()
+		}
+
+		for i := 0; i < len(scalars); i++ {
+			// j*W to get the nibble
+			// >> 3 to convert to byte, / 8
+			// (W * j & W) gets the nibble, mod W
+			// 1 << W - 1 to get the offset
+			index := bytes[i][j*W>>3] >> (W * j & W) & (1<<W - 1) // little-endian
+			buckets[index].Add(&buckets[index], points[i])
+		}
+
+		sum.Identity()
+
+		for i := bucketSize - 1; i > 0; i-- {
+			sum.Add(&sum, &buckets[i])
+			windows[j].Add(&windows[j], &sum)
+		}
+	}
+
+	g2.Identity()
+	for i := len(windows) - 1; i >= 0; i-- {
+		for j := 0; j < W; j++ {
+			g2.Double(g2)
+		}
+
+		g2.Add(g2, &windows[i])
+	}
+	return g2, nil
+}
+
+func (g2 *G2) psi(a *G2) *G2 {
+	g2.x.FrobeniusMap(&a.x)
+	g2.y.FrobeniusMap(&a.y)
+	// z = frobenius(z)
+	g2.z.FrobeniusMap(&a.z)
+
+	// x = frobenius(x)*u^((p-1)/3)
+	g2.x.Mul(&g2.x, &psiCoeffX)
+	// y = frobenius(y)*u^((p-1)/2)
+	g2.y.Mul(&g2.y, &psiCoeffY)
+
+	return g2
+}
+
+// svdw is the Shallue-van de Woestijne map from section 6.6.1 in
+// <https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06>
+// which maps u to a point on y^2 = x^3 + 1/u.
+func (g2 *G2) svdw(u *fp2) *G2 {
+	var tv1, tv2, tv3, tv4, one, x1, x2, x3, gx, y fp2
+	one.SetOne()
+
+	// tv1 = u^2 * c1
+	tv1.Square(u)
+	tv1.Mul(&tv1, &svdwG2MapC1)
+	// tv2 = 1 + tv1
+	tv2.Add(&one, &tv1)
+	// tv1 = 1 - tv1
+	tv1.Sub(&one, &tv1)
+	// tv3 = inv0(tv1 * tv2)
+	tv3.Mul(&tv1, &tv2)
+	_, _ = tv3.Invert(&tv3)
+	// tv4 = u * tv1 * tv3 * c3
+	tv4.Mul(u, &tv1)
+	tv4.Mul(&tv4, &tv3)
+	tv4.Mul(&tv4, &svdwG2MapC3)
+	// x1 = c2 - tv4
+	x1.Sub(&svdwG2MapC2, &tv4)
+	// e1 = is_square(x1^3 + B)
+	gx.Square(&x1)
+	gx.Mul(&gx, &x1)
+	gx.Add(&gx, &curveG2B)
+	e1 := gx.Legendre()
+	// x2 = c2 + tv4
+	x2.Add(&svdwG2MapC2, &tv4)
+	// e2 = is_square(x2^3 + B) and not e1
+	gx.Square(&x2)
+	gx.Mul(&gx, &x2)
+	gx.Add(&gx, &curveG2B)
+	e2 := gx.Legendre()
+	// x3 = (tv2^2 * tv3)^2 * c4 + Z
+	x3.Square(&tv2)
+	x3.Mul(&x3, &tv3)
+	x3.Square(&x3)
+	x3.Mul(&x3, &svdwG2MapC4)
+	x3.Add(&x3, &svdwG2MapZ)
+
+	g2.x.CMove(&x3, &x1, isSquare(e1))
+	g2.x.CMove(&g2.x, &x2, isSquare(e2)&isNonSquare(e1))
+
+	// y = sqrt(x^3 + B)
+	gx.Square(&g2.x)
+	gx.Mul(&gx, &g2.x)
+	gx.Add(&gx, &curveG2B)
+	_, _ = y.Sqrt(&gx)
+
+	// Keep y only when neither u nor y have A coefficients
+	// larger than their negations
+	e3 := (u.A.LexicographicallyLargest() | y.A.LexicographicallyLargest()) & 1
+	g2.y.Set(&y)
+	g2.y.CNeg(&g2.y, e3)
+	g2.z.SetOne()
+	return g2
+}
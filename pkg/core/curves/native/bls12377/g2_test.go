@@ -0,0 +1,172 @@
+package bls12377
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+)
+
+func TestG2IsOnCurve(t *testing.T) {
+	require.Equal(t, 1, new(G2).Identity().IsOnCurve())
+	require.Equal(t, 1, new(G2).Generator().IsOnCurve())
+
+	var z fp2
+	z.A.SetUint64(0xdeadbeef)
+	z.B.SetUint64(0xcafebabe)
+	gen := new(G2).Generator()
+	test := G2{
+		x: *(gen.x.Mul(&gen.x, &z)),
+		y: *(gen.y.Mul(&gen.y, &z)),
+		z: z,
+	}
+	require.Equal(t, 1, test.IsOnCurve())
+	test.x = z
+	require.Equal(t, 0, test.IsOnCurve())
+}
+
+func TestG2Arithmetic(t *testing.T) {
+	g := new(G2).Generator()
+	a := new(G2).Double(g)
+	b := new(G2).Add(g, g)
+	require.Equal(t, 1, a.Equal(b))
+
+	a.Add(a, g)
+	b.Mul(g, Bls12377FqNew().SetUint64(3))
+	require.Equal(t, 1, a.Equal(b))
+
+	a.Sub(a, a)
+	require.Equal(t, 1, a.IsIdentity())
+
+	a.Neg(g)
+	a.Add(a, g)
+	require.Equal(t, 1, a.IsIdentity())
+
+	a.Add(g, new(G2).Identity())
+	require.Equal(t, 1, a.Equal(g))
+}
+
+func TestG2Mul(t *testing.T) {
+	e, _ := hex.DecodeString("a14f36c57530a8e737deef8b57ee5bbd396382c5d897d90552b03f852a5d81f14adf8596043bcedefa44a43b3216fda80170fdea898f66614b547225fb28fb98c14ba87eaee44edd555543355815f264a53eaf20583c3b0a66dbbcead9481a21")
+	var expected [WideFieldBytes]byte
+	copy(expected[:], e)
+
+	a := new(G2).Mul(new(G2).Generator(), Bls12377FqNew().SetUint64(0xdeadbeefcafebabe))
+	require.Equal(t, expected, a.ToCompressed())
+
+	// the group order annihilates the generator
+	a.multiply(new(G2).Generator(), &fqModulusBytes)
+	require.Equal(t, 1, a.IsIdentity())
+}
+
+func TestG2MulByX(t *testing.T) {
+	x := Bls12377FqNew().SetUint64(paramX)
+	for i := 0; i < 5; i++ {
+		a, err := new(G2).Random(crand.Reader)
+		require.NoError(t, err)
+		b := new(G2).MulByX(a)
+		c := new(G2).Mul(a, x)
+		require.Equal(t, 1, b.Equal(c))
+	}
+}
+
+func TestG2Psi(t *testing.T) {
+	// psi acts as multiplication by p = x mod r on the subgroup
+	for i := 0; i < 5; i++ {
+		a, err := new(G2).Random(crand.Reader)
+		require.NoError(t, err)
+		b := new(G2).psi(a)
+		require.Equal(t, 1, b.IsOnCurve())
+		c := new(G2).MulByX(a)
+		require.Equal(t, 1, b.Equal(c))
+	}
+}
+
+func TestG2ClearCofactor(t *testing.T) {
+	a := new(G2)
+	a.z.SetOne()
+	// find a point on the twist that isn't in the subgroup
+	for i := uint64(1); ; i++ {
+		var rhs fp2
+		a.x.A.SetUint64(i)
+		rhs.Square(&a.x)
+		rhs.Mul(&rhs, &a.x)
+		rhs.Add(&rhs, &curveG2B)
+		if _, wasSquare := a.y.Sqrt(&rhs); wasSquare == 1 {
+			break
+		}
+	}
+	require.Equal(t, 1, a.IsOnCurve())
+	require.Equal(t, 0, a.InCorrectSubgroup())
+	a.ClearCofactor(a)
+	require.Equal(t, 1, a.IsOnCurve())
+	require.Equal(t, 1, a.InCorrectSubgroup())
+	require.Equal(t, 0, a.IsIdentity())
+}
+
+func TestG2Hash(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-BLS12377G2_XMD:SHA-256_SVDW_RO_")
+	tests := []struct {
+		input, expected string
+	}{
+		{"", "80b371805f0b80e81011b023deb5f136c66e42bb93446c31af424bd3b77baa08a260a43396adabfc1835e120f2a79630002989e1dd3d011c99fbb7418b7b2fae44d6cacbcd75a6a630cc2abe74adc02e38915fb50c1e52c09f6aabb866abac85"},
+		{"abc", "80a386b6451dac60b83d6e357adab035375eb35115215ea72c80642ca12f833041c27be7185ad769019b863918e4e348000d74cef625dbb8d06a52176f2ed596295ebe49755995f5b549f33f7cdf2a401a0c2c3ac9886bae4816afaa1e5a178f"},
+		{"abcdef0123456789", "819b2fba50da41468bb29f7bee1bdeb12c6b3c10ed9b184ee87ada2b0ea87caa25ef329e1c357b38f5d17a35d34be0260158b541bfb3e6344cdb5b8601fe998fb6f9668865c8ae9b39e2b5203e52c9aa2c8fb9e0ef07935493e35e349a44d736"},
+	}
+
+	pt := new(G2).Identity()
+	for _, tst := range tests {
+		e, _ := hex.DecodeString(tst.expected)
+		var b [WideFieldBytes]byte
+		copy(b[:], e)
+		pt.Hash(native.EllipticPointHasherSha256(), []byte(tst.input), dst)
+		require.Equal(t, 1, pt.InCorrectSubgroup())
+		require.Equal(t, b, pt.ToCompressed())
+	}
+}
+
+func TestG2Serialization(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		a, err := new(G2).Random(crand.Reader)
+		require.NoError(t, err)
+
+		c := a.ToCompressed()
+		b, err := new(G2).FromCompressed(&c)
+		require.NoError(t, err)
+		require.Equal(t, 1, a.Equal(b))
+
+		u := a.ToUncompressed()
+		b, err = new(G2).FromUncompressed(&u)
+		require.NoError(t, err)
+		require.Equal(t, 1, a.Equal(b))
+
+		x, y := a.BigInt()
+		b, err = new(G2).SetBigInt(x, y)
+		require.NoError(t, err)
+		require.Equal(t, 1, a.Equal(b))
+	}
+
+	c := new(G2).Identity().ToCompressed()
+	b, err := new(G2).FromCompressed(&c)
+	require.NoError(t, err)
+	require.Equal(t, 1, b.IsIdentity())
+}
+
+func TestG2SumOfProducts(t *testing.T) {
+	points := make([]*G2, 5)
+	scalars := make([]*native.Field, 5)
+	expected := new(G2).Identity()
+	for i := range points {
+		var wide [native.WideFieldBytes]byte
+		_, _ = crand.Read(wide[:])
+		points[i], _ = new(G2).Random(crand.Reader)
+		scalars[i] = Bls12377FqNew().SetBytesWide(&wide)
+		expected.Add(expected, new(G2).Mul(points[i], scalars[i]))
+	}
+	actual, err := new(G2).SumOfProducts(points, scalars)
+	require.NoError(t, err)
+	require.Equal(t, 1, expected.Equal(actual))
+}
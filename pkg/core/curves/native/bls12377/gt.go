@@ -0,0 +1,389 @@
+package bls12377
+
+import (
+	"io"
+
+	"github.com/coinbase/kryptology/internal"
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+)
+
+// GtFieldBytes is the number of bytes needed to represent this field
+const GtFieldBytes = 576
+
+// Gt is the target group
+type Gt fp12
+
+// Random generates a random field element
+func (gt *Gt) Random(reader io.Reader) (*Gt, error) {
+	_, err := (*fp12)(gt).Random(reader)
+	return gt, err
+}
+
+// FinalExponentiation performs a "final exponentiation" routine to convert the result
+// of a Miller loop into an element of `Gt` with help of efficient squaring
+// operation in the so-called `cyclotomic subgroup` of `Fq6` so that
+// it can be compared with other elements of `Gt`.
+func (gt *Gt) FinalExponentiation(a *Gt) *Gt {
+	var t0, t1, t2, t fp12
+
+	// Easy part: t = a^((p^6 - 1)(p^2 + 1))
+	t0.Conjugate((*fp12)(a))
+	// Shouldn't happen since we enforce `a` to be non-zero but just in case
+	_, wasInverted := t1.Invert((*fp12)(a))
+	t0.Mul(&t0, &t1)
+	t.FrobeniusMap(&t0)
+	t.FrobeniusMap(&t)
+	t.Mul(&t, &t0)
+
+	// Hard part, https://eprint.iacr.org/2020/875.pdf
+	t0.cyclotomicSquare(&t)
+	t1.cyclotomicExp(&t)
+	t2.Conjugate(&t)
+	t1.Mul(&t1, &t2)
+	t2.cyclotomicExp(&t1)
+	t1.Conjugate(&t1)
+	t1.Mul(&t1, &t2)
+	t2.cyclotomicExp(&t1)
+	t1.FrobeniusMap(&t1)
+	t1.Mul(&t1, &t2)
+	t.Mul(&t, &t0)
+	t0.cyclotomicExp(&t1)
+	t2.cyclotomicExp(&t0)
+	t0.FrobeniusMap(&t1)
+	t0.FrobeniusMap(&t0)
+	t1.Conjugate(&t1)
+	t1.Mul(&t1, &t2)
+	t1.Mul(&t1, &t0)
+	t.Mul(&t, &t1)
+	(*fp12)(gt).CMove((*fp12)(gt), &t, wasInverted)
+	return gt
+}
+
+// IsZero returns 1 if gt == 0, 0 otherwise
+func (gt *Gt) IsZero() int {
+	return (*fp12)(gt).IsZero()
+}
+
+// IsOne returns 1 if gt == 1, 0 otherwise
+func (gt *Gt) IsOne() int {
+	return (*fp12)(gt).IsOne()
+}
+
+// SetOne gt = one
+func (gt *Gt) SetOne() *Gt {
+	(*fp12)(gt).SetOne()
+	return gt
+}
+
+// Set copies a into gt
+func (gt *Gt) Set(a *Gt) *Gt {
+	gt.A.Set(&a.A)
+	gt.B.Set(&a.B)
+	return gt
+}
+
+// coefficients returns the fp coefficients of gt from
+// the lowest to the highest power
+func (gt *Gt) coefficients() [12]*fp {
+	return [12]*fp{
+		&gt.A.A.A, &gt.A.A.B, &gt.A.B.A, &gt.A.B.B, &gt.A.C.A, &gt.A.C.B,
+		&gt.B.A.A, &gt.B.A.B, &gt.B.B.A, &gt.B.B.B, &gt.B.C.A, &gt.B.C.B,
+	}
+}
+
+// Bytes returns the Gt field byte representation.
+// The coefficients are written as big-endian integers
+// from the highest power down to the lowest.
+func (gt *Gt) Bytes() [GtFieldBytes]byte {
+	var out [GtFieldBytes]byte
+	for i, c := range gt.coefficients() {
+		t := c.Bytes()
+		copy(out[(11-i)*FieldBytes:(12-i)*FieldBytes], internal.ReverseScalarBytes(t[:]))
+	}
+	return out
+}
+
+// SetBytes attempts to convert a big-endian byte representation of
+// a scalar into a `Gt`, failing if the input is not canonical.
+func (gt *Gt) SetBytes(input *[GtFieldBytes]byte) (*Gt, int) {
+	var t [FieldBytes]byte
+	valid := 1
+	for i, c := range gt.coefficients() {
+		copy(t[:], internal.ReverseScalarBytes(input[(11-i)*FieldBytes:(12-i)*FieldBytes]))
+		_, v := c.SetBytes(&t)
+		valid &= v
+	}
+	return gt, valid
+}
+
+// Equal returns 1 if gt == rhs, 0 otherwise
+func (gt *Gt) Equal(rhs *Gt) int {
+	return (*fp12)(gt).Equal((*fp12)(rhs))
+}
+
+// Generator returns the base point
+func (gt *Gt) Generator() *Gt {
+	// pairing(&G1::generator(), &G2::generator())
+	gt.Set((*Gt)(&fp12{
+		A: fp6{
+			A: fp2{
+				A: fp{
+					0xc4b3472d30a4bf39,
+					0x35c1ef858be55f92,
+					0x060dcc1815f69ff5,
+					0xcfa9bb5bf4998296,
+					0x1d484a0be53a28b4,
+					0x00ac9377060654b2,
+				},
+				B: fp{
+					0xa84f0650bb798247,
+					0x42eaf6a411ce43a8,
+					0x312d17a00329a4f4,
+					0x11c5e8aea8ec7b8a,
+					0x39a162bfc2d51802,
+					0x014331e345598578,
+				},
+			},
+			B: fp2{
+				A: fp{
+					0x18f6e9b8397453e3,
+					0x9d3ecd0bb1bed22a,
+					0x8140f78f9e1b10a5,
+					0x696921f481afa67c,
+					0xad6ca43bb870b508,
+					0x00d1afa30239d426,
+				},
+				B: fp{
+					0xacfdb15f62a78e98,
+					0xb9df53c9bbdc6814,
+					0x8c1f02fb7de45b77,
+					0xd89f9287823548d0,
+					0xa150807ef5939998,
+					0x00dcc44d819b8bb2,
+				},
+			},
+			C: fp2{
+				A: fp{
+					0x9878ea99d586f038,
+					0x5edf53d3a5f97c5f,
+					0x79ddf03d1388b010,
+					0x730bec974ef060bf,
+					0x6bedaabac5bdf751,
+					0x01add0571f38e0cb,
+				},
+				B: fp{
+					0x5a951c27a034f14b,
+					0xc2a3ee3e3cf67d01,
+					0x5158c474dc565ad7,
+					0xa85b6c5fb86d25a8,
+					0x6bc46e54231d9bcd,
+					0x000f3b6fdb50f337,
+				},
+			},
+		},
+		B: fp6{
+			A: fp2{
+				A: fp{
+					0x8fb659bfe568691f,
+					0x6797923332542153,
+					0x2d7202118d713deb,
+					0x9b6fa6cf3fcaf3a2,
+					0x3f01379019dbd743,
+					0x003c366037d000f5,
+				},
+				B: fp{
+					0xe86d9c233db4471b,
+					0xd126250c1ec9331d,
+					0x308a5de361d4ddc3,
+					0x11c191733bff54c7,
+					0xb54dde5646f96a34,
+					0x006c5f2cceb0e5f0,
+				},
+			},
+			B: fp2{
+				A: fp{
+					0x50aa84c3d78ddbb1,
+					0x02d4b6d5fd5ccba4,
+					0xe17b8f924da8878a,
+					0x0e4543259c193100,
+					0x473d3b895c46a165,
+					0x01295fb1248376e1,
+				},
+				B: fp{
+					0xf6421a267982759c,
+					0x1dcc534fd3bf8e89,
+					0x2cfe701c7f5a01af,
+					0x81d3097736115e39,
+					0xd5aa723ab9964f66,
+					0x0041bc71bca98e57,
+				},
+			},
+			C: fp2{
+				A: fp{
+					0x28c9dbbcc7e99b90,
+					0x7bfaafc6a8868bb0,
+					0x6c054066d40cae7f,
+					0x19add1889db3eb9c,
+					0xae7ebd1a4050b58f,
+					0x011e20a8013e1aff,
+				},
+				B: fp{
+					0x688095ab92f90691,
+					0xd61a8d80192de450,
+					0x581a97cf7ce10d0f,
+					0xa7ba31f5773aca67,
+					0xa809e8cf97cec652,
+					0x0110a7687ae95872,
+				},
+			},
+		},
+	}))
+	return gt
+}
+
+// Add adds this value to another value.
+func (gt *Gt) Add(arg1, arg2 *Gt) *Gt {
+	(*fp12)(gt).Mul((*fp12)(arg1), (*fp12)(arg2))
+	return gt
+}
+
+// Double this value
+func (gt *Gt) Double(a *Gt) *Gt {
+	(*fp12)(gt).Square((*fp12)(a))
+	return gt
+}
+
+// Sub subtracts the two values
+func (gt *Gt) Sub(arg1, arg2 *Gt) *Gt {
+	var t fp12
+	t.Conjugate((*fp12)(arg2))
+	(*fp12)(gt).Mul((*fp12)(arg1), &t)
+	return gt
+}
+
+// Neg negates this value
+func (gt *Gt) Neg(a *Gt) *Gt {
+	(*fp12)(gt).Conjugate((*fp12)(a))
+	return gt
+}
+
+// Mul multiplies this value by the input scalar
+func (gt *Gt) Mul(a *Gt, s *native.Field) *Gt {
+	var f, p fp12
+	f.Set((*fp12)(a))
+	p.SetOne()
+	bytes := s.Bytes()
+
+	precomputed := [16]fp12{}
+	precomputed[0].SetOne()
+	precomputed[1].Set(&f)
+	for i := 2; i < 16; i += 2 {
+		precomputed[i].Square(&precomputed[i>>1])
+		precomputed[i+1].Mul(&precomputed[i], &f)
+	}
+	for i := 0; i < 256; i += 4 {
+		// Brouwer / windowing method. window size of 4.
+		for j := 0; j < 4; j++ {
+			p.Square(&p)
+		}
+		window := bytes[32-1-i>>3] >> (4 - i&0x04) & 0x0F
+		p.Mul(&p, &precomputed[window])
+	}
+	(*fp12)(gt).Set(&p)
+	return gt
+}
+
+// Square this value
+func (gt *Gt) Square(a *Gt) *Gt {
+	(*fp12)(gt).cyclotomicSquare((*fp12)(a))
+	return gt
+}
+
+// Invert this value
+func (gt *Gt) Invert(a *Gt) (*Gt, int) {
+	_, wasInverted := (*fp12)(gt).Invert((*fp12)(a))
+	return gt, wasInverted
+}
+
+func fp4Square(a, b, arg1, arg2 *fp2) {
+	var t0, t1, t2 fp2
+
+	t0.Square(arg1)
+	t1.Square(arg2)
+	t2.MulByNonResidue(&t1)
+	a.Add(&t2, &t0)
+	t2.Add(arg1, arg2)
+	t2.Square(&t2)
+	t2.Sub(&t2, &t0)
+	b.Sub(&t2, &t1)
+}
+
+func (f *fp12) cyclotomicSquare(a *fp12) *fp12 {
+	// Adaptation of Algorithm 5.5.4, Guide to Pairing-Based Cryptography
+	// Faster Squaring in the Cyclotomic Subgroup of Sixth Degree Extensions
+	// https://eprint.iacr.org/2009/565.pdf
+	var z0, z1, z2, z3, z4, z5, t0, t1, t2, t3 fp2
+	z0.Set(&a.A.A)
+	z4.Set(&a.A.B)
+	z3.Set(&a.A.C)
+	z2.Set(&a.B.A)
+	z1.Set(&a.B.B)
+	z5.Set(&a.B.C)
+
+	fp4Square(&t0, &t1, &z0, &z1)
+	z0.Sub(&t0, &z0)
+	z0.Double(&z0)
+	z0.Add(&z0, &t0)
+
+	z1.Add(&t1, &z1)
+	z1.Double(&z1)
+	z1.Add(&z1, &t1)
+
+	fp4Square(&t0, &t1, &z2, &z3)
+	fp4Square(&t2, &t3, &z4, &z5)
+
+	z4.Sub(&t0, &z4)
+	z4.Double(&z4)
+	z4.Add(&z4, &t0)
+
+	z5.Add(&z5, &t1)
+	z5.Double(&z5)
+	z5.Add(&z5, &t1)
+
+	t0.MulByNonResidue(&t3)
+	z2.Add(&z2, &t0)
+	z2.Double(&z2)
+	z2.Add(&z2, &t0)
+
+	z3.Sub(&t2, &z3)
+	z3.Double(&z3)
+	z3.Add(&z3, &t2)
+
+	f.A.A.Set(&z0)
+	f.A.B.Set(&z4)
+	f.A.C.Set(&z3)
+
+	f.B.A.Set(&z2)
+	f.B.B.Set(&z1)
+	f.B.C.Set(&z5)
+	return f
+}
+
+func (f *fp12) cyclotomicExp(a *fp12) *fp12 {
+	var t fp12
+	t.SetOne()
+	foundOne := 0
+
+	for i := 63; i >= 0; i-- {
+		b := int((paramX >> i) & 1)
+		if foundOne == 1 {
+			t.cyclotomicSquare(&t)
+		} else {
+			foundOne = b
+		}
+		if b == 1 {
+			t.Mul(&t, a)
+		}
+	}
+	return f.Set(&t)
+}
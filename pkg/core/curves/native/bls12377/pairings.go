@@ -0,0 +1,214 @@
+package bls12377
+
+const coefficientsG2 = 69
+
+type Engine struct {
+	pairs []pair
+}
+
+type pair struct {
+	g1 G1
+	g2 G2
+}
+
+type g2Prepared struct {
+	identity     int
+	coefficients []coefficients
+}
+
+type coefficients struct {
+	a, b, c fp2
+}
+
+func (c *coefficients) CMove(arg1, arg2 *coefficients, choice int) *coefficients {
+	c.a.CMove(&arg1.a, &arg2.a, choice)
+	c.b.CMove(&arg1.b, &arg2.b, choice)
+	c.c.CMove(&arg1.c, &arg2.c, choice)
+	return c
+}
+
+// AddPair adds a pair of points to be paired
+func (e *Engine) AddPair(g1 *G1, g2 *G2) *Engine {
+	var p pair
+	p.g1.ToAffine(g1)
+	p.g2.ToAffine(g2)
+	if p.g1.IsIdentity()|p.g2.IsIdentity() == 0 {
+		e.pairs = append(e.pairs, p)
+	}
+	return e
+}
+
+// AddPairInvG1 adds a pair of points to be paired. G1 point is negated
+func (e *Engine) AddPairInvG1(g1 *G1, g2 *G2) *Engine {
+	var p G1
+	p.Neg(g1)
+	return e.AddPair(&p, g2)
+}
+
+// AddPairInvG2 adds a pair of points to be paired. G2 point is negated
+func (e *Engine) AddPairInvG2(g1 *G1, g2 *G2) *Engine {
+	var p G2
+	p.Neg(g2)
+	return e.AddPair(g1, &p)
+}
+
+func (e *Engine) Reset() *Engine {
+	e.pairs = []pair{}
+	return e
+}
+
+func (e *Engine) Check() bool {
+	return e.pairing().IsOne() == 1
+}
+
+func (e *Engine) Result() *Gt {
+	return e.pairing()
+}
+
+func (e *Engine) pairing() *Gt {
+	f := new(Gt).SetOne()
+	if len(e.pairs) == 0 {
+		return f
+	}
+	coeffs := e.computeCoeffs()
+	e.millerLoop((*fp12)(f), coeffs)
+	return f.FinalExponentiation(f)
+}
+
+func (e *Engine) millerLoop(f *fp12, coeffs []g2Prepared) {
+	newF := new(fp12).SetZero()
+	cIdx := 0
+	// The leading bit of x is consumed by starting at q
+	for i := 62; i >= 0; i-- {
+		x := int((paramX >> i) & 1)
+		f.Square(f)
+
+		// doubling
+		for j, terms := range coeffs {
+			identity := e.pairs[j].g1.IsIdentity() | terms.identity
+			newF.Set(f)
+			ell(newF, terms.coefficients[cIdx], &e.pairs[j].g1)
+			f.CMove(newF, f, identity)
+		}
+		cIdx++
+
+		if x == 1 {
+			// adding
+			for j, terms := range coeffs {
+				identity := e.pairs[j].g1.IsIdentity() | terms.identity
+				newF.Set(f)
+				ell(newF, terms.coefficients[cIdx], &e.pairs[j].g1)
+				f.CMove(newF, f, identity)
+			}
+			cIdx++
+		}
+	}
+}
+
+func (e *Engine) computeCoeffs() []g2Prepared {
+	coeffs := make([]g2Prepared, len(e.pairs))
+	for i, p := range e.pairs {
+		identity := p.g2.IsIdentity()
+		q := new(G2).Generator()
+		q.CMove(&p.g2, q, identity)
+		c := new(G2).Set(q)
+		cfs := make([]coefficients, coefficientsG2)
+		k := 0
+
+		for j := 62; j >= 0; j-- {
+			x := int((paramX >> j) & 1)
+			cfs[k] = doublingStep(c)
+			k++
+
+			if x == 1 {
+				cfs[k] = additionStep(c, q)
+				k++
+			}
+		}
+		coeffs[i] = g2Prepared{
+			coefficients: cfs, identity: identity,
+		}
+	}
+	return coeffs
+}
+
+func ell(f *fp12, coeffs coefficients, p *G1) {
+	var x, y fp2
+	y.Mul0(&coeffs.a, &p.y)
+	x.Mul0(&coeffs.b, &p.x)
+	f.MulByADE(f, &y, &x, &coeffs.c)
+}
+
+func doublingStep(p *G2) coefficients {
+	// Section 4.3, https://eprint.iacr.org/2013/722.pdf
+	// with p in homogeneous projective coordinates
+	var a, b, c, d, e, ee, f, g, h, i, j, k, t fp2
+	a.Mul(&p.x, &p.y)
+	a.Mul0(&a, &twoInv)
+	b.Square(&p.y)
+	c.Square(&p.z)
+	d.Double(&c)
+	d.Add(&d, &c)
+	e.Mul(&d, &curveG2B)
+	f.Double(&e)
+	f.Add(&f, &e)
+	g.Add(&b, &f)
+	g.Mul0(&g, &twoInv)
+	h.Add(&p.y, &p.z)
+	h.Square(&h)
+	t.Add(&b, &c)
+	h.Sub(&h, &t)
+	i.Sub(&e, &b)
+	j.Square(&p.x)
+	ee.Square(&e)
+	k.Double(&ee)
+	k.Add(&k, &ee)
+
+	p.x.Sub(&b, &f)
+	p.x.Mul(&p.x, &a)
+	p.y.Square(&g)
+	p.y.Sub(&p.y, &k)
+	p.z.Mul(&b, &h)
+
+	h.Neg(&h)
+	t.Double(&j)
+	t.Add(&t, &j)
+
+	return coefficients{
+		a: h, b: t, c: i,
+	}
+}
+
+func additionStep(r, q *G2) coefficients {
+	// Section 4.3, https://eprint.iacr.org/2013/722.pdf
+	// with r in homogeneous projective coordinates and q affine
+	var o, l, c, d, e, f, g, h, j, t fp2
+	t.Mul(&q.y, &r.z)
+	o.Sub(&r.y, &t)
+	t.Mul(&q.x, &r.z)
+	l.Sub(&r.x, &t)
+	c.Square(&o)
+	d.Square(&l)
+	e.Mul(&l, &d)
+	f.Mul(&r.z, &c)
+	g.Mul(&r.x, &d)
+	t.Double(&g)
+	h.Add(&e, &f)
+	h.Sub(&h, &t)
+	t.Mul(&r.y, &e)
+
+	r.x.Mul(&l, &h)
+	r.y.Sub(&g, &h)
+	r.y.Mul(&r.y, &o)
+	r.y.Sub(&r.y, &t)
+	r.z.Mul(&e, &r.z)
+
+	t.Mul(&l, &q.y)
+	j.Mul(&q.x, &o)
+	j.Sub(&j, &t)
+	o.Neg(&o)
+
+	return coefficients{
+		a: l, b: o, c: j,
+	}
+}
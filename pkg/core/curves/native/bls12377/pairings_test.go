@@ -0,0 +1,98 @@
+package bls12377
+
+import (
+	crand "crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+)
+
+func TestSinglePairing(t *testing.T) {
+	g := new(G1).Generator()
+	h := new(G2).Generator()
+
+	e := new(Engine)
+	e.AddPair(g, h)
+	p := e.Result()
+	require.Equal(t, 1, p.Equal(new(Gt).Generator()))
+	p.Neg(p)
+
+	e.Reset()
+	e.AddPairInvG2(g, h)
+	q := e.Result()
+	e.Reset()
+	e.AddPairInvG1(g, h)
+	r := e.Result()
+
+	require.Equal(t, 1, p.Equal(q))
+	require.Equal(t, 1, q.Equal(r))
+}
+
+func TestPairingBilinearity(t *testing.T) {
+	var wide [native.WideFieldBytes]byte
+	_, _ = crand.Read(wide[:])
+	a := Bls12377FqNew().SetBytesWide(&wide)
+	_, _ = crand.Read(wide[:])
+	b := Bls12377FqNew().SetBytesWide(&wide)
+	ab := Bls12377FqNew().Mul(a, b)
+
+	g := new(G1).Mul(new(G1).Generator(), a)
+	h := new(G2).Mul(new(G2).Generator(), b)
+	actual := new(Engine).AddPair(g, h).Result()
+	expected := new(Gt).Mul(new(Gt).Generator(), ab)
+	require.Equal(t, 1, expected.Equal(actual))
+
+	// e(aG, bH) * e(-abG, H) = 1
+	g2 := new(G1).Mul(new(G1).Generator(), ab)
+	require.True(t, new(Engine).AddPair(g, h).AddPairInvG1(g2, new(G2).Generator()).Check())
+	require.False(t, new(Engine).AddPair(g, h).AddPair(g2, new(G2).Generator()).Check())
+}
+
+func TestMultiPairing(t *testing.T) {
+	const Tests = 10
+	e1 := new(Engine)
+	e2 := new(Engine)
+
+	g1s := make([]*G1, Tests)
+	g2s := make([]*G2, Tests)
+	sc := make([]*native.Field, Tests)
+	res := make([]*Gt, Tests)
+	expected := new(Gt).SetOne()
+
+	for i := 0; i < Tests; i++ {
+		var bytes [64]byte
+		g1s[i] = new(G1).Generator()
+		g2s[i] = new(G2).Generator()
+		sc[i] = Bls12377FqNew()
+		_, _ = crand.Read(bytes[:])
+		sc[i].SetBytesWide(&bytes)
+		if i&1 == 0 {
+			g1s[i].Mul(g1s[i], sc[i])
+		} else {
+			g2s[i].Mul(g2s[i], sc[i])
+		}
+		e1.AddPair(g1s[i], g2s[i])
+		e2.AddPair(g1s[i], g2s[i])
+		res[i] = e1.Result()
+		e1.Reset()
+		expected.Add(expected, res[i])
+	}
+
+	actual := e2.Result()
+	require.Equal(t, 1, expected.Equal(actual))
+}
+
+func TestGtBytes(t *testing.T) {
+	a := new(Gt).Generator()
+	seq := a.Bytes()
+	b, isCanonical := new(Gt).SetBytes(&seq)
+	require.Equal(t, 1, isCanonical)
+	require.Equal(t, 1, a.Equal(b))
+
+	c, wasInverted := new(Gt).Invert(a)
+	require.Equal(t, 1, wasInverted)
+	c.Add(c, a)
+	require.Equal(t, 1, c.IsOne())
+}
@@ -0,0 +1,475 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package fp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+
+	"github.com/coinbase/kryptology/internal"
+)
+
+const (
+	// Limbs is the number of 64-bit words in an element
+	Limbs = 6
+	// FieldBytes is the number of bytes in a canonical element
+	FieldBytes = 48
+	// WideFieldBytes is the number of bytes accepted by SetBytesWide
+	WideFieldBytes = 96
+)
+
+// Fp is an element of the P384 base field in montgomery form
+type Fp [Limbs]uint64
+
+var (
+	// See FIPS 186-4, section D.1.2.4
+	modulus = Fp{
+		0x00000000ffffffff,
+		0xffffffff00000000,
+		0xfffffffffffffffe,
+		0xffffffffffffffff,
+		0xffffffffffffffff,
+		0xffffffffffffffff,
+	}
+	// 2^384 mod m
+	r = Fp{
+		0xffffffff00000001,
+		0x00000000ffffffff,
+		0x0000000000000001,
+		0x0000000000000000,
+		0x0000000000000000,
+		0x0000000000000000,
+	}
+	// 2^768 mod m
+	r2 = Fp{
+		0xfffffffe00000001,
+		0x0000000200000000,
+		0xfffffffe00000000,
+		0x0000000200000000,
+		0x0000000000000001,
+		0x0000000000000000,
+	}
+	// 2^384 * r2 mod m
+	r3 = Fp{
+		0xfffffffc00000002,
+		0x0000000300000002,
+		0xfffffffcfffffffe,
+		0x0000000300000005,
+		0xfffffffdfffffffd,
+		0x0000000300000002,
+	}
+	// m - 2
+	invExp = Fp{
+		0x00000000fffffffd,
+		0xffffffff00000000,
+		0xfffffffffffffffe,
+		0xffffffffffffffff,
+		0xffffffffffffffff,
+		0xffffffffffffffff,
+	}
+
+	biModulus = new(big.Int).SetBytes([]byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff},
+	)
+)
+
+// inv = -(m^{-1} mod 2^64) mod 2^64
+const inv = 0x0000000100000001
+
+// P384FpNew returns a new zero element
+func P384FpNew() *Fp {
+	return new(Fp)
+}
+
+// IsZero returns 1 if Fp == 0, 0 otherwise
+func (f *Fp) IsZero() int {
+	t := uint64(0)
+	for i := range f {
+		t |= f[i]
+	}
+	return int(((int64(t) | int64(-t)) >> 63) + 1)
+}
+
+// IsNonZero returns 1 if Fp != 0, 0 otherwise
+func (f *Fp) IsNonZero() int {
+	t := uint64(0)
+	for i := range f {
+		t |= f[i]
+	}
+	return int(-((int64(t) | int64(-t)) >> 63))
+}
+
+// IsOne returns 1 if Fp == 1, 0 otherwise
+func (f *Fp) IsOne() int {
+	return f.Equal(&r)
+}
+
+// Cmp returns -1 if f < rhs
+// 0 if f == rhs
+// 1 if f > rhs
+func (f *Fp) Cmp(rhs *Fp) int {
+	gt := uint64(0)
+	lt := uint64(0)
+	for i := Limbs - 1; i >= 0; i-- {
+		// convert to two 64-bit numbers where
+		// the leading bits are zeros and hold no meaning
+		//  so rhs - f actually means gt
+		// and f - rhs actually means lt.
+		rhsH := rhs[i] >> 32
+		rhsL := rhs[i] & 0xffffffff
+		lhsH := f[i] >> 32
+		lhsL := f[i] & 0xffffffff
+
+		// Check the leading bit
+		// if negative then f > rhs
+		// if positive then f < rhs
+		gt |= (rhsH - lhsH) >> 32 & 1 &^ lt
+		lt |= (lhsH - rhsH) >> 32 & 1 &^ gt
+		gt |= (rhsL - lhsL) >> 32 & 1 &^ lt
+		lt |= (lhsL - rhsL) >> 32 & 1 &^ gt
+	}
+	// Make the result -1 for <, 0 for =, 1 for >
+	return int(gt) - int(lt)
+}
+
+// Equal returns 1 if Fp == rhs, 0 otherwise
+func (f *Fp) Equal(rhs *Fp) int {
+	t := uint64(0)
+	for i := range f {
+		t |= f[i] ^ rhs[i]
+	}
+	return int(((int64(t) | int64(-t)) >> 63) + 1)
+}
+
+// Sgn0 returns the lowest bit value
+func (f *Fp) Sgn0() int {
+	t := new(Fp).fromMontgomery(f)
+	return int(t[0] & 1)
+}
+
+// SetOne Fp = r
+func (f *Fp) SetOne() *Fp {
+	return f.Set(&r)
+}
+
+// SetZero Fp = 0
+func (f *Fp) SetZero() *Fp {
+	for i := range f {
+		f[i] = 0
+	}
+	return f
+}
+
+// SetUint64 Fp = rhs
+func (f *Fp) SetUint64(rhs uint64) *Fp {
+	f.SetZero()
+	f[0] = rhs
+	return f.toMontgomery(f)
+}
+
+// Random generates a random field element
+func (f *Fp) Random(reader io.Reader) (*Fp, error) {
+	var t [WideFieldBytes]byte
+	n, err := reader.Read(t[:])
+	if err != nil {
+		return nil, err
+	}
+	if n != WideFieldBytes {
+		return nil, fmt.Errorf("can only read %d when %d are needed", n, WideFieldBytes)
+	}
+	return f.SetBytesWide(&t), nil
+}
+
+// toMontgomery converts this field to montgomery form
+func (f *Fp) toMontgomery(a *Fp) *Fp {
+	// arg.R^0 * R^2 / R = arg.R
+	return f.Mul(a, &r2)
+}
+
+// fromMontgomery converts this field from montgomery form
+func (f *Fp) fromMontgomery(a *Fp) *Fp {
+	// Mul by 1 is division by R mod m
+	return f.Mul(a, &Fp{1})
+}
+
+// Neg performs modular negation
+func (f *Fp) Neg(a *Fp) *Fp {
+	// Subtract `arg` from `modulus`. Ignore final borrow
+	// since it can't underflow.
+	var t Fp
+	var borrow uint64
+	for i := range t {
+		t[i], borrow = bits.Sub64(modulus[i], a[i], borrow)
+	}
+
+	// t could be `modulus` if `arg`=0. Set mask=0 if self=0
+	// and 0xff..ff if `arg`!=0
+	mask := uint64(-a.IsNonZero())
+	for i := range f {
+		f[i] = t[i] & mask
+	}
+	return f
+}
+
+// Square performs modular square
+func (f *Fp) Square(a *Fp) *Fp {
+	return f.Mul(a, a)
+}
+
+// Double this element
+func (f *Fp) Double(a *Fp) *Fp {
+	return f.Add(a, a)
+}
+
+// Mul performs modular multiplication
+func (f *Fp) Mul(arg1, arg2 *Fp) *Fp {
+	// Coarsely integrated operand scanning, Algorithm 14.36 in
+	// Handbook of Applied Cryptography. The modulus leaves no spare
+	// bits in the top limb so two extra words hold the carries.
+	var t [Limbs + 2]uint64
+	var c, k uint64
+
+	for i := 0; i < Limbs; i++ {
+		c = 0
+		for j := 0; j < Limbs; j++ {
+			t[j], c = mac(t[j], arg1[j], arg2[i], c)
+		}
+		t[Limbs], c = bits.Add64(t[Limbs], c, 0)
+		t[Limbs+1] = c
+
+		k = t[0] * inv
+		_, c = mac(t[0], k, modulus[0], 0)
+		for j := 1; j < Limbs; j++ {
+			t[j-1], c = mac(t[j], k, modulus[j], c)
+		}
+		t[Limbs-1], c = bits.Add64(t[Limbs], c, 0)
+		t[Limbs] = t[Limbs+1] + c
+	}
+
+	var rr Fp
+	copy(rr[:], t[:Limbs])
+	return f.reduce(&rr, t[Limbs])
+}
+
+// Add performs modular addition
+func (f *Fp) Add(arg1, arg2 *Fp) *Fp {
+	var t Fp
+	var carry uint64
+	for i := range t {
+		t[i], carry = bits.Add64(arg1[i], arg2[i], carry)
+	}
+	return f.reduce(&t, carry)
+}
+
+// Sub performs modular subtraction
+func (f *Fp) Sub(arg1, arg2 *Fp) *Fp {
+	var t Fp
+	var borrow, carry uint64
+	for i := range t {
+		t[i], borrow = bits.Sub64(arg1[i], arg2[i], borrow)
+	}
+
+	// If underflow occurred on the final limb, borrow 0xff...ff, otherwise
+	// borrow = 0x00...00. Conditionally mask to add the modulus
+	mask := -borrow
+	for i := range f {
+		f[i], carry = bits.Add64(t[i], modulus[i]&mask, carry)
+	}
+	return f
+}
+
+// reduce subtracts the modulus from the value held in
+// t and the carry word if it is not already smaller
+func (f *Fp) reduce(t *Fp, carry uint64) *Fp {
+	var d Fp
+	var borrow uint64
+	for i := range d {
+		d[i], borrow = bits.Sub64(t[i], modulus[i], borrow)
+	}
+	// Keep t only when the subtraction underflowed
+	// and there was no carry out of the top limb
+	_, borrow = bits.Sub64(carry, 0, borrow)
+	return f.CMove(&d, t, int(borrow))
+}
+
+// sqrtExp = (m + 1) / 4
+var sqrtExp = Fp{
+	0x0000000040000000,
+	0xbfffffffc0000000,
+	0xffffffffffffffff,
+	0xffffffffffffffff,
+	0xffffffffffffffff,
+	0x3fffffffffffffff,
+}
+
+// Sqrt performs modular square root
+func (f *Fp) Sqrt(a *Fp) (*Fp, bool) {
+	// The modulus is 3 mod 4 so a^((m+1)/4) is a
+	// square root of a whenever one exists
+	var z, c Fp
+	z.pow(a, &sqrtExp)
+	c.Square(&z)
+	wasSquare := c.Equal(a)
+	f.CMove(f, &z, wasSquare)
+	return f, wasSquare == 1
+}
+
+// Invert performs modular inverse
+func (f *Fp) Invert(a *Fp) (*Fp, bool) {
+	// Exponentiate by m - 2
+	var t Fp
+	t.pow(a, &invExp)
+	wasInverted := a.IsNonZero()
+	f.CMove(a, &t, wasInverted)
+	return f, wasInverted == 1
+}
+
+// SetBytes attempts to convert a little endian byte representation
+// of a scalar into a `Fp`, failing if input is not canonical
+func (f *Fp) SetBytes(input *[FieldBytes]byte) (*Fp, error) {
+	var buffer [Limbs * 8]byte
+	var t Fp
+	copy(buffer[:], input[:])
+	for i := range t {
+		t[i] = binary.LittleEndian.Uint64(buffer[i*8:])
+	}
+	if t.Cmp(&modulus) != -1 {
+		return nil, fmt.Errorf("invalid byte sequence")
+	}
+	return f.toMontgomery(&t), nil
+}
+
+// SetBytesWide takes 96 bytes as input and treats them as a little endian number.
+// The number is decomposed into two 384-bit digits with the higher bits
+// multiplied by 2^384. Thus, we perform two reductions
+//
+// 1. the lower bits are multiplied by r^2, as normal
+// 2. the upper bits are multiplied by r^2 * 2^384 = r3
+//
+// and computing their sum in the field. Both digits are smaller than
+// r = 2^384 so the montgomery reduction of each product is safe.
+func (f *Fp) SetBytesWide(input *[WideFieldBytes]byte) *Fp {
+	var buffer [Limbs * 8]byte
+	var d0, d1 Fp
+	copy(buffer[:], input[:FieldBytes])
+	for i := range d0 {
+		d0[i] = binary.LittleEndian.Uint64(buffer[i*8:])
+	}
+	copy(buffer[:], input[FieldBytes:])
+	for i := range d1 {
+		d1[i] = binary.LittleEndian.Uint64(buffer[i*8:])
+	}
+	// d0*r2 + d1*r3
+	d0.Mul(&d0, &r2)
+	d1.Mul(&d1, &r3)
+	return f.Add(&d0, &d1)
+}
+
+// SetBigInt initializes an element from big.Int
+// The value is reduced by the modulus
+func (f *Fp) SetBigInt(bi *big.Int) *Fp {
+	var buffer [FieldBytes]byte
+	t := new(big.Int).Set(bi)
+	t.Mod(t, biModulus)
+	t.FillBytes(buffer[:])
+	copy(buffer[:], internal.ReverseScalarBytes(buffer[:]))
+	_, _ = f.SetBytes(&buffer)
+	return f
+}
+
+// Set copies a into Fp
+func (f *Fp) Set(a *Fp) *Fp {
+	*f = *a
+	return f
+}
+
+// SetLimbs converts an array into a field element
+// by converting to montgomery form
+func (f *Fp) SetLimbs(a *[Limbs]uint64) *Fp {
+	return f.toMontgomery((*Fp)(a))
+}
+
+// SetRaw converts a raw array into a field element
+// Assumes input is already in montgomery form
+func (f *Fp) SetRaw(a *[Limbs]uint64) *Fp {
+	*f = *a
+	return f
+}
+
+// Bytes converts a field element to a little endian byte array
+func (f *Fp) Bytes() [FieldBytes]byte {
+	var buffer [Limbs * 8]byte
+	var out [FieldBytes]byte
+	t := new(Fp).fromMontgomery(f)
+	for i := range t {
+		binary.LittleEndian.PutUint64(buffer[i*8:], t[i])
+	}
+	copy(out[:], buffer[:FieldBytes])
+	return out
+}
+
+// BigInt converts this element into the big.Int struct
+func (f *Fp) BigInt() *big.Int {
+	buffer := f.Bytes()
+	return new(big.Int).SetBytes(internal.ReverseScalarBytes(buffer[:]))
+}
+
+// Raw converts this element into the a [Limbs]uint64
+func (f *Fp) Raw() [Limbs]uint64 {
+	t := new(Fp).fromMontgomery(f)
+	return *t
+}
+
+// CMove performs conditional select.
+// selects arg1 if choice == 0 and arg2 if choice == 1
+func (f *Fp) CMove(arg1, arg2 *Fp, choice int) *Fp {
+	mask := uint64(-choice)
+	for i := range f {
+		f[i] = arg1[i] ^ ((arg1[i] ^ arg2[i]) & mask)
+	}
+	return f
+}
+
+// CNeg conditionally negates a if choice == 1
+func (f *Fp) CNeg(a *Fp, choice int) *Fp {
+	var t Fp
+	t.Neg(a)
+	return f.CMove(a, &t, choice)
+}
+
+// Exp raises base^exp.
+func (f *Fp) Exp(base, exp *Fp) *Fp {
+	e := new(Fp).fromMontgomery(exp)
+	return f.pow(base, e)
+}
+
+func (f *Fp) pow(base, e *Fp) *Fp {
+	var tmp, res Fp
+	res.SetOne()
+
+	for i := len(e) - 1; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			res.Square(&res)
+			tmp.Mul(&res, base)
+			res.CMove(&res, &tmp, int(e[i]>>j)&1)
+		}
+	}
+	return f.Set(&res)
+}
+
+// mac Multiply and Accumulate - compute a + (b * c) + d, return the result and new carry
+func mac(a, b, c, d uint64) (uint64, uint64) {
+	hi, lo := bits.Mul64(b, c)
+	lo, carry := bits.Add64(lo, a, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, d, 0)
+	hi += carry
+	return lo, hi
+}
@@ -0,0 +1,181 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package fp
+
+import (
+	crand "crypto/rand"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/internal"
+)
+
+func fpRandBig(t *testing.T, rng *rand.Rand) *big.Int {
+	var buf [WideFieldBytes]byte
+	_, err := rng.Read(buf[:])
+	require.NoError(t, err)
+	return new(big.Int).Mod(new(big.Int).SetBytes(buf[:]), biModulus)
+}
+
+func TestFpSetOne(t *testing.T) {
+	one := P384FpNew().SetOne()
+	require.Equal(t, 1, one.IsOne())
+	require.Equal(t, big.NewInt(1), one.BigInt())
+	require.Equal(t, [Limbs]uint64{1}, one.Raw())
+}
+
+func TestFpSetUint64(t *testing.T) {
+	act := P384FpNew().SetUint64(1 << 60)
+	require.Equal(t, new(big.Int).Lsh(big.NewInt(1), 60), act.BigInt())
+	require.Equal(t, 1, P384FpNew().SetUint64(0).IsZero())
+}
+
+func TestFpArithmetic(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		a := fpRandBig(t, rng)
+		b := fpRandBig(t, rng)
+		fa := P384FpNew().SetBigInt(a)
+		fb := P384FpNew().SetBigInt(b)
+
+		exp := new(big.Int).Add(a, b)
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FpNew().Add(fa, fb).BigInt())
+
+		exp.Sub(a, b)
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FpNew().Sub(fa, fb).BigInt())
+
+		exp.Mul(a, b)
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FpNew().Mul(fa, fb).BigInt())
+
+		exp.Mul(a, a)
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FpNew().Square(fa).BigInt())
+
+		exp.Lsh(a, 1)
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FpNew().Double(fa).BigInt())
+
+		exp.Neg(a)
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FpNew().Neg(fa).BigInt())
+	}
+}
+
+func TestFpExtremes(t *testing.T) {
+	// m - 1 exercises the carries out of the top limb
+	mMinus1 := new(big.Int).Sub(biModulus, big.NewInt(1))
+	a := P384FpNew().SetBigInt(mMinus1)
+	one := P384FpNew().SetOne()
+	require.Equal(t, 1, P384FpNew().Add(a, one).IsZero())
+	require.Equal(t, 1, P384FpNew().Square(a).IsOne())
+	mMinus2 := new(big.Int).Sub(mMinus1, big.NewInt(1))
+	require.Equal(t, mMinus2, P384FpNew().Double(a).BigInt())
+	require.Equal(t, mMinus1, P384FpNew().Sub(P384FpNew(), one).BigInt())
+	require.Equal(t, 1, P384FpNew().Neg(P384FpNew()).IsZero())
+}
+
+func TestFpExp(t *testing.T) {
+	e := P384FpNew().SetUint64(8)
+	a := P384FpNew().SetUint64(2)
+	by2 := P384FpNew().SetUint64(256)
+	require.Equal(t, 1, by2.Equal(P384FpNew().Exp(a, e)))
+}
+
+func TestFpSqrt(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	squares, nonSquares := 0, 0
+	for i := 0; i < 40; i++ {
+		a := P384FpNew().SetBigInt(fpRandBig(t, rng))
+		sq := P384FpNew().Square(a)
+		r, wasSquare := P384FpNew().Sqrt(sq)
+		require.True(t, wasSquare)
+		require.Equal(t, 1, P384FpNew().Square(r).Equal(sq))
+
+		b := P384FpNew().SetBigInt(fpRandBig(t, rng))
+		r, wasSquare = P384FpNew().Sqrt(b)
+		if new(big.Int).ModSqrt(b.BigInt(), biModulus) != nil {
+			require.True(t, wasSquare)
+			require.Equal(t, 1, P384FpNew().Square(r).Equal(b))
+			squares++
+		} else {
+			require.False(t, wasSquare)
+			nonSquares++
+		}
+	}
+	require.NotZero(t, squares)
+	require.NotZero(t, nonSquares)
+	r, wasSquare := P384FpNew().Sqrt(P384FpNew())
+	require.True(t, wasSquare)
+	require.Equal(t, 1, r.IsZero())
+}
+
+func TestFpInvert(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	for i := 0; i < 20; i++ {
+		a := fpRandBig(t, rng)
+		inv, wasInverted := P384FpNew().Invert(P384FpNew().SetBigInt(a))
+		require.True(t, wasInverted)
+		require.Equal(t, new(big.Int).ModInverse(a, biModulus), inv.BigInt())
+	}
+	_, wasInverted := P384FpNew().Invert(P384FpNew())
+	require.False(t, wasInverted)
+}
+
+func TestFpCMove(t *testing.T) {
+	t1 := P384FpNew().SetUint64(5)
+	t2 := P384FpNew().SetUint64(10)
+	require.Equal(t, t1, P384FpNew().CMove(t1, t2, 0))
+	require.Equal(t, t2, P384FpNew().CMove(t1, t2, 1))
+}
+
+func TestFpBytes(t *testing.T) {
+	for i := 0; i < 25; i++ {
+		a, err := P384FpNew().Random(crand.Reader)
+		require.NoError(t, err)
+		seq := a.Bytes()
+		b, err := P384FpNew().SetBytes(&seq)
+		require.NoError(t, err)
+		require.Equal(t, 1, a.Equal(b))
+	}
+
+	var mod [FieldBytes]byte
+	biModulus.FillBytes(mod[:])
+	copy(mod[:], internal.ReverseScalarBytes(mod[:]))
+	_, err := P384FpNew().SetBytes(&mod)
+	require.Error(t, err)
+}
+
+func TestFpCmp(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	for i := 0; i < 25; i++ {
+		a := fpRandBig(t, rng)
+		b := fpRandBig(t, rng)
+		fa := P384FpNew().SetBigInt(a)
+		fb := P384FpNew().SetBigInt(b)
+		ra, rb := fa.Raw(), fb.Raw()
+		require.Equal(t, a.Cmp(b), (*Fp)(&ra).Cmp((*Fp)(&rb)))
+		require.Equal(t, 0, fa.Cmp(fa))
+	}
+}
+
+func TestFpSetBytesWide(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	for i := 0; i < 25; i++ {
+		var wide [WideFieldBytes]byte
+		_, err := rng.Read(wide[:])
+		require.NoError(t, err)
+		exp := new(big.Int).SetBytes(internal.ReverseScalarBytes(wide[:]))
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FpNew().SetBytesWide(&wide).BigInt())
+	}
+}
@@ -0,0 +1,181 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package fq
+
+import (
+	crand "crypto/rand"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/internal"
+)
+
+func fqRandBig(t *testing.T, rng *rand.Rand) *big.Int {
+	var buf [WideFieldBytes]byte
+	_, err := rng.Read(buf[:])
+	require.NoError(t, err)
+	return new(big.Int).Mod(new(big.Int).SetBytes(buf[:]), biModulus)
+}
+
+func TestFqSetOne(t *testing.T) {
+	one := P384FqNew().SetOne()
+	require.Equal(t, 1, one.IsOne())
+	require.Equal(t, big.NewInt(1), one.BigInt())
+	require.Equal(t, [Limbs]uint64{1}, one.Raw())
+}
+
+func TestFqSetUint64(t *testing.T) {
+	act := P384FqNew().SetUint64(1 << 60)
+	require.Equal(t, new(big.Int).Lsh(big.NewInt(1), 60), act.BigInt())
+	require.Equal(t, 1, P384FqNew().SetUint64(0).IsZero())
+}
+
+func TestFqArithmetic(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		a := fqRandBig(t, rng)
+		b := fqRandBig(t, rng)
+		fa := P384FqNew().SetBigInt(a)
+		fb := P384FqNew().SetBigInt(b)
+
+		exp := new(big.Int).Add(a, b)
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FqNew().Add(fa, fb).BigInt())
+
+		exp.Sub(a, b)
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FqNew().Sub(fa, fb).BigInt())
+
+		exp.Mul(a, b)
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FqNew().Mul(fa, fb).BigInt())
+
+		exp.Mul(a, a)
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FqNew().Square(fa).BigInt())
+
+		exp.Lsh(a, 1)
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FqNew().Double(fa).BigInt())
+
+		exp.Neg(a)
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FqNew().Neg(fa).BigInt())
+	}
+}
+
+func TestFqExtremes(t *testing.T) {
+	// m - 1 exercises the carries out of the top limb
+	mMinus1 := new(big.Int).Sub(biModulus, big.NewInt(1))
+	a := P384FqNew().SetBigInt(mMinus1)
+	one := P384FqNew().SetOne()
+	require.Equal(t, 1, P384FqNew().Add(a, one).IsZero())
+	require.Equal(t, 1, P384FqNew().Square(a).IsOne())
+	mMinus2 := new(big.Int).Sub(mMinus1, big.NewInt(1))
+	require.Equal(t, mMinus2, P384FqNew().Double(a).BigInt())
+	require.Equal(t, mMinus1, P384FqNew().Sub(P384FqNew(), one).BigInt())
+	require.Equal(t, 1, P384FqNew().Neg(P384FqNew()).IsZero())
+}
+
+func TestFqExp(t *testing.T) {
+	e := P384FqNew().SetUint64(8)
+	a := P384FqNew().SetUint64(2)
+	by2 := P384FqNew().SetUint64(256)
+	require.Equal(t, 1, by2.Equal(P384FqNew().Exp(a, e)))
+}
+
+func TestFqSqrt(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	squares, nonSquares := 0, 0
+	for i := 0; i < 40; i++ {
+		a := P384FqNew().SetBigInt(fqRandBig(t, rng))
+		sq := P384FqNew().Square(a)
+		r, wasSquare := P384FqNew().Sqrt(sq)
+		require.True(t, wasSquare)
+		require.Equal(t, 1, P384FqNew().Square(r).Equal(sq))
+
+		b := P384FqNew().SetBigInt(fqRandBig(t, rng))
+		r, wasSquare = P384FqNew().Sqrt(b)
+		if new(big.Int).ModSqrt(b.BigInt(), biModulus) != nil {
+			require.True(t, wasSquare)
+			require.Equal(t, 1, P384FqNew().Square(r).Equal(b))
+			squares++
+		} else {
+			require.False(t, wasSquare)
+			nonSquares++
+		}
+	}
+	require.NotZero(t, squares)
+	require.NotZero(t, nonSquares)
+	r, wasSquare := P384FqNew().Sqrt(P384FqNew())
+	require.True(t, wasSquare)
+	require.Equal(t, 1, r.IsZero())
+}
+
+func TestFqInvert(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	for i := 0; i < 20; i++ {
+		a := fqRandBig(t, rng)
+		inv, wasInverted := P384FqNew().Invert(P384FqNew().SetBigInt(a))
+		require.True(t, wasInverted)
+		require.Equal(t, new(big.Int).ModInverse(a, biModulus), inv.BigInt())
+	}
+	_, wasInverted := P384FqNew().Invert(P384FqNew())
+	require.False(t, wasInverted)
+}
+
+func TestFqCMove(t *testing.T) {
+	t1 := P384FqNew().SetUint64(5)
+	t2 := P384FqNew().SetUint64(10)
+	require.Equal(t, t1, P384FqNew().CMove(t1, t2, 0))
+	require.Equal(t, t2, P384FqNew().CMove(t1, t2, 1))
+}
+
+func TestFqBytes(t *testing.T) {
+	for i := 0; i < 25; i++ {
+		a, err := P384FqNew().Random(crand.Reader)
+		require.NoError(t, err)
+		seq := a.Bytes()
+		b, err := P384FqNew().SetBytes(&seq)
+		require.NoError(t, err)
+		require.Equal(t, 1, a.Equal(b))
+	}
+
+	var mod [FieldBytes]byte
+	biModulus.FillBytes(mod[:])
+	copy(mod[:], internal.ReverseScalarBytes(mod[:]))
+	_, err := P384FqNew().SetBytes(&mod)
+	require.Error(t, err)
+}
+
+func TestFqCmp(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	for i := 0; i < 25; i++ {
+		a := fqRandBig(t, rng)
+		b := fqRandBig(t, rng)
+		fa := P384FqNew().SetBigInt(a)
+		fb := P384FqNew().SetBigInt(b)
+		ra, rb := fa.Raw(), fb.Raw()
+		require.Equal(t, a.Cmp(b), (*Fq)(&ra).Cmp((*Fq)(&rb)))
+		require.Equal(t, 0, fa.Cmp(fa))
+	}
+}
+
+func TestFqSetBytesWide(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	for i := 0; i < 25; i++ {
+		var wide [WideFieldBytes]byte
+		_, err := rng.Read(wide[:])
+		require.NoError(t, err)
+		exp := new(big.Int).SetBytes(internal.ReverseScalarBytes(wide[:]))
+		exp.Mod(exp, biModulus)
+		require.Equal(t, exp, P384FqNew().SetBytesWide(&wide).BigInt())
+	}
+}
@@ -0,0 +1,659 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package p384
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/internal"
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/p384/fp"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/p384/fq"
+)
+
+const (
+	// CompressedBytes is the length of a SEC1 compressed point
+	CompressedBytes = fp.FieldBytes + 1
+	// UncompressedBytes is the length of a SEC1 uncompressed point
+	UncompressedBytes = 2*fp.FieldBytes + 1
+	// hashBytes is the length L of each element derived by hash to field
+	hashBytes = 72
+)
+
+// See FIPS 186-4, section D.1.2.4. All constants are in montgomery form
+var (
+	curveB = fp.Fp{
+		0x081188719d412dcc,
+		0xf729add87a4c32ec,
+		0x77f2209b1920022e,
+		0xe3374bee94938ae2,
+		0xb62b21f41f022094,
+		0xcd08114b604fbff9,
+	}
+	generatorX = fp.Fp{
+		0x3dd0756649c0b528,
+		0x20e378e2a0d6ce38,
+		0x879c3afc541b4d6e,
+		0x6454868459a30eff,
+		0x812ff723614ede2b,
+		0x4d3aadc2299e1513,
+	}
+	generatorY = fp.Fp{
+		0x23043dad4b03a4fe,
+		0xa1bfa8bf7bb4a9ac,
+		0x8bade7562e83b050,
+		0xc6c3521968f4ffd9,
+		0xdd8002263969a840,
+		0x2b78abc25a15c5e9,
+	}
+	// sswuZ = -12, see RFC 9380 section 8.3
+	sswuZ = fp.Fp{
+		0x0000000cfffffff3,
+		0xfffffff300000000,
+		0xfffffffffffffff2,
+		0xffffffffffffffff,
+		0xffffffffffffffff,
+		0xffffffffffffffff,
+	}
+	// sswuC1 = -B / A
+	sswuC1 = fp.Fp{
+		0xad5b2d7b346b0f44,
+		0xfd0de49d7e19664e,
+		0x27fb6033b30aab64,
+		0x4bbd194f86dbd8f6,
+		0xe763b5fc0a560adc,
+		0x445805c3cac53ffd,
+	}
+	// sswuC2 = B / (Z * A)
+	sswuC2 = fp.Fp{
+		0xe3c7991fef08ebf0,
+		0x6a6bd30cca821ddb,
+		0x2dff9d59a440e39d,
+		0x5ba517714b3cfcbf,
+		0x289da47fab872b92,
+		0x5b0755d050e5c555,
+	}
+)
+
+// Point is a P-384 point in projective coordinates
+type Point struct {
+	x, y, z fp.Fp
+}
+
+// P384PointNew returns a new identity point
+func P384PointNew() *Point {
+	return new(Point).Identity()
+}
+
+// Random creates a random point on the curve
+// from the specified reader
+func (p *Point) Random(reader io.Reader) (*Point, error) {
+	var seed [fp.WideFieldBytes]byte
+	n, err := reader.Read(seed[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "random could not read from stream")
+	}
+	if n != fp.WideFieldBytes {
+		return nil, fmt.Errorf("insufficient bytes read %d when %d are needed", n, fp.WideFieldBytes)
+	}
+	dst := []byte("P384_XMD:SHA-384_SSWU_RO_")
+	return p.Hash(native.EllipticPointHasherSha384(), seed[:], dst), nil
+}
+
+// Hash uses the hasher to map bytes to a valid point
+// with the simplified SWU map from RFC 9380
+func (p *Point) Hash(hash *native.EllipticPointHasher, msg, dst []byte) *Point {
+	var u []byte
+	var u0, u1 fp.Fp
+	var q0, q1 Point
+
+	switch hash.Type() {
+	case native.XMD:
+		u = native.ExpandMsgXmd(hash, msg, dst, 2*hashBytes)
+	case native.XOF:
+		u = native.ExpandMsgXof(hash, msg, dst, 2*hashBytes)
+	}
+
+	var buf [fp.WideFieldBytes]byte
+	copy(buf[:hashBytes], internal.ReverseScalarBytes(u[:hashBytes]))
+	u0.SetBytesWide(&buf)
+	copy(buf[:hashBytes], internal.ReverseScalarBytes(u[hashBytes:]))
+	u1.SetBytesWide(&buf)
+
+	q0.sswu(&u0)
+	q1.sswu(&u1)
+	// The cofactor is 1 so there is nothing to clear
+	return p.Add(&q0, &q1)
+}
+
+// Identity returns the identity point
+func (p *Point) Identity() *Point {
+	p.x.SetZero()
+	p.y.SetOne()
+	p.z.SetZero()
+	return p
+}
+
+// Generator returns the base point
+func (p *Point) Generator() *Point {
+	p.x.Set(&generatorX)
+	p.y.Set(&generatorY)
+	p.z.SetOne()
+	return p
+}
+
+// IsIdentity returns 1 if this point is at infinity, 0 otherwise
+func (p *Point) IsIdentity() int {
+	return p.z.IsZero()
+}
+
+// IsOnCurve returns 1 if this point represents a valid curve point, 0 otherwise
+func (p *Point) IsOnCurve() int {
+	// Y^2 Z = X^3 - 3 X Z^2 + b Z^3
+	var lhs, rhs, zz, t fp.Fp
+	zz.Square(&p.z)
+	lhs.Square(&p.y)
+	lhs.Mul(&lhs, &p.z)
+
+	rhs.Square(&p.x)
+	rhs.Mul(&rhs, &p.x)
+	t.Mul(&p.x, &zz)
+	rhs.Sub(&rhs, &t)
+	rhs.Sub(&rhs, &t)
+	rhs.Sub(&rhs, &t)
+	t.Mul(&zz, &p.z)
+	t.Mul(&t, &curveB)
+	rhs.Add(&rhs, &t)
+
+	// The group has odd order so no point has y = 0,
+	// which also rules out the all zero coordinates
+	return lhs.Equal(&rhs) & p.y.IsNonZero()
+}
+
+// Add adds two points
+func (p *Point) Add(arg1, arg2 *Point) *Point {
+	// Addition formula from Renes-Costello-Batina 2015
+	// (https://eprint.iacr.org/2015/1060 Algorithm 4).
+	var xx, yy, zz, zz3, bxz, bxz3 fp.Fp
+	var tv1, xyPairs, yzPairs, xzPairs fp.Fp
+	var bzz, bzz3, yyMBzz3, yyPBzz3 fp.Fp
+	var xx3Mzz3, x, y, z fp.Fp
+
+	xx.Mul(&arg1.x, &arg2.x)
+	yy.Mul(&arg1.y, &arg2.y)
+	zz.Mul(&arg1.z, &arg2.z)
+
+	tv1.Add(&arg2.x, &arg2.y)
+	xyPairs.Add(&arg1.x, &arg1.y)
+	xyPairs.Mul(&xyPairs, &tv1)
+	xyPairs.Sub(&xyPairs, &xx)
+	xyPairs.Sub(&xyPairs, &yy)
+
+	tv1.Add(&arg2.y, &arg2.z)
+	yzPairs.Add(&arg1.y, &arg1.z)
+	yzPairs.Mul(&yzPairs, &tv1)
+	yzPairs.Sub(&yzPairs, &yy)
+	yzPairs.Sub(&yzPairs, &zz)
+
+	tv1.Add(&arg2.x, &arg2.z)
+	xzPairs.Add(&arg1.x, &arg1.z)
+	xzPairs.Mul(&xzPairs, &tv1)
+	xzPairs.Sub(&xzPairs, &xx)
+	xzPairs.Sub(&xzPairs, &zz)
+
+	bzz.Mul(&curveB, &zz)
+	bzz.Sub(&xzPairs, &bzz)
+
+	bzz3.Double(&bzz)
+	bzz3.Add(&bzz3, &bzz)
+
+	yyMBzz3.Sub(&yy, &bzz3)
+	yyPBzz3.Add(&yy, &bzz3)
+
+	zz3.Double(&zz)
+	zz3.Add(&zz3, &zz)
+
+	bxz.Mul(&curveB, &xzPairs)
+	bxz.Sub(&bxz, &zz3)
+	bxz.Sub(&bxz, &xx)
+
+	bxz3.Double(&bxz)
+	bxz3.Add(&bxz3, &bxz)
+
+	xx3Mzz3.Double(&xx)
+	xx3Mzz3.Add(&xx3Mzz3, &xx)
+	xx3Mzz3.Sub(&xx3Mzz3, &zz3)
+
+	tv1.Mul(&yzPairs, &bxz3)
+	x.Mul(&yyPBzz3, &xyPairs)
+	x.Sub(&x, &tv1)
+
+	tv1.Mul(&xx3Mzz3, &bxz3)
+	y.Mul(&yyPBzz3, &yyMBzz3)
+	y.Add(&y, &tv1)
+
+	tv1.Mul(&xyPairs, &xx3Mzz3)
+	z.Mul(&yyMBzz3, &yzPairs)
+	z.Add(&z, &tv1)
+
+	p.x.Set(&x)
+	p.y.Set(&y)
+	p.z.Set(&z)
+	return p
+}
+
+// Sub subtracts the two points
+func (p *Point) Sub(arg1, arg2 *Point) *Point {
+	var t Point
+	t.Neg(arg2)
+	return p.Add(arg1, &t)
+}
+
+// Double this point
+func (p *Point) Double(a *Point) *Point {
+	// Doubling formula from Renes-Costello-Batina 2015
+	// (https://eprint.iacr.org/2015/1060 Algorithm 6)
+	var xx, yy, zz, xy2, yz2, xz2, bzz, bzz3 fp.Fp
+	var yyMBzz3, yyPBzz3, yFrag, xFrag, zz3 fp.Fp
+	var bxz2, bxz6, xx3Mzz3, x, y, z fp.Fp
+
+	xx.Square(&a.x)
+	yy.Square(&a.y)
+	zz.Square(&a.z)
+
+	xy2.Mul(&a.x, &a.y)
+	xy2.Double(&xy2)
+
+	yz2.Mul(&a.y, &a.z)
+	yz2.Double(&yz2)
+
+	xz2.Mul(&a.x, &a.z)
+	xz2.Double(&xz2)
+
+	bzz.Mul(&curveB, &zz)
+	bzz.Sub(&bzz, &xz2)
+
+	bzz3.Double(&bzz)
+	bzz3.Add(&bzz3, &bzz)
+
+	yyMBzz3.Sub(&yy, &bzz3)
+	yyPBzz3.Add(&yy, &bzz3)
+	yFrag.Mul(&yyPBzz3, &yyMBzz3)
+	xFrag.Mul(&yyMBzz3, &xy2)
+
+	zz3.Double(&zz)
+	zz3.Add(&zz3, &zz)
+
+	bxz2.Mul(&curveB, &xz2)
+	bxz2.Sub(&bxz2, &zz3)
+	bxz2.Sub(&bxz2, &xx)
+
+	bxz6.Double(&bxz2)
+	bxz6.Add(&bxz6, &bxz2)
+
+	xx3Mzz3.Double(&xx)
+	xx3Mzz3.Add(&xx3Mzz3, &xx)
+	xx3Mzz3.Sub(&xx3Mzz3, &zz3)
+
+	x.Mul(&bxz6, &yz2)
+	x.Sub(&xFrag, &x)
+
+	y.Mul(&xx3Mzz3, &bxz6)
+	y.Add(&yFrag, &y)
+
+	z.Mul(&yz2, &yy)
+	z.Double(&z)
+	z.Double(&z)
+
+	p.x.Set(&x)
+	p.y.Set(&y)
+	p.z.Set(&z)
+	return p
+}
+
+// Mul multiplies this point by the input scalar
+func (p *Point) Mul(a *Point, s *fq.Fq) *Point {
+	bytes := s.Bytes()
+	var precomputed [16]Point
+	precomputed[0].Identity()
+	precomputed[1].Set(a)
+	for i := 2; i < 16; i += 2 {
+		precomputed[i].Double(&precomputed[i>>1])
+		precomputed[i+1].Add(&precomputed[i], a)
+	}
+
+	var r, t Point
+	r.Identity()
+	for i := fq.FieldBytes*8 - 4; i >= 0; i -= 4 {
+		// Brouwer / windowing method. window size of 4.
+		for j := 0; j < 4; j++ {
+			r.Double(&r)
+		}
+		window := int(bytes[i>>3]>>(i&4)) & 0x0F
+		// Scan the whole table so the lookup doesn't leak the window
+		t.Identity()
+		for k := 1; k < 16; k++ {
+			t.CMove(&t, &precomputed[k], ctEqual(k, window))
+		}
+		r.Add(&r, &t)
+	}
+	return p.Set(&r)
+}
+
+// Neg negates this point
+func (p *Point) Neg(a *Point) *Point {
+	p.x.Set(&a.x)
+	p.y.Neg(&a.y)
+	p.z.Set(&a.z)
+	return p
+}
+
+// Set copies a into p
+func (p *Point) Set(a *Point) *Point {
+	p.x.Set(&a.x)
+	p.y.Set(&a.y)
+	p.z.Set(&a.z)
+	return p
+}
+
+// BigInt returns the x and y as big.Ints in affine
+func (p *Point) BigInt() (x, y *big.Int) {
+	var t Point
+	t.ToAffine(p)
+	x = t.x.BigInt()
+	y = t.y.BigInt()
+	return
+}
+
+// SetBigInt creates a point from affine x, y
+// and returns the point if it is on the curve
+func (p *Point) SetBigInt(x, y *big.Int) (*Point, error) {
+	var pp Point
+	pp.x.SetBigInt(x)
+	pp.y.SetBigInt(y)
+
+	if pp.x.IsZero()&pp.y.IsZero() == 1 {
+		return p.Identity(), nil
+	}
+
+	pp.z.SetOne()
+	if pp.IsOnCurve() == 0 {
+		return nil, fmt.Errorf("invalid coordinates")
+	}
+	return p.Set(&pp), nil
+}
+
+// ToCompressed serializes this element into SEC1 compressed form.
+// The identity is encoded as all zeros
+func (p *Point) ToCompressed() [CompressedBytes]byte {
+	var out [CompressedBytes]byte
+	var t Point
+	t.ToAffine(p)
+	xBytes := t.x.Bytes()
+	copy(out[1:], internal.ReverseScalarBytes(xBytes[:]))
+	out[0] = byte(2 | t.y.Sgn0())
+	out[0] &= byte(p.IsIdentity()) - 1
+	return out
+}
+
+// FromCompressed deserializes this element from SEC1 compressed form.
+func (p *Point) FromCompressed(input *[CompressedBytes]byte) (*Point, error) {
+	var x [fp.FieldBytes]byte
+	var xFp, yFp fp.Fp
+
+	if input[0] == 0 {
+		if isZero(input[1:]) {
+			return p.Identity(), nil
+		}
+		return nil, errors.New("invalid identity encoding")
+	}
+	if input[0] != 2 && input[0] != 3 {
+		return nil, errors.New("invalid sign byte")
+	}
+	sign := int(input[0] & 1)
+
+	copy(x[:], internal.ReverseScalarBytes(input[1:]))
+	if _, err := xFp.SetBytes(&x); err != nil {
+		return nil, errors.New("invalid bytes - not in field")
+	}
+
+	rhs(&yFp, &xFp)
+	if _, wasSquare := yFp.Sqrt(&yFp); !wasSquare {
+		return nil, errors.New("point is not on the curve")
+	}
+	yFp.CNeg(&yFp, yFp.Sgn0()^sign)
+
+	p.x.Set(&xFp)
+	p.y.Set(&yFp)
+	p.z.SetOne()
+	return p, nil
+}
+
+// ToUncompressed serializes this element into SEC1 uncompressed form.
+// The identity is encoded as all zeros
+func (p *Point) ToUncompressed() [UncompressedBytes]byte {
+	var out [UncompressedBytes]byte
+	if p.IsIdentity() == 1 {
+		return out
+	}
+	var t Point
+	t.ToAffine(p)
+	xBytes := t.x.Bytes()
+	yBytes := t.y.Bytes()
+	out[0] = 4
+	copy(out[1:1+fp.FieldBytes], internal.ReverseScalarBytes(xBytes[:]))
+	copy(out[1+fp.FieldBytes:], internal.ReverseScalarBytes(yBytes[:]))
+	return out
+}
+
+// FromUncompressed deserializes this element from SEC1 uncompressed form.
+func (p *Point) FromUncompressed(input *[UncompressedBytes]byte) (*Point, error) {
+	var t [fp.FieldBytes]byte
+	var pp Point
+
+	if input[0] == 0 {
+		if isZero(input[1:]) {
+			return p.Identity(), nil
+		}
+		return nil, errors.New("invalid identity encoding")
+	}
+	if input[0] != 4 {
+		return nil, errors.New("invalid sign byte")
+	}
+
+	copy(t[:], internal.ReverseScalarBytes(input[1:1+fp.FieldBytes]))
+	if _, err := pp.x.SetBytes(&t); err != nil {
+		return nil, errors.New("invalid bytes - x not in field")
+	}
+	copy(t[:], internal.ReverseScalarBytes(input[1+fp.FieldBytes:]))
+	if _, err := pp.y.SetBytes(&t); err != nil {
+		return nil, errors.New("invalid bytes - y not in field")
+	}
+	pp.z.SetOne()
+
+	if pp.IsOnCurve() == 0 {
+		return nil, errors.New("point is not on the curve")
+	}
+	return p.Set(&pp), nil
+}
+
+// ToAffine converts the point into affine coordinates
+func (p *Point) ToAffine(a *Point) *Point {
+	var zero, x, y, z fp.Fp
+	_, _ = z.Invert(&a.z)
+	x.Mul(&a.x, &z)
+	y.Mul(&a.y, &z)
+
+	choice := a.z.IsNonZero()
+	p.x.CMove(&zero, &x, choice)
+	p.y.CMove(&zero, &y, choice)
+	p.z.CMove(&zero, z.SetOne(), choice)
+	return p
+}
+
+// GetX returns the affine X coordinate
+func (p *Point) GetX() *fp.Fp {
+	var t Point
+	t.ToAffine(p)
+	return &t.x
+}
+
+// GetY returns the affine Y coordinate
+func (p *Point) GetY() *fp.Fp {
+	var t Point
+	t.ToAffine(p)
+	return &t.y
+}
+
+// Equal returns 1 if the two points are equal 0 otherwise.
+func (p *Point) Equal(rhs *Point) int {
+	var x1, x2, y1, y2 fp.Fp
+	var e1, e2 int
+
+	// This technique avoids inversions
+	x1.Mul(&p.x, &rhs.z)
+	x2.Mul(&rhs.x, &p.z)
+
+	y1.Mul(&p.y, &rhs.z)
+	y2.Mul(&rhs.y, &p.z)
+
+	e1 = p.z.IsZero()
+	e2 = rhs.z.IsZero()
+
+	// Both at infinity or coordinates are the same
+	return (e1 & e2) | (^e1 & ^e2)&x1.Equal(&x2)&y1.Equal(&y2)
+}
+
+// CMove sets p = arg1 if choice == 0 and p = arg2 if choice == 1
+func (p *Point) CMove(arg1, arg2 *Point, choice int) *Point {
+	p.x.CMove(&arg1.x, &arg2.x, choice)
+	p.y.CMove(&arg1.y, &arg2.y, choice)
+	p.z.CMove(&arg1.z, &arg2.z, choice)
+	return p
+}
+
+// SumOfProducts computes the multi-exponentiation for the specified
+// points and scalars and stores the result in `p`.
+// Returns an error if the lengths of the arguments is not equal.
+func (p *Point) SumOfProducts(points []*Point, scalars []*fq.Fq) (*Point, error) {
+	const Upper = fq.FieldBytes * 8
+	const W = 4
+	const Windows = Upper / W // careful--use ceiling division in case this doesn't divide evenly
+	var sum Point
+	if len(points) != len(scalars) {
+		return nil, fmt.Errorf("length mismatch")
+	}
+
+	bucketSize := 1 << W
+	windows := make([]Point, Windows)
+	bytes := make([][fq.FieldBytes]byte, len(scalars))
+	buckets := make([]Point, bucketSize)
+
+	for i, scalar := range scalars {
+		bytes[i] = scalar.Bytes()
+	}
+	for i := range windows {
+		windows[i].Identity()
+	}
+
+	for j := 0; j < len(windows); j++ {
+		for i := 0; i < bucketSize; i++ {
+			buckets[i].Identity()
+		}
+
+		for i := 0; i < len(scalars); i++ {
+			// j*W to get the nibble
+			// >> 3 to convert to byte, / 8
+			// (W * j & W) gets the nibble, mod W
+			// 1 << W - 1 to get the offset
+			index := bytes[i][j*W>>3] >> (W * j & W) & (1<<W - 1) // little-endian
+			buckets[index].Add(&buckets[index], points[i])
+		}
+
+		sum.Identity()
+
+		for i := bucketSize - 1; i > 0; i-- {
+			sum.Add(&sum, &buckets[i])
+			windows[j].Add(&windows[j], &sum)
+		}
+	}
+
+	p.Identity()
+	for i := len(windows) - 1; i >= 0; i-- {
+		for j := 0; j < W; j++ {
+			p.Double(p)
+		}
+
+		p.Add(p, &windows[i])
+	}
+	return p, nil
+}
+
+// sswu is the simplified Shallue-van de Woestijne-Ulas
+// method from RFC 9380 section 6.6.2
+func (p *Point) sswu(u *fp.Fp) *Point {
+	var tv1, tv2, x1, x2, gx1, gx2, y1, y2 fp.Fp
+
+	// tv1 = Z * u^2
+	tv1.Square(u)
+	tv1.Mul(&tv1, &sswuZ)
+	// tv2 = tv1^2 + tv1
+	tv2.Square(&tv1)
+	tv2.Add(&tv2, &tv1)
+	// x1 = (-B / A) * (1 + 1 / tv2), or B / (Z * A) if tv2 == 0
+	exceptional := tv2.IsZero()
+	_, _ = tv2.Invert(&tv2)
+	tv2.Add(&tv2, new(fp.Fp).SetOne())
+	x1.Mul(&tv2, &sswuC1)
+	x1.CMove(&x1, &sswuC2, exceptional)
+	// x2 = Z * u^2 * x1
+	x2.Mul(&tv1, &x1)
+
+	rhs(&gx1, &x1)
+	rhs(&gx2, &x2)
+	_, gx1Square := y1.Sqrt(&gx1)
+	_, _ = y2.Sqrt(&gx2)
+
+	e := 0
+	if gx1Square {
+		e = 1
+	}
+	p.x.CMove(&x2, &x1, e)
+	p.y.CMove(&y2, &y1, e)
+	// Fix the sign of y to match u
+	p.y.CNeg(&p.y, u.Sgn0()^p.y.Sgn0())
+	p.z.SetOne()
+	return p
+}
+
+// rhs computes x^3 - 3x + b
+func rhs(out, x *fp.Fp) *fp.Fp {
+	var x3, x3a fp.Fp
+	x3.Square(x)
+	x3.Mul(&x3, x)
+	x3a.Double(x)
+	x3a.Add(&x3a, x)
+	x3.Sub(&x3, &x3a)
+	return out.Add(&x3, &curveB)
+}
+
+// ctEqual returns 1 if a == b, 0 otherwise
+func ctEqual(a, b int) int {
+	return int((uint64(a^b) - 1) >> 63)
+}
+
+func isZero(input []byte) bool {
+	t := byte(0)
+	for _, b := range input {
+		t |= b
+	}
+	return t == 0
+}
@@ -0,0 +1,184 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package p384
+
+import (
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/p384/fq"
+)
+
+func bigFromHex(t *testing.T, s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 16)
+	require.True(t, ok)
+	return v
+}
+
+func TestPointGenerator(t *testing.T) {
+	g := P384PointNew().Generator()
+	require.Equal(t, 1, g.IsOnCurve())
+	x, y := g.BigInt()
+	params := elliptic.P384().Params()
+	require.Equal(t, params.Gx, x)
+	require.Equal(t, params.Gy, y)
+
+	id := P384PointNew()
+	require.Equal(t, 1, id.IsIdentity())
+	require.Equal(t, 1, id.IsOnCurve())
+}
+
+func TestPointAddDouble(t *testing.T) {
+	g := P384PointNew().Generator()
+	id := P384PointNew()
+	d := P384PointNew().Double(g)
+	require.Equal(t, 1, d.Equal(P384PointNew().Add(g, g)))
+	require.Equal(t, 1, g.Equal(P384PointNew().Add(g, id)))
+	require.Equal(t, 1, g.Equal(P384PointNew().Add(id, g)))
+	require.Equal(t, 1, P384PointNew().Sub(g, g).IsIdentity())
+	require.Equal(t, 1, P384PointNew().Double(id).IsIdentity())
+
+	x, y := d.BigInt()
+	ex, ey := elliptic.P384().Double(elliptic.P384().Params().Gx, elliptic.P384().Params().Gy)
+	require.Equal(t, ex, x)
+	require.Equal(t, ey, y)
+}
+
+func TestPointMul(t *testing.T) {
+	params := elliptic.P384().Params()
+	for i := 0; i < 10; i++ {
+		s, err := fq.P384FqNew().Random(crand.Reader)
+		require.NoError(t, err)
+		p := P384PointNew().Mul(P384PointNew().Generator(), s)
+		require.Equal(t, 1, p.IsOnCurve())
+		x, y := p.BigInt()
+		k := s.BigInt().Bytes()
+		ex, ey := elliptic.P384().ScalarMult(params.Gx, params.Gy, k)
+		require.Equal(t, ex, x)
+		require.Equal(t, ey, y)
+	}
+
+	// (n - 1) * G = -G
+	s := fq.P384FqNew().SetBigInt(new(big.Int).Sub(params.N, big.NewInt(1)))
+	p := P384PointNew().Mul(P384PointNew().Generator(), s)
+	require.Equal(t, 1, p.Equal(P384PointNew().Neg(P384PointNew().Generator())))
+	require.Equal(t, 1, P384PointNew().Mul(p, fq.P384FqNew()).IsIdentity())
+}
+
+func TestPointHash(t *testing.T) {
+	// Test vectors from RFC 9380 appendix J.2.1
+	dst := []byte("QUUX-V01-CS02-with-P384_XMD:SHA-384_SSWU_RO_")
+	tests := []struct {
+		msg  string
+		x, y string
+	}{
+		{
+			msg: "",
+			x:   "eb9fe1b4f4e14e7140803c1d99d0a93cd823d2b024040f9c067a8eca1f5a2eeac9ad604973527a356f3fa3aeff0e4d83",
+			y:   "0c21708cff382b7f4643c07b105c2eaec2cead93a917d825601e63c8f21f6abd9abc22c93c2bed6f235954b25048bb1a",
+		},
+		{
+			msg: "abc",
+			x:   "e02fc1a5f44a7519419dd314e29863f30df55a514da2d655775a81d413003c4d4e7fd59af0826dfaad4200ac6f60abe1",
+			y:   "01f638d04d98677d65bef99aef1a12a70a4cbb9270ec55248c04530d8bc1f8f90f8a6a859a7c1f1ddccedf8f96d675f6",
+		},
+		{
+			msg: "abcdef0123456789",
+			x:   "bdecc1c1d870624965f19505be50459d363c71a699a496ab672f9a5d6b78676400926fbceee6fcd1780fe86e62b2aa89",
+			y:   "57cf1f99b5ee00f3c201139b3bfe4dd30a653193778d89a0accc5e0f47e46e4e4b85a0595da29c9494c1814acafe183c",
+		},
+	}
+	for _, test := range tests {
+		p := P384PointNew().Hash(native.EllipticPointHasherSha384(), []byte(test.msg), dst)
+		require.Equal(t, 1, p.IsOnCurve())
+		x, y := p.BigInt()
+		require.Equal(t, bigFromHex(t, test.x), x, test.msg)
+		require.Equal(t, bigFromHex(t, test.y), y, test.msg)
+	}
+}
+
+func TestPointSerialization(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		p, err := P384PointNew().Random(crand.Reader)
+		require.NoError(t, err)
+
+		c := p.ToCompressed()
+		q, err := P384PointNew().FromCompressed(&c)
+		require.NoError(t, err)
+		require.Equal(t, 1, p.Equal(q))
+
+		u := p.ToUncompressed()
+		require.Equal(t, byte(4), u[0])
+		q, err = P384PointNew().FromUncompressed(&u)
+		require.NoError(t, err)
+		require.Equal(t, 1, p.Equal(q))
+
+		x, y := p.BigInt()
+		require.Equal(t, elliptic.Marshal(elliptic.P384(), x, y), u[:])
+		require.Equal(t, elliptic.MarshalCompressed(elliptic.P384(), x, y), c[:])
+	}
+
+	id := P384PointNew()
+	c := id.ToCompressed()
+	require.Equal(t, [CompressedBytes]byte{}, c)
+	q, err := P384PointNew().Generator().FromCompressed(&c)
+	require.NoError(t, err)
+	require.Equal(t, 1, q.IsIdentity())
+	u := id.ToUncompressed()
+	require.Equal(t, [UncompressedBytes]byte{}, u)
+	q, err = P384PointNew().Generator().FromUncompressed(&u)
+	require.NoError(t, err)
+	require.Equal(t, 1, q.IsIdentity())
+
+	// Not on the curve
+	u = P384PointNew().Generator().ToUncompressed()
+	u[UncompressedBytes-1] ^= 1
+	_, err = P384PointNew().FromUncompressed(&u)
+	require.Error(t, err)
+	c[0] = 5
+	_, err = P384PointNew().FromCompressed(&c)
+	require.Error(t, err)
+}
+
+func TestPointSetBigInt(t *testing.T) {
+	params := elliptic.P384().Params()
+	p, err := P384PointNew().SetBigInt(params.Gx, params.Gy)
+	require.NoError(t, err)
+	require.Equal(t, 1, p.Equal(P384PointNew().Generator()))
+
+	p, err = P384PointNew().SetBigInt(big.NewInt(0), big.NewInt(0))
+	require.NoError(t, err)
+	require.Equal(t, 1, p.IsIdentity())
+
+	_, err = P384PointNew().SetBigInt(params.Gx, new(big.Int).Add(params.Gy, big.NewInt(1)))
+	require.Error(t, err)
+}
+
+func TestPointSumOfProducts(t *testing.T) {
+	points := make([]*Point, 5)
+	scalars := make([]*fq.Fq, 5)
+	expected := P384PointNew()
+	for i := range points {
+		var err error
+		points[i], err = P384PointNew().Random(crand.Reader)
+		require.NoError(t, err)
+		scalars[i], err = fq.P384FqNew().Random(crand.Reader)
+		require.NoError(t, err)
+		expected.Add(expected, P384PointNew().Mul(points[i], scalars[i]))
+	}
+	actual, err := P384PointNew().SumOfProducts(points, scalars)
+	require.NoError(t, err)
+	require.Equal(t, 1, expected.Equal(actual))
+
+	_, err = P384PointNew().SumOfProducts(points, scalars[1:])
+	require.Error(t, err)
+}
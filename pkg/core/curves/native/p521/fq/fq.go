@@ -0,0 +1,531 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package fq
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+
+	"github.com/coinbase/kryptology/internal"
+)
+
+const (
+	// Limbs is the number of 64-bit words in an element
+	Limbs = 9
+	// FieldBytes is the number of bytes in a canonical element
+	FieldBytes = 66
+	// WideFieldBytes is the number of bytes accepted by SetBytesWide
+	WideFieldBytes = 132
+)
+
+// Fq is an element of the P521 scalar field in montgomery form
+type Fq [Limbs]uint64
+
+var (
+	// See FIPS 186-4, section D.1.2.5
+	modulus = Fq{
+		0xbb6fb71e91386409,
+		0x3bb5c9b8899c47ae,
+		0x7fcc0148f709a5d0,
+		0x51868783bf2f966b,
+		0xfffffffffffffffa,
+		0xffffffffffffffff,
+		0xffffffffffffffff,
+		0xffffffffffffffff,
+		0x00000000000001ff,
+	}
+	// 2^576 mod m
+	r = Fq{
+		0xfb80000000000000,
+		0x28a2482470b763cd,
+		0x17e2251b23bb31dc,
+		0xca4019ff5b847b2d,
+		0x02d73cbc3e206834,
+		0x0000000000000000,
+		0x0000000000000000,
+		0x0000000000000000,
+		0x0000000000000000,
+	}
+	// 2^1152 mod m
+	r2 = Fq{
+		0x137cd04dcf15dd04,
+		0xf707badce5547ea3,
+		0x12a78d38794573ff,
+		0xd3721ef557f75e06,
+		0xdd6e23d82e49c7db,
+		0xcff3d142b7756e3e,
+		0x5bcc6d61a8e567bc,
+		0x2d8e03d1492d0d45,
+		0x000000000000003d,
+	}
+	// 2^528 * r2 mod m
+	r3 = Fq{
+		0xded9e17426465f07,
+		0x39ee69ef25c7e311,
+		0x126a7308c597a151,
+		0x5d1f7638a7f5b816,
+		0x66f966e917a06d49,
+		0x43fc5f7321cedf0f,
+		0xb1117a1814fdc99c,
+		0x362fb2dd5c97db25,
+		0x0000000000000090,
+	}
+	// m - 2
+	invExp = Fq{
+		0xbb6fb71e91386407,
+		0x3bb5c9b8899c47ae,
+		0x7fcc0148f709a5d0,
+		0x51868783bf2f966b,
+		0xfffffffffffffffa,
+		0xffffffffffffffff,
+		0xffffffffffffffff,
+		0xffffffffffffffff,
+		0x00000000000001ff,
+	}
+
+	biModulus = new(big.Int).SetBytes([]byte{
+		0x01, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfa, 0x51, 0x86, 0x87, 0x83, 0xbf, 0x2f, 0x96, 0x6b, 0x7f, 0xcc, 0x01, 0x48, 0xf7, 0x09, 0xa5, 0xd0, 0x3b, 0xb5, 0xc9, 0xb8, 0x89, 0x9c, 0x47, 0xae, 0xbb, 0x6f, 0xb7, 0x1e, 0x91, 0x38, 0x64, 0x09},
+	)
+)
+
+// inv = -(m^{-1} mod 2^64) mod 2^64
+const inv = 0x1d2f5ccd79a995c7
+
+// P521FqNew returns a new zero element
+func P521FqNew() *Fq {
+	return new(Fq)
+}
+
+// IsZero returns 1 if Fq == 0, 0 otherwise
+func (f *Fq) IsZero() int {
+	t := uint64(0)
+	for i := range f {
+		t |= f[i]
+	}
+	return int(((int64(t) | int64(-t)) >> 63) + 1)
+}
+
+// IsNonZero returns 1 if Fq != 0, 0 otherwise
+func (f *Fq) IsNonZero() int {
+	t := uint64(0)
+	for i := range f {
+		t |= f[i]
+	}
+	return int(-((int64(t) | int64(-t)) >> 63))
+}
+
+// IsOne returns 1 if Fq == 1, 0 otherwise
+func (f *Fq) IsOne() int {
+	return f.Equal(&r)
+}
+
+// Cmp returns -1 if f < rhs
+// 0 if f == rhs
+// 1 if f > rhs
+func (f *Fq) Cmp(rhs *Fq) int {
+	gt := uint64(0)
+	lt := uint64(0)
+	for i := Limbs - 1; i >= 0; i-- {
+		// convert to two 64-bit numbers where
+		// the leading bits are zeros and hold no meaning
+		//  so rhs - f actually means gt
+		// and f - rhs actually means lt.
+		rhsH := rhs[i] >> 32
+		rhsL := rhs[i] & 0xffffffff
+		lhsH := f[i] >> 32
+		lhsL := f[i] & 0xffffffff
+
+		// Check the leading bit
+		// if negative then f > rhs
+		// if positive then f < rhs
+		gt |= (rhsH - lhsH) >> 32 & 1 &^ lt
+		lt |= (lhsH - rhsH) >> 32 & 1 &^ gt
+		gt |= (rhsL - lhsL) >> 32 & 1 &^ lt
+		lt |= (lhsL - rhsL) >> 32 & 1 &^ gt
+	}
+	// Make the result -1 for <, 0 for =, 1 for >
+	return int(gt) - int(lt)
+}
+
+// Equal returns 1 if Fq == rhs, 0 otherwise
+func (f *Fq) Equal(rhs *Fq) int {
+	t := uint64(0)
+	for i := range f {
+		t |= f[i] ^ rhs[i]
+	}
+	return int(((int64(t) | int64(-t)) >> 63) + 1)
+}
+
+// Sgn0 returns the lowest bit value
+func (f *Fq) Sgn0() int {
+	t := new(Fq).fromMontgomery(f)
+	return int(t[0] & 1)
+}
+
+// SetOne Fq = r
+func (f *Fq) SetOne() *Fq {
+	return f.Set(&r)
+}
+
+// SetZero Fq = 0
+func (f *Fq) SetZero() *Fq {
+	for i := range f {
+		f[i] = 0
+	}
+	return f
+}
+
+// SetUint64 Fq = rhs
+func (f *Fq) SetUint64(rhs uint64) *Fq {
+	f.SetZero()
+	f[0] = rhs
+	return f.toMontgomery(f)
+}
+
+// Random generates a random field element
+func (f *Fq) Random(reader io.Reader) (*Fq, error) {
+	var t [WideFieldBytes]byte
+	n, err := reader.Read(t[:])
+	if err != nil {
+		return nil, err
+	}
+	if n != WideFieldBytes {
+		return nil, fmt.Errorf("can only read %d when %d are needed", n, WideFieldBytes)
+	}
+	return f.SetBytesWide(&t), nil
+}
+
+// toMontgomery converts this field to montgomery form
+func (f *Fq) toMontgomery(a *Fq) *Fq {
+	// arg.R^0 * R^2 / R = arg.R
+	return f.Mul(a, &r2)
+}
+
+// fromMontgomery converts this field from montgomery form
+func (f *Fq) fromMontgomery(a *Fq) *Fq {
+	// Mul by 1 is division by R mod m
+	return f.Mul(a, &Fq{1})
+}
+
+// Neg performs modular negation
+func (f *Fq) Neg(a *Fq) *Fq {
+	// Subtract `arg` from `modulus`. Ignore final borrow
+	// since it can't underflow.
+	var t Fq
+	var borrow uint64
+	for i := range t {
+		t[i], borrow = bits.Sub64(modulus[i], a[i], borrow)
+	}
+
+	// t could be `modulus` if `arg`=0. Set mask=0 if self=0
+	// and 0xff..ff if `arg`!=0
+	mask := uint64(-a.IsNonZero())
+	for i := range f {
+		f[i] = t[i] & mask
+	}
+	return f
+}
+
+// Square performs modular square
+func (f *Fq) Square(a *Fq) *Fq {
+	return f.Mul(a, a)
+}
+
+// Double this element
+func (f *Fq) Double(a *Fq) *Fq {
+	return f.Add(a, a)
+}
+
+// Mul performs modular multiplication
+func (f *Fq) Mul(arg1, arg2 *Fq) *Fq {
+	// Coarsely integrated operand scanning, Algorithm 14.36 in
+	// Handbook of Applied Cryptography. The modulus leaves no spare
+	// bits in the top limb so two extra words hold the carries.
+	var t [Limbs + 2]uint64
+	var c, k uint64
+
+	for i := 0; i < Limbs; i++ {
+		c = 0
+		for j := 0; j < Limbs; j++ {
+			t[j], c = mac(t[j], arg1[j], arg2[i], c)
+		}
+		t[Limbs], c = bits.Add64(t[Limbs], c, 0)
+		t[Limbs+1] = c
+
+		k = t[0] * inv
+		_, c = mac(t[0], k, modulus[0], 0)
+		for j := 1; j < Limbs; j++ {
+			t[j-1], c = mac(t[j], k, modulus[j], c)
+		}
+		t[Limbs-1], c = bits.Add64(t[Limbs], c, 0)
+		t[Limbs] = t[Limbs+1] + c
+	}
+
+	var rr Fq
+	copy(rr[:], t[:Limbs])
+	return f.reduce(&rr, t[Limbs])
+}
+
+// Add performs modular addition
+func (f *Fq) Add(arg1, arg2 *Fq) *Fq {
+	var t Fq
+	var carry uint64
+	for i := range t {
+		t[i], carry = bits.Add64(arg1[i], arg2[i], carry)
+	}
+	return f.reduce(&t, carry)
+}
+
+// Sub performs modular subtraction
+func (f *Fq) Sub(arg1, arg2 *Fq) *Fq {
+	var t Fq
+	var borrow, carry uint64
+	for i := range t {
+		t[i], borrow = bits.Sub64(arg1[i], arg2[i], borrow)
+	}
+
+	// If underflow occurred on the final limb, borrow 0xff...ff, otherwise
+	// borrow = 0x00...00. Conditionally mask to add the modulus
+	mask := -borrow
+	for i := range f {
+		f[i], carry = bits.Add64(t[i], modulus[i]&mask, carry)
+	}
+	return f
+}
+
+// reduce subtracts the modulus from the value held in
+// t and the carry word if it is not already smaller
+func (f *Fq) reduce(t *Fq, carry uint64) *Fq {
+	var d Fq
+	var borrow uint64
+	for i := range d {
+		d[i], borrow = bits.Sub64(t[i], modulus[i], borrow)
+	}
+	// Keep t only when the subtraction underflowed
+	// and there was no carry out of the top limb
+	_, borrow = bits.Sub64(carry, 0, borrow)
+	return f.CMove(&d, t, int(borrow))
+}
+
+// 2^tsS * s = m - 1 with s odd
+const tsS = 3
+
+// rootOfUnity = 3^s, a primitive 2^3-th root of unity
+var rootOfUnity = Fq{
+	0xabb188098dced84b,
+	0x855e9aa0e4514691,
+	0xf8ad091b5630adf2,
+	0x348752d031eaefa5,
+	0xd6afc26dde0c84b2,
+	0x9d48ef02e5b1ea1d,
+	0x6da18e4abac1b0ee,
+	0x6004a9df317d5be0,
+	0x0000000000000032,
+}
+
+// tsExp = (s - 1) / 2
+var tsExp = Fq{
+	0xebb6fb71e9138640,
+	0x03bb5c9b8899c47a,
+	0xb7fcc0148f709a5d,
+	0xa51868783bf2f966,
+	0xffffffffffffffff,
+	0xffffffffffffffff,
+	0xffffffffffffffff,
+	0xffffffffffffffff,
+	0x000000000000001f,
+}
+
+// Sqrt performs modular square root
+func (f *Fq) Sqrt(a *Fq) (*Fq, bool) {
+	// Tonelli-Shanks, as m = 1 (mod 2^3). See sqrt_ts_ct at
+	// https://datatracker.ietf.org/doc/html/rfc9380#appendix-I.4
+	var z, t, b, c, tv Fq
+
+	// z = a^((s-1)/2) where 2^3 * s = m - 1
+	z.pow(a, &tsExp)
+	t.Square(&z)
+	t.Mul(&t, a)
+	z.Mul(&z, a)
+	b.Set(&t)
+	c.Set(&rootOfUnity)
+
+	for i := tsS; i >= 2; i-- {
+		for j := 1; j <= i-2; j++ {
+			b.Square(&b)
+		}
+		// if b == 1 flag = 0 else flag = 1
+		flag := b.IsOne() ^ 1
+		tv.Mul(&z, &c)
+		z.CMove(&z, &tv, flag)
+		c.Square(&c)
+		tv.Mul(&t, &c)
+		t.CMove(&t, &tv, flag)
+		b.Set(&t)
+	}
+
+	c.Square(&z)
+	wasSquare := c.Equal(a)
+	f.CMove(f, &z, wasSquare)
+	return f, wasSquare == 1
+}
+
+// Invert performs modular inverse
+func (f *Fq) Invert(a *Fq) (*Fq, bool) {
+	// Exponentiate by m - 2
+	var t Fq
+	t.pow(a, &invExp)
+	wasInverted := a.IsNonZero()
+	f.CMove(a, &t, wasInverted)
+	return f, wasInverted == 1
+}
+
+// SetBytes attempts to convert a little endian byte representation
+// of a scalar into a `Fq`, failing if input is not canonical
+func (f *Fq) SetBytes(input *[FieldBytes]byte) (*Fq, error) {
+	var buffer [Limbs * 8]byte
+	var t Fq
+	copy(buffer[:], input[:])
+	for i := range t {
+		t[i] = binary.LittleEndian.Uint64(buffer[i*8:])
+	}
+	if t.Cmp(&modulus) != -1 {
+		return nil, fmt.Errorf("invalid byte sequence")
+	}
+	return f.toMontgomery(&t), nil
+}
+
+// SetBytesWide takes 132 bytes as input and treats them as a little endian number.
+// The number is decomposed into two 528-bit digits with the higher bits
+// multiplied by 2^528. Thus, we perform two reductions
+//
+// 1. the lower bits are multiplied by r^2, as normal
+// 2. the upper bits are multiplied by r^2 * 2^528 = r3
+//
+// and computing their sum in the field. Both digits are smaller than
+// r = 2^576 so the montgomery reduction of each product is safe.
+func (f *Fq) SetBytesWide(input *[WideFieldBytes]byte) *Fq {
+	var buffer [Limbs * 8]byte
+	var d0, d1 Fq
+	copy(buffer[:], input[:FieldBytes])
+	for i := range d0 {
+		d0[i] = binary.LittleEndian.Uint64(buffer[i*8:])
+	}
+	copy(buffer[:], input[FieldBytes:])
+	for i := range d1 {
+		d1[i] = binary.LittleEndian.Uint64(buffer[i*8:])
+	}
+	// d0*r2 + d1*r3
+	d0.Mul(&d0, &r2)
+	d1.Mul(&d1, &r3)
+	return f.Add(&d0, &d1)
+}
+
+// SetBigInt initializes an element from big.Int
+// The value is reduced by the modulus
+func (f *Fq) SetBigInt(bi *big.Int) *Fq {
+	var buffer [FieldBytes]byte
+	t := new(big.Int).Set(bi)
+	t.Mod(t, biModulus)
+	t.FillBytes(buffer[:])
+	copy(buffer[:], internal.ReverseScalarBytes(buffer[:]))
+	_, _ = f.SetBytes(&buffer)
+	return f
+}
+
+// Set copies a into Fq
+func (f *Fq) Set(a *Fq) *Fq {
+	*f = *a
+	return f
+}
+
+// SetLimbs converts an array into a field element
+// by converting to montgomery form
+func (f *Fq) SetLimbs(a *[Limbs]uint64) *Fq {
+	return f.toMontgomery((*Fq)(a))
+}
+
+// SetRaw converts a raw array into a field element
+// Assumes input is already in montgomery form
+func (f *Fq) SetRaw(a *[Limbs]uint64) *Fq {
+	*f = *a
+	return f
+}
+
+// Bytes converts a field element to a little endian byte array
+func (f *Fq) Bytes() [FieldBytes]byte {
+	var buffer [Limbs * 8]byte
+	var out [FieldBytes]byte
+	t := new(Fq).fromMontgomery(f)
+	for i := range t {
+		binary.LittleEndian.PutUint64(buffer[i*8:], t[i])
+	}
+	copy(out[:], buffer[:FieldBytes])
+	return out
+}
+
+// BigInt converts this element into the big.Int struct
+func (f *Fq) BigInt() *big.Int {
+	buffer := f.Bytes()
+	return new(big.Int).SetBytes(internal.ReverseScalarBytes(buffer[:]))
+}
+
+// Raw converts this element into the a [Limbs]uint64
+func (f *Fq) Raw() [Limbs]uint64 {
+	t := new(Fq).fromMontgomery(f)
+	return *t
+}
+
+// CMove performs conditional select.
+// selects arg1 if choice == 0 and arg2 if choice == 1
+func (f *Fq) CMove(arg1, arg2 *Fq, choice int) *Fq {
+	mask := uint64(-choice)
+	for i := range f {
+		f[i] = arg1[i] ^ ((arg1[i] ^ arg2[i]) & mask)
+	}
+	return f
+}
+
+// CNeg conditionally negates a if choice == 1
+func (f *Fq) CNeg(a *Fq, choice int) *Fq {
+	var t Fq
+	t.Neg(a)
+	return f.CMove(a, &t, choice)
+}
+
+// Exp raises base^exp.
+func (f *Fq) Exp(base, exp *Fq) *Fq {
+	e := new(Fq).fromMontgomery(exp)
+	return f.pow(base, e)
+}
+
+func (f *Fq) pow(base, e *Fq) *Fq {
+	var tmp, res Fq
+	res.SetOne()
+
+	for i := len(e) - 1; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			res.Square(&res)
+			tmp.Mul(&res, base)
+			res.CMove(&res, &tmp, int(e[i]>>j)&1)
+		}
+	}
+	return f.Set(&res)
+}
+
+// mac Multiply and Accumulate - compute a + (b * c) + d, return the result and new carry
+func mac(a, b, c, d uint64) (uint64, uint64) {
+	hi, lo := bits.Mul64(b, c)
+	lo, carry := bits.Add64(lo, a, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, d, 0)
+	hi += carry
+	return lo, hi
+}
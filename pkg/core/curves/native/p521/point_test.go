@@ -0,0 +1,184 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package p521
+
+import (
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/p521/fq"
+)
+
+func bigFromHex(t *testing.T, s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 16)
+	require.True(t, ok)
+	return v
+}
+
+func TestPointGenerator(t *testing.T) {
+	g := P521PointNew().Generator()
+	require.Equal(t, 1, g.IsOnCurve())
+	x, y := g.BigInt()
+	params := elliptic.P521().Params()
+	require.Equal(t, params.Gx, x)
+	require.Equal(t, params.Gy, y)
+
+	id := P521PointNew()
+	require.Equal(t, 1, id.IsIdentity())
+	require.Equal(t, 1, id.IsOnCurve())
+}
+
+func TestPointAddDouble(t *testing.T) {
+	g := P521PointNew().Generator()
+	id := P521PointNew()
+	d := P521PointNew().Double(g)
+	require.Equal(t, 1, d.Equal(P521PointNew().Add(g, g)))
+	require.Equal(t, 1, g.Equal(P521PointNew().Add(g, id)))
+	require.Equal(t, 1, g.Equal(P521PointNew().Add(id, g)))
+	require.Equal(t, 1, P521PointNew().Sub(g, g).IsIdentity())
+	require.Equal(t, 1, P521PointNew().Double(id).IsIdentity())
+
+	x, y := d.BigInt()
+	ex, ey := elliptic.P521().Double(elliptic.P521().Params().Gx, elliptic.P521().Params().Gy)
+	require.Equal(t, ex, x)
+	require.Equal(t, ey, y)
+}
+
+func TestPointMul(t *testing.T) {
+	params := elliptic.P521().Params()
+	for i := 0; i < 10; i++ {
+		s, err := fq.P521FqNew().Random(crand.Reader)
+		require.NoError(t, err)
+		p := P521PointNew().Mul(P521PointNew().Generator(), s)
+		require.Equal(t, 1, p.IsOnCurve())
+		x, y := p.BigInt()
+		k := s.BigInt().Bytes()
+		ex, ey := elliptic.P521().ScalarMult(params.Gx, params.Gy, k)
+		require.Equal(t, ex, x)
+		require.Equal(t, ey, y)
+	}
+
+	// (n - 1) * G = -G
+	s := fq.P521FqNew().SetBigInt(new(big.Int).Sub(params.N, big.NewInt(1)))
+	p := P521PointNew().Mul(P521PointNew().Generator(), s)
+	require.Equal(t, 1, p.Equal(P521PointNew().Neg(P521PointNew().Generator())))
+	require.Equal(t, 1, P521PointNew().Mul(p, fq.P521FqNew()).IsIdentity())
+}
+
+func TestPointHash(t *testing.T) {
+	// Test vectors from RFC 9380 appendix J.3.1
+	dst := []byte("QUUX-V01-CS02-with-P521_XMD:SHA-512_SSWU_RO_")
+	tests := []struct {
+		msg  string
+		x, y string
+	}{
+		{
+			msg: "",
+			x:   "00fd767cebb2452030358d0e9cf907f525f50920c8f607889a6a35680727f64f4d66b161fafeb2654bea0d35086bec0a10b30b14adef3556ed9f7f1bc23cecc9c088",
+			y:   "0169ba78d8d851e930680322596e39c78f4fe31b97e57629ef6460ddd68f8763fd7bd767a4e94a80d3d21a3c2ee98347e024fc73ee1c27166dc3fe5eeef782be411d",
+		},
+		{
+			msg: "abc",
+			x:   "002f89a1677b28054b50d15e1f81ed6669b5a2158211118ebdef8a6efc77f8ccaa528f698214e4340155abc1fa08f8f613ef14a043717503d57e267d57155cf784a4",
+			y:   "010e0be5dc8e753da8ce51091908b72396d3deed14ae166f66d8ebf0a4e7059ead169ea4bead0232e9b700dd380b316e9361cfdba55a08c73545563a80966ecbb86d",
+		},
+		{
+			msg: "abcdef0123456789",
+			x:   "006e200e276a4a81760099677814d7f8794a4a5f3658442de63c18d2244dcc957c645e94cb0754f95fcf103b2aeaf94411847c24187b89fb7462ad3679066337cbc4",
+			y:   "001dd8dfa9775b60b1614f6f169089d8140d4b3e4012949b52f98db2deff3e1d97bf73a1fa4d437d1dcdf39b6360cc518d8ebcc0f899018206fded7617b654f6b168",
+		},
+	}
+	for _, test := range tests {
+		p := P521PointNew().Hash(native.EllipticPointHasherSha512(), []byte(test.msg), dst)
+		require.Equal(t, 1, p.IsOnCurve())
+		x, y := p.BigInt()
+		require.Equal(t, bigFromHex(t, test.x), x, test.msg)
+		require.Equal(t, bigFromHex(t, test.y), y, test.msg)
+	}
+}
+
+func TestPointSerialization(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		p, err := P521PointNew().Random(crand.Reader)
+		require.NoError(t, err)
+
+		c := p.ToCompressed()
+		q, err := P521PointNew().FromCompressed(&c)
+		require.NoError(t, err)
+		require.Equal(t, 1, p.Equal(q))
+
+		u := p.ToUncompressed()
+		require.Equal(t, byte(4), u[0])
+		q, err = P521PointNew().FromUncompressed(&u)
+		require.NoError(t, err)
+		require.Equal(t, 1, p.Equal(q))
+
+		x, y := p.BigInt()
+		require.Equal(t, elliptic.Marshal(elliptic.P521(), x, y), u[:])
+		require.Equal(t, elliptic.MarshalCompressed(elliptic.P521(), x, y), c[:])
+	}
+
+	id := P521PointNew()
+	c := id.ToCompressed()
+	require.Equal(t, [CompressedBytes]byte{}, c)
+	q, err := P521PointNew().Generator().FromCompressed(&c)
+	require.NoError(t, err)
+	require.Equal(t, 1, q.IsIdentity())
+	u := id.ToUncompressed()
+	require.Equal(t, [UncompressedBytes]byte{}, u)
+	q, err = P521PointNew().Generator().FromUncompressed(&u)
+	require.NoError(t, err)
+	require.Equal(t, 1, q.IsIdentity())
+
+	// Not on the curve
+	u = P521PointNew().Generator().ToUncompressed()
+	u[UncompressedBytes-1] ^= 1
+	_, err = P521PointNew().FromUncompressed(&u)
+	require.Error(t, err)
+	c[0] = 5
+	_, err = P521PointNew().FromCompressed(&c)
+	require.Error(t, err)
+}
+
+func TestPointSetBigInt(t *testing.T) {
+	params := elliptic.P521().Params()
+	p, err := P521PointNew().SetBigInt(params.Gx, params.Gy)
+	require.NoError(t, err)
+	require.Equal(t, 1, p.Equal(P521PointNew().Generator()))
+
+	p, err = P521PointNew().SetBigInt(big.NewInt(0), big.NewInt(0))
+	require.NoError(t, err)
+	require.Equal(t, 1, p.IsIdentity())
+
+	_, err = P521PointNew().SetBigInt(params.Gx, new(big.Int).Add(params.Gy, big.NewInt(1)))
+	require.Error(t, err)
+}
+
+func TestPointSumOfProducts(t *testing.T) {
+	points := make([]*Point, 5)
+	scalars := make([]*fq.Fq, 5)
+	expected := P521PointNew()
+	for i := range points {
+		var err error
+		points[i], err = P521PointNew().Random(crand.Reader)
+		require.NoError(t, err)
+		scalars[i], err = fq.P521FqNew().Random(crand.Reader)
+		require.NoError(t, err)
+		expected.Add(expected, P521PointNew().Mul(points[i], scalars[i]))
+	}
+	actual, err := P521PointNew().SumOfProducts(points, scalars)
+	require.NoError(t, err)
+	require.Equal(t, 1, expected.Equal(actual))
+
+	_, err = P521PointNew().SumOfProducts(points, scalars[1:])
+	require.Error(t, err)
+}
@@ -37,6 +37,7 @@ const (
 	BLAKE2B
 	SHAKE128
 	SHAKE256
+	SHA384
 )
 
 // EllipticPoint represents a Weierstrauss elliptic curve point
@@ -98,6 +99,15 @@ func EllipticPointHasherSha256() *EllipticPointHasher {
 	}
 }
 
+// EllipticPointHasherSha384 creates a point hasher that uses Sha384
+func EllipticPointHasherSha384() *EllipticPointHasher {
+	return &EllipticPointHasher{
+		name:     SHA384,
+		hashType: XMD,
+		xmd:      sha512.New384(),
+	}
+}
+
 // EllipticPointHasherSha512 creates a point hasher that uses Sha512
 func EllipticPointHasherSha512() *EllipticPointHasher {
 	return &EllipticPointHasher{
@@ -208,6 +218,8 @@ func (n EllipticPointHashName) String() string {
 		return "SHAKE-128"
 	case SHAKE256:
 		return "SHAKE-256"
+	case SHA384:
+		return "SHA-384"
 	}
 	return "unknown"
 }
@@ -0,0 +1,137 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package fp
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+)
+
+var sm2FpInitonce sync.Once
+var sm2FpParams native.FieldParams
+
+func SM2FpNew() *native.Field {
+	return &native.Field{
+		Value:      [native.FieldLimbs]uint64{},
+		Params:     getSM2FpParams(),
+		Arithmetic: sm2FpArithmetic{},
+	}
+}
+
+func sm2FpParamsInit() {
+	// See GB/T 32918.5-2017, section 4
+	sm2FpParams = native.FieldParams{
+		R:       [native.FieldLimbs]uint64{0x0000000000000001, 0x00000000ffffffff, 0x0000000000000000, 0x0000000100000000},
+		R2:      [native.FieldLimbs]uint64{0x0000000200000003, 0x00000002ffffffff, 0x0000000100000001, 0x0000000400000002},
+		R3:      [native.FieldLimbs]uint64{0x0000001200000016, 0x0000000efffffff8, 0x0000000a0000000c, 0x0000001b00000009},
+		Modulus: [native.FieldLimbs]uint64{0xffffffffffffffff, 0xffffffff00000000, 0xffffffffffffffff, 0xfffffffeffffffff},
+		BiModulus: new(big.Int).SetBytes([]byte{
+			0xff, 0xff, 0xff, 0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}),
+	}
+}
+
+func getSM2FpParams() *native.FieldParams {
+	sm2FpInitonce.Do(sm2FpParamsInit)
+	return &sm2FpParams
+}
+
+// sm2FpArithmetic is a struct with all the methods needed for working
+// in mod p
+type sm2FpArithmetic struct{}
+
+// ToMontgomery converts this field to montgomery form
+func (f sm2FpArithmetic) ToMontgomery(out, arg *[native.FieldLimbs]uint64) {
+	ToMontgomery((*MontgomeryDomainFieldElement)(out), (*NonMontgomeryDomainFieldElement)(arg))
+}
+
+// FromMontgomery converts this field from montgomery form
+func (f sm2FpArithmetic) FromMontgomery(out, arg *[native.FieldLimbs]uint64) {
+	FromMontgomery((*NonMontgomeryDomainFieldElement)(out), (*MontgomeryDomainFieldElement)(arg))
+}
+
+// Neg performs modular negation
+func (f sm2FpArithmetic) Neg(out, arg *[native.FieldLimbs]uint64) {
+	Opp((*MontgomeryDomainFieldElement)(out), (*MontgomeryDomainFieldElement)(arg))
+}
+
+// Square performs modular square
+func (f sm2FpArithmetic) Square(out, arg *[native.FieldLimbs]uint64) {
+	Square((*MontgomeryDomainFieldElement)(out), (*MontgomeryDomainFieldElement)(arg))
+}
+
+// Mul performs modular multiplication
+func (f sm2FpArithmetic) Mul(out, arg1, arg2 *[native.FieldLimbs]uint64) {
+	Mul((*MontgomeryDomainFieldElement)(out), (*MontgomeryDomainFieldElement)(arg1), (*MontgomeryDomainFieldElement)(arg2))
+}
+
+// Add performs modular addition
+func (f sm2FpArithmetic) Add(out, arg1, arg2 *[native.FieldLimbs]uint64) {
+	Add((*MontgomeryDomainFieldElement)(out), (*MontgomeryDomainFieldElement)(arg1), (*MontgomeryDomainFieldElement)(arg2))
+}
+
+// Sub performs modular subtraction
+func (f sm2FpArithmetic) Sub(out, arg1, arg2 *[native.FieldLimbs]uint64) {
+	Sub((*MontgomeryDomainFieldElement)(out), (*MontgomeryDomainFieldElement)(arg1), (*MontgomeryDomainFieldElement)(arg2))
+}
+
+// Sqrt performs modular square root
+func (f sm2FpArithmetic) Sqrt(wasSquare *int, out, arg *[native.FieldLimbs]uint64) {
+	// Use p = 3 mod 4 by Euler's criterion means
+	// arg^((p+1)/4 mod p
+	var t, c [native.FieldLimbs]uint64
+	c1 := [native.FieldLimbs]uint64{
+		0x4000_0000_0000_0000,
+		0xffff_ffff_c000_0000,
+		0xffff_ffff_ffff_ffff,
+		0x3fff_ffff_bfff_ffff,
+	}
+	native.Pow(&t, arg, &c1, getSM2FpParams(), f)
+	Square((*MontgomeryDomainFieldElement)(&c), (*MontgomeryDomainFieldElement)(&t))
+	*wasSquare = (&native.Field{Value: c, Params: getSM2FpParams(), Arithmetic: f}).Equal(&native.Field{
+		Value: *arg, Params: getSM2FpParams(), Arithmetic: f,
+	})
+	Selectznz(out, uint1(*wasSquare), out, &t)
+}
+
+// Invert performs modular inverse
+func (f sm2FpArithmetic) Invert(wasInverted *int, out, arg *[native.FieldLimbs]uint64) {
+	// Fermat's Little Theorem
+	// a ^ (p - 2) mod p
+	var r [native.FieldLimbs]uint64
+	pMinus2 := [native.FieldLimbs]uint64{
+		0xffff_ffff_ffff_fffd,
+		0xffff_ffff_0000_0000,
+		0xffff_ffff_ffff_ffff,
+		0xffff_fffe_ffff_ffff,
+	}
+	native.Pow(&r, arg, &pMinus2, getSM2FpParams(), f)
+
+	*wasInverted = (&native.Field{
+		Value:      *arg,
+		Params:     getSM2FpParams(),
+		Arithmetic: f,
+	}).IsNonZero()
+	Selectznz(out, uint1(*wasInverted), out, &r)
+}
+
+// FromBytes converts a little endian byte array into a field element
+func (f sm2FpArithmetic) FromBytes(out *[native.FieldLimbs]uint64, arg *[native.FieldBytes]byte) {
+	FromBytes(out, arg)
+}
+
+// ToBytes converts a field element to a little endian byte array
+func (f sm2FpArithmetic) ToBytes(out *[native.FieldBytes]byte, arg *[native.FieldLimbs]uint64) {
+	ToBytes(out, arg)
+}
+
+// Selectznz performs conditional select.
+// selects arg1 if choice == 0 and arg2 if choice == 1
+func (f sm2FpArithmetic) Selectznz(out, arg1, arg2 *[native.FieldLimbs]uint64, choice int) {
+	Selectznz(out, uint1(choice), arg1, arg2)
+}
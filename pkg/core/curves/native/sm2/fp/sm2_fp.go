@@ -0,0 +1,298 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Word-by-word Montgomery arithmetic for the SM2 base field.
+//
+// This file is written by hand, not generated by fiat-crypto. It only follows
+// the function names and element types of the fiat-crypto files of the p256
+// and k256 packages, so that the field glue in fp.go has the same shape.
+// Every routine is branch free and uses math/bits for carries.
+//
+// m = 0xfffffffeffffffffffffffffffffffffffffffff00000000ffffffffffffffff (from "2^256 - 2^224 - 2^96 + 2^64 - 1")
+//
+// Computed values:
+//
+//	eval z = z[0] + (z[1] << 64) + (z[2] << 128) + (z[3] << 192)
+//
+//	bytes_eval z = z[0] + (z[1] << 8) + ... + (z[31] << 248)
+package fp
+
+import "math/bits"
+
+type uint1 uint64
+
+// MontgomeryDomainFieldElement is a field element in the Montgomery domain.
+type MontgomeryDomainFieldElement [4]uint64
+
+// NonMontgomeryDomainFieldElement is a field element NOT in the Montgomery domain.
+type NonMontgomeryDomainFieldElement [4]uint64
+
+// modulus is the SM2 prime in little endian limbs
+var modulus = [4]uint64{0xffffffffffffffff, 0xffffffff00000000, 0xffffffffffffffff, 0xfffffffeffffffff}
+
+// m0Inv is -m^{-1} mod 2^64
+const m0Inv uint64 = 0x0000000000000001
+
+// r2 is 2^512 mod m
+var r2 = [4]uint64{0x0000000200000003, 0x00000002ffffffff, 0x0000000100000001, 0x0000000400000002}
+
+// cmovznzU64 is a single-word conditional move.
+//
+// Postconditions:
+//
+//	out1 = (if arg1 = 0 then arg2 else arg3)
+func cmovznzU64(out1 *uint64, arg1 uint1, arg2 uint64, arg3 uint64) {
+	x1 := (uint64(arg1) * 0xffffffffffffffff)
+	*out1 = (x1 & arg3) | ((^x1) & arg2)
+}
+
+// reduce conditionally subtracts the modulus from the 5 limb value (t, hi)
+// which must be less than 2m.
+func reduce(out1 *[4]uint64, t *[4]uint64, hi uint64) {
+	var r [4]uint64
+	var borrow uint64
+	r[0], borrow = bits.Sub64(t[0], modulus[0], 0)
+	r[1], borrow = bits.Sub64(t[1], modulus[1], borrow)
+	r[2], borrow = bits.Sub64(t[2], modulus[2], borrow)
+	r[3], borrow = bits.Sub64(t[3], modulus[3], borrow)
+	_, borrow = bits.Sub64(hi, 0, borrow)
+	// borrow == 1 means t < m so t is already reduced
+	cmovznzU64(&out1[0], uint1(borrow), r[0], t[0])
+	cmovznzU64(&out1[1], uint1(borrow), r[1], t[1])
+	cmovznzU64(&out1[2], uint1(borrow), r[2], t[2])
+	cmovznzU64(&out1[3], uint1(borrow), r[3], t[3])
+}
+
+// montMul computes arg1 * arg2 * 2^-256 mod m using the CIOS method.
+func montMul(out1 *[4]uint64, arg1 *[4]uint64, arg2 *[4]uint64) {
+	var t [4]uint64
+	var t4, t5 uint64
+	for i := 0; i < 4; i++ {
+		var c, hi, lo, carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo = bits.Mul64(arg1[j], arg2[i])
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			lo, carry = bits.Add64(lo, c, 0)
+			hi += carry
+			t[j] = lo
+			c = hi
+		}
+		t4, carry = bits.Add64(t4, c, 0)
+		t5 = carry
+
+		m := t[0] * m0Inv
+		hi, lo = bits.Mul64(m, modulus[0])
+		_, carry = bits.Add64(lo, t[0], 0)
+		c = hi + carry
+		for j := 1; j < 4; j++ {
+			hi, lo = bits.Mul64(m, modulus[j])
+			lo, carry = bits.Add64(lo, t[j], 0)
+			hi += carry
+			lo, carry = bits.Add64(lo, c, 0)
+			hi += carry
+			t[j-1] = lo
+			c = hi
+		}
+		t[3], carry = bits.Add64(t4, c, 0)
+		t4 = t5 + carry
+	}
+	reduce(out1, &t, t4)
+}
+
+// Mul multiplies two field elements in the Montgomery domain.
+//
+// Preconditions:
+//
+//	0 ≤ eval arg1 < m
+//	0 ≤ eval arg2 < m
+//
+// Postconditions:
+//
+//	eval (from_montgomery out1) mod m = (eval (from_montgomery arg1) * eval (from_montgomery arg2)) mod m
+//	0 ≤ eval out1 < m
+func Mul(out1 *MontgomeryDomainFieldElement, arg1 *MontgomeryDomainFieldElement, arg2 *MontgomeryDomainFieldElement) {
+	montMul((*[4]uint64)(out1), (*[4]uint64)(arg1), (*[4]uint64)(arg2))
+}
+
+// Square squares a field element in the Montgomery domain.
+//
+// Preconditions:
+//
+//	0 ≤ eval arg1 < m
+//
+// Postconditions:
+//
+//	eval (from_montgomery out1) mod m = (eval (from_montgomery arg1) * eval (from_montgomery arg1)) mod m
+//	0 ≤ eval out1 < m
+func Square(out1 *MontgomeryDomainFieldElement, arg1 *MontgomeryDomainFieldElement) {
+	montMul((*[4]uint64)(out1), (*[4]uint64)(arg1), (*[4]uint64)(arg1))
+}
+
+// Add adds two field elements in the Montgomery domain.
+//
+// Preconditions:
+//
+//	0 ≤ eval arg1 < m
+//	0 ≤ eval arg2 < m
+//
+// Postconditions:
+//
+//	eval (from_montgomery out1) mod m = (eval (from_montgomery arg1) + eval (from_montgomery arg2)) mod m
+//	0 ≤ eval out1 < m
+func Add(out1 *MontgomeryDomainFieldElement, arg1 *MontgomeryDomainFieldElement, arg2 *MontgomeryDomainFieldElement) {
+	var t [4]uint64
+	var carry uint64
+	t[0], carry = bits.Add64(arg1[0], arg2[0], 0)
+	t[1], carry = bits.Add64(arg1[1], arg2[1], carry)
+	t[2], carry = bits.Add64(arg1[2], arg2[2], carry)
+	t[3], carry = bits.Add64(arg1[3], arg2[3], carry)
+	reduce((*[4]uint64)(out1), &t, carry)
+}
+
+// Sub subtracts two field elements in the Montgomery domain.
+//
+// Preconditions:
+//
+//	0 ≤ eval arg1 < m
+//	0 ≤ eval arg2 < m
+//
+// Postconditions:
+//
+//	eval (from_montgomery out1) mod m = (eval (from_montgomery arg1) - eval (from_montgomery arg2)) mod m
+//	0 ≤ eval out1 < m
+func Sub(out1 *MontgomeryDomainFieldElement, arg1 *MontgomeryDomainFieldElement, arg2 *MontgomeryDomainFieldElement) {
+	var t [4]uint64
+	var borrow, carry uint64
+	t[0], borrow = bits.Sub64(arg1[0], arg2[0], 0)
+	t[1], borrow = bits.Sub64(arg1[1], arg2[1], borrow)
+	t[2], borrow = bits.Sub64(arg1[2], arg2[2], borrow)
+	t[3], borrow = bits.Sub64(arg1[3], arg2[3], borrow)
+	mask := -borrow
+	out1[0], carry = bits.Add64(t[0], modulus[0]&mask, 0)
+	out1[1], carry = bits.Add64(t[1], modulus[1]&mask, carry)
+	out1[2], carry = bits.Add64(t[2], modulus[2]&mask, carry)
+	out1[3], _ = bits.Add64(t[3], modulus[3]&mask, carry)
+}
+
+// Opp negates a field element in the Montgomery domain.
+//
+// Preconditions:
+//
+//	0 ≤ eval arg1 < m
+//
+// Postconditions:
+//
+//	eval (from_montgomery out1) mod m = -eval (from_montgomery arg1) mod m
+//	0 ≤ eval out1 < m
+func Opp(out1 *MontgomeryDomainFieldElement, arg1 *MontgomeryDomainFieldElement) {
+	var zero MontgomeryDomainFieldElement
+	Sub(out1, &zero, arg1)
+}
+
+// FromMontgomery translates a field element out of the Montgomery domain.
+//
+// Preconditions:
+//
+//	0 ≤ eval arg1 < m
+//
+// Postconditions:
+//
+//	eval out1 mod m = (eval arg1 * ((2^64)⁻¹ mod m)^4) mod m
+//	0 ≤ eval out1 < m
+func FromMontgomery(out1 *NonMontgomeryDomainFieldElement, arg1 *MontgomeryDomainFieldElement) {
+	one := [4]uint64{1, 0, 0, 0}
+	montMul((*[4]uint64)(out1), (*[4]uint64)(arg1), &one)
+}
+
+// ToMontgomery translates a field element into the Montgomery domain.
+//
+// Preconditions:
+//
+//	0 ≤ eval arg1 < m
+//
+// Postconditions:
+//
+//	eval (from_montgomery out1) mod m = eval arg1 mod m
+//	0 ≤ eval out1 < m
+func ToMontgomery(out1 *MontgomeryDomainFieldElement, arg1 *NonMontgomeryDomainFieldElement) {
+	montMul((*[4]uint64)(out1), (*[4]uint64)(arg1), &r2)
+}
+
+// Nonzero outputs a single non-zero word if the input is non-zero and zero otherwise.
+//
+// Preconditions:
+//
+//	0 ≤ eval arg1 < m
+//
+// Postconditions:
+//
+//	out1 = 0 ↔ eval (from_montgomery arg1) mod m = 0
+func Nonzero(out1 *uint64, arg1 *[4]uint64) {
+	*out1 = arg1[0] | arg1[1] | arg1[2] | arg1[3]
+}
+
+// Selectznz is a multi-limb conditional select.
+//
+// Postconditions:
+//
+//	out1 = (if arg1 = 0 then arg2 else arg3)
+func Selectznz(out1 *[4]uint64, arg1 uint1, arg2 *[4]uint64, arg3 *[4]uint64) {
+	cmovznzU64(&out1[0], arg1, arg2[0], arg3[0])
+	cmovznzU64(&out1[1], arg1, arg2[1], arg3[1])
+	cmovznzU64(&out1[2], arg1, arg2[2], arg3[2])
+	cmovznzU64(&out1[3], arg1, arg2[3], arg3[3])
+}
+
+// ToBytes serializes a field element NOT in the Montgomery domain to bytes in little-endian order.
+//
+// Preconditions:
+//
+//	0 ≤ eval arg1 < m
+//
+// Postconditions:
+//
+//	out1 = map (λ x, ⌊((eval arg1 mod m) mod 2^(8 * (x + 1))) / 2^(8 * x)⌋) [0..31]
+func ToBytes(out1 *[32]uint8, arg1 *[4]uint64) {
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 8; j++ {
+			out1[8*i+j] = uint8(arg1[i] >> (8 * j))
+		}
+	}
+}
+
+// FromBytes deserializes a field element NOT in the Montgomery domain from bytes in little-endian order.
+//
+// Preconditions:
+//
+//	0 ≤ bytes_eval arg1 < m
+//
+// Postconditions:
+//
+//	eval out1 mod m = bytes_eval arg1 mod m
+//	0 ≤ eval out1 < m
+func FromBytes(out1 *[4]uint64, arg1 *[32]uint8) {
+	for i := 0; i < 4; i++ {
+		var w uint64
+		for j := 7; j >= 0; j-- {
+			w = (w << 8) | uint64(arg1[8*i+j])
+		}
+		out1[i] = w
+	}
+}
+
+// SetOne returns the field element one in the Montgomery domain.
+//
+// Postconditions:
+//
+//	eval (from_montgomery out1) mod m = 1 mod m
+//	0 ≤ eval out1 < m
+func SetOne(out1 *MontgomeryDomainFieldElement) {
+	out1[0] = 0x0000000000000001
+	out1[1] = 0x00000000ffffffff
+	out1[2] = 0x0000000000000000
+	out1[3] = 0x0000000100000000
+}
@@ -0,0 +1,137 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package fq
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+)
+
+var sm2FqInitonce sync.Once
+var sm2FqParams native.FieldParams
+
+func SM2FqNew() *native.Field {
+	return &native.Field{
+		Value:      [native.FieldLimbs]uint64{},
+		Params:     getSM2FqParams(),
+		Arithmetic: sm2FqArithmetic{},
+	}
+}
+
+func sm2FqParamsInit() {
+	// See GB/T 32918.5-2017, section 4 (the order n of G)
+	sm2FqParams = native.FieldParams{
+		R:       [native.FieldLimbs]uint64{0xac440bf6c62abedd, 0x8dfc2094de39fad4, 0x0000000000000000, 0x0000000100000000},
+		R2:      [native.FieldLimbs]uint64{0x901192af7c114f20, 0x3464504ade6fa2fa, 0x620fc84c3affe0d4, 0x1eb5e412a22b3d3b},
+		R3:      [native.FieldLimbs]uint64{0x6ff874c70eaa0b85, 0x87d0c315aabe8d32, 0x4c4fbbb397185afc, 0xc813249cd574ea14},
+		Modulus: [native.FieldLimbs]uint64{0x53bbf40939d54123, 0x7203df6b21c6052b, 0xffffffffffffffff, 0xfffffffeffffffff},
+		BiModulus: new(big.Int).SetBytes([]byte{
+			0xff, 0xff, 0xff, 0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x72, 0x03, 0xdf, 0x6b, 0x21, 0xc6, 0x05, 0x2b, 0x53, 0xbb, 0xf4, 0x09, 0x39, 0xd5, 0x41, 0x23}),
+	}
+}
+
+func getSM2FqParams() *native.FieldParams {
+	sm2FqInitonce.Do(sm2FqParamsInit)
+	return &sm2FqParams
+}
+
+// sm2FqArithmetic is a struct with all the methods needed for working
+// in mod q
+type sm2FqArithmetic struct{}
+
+// ToMontgomery converts this field to montgomery form
+func (f sm2FqArithmetic) ToMontgomery(out, arg *[native.FieldLimbs]uint64) {
+	ToMontgomery((*MontgomeryDomainFieldElement)(out), (*NonMontgomeryDomainFieldElement)(arg))
+}
+
+// FromMontgomery converts this field from montgomery form
+func (f sm2FqArithmetic) FromMontgomery(out, arg *[native.FieldLimbs]uint64) {
+	FromMontgomery((*NonMontgomeryDomainFieldElement)(out), (*MontgomeryDomainFieldElement)(arg))
+}
+
+// Neg performs modular negation
+func (f sm2FqArithmetic) Neg(out, arg *[native.FieldLimbs]uint64) {
+	Opp((*MontgomeryDomainFieldElement)(out), (*MontgomeryDomainFieldElement)(arg))
+}
+
+// Square performs modular square
+func (f sm2FqArithmetic) Square(out, arg *[native.FieldLimbs]uint64) {
+	Square((*MontgomeryDomainFieldElement)(out), (*MontgomeryDomainFieldElement)(arg))
+}
+
+// Mul performs modular multiplication
+func (f sm2FqArithmetic) Mul(out, arg1, arg2 *[native.FieldLimbs]uint64) {
+	Mul((*MontgomeryDomainFieldElement)(out), (*MontgomeryDomainFieldElement)(arg1), (*MontgomeryDomainFieldElement)(arg2))
+}
+
+// Add performs modular addition
+func (f sm2FqArithmetic) Add(out, arg1, arg2 *[native.FieldLimbs]uint64) {
+	Add((*MontgomeryDomainFieldElement)(out), (*MontgomeryDomainFieldElement)(arg1), (*MontgomeryDomainFieldElement)(arg2))
+}
+
+// Sub performs modular subtraction
+func (f sm2FqArithmetic) Sub(out, arg1, arg2 *[native.FieldLimbs]uint64) {
+	Sub((*MontgomeryDomainFieldElement)(out), (*MontgomeryDomainFieldElement)(arg1), (*MontgomeryDomainFieldElement)(arg2))
+}
+
+// Sqrt performs modular square root
+func (f sm2FqArithmetic) Sqrt(wasSquare *int, out, arg *[native.FieldLimbs]uint64) {
+	// Use q = 3 mod 4 by Euler's criterion means
+	// arg^((q+1)/4 mod q
+	var t, c [native.FieldLimbs]uint64
+	c1 := [native.FieldLimbs]uint64{
+		0xd4ee_fd02_4e75_5049,
+		0xdc80_f7da_c871_814a,
+		0xffff_ffff_ffff_ffff,
+		0x3fff_ffff_bfff_ffff,
+	}
+	native.Pow(&t, arg, &c1, getSM2FqParams(), f)
+	Square((*MontgomeryDomainFieldElement)(&c), (*MontgomeryDomainFieldElement)(&t))
+	*wasSquare = (&native.Field{Value: c, Params: getSM2FqParams(), Arithmetic: f}).Equal(&native.Field{
+		Value: *arg, Params: getSM2FqParams(), Arithmetic: f,
+	})
+	Selectznz(out, uint1(*wasSquare), out, &t)
+}
+
+// Invert performs modular inverse
+func (f sm2FqArithmetic) Invert(wasInverted *int, out, arg *[native.FieldLimbs]uint64) {
+	// Fermat's Little Theorem
+	// a ^ (q - 2) mod q
+	var r [native.FieldLimbs]uint64
+	qMinus2 := [native.FieldLimbs]uint64{
+		0x53bb_f409_39d5_4121,
+		0x7203_df6b_21c6_052b,
+		0xffff_ffff_ffff_ffff,
+		0xffff_fffe_ffff_ffff,
+	}
+	native.Pow(&r, arg, &qMinus2, getSM2FqParams(), f)
+
+	*wasInverted = (&native.Field{
+		Value:      *arg,
+		Params:     getSM2FqParams(),
+		Arithmetic: f,
+	}).IsNonZero()
+	Selectznz(out, uint1(*wasInverted), out, &r)
+}
+
+// FromBytes converts a little endian byte array into a field element
+func (f sm2FqArithmetic) FromBytes(out *[native.FieldLimbs]uint64, arg *[native.FieldBytes]byte) {
+	FromBytes(out, arg)
+}
+
+// ToBytes converts a field element to a little endian byte array
+func (f sm2FqArithmetic) ToBytes(out *[native.FieldBytes]byte, arg *[native.FieldLimbs]uint64) {
+	ToBytes(out, arg)
+}
+
+// Selectznz performs conditional select.
+// selects arg1 if choice == 0 and arg2 if choice == 1
+func (f sm2FqArithmetic) Selectznz(out, arg1, arg2 *[native.FieldLimbs]uint64, choice int) {
+	Selectznz(out, uint1(choice), arg1, arg2)
+}
@@ -0,0 +1,316 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package sm2
+
+import (
+	"sync"
+
+	"github.com/coinbase/kryptology/internal"
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/sm2/fp"
+)
+
+var (
+	sm2PointInitonce     sync.Once
+	sm2PointParams       native.EllipticPointParams
+	sm2PointSswuInitOnce sync.Once
+	sm2PointSswuParams   native.SswuParams
+)
+
+func SM2PointNew() *native.EllipticPoint {
+	return &native.EllipticPoint{
+		X:          fp.SM2FpNew(),
+		Y:          fp.SM2FpNew(),
+		Z:          fp.SM2FpNew(),
+		Params:     getSM2PointParams(),
+		Arithmetic: &sm2PointArithmetic{},
+	}
+}
+
+func sm2PointParamsInit() {
+	// How these values were derived
+	// left for informational purposes
+	//p := bhex("fffffffeffffffffffffffffffffffffffffffff00000000ffffffffffffffff")
+	//a := big.NewInt(-3)
+	//a.Mod(a, p)
+	//capA := fp.SM2FpNew().SetBigInt(a)
+	//capB := fp.SM2FpNew().SetBigInt(bhex("28e9fa9e9d9f5e344d5a9e4bcf6509a7f39789f515ab8f92ddbcbd414d940e93"))
+	//gx := fp.SM2FpNew().SetBigInt(bhex("32c4ae2c1f1981195f9904466a39c9948fe30bbff2660be1715a4589334c74c7"))
+	//gy := fp.SM2FpNew().SetBigInt(bhex("bc3736a2f4f6779c59bdcee36b692153d0a9877cc62a474002df32e52139f0a0"))
+
+	sm2PointParams = native.EllipticPointParams{
+		A:       fp.SM2FpNew().SetRaw(&[native.FieldLimbs]uint64{0xfffffffffffffffc, 0xfffffffc00000003, 0xffffffffffffffff, 0xfffffffbffffffff}),
+		B:       fp.SM2FpNew().SetRaw(&[native.FieldLimbs]uint64{0x90d230632bc0dd42, 0x71cf379ae9b537ab, 0x527981505ea51c3c, 0x240fe188ba20e2c8}),
+		Gx:      fp.SM2FpNew().SetRaw(&[native.FieldLimbs]uint64{0x61328990f418029e, 0x3e7981eddca6c050, 0xd6a1ed99ac24c3c3, 0x91167a5ee1c13b05}),
+		Gy:      fp.SM2FpNew().SetRaw(&[native.FieldLimbs]uint64{0xc1354e593c2d0ddd, 0xc1f5e5788d3295fa, 0x8d4cfb066e2a48f8, 0x63cd65d481d735bd}),
+		BitSize: 256,
+		Name:    "SM2",
+	}
+}
+
+func getSM2PointParams() *native.EllipticPointParams {
+	sm2PointInitonce.Do(sm2PointParamsInit)
+	return &sm2PointParams
+}
+
+func getSM2PointSswuParams() *native.SswuParams {
+	sm2PointSswuInitOnce.Do(sm2PointSswuParamsInit)
+	return &sm2PointSswuParams
+}
+
+func sm2PointSswuParamsInit() {
+	// How these values were derived
+	// left for informational purposes
+	//p := bhex("fffffffeffffffffffffffffffffffffffffffff00000000ffffffffffffffff")
+	//
+	//// c1 = (q - 3) / 4
+	//c1 := new(big.Int).Set(p)
+	//c1.Sub(c1, big.NewInt(3))
+	//c1.Rsh(c1, 2)
+	//
+	//a := big.NewInt(-3)
+	//a.Mod(a, p)
+	//b := bhex("28e9fa9e9d9f5e344d5a9e4bcf6509a7f39789f515ab8f92ddbcbd414d940e93")
+	//// z is the output of find_z_sswu from RFC 9380 appendix H.2
+	//z := big.NewInt(-9)
+	//z.Mod(z, p)
+	//// sqrt(-Z^3)
+	//zTmp := new(big.Int).Exp(z, big.NewInt(3), nil)
+	//zTmp = zTmp.Neg(zTmp)
+	//zTmp.Mod(zTmp, p)
+	//c2 := new(big.Int).ModSqrt(zTmp, p)
+	//
+	//capC2 := fp.SM2FpNew().SetBigInt(c2)
+	//capA := fp.SM2FpNew().SetBigInt(a)
+	//capB := fp.SM2FpNew().SetBigInt(b)
+	//capZ := fp.SM2FpNew().SetBigInt(z)
+
+	sm2PointSswuParams = native.SswuParams{
+		C1: [native.FieldLimbs]uint64{0x3fffffffffffffff, 0xffffffffc0000000, 0xffffffffffffffff, 0x3fffffffbfffffff},
+		C2: [native.FieldLimbs]uint64{0x000000000000001b, 0x0000001affffffe5, 0x0000000000000000, 0x0000001b00000000},
+		A:  [native.FieldLimbs]uint64{0xfffffffffffffffc, 0xfffffffc00000003, 0xffffffffffffffff, 0xfffffffbffffffff},
+		B:  [native.FieldLimbs]uint64{0x90d230632bc0dd42, 0x71cf379ae9b537ab, 0x527981505ea51c3c, 0x240fe188ba20e2c8},
+		Z:  [native.FieldLimbs]uint64{0xfffffffffffffff6, 0xfffffff600000009, 0xffffffffffffffff, 0xfffffff5ffffffff},
+	}
+}
+
+type sm2PointArithmetic struct{}
+
+func (k sm2PointArithmetic) Hash(out *native.EllipticPoint, hash *native.EllipticPointHasher, msg, dst []byte) error {
+	var u []byte
+	sswuParams := getSM2PointSswuParams()
+
+	switch hash.Type() {
+	case native.XMD:
+		u = native.ExpandMsgXmd(hash, msg, dst, 96)
+	case native.XOF:
+		u = native.ExpandMsgXof(hash, msg, dst, 96)
+	}
+	var buf [64]byte
+	copy(buf[:48], internal.ReverseScalarBytes(u[:48]))
+	u0 := fp.SM2FpNew().SetBytesWide(&buf)
+	copy(buf[:48], internal.ReverseScalarBytes(u[48:]))
+	u1 := fp.SM2FpNew().SetBytesWide(&buf)
+
+	q0x, q0y := sswuParams.Osswu3mod4(u0)
+	q1x, q1y := sswuParams.Osswu3mod4(u1)
+	out.X = q0x
+	out.Y = q0y
+	out.Z.SetOne()
+	tv := &native.EllipticPoint{
+		X: q1x,
+		Y: q1y,
+		Z: fp.SM2FpNew().SetOne(),
+	}
+	k.Add(out, out, tv)
+	return nil
+}
+
+func (k sm2PointArithmetic) Double(out, arg *native.EllipticPoint) {
+	// Addition formula from Renes-Costello-Batina 2015
+	// (https://eprint.iacr.org/2015/1060 Algorithm 6)
+	var xx, yy, zz, xy2, yz2, xz2, bzz, bzz3 [native.FieldLimbs]uint64
+	var yyMBzz3, yyPBzz3, yFrag, xFrag, zz3 [native.FieldLimbs]uint64
+	var bxz2, bxz6, xx3Mzz3, x, y, z [native.FieldLimbs]uint64
+	b := getSM2PointParams().B.Value
+	f := arg.X.Arithmetic
+
+	f.Square(&xx, &arg.X.Value)
+	f.Square(&yy, &arg.Y.Value)
+	f.Square(&zz, &arg.Z.Value)
+
+	f.Mul(&xy2, &arg.X.Value, &arg.Y.Value)
+	f.Add(&xy2, &xy2, &xy2)
+
+	f.Mul(&yz2, &arg.Y.Value, &arg.Z.Value)
+	f.Add(&yz2, &yz2, &yz2)
+
+	f.Mul(&xz2, &arg.X.Value, &arg.Z.Value)
+	f.Add(&xz2, &xz2, &xz2)
+
+	f.Mul(&bzz, &b, &zz)
+	f.Sub(&bzz, &bzz, &xz2)
+
+	f.Add(&bzz3, &bzz, &bzz)
+	f.Add(&bzz3, &bzz3, &bzz)
+
+	f.Sub(&yyMBzz3, &yy, &bzz3)
+	f.Add(&yyPBzz3, &yy, &bzz3)
+	f.Mul(&yFrag, &yyPBzz3, &yyMBzz3)
+	f.Mul(&xFrag, &yyMBzz3, &xy2)
+
+	f.Add(&zz3, &zz, &zz)
+	f.Add(&zz3, &zz3, &zz)
+
+	f.Mul(&bxz2, &b, &xz2)
+	f.Sub(&bxz2, &bxz2, &zz3)
+	f.Sub(&bxz2, &bxz2, &xx)
+
+	f.Add(&bxz6, &bxz2, &bxz2)
+	f.Add(&bxz6, &bxz6, &bxz2)
+
+	f.Add(&xx3Mzz3, &xx, &xx)
+	f.Add(&xx3Mzz3, &xx3Mzz3, &xx)
+	f.Sub(&xx3Mzz3, &xx3Mzz3, &zz3)
+
+	f.Mul(&x, &bxz6, &yz2)
+	f.Sub(&x, &xFrag, &x)
+
+	f.Mul(&y, &xx3Mzz3, &bxz6)
+	f.Add(&y, &yFrag, &y)
+
+	f.Mul(&z, &yz2, &yy)
+	f.Add(&z, &z, &z)
+	f.Add(&z, &z, &z)
+
+	out.X.Value = x
+	out.Y.Value = y
+	out.Z.Value = z
+}
+
+func (k sm2PointArithmetic) Add(out, arg1, arg2 *native.EllipticPoint) {
+	// Addition formula from Renes-Costello-Batina 2015
+	// (https://eprint.iacr.org/2015/1060 Algorithm 4).
+	var xx, yy, zz, zz3, bxz, bxz3 [native.FieldLimbs]uint64
+	var tv1, xyPairs, yzPairs, xzPairs [native.FieldLimbs]uint64
+	var bzz, bzz3, yyMBzz3, yyPBzz3 [native.FieldLimbs]uint64
+	var xx3Mzz3, x, y, z [native.FieldLimbs]uint64
+	f := arg1.X.Arithmetic
+	b := getSM2PointParams().B.Value
+
+	f.Mul(&xx, &arg1.X.Value, &arg2.X.Value)
+	f.Mul(&yy, &arg1.Y.Value, &arg2.Y.Value)
+	f.Mul(&zz, &arg1.Z.Value, &arg2.Z.Value)
+
+	f.Add(&tv1, &arg2.X.Value, &arg2.Y.Value)
+	f.Add(&xyPairs, &arg1.X.Value, &arg1.Y.Value)
+	f.Mul(&xyPairs, &xyPairs, &tv1)
+	f.Sub(&xyPairs, &xyPairs, &xx)
+	f.Sub(&xyPairs, &xyPairs, &yy)
+
+	f.Add(&tv1, &arg2.Y.Value, &arg2.Z.Value)
+	f.Add(&yzPairs, &arg1.Y.Value, &arg1.Z.Value)
+	f.Mul(&yzPairs, &yzPairs, &tv1)
+	f.Sub(&yzPairs, &yzPairs, &yy)
+	f.Sub(&yzPairs, &yzPairs, &zz)
+
+	f.Add(&tv1, &arg2.X.Value, &arg2.Z.Value)
+	f.Add(&xzPairs, &arg1.X.Value, &arg1.Z.Value)
+	f.Mul(&xzPairs, &xzPairs, &tv1)
+	f.Sub(&xzPairs, &xzPairs, &xx)
+	f.Sub(&xzPairs, &xzPairs, &zz)
+
+	f.Mul(&bzz, &b, &zz)
+	f.Sub(&bzz, &xzPairs, &bzz)
+
+	f.Add(&bzz3, &bzz, &bzz)
+	f.Add(&bzz3, &bzz3, &bzz)
+
+	f.Sub(&yyMBzz3, &yy, &bzz3)
+	f.Add(&yyPBzz3, &yy, &bzz3)
+
+	f.Add(&zz3, &zz, &zz)
+	f.Add(&zz3, &zz3, &zz)
+
+	f.Mul(&bxz, &b, &xzPairs)
+	f.Sub(&bxz, &bxz, &zz3)
+	f.Sub(&bxz, &bxz, &xx)
+
+	f.Add(&bxz3, &bxz, &bxz)
+	f.Add(&bxz3, &bxz3, &bxz)
+
+	f.Add(&xx3Mzz3, &xx, &xx)
+	f.Add(&xx3Mzz3, &xx3Mzz3, &xx)
+	f.Sub(&xx3Mzz3, &xx3Mzz3, &zz3)
+
+	f.Mul(&tv1, &yzPairs, &bxz3)
+	f.Mul(&x, &yyPBzz3, &xyPairs)
+	f.Sub(&x, &x, &tv1)
+
+	f.Mul(&tv1, &xx3Mzz3, &bxz3)
+	f.Mul(&y, &yyPBzz3, &yyMBzz3)
+	f.Add(&y, &y, &tv1)
+
+	f.Mul(&tv1, &xyPairs, &xx3Mzz3)
+	f.Mul(&z, &yyMBzz3, &yzPairs)
+	f.Add(&z, &z, &tv1)
+
+	e1 := arg1.Z.IsZero()
+	e2 := arg2.Z.IsZero()
+
+	// If arg1 is identity set it to arg2
+	f.Selectznz(&z, &z, &arg2.Z.Value, e1)
+	f.Selectznz(&y, &y, &arg2.Y.Value, e1)
+	f.Selectznz(&x, &x, &arg2.X.Value, e1)
+	// If arg2 is identity set it to arg1
+	f.Selectznz(&z, &z, &arg1.Z.Value, e2)
+	f.Selectznz(&y, &y, &arg1.Y.Value, e2)
+	f.Selectznz(&x, &x, &arg1.X.Value, e2)
+
+	out.X.Value = x
+	out.Y.Value = y
+	out.Z.Value = z
+}
+
+func (k sm2PointArithmetic) IsOnCurve(arg *native.EllipticPoint) bool {
+	affine := SM2PointNew()
+	k.ToAffine(affine, arg)
+	lhs := fp.SM2FpNew().Square(affine.Y)
+	rhs := fp.SM2FpNew()
+	k.RhsEq(rhs, affine.X)
+	return lhs.Equal(rhs) == 1
+}
+
+func (k sm2PointArithmetic) ToAffine(out, arg *native.EllipticPoint) {
+	var wasInverted int
+	var zero, x, y, z [native.FieldLimbs]uint64
+	f := arg.X.Arithmetic
+
+	f.Invert(&wasInverted, &z, &arg.Z.Value)
+	f.Mul(&x, &arg.X.Value, &z)
+	f.Mul(&y, &arg.Y.Value, &z)
+
+	out.Z.SetOne()
+	// If point at infinity this does nothing
+	f.Selectznz(&x, &zero, &x, wasInverted)
+	f.Selectznz(&y, &zero, &y, wasInverted)
+	f.Selectznz(&z, &zero, &out.Z.Value, wasInverted)
+
+	out.X.Value = x
+	out.Y.Value = y
+	out.Z.Value = z
+	out.Params = arg.Params
+	out.Arithmetic = arg.Arithmetic
+}
+
+func (k sm2PointArithmetic) RhsEq(out, x *native.Field) {
+	// Elliptic curve equation for sm2 is: y^2 = x^3 ax + b
+	out.Square(x)
+	out.Mul(out, x)
+	out.Add(out, getSM2PointParams().B)
+	out.Add(out, fp.SM2FpNew().Mul(getSM2PointParams().A, x))
+}
@@ -0,0 +1,38 @@
+package sm2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/sm2"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/sm2/fp"
+)
+
+func TestSM2PointArithmetic_Double(t *testing.T) {
+	g := sm2.SM2PointNew().Generator()
+	pt1 := sm2.SM2PointNew().Double(g)
+	pt2 := sm2.SM2PointNew().Add(g, g)
+	pt3 := sm2.SM2PointNew().Mul(g, fp.SM2FpNew().SetUint64(2))
+
+	e1 := pt1.Equal(pt2)
+	e2 := pt1.Equal(pt3)
+	e3 := pt2.Equal(pt3)
+	require.Equal(t, 1, e1)
+	require.Equal(t, 1, e2)
+	require.Equal(t, 1, e3)
+}
+
+func TestSM2PointArithmetic_Hash(t *testing.T) {
+	var b [32]byte
+	sc, err := sm2.SM2PointNew().Hash(b[:], native.EllipticPointHasherSha256())
+	sc1 := curves.SM2().NewIdentityPoint().Hash(b[:])
+
+	require.NoError(t, err)
+	require.True(t, !sc.IsIdentity())
+	require.True(t, sc.IsOnCurve())
+	require.True(t, sc1.IsOnCurve())
+	require.False(t, sc1.IsIdentity())
+}
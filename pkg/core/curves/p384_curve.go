@@ -0,0 +1,590 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package curves
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/coinbase/kryptology/internal"
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+	p384n "github.com/coinbase/kryptology/pkg/core/curves/native/p384"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/p384/fp"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/p384/fq"
+)
+
+var nistP384InitOnce sync.Once
+var nistP384 NistP384
+
+// NistP384 exposes the native P-384 implementation as an `elliptic.Curve`
+type NistP384 struct {
+	*elliptic.CurveParams
+}
+
+func nistP384InitAll() {
+	nistP384.CurveParams = elliptic.P384().Params()
+}
+
+func NistP384Curve() *NistP384 {
+	nistP384InitOnce.Do(nistP384InitAll)
+	return &nistP384
+}
+
+func (curve *NistP384) Params() *elliptic.CurveParams {
+	return curve.CurveParams
+}
+
+func (curve *NistP384) IsOnCurve(x, y *big.Int) bool {
+	_, err := p384n.P384PointNew().SetBigInt(x, y)
+	return err == nil
+}
+
+func (curve *NistP384) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	p1, err := p384n.P384PointNew().SetBigInt(x1, y1)
+	if err != nil {
+		return nil, nil
+	}
+	p2, err := p384n.P384PointNew().SetBigInt(x2, y2)
+	if err != nil {
+		return nil, nil
+	}
+	return p1.Add(p1, p2).BigInt()
+}
+
+func (curve *NistP384) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	p1, err := p384n.P384PointNew().SetBigInt(x1, y1)
+	if err != nil {
+		return nil, nil
+	}
+	return p1.Double(p1).BigInt()
+}
+
+func (curve *NistP384) ScalarMult(Bx, By *big.Int, k []byte) (*big.Int, *big.Int) {
+	p1, err := p384n.P384PointNew().SetBigInt(Bx, By)
+	if err != nil {
+		return nil, nil
+	}
+	s := fq.P384FqNew().SetBigInt(new(big.Int).SetBytes(k))
+	return p1.Mul(p1, s).BigInt()
+}
+
+func (curve *NistP384) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	s := fq.P384FqNew().SetBigInt(new(big.Int).SetBytes(k))
+	p1 := p384n.P384PointNew().Generator()
+	return p1.Mul(p1, s).BigInt()
+}
+
+type ScalarP384 struct {
+	value *fq.Fq
+}
+
+type PointP384 struct {
+	value *p384n.Point
+}
+
+func (s *ScalarP384) Random(reader io.Reader) Scalar {
+	if reader == nil {
+		return nil
+	}
+	var seed [fq.WideFieldBytes]byte
+	_, _ = reader.Read(seed[:])
+	return s.Hash(seed[:])
+}
+
+func (s *ScalarP384) Hash(bytes []byte) Scalar {
+	dst := []byte("P384_XMD:SHA-384_SSWU_RO_")
+	xmd := native.ExpandMsgXmd(native.EllipticPointHasherSha384(), bytes, dst, 72)
+	var t [fq.WideFieldBytes]byte
+	copy(t[:72], internal.ReverseScalarBytes(xmd))
+
+	return &ScalarP384{
+		value: fq.P384FqNew().SetBytesWide(&t),
+	}
+}
+
+func (s *ScalarP384) Zero() Scalar {
+	return &ScalarP384{
+		value: fq.P384FqNew().SetZero(),
+	}
+}
+
+func (s *ScalarP384) One() Scalar {
+	return &ScalarP384{
+		value: fq.P384FqNew().SetOne(),
+	}
+}
+
+func (s *ScalarP384) IsZero() bool {
+	return s.value.IsZero() == 1
+}
+
+func (s *ScalarP384) IsOne() bool {
+	return s.value.IsOne() == 1
+}
+
+func (s *ScalarP384) IsOdd() bool {
+	return s.value.Bytes()[0]&1 == 1
+}
+
+func (s *ScalarP384) IsEven() bool {
+	return s.value.Bytes()[0]&1 == 0
+}
+
+func (s *ScalarP384) New(value int) Scalar {
+	return &ScalarP384{
+		value: fq.P384FqNew().SetBigInt(big.NewInt(int64(value))),
+	}
+}
+
+func (s *ScalarP384) Cmp(rhs Scalar) int {
+	r, ok := rhs.(*ScalarP384)
+	if ok {
+		return s.value.Cmp(r.value)
+	} else {
+		return -2
+	}
+}
+
+func (s *ScalarP384) Square() Scalar {
+	return &ScalarP384{
+		value: fq.P384FqNew().Square(s.value),
+	}
+}
+
+func (s *ScalarP384) Double() Scalar {
+	return &ScalarP384{
+		value: fq.P384FqNew().Double(s.value),
+	}
+}
+
+func (s *ScalarP384) Invert() (Scalar, error) {
+	value, wasInverted := fq.P384FqNew().Invert(s.value)
+	if !wasInverted {
+		return nil, fmt.Errorf("inverse doesn't exist")
+	}
+	return &ScalarP384{
+		value,
+	}, nil
+}
+
+func (s *ScalarP384) Sqrt() (Scalar, error) {
+	value, wasSquare := fq.P384FqNew().Sqrt(s.value)
+	if !wasSquare {
+		return nil, fmt.Errorf("not a square")
+	}
+	return &ScalarP384{
+		value,
+	}, nil
+}
+
+func (s *ScalarP384) Cube() Scalar {
+	value := fq.P384FqNew().Mul(s.value, s.value)
+	value.Mul(value, s.value)
+	return &ScalarP384{
+		value,
+	}
+}
+
+func (s *ScalarP384) Add(rhs Scalar) Scalar {
+	r, ok := rhs.(*ScalarP384)
+	if ok {
+		return &ScalarP384{
+			value: fq.P384FqNew().Add(s.value, r.value),
+		}
+	} else {
+		return nil
+	}
+}
+
+func (s *ScalarP384) Sub(rhs Scalar) Scalar {
+	r, ok := rhs.(*ScalarP384)
+	if ok {
+		return &ScalarP384{
+			value: fq.P384FqNew().Sub(s.value, r.value),
+		}
+	} else {
+		return nil
+	}
+}
+
+func (s *ScalarP384) Mul(rhs Scalar) Scalar {
+	r, ok := rhs.(*ScalarP384)
+	if ok {
+		return &ScalarP384{
+			value: fq.P384FqNew().Mul(s.value, r.value),
+		}
+	} else {
+		return nil
+	}
+}
+
+func (s *ScalarP384) MulAdd(y, z Scalar) Scalar {
+	return s.Mul(y).Add(z)
+}
+
+func (s *ScalarP384) Div(rhs Scalar) Scalar {
+	r, ok := rhs.(*ScalarP384)
+	if ok {
+		v, wasInverted := fq.P384FqNew().Invert(r.value)
+		if !wasInverted {
+			return nil
+		}
+		v.Mul(v, s.value)
+		return &ScalarP384{value: v}
+	} else {
+		return nil
+	}
+}
+
+func (s *ScalarP384) Neg() Scalar {
+	return &ScalarP384{
+		value: fq.P384FqNew().Neg(s.value),
+	}
+}
+
+func (s *ScalarP384) SetBigInt(v *big.Int) (Scalar, error) {
+	if v == nil {
+		return nil, fmt.Errorf("'v' cannot be nil")
+	}
+	value := fq.P384FqNew().SetBigInt(v)
+	return &ScalarP384{
+		value,
+	}, nil
+}
+
+func (s *ScalarP384) BigInt() *big.Int {
+	return s.value.BigInt()
+}
+
+func (s *ScalarP384) Bytes() []byte {
+	t := s.value.Bytes()
+	return internal.ReverseScalarBytes(t[:])
+}
+
+func (s *ScalarP384) SetBytes(bytes []byte) (Scalar, error) {
+	if len(bytes) != fq.FieldBytes {
+		return nil, fmt.Errorf("invalid length")
+	}
+	var seq [fq.FieldBytes]byte
+	copy(seq[:], internal.ReverseScalarBytes(bytes))
+	value, err := fq.P384FqNew().SetBytes(&seq)
+	if err != nil {
+		return nil, err
+	}
+	return &ScalarP384{
+		value,
+	}, nil
+}
+
+func (s *ScalarP384) SetBytesWide(bytes []byte) (Scalar, error) {
+	if len(bytes) != fq.WideFieldBytes {
+		return nil, fmt.Errorf("invalid length")
+	}
+	var seq [fq.WideFieldBytes]byte
+	copy(seq[:], bytes)
+	return &ScalarP384{
+		value: fq.P384FqNew().SetBytesWide(&seq),
+	}, nil
+}
+
+func (s *ScalarP384) Point() Point {
+	return new(PointP384).Identity()
+}
+
+func (s *ScalarP384) Clone() Scalar {
+	return &ScalarP384{
+		value: fq.P384FqNew().Set(s.value),
+	}
+}
+
+func (s *ScalarP384) MarshalBinary() ([]byte, error) {
+	return scalarMarshalBinary(s)
+}
+
+func (s *ScalarP384) UnmarshalBinary(input []byte) error {
+	sc, err := scalarUnmarshalBinary(input)
+	if err != nil {
+		return err
+	}
+	ss, ok := sc.(*ScalarP384)
+	if !ok {
+		return fmt.Errorf("invalid scalar")
+	}
+	s.value = ss.value
+	return nil
+}
+
+func (s *ScalarP384) MarshalText() ([]byte, error) {
+	return scalarMarshalText(s)
+}
+
+func (s *ScalarP384) UnmarshalText(input []byte) error {
+	sc, err := scalarUnmarshalText(input)
+	if err != nil {
+		return err
+	}
+	ss, ok := sc.(*ScalarP384)
+	if !ok {
+		return fmt.Errorf("invalid scalar")
+	}
+	s.value = ss.value
+	return nil
+}
+
+func (s *ScalarP384) MarshalJSON() ([]byte, error) {
+	return scalarMarshalJson(s)
+}
+
+func (s *ScalarP384) UnmarshalJSON(input []byte) error {
+	sc, err := scalarUnmarshalJson(input)
+	if err != nil {
+		return err
+	}
+	S, ok := sc.(*ScalarP384)
+	if !ok {
+		return fmt.Errorf("invalid type")
+	}
+	s.value = S.value
+	return nil
+}
+
+func (p *PointP384) Random(reader io.Reader) Point {
+	var seed [fp.WideFieldBytes]byte
+	_, _ = reader.Read(seed[:])
+	return p.Hash(seed[:])
+}
+
+func (p *PointP384) Hash(bytes []byte) Point {
+	dst := []byte("P384_XMD:SHA-384_SSWU_RO_")
+	value := p384n.P384PointNew().Hash(native.EllipticPointHasherSha384(), bytes, dst)
+	return &PointP384{value}
+}
+
+func (p *PointP384) Identity() Point {
+	return &PointP384{
+		value: p384n.P384PointNew().Identity(),
+	}
+}
+
+func (p *PointP384) Generator() Point {
+	return &PointP384{
+		value: p384n.P384PointNew().Generator(),
+	}
+}
+
+func (p *PointP384) IsIdentity() bool {
+	return p.value.IsIdentity() == 1
+}
+
+func (p *PointP384) IsNegative() bool {
+	return p.value.GetY().Sgn0() == 1
+}
+
+func (p *PointP384) IsOnCurve() bool {
+	return p.value.IsOnCurve() == 1
+}
+
+func (p *PointP384) Double() Point {
+	value := p384n.P384PointNew().Double(p.value)
+	return &PointP384{value}
+}
+
+func (p *PointP384) Scalar() Scalar {
+	return new(ScalarP384).Zero()
+}
+
+func (p *PointP384) Neg() Point {
+	value := p384n.P384PointNew().Neg(p.value)
+	return &PointP384{value}
+}
+
+func (p *PointP384) Add(rhs Point) Point {
+	if rhs == nil {
+		return nil
+	}
+	r, ok := rhs.(*PointP384)
+	if ok {
+		value := p384n.P384PointNew().Add(p.value, r.value)
+		return &PointP384{value}
+	} else {
+		return nil
+	}
+}
+
+func (p *PointP384) Sub(rhs Point) Point {
+	if rhs == nil {
+		return nil
+	}
+	r, ok := rhs.(*PointP384)
+	if ok {
+		value := p384n.P384PointNew().Sub(p.value, r.value)
+		return &PointP384{value}
+	} else {
+		return nil
+	}
+}
+
+func (p *PointP384) Mul(rhs Scalar) Point {
+	if rhs == nil {
+		return nil
+	}
+	r, ok := rhs.(*ScalarP384)
+	if ok {
+		value := p384n.P384PointNew().Mul(p.value, r.value)
+		return &PointP384{value}
+	} else {
+		return nil
+	}
+}
+
+func (p *PointP384) Equal(rhs Point) bool {
+	r, ok := rhs.(*PointP384)
+	if ok {
+		return p.value.Equal(r.value) == 1
+	} else {
+		return false
+	}
+}
+
+func (p *PointP384) Set(x, y *big.Int) (Point, error) {
+	value, err := p384n.P384PointNew().SetBigInt(x, y)
+	if err != nil {
+		return nil, err
+	}
+	return &PointP384{value}, nil
+}
+
+func (p *PointP384) ToAffineCompressed() []byte {
+	out := p.value.ToCompressed()
+	return out[:]
+}
+
+func (p *PointP384) ToAffineUncompressed() []byte {
+	out := p.value.ToUncompressed()
+	return out[:]
+}
+
+func (p *PointP384) FromAffineCompressed(bytes []byte) (Point, error) {
+	var input [p384n.CompressedBytes]byte
+	if len(bytes) != p384n.CompressedBytes {
+		return nil, fmt.Errorf("invalid byte sequence")
+	}
+	copy(input[:], bytes)
+	value, err := p384n.P384PointNew().FromCompressed(&input)
+	if err != nil {
+		return nil, err
+	}
+	return &PointP384{value}, nil
+}
+
+func (p *PointP384) FromAffineUncompressed(bytes []byte) (Point, error) {
+	var input [p384n.UncompressedBytes]byte
+	if len(bytes) != p384n.UncompressedBytes {
+		return nil, fmt.Errorf("invalid byte sequence")
+	}
+	copy(input[:], bytes)
+	value, err := p384n.P384PointNew().FromUncompressed(&input)
+	if err != nil {
+		return nil, err
+	}
+	return &PointP384{value}, nil
+}
+
+func (p *PointP384) CurveName() string {
+	return elliptic.P384().Params().Name
+}
+
+func (p *PointP384) SumOfProducts(points []Point, scalars []Scalar) Point {
+	nPoints := make([]*p384n.Point, len(points))
+	nScalars := make([]*fq.Fq, len(scalars))
+	for i, pt := range points {
+		ptv, ok := pt.(*PointP384)
+		if !ok {
+			return nil
+		}
+		nPoints[i] = ptv.value
+	}
+	for i, sc := range scalars {
+		s, ok := sc.(*ScalarP384)
+		if !ok {
+			return nil
+		}
+		nScalars[i] = s.value
+	}
+	value := p384n.P384PointNew()
+	_, err := value.SumOfProducts(nPoints, nScalars)
+	if err != nil {
+		return nil
+	}
+	return &PointP384{value}
+}
+
+func (p *PointP384) X() *fp.Fp {
+	return p.value.GetX()
+}
+
+func (p *PointP384) Y() *fp.Fp {
+	return p.value.GetY()
+}
+
+func (p *PointP384) Params() *elliptic.CurveParams {
+	return elliptic.P384().Params()
+}
+
+func (p *PointP384) MarshalBinary() ([]byte, error) {
+	return pointMarshalBinary(p)
+}
+
+func (p *PointP384) UnmarshalBinary(input []byte) error {
+	pt, err := pointUnmarshalBinary(input)
+	if err != nil {
+		return err
+	}
+	ppt, ok := pt.(*PointP384)
+	if !ok {
+		return fmt.Errorf("invalid point")
+	}
+	p.value = ppt.value
+	return nil
+}
+
+func (p *PointP384) MarshalText() ([]byte, error) {
+	return pointMarshalText(p)
+}
+
+func (p *PointP384) UnmarshalText(input []byte) error {
+	pt, err := pointUnmarshalText(input)
+	if err != nil {
+		return err
+	}
+	ppt, ok := pt.(*PointP384)
+	if !ok {
+		return fmt.Errorf("invalid point")
+	}
+	p.value = ppt.value
+	return nil
+}
+
+func (p *PointP384) MarshalJSON() ([]byte, error) {
+	return pointMarshalJson(p)
+}
+
+func (p *PointP384) UnmarshalJSON(input []byte) error {
+	pt, err := pointUnmarshalJson(input)
+	if err != nil {
+		return err
+	}
+	P, ok := pt.(*PointP384)
+	if !ok {
+		return fmt.Errorf("invalid type")
+	}
+	p.value = P.value
+	return nil
+}
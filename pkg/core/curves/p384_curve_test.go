@@ -0,0 +1,209 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package curves
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScalarP384Arithmetic(t *testing.T) {
+	p384 := P384()
+	nine := p384.Scalar.New(9)
+	require.Equal(t, nine.Square().Cmp(p384.Scalar.New(81)), 0)
+	require.Equal(t, nine.Cube().Cmp(p384.Scalar.New(729)), 0)
+	require.Equal(t, nine.Double().Cmp(p384.Scalar.New(18)), 0)
+	require.Equal(t, nine.Add(p384.Scalar.New(-10)).Cmp(p384.Scalar.New(-1)), 0)
+	require.Equal(t, nine.Sub(nine).Cmp(p384.Scalar.Zero()), 0)
+	require.Equal(t, p384.Scalar.New(54).Div(nine).Cmp(p384.Scalar.New(6)), 0)
+	require.True(t, nine.Neg().Add(nine).IsZero())
+
+	inv, err := nine.Invert()
+	require.NoError(t, err)
+	require.True(t, inv.Mul(nine).IsOne())
+	root, err := nine.Sqrt()
+	require.NoError(t, err)
+	require.Equal(t, root.Square().Cmp(nine), 0)
+
+	n := elliptic.P384().Params().N
+	require.Equal(t, p384.Scalar.New(-1).BigInt(), new(big.Int).Sub(n, big.NewInt(1)))
+	reduced, err := p384.Scalar.SetBigInt(n)
+	require.NoError(t, err)
+	require.True(t, reduced.IsZero())
+}
+
+func TestScalarP384Hash(t *testing.T) {
+	p384 := P384()
+	var b [32]byte
+	sc := p384.Scalar.Hash(b[:])
+	_, ok := sc.(*ScalarP384)
+	require.True(t, ok)
+	require.False(t, sc.IsZero())
+	require.Equal(t, sc.Cmp(p384.Scalar.Hash(b[:])), 0)
+	require.NotEqual(t, sc.Cmp(p384.Scalar.Hash([]byte("other"))), 0)
+}
+
+func TestScalarP384Serialize(t *testing.T) {
+	p384 := P384()
+	sc := p384.Scalar.New(255)
+	sequence := sc.Bytes()
+	require.Equal(t, len(sequence), 48)
+	require.Equal(t, sequence[48-1], byte(0xff))
+	ret, err := p384.Scalar.SetBytes(sequence)
+	require.NoError(t, err)
+	require.Equal(t, ret.Cmp(sc), 0)
+
+	for i := 0; i < 10; i++ {
+		sc = p384.Scalar.Random(crand.Reader)
+		sequence = sc.Bytes()
+		require.Equal(t, len(sequence), 48)
+		ret, err = p384.Scalar.SetBytes(sequence)
+		require.NoError(t, err)
+		require.Equal(t, ret.Cmp(sc), 0)
+
+		bin, err := scalarMarshalBinary(sc)
+		require.NoError(t, err)
+		ret, err = scalarUnmarshalBinary(bin)
+		require.NoError(t, err)
+		require.Equal(t, ret.Cmp(sc), 0)
+
+		txt, err := scalarMarshalText(sc)
+		require.NoError(t, err)
+		ret, err = scalarUnmarshalText(txt)
+		require.NoError(t, err)
+		require.Equal(t, ret.Cmp(sc), 0)
+	}
+	_, err = p384.Scalar.SetBytesWide(make([]byte, 48))
+	require.Error(t, err)
+}
+
+func TestPointP384Generator(t *testing.T) {
+	p384 := P384()
+	sc := p384.Point.Generator()
+	s, ok := sc.(*PointP384)
+	require.True(t, ok)
+	require.Equal(t, s.X().BigInt(), elliptic.P384().Params().Gx)
+	require.Equal(t, s.Y().BigInt(), elliptic.P384().Params().Gy)
+	require.True(t, s.IsOnCurve())
+	require.Equal(t, s.CurveName(), P384Name)
+}
+
+func TestPointP384Arithmetic(t *testing.T) {
+	p384 := P384()
+	g := p384.Point.Generator()
+	require.True(t, g.Add(g).Equal(g.Double()))
+	require.True(t, g.Mul(p384.Scalar.New(3)).Equal(g.Add(g).Add(g)))
+	require.True(t, g.Mul(p384.Scalar.New(4)).Sub(g).Sub(g).Sub(g).Equal(g))
+	require.True(t, g.Neg().Add(g).IsIdentity())
+	require.True(t, g.Mul(p384.Scalar.New(-1)).Equal(g.Neg()))
+	require.True(t, p384.Point.Identity().Double().IsIdentity())
+
+	// Compare against the standard library
+	curve := elliptic.P384()
+	for i := 0; i < 5; i++ {
+		s := p384.Scalar.Random(crand.Reader)
+		pt := g.Mul(s).(*PointP384)
+		x, y := curve.ScalarBaseMult(s.Bytes())
+		require.Equal(t, pt.X().BigInt(), x)
+		require.Equal(t, pt.Y().BigInt(), y)
+	}
+}
+
+func TestPointP384Hash(t *testing.T) {
+	p384 := P384()
+	var b [32]byte
+	pt := p384.Point.Hash(b[:])
+	require.True(t, pt.IsOnCurve())
+	require.False(t, pt.IsIdentity())
+	require.True(t, pt.Equal(p384.Point.Hash(b[:])))
+	require.False(t, pt.Equal(p384.Point.Hash([]byte("other"))))
+}
+
+func TestPointP384Serialize(t *testing.T) {
+	p384 := P384()
+	g := p384.Point.Generator()
+	curve := elliptic.P384()
+	for i := 0; i < 10; i++ {
+		s := p384.Scalar.Random(crand.Reader)
+		pt := g.Mul(s).(*PointP384)
+
+		cmprs := pt.ToAffineCompressed()
+		require.Equal(t, len(cmprs), 49)
+		require.Equal(t, cmprs, elliptic.MarshalCompressed(curve, pt.X().BigInt(), pt.Y().BigInt()))
+		retC, err := pt.FromAffineCompressed(cmprs)
+		require.NoError(t, err)
+		require.True(t, pt.Equal(retC))
+
+		un := pt.ToAffineUncompressed()
+		require.Equal(t, len(un), 97)
+		require.Equal(t, un, elliptic.Marshal(curve, pt.X().BigInt(), pt.Y().BigInt()))
+		retU, err := pt.FromAffineUncompressed(un)
+		require.NoError(t, err)
+		require.True(t, pt.Equal(retU))
+
+		bin, err := pointMarshalBinary(pt)
+		require.NoError(t, err)
+		retB, err := pointUnmarshalBinary(bin)
+		require.NoError(t, err)
+		require.True(t, pt.Equal(retB))
+	}
+	un := g.ToAffineUncompressed()
+	un[len(un)-1] ^= 1
+	_, err := g.FromAffineUncompressed(un)
+	require.Error(t, err)
+}
+
+func TestPointP384SumOfProducts(t *testing.T) {
+	lhs := new(PointP384).Generator().Mul(new(ScalarP384).New(50))
+	points := make([]Point, 5)
+	for i := range points {
+		points[i] = new(PointP384).Generator()
+	}
+	scalars := []Scalar{
+		new(ScalarP384).New(8),
+		new(ScalarP384).New(9),
+		new(ScalarP384).New(10),
+		new(ScalarP384).New(11),
+		new(ScalarP384).New(12),
+	}
+	rhs := lhs.SumOfProducts(points, scalars)
+	require.NotNil(t, rhs)
+	require.True(t, lhs.Equal(rhs))
+}
+
+func TestP384Ecdsa(t *testing.T) {
+	p384 := P384()
+	require.Equal(t, GetCurveByName(P384Name), p384)
+	curve, err := p384.ToEllipticCurve()
+	require.NoError(t, err)
+	require.Equal(t, curve, NistP384Curve())
+
+	sk, err := ecdsa.GenerateKey(curve, crand.Reader)
+	require.NoError(t, err)
+	digest := sha512.Sum384([]byte("kryptology"))
+	r, s, err := ecdsa.Sign(crand.Reader, sk, digest[:])
+	require.NoError(t, err)
+
+	pk := &EcPoint{Curve: curve, X: sk.X, Y: sk.Y}
+	require.True(t, pk.IsOnCurve())
+	require.True(t, VerifyEcdsa(pk, digest[:], &EcdsaSignature{R: r, S: s}))
+	require.False(t, VerifyEcdsa(pk, digest[:], &EcdsaSignature{R: s, S: r}))
+
+	// The public key round trips through EcPoint's binary encoding
+	bin, err := pk.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, len(bin), 1+2*48)
+	pk2 := new(EcPoint)
+	require.NoError(t, pk2.UnmarshalBinary(bin))
+	require.True(t, pk.Equals(pk2))
+}
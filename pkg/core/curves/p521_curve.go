@@ -0,0 +1,590 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package curves
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/coinbase/kryptology/internal"
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+	p521n "github.com/coinbase/kryptology/pkg/core/curves/native/p521"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/p521/fp"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/p521/fq"
+)
+
+var nistP521InitOnce sync.Once
+var nistP521 NistP521
+
+// NistP521 exposes the native P-521 implementation as an `elliptic.Curve`
+type NistP521 struct {
+	*elliptic.CurveParams
+}
+
+func nistP521InitAll() {
+	nistP521.CurveParams = elliptic.P521().Params()
+}
+
+func NistP521Curve() *NistP521 {
+	nistP521InitOnce.Do(nistP521InitAll)
+	return &nistP521
+}
+
+func (curve *NistP521) Params() *elliptic.CurveParams {
+	return curve.CurveParams
+}
+
+func (curve *NistP521) IsOnCurve(x, y *big.Int) bool {
+	_, err := p521n.P521PointNew().SetBigInt(x, y)
+	return err == nil
+}
+
+func (curve *NistP521) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	p1, err := p521n.P521PointNew().SetBigInt(x1, y1)
+	if err != nil {
+		return nil, nil
+	}
+	p2, err := p521n.P521PointNew().SetBigInt(x2, y2)
+	if err != nil {
+		return nil, nil
+	}
+	return p1.Add(p1, p2).BigInt()
+}
+
+func (curve *NistP521) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	p1, err := p521n.P521PointNew().SetBigInt(x1, y1)
+	if err != nil {
+		return nil, nil
+	}
+	return p1.Double(p1).BigInt()
+}
+
+func (curve *NistP521) ScalarMult(Bx, By *big.Int, k []byte) (*big.Int, *big.Int) {
+	p1, err := p521n.P521PointNew().SetBigInt(Bx, By)
+	if err != nil {
+		return nil, nil
+	}
+	s := fq.P521FqNew().SetBigInt(new(big.Int).SetBytes(k))
+	return p1.Mul(p1, s).BigInt()
+}
+
+func (curve *NistP521) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	s := fq.P521FqNew().SetBigInt(new(big.Int).SetBytes(k))
+	p1 := p521n.P521PointNew().Generator()
+	return p1.Mul(p1, s).BigInt()
+}
+
+type ScalarP521 struct {
+	value *fq.Fq
+}
+
+type PointP521 struct {
+	value *p521n.Point
+}
+
+func (s *ScalarP521) Random(reader io.Reader) Scalar {
+	if reader == nil {
+		return nil
+	}
+	var seed [fq.WideFieldBytes]byte
+	_, _ = reader.Read(seed[:])
+	return s.Hash(seed[:])
+}
+
+func (s *ScalarP521) Hash(bytes []byte) Scalar {
+	dst := []byte("P521_XMD:SHA-512_SSWU_RO_")
+	xmd := native.ExpandMsgXmd(native.EllipticPointHasherSha512(), bytes, dst, 98)
+	var t [fq.WideFieldBytes]byte
+	copy(t[:98], internal.ReverseScalarBytes(xmd))
+
+	return &ScalarP521{
+		value: fq.P521FqNew().SetBytesWide(&t),
+	}
+}
+
+func (s *ScalarP521) Zero() Scalar {
+	return &ScalarP521{
+		value: fq.P521FqNew().SetZero(),
+	}
+}
+
+func (s *ScalarP521) One() Scalar {
+	return &ScalarP521{
+		value: fq.P521FqNew().SetOne(),
+	}
+}
+
+func (s *ScalarP521) IsZero() bool {
+	return s.value.IsZero() == 1
+}
+
+func (s *ScalarP521) IsOne() bool {
+	return s.value.IsOne() == 1
+}
+
+func (s *ScalarP521) IsOdd() bool {
+	return s.value.Bytes()[0]&1 == 1
+}
+
+func (s *ScalarP521) IsEven() bool {
+	return s.value.Bytes()[0]&1 == 0
+}
+
+func (s *ScalarP521) New(value int) Scalar {
+	return &ScalarP521{
+		value: fq.P521FqNew().SetBigInt(big.NewInt(int64(value))),
+	}
+}
+
+func (s *ScalarP521) Cmp(rhs Scalar) int {
+	r, ok := rhs.(*ScalarP521)
+	if ok {
+		return s.value.Cmp(r.value)
+	} else {
+		return -2
+	}
+}
+
+func (s *ScalarP521) Square() Scalar {
+	return &ScalarP521{
+		value: fq.P521FqNew().Square(s.value),
+	}
+}
+
+func (s *ScalarP521) Double() Scalar {
+	return &ScalarP521{
+		value: fq.P521FqNew().Double(s.value),
+	}
+}
+
+func (s *ScalarP521) Invert() (Scalar, error) {
+	value, wasInverted := fq.P521FqNew().Invert(s.value)
+	if !wasInverted {
+		return nil, fmt.Errorf("inverse doesn't exist")
+	}
+	return &ScalarP521{
+		value,
+	}, nil
+}
+
+func (s *ScalarP521) Sqrt() (Scalar, error) {
+	value, wasSquare := fq.P521FqNew().Sqrt(s.value)
+	if !wasSquare {
+		return nil, fmt.Errorf("not a square")
+	}
+	return &ScalarP521{
+		value,
+	}, nil
+}
+
+func (s *ScalarP521) Cube() Scalar {
+	value := fq.P521FqNew().Mul(s.value, s.value)
+	value.Mul(value, s.value)
+	return &ScalarP521{
+		value,
+	}
+}
+
+func (s *ScalarP521) Add(rhs Scalar) Scalar {
+	r, ok := rhs.(*ScalarP521)
+	if ok {
+		return &ScalarP521{
+			value: fq.P521FqNew().Add(s.value, r.value),
+		}
+	} else {
+		return nil
+	}
+}
+
+func (s *ScalarP521) Sub(rhs Scalar) Scalar {
+	r, ok := rhs.(*ScalarP521)
+	if ok {
+		return &ScalarP521{
+			value: fq.P521FqNew().Sub(s.value, r.value),
+		}
+	} else {
+		return nil
+	}
+}
+
+func (s *ScalarP521) Mul(rhs Scalar) Scalar {
+	r, ok := rhs.(*ScalarP521)
+	if ok {
+		return &ScalarP521{
+			value: fq.P521FqNew().Mul(s.value, r.value),
+		}
+	} else {
+		return nil
+	}
+}
+
+func (s *ScalarP521) MulAdd(y, z Scalar) Scalar {
+	return s.Mul(y).Add(z)
+}
+
+func (s *ScalarP521) Div(rhs Scalar) Scalar {
+	r, ok := rhs.(*ScalarP521)
+	if ok {
+		v, wasInverted := fq.P521FqNew().Invert(r.value)
+		if !wasInverted {
+			return nil
+		}
+		v.Mul(v, s.value)
+		return &ScalarP521{value: v}
+	} else {
+		return nil
+	}
+}
+
+func (s *ScalarP521) Neg() Scalar {
+	return &ScalarP521{
+		value: fq.P521FqNew().Neg(s.value),
+	}
+}
+
+func (s *ScalarP521) SetBigInt(v *big.Int) (Scalar, error) {
+	if v == nil {
+		return nil, fmt.Errorf("'v' cannot be nil")
+	}
+	value := fq.P521FqNew().SetBigInt(v)
+	return &ScalarP521{
+		value,
+	}, nil
+}
+
+func (s *ScalarP521) BigInt() *big.Int {
+	return s.value.BigInt()
+}
+
+func (s *ScalarP521) Bytes() []byte {
+	t := s.value.Bytes()
+	return internal.ReverseScalarBytes(t[:])
+}
+
+func (s *ScalarP521) SetBytes(bytes []byte) (Scalar, error) {
+	if len(bytes) != fq.FieldBytes {
+		return nil, fmt.Errorf("invalid length")
+	}
+	var seq [fq.FieldBytes]byte
+	copy(seq[:], internal.ReverseScalarBytes(bytes))
+	value, err := fq.P521FqNew().SetBytes(&seq)
+	if err != nil {
+		return nil, err
+	}
+	return &ScalarP521{
+		value,
+	}, nil
+}
+
+func (s *ScalarP521) SetBytesWide(bytes []byte) (Scalar, error) {
+	if len(bytes) != fq.WideFieldBytes {
+		return nil, fmt.Errorf("invalid length")
+	}
+	var seq [fq.WideFieldBytes]byte
+	copy(seq[:], bytes)
+	return &ScalarP521{
+		value: fq.P521FqNew().SetBytesWide(&seq),
+	}, nil
+}
+
+func (s *ScalarP521) Point() Point {
+	return new(PointP521).Identity()
+}
+
+func (s *ScalarP521) Clone() Scalar {
+	return &ScalarP521{
+		value: fq.P521FqNew().Set(s.value),
+	}
+}
+
+func (s *ScalarP521) MarshalBinary() ([]byte, error) {
+	return scalarMarshalBinary(s)
+}
+
+func (s *ScalarP521) UnmarshalBinary(input []byte) error {
+	sc, err := scalarUnmarshalBinary(input)
+	if err != nil {
+		return err
+	}
+	ss, ok := sc.(*ScalarP521)
+	if !ok {
+		return fmt.Errorf("invalid scalar")
+	}
+	s.value = ss.value
+	return nil
+}
+
+func (s *ScalarP521) MarshalText() ([]byte, error) {
+	return scalarMarshalText(s)
+}
+
+func (s *ScalarP521) UnmarshalText(input []byte) error {
+	sc, err := scalarUnmarshalText(input)
+	if err != nil {
+		return err
+	}
+	ss, ok := sc.(*ScalarP521)
+	if !ok {
+		return fmt.Errorf("invalid scalar")
+	}
+	s.value = ss.value
+	return nil
+}
+
+func (s *ScalarP521) MarshalJSON() ([]byte, error) {
+	return scalarMarshalJson(s)
+}
+
+func (s *ScalarP521) UnmarshalJSON(input []byte) error {
+	sc, err := scalarUnmarshalJson(input)
+	if err != nil {
+		return err
+	}
+	S, ok := sc.(*ScalarP521)
+	if !ok {
+		return fmt.Errorf("invalid type")
+	}
+	s.value = S.value
+	return nil
+}
+
+func (p *PointP521) Random(reader io.Reader) Point {
+	var seed [fp.WideFieldBytes]byte
+	_, _ = reader.Read(seed[:])
+	return p.Hash(seed[:])
+}
+
+func (p *PointP521) Hash(bytes []byte) Point {
+	dst := []byte("P521_XMD:SHA-512_SSWU_RO_")
+	value := p521n.P521PointNew().Hash(native.EllipticPointHasherSha512(), bytes, dst)
+	return &PointP521{value}
+}
+
+func (p *PointP521) Identity() Point {
+	return &PointP521{
+		value: p521n.P521PointNew().Identity(),
+	}
+}
+
+func (p *PointP521) Generator() Point {
+	return &PointP521{
+		value: p521n.P521PointNew().Generator(),
+	}
+}
+
+func (p *PointP521) IsIdentity() bool {
+	return p.value.IsIdentity() == 1
+}
+
+func (p *PointP521) IsNegative() bool {
+	return p.value.GetY().Sgn0() == 1
+}
+
+func (p *PointP521) IsOnCurve() bool {
+	return p.value.IsOnCurve() == 1
+}
+
+func (p *PointP521) Double() Point {
+	value := p521n.P521PointNew().Double(p.value)
+	return &PointP521{value}
+}
+
+func (p *PointP521) Scalar() Scalar {
+	return new(ScalarP521).Zero()
+}
+
+func (p *PointP521) Neg() Point {
+	value := p521n.P521PointNew().Neg(p.value)
+	return &PointP521{value}
+}
+
+func (p *PointP521) Add(rhs Point) Point {
+	if rhs == nil {
+		return nil
+	}
+	r, ok := rhs.(*PointP521)
+	if ok {
+		value := p521n.P521PointNew().Add(p.value, r.value)
+		return &PointP521{value}
+	} else {
+		return nil
+	}
+}
+
+func (p *PointP521) Sub(rhs Point) Point {
+	if rhs == nil {
+		return nil
+	}
+	r, ok := rhs.(*PointP521)
+	if ok {
+		value := p521n.P521PointNew().Sub(p.value, r.value)
+		return &PointP521{value}
+	} else {
+		return nil
+	}
+}
+
+func (p *PointP521) Mul(rhs Scalar) Point {
+	if rhs == nil {
+		return nil
+	}
+	r, ok := rhs.(*ScalarP521)
+	if ok {
+		value := p521n.P521PointNew().Mul(p.value, r.value)
+		return &PointP521{value}
+	} else {
+		return nil
+	}
+}
+
+func (p *PointP521) Equal(rhs Point) bool {
+	r, ok := rhs.(*PointP521)
+	if ok {
+		return p.value.Equal(r.value) == 1
+	} else {
+		return false
+	}
+}
+
+func (p *PointP521) Set(x, y *big.Int) (Point, error) {
+	value, err := p521n.P521PointNew().SetBigInt(x, y)
+	if err != nil {
+		return nil, err
+	}
+	return &PointP521{value}, nil
+}
+
+func (p *PointP521) ToAffineCompressed() []byte {
+	out := p.value.ToCompressed()
+	return out[:]
+}
+
+func (p *PointP521) ToAffineUncompressed() []byte {
+	out := p.value.ToUncompressed()
+	return out[:]
+}
+
+func (p *PointP521) FromAffineCompressed(bytes []byte) (Point, error) {
+	var input [p521n.CompressedBytes]byte
+	if len(bytes) != p521n.CompressedBytes {
+		return nil, fmt.Errorf("invalid byte sequence")
+	}
+	copy(input[:], bytes)
+	value, err := p521n.P521PointNew().FromCompressed(&input)
+	if err != nil {
+		return nil, err
+	}
+	return &PointP521{value}, nil
+}
+
+func (p *PointP521) FromAffineUncompressed(bytes []byte) (Point, error) {
+	var input [p521n.UncompressedBytes]byte
+	if len(bytes) != p521n.UncompressedBytes {
+		return nil, fmt.Errorf("invalid byte sequence")
+	}
+	copy(input[:], bytes)
+	value, err := p521n.P521PointNew().FromUncompressed(&input)
+	if err != nil {
+		return nil, err
+	}
+	return &PointP521{value}, nil
+}
+
+func (p *PointP521) CurveName() string {
+	return elliptic.P521().Params().Name
+}
+
+func (p *PointP521) SumOfProducts(points []Point, scalars []Scalar) Point {
+	nPoints := make([]*p521n.Point, len(points))
+	nScalars := make([]*fq.Fq, len(scalars))
+	for i, pt := range points {
+		ptv, ok := pt.(*PointP521)
+		if !ok {
+			return nil
+		}
+		nPoints[i] = ptv.value
+	}
+	for i, sc := range scalars {
+		s, ok := sc.(*ScalarP521)
+		if !ok {
+			return nil
+		}
+		nScalars[i] = s.value
+	}
+	value := p521n.P521PointNew()
+	_, err := value.SumOfProducts(nPoints, nScalars)
+	if err != nil {
+		return nil
+	}
+	return &PointP521{value}
+}
+
+func (p *PointP521) X() *fp.Fp {
+	return p.value.GetX()
+}
+
+func (p *PointP521) Y() *fp.Fp {
+	return p.value.GetY()
+}
+
+func (p *PointP521) Params() *elliptic.CurveParams {
+	return elliptic.P521().Params()
+}
+
+func (p *PointP521) MarshalBinary() ([]byte, error) {
+	return pointMarshalBinary(p)
+}
+
+func (p *PointP521) UnmarshalBinary(input []byte) error {
+	pt, err := pointUnmarshalBinary(input)
+	if err != nil {
+		return err
+	}
+	ppt, ok := pt.(*PointP521)
+	if !ok {
+		return fmt.Errorf("invalid point")
+	}
+	p.value = ppt.value
+	return nil
+}
+
+func (p *PointP521) MarshalText() ([]byte, error) {
+	return pointMarshalText(p)
+}
+
+func (p *PointP521) UnmarshalText(input []byte) error {
+	pt, err := pointUnmarshalText(input)
+	if err != nil {
+		return err
+	}
+	ppt, ok := pt.(*PointP521)
+	if !ok {
+		return fmt.Errorf("invalid point")
+	}
+	p.value = ppt.value
+	return nil
+}
+
+func (p *PointP521) MarshalJSON() ([]byte, error) {
+	return pointMarshalJson(p)
+}
+
+func (p *PointP521) UnmarshalJSON(input []byte) error {
+	pt, err := pointUnmarshalJson(input)
+	if err != nil {
+		return err
+	}
+	P, ok := pt.(*PointP521)
+	if !ok {
+		return fmt.Errorf("invalid type")
+	}
+	p.value = P.value
+	return nil
+}
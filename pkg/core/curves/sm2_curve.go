@@ -0,0 +1,667 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package curves
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/coinbase/kryptology/internal"
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+	sm2n "github.com/coinbase/kryptology/pkg/core/curves/native/sm2"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/sm2/fp"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/sm2/fq"
+)
+
+var sm2EllipticInitOnce sync.Once
+var sm2Elliptic SM2P256
+
+// SM2P256 exposes the SM2 recommended curve from GB/T 32918.5
+// as an `elliptic.Curve` backed by the native implementation
+type SM2P256 struct {
+	*elliptic.CurveParams
+}
+
+func sm2EllipticInitAll() {
+	sm2Elliptic.CurveParams = &elliptic.CurveParams{Name: SM2Name}
+	sm2Elliptic.P, _ = new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF00000000FFFFFFFFFFFFFFFF", 16)
+	sm2Elliptic.N, _ = new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFF7203DF6B21C6052B53BBF40939D54123", 16)
+	sm2Elliptic.B, _ = new(big.Int).SetString("28E9FA9E9D9F5E344D5A9E4BCF6509A7F39789F515AB8F92DDBCBD414D940E93", 16)
+	sm2Elliptic.Gx, _ = new(big.Int).SetString("32C4AE2C1F1981195F9904466A39C9948FE30BBFF2660BE1715A4589334C74C7", 16)
+	sm2Elliptic.Gy, _ = new(big.Int).SetString("BC3736A2F4F6779C59BDCEE36B692153D0A9877CC62A474002DF32E52139F0A0", 16)
+	sm2Elliptic.BitSize = 256
+}
+
+func SM2P256Curve() *SM2P256 {
+	sm2EllipticInitOnce.Do(sm2EllipticInitAll)
+	return &sm2Elliptic
+}
+
+func (curve *SM2P256) Params() *elliptic.CurveParams {
+	return curve.CurveParams
+}
+
+func (curve *SM2P256) IsOnCurve(x, y *big.Int) bool {
+	_, err := sm2n.SM2PointNew().SetBigInt(x, y)
+	return err == nil
+}
+
+func (curve *SM2P256) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	p1, err := sm2n.SM2PointNew().SetBigInt(x1, y1)
+	if err != nil {
+		return nil, nil
+	}
+	p2, err := sm2n.SM2PointNew().SetBigInt(x2, y2)
+	if err != nil {
+		return nil, nil
+	}
+	return p1.Add(p1, p2).BigInt()
+}
+
+func (curve *SM2P256) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	p1, err := sm2n.SM2PointNew().SetBigInt(x1, y1)
+	if err != nil {
+		return nil, nil
+	}
+	return p1.Double(p1).BigInt()
+}
+
+func (curve *SM2P256) ScalarMul(Bx, By *big.Int, k []byte) (*big.Int, *big.Int) {
+	p1, err := sm2n.SM2PointNew().SetBigInt(Bx, By)
+	if err != nil {
+		return nil, nil
+	}
+	var bytes [32]byte
+	copy(bytes[:], internal.ReverseScalarBytes(k))
+	s, err := fq.SM2FqNew().SetBytes(&bytes)
+	if err != nil {
+		return nil, nil
+	}
+	return p1.Mul(p1, s).BigInt()
+}
+
+func (curve *SM2P256) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	var bytes [32]byte
+	copy(bytes[:], internal.ReverseScalarBytes(k))
+	s, err := fq.SM2FqNew().SetBytes(&bytes)
+	if err != nil {
+		return nil, nil
+	}
+	p1 := sm2n.SM2PointNew().Generator()
+	return p1.Mul(p1, s).BigInt()
+}
+
+type ScalarSM2 struct {
+	value *native.Field
+}
+
+type PointSM2 struct {
+	value *native.EllipticPoint
+}
+
+func (s *ScalarSM2) Random(reader io.Reader) Scalar {
+	if reader == nil {
+		return nil
+	}
+	var seed [64]byte
+	_, _ = reader.Read(seed[:])
+	return s.Hash(seed[:])
+}
+
+func (s *ScalarSM2) Hash(bytes []byte) Scalar {
+	dst := []byte("SM2_XMD:SHA-256_SSWU_RO_")
+	xmd := native.ExpandMsgXmd(native.EllipticPointHasherSha256(), bytes, dst, 48)
+	var t [64]byte
+	copy(t[:48], internal.ReverseScalarBytes(xmd))
+
+	return &ScalarSM2{
+		value: fq.SM2FqNew().SetBytesWide(&t),
+	}
+}
+
+func (s *ScalarSM2) Zero() Scalar {
+	return &ScalarSM2{
+		value: fq.SM2FqNew().SetZero(),
+	}
+}
+
+func (s *ScalarSM2) One() Scalar {
+	return &ScalarSM2{
+		value: fq.SM2FqNew().SetOne(),
+	}
+}
+
+func (s *ScalarSM2) IsZero() bool {
+	return s.value.IsZero() == 1
+}
+
+func (s *ScalarSM2) IsOne() bool {
+	return s.value.IsOne() == 1
+}
+
+func (s *ScalarSM2) IsOdd() bool {
+	return s.value.Bytes()[0]&1 == 1
+}
+
+func (s *ScalarSM2) IsEven() bool {
+	return s.value.Bytes()[0]&1 == 0
+}
+
+func (s *ScalarSM2) New(value int) Scalar {
+	t := fq.SM2FqNew()
+	v := big.NewInt(int64(value))
+	if value < 0 {
+		v.Mod(v, t.Params.BiModulus)
+	}
+	return &ScalarSM2{
+		value: t.SetBigInt(v),
+	}
+}
+
+func (s *ScalarSM2) Cmp(rhs Scalar) int {
+	r, ok := rhs.(*ScalarSM2)
+	if ok {
+		return s.value.Cmp(r.value)
+	} else {
+		return -2
+	}
+}
+
+func (s *ScalarSM2) Square() Scalar {
+	return &ScalarSM2{
+		value: fq.SM2FqNew().Square(s.value),
+	}
+}
+
+func (s *ScalarSM2) Double() Scalar {
+	return &ScalarSM2{
+		value: fq.SM2FqNew().Double(s.value),
+	}
+}
+
+func (s *ScalarSM2) Invert() (Scalar, error) {
+	value, wasInverted := fq.SM2FqNew().Invert(s.value)
+	if !wasInverted {
+		return nil, fmt.Errorf("inverse doesn't exist")
+	}
+	return &ScalarSM2{
+		value,
+	}, nil
+}
+
+func (s *ScalarSM2) Sqrt() (Scalar, error) {
+	value, wasSquare := fq.SM2FqNew().Sqrt(s.value)
+	if !wasSquare {
+		return nil, fmt.Errorf("not a square")
+	}
+	return &ScalarSM2{
+		value,
+	}, nil
+}
+
+func (s *ScalarSM2) Cube() Scalar {
+	value := fq.SM2FqNew().Mul(s.value, s.value)
+	value.Mul(value, s.value)
+	return &ScalarSM2{
+		value,
+	}
+}
+
+func (s *ScalarSM2) Add(rhs Scalar) Scalar {
+	r, ok := rhs.(*ScalarSM2)
+	if ok {
+		return &ScalarSM2{
+			value: fq.SM2FqNew().Add(s.value, r.value),
+		}
+	} else {
+		return nil
+	}
+}
+
+func (s *ScalarSM2) Sub(rhs Scalar) Scalar {
+	r, ok := rhs.(*ScalarSM2)
+	if ok {
+		return &ScalarSM2{
+			value: fq.SM2FqNew().Sub(s.value, r.value),
+		}
+	} else {
+		return nil
+	}
+}
+
+func (s *ScalarSM2) Mul(rhs Scalar) Scalar {
+	r, ok := rhs.(*ScalarSM2)
+	if ok {
+		return &ScalarSM2{
+			value: fq.SM2FqNew().Mul(s.value, r.value),
+		}
+	} else {
+		return nil
+	}
+}
+
+func (s *ScalarSM2) MulAdd(y, z Scalar) Scalar {
+	return s.Mul(y).Add(z)
+}
+
+func (s *ScalarSM2) Div(rhs Scalar) Scalar {
+	r, ok := rhs.(*ScalarSM2)
+	if ok {
+		v, wasInverted := fq.SM2FqNew().Invert(r.value)
+		if !wasInverted {
+			return nil
+		}
+		v.Mul(v, s.value)
+		return &ScalarSM2{value: v}
+	} else {
+		return nil
+	}
+}
+
+func (s *ScalarSM2) Neg() Scalar {
+	return &ScalarSM2{
+		value: fq.SM2FqNew().Neg(s.value),
+	}
+}
+
+func (s *ScalarSM2) SetBigInt(v *big.Int) (Scalar, error) {
+	if v == nil {
+		return nil, fmt.Errorf("'v' cannot be nil")
+	}
+	value := fq.SM2FqNew().SetBigInt(v)
+	return &ScalarSM2{
+		value,
+	}, nil
+}
+
+func (s *ScalarSM2) BigInt() *big.Int {
+	return s.value.BigInt()
+}
+
+func (s *ScalarSM2) Bytes() []byte {
+	t := s.value.Bytes()
+	return internal.ReverseScalarBytes(t[:])
+}
+
+func (s *ScalarSM2) SetBytes(bytes []byte) (Scalar, error) {
+	if len(bytes) != 32 {
+		return nil, fmt.Errorf("invalid length")
+	}
+	var seq [32]byte
+	copy(seq[:], internal.ReverseScalarBytes(bytes))
+	value, err := fq.SM2FqNew().SetBytes(&seq)
+	if err != nil {
+		return nil, err
+	}
+	return &ScalarSM2{
+		value,
+	}, nil
+}
+
+func (s *ScalarSM2) SetBytesWide(bytes []byte) (Scalar, error) {
+	if len(bytes) != 64 {
+		return nil, fmt.Errorf("invalid length")
+	}
+	var seq [64]byte
+	copy(seq[:], bytes)
+	return &ScalarSM2{
+		value: fq.SM2FqNew().SetBytesWide(&seq),
+	}, nil
+}
+
+func (s *ScalarSM2) Point() Point {
+	return new(PointSM2).Identity()
+}
+
+func (s *ScalarSM2) Clone() Scalar {
+	return &ScalarSM2{
+		value: fq.SM2FqNew().Set(s.value),
+	}
+}
+
+func (s *ScalarSM2) MarshalBinary() ([]byte, error) {
+	return scalarMarshalBinary(s)
+}
+
+func (s *ScalarSM2) UnmarshalBinary(input []byte) error {
+	sc, err := scalarUnmarshalBinary(input)
+	if err != nil {
+		return err
+	}
+	ss, ok := sc.(*ScalarSM2)
+	if !ok {
+		return fmt.Errorf("invalid scalar")
+	}
+	s.value = ss.value
+	return nil
+}
+
+func (s *ScalarSM2) MarshalText() ([]byte, error) {
+	return scalarMarshalText(s)
+}
+
+func (s *ScalarSM2) UnmarshalText(input []byte) error {
+	sc, err := scalarUnmarshalText(input)
+	if err != nil {
+		return err
+	}
+	ss, ok := sc.(*ScalarSM2)
+	if !ok {
+		return fmt.Errorf("invalid scalar")
+	}
+	s.value = ss.value
+	return nil
+}
+
+func (s *ScalarSM2) MarshalJSON() ([]byte, error) {
+	return scalarMarshalJson(s)
+}
+
+func (s *ScalarSM2) UnmarshalJSON(input []byte) error {
+	sc, err := scalarUnmarshalJson(input)
+	if err != nil {
+		return err
+	}
+	S, ok := sc.(*ScalarSM2)
+	if !ok {
+		return fmt.Errorf("invalid type")
+	}
+	s.value = S.value
+	return nil
+}
+
+func (p *PointSM2) Random(reader io.Reader) Point {
+	var seed [64]byte
+	_, _ = reader.Read(seed[:])
+	return p.Hash(seed[:])
+}
+
+func (p *PointSM2) Hash(bytes []byte) Point {
+	value, err := sm2n.SM2PointNew().Hash(bytes, native.EllipticPointHasherSha256())
+
+	// TODO: change hash to return an error also
+	if err != nil {
+		return nil
+	}
+
+	return &PointSM2{value}
+}
+
+func (p *PointSM2) Identity() Point {
+	return &PointSM2{
+		value: sm2n.SM2PointNew().Identity(),
+	}
+}
+
+func (p *PointSM2) Generator() Point {
+	return &PointSM2{
+		value: sm2n.SM2PointNew().Generator(),
+	}
+}
+
+func (p *PointSM2) IsIdentity() bool {
+	return p.value.IsIdentity()
+}
+
+func (p *PointSM2) IsNegative() bool {
+	return p.value.GetY().Value[0]&1 == 1
+}
+
+func (p *PointSM2) IsOnCurve() bool {
+	return p.value.IsOnCurve()
+}
+
+func (p *PointSM2) Double() Point {
+	value := sm2n.SM2PointNew().Double(p.value)
+	return &PointSM2{value}
+}
+
+func (p *PointSM2) Scalar() Scalar {
+	return new(ScalarSM2).Zero()
+}
+
+func (p *PointSM2) Neg() Point {
+	value := sm2n.SM2PointNew().Neg(p.value)
+	return &PointSM2{value}
+}
+
+func (p *PointSM2) Add(rhs Point) Point {
+	if rhs == nil {
+		return nil
+	}
+	r, ok := rhs.(*PointSM2)
+	if ok {
+		value := sm2n.SM2PointNew().Add(p.value, r.value)
+		return &PointSM2{value}
+	} else {
+		return nil
+	}
+}
+
+func (p *PointSM2) Sub(rhs Point) Point {
+	if rhs == nil {
+		return nil
+	}
+	r, ok := rhs.(*PointSM2)
+	if ok {
+		value := sm2n.SM2PointNew().Sub(p.value, r.value)
+		return &PointSM2{value}
+	} else {
+		return nil
+	}
+}
+
+func (p *PointSM2) Mul(rhs Scalar) Point {
+	if rhs == nil {
+		return nil
+	}
+	r, ok := rhs.(*ScalarSM2)
+	if ok {
+		value := sm2n.SM2PointNew().Mul(p.value, r.value)
+		return &PointSM2{value}
+	} else {
+		return nil
+	}
+}
+
+func (p *PointSM2) Equal(rhs Point) bool {
+	r, ok := rhs.(*PointSM2)
+	if ok {
+		return p.value.Equal(r.value) == 1
+	} else {
+		return false
+	}
+}
+
+func (p *PointSM2) Set(x, y *big.Int) (Point, error) {
+	value, err := sm2n.SM2PointNew().SetBigInt(x, y)
+	if err != nil {
+		return nil, err
+	}
+	return &PointSM2{value}, nil
+}
+
+func (p *PointSM2) ToAffineCompressed() []byte {
+	var x [33]byte
+	x[0] = byte(2)
+
+	t := sm2n.SM2PointNew().ToAffine(p.value)
+
+	x[0] |= t.Y.Bytes()[0] & 1
+
+	xBytes := t.X.Bytes()
+	copy(x[1:], internal.ReverseScalarBytes(xBytes[:]))
+	return x[:]
+}
+
+func (p *PointSM2) ToAffineUncompressed() []byte {
+	var out [65]byte
+	out[0] = byte(4)
+	t := sm2n.SM2PointNew().ToAffine(p.value)
+	arr := t.X.Bytes()
+	copy(out[1:33], internal.ReverseScalarBytes(arr[:]))
+	arr = t.Y.Bytes()
+	copy(out[33:], internal.ReverseScalarBytes(arr[:]))
+	return out[:]
+}
+
+func (p *PointSM2) FromAffineCompressed(bytes []byte) (Point, error) {
+	var raw [native.FieldBytes]byte
+	if len(bytes) != 33 {
+		return nil, fmt.Errorf("invalid byte sequence")
+	}
+	sign := int(bytes[0])
+	if sign != 2 && sign != 3 {
+		return nil, fmt.Errorf("invalid sign byte")
+	}
+	sign &= 0x1
+
+	copy(raw[:], internal.ReverseScalarBytes(bytes[1:]))
+	x, err := fp.SM2FpNew().SetBytes(&raw)
+	if err != nil {
+		return nil, err
+	}
+
+	value := sm2n.SM2PointNew().Identity()
+	rhs := fp.SM2FpNew()
+	p.value.Arithmetic.RhsEq(rhs, x)
+	// test that rhs is quadratic residue
+	// if not, then this Point is at infinity
+	y, wasQr := fp.SM2FpNew().Sqrt(rhs)
+	if wasQr {
+		// fix the sign
+		sigY := int(y.Bytes()[0] & 1)
+		if sigY != sign {
+			y.Neg(y)
+		}
+		value.X = x
+		value.Y = y
+		value.Z.SetOne()
+	}
+	return &PointSM2{value}, nil
+}
+
+func (p *PointSM2) FromAffineUncompressed(bytes []byte) (Point, error) {
+	var arr [native.FieldBytes]byte
+	if len(bytes) != 65 {
+		return nil, fmt.Errorf("invalid byte sequence")
+	}
+	if bytes[0] != 4 {
+		return nil, fmt.Errorf("invalid sign byte")
+	}
+
+	copy(arr[:], internal.ReverseScalarBytes(bytes[1:33]))
+	x, err := fp.SM2FpNew().SetBytes(&arr)
+	if err != nil {
+		return nil, err
+	}
+	copy(arr[:], internal.ReverseScalarBytes(bytes[33:]))
+	y, err := fp.SM2FpNew().SetBytes(&arr)
+	if err != nil {
+		return nil, err
+	}
+	value := sm2n.SM2PointNew()
+	value.X = x
+	value.Y = y
+	value.Z.SetOne()
+	return &PointSM2{value}, nil
+}
+
+func (p *PointSM2) CurveName() string {
+	return SM2Name
+}
+
+func (p *PointSM2) SumOfProducts(points []Point, scalars []Scalar) Point {
+	nPoints := make([]*native.EllipticPoint, len(points))
+	nScalars := make([]*native.Field, len(scalars))
+	for i, pt := range points {
+		ptv, ok := pt.(*PointSM2)
+		if !ok {
+			return nil
+		}
+		nPoints[i] = ptv.value
+	}
+	for i, sc := range scalars {
+		s, ok := sc.(*ScalarSM2)
+		if !ok {
+			return nil
+		}
+		nScalars[i] = s.value
+	}
+	value := sm2n.SM2PointNew()
+	_, err := value.SumOfProducts(nPoints, nScalars)
+	if err != nil {
+		return nil
+	}
+	return &PointSM2{value}
+}
+
+func (p *PointSM2) X() *native.Field {
+	return p.value.GetX()
+}
+
+func (p *PointSM2) Y() *native.Field {
+	return p.value.GetY()
+}
+
+func (p *PointSM2) Params() *elliptic.CurveParams {
+	return SM2P256Curve().Params()
+}
+
+func (p *PointSM2) MarshalBinary() ([]byte, error) {
+	return pointMarshalBinary(p)
+}
+
+func (p *PointSM2) UnmarshalBinary(input []byte) error {
+	pt, err := pointUnmarshalBinary(input)
+	if err != nil {
+		return err
+	}
+	ppt, ok := pt.(*PointSM2)
+	if !ok {
+		return fmt.Errorf("invalid point")
+	}
+	p.value = ppt.value
+	return nil
+}
+
+func (p *PointSM2) MarshalText() ([]byte, error) {
+	return pointMarshalText(p)
+}
+
+func (p *PointSM2) UnmarshalText(input []byte) error {
+	pt, err := pointUnmarshalText(input)
+	if err != nil {
+		return err
+	}
+	ppt, ok := pt.(*PointSM2)
+	if !ok {
+		return fmt.Errorf("invalid point")
+	}
+	p.value = ppt.value
+	return nil
+}
+
+func (p *PointSM2) MarshalJSON() ([]byte, error) {
+	return pointMarshalJson(p)
+}
+
+func (p *PointSM2) UnmarshalJSON(input []byte) error {
+	pt, err := pointUnmarshalJson(input)
+	if err != nil {
+		return err
+	}
+	P, ok := pt.(*PointSM2)
+	if !ok {
+		return fmt.Errorf("invalid type")
+	}
+	p.value = P.value
+	return nil
+}
@@ -0,0 +1,385 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package curves
+
+import (
+	crand "crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScalarSM2Random(t *testing.T) {
+	sm2 := SM2()
+	sc := sm2.Scalar.Random(testRng())
+	s, ok := sc.(*ScalarSM2)
+	require.True(t, ok)
+	expected := bhex("96cb15b17d6bc6d624c34ee3515f65d078cfc1ccf6697b03aeb491e4592a043a")
+	require.Equal(t, s.value.BigInt(), expected)
+	// Try 10 random values
+	for i := 0; i < 10; i++ {
+		sc := sm2.Scalar.Random(crand.Reader)
+		_, ok := sc.(*ScalarSM2)
+		require.True(t, ok)
+		require.True(t, !sc.IsZero())
+	}
+}
+
+func TestScalarSM2Hash(t *testing.T) {
+	var b [32]byte
+	sm2 := SM2()
+	sc := sm2.Scalar.Hash(b[:])
+	s, ok := sc.(*ScalarSM2)
+	require.True(t, ok)
+	expected := bhex("564ba886d80fc4826a7c59a38351305a771b540be57267d89f4d71f6ec4b1850")
+	require.Equal(t, s.value.BigInt(), expected)
+}
+
+func TestScalarSM2Zero(t *testing.T) {
+	sm2 := SM2()
+	sc := sm2.Scalar.Zero()
+	require.True(t, sc.IsZero())
+	require.True(t, sc.IsEven())
+}
+
+func TestScalarSM2One(t *testing.T) {
+	sm2 := SM2()
+	sc := sm2.Scalar.One()
+	require.True(t, sc.IsOne())
+	require.True(t, sc.IsOdd())
+}
+
+func TestScalarSM2New(t *testing.T) {
+	sm2 := SM2()
+	three := sm2.Scalar.New(3)
+	require.True(t, three.IsOdd())
+	four := sm2.Scalar.New(4)
+	require.True(t, four.IsEven())
+	neg1 := sm2.Scalar.New(-1)
+	require.True(t, neg1.IsEven())
+	neg2 := sm2.Scalar.New(-2)
+	require.True(t, neg2.IsOdd())
+}
+
+func TestScalarSM2Square(t *testing.T) {
+	sm2 := SM2()
+	three := sm2.Scalar.New(3)
+	nine := sm2.Scalar.New(9)
+	require.Equal(t, three.Square().Cmp(nine), 0)
+}
+
+func TestScalarSM2Cube(t *testing.T) {
+	sm2 := SM2()
+	three := sm2.Scalar.New(3)
+	twentySeven := sm2.Scalar.New(27)
+	require.Equal(t, three.Cube().Cmp(twentySeven), 0)
+}
+
+func TestScalarSM2Double(t *testing.T) {
+	sm2 := SM2()
+	three := sm2.Scalar.New(3)
+	six := sm2.Scalar.New(6)
+	require.Equal(t, three.Double().Cmp(six), 0)
+}
+
+func TestScalarSM2Neg(t *testing.T) {
+	sm2 := SM2()
+	one := sm2.Scalar.One()
+	neg1 := sm2.Scalar.New(-1)
+	require.Equal(t, one.Neg().Cmp(neg1), 0)
+	lotsOfThrees := sm2.Scalar.New(333333)
+	expected := sm2.Scalar.New(-333333)
+	require.Equal(t, lotsOfThrees.Neg().Cmp(expected), 0)
+}
+
+func TestScalarSM2Invert(t *testing.T) {
+	sm2 := SM2()
+	nine := sm2.Scalar.New(9)
+	actual, _ := nine.Invert()
+	sa, _ := actual.(*ScalarSM2)
+	bn := bhex("8e38e38daaaaaaaaaaaaaaaaaaaaaaaa5bc9433b848a74a64af6a40520212430")
+	expected, err := sm2.Scalar.SetBigInt(bn)
+	require.NoError(t, err)
+	require.Equal(t, sa.Cmp(expected), 0)
+}
+
+func TestScalarSM2Sqrt(t *testing.T) {
+	sm2 := SM2()
+	nine := sm2.Scalar.New(9)
+	actual, err := nine.Sqrt()
+	require.NoError(t, err)
+	require.Equal(t, actual.Square().Cmp(nine), 0)
+}
+
+func TestScalarSM2Add(t *testing.T) {
+	sm2 := SM2()
+	nine := sm2.Scalar.New(9)
+	six := sm2.Scalar.New(6)
+	fifteen := nine.Add(six)
+	require.NotNil(t, fifteen)
+	expected := sm2.Scalar.New(15)
+	require.Equal(t, expected.Cmp(fifteen), 0)
+	n := new(big.Int).Set(SM2P256Curve().Params().N)
+	n.Sub(n, big.NewInt(3))
+
+	upper, err := sm2.Scalar.SetBigInt(n)
+	require.NoError(t, err)
+	actual := upper.Add(nine)
+	require.NotNil(t, actual)
+	require.Equal(t, actual.Cmp(six), 0)
+}
+
+func TestScalarSM2Sub(t *testing.T) {
+	sm2 := SM2()
+	nine := sm2.Scalar.New(9)
+	six := sm2.Scalar.New(6)
+	n := new(big.Int).Set(SM2P256Curve().Params().N)
+	n.Sub(n, big.NewInt(3))
+
+	expected, err := sm2.Scalar.SetBigInt(n)
+	require.NoError(t, err)
+	actual := six.Sub(nine)
+	require.Equal(t, expected.Cmp(actual), 0)
+
+	actual = nine.Sub(six)
+	require.Equal(t, actual.Cmp(sm2.Scalar.New(3)), 0)
+}
+
+func TestScalarSM2Mul(t *testing.T) {
+	sm2 := SM2()
+	nine := sm2.Scalar.New(9)
+	six := sm2.Scalar.New(6)
+	actual := nine.Mul(six)
+	require.Equal(t, actual.Cmp(sm2.Scalar.New(54)), 0)
+	n := new(big.Int).Set(SM2P256Curve().Params().N)
+	n.Sub(n, big.NewInt(1))
+	upper, err := sm2.Scalar.SetBigInt(n)
+	require.NoError(t, err)
+	require.Equal(t, upper.Mul(upper).Cmp(sm2.Scalar.New(1)), 0)
+}
+
+func TestScalarSM2Div(t *testing.T) {
+	sm2 := SM2()
+	nine := sm2.Scalar.New(9)
+	actual := nine.Div(nine)
+	require.Equal(t, actual.Cmp(sm2.Scalar.New(1)), 0)
+	require.Equal(t, sm2.Scalar.New(54).Div(nine).Cmp(sm2.Scalar.New(6)), 0)
+}
+
+func TestScalarSM2Serialize(t *testing.T) {
+	sm2 := SM2()
+	sc := sm2.Scalar.New(255)
+	sequence := sc.Bytes()
+	require.Equal(t, len(sequence), 32)
+	require.Equal(t, sequence, []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff})
+	ret, err := sm2.Scalar.SetBytes(sequence)
+	require.NoError(t, err)
+	require.Equal(t, ret.Cmp(sc), 0)
+
+	// Try 10 random values
+	for i := 0; i < 10; i++ {
+		sc = sm2.Scalar.Random(crand.Reader)
+		sequence = sc.Bytes()
+		require.Equal(t, len(sequence), 32)
+		ret, err = sm2.Scalar.SetBytes(sequence)
+		require.NoError(t, err)
+		require.Equal(t, ret.Cmp(sc), 0)
+	}
+}
+
+func TestScalarSM2Nil(t *testing.T) {
+	sm2 := SM2()
+	one := sm2.Scalar.New(1)
+	require.Nil(t, one.Add(nil))
+	require.Nil(t, one.Sub(nil))
+	require.Nil(t, one.Mul(nil))
+	require.Nil(t, one.Div(nil))
+	require.Nil(t, sm2.Scalar.Random(nil))
+	require.Equal(t, one.Cmp(nil), -2)
+	_, err := sm2.Scalar.SetBigInt(nil)
+	require.Error(t, err)
+}
+
+func TestPointSM2Random(t *testing.T) {
+	sm2 := SM2()
+	sc := sm2.Point.Random(testRng())
+	s, ok := sc.(*PointSM2)
+	require.True(t, ok)
+	expectedX, _ := new(big.Int).SetString("9a416452131adec6b3d66a847aa8f6811814e119a4e6a3d91c1bc784f16f0785", 16)
+	expectedY, _ := new(big.Int).SetString("17ea6097191f932c9f04e94be5e3e43a31f66d5b8e60bd362d8d7c3659df5686", 16)
+	require.Equal(t, s.X().BigInt(), expectedX)
+	require.Equal(t, s.Y().BigInt(), expectedY)
+	// Try 10 random values
+	for i := 0; i < 10; i++ {
+		sc := sm2.Point.Random(crand.Reader)
+		_, ok := sc.(*PointSM2)
+		require.True(t, ok)
+		require.True(t, !sc.IsIdentity())
+	}
+}
+
+func TestPointSM2Hash(t *testing.T) {
+	var b [32]byte
+	sm2 := SM2()
+	sc := sm2.Point.Hash(b[:])
+	s, ok := sc.(*PointSM2)
+	require.True(t, ok)
+	expectedX, _ := new(big.Int).SetString("73e3ffdd10c17a526ed9a91edbedde1cbc272cc056c01e2f027d8199f181a5f8", 16)
+	expectedY, _ := new(big.Int).SetString("7aee5918bb099673437c3c58671fb84821cfc906b9fb265d9e174ad224c66312", 16)
+	require.Equal(t, s.X().BigInt(), expectedX)
+	require.Equal(t, s.Y().BigInt(), expectedY)
+}
+
+func TestPointSM2Identity(t *testing.T) {
+	sm2 := SM2()
+	sc := sm2.Point.Identity()
+	require.True(t, sc.IsIdentity())
+	require.Equal(t, sc.ToAffineCompressed(), []byte{2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+}
+
+func TestPointSM2Generator(t *testing.T) {
+	sm2 := SM2()
+	sc := sm2.Point.Generator()
+	s, ok := sc.(*PointSM2)
+	require.True(t, ok)
+	require.Equal(t, s.X().BigInt(), SM2P256Curve().Params().Gx)
+	require.Equal(t, s.Y().BigInt(), SM2P256Curve().Params().Gy)
+}
+
+func TestPointSM2Set(t *testing.T) {
+	sm2 := SM2()
+	iden, err := sm2.Point.Set(big.NewInt(0), big.NewInt(0))
+	require.NoError(t, err)
+	require.True(t, iden.IsIdentity())
+	_, err = sm2.Point.Set(SM2P256Curve().Params().Gx, SM2P256Curve().Params().Gy)
+	require.NoError(t, err)
+}
+
+func TestPointSM2Double(t *testing.T) {
+	sm2 := SM2()
+	g := sm2.Point.Generator()
+	g2 := g.Double()
+	require.True(t, g2.Equal(g.Mul(sm2.Scalar.New(2))))
+	i := sm2.Point.Identity()
+	require.True(t, i.Double().Equal(i))
+}
+
+func TestPointSM2Neg(t *testing.T) {
+	sm2 := SM2()
+	g := sm2.Point.Generator().Neg()
+	require.True(t, g.Neg().Equal(sm2.Point.Generator()))
+	require.True(t, sm2.Point.Identity().Neg().Equal(sm2.Point.Identity()))
+}
+
+func TestPointSM2Add(t *testing.T) {
+	sm2 := SM2()
+	pt := sm2.Point.Generator()
+	require.True(t, pt.Add(pt).Equal(pt.Double()))
+	require.True(t, pt.Mul(sm2.Scalar.New(3)).Equal(pt.Add(pt).Add(pt)))
+}
+
+func TestPointSM2Sub(t *testing.T) {
+	sm2 := SM2()
+	g := sm2.Point.Generator()
+	pt := sm2.Point.Generator().Mul(sm2.Scalar.New(4))
+	require.True(t, pt.Sub(g).Sub(g).Sub(g).Equal(g))
+	require.True(t, pt.Sub(g).Sub(g).Sub(g).Sub(g).IsIdentity())
+}
+
+func TestPointSM2Mul(t *testing.T) {
+	sm2 := SM2()
+	g := sm2.Point.Generator()
+	pt := sm2.Point.Generator().Mul(sm2.Scalar.New(4))
+	require.True(t, g.Double().Double().Equal(pt))
+}
+
+func TestPointSM2Serialize(t *testing.T) {
+	sm2 := SM2()
+	ss := sm2.Scalar.Random(testRng())
+	g := sm2.Point.Generator()
+
+	ppt := g.Mul(ss)
+	require.Equal(t, ppt.ToAffineCompressed(), []byte{0x2, 0xcc, 0x6a, 0xa5, 0xdd, 0x10, 0xb3, 0xb9, 0x2c, 0x7e, 0xaf, 0xd3, 0x81, 0xde, 0xff, 0x21, 0x14, 0x58, 0xea, 0x5d, 0x8d, 0x37, 0x7c, 0xb5, 0xe9, 0x62, 0x3e, 0x37, 0xbc, 0xe1, 0x2f, 0x5b, 0xd8})
+	require.Equal(t, ppt.ToAffineUncompressed(), []byte{0x4, 0xcc, 0x6a, 0xa5, 0xdd, 0x10, 0xb3, 0xb9, 0x2c, 0x7e, 0xaf, 0xd3, 0x81, 0xde, 0xff, 0x21, 0x14, 0x58, 0xea, 0x5d, 0x8d, 0x37, 0x7c, 0xb5, 0xe9, 0x62, 0x3e, 0x37, 0xbc, 0xe1, 0x2f, 0x5b, 0xd8, 0xdd, 0x1, 0x5e, 0xbc, 0xe3, 0x1c, 0xec, 0xb3, 0xf6, 0x0, 0x4, 0x1c, 0x99, 0x3f, 0xe8, 0xfe, 0x23, 0x22, 0xef, 0x2d, 0xed, 0xf3, 0xf0, 0x51, 0x4b, 0x8f, 0xa4, 0x9e, 0xb1, 0xe1, 0xcf, 0xf4})
+	retP, err := ppt.FromAffineCompressed(ppt.ToAffineCompressed())
+	require.NoError(t, err)
+	require.True(t, ppt.Equal(retP))
+	retP, err = ppt.FromAffineUncompressed(ppt.ToAffineUncompressed())
+	require.NoError(t, err)
+	require.True(t, ppt.Equal(retP))
+
+	// smoke test
+	for i := 0; i < 25; i++ {
+		s := sm2.Scalar.Random(crand.Reader)
+		pt := g.Mul(s)
+		cmprs := pt.ToAffineCompressed()
+		require.Equal(t, len(cmprs), 33)
+		retC, err := pt.FromAffineCompressed(cmprs)
+		require.NoError(t, err)
+		require.True(t, pt.Equal(retC))
+
+		un := pt.ToAffineUncompressed()
+		require.Equal(t, len(un), 65)
+		retU, err := pt.FromAffineUncompressed(un)
+		require.NoError(t, err)
+		require.True(t, pt.Equal(retU))
+	}
+}
+
+func TestPointSM2Nil(t *testing.T) {
+	sm2 := SM2()
+	one := sm2.Point.Generator()
+	require.Nil(t, one.Add(nil))
+	require.Nil(t, one.Sub(nil))
+	require.Nil(t, one.Mul(nil))
+	require.Nil(t, sm2.Scalar.Random(nil))
+	require.False(t, one.Equal(nil))
+	_, err := sm2.Scalar.SetBigInt(nil)
+	require.Error(t, err)
+}
+
+func TestPointSM2SumOfProducts(t *testing.T) {
+	lhs := new(PointSM2).Generator().Mul(new(ScalarSM2).New(50))
+	points := make([]Point, 5)
+	for i := range points {
+		points[i] = new(PointSM2).Generator()
+	}
+	scalars := []Scalar{
+		new(ScalarSM2).New(8),
+		new(ScalarSM2).New(9),
+		new(ScalarSM2).New(10),
+		new(ScalarSM2).New(11),
+		new(ScalarSM2).New(12),
+	}
+	rhs := lhs.SumOfProducts(points, scalars)
+	require.NotNil(t, rhs)
+	require.True(t, lhs.Equal(rhs))
+}
+
+func TestPointSM2KnownAnswer(t *testing.T) {
+	sm2 := SM2()
+	// Key pair from the GB/T 32918.2 signature example on the recommended curve
+	d, err := sm2.Scalar.SetBigInt(bhex("3945208f7b2144b13f36e38ac6d39f95889393692860b51a42fb81ef4df7c5b8"))
+	require.NoError(t, err)
+	pt := sm2.Point.Generator().Mul(d).(*PointSM2)
+	require.Equal(t, pt.X().BigInt(), bhex("09f9df311e5421a150dd7d161e4bc5c672179fad1833fc076bb08ff356f35020"))
+	require.Equal(t, pt.Y().BigInt(), bhex("ccea490ce26775a52dc6ea718cc1aa600aed05fbf35e084a6632f6072da9ad13"))
+
+	n := new(big.Int).Set(SM2P256Curve().Params().N)
+	order, err := sm2.Scalar.SetBigInt(n.Sub(n, big.NewInt(1)))
+	require.NoError(t, err)
+	require.True(t, sm2.Point.Generator().Mul(order).Add(sm2.Point.Generator()).IsIdentity())
+
+	ec, err := sm2.ToEllipticCurve()
+	require.NoError(t, err)
+	x, y := ec.ScalarBaseMult(d.Bytes())
+	require.Equal(t, x, pt.X().BigInt())
+	require.Equal(t, y, pt.Y().BigInt())
+	require.True(t, ec.IsOnCurve(x, y))
+	require.Equal(t, GetCurveByName(SM2Name), sm2)
+}
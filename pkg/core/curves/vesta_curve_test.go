@@ -0,0 +1,152 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package curves
+
+import (
+	crand "crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves/native/pasta/fp"
+)
+
+func TestPointVestaAddDoubleMul(t *testing.T) {
+	g := new(Eq).Generator()
+	id := new(Eq).Identity()
+	require.Equal(t, g.Add(g, id), g)
+
+	g2 := new(Eq).Add(g, g)
+	require.True(t, new(Eq).Double(g).Equal(g2))
+	require.Equal(t, new(Eq).Double(g), new(Eq).Add(g, g))
+	g3 := new(Eq).Add(g, g2)
+	require.True(t, g3.Equal(new(Eq).Mul(g, new(fp.Fp).SetUint64(3))))
+
+	g4 := new(Eq).Add(g3, g)
+	require.True(t, g4.Equal(new(Eq).Double(g2)))
+	require.True(t, g4.Equal(new(Eq).Mul(g, new(fp.Fp).SetUint64(4))))
+}
+
+func TestPointVestaGenerator(t *testing.T) {
+	g := new(Eq).Generator()
+	require.True(t, g.IsOnCurve())
+	require.True(t, g.X().IsOne())
+
+	// the order of the generator is the modulus of fp
+	minusOne := new(fp.Fp).Neg(new(fp.Fp).SetOne())
+	require.True(t, new(Eq).Mul(g, minusOne).Equal(new(Eq).Neg(g)))
+	require.True(t, new(Eq).Add(new(Eq).Mul(g, minusOne), g).IsIdentity())
+}
+
+func TestPastaCycle(t *testing.T) {
+	// the base field of each curve is the scalar field of the other: -1 in the scalar field of one is -x for the
+	// generator x = 1 of the other
+	pallasOrder := new(ScalarPallas).One().Neg().BigInt()
+	vestaOrder := new(ScalarVesta).One().Neg().BigInt()
+	vestaP := new(Eq).Generator().X()
+	vestaP.Neg(vestaP)
+	pallasP := new(Ep).Generator().X()
+	pallasP.Neg(pallasP)
+	require.Equal(t, 0, pallasOrder.Cmp(vestaP.BigInt()))
+	require.Equal(t, 0, vestaOrder.Cmp(pallasP.BigInt()))
+}
+
+func TestPointVestaHash(t *testing.T) {
+	h0 := new(Eq).Hash(nil)
+	require.True(t, h0.IsOnCurve())
+	h1 := new(Eq).Hash([]byte{})
+	require.True(t, h1.IsOnCurve())
+	require.True(t, h0.Equal(h1))
+	h2 := new(Eq).Hash([]byte{1})
+	require.True(t, h2.IsOnCurve())
+	require.False(t, h2.Equal(h1))
+
+	// the iso-curve points land on Vesta for many inputs
+	for i := 0; i < 25; i++ {
+		require.True(t, new(Eq).Random(crand.Reader).IsOnCurve())
+	}
+}
+
+func TestPointVestaNeg(t *testing.T) {
+	g := new(Eq).Generator()
+	g.Neg(g)
+	require.True(t, g.Neg(g).Equal(new(Eq).Generator()))
+	id := new(Eq).Identity()
+	require.True(t, new(Eq).Neg(id).Equal(id))
+}
+
+func TestPointVestaSerialize(t *testing.T) {
+	g := new(Eq).Generator()
+	for i := 0; i < 25; i++ {
+		s := new(ScalarVesta).Random(crand.Reader).(*ScalarVesta)
+		pt := new(Eq).Mul(g, s.value)
+		cmprs := pt.ToAffineCompressed()
+		require.Equal(t, len(cmprs), 32)
+		retC, err := new(Eq).FromAffineCompressed(cmprs)
+		require.NoError(t, err)
+		require.True(t, pt.Equal(retC))
+
+		un := pt.ToAffineUncompressed()
+		require.Equal(t, len(un), 64)
+		retU, err := new(Eq).FromAffineUncompressed(un)
+		require.NoError(t, err)
+		require.True(t, pt.Equal(retU))
+	}
+
+	curve := VESTA()
+	point := curve.Point.Random(crand.Reader)
+	data, err := point.(*PointVesta).MarshalBinary()
+	require.NoError(t, err)
+	decoded := new(PointVesta)
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.True(t, point.Equal(decoded))
+
+	scalar := curve.Scalar.Random(crand.Reader)
+	data, err = scalar.(*ScalarVesta).MarshalJSON()
+	require.NoError(t, err)
+	decodedScalar := new(ScalarVesta)
+	require.NoError(t, decodedScalar.UnmarshalJSON(data))
+	require.Equal(t, 0, scalar.Cmp(decodedScalar))
+
+	// pallas points are not vesta points
+	data, err = PALLAS().Point.Generator().(*PointPallas).MarshalBinary()
+	require.NoError(t, err)
+	require.Error(t, decoded.UnmarshalBinary(data))
+}
+
+func TestPointVestaCMove(t *testing.T) {
+	a := new(Eq).Random(crand.Reader)
+	b := new(Eq).Random(crand.Reader)
+	require.True(t, new(Eq).CMove(a, b, 1).Equal(b))
+	require.True(t, new(Eq).CMove(a, b, 0).Equal(a))
+}
+
+func TestPointVestaSumOfProducts(t *testing.T) {
+	lhs := new(Eq).Generator()
+	lhs.Mul(lhs, new(fp.Fp).SetUint64(50))
+	points := make([]*Eq, 5)
+	for i := range points {
+		points[i] = new(Eq).Generator()
+	}
+	scalars := []Scalar{
+		new(ScalarVesta).New(8),
+		new(ScalarVesta).New(9),
+		new(ScalarVesta).New(10),
+		new(ScalarVesta).New(11),
+		new(ScalarVesta).New(12),
+	}
+	rhs := lhs.SumOfProducts(points, scalars)
+	require.NotNil(t, rhs)
+	require.True(t, lhs.Equal(rhs))
+}
+
+func TestVestaCurveByName(t *testing.T) {
+	curve := GetCurveByName(VestaName)
+	require.NotNil(t, curve)
+	require.Equal(t, VestaName, curve.Name)
+	require.True(t, curve.ScalarBaseMult(curve.Scalar.New(2)).Equal(curve.NewGeneratorPoint().Double()))
+}
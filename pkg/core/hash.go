@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"crypto/elliptic"
 	"crypto/sha256"
+	"crypto/sha512"
 	"fmt"
 	"hash"
 	"math"
@@ -48,6 +49,28 @@ func getParams(curve elliptic.Curve) (*Params, error) {
 			Hash:              sha256.New,
 			L:                 48,
 		}, nil
+	case elliptic.P384().Params().Name:
+		return &Params{
+			F: &HashField{
+				Order:           curve.Params().P,
+				Characteristic:  curve.Params().P,
+				ExtensionDegree: new(big.Int).SetInt64(1),
+			},
+			SecurityParameter: 192,
+			Hash:              sha512.New384,
+			L:                 72,
+		}, nil
+	case elliptic.P521().Params().Name:
+		return &Params{
+			F: &HashField{
+				Order:           curve.Params().P,
+				Characteristic:  curve.Params().P,
+				ExtensionDegree: new(big.Int).SetInt64(1),
+			},
+			SecurityParameter: 256,
+			Hash:              sha512.New,
+			L:                 98,
+		}, nil
 	case "Bls12381G1":
 		return &Params{
 			F: &HashField{
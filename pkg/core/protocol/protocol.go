@@ -25,6 +25,24 @@ const (
 	// Dkls18Refresh specifies the DKG protocol of the DKLs18 potocol.
 	Dkls18Refresh = "DKLs18-Refresh"
 
+	// CrtSm2Dkg specifies the DKG protocol of the CRT-SM2 threshold signature scheme.
+	CrtSm2Dkg = "CRT-SM2-DKG"
+
+	// CrtSm2Sign specifies the signing protocol of the CRT-SM2 threshold signature scheme.
+	CrtSm2Sign = "CRT-SM2-Sign"
+
+	// CetSm2Dkg specifies the DKG protocol of the Onion-SM2 (CET-SM2) signature scheme.
+	CetSm2Dkg = "CET-SM2-DKG"
+
+	// CetSm2Sign specifies the signing protocol of the Onion-SM2 (CET-SM2) signature scheme.
+	CetSm2Sign = "CET-SM2-Sign"
+
+	// Lnr18Dkg specifies the DKG protocol of the LNR18 threshold ECDSA scheme.
+	Lnr18Dkg = "LNR18-DKG"
+
+	// Lnr18Sign specifies the signing protocol of the LNR18 threshold ECDSA scheme.
+	Lnr18Sign = "LNR18-Sign"
+
 	// versions will increment in 100 intervals, to leave room for adding other versions in between them if it is
 	// ever needed in the future.
 
@@ -0,0 +1,172 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package sm3 implements the SM3 cryptographic hash function
+// as specified in GB/T 32905-2016.
+package sm3
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// Size is the size of an SM3 digest in bytes
+const Size = 32
+
+// BlockSize is the block size of SM3 in bytes
+const BlockSize = 64
+
+var iv = [8]uint32{
+	0x7380166f, 0x4914b2b9, 0x172442d7, 0xda8a0600,
+	0xa96f30bc, 0x163138aa, 0xe38dee4d, 0xb0fb0e4e,
+}
+
+type digest struct {
+	h   [8]uint32
+	x   [BlockSize]byte
+	nx  int
+	len uint64
+}
+
+// New returns a new hash.Hash computing the SM3 digest
+func New() hash.Hash {
+	d := new(digest)
+	d.Reset()
+	return d
+}
+
+// Sum returns the SM3 digest of the data
+func Sum(data []byte) [Size]byte {
+	var d digest
+	d.Reset()
+	_, _ = d.Write(data)
+	var out [Size]byte
+	d.checkSum(&out)
+	return out
+}
+
+func (d *digest) Reset() {
+	d.h = iv
+	d.nx = 0
+	d.len = 0
+}
+
+func (d *digest) Size() int { return Size }
+
+func (d *digest) BlockSize() int { return BlockSize }
+
+func (d *digest) Write(p []byte) (int, error) {
+	nn := len(p)
+	d.len += uint64(nn)
+	if d.nx > 0 {
+		n := copy(d.x[d.nx:], p)
+		d.nx += n
+		if d.nx == BlockSize {
+			block(&d.h, d.x[:])
+			d.nx = 0
+		}
+		p = p[n:]
+	}
+	if len(p) >= BlockSize {
+		n := len(p) &^ (BlockSize - 1)
+		block(&d.h, p[:n])
+		p = p[n:]
+	}
+	if len(p) > 0 {
+		d.nx = copy(d.x[:], p)
+	}
+	return nn, nil
+}
+
+func (d *digest) Sum(in []byte) []byte {
+	// Make a copy so the caller can keep writing and summing
+	d0 := *d
+	var out [Size]byte
+	d0.checkSum(&out)
+	return append(in, out[:]...)
+}
+
+func (d *digest) checkSum(out *[Size]byte) {
+	length := d.len
+	// Padding: a single 1 bit, zeros until 56 mod 64 bytes, then the bit length
+	var tmp [BlockSize + 8]byte
+	tmp[0] = 0x80
+	var padLen int
+	if length%BlockSize < 56 {
+		padLen = int(56 - length%BlockSize)
+	} else {
+		padLen = int(BlockSize + 56 - length%BlockSize)
+	}
+	binary.BigEndian.PutUint64(tmp[padLen:], length<<3)
+	_, _ = d.Write(tmp[:padLen+8])
+
+	for i, v := range d.h {
+		binary.BigEndian.PutUint32(out[4*i:], v)
+	}
+}
+
+func p0(x uint32) uint32 {
+	return x ^ bits.RotateLeft32(x, 9) ^ bits.RotateLeft32(x, 17)
+}
+
+func p1(x uint32) uint32 {
+	return x ^ bits.RotateLeft32(x, 15) ^ bits.RotateLeft32(x, 23)
+}
+
+// block runs the compression function over every complete block in p
+func block(h *[8]uint32, p []byte) {
+	var w [68]uint32
+	var w1 [64]uint32
+	for len(p) >= BlockSize {
+		for i := 0; i < 16; i++ {
+			w[i] = binary.BigEndian.Uint32(p[4*i:])
+		}
+		for i := 16; i < 68; i++ {
+			w[i] = p1(w[i-16]^w[i-9]^bits.RotateLeft32(w[i-3], 15)) ^ bits.RotateLeft32(w[i-13], 7) ^ w[i-6]
+		}
+		for i := 0; i < 64; i++ {
+			w1[i] = w[i] ^ w[i+4]
+		}
+
+		a, b, c, d, e, f, g, hh := h[0], h[1], h[2], h[3], h[4], h[5], h[6], h[7]
+		for j := 0; j < 64; j++ {
+			var tj, ff, gg uint32
+			if j < 16 {
+				tj = 0x79cc4519
+				ff = a ^ b ^ c
+				gg = e ^ f ^ g
+			} else {
+				tj = 0x7a879d8a
+				ff = (a & b) | (a & c) | (b & c)
+				gg = (e & f) | (^e & g)
+			}
+			a12 := bits.RotateLeft32(a, 12)
+			ss1 := bits.RotateLeft32(a12+e+bits.RotateLeft32(tj, j%32), 7)
+			ss2 := ss1 ^ a12
+			tt1 := ff + d + ss2 + w1[j]
+			tt2 := gg + hh + ss1 + w[j]
+			d = c
+			c = bits.RotateLeft32(b, 9)
+			b = a
+			a = tt1
+			hh = g
+			g = bits.RotateLeft32(f, 19)
+			f = e
+			e = p0(tt2)
+		}
+		h[0] ^= a
+		h[1] ^= b
+		h[2] ^= c
+		h[3] ^= d
+		h[4] ^= e
+		h[5] ^= f
+		h[6] ^= g
+		h[7] ^= hh
+
+		p = p[BlockSize:]
+	}
+}
@@ -0,0 +1,60 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package sm3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSM3Vectors(t *testing.T) {
+	tests := []struct {
+		input    []byte
+		expected string
+	}{
+		// GB/T 32905-2016 Appendix A
+		{[]byte("abc"), "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"},
+		{bytes.Repeat([]byte("abcd"), 16), "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732"},
+		{[]byte{}, "1ab21d8355cfa17f8e61194831e81a8f22bec8c728fefb747ed035eb5082aa2b"},
+	}
+	for _, test := range tests {
+		digest := Sum(test.input)
+		require.Equal(t, test.expected, hex.EncodeToString(digest[:]))
+
+		h := New()
+		_, _ = h.Write(test.input)
+		require.Equal(t, test.expected, hex.EncodeToString(h.Sum(nil)))
+	}
+}
+
+func TestSM3IncrementalWrites(t *testing.T) {
+	data := bytes.Repeat([]byte("kryptology"), 50)
+	expected := Sum(data)
+	for _, step := range []int{1, 3, 63, 64, 65, 200} {
+		h := New()
+		for i := 0; i < len(data); i += step {
+			end := i + step
+			if end > len(data) {
+				end = len(data)
+			}
+			_, _ = h.Write(data[i:end])
+		}
+		require.Equal(t, expected[:], h.Sum(nil))
+		// Sum must not change the running state
+		require.Equal(t, expected[:], h.Sum(nil))
+	}
+	h := New()
+	_, _ = h.Write([]byte("garbage"))
+	h.Reset()
+	_, _ = h.Write(data)
+	require.Equal(t, expected[:], h.Sum(nil))
+	require.Equal(t, Size, h.Size())
+	require.Equal(t, BlockSize, h.BlockSize())
+}
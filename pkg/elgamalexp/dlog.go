@@ -0,0 +1,63 @@
+package elgamalexp
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+)
+
+// DLogTable recovers a small message m in [0, bound) from m*basePoint, as returned by SemiDecrypt and
+// ThresholdKey.Combine, with baby-step giant-step. The table of the baby steps is computed once, so that a table can
+// serve many decryptions, e.g. the tally of a vote or the value of a counter.
+type DLogTable struct {
+	bound     uint64
+	steps     uint64
+	babySteps map[string]uint64
+	giantStep curves.Point
+}
+
+// NewDLogTable precomputes the ceil(sqrt(bound)) baby steps j*basePoint
+func NewDLogTable(curve *curves.Curve, basePoint curves.Point, bound uint64) (*DLogTable, error) {
+	if bound == 0 {
+		return nil, fmt.Errorf("empty message space")
+	}
+	if basePoint == nil {
+		basePoint = curve.NewGeneratorPoint()
+	}
+	steps := uint64(math.Ceil(math.Sqrt(float64(bound))))
+	for steps*steps < bound {
+		steps++
+	}
+	table := &DLogTable{
+		bound:     bound,
+		steps:     steps,
+		babySteps: make(map[string]uint64, steps),
+	}
+	point := curve.NewIdentityPoint()
+	for j := uint64(0); j < steps; j++ {
+		table.babySteps[string(point.ToAffineCompressed())] = j
+		point = point.Add(basePoint)
+	}
+	// point is now steps*basePoint
+	table.giantStep = point.Neg()
+	return table, nil
+}
+
+// Solve returns the message m in [0, bound) such that point = m*basePoint
+func (table *DLogTable) Solve(point curves.Point) (uint64, error) {
+	if point == nil {
+		return 0, fmt.Errorf("point missing")
+	}
+	for i := uint64(0); i*table.steps < table.bound; i++ {
+		if j, ok := table.babySteps[string(point.ToAffineCompressed())]; ok {
+			m := i*table.steps + j
+			if m < table.bound {
+				return m, nil
+			}
+			break
+		}
+		point = point.Add(table.giantStep)
+	}
+	return 0, fmt.Errorf("message out of range [0, %d)", table.bound)
+}
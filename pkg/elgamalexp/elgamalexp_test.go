@@ -57,3 +57,147 @@ func TestElGamalExpReRandomizeOverMultipleCurves(t *testing.T) {
 		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
 	}
 }
+
+func TestThresholdDecryption(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+	}
+	sessionId := []byte("threshold decryption")
+	for i, curve := range curveInstances {
+		basePoint := curve.Point.Random(rand.Reader)
+		table, err := NewDLogTable(curve, basePoint, 1000)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+
+		nOfN, nOfNShares, err := KeyGenNOfN(curve, basePoint, 3)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		tOfN, tOfNShares, err := DealTOfN(curve, basePoint, 2, 3)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+
+		for _, tc := range []struct {
+			key    *ThresholdKey
+			shares []*KeyShare
+		}{
+			{nOfN, nOfNShares},
+			{tOfN, tOfNShares},
+			{tOfN, tOfNShares[1:]},
+		} {
+			ciphertext := tc.key.Encryptor().Encrypt(curve.Scalar.New(42), curve.Scalar.Random(rand.Reader))
+			partials := make([]*PartialDecryption, len(tc.shares))
+			for j, share := range tc.shares {
+				partials[j], err = NewThresholdDecryptor(curve, basePoint, share).PartialDecrypt(ciphertext, sessionId)
+				require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+			}
+			semiMessage, err := tc.key.Combine(ciphertext, partials, sessionId)
+			require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+			m, err := table.Solve(semiMessage)
+			require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+			require.Equal(t, uint64(42), m)
+
+			_, err = tc.key.Combine(ciphertext, partials, []byte("other session"))
+			require.Error(t, err)
+			_, err = tc.key.Combine(ciphertext, partials[:tc.key.Threshold-1], sessionId)
+			require.Error(t, err)
+
+			// a wrong partial decryption is caught by its proof
+			forged := *partials[0]
+			forged.D = forged.D.Add(basePoint)
+			forged.Proof.Statement2 = forged.D
+			_, err = tc.key.Combine(ciphertext, append([]*PartialDecryption{&forged}, partials[1:]...), sessionId)
+			require.Error(t, err)
+		}
+	}
+}
+
+func TestAggregateKeys(t *testing.T) {
+	curve := curves.K256()
+	shares := make([]*KeyShare, 3)
+	publicShares := make(map[uint32]curves.Point)
+	for i := range shares {
+		T, d := KeyGen(curve, nil)
+		shares[i] = &KeyShare{Id: uint32(i + 1), Share: d}
+		publicShares[shares[i].Id] = T
+	}
+	key, err := AggregateKeys(curve, nil, publicShares)
+	require.NoError(t, err)
+
+	ciphertext := key.Encryptor().Encrypt(curve.Scalar.New(7), curve.Scalar.Random(rand.Reader))
+	partials := make([]*PartialDecryption, len(shares))
+	for i, share := range shares {
+		partials[i], err = NewThresholdDecryptor(curve, nil, share).PartialDecrypt(ciphertext, nil)
+		require.NoError(t, err)
+	}
+	semiMessage, err := key.Combine(ciphertext, partials, nil)
+	require.NoError(t, err)
+	require.NoError(t, Compare(curve, curve.NewGeneratorPoint(), curve.Scalar.New(7), semiMessage))
+
+	_, err = key.Combine(ciphertext, []*PartialDecryption{partials[0], partials[0], partials[1]}, nil)
+	require.Error(t, err)
+}
+
+func TestDealings(t *testing.T) {
+	curve := curves.P256()
+	basePoint := curve.Point.Random(rand.Reader)
+	const threshold, n = 3, 4
+	dealings := make(map[uint32]*Dealing, n)
+	commitments := make(map[uint32][]curves.Point, n)
+	for dealer := uint32(1); dealer <= n; dealer++ {
+		dealing, err := Deal(curve, basePoint, threshold, n)
+		require.NoError(t, err)
+		dealings[dealer] = dealing
+		commitments[dealer] = dealing.Commitments
+	}
+	key, err := AggregateDealings(curve, basePoint, threshold, n, commitments)
+	require.NoError(t, err)
+
+	shares := make([]*KeyShare, 0, n)
+	for id := uint32(1); id <= n; id++ {
+		dealt := make(map[uint32]curves.Scalar, n)
+		for dealer, dealing := range dealings {
+			dealt[dealer] = dealing.Shares[id]
+		}
+		share, err := CombineDealtShares(curve, basePoint, id, n, dealt, commitments)
+		require.NoError(t, err)
+		require.True(t, basePoint.Mul(share.Share).Equal(key.VerificationKeys[id]))
+		shares = append(shares, share)
+	}
+
+	ciphertext := key.Encryptor().Encrypt(curve.Scalar.New(5), curve.Scalar.Random(rand.Reader))
+	partials := make([]*PartialDecryption, 0, threshold)
+	for _, share := range shares[1:] {
+		partial, err := NewThresholdDecryptor(curve, basePoint, share).PartialDecrypt(ciphertext, nil)
+		require.NoError(t, err)
+		partials = append(partials, partial)
+	}
+	semiMessage, err := key.Combine(ciphertext, partials, nil)
+	require.NoError(t, err)
+	require.NoError(t, Compare(curve, basePoint, curve.Scalar.New(5), semiMessage))
+
+	// a share that does not match the commitments of its dealer is rejected
+	dealt := map[uint32]curves.Scalar{1: dealings[1].Shares[1], 2: dealings[2].Shares[2], 3: dealings[3].Shares[1], 4: dealings[4].Shares[1]}
+	_, err = CombineDealtShares(curve, basePoint, 1, n, dealt, commitments)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "decryptor 2")
+
+	// every decryptor must deal
+	delete(commitments, 4)
+	_, err = AggregateDealings(curve, basePoint, threshold, n, commitments)
+	require.Error(t, err)
+}
+
+func TestDLogTable(t *testing.T) {
+	curve := curves.P256()
+	for _, bound := range []uint64{1, 2, 10, 1000} {
+		table, err := NewDLogTable(curve, nil, bound)
+		require.NoError(t, err)
+		for _, m := range []uint64{0, bound / 2, bound - 1} {
+			solved, err := table.Solve(curve.NewGeneratorPoint().Mul(curve.Scalar.New(int(m))))
+			require.NoError(t, err)
+			require.Equal(t, m, solved)
+		}
+		_, err = table.Solve(curve.NewGeneratorPoint().Mul(curve.Scalar.New(int(bound))))
+		require.Error(t, err)
+	}
+	_, err := NewDLogTable(curve, nil, 0)
+	require.Error(t, err)
+}
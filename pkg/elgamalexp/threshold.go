@@ -0,0 +1,373 @@
+package elgamalexp
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/sharing"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+)
+
+// KeyShare is the share of the decryption key d held by the decryptor with the given id
+type KeyShare struct {
+	Id    uint32
+	Share curves.Scalar
+}
+
+// ThresholdKey is the public side of a shared decryption key: the encryption key T and, for every decryptor, its
+// verification key d_i*basePoint, against which its partial decryptions are checked.
+// With additive shares all the decryptors are needed, otherwise any Threshold of them.
+type ThresholdKey struct {
+	curve            *curves.Curve
+	basePoint        curves.Point
+	additive         bool
+	T                curves.Point
+	Threshold        uint32
+	VerificationKeys map[uint32]curves.Point
+}
+
+// PartialDecryption is the share d_i*U of the decryption of a ciphertext (U, V) by the decryptor with the given id,
+// with the proof that it uses the same d_i as the verification key of the decryptor
+type PartialDecryption struct {
+	Id    uint32
+	D     curves.Point
+	Proof *chaumpedersen.Proof
+}
+
+// ThresholdDecryptor computes partial decryptions with its key share
+type ThresholdDecryptor struct {
+	curve     *curves.Curve
+	basePoint curves.Point
+	share     *KeyShare
+}
+
+// KeyGenNOfN deals additive shares of a random decryption key to the decryptors 1 to n, all of whom are needed to
+// decrypt. The caller is a trusted dealer that learns the key: decryptors that do not trust anyone generate their own
+// shares with KeyGen and combine them with AggregateKeys.
+func KeyGenNOfN(curve *curves.Curve, basePoint curves.Point, n uint32) (*ThresholdKey, []*KeyShare, error) {
+	if n < 2 {
+		return nil, nil, fmt.Errorf("at least two decryptors are needed")
+	}
+	if basePoint == nil {
+		basePoint = curve.NewGeneratorPoint()
+	}
+	shares := make([]*KeyShare, n)
+	publicShares := make(map[uint32]curves.Point, n)
+	for i := range shares {
+		id := uint32(i + 1)
+		shares[i] = &KeyShare{Id: id, Share: curve.Scalar.Random(rand.Reader)}
+		publicShares[id] = basePoint.Mul(shares[i].Share)
+	}
+	key, err := AggregateKeys(curve, basePoint, publicShares)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, shares, nil
+}
+
+// DealTOfN deals Shamir shares of a random decryption key to the decryptors 1 to n, any t of whom can decrypt.
+// The caller is a trusted dealer that learns the key and must erase it: decryptors that do not trust anyone generate
+// the key together with Deal instead.
+func DealTOfN(curve *curves.Curve, basePoint curves.Point, t uint32, n uint32) (*ThresholdKey, []*KeyShare, error) {
+	shamir, err := sharing.NewShamir(t, n, curve)
+	if err != nil {
+		return nil, nil, err
+	}
+	if basePoint == nil {
+		basePoint = curve.NewGeneratorPoint()
+	}
+	T, d := KeyGen(curve, basePoint)
+	shamirShares, err := shamir.Split(d, rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := &ThresholdKey{
+		curve:            curve,
+		basePoint:        basePoint,
+		T:                T,
+		Threshold:        t,
+		VerificationKeys: make(map[uint32]curves.Point, n),
+	}
+	shares := make([]*KeyShare, n)
+	for i, shamirShare := range shamirShares {
+		share, err := curve.Scalar.SetBytes(shamirShare.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+		shares[i] = &KeyShare{Id: shamirShare.Id, Share: share}
+		key.VerificationKeys[shamirShare.Id] = basePoint.Mul(share)
+	}
+	return key, shares, nil
+}
+
+// AggregateKeys returns the n-of-n key whose decryptors generated their own shares with KeyGen and published the
+// public keys in publicShares. The caller is responsible for checking that each decryptor knows its share, for
+// instance with a schnorr proof, since otherwise the last one to publish can choose T.
+func AggregateKeys(curve *curves.Curve, basePoint curves.Point, publicShares map[uint32]curves.Point) (*ThresholdKey, error) {
+	if len(publicShares) < 2 {
+		return nil, fmt.Errorf("at least two decryptors are needed")
+	}
+	if basePoint == nil {
+		basePoint = curve.NewGeneratorPoint()
+	}
+	key := &ThresholdKey{
+		curve:            curve,
+		basePoint:        basePoint,
+		additive:         true,
+		T:                curve.NewIdentityPoint(),
+		Threshold:        uint32(len(publicShares)),
+		VerificationKeys: make(map[uint32]curves.Point, len(publicShares)),
+	}
+	for id, publicShare := range publicShares {
+		if id == 0 || publicShare == nil {
+			return nil, fmt.Errorf("invalid public share of decryptor %d", id)
+		}
+		key.T = key.T.Add(publicShare)
+		key.VerificationKeys[id] = publicShare
+	}
+	return key, nil
+}
+
+// Dealing is the contribution of a decryptor to a t-of-n key that no single party knows: the Feldman commitments
+// a_k*basePoint to the coefficients of its random polynomial f of degree t-1, which it broadcasts, and the shares
+// f(j), each of which it sends to the decryptor j over a private channel
+type Dealing struct {
+	Commitments []curves.Point
+	Shares      map[uint32]curves.Scalar
+}
+
+// Deal returns the contribution of a decryptor to a t-of-n key of the decryptors 1 to n, without a trusted dealer.
+// Every decryptor deals, adds up the shares it receives with CombineDealtShares and computes the key from the
+// commitments of all the dealers with AggregateDealings. The commitments must be broadcast, so that every decryptor
+// checks its share against the same ones. As in Pedersen's DKG, a dealer that broadcasts last can bias T unless the
+// dealers commit to their commitments first.
+func Deal(curve *curves.Curve, basePoint curves.Point, t uint32, n uint32) (*Dealing, error) {
+	if _, err := sharing.NewShamir(t, n, curve); err != nil {
+		return nil, err
+	}
+	if basePoint == nil {
+		basePoint = curve.NewGeneratorPoint()
+	}
+	polynomial := new(sharing.Polynomial).Init(curve.Scalar.Random(rand.Reader), t, rand.Reader)
+	dealing := &Dealing{
+		Commitments: make([]curves.Point, t),
+		Shares:      make(map[uint32]curves.Scalar, n),
+	}
+	for k, coefficient := range polynomial.Coefficients {
+		dealing.Commitments[k] = basePoint.Mul(coefficient)
+	}
+	for id := uint32(1); id <= n; id++ {
+		dealing.Shares[id] = polynomial.Evaluate(curve.Scalar.New(int(id)))
+	}
+	return dealing, nil
+}
+
+// CombineDealtShares checks the shares dealt to the decryptor id, by dealer, against the commitments of their dealers
+// and adds them up to the key share of the decryptor. Every decryptor 1 to n must have dealt.
+func CombineDealtShares(curve *curves.Curve, basePoint curves.Point, id uint32, n uint32, shares map[uint32]curves.Scalar, commitments map[uint32][]curves.Point) (*KeyShare, error) {
+	if basePoint == nil {
+		basePoint = curve.NewGeneratorPoint()
+	}
+	if err := checkDealers(n, commitments); err != nil {
+		return nil, err
+	}
+	if id == 0 || id > n {
+		return nil, fmt.Errorf("invalid decryptor %d", id)
+	}
+	if len(shares) != len(commitments) {
+		return nil, fmt.Errorf("expected %d dealt shares, got %d", len(commitments), len(shares))
+	}
+	keyShare := &KeyShare{Id: id, Share: curve.Scalar.Zero()}
+	for dealer, share := range shares {
+		dealerCommitments, ok := commitments[dealer]
+		if !ok || share == nil {
+			return nil, fmt.Errorf("invalid share dealt by decryptor %d", dealer)
+		}
+		// the share is f(id) for the polynomial f of the dealer
+		if !basePoint.Mul(share).Equal(evaluateCommitments(curve, dealerCommitments, id)) {
+			return nil, fmt.Errorf("the share dealt by decryptor %d does not match its commitments", dealer)
+		}
+		keyShare.Share = keyShare.Share.Add(share)
+	}
+	return keyShare, nil
+}
+
+// AggregateDealings returns the t-of-n key whose decryptors 1 to n all dealt with Deal and broadcast the commitments,
+// by dealer. The decryptors must only use the key once all of them have accepted their shares with
+// CombineDealtShares, since a dealer whose shares do not match its commitments leaves the others unable to decrypt.
+func AggregateDealings(curve *curves.Curve, basePoint curves.Point, t uint32, n uint32, commitments map[uint32][]curves.Point) (*ThresholdKey, error) {
+	if _, err := sharing.NewShamir(t, n, curve); err != nil {
+		return nil, err
+	}
+	if basePoint == nil {
+		basePoint = curve.NewGeneratorPoint()
+	}
+	if err := checkDealers(n, commitments); err != nil {
+		return nil, err
+	}
+	// the commitments to the coefficients of the sum of the polynomials of the dealers
+	sum := make([]curves.Point, t)
+	for k := range sum {
+		sum[k] = curve.NewIdentityPoint()
+	}
+	for dealer, dealerCommitments := range commitments {
+		if len(dealerCommitments) != int(t) {
+			return nil, fmt.Errorf("expected %d commitments of decryptor %d, got %d", t, dealer, len(dealerCommitments))
+		}
+		for k, commitment := range dealerCommitments {
+			sum[k] = sum[k].Add(commitment)
+		}
+	}
+	key := &ThresholdKey{
+		curve:            curve,
+		basePoint:        basePoint,
+		T:                sum[0],
+		Threshold:        t,
+		VerificationKeys: make(map[uint32]curves.Point, n),
+	}
+	if key.T.IsIdentity() {
+		return nil, fmt.Errorf("the encryption key is the identity")
+	}
+	for id := uint32(1); id <= n; id++ {
+		key.VerificationKeys[id] = evaluateCommitments(curve, sum, id)
+	}
+	return key, nil
+}
+
+// checkDealers checks that the decryptors 1 to n all committed to a polynomial
+func checkDealers(n uint32, commitments map[uint32][]curves.Point) error {
+	if len(commitments) != int(n) {
+		return fmt.Errorf("expected the commitments of %d decryptors, got %d", n, len(commitments))
+	}
+	for dealer, dealerCommitments := range commitments {
+		if dealer == 0 || dealer > n || len(dealerCommitments) == 0 {
+			return fmt.Errorf("invalid commitments of decryptor %d", dealer)
+		}
+		for _, commitment := range dealerCommitments {
+			if commitment == nil {
+				return fmt.Errorf("invalid commitments of decryptor %d", dealer)
+			}
+		}
+	}
+	return nil
+}
+
+// evaluateCommitments returns f(id)*basePoint for the polynomial f whose coefficients are committed to
+func evaluateCommitments(curve *curves.Curve, commitments []curves.Point, id uint32) curves.Point {
+	x := curve.Scalar.New(int(id))
+	result := commitments[len(commitments)-1]
+	for k := len(commitments) - 2; k >= 0; k-- {
+		result = result.Mul(x).Add(commitments[k])
+	}
+	return result
+}
+
+// Encryptor returns the encryptor for the key
+func (key *ThresholdKey) Encryptor() *Encryptor {
+	return NewEncryptor(key.curve, key.basePoint, key.T)
+}
+
+// NewThresholdDecryptor returns the decryptor holding the given share
+func NewThresholdDecryptor(curve *curves.Curve, basePoint curves.Point, share *KeyShare) *ThresholdDecryptor {
+	if basePoint == nil {
+		basePoint = curve.NewGeneratorPoint()
+	}
+	return &ThresholdDecryptor{
+		curve:     curve,
+		basePoint: basePoint,
+		share:     share,
+	}
+}
+
+// PartialDecrypt computes the partial decryption of the ciphertext with a proof bound to the session id
+func (decryptor *ThresholdDecryptor) PartialDecrypt(ciphertext *Ciphertext, sessionId []byte) (*PartialDecryption, error) {
+	if ciphertext == nil || ciphertext.U == nil || ciphertext.V == nil {
+		return nil, fmt.Errorf("ciphertext missing")
+	}
+	prover, err := chaumpedersen.NewProver(decryptor.curve, decryptor.basePoint, ciphertext.U, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := prover.Prove(decryptor.share.Share)
+	if err != nil {
+		return nil, errors.Wrap(err, "proving the partial decryption")
+	}
+	return &PartialDecryption{
+		Id:    decryptor.share.Id,
+		D:     proof.Statement2,
+		Proof: proof,
+	}, nil
+}
+
+// VerifyPartialDecryption checks the proof of a partial decryption of the ciphertext against the verification key of
+// its decryptor
+func (key *ThresholdKey) VerifyPartialDecryption(ciphertext *Ciphertext, partial *PartialDecryption, sessionId []byte) error {
+	if ciphertext == nil || ciphertext.U == nil || ciphertext.V == nil {
+		return fmt.Errorf("ciphertext missing")
+	}
+	if partial == nil || partial.D == nil || partial.Proof == nil {
+		return fmt.Errorf("partial decryption missing")
+	}
+	verificationKey, ok := key.VerificationKeys[partial.Id]
+	if !ok {
+		return fmt.Errorf("unknown decryptor %d", partial.Id)
+	}
+	if partial.Proof.Statement1 == nil || !partial.Proof.Statement1.Equal(verificationKey) ||
+		partial.Proof.Statement2 == nil || !partial.Proof.Statement2.Equal(partial.D) {
+		return fmt.Errorf("the proof of decryptor %d is for another statement", partial.Id)
+	}
+	if err := chaumpedersen.Verify(partial.Proof, key.curve, key.basePoint, ciphertext.U, sessionId); err != nil {
+		return errors.Wrapf(err, "partial decryption of decryptor %d", partial.Id)
+	}
+	return nil
+}
+
+// Combine verifies the partial decryptions of the ciphertext and combines them into m*basePoint, like SemiDecrypt.
+// It needs the partial decryptions of all the decryptors of an n-of-n key, and of at least Threshold distinct
+// decryptors otherwise.
+func (key *ThresholdKey) Combine(ciphertext *Ciphertext, partials []*PartialDecryption, sessionId []byte) (curves.Point, error) {
+	if len(partials) < int(key.Threshold) {
+		return nil, fmt.Errorf("expected %d partial decryptions, got %d", key.Threshold, len(partials))
+	}
+	byId := make(map[uint32]*PartialDecryption, len(partials))
+	for _, partial := range partials {
+		if err := key.VerifyPartialDecryption(ciphertext, partial, sessionId); err != nil {
+			return nil, err
+		}
+		if _, ok := byId[partial.Id]; ok {
+			return nil, fmt.Errorf("duplicate partial decryption of decryptor %d", partial.Id)
+		}
+		byId[partial.Id] = partial
+	}
+	ids := make([]uint32, 0, len(byId))
+	for id := range byId {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	// d*U is the sum of the partial decryptions, weighted by the Lagrange coefficients for Shamir shares
+	dU := key.curve.NewIdentityPoint()
+	if key.additive {
+		for _, id := range ids {
+			dU = dU.Add(byId[id].D)
+		}
+	} else {
+		ids = ids[:key.Threshold]
+		shamir, err := sharing.NewShamir(key.Threshold, uint32(len(key.VerificationKeys)), key.curve)
+		if err != nil {
+			return nil, err
+		}
+		coefficients, err := shamir.LagrangeCoeffs(ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			dU = dU.Add(byId[id].D.Mul(coefficients[id]))
+		}
+	}
+	return ciphertext.V.Sub(dU), nil
+}
@@ -50,6 +50,25 @@ const (
 	cOtExtendedBlockSizeBytes = lPrime >> 3
 )
 
+// ScalarBytes returns the number of pseudorandom bytes from which a single scalar of `curve` is derived.
+// Curves with 32 byte scalars use one digest, as in the original protocol. Wider scalars use twice their width, which
+// is then reduced modulo q; this keeps the bias of the result negligible.
+func ScalarBytes(curve *curves.Curve) int {
+	width := len(curve.Scalar.Bytes())
+	if width <= simplest.DigestSize {
+		return simplest.DigestSize
+	}
+	return 2 * width
+}
+
+// NewScalar derives a scalar of `curve` from `ScalarBytes(curve)` pseudorandom bytes.
+func NewScalar(curve *curves.Curve, bytes []byte) (curves.Scalar, error) {
+	if len(curve.Scalar.Bytes()) <= simplest.DigestSize {
+		return curve.Scalar.SetBytes(bytes)
+	}
+	return curve.Scalar.SetBytesWide(bytes)
+}
+
 type Receiver struct {
 	// OutputAdditiveShares are the ultimate output received. basically just the "pads".
 	OutputAdditiveShares [L][OtWidth]curves.Scalar
@@ -316,8 +335,9 @@ func (sender *Sender) Round2Transfer(uniqueSessionId [simplest.DigestSize]byte,
 		return nil, fmt.Errorf("cOT receiver's consistency check failed; this may be an attempted attack; do NOT re-run the protocol")
 	}
 	result := &Round2Output{}
+	scalarBytes := ScalarBytes(sender.curve)
 	for j := 0; j < L; j++ {
-		column := make([]byte, OtWidth*simplest.DigestSize)
+		column := make([]byte, OtWidth*scalarBytes)
 		shake := sha3.NewCShake256(uniqueSessionId[:], []byte("Coinbase_DKLs_cOT"))
 		jBytes := [2]byte{}
 		binary.BigEndian.PutUint16(jBytes[:], uint16(j))
@@ -332,7 +352,7 @@ func (sender *Sender) Round2Transfer(uniqueSessionId [simplest.DigestSize]byte,
 		}
 		var err error
 		for k := 0; k < OtWidth; k++ {
-			sender.OutputAdditiveShares[j][k], err = sender.curve.Scalar.SetBytes(column[k*simplest.DigestSize : (k+1)*simplest.DigestSize])
+			sender.OutputAdditiveShares[j][k], err = NewScalar(sender.curve, column[k*scalarBytes:(k+1)*scalarBytes])
 			if err != nil {
 				return nil, errors.Wrap(err, "OutputAdditiveShares scalar from bytes")
 			}
@@ -340,7 +360,7 @@ func (sender *Sender) Round2Transfer(uniqueSessionId [simplest.DigestSize]byte,
 		for i := 0; i < KappaBytes; i++ {
 			zeta[j][i] ^= sender.seedOtResults.PackedRandomChoiceBits[i] // note: overwrites zeta_j. just using it as a place to store
 		}
-		column = make([]byte, OtWidth*simplest.DigestSize)
+		column = make([]byte, OtWidth*scalarBytes)
 		shake = sha3.NewCShake256(uniqueSessionId[:], []byte("Coinbase_DKLs_cOT"))
 		binary.BigEndian.PutUint16(jBytes[:], uint16(j))
 		if _, err := shake.Write(jBytes[:]); err != nil { // write j into hash
@@ -353,7 +373,7 @@ func (sender *Sender) Round2Transfer(uniqueSessionId [simplest.DigestSize]byte,
 			return nil, errors.Wrap(err, "reading shake into column while computing tau in cOT sender round 2 transfer")
 		}
 		for k := 0; k < OtWidth; k++ {
-			result.Tau[j][k], err = sender.curve.Scalar.SetBytes(column[k*simplest.DigestSize : (k+1)*simplest.DigestSize])
+			result.Tau[j][k], err = NewScalar(sender.curve, column[k*scalarBytes:(k+1)*scalarBytes])
 			if err != nil {
 				return nil, errors.Wrap(err, "scalar Tau from bytes")
 			}
@@ -366,8 +386,9 @@ func (sender *Sender) Round2Transfer(uniqueSessionId [simplest.DigestSize]byte,
 
 // Round3Transfer does the receiver (Bob)'s step 7) of Protocol 9, namely the computation of the outputs tB.
 func (receiver *Receiver) Round3Transfer(round2Output *Round2Output) error {
+	scalarBytes := ScalarBytes(receiver.curve)
 	for j := 0; j < L; j++ {
-		column := make([]byte, OtWidth*simplest.DigestSize)
+		column := make([]byte, OtWidth*scalarBytes)
 		shake := sha3.NewCShake256(receiver.uniqueSessionId[:], []byte("Coinbase_DKLs_cOT"))
 		jBytes := [2]byte{}
 		binary.BigEndian.PutUint16(jBytes[:], uint16(j))
@@ -383,7 +404,7 @@ func (receiver *Receiver) Round3Transfer(round2Output *Round2Output) error {
 		bit := int(simplest.ExtractBitFromByteVector(receiver.extendedPackedChoices[:], j))
 		var err error
 		for k := 0; k < OtWidth; k++ {
-			receiver.OutputAdditiveShares[j][k], err = receiver.curve.Scalar.SetBytes(column[k*simplest.DigestSize : (k+1)*simplest.DigestSize])
+			receiver.OutputAdditiveShares[j][k], err = NewScalar(receiver.curve, column[k*scalarBytes:(k+1)*scalarBytes])
 			if err != nil {
 				return errors.Wrap(err, "scalar output additive shares from bytes")
 			}
@@ -34,6 +34,8 @@ func TestCOTExtension(t *testing.T) {
 	curveInstances := []*curves.Curve{
 		curves.K256(),
 		curves.P256(),
+		curves.P384(),
+		curves.P521(),
 	}
 	for _, curve := range curveInstances {
 		uniqueSessionId := [simplest.DigestSize]byte{}
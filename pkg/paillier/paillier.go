@@ -76,6 +76,11 @@ func NewKeys() (*PublicKey, *SecretKey, error) {
 	return keyGenerator(core.GenerateSafePrime, PaillierPrimeBits)
 }
 
+// NewKeysWithPrimeBits generates Paillier keys with safe primes of the given size.
+func NewKeysWithPrimeBits(bits uint) (*PublicKey, *SecretKey, error) {
+	return keyGenerator(core.GenerateSafePrime, bits)
+}
+
 // keyGenerator generates Paillier keys with `bits` sized safe primes using function
 // `genSafePrime` to generate the safe primes.
 func keyGenerator(genSafePrime func(uint) (*big.Int, error), bits uint) (*PublicKey, *SecretKey, error) {
@@ -595,6 +595,13 @@ func TestNewKeysDistinct(t *testing.T) {
 	require.NotEqual(t, sec1.U, sec2.U)
 }
 
+func TestNewKeysWithPrimeBits(t *testing.T) {
+	pub, sec, err := NewKeysWithPrimeBits(128)
+	require.NoError(t, err)
+	require.Contains(t, []int{255, 256}, pub.N.BitLen())
+	require.Equal(t, pub.N, sec.N)
+}
+
 // Tests the restrictions on input values for paillier.Add
 func TestAddErrorConditions(t *testing.T) {
 	pk, err := NewPubkey(N)
@@ -0,0 +1,170 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package sm2
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/sm3"
+)
+
+// Ciphertext is an SM2 ciphertext from GB/T 32918.4-2016, where C1 = kG carries the ephemeral key,
+// C3 = SM3(x2 || M || y2) authenticates the plaintext and C2 = M xor KDF(x2 || y2) hides it
+type Ciphertext struct {
+	C1 curves.Point
+	C3 []byte
+	C2 []byte
+}
+
+// Encrypt encrypts plaintext to the public key pk
+func Encrypt(curve *curves.Curve, pk curves.Point, plaintext []byte) (*Ciphertext, error) {
+	if pk == nil || pk.IsIdentity() || !pk.IsOnCurve() {
+		return nil, fmt.Errorf("invalid public key")
+	}
+	if len(plaintext) == 0 {
+		return nil, fmt.Errorf("plaintext is empty")
+	}
+	for {
+		k := curve.Scalar.Random(rand.Reader)
+		if k.IsZero() {
+			continue
+		}
+		x2, y2 := coordinates(pk.Mul(k))
+		t := kdf(append(x2, y2...), len(plaintext))
+		if allZero(t) {
+			continue
+		}
+		return &Ciphertext{
+			C1: curve.ScalarBaseMult(k),
+			C3: hashC3(x2, plaintext, y2),
+			C2: xor(plaintext, t),
+		}, nil
+	}
+}
+
+// Decrypt decrypts ct with the secret key sk
+func Decrypt(curve *curves.Curve, sk curves.Scalar, ct *Ciphertext) ([]byte, error) {
+	if sk == nil || sk.IsZero() {
+		return nil, fmt.Errorf("invalid secret key")
+	}
+	if err := ct.validate(); err != nil {
+		return nil, err
+	}
+	return DecryptWithPoint(ct, ct.C1.Mul(sk))
+}
+
+// DecryptWithPoint recovers the plaintext of ct from the point sk*C1, which may have been computed by parties
+// that hold shares of sk. It fails unless the plaintext matches C3.
+func DecryptWithPoint(ct *Ciphertext, point curves.Point) ([]byte, error) {
+	if err := ct.validate(); err != nil {
+		return nil, err
+	}
+	if point == nil || point.IsIdentity() {
+		return nil, fmt.Errorf("invalid decryption point")
+	}
+	x2, y2 := coordinates(point)
+	t := kdf(append(x2, y2...), len(ct.C2))
+	if allZero(t) {
+		return nil, fmt.Errorf("decryption failed")
+	}
+	plaintext := xor(ct.C2, t)
+	if subtle.ConstantTimeCompare(hashC3(x2, plaintext, y2), ct.C3) != 1 {
+		return nil, fmt.Errorf("decryption failed")
+	}
+	return plaintext, nil
+}
+
+// Bytes encodes the ciphertext as C1 || C3 || C2 with C1 in the uncompressed form
+func (ct *Ciphertext) Bytes() []byte {
+	var buf bytes.Buffer
+	buf.Write(ct.C1.ToAffineUncompressed())
+	buf.Write(ct.C3)
+	buf.Write(ct.C2)
+	return buf.Bytes()
+}
+
+// ParseCiphertext decodes a ciphertext encoded as C1 || C3 || C2
+func ParseCiphertext(curve *curves.Curve, data []byte) (*Ciphertext, error) {
+	pointSize := len(curve.NewGeneratorPoint().ToAffineUncompressed())
+	if len(data) <= pointSize+sm3.Size {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	c1, err := curve.Point.FromAffineUncompressed(data[:pointSize])
+	if err != nil {
+		return nil, err
+	}
+	ct := &Ciphertext{
+		C1: c1,
+		C3: append([]byte{}, data[pointSize:pointSize+sm3.Size]...),
+		C2: append([]byte{}, data[pointSize+sm3.Size:]...),
+	}
+	if err = ct.validate(); err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
+func (ct *Ciphertext) validate() error {
+	if ct == nil || ct.C1 == nil || ct.C1.IsIdentity() || !ct.C1.IsOnCurve() {
+		return fmt.Errorf("invalid ciphertext")
+	}
+	if len(ct.C3) != sm3.Size || len(ct.C2) == 0 {
+		return fmt.Errorf("invalid ciphertext")
+	}
+	return nil
+}
+
+// coordinates returns the big-endian affine coordinates of point
+func coordinates(point curves.Point) ([]byte, []byte) {
+	uncompressed := point.ToAffineUncompressed()
+	size := (len(uncompressed) - 1) / 2
+	return uncompressed[1 : 1+size : 1+size], uncompressed[1+size:]
+}
+
+// kdf is the key derivation function of GB/T 32918.4 built on SM3, which outputs length bytes
+func kdf(z []byte, length int) []byte {
+	out := make([]byte, 0, length+sm3.Size)
+	var ct [4]byte
+	for counter := uint32(1); len(out) < length; counter++ {
+		binary.BigEndian.PutUint32(ct[:], counter)
+		h := sm3.New()
+		_, _ = h.Write(z)
+		_, _ = h.Write(ct[:])
+		out = h.Sum(out)
+	}
+	return out[:length]
+}
+
+func hashC3(x2, plaintext, y2 []byte) []byte {
+	h := sm3.New()
+	_, _ = h.Write(x2)
+	_, _ = h.Write(plaintext)
+	_, _ = h.Write(y2)
+	return h.Sum(nil)
+}
+
+func xor(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+func allZero(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
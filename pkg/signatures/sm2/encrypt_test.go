@@ -0,0 +1,97 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package sm2
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+)
+
+// Ciphertext produced by the OpenSSL 3.0 SM2 implementation, re-encoded from its ASN.1 form as C1 || C3 || C2
+func TestDecryptOpenSSLVector(t *testing.T) {
+	curve := curves.SM2()
+	d := hexScalar(t, curve, "90b6b6d62d403f496949fd339f149d95d087512f060639c177a63420782afb0d")
+	data, err := hex.DecodeString("04" +
+		"0c72c7fff4b0a121dc6ef4efdcc439271a8d169e2fa17b71e03683a540318865" +
+		"1282b77adf47b7d535006aed3411499363507275de28a9dfcbe5546e02245a10" +
+		"e8a6eaef4c9b642d2adc56c8dcdbd53d81ed6d880bb3277e5762a4292d2f0d9e" +
+		"f721bd18e6528ac2bc74c414506d43c934f674")
+	require.NoError(t, err)
+
+	ct, err := ParseCiphertext(curve, data)
+	require.NoError(t, err)
+	require.Equal(t, data, ct.Bytes())
+	plaintext, err := Decrypt(curve, d, ct)
+	require.NoError(t, err)
+	require.Equal(t, "encryption standard", string(plaintext))
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	for _, curve := range []*curves.Curve{curves.SM2(), curves.K256(), curves.P256()} {
+		sk, pk := NewKeys(curve)
+		// lengths around the size of an SM3 block exercise the counter of the KDF
+		for _, length := range []int{1, 31, 32, 33, 100} {
+			plaintext := make([]byte, length)
+			for i := range plaintext {
+				plaintext[i] = byte(i)
+			}
+			ct, err := Encrypt(curve, pk, plaintext)
+			require.NoError(t, err)
+			require.Len(t, ct.C2, length)
+
+			decrypted, err := Decrypt(curve, sk, ct)
+			require.NoError(t, err)
+			require.Equal(t, plaintext, decrypted)
+
+			parsed, err := ParseCiphertext(curve, ct.Bytes())
+			require.NoError(t, err)
+			decrypted, err = DecryptWithPoint(parsed, parsed.C1.Mul(sk))
+			require.NoError(t, err)
+			require.Equal(t, plaintext, decrypted)
+		}
+	}
+}
+
+func TestDecryptRejects(t *testing.T) {
+	curve := curves.SM2()
+	sk, pk := NewKeys(curve)
+	ct, err := Encrypt(curve, pk, []byte("test message"))
+	require.NoError(t, err)
+
+	otherSk, _ := NewKeys(curve)
+	_, err = Decrypt(curve, otherSk, ct)
+	require.Error(t, err)
+
+	c2 := append([]byte{}, ct.C2...)
+	c2[0] ^= 1
+	_, err = Decrypt(curve, sk, &Ciphertext{C1: ct.C1, C3: ct.C3, C2: c2})
+	require.Error(t, err)
+
+	c3 := append([]byte{}, ct.C3...)
+	c3[0] ^= 1
+	_, err = Decrypt(curve, sk, &Ciphertext{C1: ct.C1, C3: c3, C2: ct.C2})
+	require.Error(t, err)
+
+	_, err = Decrypt(curve, sk, &Ciphertext{C1: curve.NewIdentityPoint(), C3: ct.C3, C2: ct.C2})
+	require.Error(t, err)
+
+	_, err = Encrypt(curve, pk, nil)
+	require.Error(t, err)
+	_, err = Encrypt(curve, curve.NewIdentityPoint(), []byte("test message"))
+	require.Error(t, err)
+
+	data := ct.Bytes()
+	_, err = ParseCiphertext(curve, data[:65+32])
+	require.Error(t, err)
+	data[1] ^= 1
+	_, err = ParseCiphertext(curve, data)
+	require.Error(t, err)
+}
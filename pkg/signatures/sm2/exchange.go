@@ -0,0 +1,212 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package sm2
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/sm3"
+)
+
+// ExchangeXBar computes x̄ = 2^w + (x mod 2^w) of the x-coordinate of the ephemeral key R,
+// where w = ceil(ceil(log2(n)) / 2) - 1
+func ExchangeXBar(curve *curves.Curve, R curves.Point) (curves.Scalar, error) {
+	if R == nil || R.IsIdentity() || !R.IsOnCurve() {
+		return nil, fmt.Errorf("invalid ephemeral key")
+	}
+	ec, err := curve.ToEllipticCurve()
+	if err != nil {
+		return nil, err
+	}
+	w := uint((ec.Params().N.BitLen()+1)/2 - 1)
+	x, _ := coordinates(R)
+	twoW := new(big.Int).Lsh(big.NewInt(1), w)
+	xBar := new(big.Int).SetBytes(x)
+	xBar.And(xBar, new(big.Int).Sub(twoW, big.NewInt(1)))
+	return curve.Scalar.SetBigInt(xBar.Add(xBar, twoW))
+}
+
+// ExchangeBase computes P + x̄ R from the static public key P and the ephemeral key R of the peer.
+// The shared point is the product of this base with t = d + x̄ r, where d and r are the own static and ephemeral keys.
+func ExchangeBase(curve *curves.Curve, peerPk curves.Point, peerR curves.Point) (curves.Point, error) {
+	if peerPk == nil || peerPk.IsIdentity() || !peerPk.IsOnCurve() {
+		return nil, fmt.Errorf("invalid public key")
+	}
+	xBar, err := ExchangeXBar(curve, peerR)
+	if err != nil {
+		return nil, err
+	}
+	return peerPk.Add(peerR.Mul(xBar)), nil
+}
+
+// ExchangeTranscript holds the public values of a key exchange from GB/T 32918.3-2016:
+// the digests Z and the ephemeral keys of the initiator A and the responder B
+type ExchangeTranscript struct {
+	ZA, ZB []byte
+	RA, RB curves.Point
+}
+
+// NewExchangeTranscript orders the digest and ephemeral key of one side and those of its peer by role
+func NewExchangeTranscript(initiator bool, z []byte, R curves.Point, peerZ []byte, peerR curves.Point) *ExchangeTranscript {
+	if initiator {
+		return &ExchangeTranscript{ZA: z, ZB: peerZ, RA: R, RB: peerR}
+	}
+	return &ExchangeTranscript{ZA: peerZ, ZB: z, RA: peerR, RB: R}
+}
+
+// Key derives a key of keyLen bytes from the shared point U = V
+func (tr *ExchangeTranscript) Key(shared curves.Point, keyLen int) []byte {
+	x, y := coordinates(shared)
+	z := make([]byte, 0, len(x)+len(y)+len(tr.ZA)+len(tr.ZB))
+	z = append(z, x...)
+	z = append(z, y...)
+	z = append(z, tr.ZA...)
+	z = append(z, tr.ZB...)
+	return kdf(z, keyLen)
+}
+
+// ResponderConfirmation computes S_B, which the responder sends and the initiator checks as S1
+func (tr *ExchangeTranscript) ResponderConfirmation(shared curves.Point) []byte {
+	return tr.confirmation(0x02, shared)
+}
+
+// InitiatorConfirmation computes S_A, which the initiator sends and the responder checks as S2
+func (tr *ExchangeTranscript) InitiatorConfirmation(shared curves.Point) []byte {
+	return tr.confirmation(0x03, shared)
+}
+
+// confirmation computes Hash(prefix || y || Hash(x || Z_A || Z_B || x1 || y1 || x2 || y2))
+func (tr *ExchangeTranscript) confirmation(prefix byte, shared curves.Point) []byte {
+	x, y := coordinates(shared)
+	x1, y1 := coordinates(tr.RA)
+	x2, y2 := coordinates(tr.RB)
+	inner := sm3.New()
+	for _, v := range [][]byte{x, tr.ZA, tr.ZB, x1, y1, x2, y2} {
+		_, _ = inner.Write(v)
+	}
+	outer := sm3.New()
+	_, _ = outer.Write([]byte{prefix})
+	_, _ = outer.Write(y)
+	_, _ = outer.Write(inner.Sum(nil))
+	return outer.Sum(nil)
+}
+
+// VerifyConfirmation checks the confirmation that was sent by the initiator if fromInitiator is set,
+// or by the responder otherwise
+func (tr *ExchangeTranscript) VerifyConfirmation(fromInitiator bool, shared curves.Point, confirmation []byte) error {
+	expected := tr.ResponderConfirmation(shared)
+	if fromInitiator {
+		expected = tr.InitiatorConfirmation(shared)
+	}
+	if subtle.ConstantTimeCompare(expected, confirmation) != 1 {
+		return fmt.Errorf("key confirmation failed")
+	}
+	return nil
+}
+
+// KeyExchange is one side of an SM2 key exchange. The curves supported here have a cofactor of 1.
+type KeyExchange struct {
+	curve     *curves.Curve
+	initiator bool
+
+	sk     curves.Scalar
+	z      []byte
+	peerPk curves.Point
+	peerZ  []byte
+
+	r curves.Scalar
+	R curves.Point
+
+	transcript *ExchangeTranscript
+	shared     curves.Point
+}
+
+// NewKeyExchange starts a key exchange between the owner of sk and the peer with the public key peerPk.
+// A nil signerId or peerId selects the default distinguishing identifier.
+func NewKeyExchange(curve *curves.Curve, sk curves.Scalar, signerId []byte, peerPk curves.Point, peerId []byte, initiator bool) (*KeyExchange, error) {
+	if sk == nil || sk.IsZero() {
+		return nil, fmt.Errorf("invalid secret key")
+	}
+	if signerId == nil {
+		signerId = DefaultSignerId
+	}
+	if peerId == nil {
+		peerId = DefaultSignerId
+	}
+	z, err := ZA(curve, signerId, curve.ScalarBaseMult(sk))
+	if err != nil {
+		return nil, err
+	}
+	peerZ, err := ZA(curve, peerId, peerPk)
+	if err != nil {
+		return nil, err
+	}
+	r := curve.Scalar.Random(rand.Reader)
+	return &KeyExchange{
+		curve:     curve,
+		initiator: initiator,
+		sk:        sk,
+		z:         z,
+		peerPk:    peerPk,
+		peerZ:     peerZ,
+		r:         r,
+		R:         curve.ScalarBaseMult(r),
+	}, nil
+}
+
+// EphemeralKey returns R_A for the initiator or R_B for the responder, which is sent to the peer
+func (ke *KeyExchange) EphemeralKey() curves.Point {
+	return ke.R
+}
+
+// Agree computes the shared key of keyLen bytes from the ephemeral key of the peer.
+// The ephemeral secret is erased, so Agree can only be called once.
+func (ke *KeyExchange) Agree(peerR curves.Point, keyLen int) ([]byte, error) {
+	if ke.r == nil {
+		return nil, fmt.Errorf("the key exchange has completed")
+	}
+	base, err := ExchangeBase(ke.curve, ke.peerPk, peerR)
+	if err != nil {
+		return nil, err
+	}
+	xBar, err := ExchangeXBar(ke.curve, ke.R)
+	if err != nil {
+		return nil, err
+	}
+	shared := base.Mul(ke.sk.Add(xBar.Mul(ke.r)))
+	ke.r = nil
+	if shared.IsIdentity() {
+		return nil, fmt.Errorf("key exchange failed")
+	}
+	ke.shared = shared
+	ke.transcript = NewExchangeTranscript(ke.initiator, ke.z, ke.R, ke.peerZ, peerR)
+	return ke.transcript.Key(shared, keyLen), nil
+}
+
+// Confirmation returns the optional confirmation that this side sends after Agree, S_B for the responder and
+// S_A for the initiator
+func (ke *KeyExchange) Confirmation() ([]byte, error) {
+	if ke.shared == nil {
+		return nil, fmt.Errorf("the key exchange has not completed")
+	}
+	if ke.initiator {
+		return ke.transcript.InitiatorConfirmation(ke.shared), nil
+	}
+	return ke.transcript.ResponderConfirmation(ke.shared), nil
+}
+
+// VerifyConfirmation checks the confirmation sent by the peer after Agree
+func (ke *KeyExchange) VerifyConfirmation(peerConfirmation []byte) error {
+	if ke.shared == nil {
+		return fmt.Errorf("the key exchange has not completed")
+	}
+	return ke.transcript.VerifyConfirmation(!ke.initiator, ke.shared, peerConfirmation)
+}
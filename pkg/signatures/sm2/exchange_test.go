@@ -0,0 +1,109 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package sm2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+)
+
+func TestExchangeXBar(t *testing.T) {
+	curve := curves.SM2()
+	R := curve.ScalarBaseMult(hexScalar(t, curve, "83a2c9c8b96e5af70bd480b472409a9a327257f1ebb73f5b073354b248668563"))
+	xBar, err := ExchangeXBar(curve, R)
+	require.NoError(t, err)
+
+	// x̄ keeps the low 127 bits of x and sets bit 127
+	x, _ := coordinates(R)
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	expected := new(big.Int).And(new(big.Int).SetBytes(x), mask)
+	expected.SetBit(expected, 127, 1)
+	require.Equal(t, 0, expected.Cmp(xBar.BigInt()))
+
+	_, err = ExchangeXBar(curve, curve.NewIdentityPoint())
+	require.Error(t, err)
+}
+
+func TestKeyExchange(t *testing.T) {
+	for _, curve := range []*curves.Curve{curves.SM2(), curves.K256(), curves.P256()} {
+		skA, pkA := NewKeys(curve)
+		skB, pkB := NewKeys(curve)
+		idA := []byte("ALICE123@YAHOO.COM")
+
+		a, err := NewKeyExchange(curve, skA, idA, pkB, nil, true)
+		require.NoError(t, err)
+		b, err := NewKeyExchange(curve, skB, nil, pkA, idA, false)
+		require.NoError(t, err)
+
+		_, err = b.Confirmation()
+		require.Error(t, err, "the key exchange has not completed")
+
+		keyB, err := b.Agree(a.EphemeralKey(), 16)
+		require.NoError(t, err)
+		sB, err := b.Confirmation()
+		require.NoError(t, err)
+
+		keyA, err := a.Agree(b.EphemeralKey(), 16)
+		require.NoError(t, err)
+		require.Equal(t, keyA, keyB)
+		require.Len(t, keyA, 16)
+		require.NoError(t, a.VerifyConfirmation(sB))
+		sA, err := a.Confirmation()
+		require.NoError(t, err)
+		require.NoError(t, b.VerifyConfirmation(sA))
+
+		// the confirmations are bound to the role of the sender
+		require.Error(t, a.VerifyConfirmation(sA))
+		require.Error(t, b.VerifyConfirmation(sB))
+
+		_, err = a.Agree(b.EphemeralKey(), 16)
+		require.Error(t, err, "the ephemeral key has been used")
+	}
+}
+
+func TestKeyExchangeMismatch(t *testing.T) {
+	curve := curves.SM2()
+	skA, pkA := NewKeys(curve)
+	skB, pkB := NewKeys(curve)
+	_, pkC := NewKeys(curve)
+
+	// A believes it talks to C, so both sides derive different keys and the confirmation fails
+	a, err := NewKeyExchange(curve, skA, nil, pkC, nil, true)
+	require.NoError(t, err)
+	b, err := NewKeyExchange(curve, skB, nil, pkA, nil, false)
+	require.NoError(t, err)
+	keyB, err := b.Agree(a.EphemeralKey(), 32)
+	require.NoError(t, err)
+	keyA, err := a.Agree(b.EphemeralKey(), 32)
+	require.NoError(t, err)
+	require.NotEqual(t, keyA, keyB)
+	sB, err := b.Confirmation()
+	require.NoError(t, err)
+	require.Error(t, a.VerifyConfirmation(sB))
+
+	// a wrong identifier changes Z_B
+	a, err = NewKeyExchange(curve, skA, nil, pkB, []byte("another id"), true)
+	require.NoError(t, err)
+	b, err = NewKeyExchange(curve, skB, nil, pkA, nil, false)
+	require.NoError(t, err)
+	keyB, err = b.Agree(a.EphemeralKey(), 32)
+	require.NoError(t, err)
+	keyA, err = a.Agree(b.EphemeralKey(), 32)
+	require.NoError(t, err)
+	require.NotEqual(t, keyA, keyB)
+
+	_, err = NewKeyExchange(curve, skA, nil, curve.NewIdentityPoint(), nil, true)
+	require.Error(t, err)
+	a, err = NewKeyExchange(curve, skA, nil, pkB, nil, true)
+	require.NoError(t, err)
+	_, err = a.Agree(curve.NewIdentityPoint(), 32)
+	require.Error(t, err)
+}
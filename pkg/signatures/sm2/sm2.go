@@ -0,0 +1,218 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package sm2 implements the SM2 digital signature algorithm from GB/T 32918.2-2016.
+// Signatures are computed over e = SM3(Z_A || M) where Z_A binds the signer's
+// distinguishing identifier, the curve domain parameters and the public key.
+// It also implements the public key encryption of GB/T 32918.4-2016.
+package sm2
+
+import (
+	"crypto/rand"
+	"encoding/asn1"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/sm3"
+)
+
+// DefaultSignerId is the distinguishing identifier used when the signer does not specify one
+var DefaultSignerId = []byte("1234567812345678")
+
+// Signature is an SM2 signature (r, s)
+type Signature struct {
+	R, S curves.Scalar
+}
+
+type derSignature struct {
+	R, S *big.Int
+}
+
+// NewKeys creates a new SM2 key pair.
+// The secret key d is chosen so that 1+d is invertible as required by the standard.
+func NewKeys(curve *curves.Curve) (curves.Scalar, curves.Point) {
+	for {
+		d := curve.Scalar.Random(rand.Reader)
+		if d.IsZero() || d.Add(curve.Scalar.One()).IsZero() {
+			continue
+		}
+		return d, curve.ScalarBaseMult(d)
+	}
+}
+
+// ZA computes the user digest SM3(ENTL || ID || a || b || Gx || Gy || xA || yA)
+func ZA(curve *curves.Curve, signerId []byte, pk curves.Point) ([]byte, error) {
+	if len(signerId) > 0x1fff {
+		return nil, fmt.Errorf("signer id is too long")
+	}
+	if pk == nil || pk.IsIdentity() {
+		return nil, fmt.Errorf("invalid public key")
+	}
+	ec, err := curve.ToEllipticCurve()
+	if err != nil {
+		return nil, err
+	}
+	params := ec.Params()
+	size := (params.P.BitLen() + 7) / 8
+
+	// Not every elliptic.CurveParams carries a, so recover it from the generator
+	// a = (Gy^2 - Gx^3 - b) / Gx mod p
+	a := new(big.Int).Mul(params.Gy, params.Gy)
+	a.Sub(a, new(big.Int).Exp(params.Gx, big.NewInt(3), params.P))
+	a.Sub(a, params.B)
+	a.Mul(a, new(big.Int).ModInverse(params.Gx, params.P))
+	a.Mod(a, params.P)
+
+	uncompressed := pk.ToAffineUncompressed()
+	if len(uncompressed) != 2*size+1 {
+		return nil, fmt.Errorf("invalid public key encoding")
+	}
+
+	h := sm3.New()
+	var entl [2]byte
+	binary.BigEndian.PutUint16(entl[:], uint16(len(signerId)*8))
+	_, _ = h.Write(entl[:])
+	_, _ = h.Write(signerId)
+	for _, v := range []*big.Int{a, params.B, params.Gx, params.Gy} {
+		_, _ = h.Write(v.FillBytes(make([]byte, size)))
+	}
+	_, _ = h.Write(uncompressed[1:])
+	return h.Sum(nil), nil
+}
+
+// Digest computes the message representative e = SM3(Z_A || M) mod n
+func Digest(curve *curves.Curve, signerId []byte, pk curves.Point, message []byte) (curves.Scalar, error) {
+	za, err := ZA(curve, signerId, pk)
+	if err != nil {
+		return nil, err
+	}
+	h := sm3.New()
+	_, _ = h.Write(za)
+	_, _ = h.Write(message)
+	ec, err := curve.ToEllipticCurve()
+	if err != nil {
+		return nil, err
+	}
+	e := new(big.Int).SetBytes(h.Sum(nil))
+	return curve.Scalar.SetBigInt(e.Mod(e, ec.Params().N))
+}
+
+// Sign computes an SM2 signature over message with the secret key sk
+func Sign(curve *curves.Curve, sk curves.Scalar, signerId []byte, message []byte) (*Signature, error) {
+	return sign(curve, sk, signerId, message, func() curves.Scalar {
+		return curve.Scalar.Random(rand.Reader)
+	})
+}
+
+func sign(curve *curves.Curve, sk curves.Scalar, signerId []byte, message []byte, nonce func() curves.Scalar) (*Signature, error) {
+	if sk == nil || sk.IsZero() {
+		return nil, fmt.Errorf("invalid secret key")
+	}
+	dInv, err := sk.Add(curve.Scalar.One()).Invert()
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret key")
+	}
+	e, err := Digest(curve, signerId, curve.ScalarBaseMult(sk), message)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		k := nonce()
+		x1, err := XCoordinate(curve, curve.ScalarBaseMult(k))
+		if err != nil {
+			return nil, err
+		}
+		r := e.Add(x1)
+		if r.IsZero() || r.Add(k).IsZero() {
+			continue
+		}
+		s := dInv.Mul(k.Sub(r.Mul(sk)))
+		if s.IsZero() {
+			continue
+		}
+		return &Signature{R: r, S: s}, nil
+	}
+}
+
+// Verify checks an SM2 signature over message against the public key pk
+func Verify(curve *curves.Curve, pk curves.Point, signerId []byte, message []byte, sig *Signature) error {
+	if sig == nil || sig.R == nil || sig.S == nil || sig.R.IsZero() || sig.S.IsZero() {
+		return fmt.Errorf("invalid signature")
+	}
+	if pk == nil || pk.IsIdentity() || !pk.IsOnCurve() {
+		return fmt.Errorf("invalid public key")
+	}
+	t := sig.R.Add(sig.S)
+	if t.IsZero() {
+		return fmt.Errorf("verification failed")
+	}
+	e, err := Digest(curve, signerId, pk, message)
+	if err != nil {
+		return err
+	}
+	R := curve.ScalarBaseMult(sig.S).Add(pk.Mul(t))
+	if R.IsIdentity() {
+		return fmt.Errorf("verification failed")
+	}
+	x1, err := XCoordinate(curve, R)
+	if err != nil {
+		return err
+	}
+	if sig.R.Cmp(e.Add(x1)) != 0 {
+		return fmt.Errorf("verification failed")
+	}
+	return nil
+}
+
+// XCoordinate returns the affine x-coordinate of the point reduced modulo the group order
+func XCoordinate(curve *curves.Curve, point curves.Point) (curves.Scalar, error) {
+	affine := point.ToAffineCompressed()
+	x := new(big.Int).SetBytes(affine[1:])
+	ec, err := curve.ToEllipticCurve()
+	if err != nil {
+		return nil, err
+	}
+	return curve.Scalar.SetBigInt(x.Mod(x, ec.Params().N))
+}
+
+// MarshalDER encodes the signature as the ASN.1 SEQUENCE { r INTEGER, s INTEGER }
+func (sig *Signature) MarshalDER() ([]byte, error) {
+	if sig.R == nil || sig.S == nil {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	return asn1.Marshal(derSignature{R: sig.R.BigInt(), S: sig.S.BigInt()})
+}
+
+// UnmarshalDER decodes an ASN.1 DER encoded signature
+func UnmarshalDER(curve *curves.Curve, input []byte) (*Signature, error) {
+	var der derSignature
+	rest, err := asn1.Unmarshal(input, &der)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("trailing data after signature")
+	}
+	ec, err := curve.ToEllipticCurve()
+	if err != nil {
+		return nil, err
+	}
+	n := ec.Params().N
+	if der.R.Sign() <= 0 || der.S.Sign() <= 0 || der.R.Cmp(n) >= 0 || der.S.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("signature values out of range")
+	}
+	r, err := curve.Scalar.SetBigInt(der.R)
+	if err != nil {
+		return nil, err
+	}
+	s, err := curve.Scalar.SetBigInt(der.S)
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{R: r, S: s}, nil
+}
@@ -0,0 +1,88 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package sm2
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+)
+
+func hexScalar(t *testing.T, curve *curves.Curve, s string) curves.Scalar {
+	v, ok := new(big.Int).SetString(s, 16)
+	require.True(t, ok)
+	sc, err := curve.Scalar.SetBigInt(v)
+	require.NoError(t, err)
+	return sc
+}
+
+// Example from GB/T 32918.2-2016 Appendix A on the recommended curve
+func TestSignGBTVector(t *testing.T) {
+	curve := curves.SM2()
+	d := hexScalar(t, curve, "3945208f7b2144b13f36e38ac6d39f95889393692860b51a42fb81ef4df7c5b8")
+	k := hexScalar(t, curve, "59276e27d506861a16680f3ad9c02dccef3cc1fa3cdbe4ce6d54b80deac1bc21")
+	pk := curve.ScalarBaseMult(d)
+	message := []byte("message digest")
+
+	za, err := ZA(curve, DefaultSignerId, pk)
+	require.NoError(t, err)
+	require.Equal(t, "b2e14c5c79c6df5b85f4fe7ed8db7a262b9da7e07ccb0ea9f4747b8ccda8a4f3", hex.EncodeToString(za))
+
+	e, err := Digest(curve, DefaultSignerId, pk, message)
+	require.NoError(t, err)
+	require.Equal(t, 0, e.Cmp(hexScalar(t, curve, "f0b43e94ba45accaace692ed534382eb17e6ab5a19ce7b31f4486fdfc0d28640")))
+
+	sig, err := sign(curve, d, DefaultSignerId, message, func() curves.Scalar { return k })
+	require.NoError(t, err)
+	require.Equal(t, 0, sig.R.Cmp(hexScalar(t, curve, "f5a03b0648d2c4630eeac513e1bb81a15944da3827d5b74143ac7eaceee720b3")))
+	require.Equal(t, 0, sig.S.Cmp(hexScalar(t, curve, "b1b6aa29df212fd8763182bc0d421ca1bb9038fd1f7f42d4840b69c485bbc1aa")))
+	require.NoError(t, Verify(curve, pk, DefaultSignerId, message, sig))
+
+	der, err := sig.MarshalDER()
+	require.NoError(t, err)
+	require.Equal(t, "3046022100f5a03b0648d2c4630eeac513e1bb81a15944da3827d5b74143ac7eaceee720b3022100b1b6aa29df212fd8763182bc0d421ca1bb9038fd1f7f42d4840b69c485bbc1aa", hex.EncodeToString(der))
+	parsed, err := UnmarshalDER(curve, der)
+	require.NoError(t, err)
+	require.Equal(t, 0, parsed.R.Cmp(sig.R))
+	require.Equal(t, 0, parsed.S.Cmp(sig.S))
+}
+
+func TestSignVerifyOverMultipleCurves(t *testing.T) {
+	for _, curve := range []*curves.Curve{curves.SM2(), curves.P256(), curves.K256()} {
+		sk, pk := NewKeys(curve)
+		message := []byte("test message")
+		id := []byte("ALICE123@YAHOO.COM")
+		sig, err := Sign(curve, sk, id, message)
+		require.NoError(t, err)
+		require.NoError(t, Verify(curve, pk, id, message, sig))
+
+		require.Error(t, Verify(curve, pk, DefaultSignerId, message, sig))
+		require.Error(t, Verify(curve, pk, id, []byte("another message"), sig))
+		require.Error(t, Verify(curve, curve.ScalarBaseMult(sk.Double()), id, message, sig))
+		require.Error(t, Verify(curve, pk, id, message, &Signature{R: sig.S, S: sig.R}))
+		require.Error(t, Verify(curve, pk, id, message, &Signature{R: sig.R, S: curve.Scalar.Zero()}))
+	}
+}
+
+func TestUnmarshalDERInvalid(t *testing.T) {
+	curve := curves.SM2()
+	_, err := UnmarshalDER(curve, []byte{0x30, 0x00})
+	require.Error(t, err)
+	// r = 0
+	_, err = UnmarshalDER(curve, []byte{0x30, 0x06, 0x02, 0x01, 0x00, 0x02, 0x01, 0x01})
+	require.Error(t, err)
+	// trailing data
+	_, err = UnmarshalDER(curve, []byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x01, 0x00})
+	require.Error(t, err)
+	sig, err := UnmarshalDER(curve, []byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02})
+	require.NoError(t, err)
+	require.True(t, sig.R.IsOne())
+}
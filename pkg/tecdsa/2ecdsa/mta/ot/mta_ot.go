@@ -3,6 +3,7 @@ package mta_ot
 import (
 	"crypto/rand"
 	"crypto/subtle"
+	"encoding/binary"
 	"fmt"
 	"math/big"
 
@@ -28,6 +29,7 @@ type Sender struct {
 	curve           *curves.Curve
 	transcript      *merlin.Transcript
 	uniqueSessionId [simplest.DigestSize]byte
+	multiplications multiplications
 }
 
 // Receiver is the party that plays the role of Sender in the multiplication protocol (protocol 5 of the paper).
@@ -38,6 +40,36 @@ type Receiver struct {
 	curve           *curves.Curve
 	transcript      *merlin.Transcript
 	uniqueSessionId [simplest.DigestSize]byte
+	multiplications multiplications
+}
+
+// multiplications derives a fresh cOT session id for every multiplication of an instance. The cOT pads are expanded
+// from the base OT seeds under the session id, so two multiplications under the same id would reuse them and the
+// difference of their U rows would reveal the XOR of the receiver's choice bits.
+type multiplications struct {
+	sessionId []byte
+	counter   uint64
+}
+
+// next returns the session id of the next multiplication, H(instance id || session id || counter)
+func (m *multiplications) next(uniqueSessionId [simplest.DigestSize]byte) [simplest.DigestSize]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte("Coinbase_DKLs_Multiply_cOT"))
+	_, _ = h.Write(uniqueSessionId[:])
+	_ = binary.Write(h, binary.BigEndian, uint32(len(m.sessionId)))
+	_, _ = h.Write(m.sessionId)
+	_ = binary.Write(h, binary.BigEndian, m.counter)
+	m.counter++
+	multiplicationSessionId := [simplest.DigestSize]byte{}
+	copy(multiplicationSessionId[:], h.Sum(nil))
+	return multiplicationSessionId
+}
+
+// newTranscript starts the transcript of a multiplication
+func newTranscript(multiplicationSessionId [simplest.DigestSize]byte) *merlin.Transcript {
+	transcript := merlin.NewTranscript("Coinbase_DKLs_Multiply")
+	transcript.AppendMessage([]byte("session_id"), multiplicationSessionId[:])
+	return transcript
 }
 
 func generateGadgetVector(curve *curves.Curve) ([kos.L]curves.Scalar, error) {
@@ -74,12 +106,9 @@ func NewSender(seedOtResults *simplest.ReceiverOutput, curve *curves.Curve, uniq
 		return nil, errors.Wrap(err, "error generating gadget vector in new multiply sender")
 	}
 
-	transcript := merlin.NewTranscript("Coinbase_DKLs_Multiply")
-	transcript.AppendMessage([]byte("session_id"), uniqueSessionId[:])
 	return &Sender{
 		cOtSender:       sender,
 		curve:           curve,
-		transcript:      transcript,
 		uniqueSessionId: uniqueSessionId,
 		gadget:          gadget,
 	}, nil
@@ -94,17 +123,26 @@ func NewReceiver(seedOtResults *simplest.SenderOutput, curve *curves.Curve, uniq
 	if err != nil {
 		return nil, errors.Wrap(err, "error generating gadget vector in new multiply receiver")
 	}
-	transcript := merlin.NewTranscript("Coinbase_DKLs_Multiply")
-	transcript.AppendMessage([]byte("session_id"), uniqueSessionId[:])
 	return &Receiver{
 		cOtReceiver:     receiver,
 		curve:           curve,
-		transcript:      transcript,
 		uniqueSessionId: uniqueSessionId,
 		gadget:          gadget,
 	}, nil
 }
 
+// SetSessionId binds the following multiplications to sessionId, e.g. the session of a signature, and restarts their
+// count. Both ends must set the same session id before their next multiplication, which keeps them in step even if a
+// previous session was aborted halfway through a multiplication.
+func (sender *Sender) SetSessionId(sessionId []byte) {
+	sender.multiplications = multiplications{sessionId: append([]byte(nil), sessionId...)}
+}
+
+// SetSessionId binds the following multiplications to sessionId and restarts their count, see Sender.SetSessionId
+func (receiver *Receiver) SetSessionId(sessionId []byte) {
+	receiver.multiplications = multiplications{sessionId: append([]byte(nil), sessionId...)}
+}
+
 type Round1Output = kos.Round1Output
 
 // Round2Output is the output of the second round of the multiplication protocol.
@@ -148,7 +186,9 @@ func (receiver *Receiver) Init(beta curves.Scalar) *Round1Output {
 	if receiver.omega, err = receiver.encode(beta); err != nil {
 		panic("MtA OT init")
 	}
-	cOtRound1Output, err := receiver.cOtReceiver.Round1Initialize(receiver.uniqueSessionId, receiver.omega)
+	multiplicationSessionId := receiver.multiplications.next(receiver.uniqueSessionId)
+	receiver.transcript = newTranscript(multiplicationSessionId)
+	cOtRound1Output, err := receiver.cOtReceiver.Round1Initialize(multiplicationSessionId, receiver.omega)
 	if err != nil {
 		panic("MtA OT init")
 	}
@@ -174,8 +214,10 @@ func (sender *Sender) Update(alpha curves.Scalar, round1Output *kos.Round1Output
 		input[j][0] = alpha
 		input[j][1] = alphaHat
 	}
+	multiplicationSessionId := sender.multiplications.next(sender.uniqueSessionId)
+	sender.transcript = newTranscript(multiplicationSessionId)
 	round2Output := &Round2Output{}
-	round2Output.COTRound2Output, err = sender.cOtSender.Round2Transfer(sender.uniqueSessionId, input, round1Output)
+	round2Output.COTRound2Output, err = sender.cOtSender.Round2Transfer(multiplicationSessionId, input, round1Output)
 	if err != nil {
 		panic("MtA OT update")
 	}
@@ -38,6 +38,43 @@ func TestMtAOT(t *testing.T) {
 	require.Equal(t, product, sum)
 }
 
+func TestMtAOTFreshPads(t *testing.T) {
+	curve := curves.K256()
+	hashKeySeed := [simplest.DigestSize]byte{}
+	_, err := rand.Read(hashKeySeed[:])
+	require.NoError(t, err)
+
+	baseOtSenderOutput, baseOtReceiverOutput, err := ottest.RunSimplestOT(curve, kos.Kappa, hashKeySeed)
+	require.NoError(t, err)
+
+	sender, err := NewSender(baseOtReceiverOutput, curve, hashKeySeed)
+	require.NoError(t, err)
+	receiver, err := NewReceiver(baseOtSenderOutput, curve, hashKeySeed)
+	require.NoError(t, err)
+
+	// two multiplications on the same instance expand the base OT seeds under different session ids. Under the same
+	// session id their pads would be equal, so that every row of U1 ^ U2 would be the XOR of the two choice vectors.
+	alpha := curve.Scalar.Random(rand.Reader)
+	beta := curve.Scalar.Random(rand.Reader)
+	var us [2]*Round1Output
+	for i := range us {
+		us[i] = receiver.Init(beta)
+		ta, round2Output := sender.Update(alpha, us[i])
+		tb := receiver.Multiply(round2Output)
+		require.Equal(t, alpha.Mul(beta), ta.Add(tb))
+	}
+	differences := make(map[string]struct{}, kos.Kappa)
+	for i := 0; i < kos.Kappa; i++ {
+		require.NotEqual(t, us[0].U[i], us[1].U[i], "row %d of U repeats", i)
+		difference := make([]byte, len(us[0].U[i]))
+		for j := range difference {
+			difference[j] = us[0].U[i][j] ^ us[1].U[i][j]
+		}
+		differences[string(difference)] = struct{}{}
+	}
+	require.Len(t, differences, kos.Kappa)
+}
+
 func BenchmarkMtAOTSetup(b *testing.B) {
 	curve := curves.K256()
 	hashKeySeed := [simplest.DigestSize]byte{}
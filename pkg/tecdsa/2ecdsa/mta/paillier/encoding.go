@@ -0,0 +1,116 @@
+package mta_paillier
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/paillier/zk"
+	zk_qr "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/paillier/zk/qr"
+	zk_qrdl "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/paillier/zk/qrdl"
+	zk_r_affran "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/paillier/zk/r_affran"
+	zk_r_p "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/paillier/zk/r_p"
+	zk_r_pwr "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/paillier/zk/r_pwr"
+)
+
+// The encodings below mirror the messages with exported fields so that encoding/gob can carry them between the
+// parties. The proofs encode themselves.
+
+type setupStatementEncoding struct {
+	N *big.Int
+	H *big.Int
+	G *big.Int
+}
+
+type setupProofEncoding struct {
+	N *zk_r_p.Proof
+	H *zk_qr.Proof
+	G *zk_qrdl.Proof
+}
+
+type round1OutputEncoding struct {
+	C     *big.Int
+	Proof *zk_r_pwr.Proof
+}
+
+type round2OutputEncoding struct {
+	C     *big.Int
+	Proof *zk_r_affran.Proof
+}
+
+// MarshalBinary encodes the statement with encoding/gob
+func (statement *SetupStatement) MarshalBinary() ([]byte, error) {
+	return zk.Marshal(&setupStatementEncoding{statement.r_p, statement.qr, statement.qrdl})
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary
+func (statement *SetupStatement) UnmarshalBinary(data []byte) error {
+	decoded := &setupStatementEncoding{}
+	if err := zk.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	if err := zk.CheckInts(decoded.N, decoded.H, decoded.G); err != nil {
+		return err
+	}
+	statement.r_p, statement.qr, statement.qrdl = decoded.N, decoded.H, decoded.G
+	return nil
+}
+
+// MarshalBinary encodes the proofs with encoding/gob
+func (proof *SetupProof) MarshalBinary() ([]byte, error) {
+	return zk.Marshal(&setupProofEncoding{proof.r_p, proof.qr, proof.qrdl})
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary
+func (proof *SetupProof) UnmarshalBinary(data []byte) error {
+	decoded := &setupProofEncoding{}
+	if err := zk.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	if decoded.N == nil || decoded.H == nil || decoded.G == nil {
+		return fmt.Errorf("missing setup proof")
+	}
+	proof.r_p, proof.qr, proof.qrdl = decoded.N, decoded.H, decoded.G
+	return nil
+}
+
+// MarshalBinary encodes the ciphertext and its proof with encoding/gob
+func (output *Round1Output) MarshalBinary() ([]byte, error) {
+	return zk.Marshal(&round1OutputEncoding{output.c_B, output.proof})
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary
+func (output *Round1Output) UnmarshalBinary(data []byte) error {
+	decoded := &round1OutputEncoding{}
+	if err := zk.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	if err := zk.CheckInts(decoded.C); err != nil {
+		return err
+	}
+	if decoded.Proof == nil {
+		return fmt.Errorf("missing proof")
+	}
+	output.c_B, output.proof = decoded.C, decoded.Proof
+	return nil
+}
+
+// MarshalBinary encodes the ciphertext and its proof with encoding/gob
+func (output *Round2Output) MarshalBinary() ([]byte, error) {
+	return zk.Marshal(&round2OutputEncoding{output.c_A, output.proof})
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary
+func (output *Round2Output) UnmarshalBinary(data []byte) error {
+	decoded := &round2OutputEncoding{}
+	if err := zk.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	if err := zk.CheckInts(decoded.C); err != nil {
+		return err
+	}
+	if decoded.Proof == nil {
+		return fmt.Errorf("missing proof")
+	}
+	output.c_A, output.proof = decoded.C, decoded.Proof
+	return nil
+}
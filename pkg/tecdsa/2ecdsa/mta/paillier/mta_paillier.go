@@ -96,6 +96,21 @@ func NewReceiver(curve *curves.Curve, p *big.Int, q *big.Int) *Receiver {
 	}
 }
 
+// NewSenderWithSessionId is NewSender with the transcript bound to uniqueSessionId, which must be unique to the pair
+// of parties and the receiver must use the same one
+func NewSenderWithSessionId(curve *curves.Curve, p *big.Int, q *big.Int, uniqueSessionId []byte) *Sender {
+	sender := NewSender(curve, p, q)
+	sender.tx.AppendMessage([]byte("session_id"), uniqueSessionId)
+	return sender
+}
+
+// NewReceiverWithSessionId is NewReceiver with the transcript bound to uniqueSessionId
+func NewReceiverWithSessionId(curve *curves.Curve, p *big.Int, q *big.Int, uniqueSessionId []byte) *Receiver {
+	receiver := NewReceiver(curve, p, q)
+	receiver.tx.AppendMessage([]byte("session_id"), uniqueSessionId)
+	return receiver
+}
+
 func (receiver *Receiver) SetupInit() (SetupStatement, SetupProof) {
 	// P2 generates N, g, h
 	receiver.pp.N = receiver.sk.N
@@ -135,3 +135,43 @@ func BenchmarkMtAPaillierStep3(b *testing.B) {
 		receiver.Multiply(round2Output)
 	}
 }
+
+// roundTrip sends a message through its binary encoding
+func roundTrip[T any, PT interface {
+	*T
+	MarshalBinary() ([]byte, error)
+	UnmarshalBinary([]byte) error
+}](t *testing.T, message PT) PT {
+	data, err := message.MarshalBinary()
+	require.NoError(t, err)
+	decoded := PT(new(T))
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	return decoded
+}
+
+func TestMtAPaillierEncoding(t *testing.T) {
+	curve := curves.SM2()
+	sessionId := []byte("test session")
+
+	sender := NewSenderWithSessionId(curve, p, q, sessionId)
+	receiver := NewReceiverWithSessionId(curve, p0, q0, sessionId)
+
+	setup1Statement, setup1Proof := receiver.SetupInit()
+	setup2Statement, setup2Proof := sender.SetupUpdate(*roundTrip(t, &setup1Statement), *roundTrip(t, &setup1Proof))
+	receiver.SetupDone(*roundTrip(t, &setup2Statement), *roundTrip(t, &setup2Proof))
+
+	alpha := curve.Scalar.Random(rand.Reader)
+	beta := curve.Scalar.Random(rand.Reader)
+
+	round1Output := receiver.Init(beta)
+	ta, round2Output := sender.Update(alpha, roundTrip(t, round1Output))
+	tb := receiver.Multiply(roundTrip(t, round2Output))
+
+	require.Equal(t, alpha.Mul(beta), ta.Add(tb))
+
+	// messages without their proofs are rejected
+	data, err := (&Round1Output{c_B: round1Output.c_B}).MarshalBinary()
+	require.NoError(t, err)
+	require.Error(t, new(Round1Output).UnmarshalBinary(data))
+	require.Error(t, new(Round2Output).UnmarshalBinary([]byte("garbage")))
+}
@@ -0,0 +1,32 @@
+package zk
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math/big"
+)
+
+// Marshal gob-encodes v, the exported mirror of a proof or message, for its MarshalBinary method
+func Marshal(v interface{}) ([]byte, error) {
+	buf := bytes.NewBuffer([]byte{})
+	if err := gob.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the output of Marshal into v
+func Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// CheckInts reports an error if any of the decoded integers is missing, since the verifiers dereference every field
+func CheckInts(ints ...*big.Int) error {
+	for _, i := range ints {
+		if i == nil {
+			return fmt.Errorf("missing integer")
+		}
+	}
+	return nil
+}
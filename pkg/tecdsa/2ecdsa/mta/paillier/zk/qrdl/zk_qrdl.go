@@ -84,3 +84,28 @@ func Verify(tx *merlin.Transcript, pp *Agreed, g *Statement, proof *Proof) bool
 
 	return e.Cmp(proof.e) == 0 // Compare the actual challenge with the alleged one
 }
+
+// proofEncoding mirrors Proof with exported fields so that encoding/gob can carry it
+type proofEncoding struct {
+	Z [zk.T]*big.Int
+	E *big.Int
+}
+
+// MarshalBinary encodes the proof with encoding/gob
+func (proof *Proof) MarshalBinary() ([]byte, error) {
+	return zk.Marshal(&proofEncoding{Z: proof.z, E: proof.e})
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary, rejecting proofs with missing integers
+func (proof *Proof) UnmarshalBinary(data []byte) error {
+	decoded := &proofEncoding{}
+	if err := zk.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	if err := zk.CheckInts(append(decoded.Z[:], decoded.E)...); err != nil {
+		return err
+	}
+	proof.z = decoded.Z
+	proof.e = decoded.E
+	return nil
+}
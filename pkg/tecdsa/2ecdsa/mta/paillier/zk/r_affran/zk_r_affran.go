@@ -124,3 +124,42 @@ func Verify(tx *merlin.Transcript, pp *Agreed, c_A *Statement, proof *Proof) boo
 	}
 	return true
 }
+
+// proofEncoding mirrors Proof with exported fields so that encoding/gob can carry it
+type proofEncoding struct {
+	A  *big.Int
+	B1 *big.Int
+	B2 *big.Int
+	B3 *big.Int
+	B4 *big.Int
+	Z1 *big.Int
+	Z2 *big.Int
+	Z3 *big.Int
+	Z4 *big.Int
+}
+
+// MarshalBinary encodes the proof with encoding/gob
+func (proof *Proof) MarshalBinary() ([]byte, error) {
+	return zk.Marshal(&proofEncoding{A: proof.A, B1: proof.B1, B2: proof.B2, B3: proof.B3, B4: proof.B4, Z1: proof.z1, Z2: proof.z2, Z3: proof.z3, Z4: proof.z4})
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary, rejecting proofs with missing integers
+func (proof *Proof) UnmarshalBinary(data []byte) error {
+	decoded := &proofEncoding{}
+	if err := zk.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	if err := zk.CheckInts(decoded.A, decoded.B1, decoded.B2, decoded.B3, decoded.B4, decoded.Z1, decoded.Z2, decoded.Z3, decoded.Z4); err != nil {
+		return err
+	}
+	proof.A = decoded.A
+	proof.B1 = decoded.B1
+	proof.B2 = decoded.B2
+	proof.B3 = decoded.B3
+	proof.B4 = decoded.B4
+	proof.z1 = decoded.Z1
+	proof.z2 = decoded.Z2
+	proof.z3 = decoded.Z3
+	proof.z4 = decoded.Z4
+	return nil
+}
@@ -156,3 +156,34 @@ func Verify(tx *merlin.Transcript, N *Statement, proof *Proof) bool {
 
 	return true
 }
+
+// proofEncoding mirrors Proof with exported fields so that encoding/gob can carry it
+type proofEncoding struct {
+	W *big.Int
+	X [zk.T]*big.Int
+	A [zk.T]bool
+	B [zk.T]bool
+	Z [zk.T]*big.Int
+}
+
+// MarshalBinary encodes the proof with encoding/gob
+func (proof *Proof) MarshalBinary() ([]byte, error) {
+	return zk.Marshal(&proofEncoding{W: proof.w, X: proof.x, A: proof.a, B: proof.b, Z: proof.z})
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary, rejecting proofs with missing integers
+func (proof *Proof) UnmarshalBinary(data []byte) error {
+	decoded := &proofEncoding{}
+	if err := zk.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	if err := zk.CheckInts(append(append([]*big.Int{decoded.W}, decoded.X[:]...), decoded.Z[:]...)...); err != nil {
+		return err
+	}
+	proof.w = decoded.W
+	proof.x = decoded.X
+	proof.a = decoded.A
+	proof.b = decoded.B
+	proof.z = decoded.Z
+	return nil
+}
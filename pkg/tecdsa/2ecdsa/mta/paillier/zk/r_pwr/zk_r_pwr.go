@@ -102,3 +102,36 @@ func Verify(tx *merlin.Transcript, pp *Agreed, c *Statement, proof *Proof) bool
 	}
 	return true
 }
+
+// proofEncoding mirrors Proof with exported fields so that encoding/gob can carry it
+type proofEncoding struct {
+	C  *big.Int
+	D1 *big.Int
+	D2 *big.Int
+	Z1 *big.Int
+	Z2 *big.Int
+	Z3 *big.Int
+}
+
+// MarshalBinary encodes the proof with encoding/gob
+func (proof *Proof) MarshalBinary() ([]byte, error) {
+	return zk.Marshal(&proofEncoding{C: proof.C, D1: proof.d, D2: proof.D, Z1: proof.z1, Z2: proof.z2, Z3: proof.z3})
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary, rejecting proofs with missing integers
+func (proof *Proof) UnmarshalBinary(data []byte) error {
+	decoded := &proofEncoding{}
+	if err := zk.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	if err := zk.CheckInts(decoded.C, decoded.D1, decoded.D2, decoded.Z1, decoded.Z2, decoded.Z3); err != nil {
+		return err
+	}
+	proof.C = decoded.C
+	proof.d = decoded.D1
+	proof.D = decoded.D2
+	proof.z1 = decoded.Z1
+	proof.z2 = decoded.Z2
+	proof.z3 = decoded.Z3
+	return nil
+}
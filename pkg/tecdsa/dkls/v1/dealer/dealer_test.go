@@ -15,6 +15,8 @@ func Test_DealerCanGenerateKeysThatSign(t *testing.T) {
 	curveInstances := []*curves.Curve{
 		curves.K256(),
 		curves.P256(),
+		curves.P384(),
+		curves.P521(),
 	}
 	for _, curve := range curveInstances {
 		aliceOutput, bobOutput, err := dealer.GenerateAndDeal(curve)
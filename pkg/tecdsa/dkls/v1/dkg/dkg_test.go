@@ -21,6 +21,8 @@ func TestDkg(t *testing.T) {
 	curveInstances := []*curves.Curve{
 		curves.K256(),
 		curves.P256(),
+		curves.P384(),
+		curves.P521(),
 	}
 	for _, curve := range curveInstances {
 		boundCurve := curve
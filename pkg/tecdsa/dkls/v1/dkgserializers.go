@@ -32,6 +32,10 @@ func registerTypes() {
 	gob.Register(&curves.PointK256{})
 	gob.Register(&curves.ScalarP256{})
 	gob.Register(&curves.PointP256{})
+	gob.Register(&curves.ScalarP384{})
+	gob.Register(&curves.PointP384{})
+	gob.Register(&curves.ScalarP521{})
+	gob.Register(&curves.PointP521{})
 }
 
 func encodeDkgRound1Output(commitment [32]byte, version uint) (*protocol.Message, error) {
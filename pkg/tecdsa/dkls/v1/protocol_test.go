@@ -55,6 +55,8 @@ func TestDkgProto(t *testing.T) {
 	curveInstances := []*curves.Curve{
 		curves.K256(),
 		curves.P256(),
+		curves.P384(),
+		curves.P521(),
 	}
 	for _, curve := range curveInstances {
 		alice := NewAliceDkg(curve, protocol.Version1)
@@ -111,6 +113,8 @@ func TestRefreshProto(t *testing.T) {
 	curveInstances := []*curves.Curve{
 		curves.K256(),
 		curves.P256(),
+		curves.P384(),
+		curves.P521(),
 	}
 	for _, curve := range curveInstances {
 		boundCurve := curve
@@ -225,9 +229,10 @@ func TestDkgSignProto(t *testing.T) {
 		require.NoError(t, err)
 		digest := hash.Sum(nil)
 		unCompressedAffinePublicKey := aliceDkg.Output().PublicKey.ToAffineUncompressed()
-		require.Equal(t, 65, len(unCompressedAffinePublicKey))
-		x := new(big.Int).SetBytes(unCompressedAffinePublicKey[1:33])
-		y := new(big.Int).SetBytes(unCompressedAffinePublicKey[33:])
+		fieldBytes := len(curve.Scalar.Bytes())
+		require.Equal(t, 1+2*fieldBytes, len(unCompressedAffinePublicKey))
+		x := new(big.Int).SetBytes(unCompressedAffinePublicKey[1 : 1+fieldBytes])
+		y := new(big.Int).SetBytes(unCompressedAffinePublicKey[1+fieldBytes:])
 		ecCurve, err := curve.ToEllipticCurve()
 		require.NoError(t, err)
 		publicKey := &curves.EcPoint{
@@ -460,9 +465,10 @@ func signV1(t *testing.T, curve *curves.Curve, aliceDkgResultMessage *protocol.M
 		require.NoError(t, err)
 		digest := hash.Sum(nil)
 		unCompressedAffinePublicKey := aliceDkg.PublicKey.ToAffineUncompressed()
-		require.Equal(t, 65, len(unCompressedAffinePublicKey))
-		x := new(big.Int).SetBytes(unCompressedAffinePublicKey[1:33])
-		y := new(big.Int).SetBytes(unCompressedAffinePublicKey[33:])
+		fieldBytes := len(curve.Scalar.Bytes())
+		require.Equal(t, 1+2*fieldBytes, len(unCompressedAffinePublicKey))
+		x := new(big.Int).SetBytes(unCompressedAffinePublicKey[1 : 1+fieldBytes])
+		y := new(big.Int).SetBytes(unCompressedAffinePublicKey[1+fieldBytes:])
 		ecCurve, err := curve.ToEllipticCurve()
 		require.NoError(t, err)
 		publicKey := &curves.EcPoint{
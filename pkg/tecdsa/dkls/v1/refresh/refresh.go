@@ -95,8 +95,9 @@ func (bob *Bob) Round2RefreshProduceSeedAndMultiplyAndStartOT(aliceSeed curves.S
 	bob.transcript.AppendMessage([]byte("alice refresh seed"), aliceSeed.Bytes())
 	bobSeed := bob.curve.Scalar.Random(rand.Reader)
 	bob.transcript.AppendMessage([]byte("bob refresh seed"), bobSeed.Bytes())
-	k, err := bob.curve.NewScalar().SetBytes(
-		bob.transcript.ExtractBytes([]byte("secret key share multiplier"), simplest.DigestSize),
+	k, err := kos.NewScalar(
+		bob.curve,
+		bob.transcript.ExtractBytes([]byte("secret key share multiplier"), kos.ScalarBytes(bob.curve)),
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "couldn't produce bob's secret key share multiplier")
@@ -122,8 +123,9 @@ func (bob *Bob) Round2RefreshProduceSeedAndMultiplyAndStartOT(aliceSeed curves.S
 
 func (alice *Alice) Round3RefreshMultiplyRound2Ot(input *RefreshRound2Output) ([]simplest.ReceiversMaskedChoices, error) {
 	alice.transcript.AppendMessage([]byte("bob refresh seed"), input.BobMultiplier.Bytes())
-	k, err := alice.curve.NewScalar().SetBytes(
-		alice.transcript.ExtractBytes([]byte("secret key share multiplier"), simplest.DigestSize),
+	k, err := kos.NewScalar(
+		alice.curve,
+		alice.transcript.ExtractBytes([]byte("secret key share multiplier"), kos.ScalarBytes(alice.curve)),
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "couldn't produce bob's secret key share multiplier")
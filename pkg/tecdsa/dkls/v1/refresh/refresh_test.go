@@ -77,6 +77,8 @@ func Test_RefreshLeadsToTheSamePublicKeyButDifferentPrivateMaterial(t *testing.T
 	curveInstances := []*curves.Curve{
 		curves.K256(),
 		curves.P256(),
+		curves.P384(),
+		curves.P521(),
 	}
 	for _, curve := range curveInstances {
 		boundCurve := curve
@@ -128,6 +130,8 @@ func Test_RefreshOTIsCorrect(t *testing.T) {
 	curveInstances := []*curves.Curve{
 		curves.K256(),
 		curves.P256(),
+		curves.P384(),
+		curves.P521(),
 	}
 	for _, curve := range curveInstances {
 		boundCurve := curve
@@ -151,6 +155,8 @@ func Test_CanSignAfterRefresh(t *testing.T) {
 	curveInstances := []*curves.Curve{
 		curves.K256(),
 		curves.P256(),
+		curves.P384(),
+		curves.P521(),
 	}
 	for _, curve := range curveInstances {
 		boundCurve := curve
@@ -29,9 +29,9 @@ import (
 
 // MultiplySender is the party that plays the role of Sender in the multiplication protocol (protocol 5 of the paper).
 type MultiplySender struct {
-	cOtSender           *kos.Sender   // underlying cOT sender struct, used by mult.
+	cOtSenders          []*kos.Sender // underlying cOT sender structs, one per batch, used by mult.
 	outputAdditiveShare curves.Scalar // ultimate output share of mult.
-	gadget              [kos.L]curves.Scalar
+	gadget              []curves.Scalar
 	curve               *curves.Curve
 	transcript          *merlin.Transcript
 	uniqueSessionId     [simplest.DigestSize]byte
@@ -39,32 +39,61 @@ type MultiplySender struct {
 
 // MultiplyReceiver is the party that plays the role of Sender in the multiplication protocol (protocol 5 of the paper).
 type MultiplyReceiver struct {
-	cOtReceiver         *kos.Receiver               // underlying cOT receiver struct, used by mult.
-	outputAdditiveShare curves.Scalar               // ultimate output share of mult.
-	omega               [kos.COtBlockSizeBytes]byte // this is used as an intermediate result during the course of mult.
-	gadget              [kos.L]curves.Scalar
+	cOtReceivers        []*kos.Receiver // underlying cOT receiver structs, one per batch, used by mult.
+	outputAdditiveShare curves.Scalar   // ultimate output share of mult.
+	omega               []byte          // this is used as an intermediate result during the course of mult.
+	gadget              []curves.Scalar
 	curve               *curves.Curve
 	transcript          *merlin.Transcript
 	uniqueSessionId     [simplest.DigestSize]byte
 }
 
-func generateGadgetVector(curve *curves.Curve) ([kos.L]curves.Scalar, error) {
+// inputBits is the number of bits of a scalar of `curve`, as laid out at the start of the gadget vector.
+func inputBits(curve *curves.Curve) int {
+	return len(curve.Scalar.Bytes()) << 3
+}
+
+// cOtBatchCount returns how many cOT batches of `kos.L` slots a multiplication over `curve` runs.
+// The encoding of Bob's input needs the input bits plus as many random slots again, plus 2s; see Algorithm 5.
+// For 256-bit scalars this is exactly `kos.L`, so those curves use a single batch.
+func cOtBatchCount(curve *curves.Curve) int {
+	slots := 2*inputBits(curve) + kos.L - 2*kos.Kappa
+	return (slots + kos.L - 1) / kos.L
+}
+
+// batchSessionId derives the session id of the `batch`th cOT batch. The first batch keeps the id of the multiplication.
+func batchSessionId(uniqueSessionId [simplest.DigestSize]byte, batch int) [simplest.DigestSize]byte {
+	if batch == 0 {
+		return uniqueSessionId
+	}
+	return sha3.Sum256([]byte(fmt.Sprintf("Coinbase_DKLs_cOT_batch %d %x", batch, uniqueSessionId)))
+}
+
+// batchLabel labels a transcript message of the `batch`th cOT batch. The first batch keeps the original labels.
+func batchLabel(label string, batch int) []byte {
+	if batch == 0 {
+		return []byte(label)
+	}
+	return []byte(fmt.Sprintf("%s of batch %d", label, batch))
+}
+
+func generateGadgetVector(curve *curves.Curve) ([]curves.Scalar, error) {
 	var err error
-	gadget := [kos.L]curves.Scalar{}
-	for i := 0; i < kos.Kappa; i++ {
+	gadget := make([]curves.Scalar, cOtBatchCount(curve)*kos.L)
+	for i := 0; i < inputBits(curve); i++ {
 		gadget[i], err = curve.Scalar.SetBigInt(new(big.Int).Lsh(big.NewInt(1), uint(i)))
 		if err != nil {
 			return gadget, errors.Wrap(err, "creating gadget scalar from big int")
 		}
 	}
 	shake := sha3.NewCShake256(nil, []byte("Coinbase DKLs gadget vector"))
-	for i := kos.Kappa; i < kos.L; i++ {
+	for i := inputBits(curve); i < len(gadget); i++ {
 		var err error
-		bytes := [simplest.DigestSize]byte{}
-		if _, err = shake.Read(bytes[:]); err != nil {
+		bytes := make([]byte, kos.ScalarBytes(curve))
+		if _, err = shake.Read(bytes); err != nil {
 			return gadget, err
 		}
-		gadget[i], err = curve.Scalar.SetBytes(bytes[:])
+		gadget[i], err = kos.NewScalar(curve, bytes)
 		if err != nil {
 			return gadget, errors.Wrap(err, "creating gadget scalar from bytes")
 		}
@@ -76,7 +105,10 @@ func generateGadgetVector(curve *curves.Curve) ([kos.L]curves.Scalar, error) {
 // You must supply it the _output_ of a seed OT, from the receiver's point of view, as well as params and a unique ID.
 // That is, the mult sender must run the base OT as the receiver; note the (apparent) reversal of roles.
 func NewMultiplySender(seedOtResults *simplest.ReceiverOutput, curve *curves.Curve, uniqueSessionId [simplest.DigestSize]byte) (*MultiplySender, error) {
-	sender := kos.NewCOtSender(seedOtResults, curve)
+	senders := make([]*kos.Sender, cOtBatchCount(curve))
+	for i := range senders {
+		senders[i] = kos.NewCOtSender(seedOtResults, curve)
+	}
 	gadget, err := generateGadgetVector(curve)
 	if err != nil {
 		return nil, errors.Wrap(err, "error generating gadget vector in new multiply sender")
@@ -85,7 +117,7 @@ func NewMultiplySender(seedOtResults *simplest.ReceiverOutput, curve *curves.Cur
 	transcript := merlin.NewTranscript("Coinbase_DKLs_Multiply")
 	transcript.AppendMessage([]byte("session_id"), uniqueSessionId[:])
 	return &MultiplySender{
-		cOtSender:       sender,
+		cOtSenders:      senders,
 		curve:           curve,
 		transcript:      transcript,
 		uniqueSessionId: uniqueSessionId,
@@ -97,7 +129,10 @@ func NewMultiplySender(seedOtResults *simplest.ReceiverOutput, curve *curves.Cur
 // You must supply it the _output_ of a seed OT, from the sender's point of view, as well as params and a unique ID.
 // That is, the mult sender must run the base OT as the sender; note the (apparent) reversal of roles.
 func NewMultiplyReceiver(seedOtResults *simplest.SenderOutput, curve *curves.Curve, uniqueSessionId [simplest.DigestSize]byte) (*MultiplyReceiver, error) {
-	receiver := kos.NewCOtReceiver(seedOtResults, curve)
+	receivers := make([]*kos.Receiver, cOtBatchCount(curve))
+	for i := range receivers {
+		receivers[i] = kos.NewCOtReceiver(seedOtResults, curve)
+	}
 	gadget, err := generateGadgetVector(curve)
 	if err != nil {
 		return nil, errors.Wrap(err, "error generating gadget vector in new multiply receiver")
@@ -105,7 +140,7 @@ func NewMultiplyReceiver(seedOtResults *simplest.SenderOutput, curve *curves.Cur
 	transcript := merlin.NewTranscript("Coinbase_DKLs_Multiply")
 	transcript.AppendMessage([]byte("session_id"), uniqueSessionId[:])
 	return &MultiplyReceiver{
-		cOtReceiver:     receiver,
+		cOtReceivers:    receivers,
 		curve:           curve,
 		transcript:      transcript,
 		uniqueSessionId: uniqueSessionId,
@@ -118,19 +153,25 @@ type MultiplyRound2Output struct {
 	COTRound2Output *kos.Round2Output
 	R               [kos.L]curves.Scalar
 	U               curves.Scalar
+
+	// COTRound2OutputsExt and RExt hold the cOT batches after the first one, which are only run for curves whose
+	// scalars are wider than 256 bits.
+	COTRound2OutputsExt []*kos.Round2Output
+	RExt                [][kos.L]curves.Scalar
 }
 
 // Algorithm 5. in DKLs. "Encodes" Bob's secret input scalars `beta` in the right way, using the opts.
 // The idea is that if Bob were to just put beta's as the choice vector, then Alice could learn a few of Bob's bits.
 // using selective failure attacks. so you subtract random components of a public random vector. see paper for details.
-func (receiver *MultiplyReceiver) encode(beta curves.Scalar) ([kos.COtBlockSizeBytes]byte, error) {
+func (receiver *MultiplyReceiver) encode(beta curves.Scalar) ([]byte, error) {
 	// passing beta by value, so that we can mutate it locally. check that this does what i want.
-	encoding := [kos.COtBlockSizeBytes]byte{}
+	encoding := make([]byte, len(receiver.gadget)>>3)
 	bytesOfBetaMinusDotProduct := beta.Bytes()
-	if _, err := rand.Read(encoding[kos.KappaBytes:]); err != nil {
+	betaBytes := len(bytesOfBetaMinusDotProduct)
+	if _, err := rand.Read(encoding[betaBytes:]); err != nil {
 		return encoding, errors.Wrap(err, "sampling `gamma` random bytes in multiply receiver encode")
 	}
-	for j := kos.Kappa; j < kos.L; j++ {
+	for j := betaBytes << 3; j < len(receiver.gadget); j++ {
 		jthBitOfGamma := simplest.ExtractBitFromByteVector(encoding[:], j)
 		// constant-time computation of the dot product beta - < gR, gamma >.
 		// we can only `ConstantTimeCopy` byte slices (as opposed to big ints). so keep them as bytes.
@@ -144,35 +185,59 @@ func (receiver *MultiplyReceiver) encode(beta curves.Scalar) ([kos.COtBlockSizeB
 		bytesOfBetaMinusDotProduct = option0Bytes
 		subtle.ConstantTimeCopy(int(jthBitOfGamma), bytesOfBetaMinusDotProduct[:], option1Bytes)
 	}
-	copy(encoding[0:kos.KappaBytes], internal.ReverseScalarBytes(bytesOfBetaMinusDotProduct[:]))
+	copy(encoding[0:betaBytes], internal.ReverseScalarBytes(bytesOfBetaMinusDotProduct[:]))
 	return encoding, nil
 }
 
-// Round1Initialize Protocol 5., Multiplication, 3). Bob (receiver) encodes beta and initiates the cOT extension
-func (receiver *MultiplyReceiver) Round1Initialize(beta curves.Scalar) (*kos.Round1Output, error) {
+// Round1Initialize Protocol 5., Multiplication, 3). Bob (receiver) encodes beta and initiates the cOT extension.
+// The output holds the first message of each cOT batch.
+func (receiver *MultiplyReceiver) Round1Initialize(beta curves.Scalar) ([]*kos.Round1Output, error) {
 	var err error
 	if receiver.omega, err = receiver.encode(beta); err != nil {
 		return nil, errors.Wrap(err, "encoding input beta in receiver round 1 initialize")
 	}
-	cOtRound1Output, err := receiver.cOtReceiver.Round1Initialize(receiver.uniqueSessionId, receiver.omega)
-	if err != nil {
-		return nil, errors.Wrap(err, "error in cOT round 1 initialize within multiply round 1 initialize")
+	cOtRound1Outputs := make([]*kos.Round1Output, len(receiver.cOtReceivers))
+	for batch, cOtReceiver := range receiver.cOtReceivers {
+		choice := [kos.COtBlockSizeBytes]byte{}
+		copy(choice[:], receiver.omega[batch*kos.COtBlockSizeBytes:])
+		cOtRound1Outputs[batch], err = cOtReceiver.Round1Initialize(batchSessionId(receiver.uniqueSessionId, batch), choice)
+		if err != nil {
+			return nil, errors.Wrap(err, "error in cOT round 1 initialize within multiply round 1 initialize")
+		}
+		// write the output of the first round to the transcript
+		for i := 0; i < kos.Kappa; i++ {
+			label := batchLabel(fmt.Sprintf("row %d of U", i), batch)
+			receiver.transcript.AppendMessage(label, cOtRound1Outputs[batch].U[i][:])
+		}
+		receiver.transcript.AppendMessage(batchLabel("wPrime", batch), cOtRound1Outputs[batch].WPrime[:])
+		receiver.transcript.AppendMessage(batchLabel("vPrime", batch), cOtRound1Outputs[batch].VPrime[:])
 	}
-	// write the output of the first round to the transcript
-	for i := 0; i < kos.Kappa; i++ {
-		label := []byte(fmt.Sprintf("row %d of U", i))
-		receiver.transcript.AppendMessage(label, cOtRound1Output.U[i][:])
+	return cOtRound1Outputs, nil
+}
+
+// drawChi draws the challenges `chi` of the multiplication's consistency check from the transcript.
+func drawChi(curve *curves.Curve, transcript *merlin.Transcript, chiWidth int) ([]curves.Scalar, error) {
+	var err error
+	chi := make([]curves.Scalar, chiWidth)
+	for k := 0; k < chiWidth; k++ {
+		label := []byte(fmt.Sprintf("draw challenge chi %d", k))
+		randomBytes := transcript.ExtractBytes(label, kos.ScalarBytes(curve))
+		chi[k], err = kos.NewScalar(curve, randomBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "setting chi scalar from bytes")
+		}
 	}
-	receiver.transcript.AppendMessage([]byte("wPrime"), cOtRound1Output.WPrime[:])
-	receiver.transcript.AppendMessage([]byte("vPrime"), cOtRound1Output.VPrime[:])
-	return cOtRound1Output, nil
+	return chi, nil
 }
 
 // Round2Multiply Protocol 5., steps 3) 5), 7). Alice _responds_ to Bob's initial cOT message, using alpha as input.
 // Doesn't actually send the message yet, only stashes it and moves onto the next steps of the multiplication protocol
 // specifically, Alice can then do step 5) (compute the outputs of the multiplication protocol), also stashes this.
 // Finishes by taking care of 7), after that, Alice is totally done with multiplication and has stashed the outputs.
-func (sender *MultiplySender) Round2Multiply(alpha curves.Scalar, round1Output *kos.Round1Output) (*MultiplyRound2Output, error) {
+func (sender *MultiplySender) Round2Multiply(alpha curves.Scalar, round1Outputs []*kos.Round1Output) (*MultiplyRound2Output, error) {
+	if len(round1Outputs) != len(sender.cOtSenders) {
+		return nil, fmt.Errorf("expected %d cOT batches, got %d", len(sender.cOtSenders), len(round1Outputs))
+	}
 	var err error
 	alphaHat := sender.curve.Scalar.Random(rand.Reader)
 	input := [kos.L][2]curves.Scalar{} // sender's input, namely integer "sums" in case w_j == 1.
@@ -180,88 +245,104 @@ func (sender *MultiplySender) Round2Multiply(alpha curves.Scalar, round1Output *
 		input[j][0] = alpha
 		input[j][1] = alphaHat
 	}
-	round2Output := &MultiplyRound2Output{}
-	round2Output.COTRound2Output, err = sender.cOtSender.Round2Transfer(sender.uniqueSessionId, input, round1Output)
-	if err != nil {
-		return nil, errors.Wrap(err, "error in cOT within round 2 multiply")
-	}
-	// write the output of the first round to the transcript
-	for i := 0; i < kos.Kappa; i++ {
-		label := []byte(fmt.Sprintf("row %d of U", i))
-		sender.transcript.AppendMessage(label, round1Output.U[i][:])
+	cOtRound2Outputs := make([]*kos.Round2Output, len(sender.cOtSenders))
+	for batch, cOtSender := range sender.cOtSenders {
+		if round1Outputs[batch] == nil {
+			return nil, fmt.Errorf("cOT batch %d is missing", batch)
+		}
+		cOtRound2Outputs[batch], err = cOtSender.Round2Transfer(batchSessionId(sender.uniqueSessionId, batch), input, round1Outputs[batch])
+		if err != nil {
+			return nil, errors.Wrap(err, "error in cOT within round 2 multiply")
+		}
+		// write the output of the first round to the transcript
+		for i := 0; i < kos.Kappa; i++ {
+			label := batchLabel(fmt.Sprintf("row %d of U", i), batch)
+			sender.transcript.AppendMessage(label, round1Outputs[batch].U[i][:])
+		}
+		sender.transcript.AppendMessage(batchLabel("wPrime", batch), round1Outputs[batch].WPrime[:])
+		sender.transcript.AppendMessage(batchLabel("vPrime", batch), round1Outputs[batch].VPrime[:])
 	}
-	sender.transcript.AppendMessage([]byte("wPrime"), round1Output.WPrime[:])
-	sender.transcript.AppendMessage([]byte("vPrime"), round1Output.VPrime[:])
 	// write our own output of the second round to the transcript
 	chiWidth := 2
-	for i := 0; i < kos.Kappa; i++ {
-		for k := 0; k < chiWidth; k++ {
-			label := []byte(fmt.Sprintf("row %d of Tau", i))
-			sender.transcript.AppendMessage(label, round2Output.COTRound2Output.Tau[i][k].Bytes())
+	for batch, cOtRound2Output := range cOtRound2Outputs {
+		for i := 0; i < kos.Kappa; i++ {
+			for k := 0; k < chiWidth; k++ {
+				label := batchLabel(fmt.Sprintf("row %d of Tau", i), batch)
+				sender.transcript.AppendMessage(label, cOtRound2Output.Tau[i][k].Bytes())
+			}
 		}
 	}
-	chi := make([]curves.Scalar, chiWidth)
-	for k := 0; k < 2; k++ {
-		label := []byte(fmt.Sprintf("draw challenge chi %d", k))
-		randomBytes := sender.transcript.ExtractBytes(label, kos.KappaBytes)
-		chi[k], err = sender.curve.Scalar.SetBytes(randomBytes)
-		if err != nil {
-			return nil, errors.Wrap(err, "setting chi scalar from bytes")
-		}
+	chi, err := drawChi(sender.curve, sender.transcript, chiWidth)
+	if err != nil {
+		return nil, err
 	}
 	sender.outputAdditiveShare = sender.curve.Scalar.Zero()
-	for j := 0; j < kos.L; j++ {
-		round2Output.R[j] = sender.curve.Scalar.Zero()
-		for k := 0; k < chiWidth; k++ {
-			round2Output.R[j] = round2Output.R[j].Add(chi[k].Mul(sender.cOtSender.OutputAdditiveShares[j][k]))
+	r := make([][kos.L]curves.Scalar, len(sender.cOtSenders))
+	for batch, cOtSender := range sender.cOtSenders {
+		for j := 0; j < kos.L; j++ {
+			r[batch][j] = sender.curve.Scalar.Zero()
+			for k := 0; k < chiWidth; k++ {
+				r[batch][j] = r[batch][j].Add(chi[k].Mul(cOtSender.OutputAdditiveShares[j][k]))
+			}
+			sender.outputAdditiveShare = sender.outputAdditiveShare.Add(sender.gadget[batch*kos.L+j].Mul(cOtSender.OutputAdditiveShares[j][0]))
 		}
-		sender.outputAdditiveShare = sender.outputAdditiveShare.Add(sender.gadget[j].Mul(sender.cOtSender.OutputAdditiveShares[j][0]))
 	}
-	round2Output.U = chi[0].Mul(alpha).Add(chi[1].Mul(alphaHat))
-	return round2Output, nil
+	return &MultiplyRound2Output{
+		COTRound2Output:     cOtRound2Outputs[0],
+		R:                   r[0],
+		U:                   chi[0].Mul(alpha).Add(chi[1].Mul(alphaHat)),
+		COTRound2OutputsExt: cOtRound2Outputs[1:],
+		RExt:                r[1:],
+	}, nil
 }
 
 // Round3Multiply Protocol 5., Multiplication, 3) and 6). Bob finalizes the cOT extension.
 // using that and Alice's multiplication message, Bob completes the multiplication protocol, including checks.
 // At the end, Bob's values tB_j are populated.
 func (receiver *MultiplyReceiver) Round3Multiply(round2Output *MultiplyRound2Output) error {
+	cOtRound2Outputs := append([]*kos.Round2Output{round2Output.COTRound2Output}, round2Output.COTRound2OutputsExt...)
+	r := append([][kos.L]curves.Scalar{round2Output.R}, round2Output.RExt...)
+	if len(cOtRound2Outputs) != len(receiver.cOtReceivers) || len(r) != len(receiver.cOtReceivers) {
+		return fmt.Errorf("expected %d cOT batches in round 3 multiply", len(receiver.cOtReceivers))
+	}
 	chiWidth := 2
 	// write the output of the second round to the transcript
-	for i := 0; i < kos.Kappa; i++ {
-		for k := 0; k < chiWidth; k++ {
-			label := []byte(fmt.Sprintf("row %d of Tau", i))
-			receiver.transcript.AppendMessage(label, round2Output.COTRound2Output.Tau[i][k].Bytes())
+	for batch, cOtRound2Output := range cOtRound2Outputs {
+		if cOtRound2Output == nil {
+			return fmt.Errorf("cOT batch %d is missing", batch)
 		}
-	}
-	if err := receiver.cOtReceiver.Round3Transfer(round2Output.COTRound2Output); err != nil {
-		return errors.Wrap(err, "error within cOT round 3 transfer within round 3 multiply")
-	}
-	var err error
-	chi := make([]curves.Scalar, chiWidth)
-	for k := 0; k < chiWidth; k++ {
-		label := []byte(fmt.Sprintf("draw challenge chi %d", k))
-		randomBytes := receiver.transcript.ExtractBytes(label, kos.KappaBytes)
-		chi[k], err = receiver.curve.Scalar.SetBytes(randomBytes)
-		if err != nil {
-			return errors.Wrap(err, "setting chi scalar from bytes")
+		for i := 0; i < kos.Kappa; i++ {
+			for k := 0; k < chiWidth; k++ {
+				label := batchLabel(fmt.Sprintf("row %d of Tau", i), batch)
+				receiver.transcript.AppendMessage(label, cOtRound2Output.Tau[i][k].Bytes())
+			}
+		}
+		if err := receiver.cOtReceivers[batch].Round3Transfer(cOtRound2Output); err != nil {
+			return errors.Wrap(err, "error within cOT round 3 transfer within round 3 multiply")
 		}
 	}
+	chi, err := drawChi(receiver.curve, receiver.transcript, chiWidth)
+	if err != nil {
+		return err
+	}
 
 	receiver.outputAdditiveShare = receiver.curve.Scalar.Zero()
-	for j := 0; j < kos.L; j++ {
-		// compute the LHS of bob's step 6) for j. note that we're "adding r_j" to both sides"; so this LHS includes r_j.
-		// the reason to do this is so that the constant-time (i.e., independent of w_j) calculation of w_j * u can proceed more cleanly.
-		leftHandSideOfCheck := round2Output.R[j]
-		for k := 0; k < chiWidth; k++ {
-			leftHandSideOfCheck = leftHandSideOfCheck.Add(chi[k].Mul(receiver.cOtReceiver.OutputAdditiveShares[j][k]))
-		}
-		rightHandSideOfCheck := [simplest.DigestSize]byte{}
-		jthBitOfOmega := simplest.ExtractBitFromByteVector(receiver.omega[:], j)
-		subtle.ConstantTimeCopy(int(jthBitOfOmega), rightHandSideOfCheck[:], round2Output.U.Bytes())
-		if subtle.ConstantTimeCompare(rightHandSideOfCheck[:], leftHandSideOfCheck.Bytes()) != 1 {
-			return fmt.Errorf("alice's values R and U failed to check in round 3 multiply")
+	for batch, cOtReceiver := range receiver.cOtReceivers {
+		for j := 0; j < kos.L; j++ {
+			// compute the LHS of bob's step 6) for j. note that we're "adding r_j" to both sides"; so this LHS includes r_j.
+			// the reason to do this is so that the constant-time (i.e., independent of w_j) calculation of w_j * u can proceed more cleanly.
+			leftHandSideOfCheck := r[batch][j]
+			for k := 0; k < chiWidth; k++ {
+				leftHandSideOfCheck = leftHandSideOfCheck.Add(chi[k].Mul(cOtReceiver.OutputAdditiveShares[j][k]))
+			}
+			rightHandSideOfCheck := make([]byte, len(round2Output.U.Bytes()))
+			jthBitOfOmega := simplest.ExtractBitFromByteVector(receiver.omega[:], batch*kos.L+j)
+			subtle.ConstantTimeCopy(int(jthBitOfOmega), rightHandSideOfCheck[:], round2Output.U.Bytes())
+			if subtle.ConstantTimeCompare(rightHandSideOfCheck[:], leftHandSideOfCheck.Bytes()) != 1 {
+				return fmt.Errorf("alice's values R and U failed to check in round 3 multiply")
+			}
+			receiver.outputAdditiveShare = receiver.outputAdditiveShare.Add(receiver.gadget[batch*kos.L+j].Mul(cOtReceiver.OutputAdditiveShares[j][0]))
 		}
-		receiver.outputAdditiveShare = receiver.outputAdditiveShare.Add(receiver.gadget[j].Mul(receiver.cOtReceiver.OutputAdditiveShares[j][0]))
 	}
 	return nil
 }
@@ -19,7 +19,43 @@ import (
 )
 
 func TestMultiply(t *testing.T) {
-	curve := curves.K256()
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+		curves.P384(),
+		curves.P521(),
+	}
+	for _, curve := range curveInstances {
+		hashKeySeed := [simplest.DigestSize]byte{}
+		_, err := rand.Read(hashKeySeed[:])
+		require.NoError(t, err)
+
+		baseOtSenderOutput, baseOtReceiverOutput, err := ottest.RunSimplestOT(curve, kos.Kappa, hashKeySeed)
+		require.NoError(t, err)
+
+		sender, err := NewMultiplySender(baseOtReceiverOutput, curve, hashKeySeed)
+		require.NoError(t, err)
+		receiver, err := NewMultiplyReceiver(baseOtSenderOutput, curve, hashKeySeed)
+		require.NoError(t, err)
+
+		alpha := curve.Scalar.Random(rand.Reader)
+		beta := curve.Scalar.Random(rand.Reader)
+
+		round1Output, err := receiver.Round1Initialize(beta)
+		require.Nil(t, err)
+		round2Output, err := sender.Round2Multiply(alpha, round1Output)
+		require.Nil(t, err)
+		err = receiver.Round3Multiply(round2Output)
+		require.Nil(t, err)
+
+		product := alpha.Mul(beta)
+		sum := sender.outputAdditiveShare.Add(receiver.outputAdditiveShare)
+		require.Equal(t, product, sum)
+	}
+}
+
+func TestMultiplyMissingBatch(t *testing.T) {
+	curve := curves.P384()
 	hashKeySeed := [simplest.DigestSize]byte{}
 	_, err := rand.Read(hashKeySeed[:])
 	require.NoError(t, err)
@@ -31,18 +67,15 @@ func TestMultiply(t *testing.T) {
 	require.NoError(t, err)
 	receiver, err := NewMultiplyReceiver(baseOtSenderOutput, curve, hashKeySeed)
 	require.NoError(t, err)
+	require.Equal(t, 2, len(receiver.cOtReceivers))
 
-	alpha := curve.Scalar.Random(rand.Reader)
-	beta := curve.Scalar.Random(rand.Reader)
-
-	round1Output, err := receiver.Round1Initialize(beta)
-	require.Nil(t, err)
-	round2Output, err := sender.Round2Multiply(alpha, round1Output)
-	require.Nil(t, err)
-	err = receiver.Round3Multiply(round2Output)
-	require.Nil(t, err)
+	round1Output, err := receiver.Round1Initialize(curve.Scalar.Random(rand.Reader))
+	require.NoError(t, err)
+	_, err = sender.Round2Multiply(curve.Scalar.Random(rand.Reader), round1Output[:1])
+	require.Error(t, err)
 
-	product := alpha.Mul(beta)
-	sum := sender.outputAdditiveShare.Add(receiver.outputAdditiveShare)
-	require.Equal(t, product, sum)
+	round2Output, err := sender.Round2Multiply(curve.Scalar.Random(rand.Reader), round1Output)
+	require.NoError(t, err)
+	round2Output.COTRound2OutputsExt = nil
+	require.Error(t, receiver.Round3Multiply(round2Output))
 }
@@ -90,6 +90,10 @@ type SignRound2Output struct {
 	// KosRound1Outputs is the output of the first round of OT Extension, stored for future rounds.
 	KosRound1Outputs [multiplicationCount]*kos.Round1Output
 
+	// KosRound1OutputsExt holds the first round of the OT Extension batches after the first one, which are only run
+	// for curves whose scalars are wider than 256 bits.
+	KosRound1OutputsExt [multiplicationCount][]*kos.Round1Output
+
 	// DB is D_{B} = k_{B} . G from the paper.
 	DB curves.Point
 
@@ -164,14 +168,16 @@ func (bob *Bob) Round2Initialize(aliceSeed [simplest.DigestSize]byte) (*SignRoun
 	round2Output.DB = bob.dB
 	kBInv := bob.curve.Scalar.One().Div(bob.kB)
 
-	round2Output.KosRound1Outputs[0], err = bob.multiplyReceivers[0].Round1Initialize(kBInv)
+	kosRound1Outputs, err := bob.multiplyReceivers[0].Round1Initialize(kBInv)
 	if err != nil {
 		return nil, errors.Wrap(err, "error in multiply round 1 initialize 0 within Bob sign round 3 initialize")
 	}
-	round2Output.KosRound1Outputs[1], err = bob.multiplyReceivers[1].Round1Initialize(bob.secretKeyShare.Mul(kBInv))
+	round2Output.KosRound1Outputs[0], round2Output.KosRound1OutputsExt[0] = kosRound1Outputs[0], kosRound1Outputs[1:]
+	kosRound1Outputs, err = bob.multiplyReceivers[1].Round1Initialize(bob.secretKeyShare.Mul(kBInv))
 	if err != nil {
 		return nil, errors.Wrap(err, "error in multiply round 1 initialize 1 within Bob sign round 3 initialize")
 	}
+	round2Output.KosRound1Outputs[1], round2Output.KosRound1OutputsExt[1] = kosRound1Outputs[0], kosRound1Outputs[1:]
 	return round2Output, nil
 }
 
@@ -198,8 +204,7 @@ func (alice *Alice) Round3Sign(message []byte, round2Output *SignRound2Output) (
 	round3Output := &SignRound3Output{}
 	kPrimeA := alice.curve.Scalar.Random(rand.Reader)
 	round3Output.RPrime = round2Output.DB.Mul(kPrimeA)
-	hashRPrimeBytes := sha3.Sum256(round3Output.RPrime.ToAffineCompressed())
-	hashRPrime, err := alice.curve.Scalar.SetBytes(hashRPrimeBytes[:])
+	hashRPrime, err := hashToScalar(alice.curve, round3Output.RPrime.ToAffineCompressed())
 	if err != nil {
 		return nil, errors.Wrap(err, "setting hashRPrime scalar from bytes")
 	}
@@ -216,10 +221,10 @@ func (alice *Alice) Round3Sign(message []byte, round2Output *SignRound2Output) (
 	phi := alice.curve.Scalar.Random(rand.Reader)
 	kAInv := alice.curve.Scalar.One().Div(kA)
 
-	if round3Output.MultiplyRound2Outputs[0], err = multiplySenders[0].Round2Multiply(phi.Add(kAInv), round2Output.KosRound1Outputs[0]); err != nil {
+	if round3Output.MultiplyRound2Outputs[0], err = multiplySenders[0].Round2Multiply(phi.Add(kAInv), kosRound1Outputs(round2Output, 0)); err != nil {
 		return nil, errors.Wrap(err, "error in round 2 multiply 0 within alice round 4 sign")
 	}
-	if round3Output.MultiplyRound2Outputs[1], err = multiplySenders[1].Round2Multiply(alice.secretKeyShare.Mul(kAInv), round2Output.KosRound1Outputs[1]); err != nil {
+	if round3Output.MultiplyRound2Outputs[1], err = multiplySenders[1].Round2Multiply(alice.secretKeyShare.Mul(kAInv), kosRound1Outputs(round2Output, 1)); err != nil {
 		return nil, errors.Wrap(err, "error in round 2 multiply 1 within alice round 4 sign")
 	}
 
@@ -227,8 +232,7 @@ func (alice *Alice) Round3Sign(message []byte, round2Output *SignRound2Output) (
 	gamma1 := alice.curve.ScalarBaseMult(kA.Mul(phi).Add(one))
 	other := r.Mul(multiplySenders[0].outputAdditiveShare.Neg())
 	gamma1 = gamma1.Add(other)
-	hashGamma1Bytes := sha3.Sum256(gamma1.ToAffineCompressed())
-	hashGamma1, err := alice.curve.Scalar.SetBytes(hashGamma1Bytes[:])
+	hashGamma1, err := hashToScalar(alice.curve, gamma1.ToAffineCompressed())
 	if err != nil {
 		return nil, errors.Wrap(err, "setting hashGamma1 scalar from bytes")
 	}
@@ -237,13 +241,13 @@ func (alice *Alice) Round3Sign(message []byte, round2Output *SignRound2Output) (
 		return nil, errors.Wrap(err, "writing message to hash in alice round 4 sign")
 	}
 	digest := alice.hash.Sum(nil)
-	hOfMAsInteger, err := alice.curve.Scalar.SetBytes(digest)
+	hOfMAsInteger, err := digestToScalar(alice.curve, digest)
 	if err != nil {
 		return nil, errors.Wrap(err, "setting hOfMAsInteger scalar from bytes")
 	}
 	affineCompressedForm := r.ToAffineCompressed()
-	if len(affineCompressedForm) != 33 {
-		return nil, errors.New("the compressed form must be exactly 33 bytes")
+	if len(affineCompressedForm) != 1+len(alice.curve.Scalar.Bytes()) {
+		return nil, fmt.Errorf("the compressed form must be exactly %d bytes", 1+len(alice.curve.Scalar.Bytes()))
 	}
 	// Discard the leading byte and parse the rest as the X coordinate.
 	rX, err := alice.curve.Scalar.SetBytes(affineCompressedForm[1:])
@@ -255,8 +259,7 @@ func (alice *Alice) Round3Sign(message []byte, round2Output *SignRound2Output) (
 	gamma2 := alice.publicKey.Mul(multiplySenders[0].outputAdditiveShare)
 	other = alice.curve.ScalarBaseMult(multiplySenders[1].outputAdditiveShare.Neg())
 	gamma2 = gamma2.Add(other)
-	hashGamma2Bytes := sha3.Sum256(gamma2.ToAffineCompressed())
-	hashGamma2, err := alice.curve.Scalar.SetBytes(hashGamma2Bytes[:])
+	hashGamma2, err := hashToScalar(alice.curve, gamma2.ToAffineCompressed())
 	if err != nil {
 		return nil, errors.Wrap(err, "setting hashGamma2 scalar from bytes")
 	}
@@ -276,8 +279,7 @@ func (bob *Bob) Round4Final(message []byte, round3Output *SignRound3Output) erro
 	if err := bob.multiplyReceivers[1].Round3Multiply(round3Output.MultiplyRound2Outputs[1]); err != nil {
 		return errors.Wrap(err, "error in round 3 multiply 1 within sign round 5")
 	}
-	rPrimeHashedBytes := sha3.Sum256(round3Output.RPrime.ToAffineCompressed())
-	rPrimeHashed, err := bob.curve.Scalar.SetBytes(rPrimeHashedBytes[:])
+	rPrimeHashed, err := hashToScalar(bob.curve, round3Output.RPrime.ToAffineCompressed())
 	if err != nil {
 		return errors.Wrap(err, "setting rPrimeHashed scalar from bytes")
 	}
@@ -293,8 +295,8 @@ func (bob *Bob) Round4Final(message []byte, round3Output *SignRound3Output) erro
 	}
 	zero := bob.curve.Scalar.Zero()
 	affineCompressedForm := r.ToAffineCompressed()
-	if len(affineCompressedForm) != 33 {
-		return errors.New("the compressed form must be exactly 33 bytes")
+	if len(affineCompressedForm) != 1+len(bob.curve.Scalar.Bytes()) {
+		return fmt.Errorf("the compressed form must be exactly %d bytes", 1+len(bob.curve.Scalar.Bytes()))
 	}
 	rY := affineCompressedForm[0] & 0x1 // this is bit(0) of Y coordinate
 	rX, err := bob.curve.Scalar.SetBytes(affineCompressedForm[1:])
@@ -306,8 +308,7 @@ func (bob *Bob) Round4Final(message []byte, round3Output *SignRound3Output) erro
 		V: int(rY),
 	}
 	gamma1 := r.Mul(bob.multiplyReceivers[0].outputAdditiveShare)
-	gamma1HashedBytes := sha3.Sum256(gamma1.ToAffineCompressed())
-	gamma1Hashed, err := bob.curve.Scalar.SetBytes(gamma1HashedBytes[:])
+	gamma1Hashed, err := hashToScalar(bob.curve, gamma1.ToAffineCompressed())
 	if err != nil {
 		return errors.Wrap(err, "setting gamma1Hashed scalar from bytes")
 	}
@@ -317,7 +318,7 @@ func (bob *Bob) Round4Final(message []byte, round3Output *SignRound3Output) erro
 		return errors.Wrap(err, "writing message to hash in Bob sign round 5 final")
 	}
 	digestBytes := bob.hash.Sum(nil)
-	digest, err := bob.curve.Scalar.SetBytes(digestBytes)
+	digest, err := digestToScalar(bob.curve, digestBytes)
 	if err != nil {
 		return errors.Wrap(err, "setting digest scalar from bytes")
 	}
@@ -329,24 +330,24 @@ func (bob *Bob) Round4Final(message []byte, round3Output *SignRound3Output) erro
 	gamma2 := bob.curve.ScalarBaseMult(bob.multiplyReceivers[1].outputAdditiveShare)
 	other := bob.publicKey.Mul(theta.Neg())
 	gamma2 = gamma2.Add(other)
-	gamma2HashedBytes := sha3.Sum256(gamma2.ToAffineCompressed())
-	gamma2Hashed, err := bob.curve.Scalar.SetBytes(gamma2HashedBytes[:])
+	gamma2Hashed, err := hashToScalar(bob.curve, gamma2.ToAffineCompressed())
 	if err != nil {
 		return errors.Wrap(err, "setting gamma2Hashed scalar from bytes")
 	}
 	scalarS := sigB.Add(round3Output.EtaSig.Sub(gamma2Hashed))
 	bob.Signature.S = scalarS.BigInt()
-	if bob.Signature.S.Bit(255) == 1 {
+	if bob.Signature.S.Bit(bob.curve.Scalar.New(-1).BigInt().BitLen()-1) == 1 {
 		bob.Signature.S = scalarS.Neg().BigInt()
 		bob.Signature.V ^= 1
 	}
 	// now verify the signature
 	unCompressedAffinePublicKey := bob.publicKey.ToAffineUncompressed()
-	if len(unCompressedAffinePublicKey) != 65 {
-		return errors.New("the uncompressed form must have exactly 65 bytes")
+	fieldBytes := len(affineCompressedForm) - 1
+	if len(unCompressedAffinePublicKey) != 1+2*fieldBytes {
+		return fmt.Errorf("the uncompressed form must have exactly %d bytes", 1+2*fieldBytes)
 	}
-	x := new(big.Int).SetBytes(unCompressedAffinePublicKey[1:33])
-	y := new(big.Int).SetBytes(unCompressedAffinePublicKey[33:])
+	x := new(big.Int).SetBytes(unCompressedAffinePublicKey[1 : 1+fieldBytes])
+	y := new(big.Int).SetBytes(unCompressedAffinePublicKey[1+fieldBytes:])
 	ellipticCurve, err := bob.curve.ToEllipticCurve()
 	if err != nil {
 		return errors.Wrap(err, "invalid curve")
@@ -356,3 +357,30 @@ func (bob *Bob) Round4Final(message []byte, round3Output *SignRound3Output) erro
 	}
 	return nil
 }
+
+// kosRound1Outputs collects the first round of every OT Extension batch of the `i`th multiplication.
+func kosRound1Outputs(round2Output *SignRound2Output, i int) []*kos.Round1Output {
+	return append([]*kos.Round1Output{round2Output.KosRound1Outputs[i]}, round2Output.KosRound1OutputsExt[i]...)
+}
+
+// hashToScalar hashes `data` into a scalar. Curves with 32 byte scalars use sha3-256; wider scalars are derived from
+// shake256 output of twice their width, so that the hash covers the whole field.
+func hashToScalar(curve *curves.Curve, data []byte) (curves.Scalar, error) {
+	if len(curve.Scalar.Bytes()) <= simplest.DigestSize {
+		digest := sha3.Sum256(data)
+		return curve.Scalar.SetBytes(digest[:])
+	}
+	bytes := make([]byte, kos.ScalarBytes(curve))
+	sha3.ShakeSum256(bytes, data)
+	return kos.NewScalar(curve, bytes)
+}
+
+// digestToScalar converts the message digest into a scalar the way ECDSA does: the digest is truncated to the bit
+// length of the group order and then reduced modulo it.
+func digestToScalar(curve *curves.Curve, digest []byte) (curves.Scalar, error) {
+	e := new(big.Int).SetBytes(digest)
+	if excess := len(digest)<<3 - curve.Scalar.New(-1).BigInt().BitLen(); excess > 0 {
+		e.Rsh(e, uint(excess))
+	}
+	return curve.Scalar.SetBigInt(e)
+}
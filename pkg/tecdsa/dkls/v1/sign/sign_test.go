@@ -24,6 +24,8 @@ func TestSign(t *testing.T) {
 	curveInstances := []*curves.Curve{
 		curves.K256(),
 		curves.P256(),
+		curves.P384(),
+		curves.P521(),
 	}
 	for _, curve := range curveInstances {
 		hashKeySeed := [simplest.DigestSize]byte{}
@@ -19,6 +19,18 @@ import (
 	"github.com/coinbase/kryptology/pkg/sharing/v1"
 )
 
+// PaillierPrimeBits returns the size of the safe primes of the Paillier keys and of the proof parameters on curve.
+// The MtA range proofs need moduli of about q^8, i.e. of 8 times as many bits as the group order q. The default
+// 1024-bit primes provide that for 256-bit curves, whereas wider curves such as P-384 and P-521 need primes of 4 times
+// as many bits as their order.
+func PaillierPrimeBits(curve elliptic.Curve) uint {
+	bits := uint(4 * curve.Params().N.BitLen())
+	if bits < paillier.PaillierPrimeBits {
+		return paillier.PaillierPrimeBits
+	}
+	return bits
+}
+
 // ParticipantData represents all data to be sent to a participant
 // after the dealer is finished
 type ParticipantData struct {
@@ -137,11 +149,16 @@ func (s *Share) UnmarshalJSON(bytes []byte) error {
 	return nil
 }
 
-// NewProofParams creates new ProofParams with `bits` sized values
+// NewProofParams creates new ProofParams with `bits` sized values, which suit curves of up to 256 bits
 func NewProofParams() (*ProofParams, error) {
 	return genProofParams(core.GenerateSafePrime, core.Rand, paillier.PaillierPrimeBits)
 }
 
+// NewProofParamsForCurve creates new ProofParams whose values are sized for curve, see PaillierPrimeBits
+func NewProofParamsForCurve(curve elliptic.Curve) (*ProofParams, error) {
+	return genProofParams(core.GenerateSafePrime, core.Rand, PaillierPrimeBits(curve))
+}
+
 // NewProofParamsWithPrimes creates new ProofParams using the
 // parameters as the primes
 func NewProofParamsWithPrimes(p, q *big.Int) (*ProofParams, error) {
@@ -180,7 +197,7 @@ func DerivePublicKey(curve elliptic.Curve, secretKey *big.Int) (*curves.EcPoint,
 	return curves.NewScalarBaseMult(curve, secretKey)
 }
 
-// NewDealerShares generates the Secp256k1 private key shares and public key
+// NewDealerShares generates the private key shares and public key on curve
 // if ikm == nil, a new private key will be generated
 func NewDealerShares(curve elliptic.Curve, threshold, total uint32, ikm *big.Int) (*curves.EcPoint, map[uint32]*Share, error) {
 	if total < threshold {
@@ -195,7 +212,7 @@ func NewDealerShares(curve elliptic.Curve, threshold, total uint32, ikm *big.Int
 	if threshold > 255 {
 		return nil, nil, fmt.Errorf("threshold cannot exceed 255")
 	}
-	if curve.Params().BitSize != 256 {
+	if curve.Params().BitSize < 256 {
 		return nil, nil, fmt.Errorf("invalid curve size")
 	}
 	var err error
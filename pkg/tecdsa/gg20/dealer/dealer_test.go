@@ -270,6 +270,34 @@ func TestNewDealerShares(t *testing.T) {
 	}
 }
 
+func TestPaillierPrimeBits(t *testing.T) {
+	tests := []struct {
+		curve *curves.Curve
+		bits  uint
+	}{
+		{curves.K256(), 1024},
+		{curves.P256(), 1024},
+		{curves.P384(), 1536},
+		{curves.P521(), 2084},
+	}
+	for _, test := range tests {
+		ellipticCurve, err := test.curve.ToEllipticCurve()
+		require.NoError(t, err)
+		require.Equal(t, test.bits, PaillierPrimeBits(ellipticCurve))
+	}
+}
+
+func TestNewDealerSharesWideCurves(t *testing.T) {
+	for _, curve := range []*curves.Curve{curves.P384(), curves.P521()} {
+		ellipticCurve, err := curve.ToEllipticCurve()
+		require.NoError(t, err)
+		pk, sharesMap, err := NewDealerShares(ellipticCurve, 2, 3, nil)
+		require.NoError(t, err)
+		require.Len(t, sharesMap, 3)
+		require.True(t, ellipticCurve.IsOnCurve(pk.X, pk.Y))
+	}
+}
+
 func TestPreparePublicShares(t *testing.T) {
 	curve := btcec.S256()
 	pk, sharesMap, err := NewDealerShares(curve, 2, 3, nil)
@@ -14,6 +14,7 @@ import (
 	"github.com/coinbase/kryptology/pkg/core"
 	"github.com/coinbase/kryptology/pkg/paillier"
 	"github.com/coinbase/kryptology/pkg/sharing/v1"
+	"github.com/coinbase/kryptology/pkg/tecdsa/gg20/dealer"
 	"github.com/coinbase/kryptology/pkg/tecdsa/gg20/proof"
 )
 
@@ -71,33 +72,22 @@ func (dp *DkgParticipant) DkgRound1(threshold, total uint32) (*DkgRound1Bcast, e
 		return nil, err
 	}
 
-	// Step 4: ski, pki := PaillierKeyGen(1^k) (generate a 2048-bit Paillier key pair
-	pki, ski, err := paillier.NewKeys()
+	// Step 4: ski, pki := PaillierKeyGen(1^k) (generate a Paillier key pair, of 2048 bits for 256-bit curves)
+	bits := dealer.PaillierPrimeBits(dp.Curve)
+	p, q, err := dp.safePrimes(bits)
 	if err != nil {
 		return nil, err
 	}
+	ski, err := paillier.NewSecretKey(p, q)
+	if err != nil {
+		return nil, err
+	}
+	pki := &ski.PublicKey
 
-	// Step 5-6: Choose 1024-bit safe primes Pi, Qi, Pi=2pi+1, Qi=2qi+1 where Pi, Qi, pi, qi are primes
-	values := make(chan *big.Int, 2)
-	errors := make(chan error, 2)
-
-	var Pi, Qi *big.Int
-	for Pi == Qi {
-		for range []int{1, 2} {
-			go func() {
-				value, err := core.GenerateSafePrime(paillier.PaillierPrimeBits)
-				values <- value
-				errors <- err
-			}()
-		}
-
-		for _, err := range []error{<-errors, <-errors} {
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		Pi, Qi = <-values, <-values
+	// Step 5-6: Choose safe primes Pi, Qi, Pi=2pi+1, Qi=2qi+1 where Pi, Qi, pi, qi are primes
+	Pi, Qi, err := dp.safePrimes(bits)
+	if err != nil {
+		return nil, err
 	}
 
 	// Step 7: Compute tildeNi = Pi*Qi
@@ -186,3 +176,33 @@ func (dp *DkgParticipant) DkgRound1(threshold, total uint32) (*DkgRound1Bcast, e
 		dp.id, Ci, pki, h1i, h2i, tildeNi, proof1, proof2,
 	}, nil
 }
+
+// safePrimes generates two distinct `bits` sized safe primes
+func (dp *DkgParticipant) safePrimes(bits uint) (*big.Int, *big.Int, error) {
+	genSafePrime := dp.genSafePrime
+	if genSafePrime == nil {
+		genSafePrime = core.GenerateSafePrime
+	}
+	values := make(chan *big.Int, 2)
+	errors := make(chan error, 2)
+
+	var p, q *big.Int
+	for p == nil || p.Cmp(q) == 0 {
+		for range []int{1, 2} {
+			go func() {
+				value, err := genSafePrime(bits)
+				values <- value
+				errors <- err
+			}()
+		}
+
+		for _, err := range []error{<-errors, <-errors} {
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		p, q = <-values, <-values
+	}
+	return p, q, nil
+}
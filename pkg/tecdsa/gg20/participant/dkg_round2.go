@@ -11,7 +11,6 @@ import (
 
 	"github.com/coinbase/kryptology/internal"
 	"github.com/coinbase/kryptology/pkg/core"
-	"github.com/coinbase/kryptology/pkg/paillier"
 	"github.com/coinbase/kryptology/pkg/sharing/v1"
 	"github.com/coinbase/kryptology/pkg/tecdsa/gg20/dealer"
 	"github.com/coinbase/kryptology/pkg/tecdsa/gg20/proof"
@@ -24,7 +23,7 @@ type DkgRound2Bcast struct {
 
 // DkgRound2P2PSend contains value that will be P2PSend to all other player Pj
 type DkgRound2P2PSend struct {
-	xij *v1.ShamirShare
+	Xij *v1.ShamirShare
 }
 
 // DkgRound2 implements distributed key generation round 2
@@ -69,7 +68,7 @@ func (dp *DkgParticipant) DkgRound2(params map[uint32]*DkgRound1Bcast) (*DkgRoun
 	dp.state.otherParticipantData = make(map[uint32]*dkgParticipantData)
 
 	// For j = [1...n]
-	expKeySize := 2 * paillier.PaillierPrimeBits
+	expKeySize := 2 * int(dealer.PaillierPrimeBits(dp.Curve))
 	for id, param := range params {
 		// If i = j, Continue
 		if id == dp.id {
@@ -108,7 +107,7 @@ func (dp *DkgParticipant) DkgRound2(params map[uint32]*DkgRound1Bcast) (*DkgRoun
 			return nil, nil, fmt.Errorf("Missing Shamir share to P2P send")
 		}
 		p2PSend[id] = &DkgRound2P2PSend{
-			xij: dp.state.X[id-1],
+			Xij: dp.state.X[id-1],
 		}
 
 		// Store other parties data
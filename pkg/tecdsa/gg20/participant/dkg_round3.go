@@ -115,10 +115,10 @@ func (dp *DkgParticipant) DkgRound3(d map[uint32]*core.Witness, x map[uint32]*v1
 			Y:     new(big.Int).Set(y.Y),
 		}
 		// 15. for k = [1,...,t]
-		for k := 0; k < int(dp.state.Threshold); k++ {
+		for k := 1; k < int(dp.state.Threshold); k++ {
 			// 16. compute ck = pj^k mod q
 			pj := big.NewInt(int64(id))
-			ck, err := core.Mul(pj, big.NewInt(int64(k+1)), dp.Curve.Params().N)
+			ck, err := core.Exp(pj, big.NewInt(int64(k)), dp.Curve.Params().N)
 			if err != nil {
 				return nil, err
 			}
@@ -13,6 +13,7 @@ import (
 	"github.com/coinbase/kryptology/internal"
 	"github.com/coinbase/kryptology/pkg/core/curves"
 	"github.com/coinbase/kryptology/pkg/paillier"
+	"github.com/coinbase/kryptology/pkg/sharing/v1"
 	"github.com/coinbase/kryptology/pkg/tecdsa/gg20/dealer"
 )
 
@@ -76,6 +77,14 @@ func (dp *DkgParticipant) DkgRound4(psfProof map[uint32]paillier.PsfProof) (*Dkg
 			ProofParams: data.ProofParams,
 		}
 	}
+	participantData[dp.id] = &DkgParticipantData{
+		PublicKey: dp.state.Pk,
+		ProofParams: &dealer.ProofParams{
+			N:  dp.state.N,
+			H1: dp.state.H1,
+			H2: dp.state.H2,
+		},
+	}
 
 	// Return all necessary information to complete signing
 	// the proof params, paillier public keys, and public commitments
@@ -89,3 +98,38 @@ func (dp *DkgParticipant) DkgRound4(psfProof map[uint32]paillier.PsfProof) (*Dkg
 		ParticipantData: participantData,
 	}, nil
 }
+
+// SignerData converts the DKG result of the participant with the given identifier into the input of NewSigner
+func (result *DkgResult) SignerData(id uint32) (*dealer.ParticipantData, error) {
+	if result == nil || result.VerificationKey == nil || result.SigningKeyShare == nil || result.EncryptionKey == nil {
+		return nil, internal.ErrNilArguments
+	}
+	if id == 0 || int(id) > len(result.PublicShares) {
+		return nil, fmt.Errorf("invalid participant identifier %d", id)
+	}
+	curve := result.VerificationKey.Curve
+	field := curves.NewField(curve.Params().N)
+
+	publicShares := make(map[uint32]*dealer.PublicShare, len(result.PublicShares))
+	for i, point := range result.PublicShares {
+		publicShares[uint32(i+1)] = &dealer.PublicShare{Point: point}
+	}
+	encryptKeys := make(map[uint32]*paillier.PublicKey, len(result.ParticipantData))
+	proofParams := make(map[uint32]*dealer.ProofParams, len(result.ParticipantData))
+	for j, data := range result.ParticipantData {
+		encryptKeys[j] = data.PublicKey
+		proofParams[j] = data.ProofParams
+	}
+	return &dealer.ParticipantData{
+		Id:         id,
+		DecryptKey: result.EncryptionKey,
+		SecretKeyShare: &dealer.Share{
+			ShamirShare: v1.NewShamirShare(id, result.SigningKeyShare.Bytes(), field),
+			Point:       result.PublicShares[id-1],
+		},
+		EcdsaPublicKey: result.VerificationKey,
+		KeyGenType:     dealer.DistributedKeyGenType{ProofParams: proofParams},
+		PublicShares:   publicShares,
+		EncryptKeys:    encryptKeys,
+	}, nil
+}
@@ -8,7 +8,10 @@ package participant
 
 import (
 	"crypto/elliptic"
+	"crypto/sha512"
+	"fmt"
 	"math/big"
+	"sync"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec"
@@ -141,6 +144,34 @@ func TestDkgRound3Works(t *testing.T) {
 	require.True(t, participants[1].state.Y.Equals(pk))
 }
 
+// TestDkgRound3PublicShares checks that the public share X_j = sum(v_k * j^k) of every participant matches its
+// signing key share, with a polynomial of degree 2 so that the powers of j differ from its multiples
+func TestDkgRound3PublicShares(t *testing.T) {
+	curve := btcec.S256()
+	playerCnt := 4
+	playerMin := 3
+	participants := setupDkgRound3ParticipantMap(curve, playerMin, playerCnt)
+	decommitments := setupDkgRound3Commitments(t, participants, playerCnt)
+
+	for id, p := range participants {
+		shares := make(map[uint32]*v1.ShamirShare, playerCnt)
+		for from, q := range participants {
+			shares[from] = q.state.X[id-1]
+		}
+		_, err := p.DkgRound3(decommitments, shares)
+		require.NoError(t, err)
+	}
+
+	for _, p := range participants {
+		require.Len(t, p.state.PublicShares, playerCnt)
+		for id, q := range participants {
+			Xj, err := curves.NewScalarBaseMult(curve, q.state.Xi)
+			require.NoError(t, err)
+			require.True(t, p.state.PublicShares[id-1].Equals(Xj), "public share of participant %d", id)
+		}
+	}
+}
+
 func TestDkgRound3RepeatCall(t *testing.T) {
 	// Setup
 	curve := btcec.S256()
@@ -1043,3 +1074,191 @@ func TestDkgFullRoundsWorks(t *testing.T) {
 	require.Equal(t, dkgR4Out[1].ParticipantData[3].ProofParams, dkgR4Out[2].ParticipantData[3].ProofParams)
 	require.Equal(t, dkgR4Out[2].ParticipantData[1].ProofParams, dkgR4Out[3].ParticipantData[1].ProofParams)
 }
+
+func TestDkgThenSign(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+	curve := btcec.S256()
+	hash, err := core.Hash([]byte("A quorum of two signs for three."), curve)
+	require.NoError(t, err)
+	dkgThenSign(t, curve, core.GenerateSafePrime, hash.Bytes(), k256Verifier)
+}
+
+func TestDkgThenSignP384(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+	hash := sha512.Sum384([]byte("A quorum of two signs for three."))
+	dkgThenSign(t, elliptic.P384(), testSafePrimes(testPrimes1536), hash[:], ecdsaVerifier)
+}
+
+func TestDkgThenSignP521(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+	hash := sha512.Sum512([]byte("A quorum of two signs for three."))
+	dkgThenSign(t, elliptic.P521(), testSafePrimes(testPrimes2084), hash[:], ecdsaVerifier)
+}
+
+// testSafePrimes hands out primes in turn instead of generating safe primes, so that two consecutive ones differ
+func testSafePrimes(primes []*big.Int) func(uint) (*big.Int, error) {
+	var mutex sync.Mutex
+	next := 0
+	return func(bits uint) (*big.Int, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		prime := primes[next%len(primes)]
+		next++
+		if prime.BitLen() != int(bits) {
+			return nil, fmt.Errorf("no %v-bit test prime", bits)
+		}
+		return prime, nil
+	}
+}
+
+// dkgThenSign runs a DKG of three participants on curve and lets two of them sign hash
+func dkgThenSign(t *testing.T, curve elliptic.Curve, genSafePrime func(uint) (*big.Int, error), hash []byte, verify curves.EcdsaVerify) {
+	total := uint32(3)
+	threshold := uint32(2)
+
+	dkgParticipants := make(map[uint32]*DkgParticipant, total)
+	for id := uint32(1); id <= total; id++ {
+		p, err := NewDkgParticipant(curve, id)
+		require.NoError(t, err)
+		p.genSafePrime = genSafePrime
+		dkgParticipants[id] = p
+	}
+
+	dkgR1Out := make(map[uint32]*DkgRound1Bcast, total)
+	for id, p := range dkgParticipants {
+		out, err := p.DkgRound1(threshold, total)
+		require.NoError(t, err)
+		dkgR1Out[id] = out
+	}
+
+	decommitments := make(map[uint32]*core.Witness, total)
+	dkgR2P2PSend := make(map[uint32]map[uint32]*DkgRound2P2PSend, total)
+	for id, p := range dkgParticipants {
+		bcast, p2p, err := p.DkgRound2(dkgR1Out)
+		require.NoError(t, err)
+		decommitments[id] = bcast.Di
+		dkgR2P2PSend[id] = p2p
+	}
+
+	dkgR3Out := make(map[uint32]paillier.PsfProof, total)
+	for id, p := range dkgParticipants {
+		shares := make(map[uint32]*v1.ShamirShare, total-1)
+		for from, p2p := range dkgR2P2PSend {
+			if from != id {
+				shares[from] = p2p[id].Xij
+			}
+		}
+		psfProof, err := p.DkgRound3(decommitments, shares)
+		require.NoError(t, err)
+		dkgR3Out[id] = psfProof
+	}
+
+	dkgR4Out := make(map[uint32]*DkgResult, total)
+	for id, p := range dkgParticipants {
+		result, err := p.DkgRound4(dkgR3Out)
+		require.NoError(t, err)
+		dkgR4Out[id] = result
+	}
+
+	// Sign with a quorum that leaves out participant 2
+	cosigners := []uint32{1, 3}
+	signers := make(map[uint32]*Signer, len(cosigners))
+	for _, id := range cosigners {
+		info, err := dkgR4Out[id].SignerData(id)
+		require.NoError(t, err)
+		signers[id], err = NewSigner(info, cosigners)
+		require.NoError(t, err)
+	}
+	_, err := dkgR4Out[1].SignerData(total + 1)
+	require.Error(t, err)
+
+	r1Bcast := make(map[uint32]*Round1Bcast, len(signers))
+	r1P2p := make(map[uint32]map[uint32]*Round1P2PSend, len(signers))
+	for id, s := range signers {
+		r1Bcast[id], r1P2p[id], err = s.SignRound1()
+		require.NoError(t, err)
+	}
+
+	r2P2p := make(map[uint32]map[uint32]*P2PSend, len(signers))
+	for id, s := range signers {
+		bcast := make(map[uint32]*Round1Bcast, len(signers)-1)
+		p2p := make(map[uint32]*Round1P2PSend, len(signers)-1)
+		for from := range signers {
+			if from != id {
+				bcast[from] = r1Bcast[from]
+				p2p[from] = r1P2p[from][id]
+			}
+		}
+		r2P2p[id], err = s.SignRound2(bcast, p2p)
+		require.NoError(t, err)
+	}
+
+	r3Bcast := make(map[uint32]*Round3Bcast, len(signers))
+	for id, s := range signers {
+		p2p := make(map[uint32]*P2PSend, len(signers)-1)
+		for from := range signers {
+			if from != id {
+				p2p[from] = r2P2p[from][id]
+			}
+		}
+		r3Bcast[id], err = s.SignRound3(p2p)
+		require.NoError(t, err)
+	}
+
+	r4Bcast := make(map[uint32]*Round4Bcast, len(signers))
+	for id, s := range signers {
+		bcast := make(map[uint32]*Round3Bcast, len(signers)-1)
+		for from := range signers {
+			if from != id {
+				bcast[from] = r3Bcast[from]
+			}
+		}
+		r4Bcast[id], err = s.SignRound4(bcast)
+		require.NoError(t, err)
+	}
+
+	r5Bcast := make(map[uint32]*Round5Bcast, len(signers))
+	r5P2p := make(map[uint32]map[uint32]*Round5P2PSend, len(signers))
+	for id, s := range signers {
+		bcast := make(map[uint32]*Round4Bcast, len(signers)-1)
+		for from := range signers {
+			if from != id {
+				bcast[from] = r4Bcast[from]
+			}
+		}
+		r5Bcast[id], r5P2p[id], err = s.SignRound5(bcast)
+		require.NoError(t, err)
+	}
+
+	r6Bcast := make(map[uint32]*Round6FullBcast, len(signers))
+	for id, s := range signers {
+		bcast := make(map[uint32]*Round5Bcast, len(signers)-1)
+		p2p := make(map[uint32]*Round5P2PSend, len(signers)-1)
+		for from := range signers {
+			if from != id {
+				bcast[from] = r5Bcast[from]
+				p2p[from] = r5P2p[from][id]
+			}
+		}
+		r6Bcast[id], err = s.SignRound6Full(hash, bcast, p2p)
+		require.NoError(t, err)
+	}
+
+	for id, s := range signers {
+		bcast := make(map[uint32]*Round6FullBcast, len(signers)-1)
+		for from := range signers {
+			if from != id {
+				bcast[from] = r6Bcast[from]
+			}
+		}
+		signature, err := s.SignOutput(bcast)
+		require.NoError(t, err)
+		require.True(t, verify(dkgR4Out[id].VerificationKey, hash, signature))
+	}
+}
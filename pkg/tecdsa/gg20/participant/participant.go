@@ -301,6 +301,24 @@ type DkgParticipant struct {
 	state *dkgstate
 	id    uint32
 	Round uint
+	// genSafePrime generates the safe primes of the Paillier keys and the proof parameters, core.GenerateSafePrime if nil
+	genSafePrime func(uint) (*big.Int, error)
+}
+
+// NewDkgParticipant creates the DKG player with the given identifier, which ranges from 1 to the total number of players
+func NewDkgParticipant(curve elliptic.Curve, id uint32) (*DkgParticipant, error) {
+	if curve == nil {
+		return nil, internal.ErrNilArguments
+	}
+	if id == 0 {
+		return nil, fmt.Errorf("participant identifier cannot be zero")
+	}
+	return &DkgParticipant{
+		Curve: curve,
+		id:    id,
+		Round: 1,
+		state: &dkgstate{},
+	}, nil
 }
 
 type dkgParticipantData struct {
@@ -66,6 +66,22 @@ var (
 		tt.B10("52495647838749571441531580865340679598533348873590977282663145916368795913408897399822291638579504238082829052094508345857857144973446573810004060341650816108578548997792700057865473467391946766537119012441105169305106247003867011741811274367120479722991749924616247396514197345075177297436299446651331187067"),
 		tt.B10("118753381771703394804894143450628876988609300829627946826004421079000316402854210786451078221445575185505001470635997217855372731401976507648597119694813440063429052266569380936671291883364036649087788968029662592370202444662489071262833666489940296758935970249316300642591963940296755031586580445184253416139"),
 	}
+	// 1536-bit safe primes sized for P-384, see dealer.PaillierPrimeBits
+	testPrimes1536 = []*big.Int{
+		tt.B10("2209587124978717500678345243719550572526297744235615264195675359437282883344209037237098586301098905995747904285119651143706110681446153610166063692978625886218832749415470593586512403165480027946441711507900401092773709774557444531510909444472475916761503350970313821597489749291471859211540487920930193048567244882629380095474838189537265882000099110507728462715953705097473799503631174989033693512891833302225272657464841325966930764709812457397418163356320599"),
+		tt.B10("1951826166897752691085792129800656172911961096726215246757289984729324520389911847445489635678429467467165612079731204317448646564383393771684613985666852888528197869082492235281330907864686512876807452263219549491137593962377229144836730672457175821970985118666416113089448230361750451511340948417911467342372660118282740100182947256402419973581227842043383269038224656492555389786055545389804262333095208211859540452037000358358422131690596247003912720905912703"),
+		tt.B10("2261237679891131340532980864369260445035140498292260077575209627097292225092067258039748736208935980993060913396643840569336159859588688921264990554069310985966650675656245301670771016233418344914878161667309609112319436017094743387469923422648025523270182465505986425191367358612778864735624641762621124358816747633442433623046938803783956051292301325810219139759179645309138648673717602043024016656023755163016477557970539625518673400043333552622141397798236143"),
+		tt.B10("2227987945303031976073470076508185521271449222197673051316051868397690608051503539195644498024376700950628332067914908237238305745335883497577864327732485724291264070637483476996787434605678356876058604703052417721704248052696517891713887741133288791248552777824598675891500329606032517419518144282794635246642532720760425587228195951796490260471685144999232822728970331730966583922580391475444120372883787858311920230949560348580647670711631579842580548932268999"),
+		tt.B10("2394916823964075171300002164320270835697890601695694493158248999110400629766020910162161900669843541538448497062094215911546661651641610201533627770884486473863154668090138897828892483162570788089625290673494968549649515155005022428744816925244711118707478579966070475925140680149972938181360070627892136314177225250003202140666286551118712600259144784154562684103091392661701558982225588367492551215256293565027039989952209753827133397515471671394179572930402419"),
+	}
+	// 2084-bit safe primes sized for P-521
+	testPrimes2084 = []*big.Int{
+		tt.B10("2136213695234014776929109648074047116866031587753253842723266799338947330457123321806907161447187846164127259451663385224485386139123163555521202266650168353301225895282620741517745250382637671212886347518858623228569800057581095875765306359999706793623056912373861147532790327253823232335475328875529818996554404875662129247200923759016043547514883850342269848656421872713515866047075867382799058434579290082571504756304169610889121417089317651439530859696027865449302551152549673301406819699028012490531638292208705186623896089457994939274098105138976265509865964073456479322109522371990049205412397522686052604598414233375999"),
+		tt.B10("1830788560628274044062350282824710248276564393902571178035147751231247141750717732412731593644722920705290595103484411117480394956558001872011136596431801730843292542770231503347450139951846883260128577375259860180310912628469533095712317916065744466301658693609852113693012805156604861207764786157945013561702572573791538703841047433180604255357486207216974045414457905803518309827597847049968143383280148139367904722217727639166138720253501246092104978215396654057166234861649016810493404255109714174526297553876560770159215445636767055458709081900292697555961627011290077560535397462570542868697741884275928102173922339586063"),
+		tt.B10("1992100343934472189189164938118994629918991214841648676986306131436850396421155675933097370419410960943482893987806201760079798510304653995851996991816383771432494996857537052673774782540828385156185735366406807880896067984092675153074645090575689985330315041895242282970888665514184377391308509911780363016540950335326547488851828967602045344775060928391662058932811553757282093262930598598130569957702319900695153842190897013986217867628925157481018753314352935225744853815091833751907023162878269949274563172665688451028088073175170709527026122794964278286255028088611080864408697799583366411223429413289969193381712166371467"),
+		tt.B10("1797293681928140625937610701977421402143199714593168332999410599077837653042844318406655004613752146185939593953671566037763683831247744887763841733279286319191835696091712344403122257596626837352561649345464346657060733755661903639463230684456461438972332955989939605306494231591356967085560820841273193447912198084771077935578679919885380826948331887140108191012870892999174963276326453809799355341105495631853405459841294146483904061186455178841589813131595333649162102452902159321984244824584297106961474466634056276414158299220329671005378107332841258100990387921712660482450512218969922169280965867167597269667513595525843"),
+		tt.B10("2117329717285255073435495322128927851319056079254339466109338261115325842755305959241543704869689760829323653123810102690568223146952410859455579724016521317585758866547074525634756092353715986455801848755460728460245295612442849121964211857965659792539710949245501818886803642138638747335203669173458456855541631896384956285558945107426482433259227905321632453772583176305655437999708897092761326196390228246600898291678043043752572598242879175937387704626575161966216584642415665687476057540546515438817934352447113590541239999161677281999854130134956870413193287779765279483427543634282347828668235364603422520641843318454223"),
+	}
 	dummyVerifier = func(pubKey *curves.EcPoint, hash []byte, signature *curves.EcdsaSignature) bool {
 		return false
 	}
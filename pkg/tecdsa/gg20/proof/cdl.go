@@ -13,6 +13,7 @@ import (
 	"math/big"
 
 	mod "github.com/coinbase/kryptology/pkg/core"
+	"github.com/coinbase/kryptology/pkg/tecdsa/gg20/dealer"
 )
 
 const ell = 128
@@ -128,7 +129,8 @@ func (p CdlProof) Verify(cv *CdlVerifyParams) error {
 
 	// When you multiply two numbers m and n, the number of bits in the product cannot be less than max(m,n) and cannot be more than (m+n).
 	// (Unless one of the two numbers is a 0).
-	if cv.N.BitLen() < 1024 {
+	// The range proofs need a modulus of about q^8, so the product of two safe primes sized for the curve.
+	if cv.N.BitLen() < 2*int(dealer.PaillierPrimeBits(cv.Curve))-1 {
 		return fmt.Errorf("Modulus length is invalid")
 	}
 
@@ -7,6 +7,7 @@
 package proof
 
 import (
+	"crypto/elliptic"
 	"encoding/json"
 	"math/big"
 	"testing"
@@ -355,3 +356,46 @@ func TestSmallModulus(t *testing.T) {
 		}
 	}
 }
+
+// A 2048-bit modulus suits 256-bit curves but is too small for the range proofs on P-521
+func TestSmallModulusForCurve(t *testing.T) {
+	P := tt.B10("165767109498679333927172882988675240871786832994588749158965767673945611886648976955012980205938446757878886183316455017521300834957764745162353525575268392435587843607612470895721541047254417540270756145682130189228024724293713540420700979243740619312487567234262826018540404128735554374146182348085336281439")
+	Q := tt.B10("138451631119797627683944394514738428837020078954518535124773153508211993035282574374708542371034182930645915695336645534003697272777264261785750332260172837811934440302323563909582466079621984165168311186255004180759468783222026674257257121598775537240537892901360209680738434517685889621651841176243400986247")
+	pi := new(big.Int).Rsh(P, 1)
+	qi := new(big.Int).Rsh(Q, 1)
+	n := new(big.Int).Mul(P, Q)
+	f, _ := mod.Rand(n)
+	h1 := new(big.Int).Exp(f, big.NewInt(2), n)
+	alpha, _ := mod.Rand(new(big.Int).Mul(pi, qi))
+	h2 := new(big.Int).Exp(h1, alpha, n)
+	for _, test := range []struct {
+		curve elliptic.Curve
+		valid bool
+	}{
+		{btcec.S256(), true},
+		{elliptic.P521(), false},
+	} {
+		pp := &CdlProofParams{
+			Curve:   test.curve,
+			Pi:      pi,
+			Qi:      qi,
+			H1:      h1,
+			H2:      h2,
+			ScalarX: alpha,
+			N:       n,
+		}
+		proof, err := pp.Prove()
+		require.NoError(t, err)
+		err = proof.Verify(&CdlVerifyParams{
+			Curve: test.curve,
+			H1:    pp.H1,
+			H2:    pp.H2,
+			N:     pp.N,
+		})
+		if test.valid {
+			require.NoError(t, err)
+		} else {
+			require.Error(t, err)
+		}
+	}
+}
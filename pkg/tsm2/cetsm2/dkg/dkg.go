@@ -2,8 +2,9 @@ package dkg
 
 import (
 	"crypto/rand"
+	"fmt"
+
 	"github.com/coinbase/kryptology/pkg/core/curves"
-	"github.com/coinbase/kryptology/pkg/ot/base/simplest"
 	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
 	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
 )
@@ -12,11 +13,9 @@ import (
 
 // pk is stored in the Statement of pkProof
 
-func PkComProve(curve *curves.Curve) (curves.Scalar, *schnorr.Proof, schnorr.Commitment, []byte, error) {
+func PkComProve(curve *curves.Curve, sessionId []byte) (curves.Scalar, *schnorr.Proof, schnorr.Commitment, []byte, error) {
 	sk := curve.Scalar.Random(rand.Reader)
 
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	sessionId := uniqueSessionId[:]
 	pkProver := schnorr.NewProver(curve, nil, sessionId)
 	pkProof, commitment, err := pkProver.ProveCommit(sk)
 
@@ -29,14 +28,17 @@ func PkDeComVerify(curve *curves.Curve, proof *schnorr.Proof, commitment schnorr
 	return schnorr.DecommitVerify(proof, commitment, curve, nil, sessionId)
 }
 
+// PkDeComBatchVerify is PkDeComVerify for the proofs of all the other parties at once
+func PkDeComBatchVerify(curve *curves.Curve, proofs []*schnorr.Proof, commitments []schnorr.Commitment, sessionIds [][]byte) error {
+	return schnorr.DecommitBatchVerify(proofs, commitments, curve, nil, sessionIds)
+}
+
 // functions for step 3
 
 // currentJointPk is stored in the Statement2 of jointPkProof
 // for the last party, currentJointPk is the calculated joint pk for all parties
 
-func JointPkCompProve(curve *curves.Curve, sk curves.Scalar, formerJointPk curves.Point) (*chaumpedersen.Proof, []byte, error) {
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	sessionId := uniqueSessionId[:]
+func JointPkCompProve(curve *curves.Curve, sk curves.Scalar, formerJointPk curves.Point, sessionId []byte) (*chaumpedersen.Proof, []byte, error) {
 	jointPkProver, err := chaumpedersen.NewProver(curve, nil, formerJointPk, sessionId)
 	if err != nil {
 		return nil, sessionId, err
@@ -50,3 +52,114 @@ func JointPkCompProve(curve *curves.Curve, sk curves.Scalar, formerJointPk curve
 func JointPkVerify(curve *curves.Curve, formerJointPk curves.Point, proof *chaumpedersen.Proof, sessionId []byte) error {
 	return chaumpedersen.Verify(proof, curve, nil, formerJointPk, sessionId)
 }
+
+// functions for refresh
+
+// RhoComProve draws the blinding rho that a party passes to the next party of the chain and commits to a proof of
+// knowledge of it, whose public counterpart rho*G is stored in the Statement of rhoProof
+// rho itself only goes to the next party, over a private channel, which checks it with RhoCheck
+func RhoComProve(curve *curves.Curve, sessionId []byte) (curves.Scalar, *schnorr.Proof, schnorr.Commitment, []byte, error) {
+	rho := curve.Scalar.Random(rand.Reader)
+
+	rhoProver := schnorr.NewProver(curve, nil, sessionId)
+	rhoProof, commitment, err := rhoProver.ProveCommit(rho)
+
+	return rho, rhoProof, commitment, sessionId, err
+}
+
+func RhoDeComVerify(curve *curves.Curve, proof *schnorr.Proof, commitment schnorr.Commitment, sessionId []byte) error {
+	return schnorr.DecommitVerify(proof, commitment, curve, nil, sessionId)
+}
+
+// RhoDeComBatchVerify is RhoDeComVerify for the proofs of all the other parties at once
+func RhoDeComBatchVerify(curve *curves.Curve, proofs []*schnorr.Proof, commitments []schnorr.Commitment, sessionIds [][]byte) error {
+	return schnorr.DecommitBatchVerify(proofs, commitments, curve, nil, sessionIds)
+}
+
+// RhoCheck checks that the rho received from the former party is the one behind its published rhoProof
+func RhoCheck(curve *curves.Curve, rho curves.Scalar, rhoProof *schnorr.Proof) error {
+	if !curve.ScalarBaseMult(rho).Equal(rhoProof.Statement) {
+		return fmt.Errorf("rho does not match its proof")
+	}
+	return nil
+}
+
+// SkRerandomize multiplies sk by rho and by the inverse of formerRho, which the previous party drew
+// the first party passes a nil formerRho and the last party a nil rho, hence the joint pk is unchanged
+func SkRerandomize(sk curves.Scalar, rho curves.Scalar, formerRho curves.Scalar) (curves.Scalar, error) {
+	if rho != nil {
+		sk = sk.Mul(rho)
+	}
+	if formerRho != nil {
+		formerRhoInvert, err := formerRho.Invert()
+		if err != nil {
+			return nil, err
+		}
+		sk = sk.Mul(formerRhoInvert)
+	}
+	return sk, nil
+}
+
+// SkReProve proves that the re-randomized pk' of a party is pk * rho / formerRho for the blindings behind the
+// published rho*G and formerRho*G. The middle point X = rho*pk = formerRho*pk' is stored in the Statement2 of both
+// proofs. The first party returns no formerRhoProof, as X = pk', and the last party no rhoProof, as X = pk.
+func SkReProve(curve *curves.Curve, sk curves.Scalar, rho curves.Scalar, formerRho curves.Scalar, refreshedSk curves.Scalar,
+	sessionId []byte) (*chaumpedersen.Proof, *chaumpedersen.Proof, []byte, error) {
+	var rhoProof, formerRhoProof *chaumpedersen.Proof
+	if rho != nil {
+		rhoProver, err := chaumpedersen.NewProver(curve, nil, curve.ScalarBaseMult(sk), sessionId)
+		if err != nil {
+			return nil, nil, sessionId, err
+		}
+		if rhoProof, err = rhoProver.Prove(rho); err != nil {
+			return nil, nil, sessionId, err
+		}
+	}
+	if formerRho != nil {
+		formerRhoProver, err := chaumpedersen.NewProver(curve, nil, curve.ScalarBaseMult(refreshedSk), sessionId)
+		if err != nil {
+			return nil, nil, sessionId, err
+		}
+		if formerRhoProof, err = formerRhoProver.Prove(formerRho); err != nil {
+			return nil, nil, sessionId, err
+		}
+	}
+	return rhoProof, formerRhoProof, sessionId, nil
+}
+
+// SkReVerify checks the proofs of SkReProve against the former and refreshed pks of a party and the published
+// counterparts of its blindings, where Rho is nil for the last party and FormerRho for the first one
+func SkReVerify(curve *curves.Curve, pk curves.Point, refreshedPk curves.Point, Rho curves.Point, FormerRho curves.Point,
+	rhoProof *chaumpedersen.Proof, formerRhoProof *chaumpedersen.Proof, sessionId []byte) error {
+	X := pk
+	if Rho != nil {
+		if rhoProof == nil || rhoProof.Statement1 == nil || !rhoProof.Statement1.Equal(Rho) {
+			return fmt.Errorf("proof is not about the published rho")
+		}
+		if err := chaumpedersen.Verify(rhoProof, curve, nil, pk, sessionId); err != nil {
+			return err
+		}
+		X = rhoProof.Statement2
+	}
+	if FormerRho == nil {
+		if !X.Equal(refreshedPk) {
+			return fmt.Errorf("the refreshed pk is not blinded by rho")
+		}
+		return nil
+	}
+	if formerRhoProof == nil || formerRhoProof.Statement1 == nil || !formerRhoProof.Statement1.Equal(FormerRho) {
+		return fmt.Errorf("proof is not about the published former rho")
+	}
+	if formerRhoProof.Statement2 == nil || !formerRhoProof.Statement2.Equal(X) {
+		return fmt.Errorf("the refreshed pk is not blinded by rho and former rho")
+	}
+	return chaumpedersen.Verify(formerRhoProof, curve, nil, refreshedPk, sessionId)
+}
+
+// PkReComProve commits to a proof of the re-randomized sk, whose pk is stored in the Statement of pkProof
+func PkReComProve(curve *curves.Curve, sk curves.Scalar, sessionId []byte) (*schnorr.Proof, schnorr.Commitment, []byte, error) {
+	pkProver := schnorr.NewProver(curve, nil, sessionId)
+	pkProof, commitment, err := pkProver.ProveCommit(sk)
+
+	return pkProof, commitment, sessionId, err
+}
@@ -28,7 +28,7 @@ func TestDKGOverMultipleCurves(t *testing.T) {
 	for i, curve := range curveInstances {
 		// generate pks and proofs
 		for id := 1; id <= n; id++ {
-			sk, pkProof, commitment, sessionId, err := PkComProve(curve)
+			sk, pkProof, commitment, sessionId, err := PkComProve(curve, nil)
 			require.NoError(t, err, fmt.Sprintf("failed in curve %d when generating pk with comproof for party %d", i, id))
 			sks[id-1] = sk
 			pkProofs[id-1] = pkProof
@@ -45,13 +45,13 @@ func TestDKGOverMultipleCurves(t *testing.T) {
 		// generate (mid-)joint pks
 		for id := 2; id <= n; id++ {
 			if id == 2 {
-				jointPkProof, sessionId, err := JointPkCompProve(curve, sks[id-1], pkProofs[id-2].Statement)
+				jointPkProof, sessionId, err := JointPkCompProve(curve, sks[id-1], pkProofs[id-2].Statement, nil)
 				require.NoError(t, err, fmt.Sprintf("failed in curve %d when party %d computing joint pk with proof", i, id))
 				jointPkProofs[id-1] = jointPkProof
 				jointPkProofSessionIds[id-1] = sessionId
 				continue
 			}
-			jointPkProof, sessionId, err := JointPkCompProve(curve, sks[id-1], jointPkProofs[id-2].Statement2)
+			jointPkProof, sessionId, err := JointPkCompProve(curve, sks[id-1], jointPkProofs[id-2].Statement2, nil)
 			require.NoError(t, err, fmt.Sprintf("failed in curve %d when party %d computing joint pk with proof", i, id))
 			jointPkProofs[id-1] = jointPkProof
 			jointPkProofSessionIds[id-1] = sessionId
@@ -87,7 +87,7 @@ func BenchmarkDKG(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		// generate pks and proofs
 		for id := 1; id <= n; id++ {
-			sk, pkProof, commitment, sessionId, err := PkComProve(curve)
+			sk, pkProof, commitment, sessionId, err := PkComProve(curve, nil)
 			require.NoError(b, err, fmt.Sprintf("generating pk with comproof for party %d", id))
 			sks[id-1] = sk
 			pkProofs[id-1] = pkProof
@@ -109,13 +109,13 @@ func BenchmarkDKG(b *testing.B) {
 		// generate (mid-)joint pks
 		for id := 2; id <= n; id++ {
 			if id == 2 {
-				jointPkProof, sessionId, err := JointPkCompProve(curve, sks[id-1], pkProofs[id-2].Statement)
+				jointPkProof, sessionId, err := JointPkCompProve(curve, sks[id-1], pkProofs[id-2].Statement, nil)
 				require.NoError(b, err, fmt.Sprintf("party %d computing joint pk with proof", id))
 				jointPkProofs[id-1] = jointPkProof
 				jointPkProofSessionIds[id-1] = sessionId
 				continue
 			}
-			jointPkProof, sessionId, err := JointPkCompProve(curve, sks[id-1], jointPkProofs[id-2].Statement2)
+			jointPkProof, sessionId, err := JointPkCompProve(curve, sks[id-1], jointPkProofs[id-2].Statement2, nil)
 			require.NoError(b, err, fmt.Sprintf("party %d computing joint pk with proof", id))
 			jointPkProofs[id-1] = jointPkProof
 			jointPkProofSessionIds[id-1] = sessionId
@@ -139,3 +139,63 @@ func BenchmarkDKG(b *testing.B) {
 	}
 
 }
+
+func TestSkRerandomize(t *testing.T) {
+	for _, curve := range []*curves.Curve{curves.K256(), curves.SM2()} {
+		const n = 5
+		product := curve.Scalar.One()
+		sks := make([]curves.Scalar, n)
+		for k := range sks {
+			sks[k], _, _, _, _ = PkComProve(curve, nil)
+			product = product.Mul(sks[k])
+		}
+
+		// the product of the re-randomized keys, hence the joint pk, is unchanged
+		var formerRho curves.Scalar
+		var formerRhoProof *schnorr.Proof
+		refreshedProduct := curve.Scalar.One()
+		for k := range sks {
+			var rho curves.Scalar
+			var rhoProof *schnorr.Proof
+			if k < n-1 {
+				var commitment schnorr.Commitment
+				var sessionId []byte
+				var err error
+				rho, rhoProof, commitment, sessionId, err = RhoComProve(curve, nil)
+				require.NoError(t, err)
+				require.NoError(t, RhoDeComVerify(curve, rhoProof, commitment, sessionId))
+			}
+			if formerRho != nil {
+				require.NoError(t, RhoCheck(curve, formerRho, formerRhoProof))
+				require.Error(t, RhoCheck(curve, formerRho.Double(), formerRhoProof))
+			}
+
+			sk, err := SkRerandomize(sks[k], rho, formerRho)
+			require.NoError(t, err)
+			require.NotEqual(t, 0, sk.Cmp(sks[k]))
+			refreshedProduct = refreshedProduct.Mul(sk)
+
+			pkProof, commitment, sessionId, err := PkReComProve(curve, sk, nil)
+			require.NoError(t, err)
+			require.NoError(t, PkDeComVerify(curve, pkProof, commitment, sessionId))
+			require.True(t, pkProof.Statement.Equal(curve.ScalarBaseMult(sk)))
+
+			// the refreshed pk is consistent with the former pk and the published blindings
+			var Rho, FormerRho curves.Point
+			if rhoProof != nil {
+				Rho = rhoProof.Statement
+			}
+			if formerRhoProof != nil {
+				FormerRho = formerRhoProof.Statement
+			}
+			pk := curve.ScalarBaseMult(sks[k])
+			reRhoProof, reFormerRhoProof, sessionId, err := SkReProve(curve, sks[k], rho, formerRho, sk, nil)
+			require.NoError(t, err)
+			require.NoError(t, SkReVerify(curve, pk, pkProof.Statement, Rho, FormerRho, reRhoProof, reFormerRhoProof, sessionId))
+			require.Error(t, SkReVerify(curve, pk, pkProof.Statement.Double(), Rho, FormerRho, reRhoProof, reFormerRhoProof, sessionId))
+
+			formerRho, formerRhoProof = rho, rhoProof
+		}
+		require.Equal(t, 0, product.Cmp(refreshedProduct))
+	}
+}
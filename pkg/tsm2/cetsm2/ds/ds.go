@@ -2,20 +2,18 @@ package ds
 
 import (
 	"crypto/rand"
+	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
-	"github.com/coinbase/kryptology/pkg/ot/base/simplest"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
 	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
-	"math/big"
 )
 
 // functions for step 1
 // R_i is stored in nonceProof.Statement
 
-func NonceComProve(curve *curves.Curve, basePoint curves.Point) (curves.Scalar, *schnorr.Proof, schnorr.Commitment, []byte, error) {
+func NonceComProve(curve *curves.Curve, basePoint curves.Point, sessionId []byte) (curves.Scalar, *schnorr.Proof, schnorr.Commitment, []byte, error) {
 	k := curve.Scalar.Random(rand.Reader)
 
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	sessionId := uniqueSessionId[:]
 	nonceProver := schnorr.NewProver(curve, basePoint, sessionId)
 	nonceProof, commitment, err := nonceProver.ProveCommit(k)
 
@@ -28,36 +26,67 @@ func NonceDeComVerify(curve *curves.Curve, basePoint curves.Point, proof *schnor
 	return schnorr.DecommitVerify(proof, commitment, curve, basePoint, sessionId)
 }
 
-func RPartComp(curve *curves.Curve, R curves.Point, message []byte) curves.Scalar {
-	h := curve.Scalar.Hash(message)
+// RPartComp computes r = e + x(R) where e = SM3(Z_A || M) is bound to the signer id
+// and the standard public key jointPk - G
+func RPartComp(curve *curves.Curve, R curves.Point, jointPk curves.Point, signerId []byte, message []byte) (curves.Scalar, error) {
+	h, err := sm2.Digest(curve, signerId, jointPk.Sub(curve.NewGeneratorPoint()), message)
+	if err != nil {
+		return nil, err
+	}
 
-	RAffine := R.ToAffineCompressed()
-	rx, err := curve.Scalar.SetBigInt(new(big.Int).SetBytes(RAffine[1 : 1+(len(RAffine)>>1)]))
+	rx, err := sm2.XCoordinate(curve, R)
 	if err != nil {
-		panic("when computing x-coordinate of R")
+		return nil, err
 	}
 
-	return h.Add(rx)
+	return h.Add(rx), nil
 }
 
 // functions for step 3
 
-func SPartComp(curve *curves.Curve, r curves.Scalar, formerS curves.Scalar, sk curves.Scalar, k curves.Scalar,
-	basePoint curves.Point, currentR curves.Point, currentJointPk curves.Point) curves.Scalar {
-	if basePoint == nil {
-		basePoint = curve.NewGeneratorPoint()
-	}
+// HopError reports the party of the signing chain whose partial signature is invalid.
+// The chain can be retried without that party.
+type HopError struct {
+	// Hop is the position of the party in the chain
+	Hop   int
+	Party uint32
+	Err   error
+}
 
+func (e *HopError) Error() string {
+	return fmt.Sprintf("invalid partial signature from party %d at hop %d: %v", e.Party, e.Hop, e.Err)
+}
+
+func (e *HopError) Unwrap() error {
+	return e.Err
+}
+
+// SPartComp computes the running s of the current hop from the one of the former hop
+// it fails if the result does not pass SPartVerify, e.g. because sk or k are not the ones behind the published values
+func SPartComp(curve *curves.Curve, r curves.Scalar, formerS curves.Scalar, sk curves.Scalar, k curves.Scalar,
+	basePoint curves.Point, currentR curves.Point, currentJointPk curves.Point) (curves.Scalar, error) {
 	skInvert, err := sk.Invert()
 	if err != nil {
-		panic("when computing the invert of sk")
+		return nil, fmt.Errorf("when computing the invert of sk")
 	}
 
 	currentS := skInvert.Mul(formerS.Add(k))
+	if err := SPartVerify(curve, r, currentS, basePoint, currentR, currentJointPk); err != nil {
+		return nil, err
+	}
+
+	return currentS, nil
+}
 
+// SPartVerify checks the running s of a hop, where currentR is the sum of the nonces up to the hop
+// and currentJointPk is the joint pk output by the hop
+func SPartVerify(curve *curves.Curve, r curves.Scalar, currentS curves.Scalar,
+	basePoint curves.Point, currentR curves.Point, currentJointPk curves.Point) error {
+	if basePoint == nil {
+		basePoint = curve.NewGeneratorPoint()
+	}
 	if !currentJointPk.Mul(currentS).Equal(basePoint.Mul(r).Add(currentR)) {
-		panic("failed when computing the s part of the signature")
+		return fmt.Errorf("failed when computing the s part of the signature")
 	}
-
-	return currentS
+	return nil
 }
@@ -38,7 +38,7 @@ func TestDSOverMultipleCurves(t *testing.T) {
 	for i, curve := range curveInstances {
 		// generate pks and proofs
 		for id := 1; id <= n; id++ {
-			sk, pkProof, commitment, sessionId, err := dkg.PkComProve(curve)
+			sk, pkProof, commitment, sessionId, err := dkg.PkComProve(curve, nil)
 			require.NoError(t, err, fmt.Sprintf("failed in curve %d when generating pk with comproof for party %d", i, id))
 			sks[id-1] = sk
 			pkProofs[id-1] = pkProof
@@ -55,13 +55,13 @@ func TestDSOverMultipleCurves(t *testing.T) {
 		// generate (mid-)joint pks
 		for id := 2; id <= n; id++ {
 			if id == 2 {
-				jointPkProof, sessionId, err := dkg.JointPkCompProve(curve, sks[id-1], pkProofs[id-2].Statement)
+				jointPkProof, sessionId, err := dkg.JointPkCompProve(curve, sks[id-1], pkProofs[id-2].Statement, nil)
 				require.NoError(t, err, fmt.Sprintf("failed in curve %d when party %d computing joint pk with proof", i, id))
 				jointPkProofs[id-1] = jointPkProof
 				jointPkProofSessionIds[id-1] = sessionId
 				continue
 			}
-			jointPkProof, sessionId, err := dkg.JointPkCompProve(curve, sks[id-1], jointPkProofs[id-2].Statement2)
+			jointPkProof, sessionId, err := dkg.JointPkCompProve(curve, sks[id-1], jointPkProofs[id-2].Statement2, nil)
 			require.NoError(t, err, fmt.Sprintf("failed in curve %d when party %d computing joint pk with proof", i, id))
 			jointPkProofs[id-1] = jointPkProof
 			jointPkProofSessionIds[id-1] = sessionId
@@ -92,7 +92,7 @@ func TestDSOverMultipleCurves(t *testing.T) {
 			} else {
 				basePoint = jointPkProofs[id-2].Statement2
 			}
-			k, nonceProof, commitment, sessionId, err := NonceComProve(curve, basePoint)
+			k, nonceProof, commitment, sessionId, err := NonceComProve(curve, basePoint, nil)
 			require.NoError(t, err, fmt.Sprintf("faied in curve %d when party %d generating nonce", i, id))
 			nonces[id-1] = k
 			nonceProofs[id-1] = nonceProof
@@ -118,7 +118,8 @@ func TestDSOverMultipleCurves(t *testing.T) {
 		for id := 2; id <= n; id++ {
 			R = R.Add(nonceProofs[id-1].Statement)
 		}
-		r := RPartComp(curve, R, message)
+		r, err := RPartComp(curve, R, jointPkProofs[n-1].Statement2, nil, message)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d when computing the r part", i))
 
 		// compute s-part of signature
 		s := r
@@ -128,10 +129,11 @@ func TestDSOverMultipleCurves(t *testing.T) {
 				RId = RId.Add(nonceProofs[j-1].Statement)
 			}
 			if id == 1 {
-				s = SPartComp(curve, r, s, sks[id-1], nonces[id-1], nil, RId, pkProofs[0].Statement)
+				s, err = SPartComp(curve, r, s, sks[id-1], nonces[id-1], nil, RId, pkProofs[0].Statement)
 			} else {
-				s = SPartComp(curve, r, s, sks[id-1], nonces[id-1], nil, RId, jointPkProofs[id-1].Statement2)
+				s, err = SPartComp(curve, r, s, sks[id-1], nonces[id-1], nil, RId, jointPkProofs[id-1].Statement2)
 			}
+			require.NoError(t, err, fmt.Sprintf("failed in computing the s part of party %d", id))
 		}
 	}
 }
@@ -159,7 +161,7 @@ func BenchmarkDS(b *testing.B) {
 
 	// generate pks and proofs
 	for id := 1; id <= n; id++ {
-		sk, pkProof, commitment, sessionId, err := dkg.PkComProve(curve)
+		sk, pkProof, commitment, sessionId, err := dkg.PkComProve(curve, nil)
 		require.NoError(b, err, fmt.Sprintf("generating pk with comproof for party %d", id))
 		sks[id-1] = sk
 		pkProofs[id-1] = pkProof
@@ -176,13 +178,13 @@ func BenchmarkDS(b *testing.B) {
 	// generate (mid-)joint pks
 	for id := 2; id <= n; id++ {
 		if id == 2 {
-			jointPkProof, sessionId, err := dkg.JointPkCompProve(curve, sks[id-1], pkProofs[id-2].Statement)
+			jointPkProof, sessionId, err := dkg.JointPkCompProve(curve, sks[id-1], pkProofs[id-2].Statement, nil)
 			require.NoError(b, err, fmt.Sprintf("party %d computing joint pk with proof", id))
 			jointPkProofs[id-1] = jointPkProof
 			jointPkProofSessionIds[id-1] = sessionId
 			continue
 		}
-		jointPkProof, sessionId, err := dkg.JointPkCompProve(curve, sks[id-1], jointPkProofs[id-2].Statement2)
+		jointPkProof, sessionId, err := dkg.JointPkCompProve(curve, sks[id-1], jointPkProofs[id-2].Statement2, nil)
 		require.NoError(b, err, fmt.Sprintf("party %d computing joint pk with proof", id))
 		jointPkProofs[id-1] = jointPkProof
 		jointPkProofSessionIds[id-1] = sessionId
@@ -216,7 +218,7 @@ func BenchmarkDS(b *testing.B) {
 			} else {
 				basePoint = jointPkProofs[id-2].Statement2
 			}
-			k, nonceProof, commitment, sessionId, err := NonceComProve(curve, basePoint)
+			k, nonceProof, commitment, sessionId, err := NonceComProve(curve, basePoint, nil)
 			require.NoError(b, err, fmt.Sprintf("party %d generating nonce", id))
 			nonces[id-1] = k
 			nonceProofs[id-1] = nonceProof
@@ -253,21 +255,25 @@ func BenchmarkDS(b *testing.B) {
 			for id := 2; id <= n; id++ {
 				R = R.Add(nonceProofs[id-1].Statement)
 			}
-			r = RPartComp(curve, R, message)
+			var err error
+			r, err = RPartComp(curve, R, jointPkProofs[n-1].Statement2, nil, message)
+			require.NoError(b, err, fmt.Sprintf("failed in computing the r part"))
 		}
 
 		// compute s-part of signature
 		s := r
+		var err error
 		for id := 1; id <= n; id++ {
 			RId := nonceProofs[0].Statement
 			for j := 2; j <= id; j++ {
 				RId = RId.Add(nonceProofs[j-1].Statement)
 			}
 			if id == 1 {
-				s = SPartComp(curve, r, s, sks[id-1], nonces[id-1], nil, RId, pkProofs[0].Statement)
+				s, err = SPartComp(curve, r, s, sks[id-1], nonces[id-1], nil, RId, pkProofs[0].Statement)
 			} else {
-				s = SPartComp(curve, r, s, sks[id-1], nonces[id-1], nil, RId, jointPkProofs[id-1].Statement2)
+				s, err = SPartComp(curve, r, s, sks[id-1], nonces[id-1], nil, RId, jointPkProofs[id-1].Statement2)
 			}
+			require.NoError(b, err, fmt.Sprintf("failed in computing the s part of party %d", id))
 		}
 
 		// verify the final signature
@@ -275,7 +281,7 @@ func BenchmarkDS(b *testing.B) {
 		EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 		****************************************/
 		for numParty := 1; numParty <= n; numParty++ {
-			err := verify.Verify(curve, nil, jointPkProofs[n-1].Statement2, message, r, s)
+			err := verify.Verify(curve, nil, jointPkProofs[n-1].Statement2, nil, message, r, s)
 			require.NoError(b, err, fmt.Sprintf("failed in curve when verify the signature"))
 		}
 	}
@@ -0,0 +1,102 @@
+package participant
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/protocol"
+	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/ds"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+)
+
+const (
+	broadcastKey = "broadcast"
+	roundKey     = "round"
+)
+
+// payloadKey is the key of the payload that belongs to the party id
+func payloadKey(id uint32) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// broadcast wraps a payload that is the same for every recipient
+func broadcast(payload []byte) map[string][]byte {
+	return map[string][]byte{broadcastKey: payload}
+}
+
+// send delivers the payloads of a round to the party to
+func (p *Participant) send(to uint32, protocolName string, round string, payloads map[string][]byte) error {
+	return p.transport.Send(to, &protocol.Message{
+		Protocol: protocolName,
+		Version:  protocol.Version1,
+		Payloads: payloads,
+		Metadata: map[string]string{roundKey: round},
+	})
+}
+
+// receive returns the message of a round sent by the party from
+func (p *Participant) receive(from uint32, protocolName string, round string) (*protocol.Message, error) {
+	msg, err := p.transport.Receive(from)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil || msg.Protocol != protocolName || msg.Version != protocol.Version1 || msg.Metadata[roundKey] != round {
+		return nil, fmt.Errorf("unexpected message from party %d in round %s of %s", from, round, protocolName)
+	}
+	return msg, nil
+}
+
+// exchange sends payload to every peer and returns the payloads that the peers sent in the same round
+func (p *Participant) exchange(protocolName string, round string, payload []byte) (map[uint32][]byte, error) {
+	for _, id := range p.ids {
+		if id == p.id {
+			continue
+		}
+		if err := p.send(id, protocolName, round, broadcast(payload)); err != nil {
+			return nil, err
+		}
+	}
+	payloads := make(map[uint32][]byte, len(p.ids)-1)
+	for pos, id := range p.ids {
+		if id == p.id {
+			continue
+		}
+		msg, err := p.receive(id, protocolName, round)
+		if err != nil {
+			return nil, err
+		}
+		data, ok := msg.Payloads[broadcastKey]
+		if !ok {
+			return nil, &ds.HopError{Hop: pos, Party: id, Err: fmt.Errorf("missing payload")}
+		}
+		payloads[id] = data
+	}
+	return payloads, nil
+}
+
+// encodeJointPks encodes the proofs of the chain of joint pks keyed by the identifier of their hop
+func encodeJointPks(proofs map[uint32]*chaumpedersen.Proof) (map[string][]byte, error) {
+	payloads := make(map[string][]byte, len(proofs))
+	for id, proof := range proofs {
+		data, err := proof.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		payloads[payloadKey(id)] = data
+	}
+	return payloads, nil
+}
+
+// decodeScalar decodes a scalar of curve, rejecting non-canonical encodings
+func decodeScalar(curve *curves.Curve, data []byte) (curves.Scalar, error) {
+	s, err := curve.Scalar.SetBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(s.Bytes(), data) {
+		return nil, fmt.Errorf("non-canonical scalar")
+	}
+	return s, nil
+}
@@ -0,0 +1,366 @@
+// Package participant runs one party of the Onion-SM2 (CET-SM2) signature scheme.
+//
+// Unlike scheme.Scheme, which simulates the whole chain inside a single struct, a Participant only holds its own
+// secrets and exchanges messages with its peers over a Transport. The parties are ordered along the chain in the
+// order of the identifiers passed to NewParticipant: every party but the first extends the running joint public key
+// in the DKG, and every party extends the running s in signing. Whenever a value received from a peer fails its check,
+// the call returns a *ds.HopError naming that peer, so that the chain can be retried without it.
+package participant
+
+import (
+	"fmt"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/protocol"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/dkg"
+	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/ds"
+	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/verify"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+)
+
+// labels of the proofs, which separate their session ids
+const (
+	pkLabel      = "DKG pk"
+	jointPkLabel = "DKG joint pk"
+	nonceLabel   = "DS nonce"
+)
+
+// Transport carries the messages between the parties.
+// Messages from a given sender must be received in the order in which they were sent. Since a party that detects a
+// fault stops, implementations should let Receive fail on a timeout or cancellation instead of blocking forever.
+type Transport interface {
+	// Send delivers msg to the party to
+	Send(to uint32, msg *protocol.Message) error
+	// Receive returns the next message sent by the party from
+	Receive(from uint32) (*protocol.Message, error)
+}
+
+// Participant is a single party of the Onion-SM2 scheme
+type Participant struct {
+	curve     *curves.Curve
+	signerId  []byte
+	sessionId []byte
+	transport Transport
+
+	id  uint32
+	pos int
+	ids []uint32
+
+	sk            curves.Scalar
+	pkProofs      map[uint32]*schnorr.Proof
+	jointPkProofs map[uint32]*chaumpedersen.Proof
+}
+
+// Signature is a signature in the CRT form checked by verify.Verify.
+// verify.ToStandard converts it to a standard SM2 signature.
+type Signature struct {
+	R curves.Scalar
+	S curves.Scalar
+}
+
+// NewParticipant creates the party id of the chain ids, which talks to its peers through transport.
+// sessionId must be the same for all parties and unique to the session; the session ids of the proofs are derived from
+// it. A nil signerId selects the default distinguishing identifier.
+func NewParticipant(curve *curves.Curve, id uint32, ids []uint32, signerId []byte, transport Transport, sessionId []byte) (*Participant, error) {
+	if curve == nil {
+		return nil, fmt.Errorf("curve is nil")
+	}
+	if len(sessionId) == 0 {
+		return nil, fmt.Errorf("session id is empty")
+	}
+	if transport == nil {
+		return nil, fmt.Errorf("transport is nil")
+	}
+	if err := parties.Validate(ids, 2); err != nil {
+		return nil, err
+	}
+	pos := -1
+	for i, other := range ids {
+		if other == id {
+			pos = i
+		}
+	}
+	if pos < 0 {
+		return nil, fmt.Errorf("party %d is not one of the participants", id)
+	}
+	if signerId == nil {
+		signerId = sm2.DefaultSignerId
+	}
+	return &Participant{
+		curve:     curve,
+		signerId:  signerId,
+		sessionId: append([]byte{}, sessionId...),
+		transport: transport,
+		id:        id,
+		pos:       pos,
+		ids:       append([]uint32{}, ids...),
+	}, nil
+}
+
+// Id returns the identifier of the participant
+func (p *Participant) Id() uint32 {
+	return p.id
+}
+
+// JointPk returns the joint public key in the CRT form, or nil if the DKG has not completed.
+// The standard SM2 public key is JointPk - G.
+func (p *Participant) JointPk() curves.Point {
+	if len(p.jointPkProofs) != len(p.ids)-1 {
+		return nil
+	}
+	return p.jointPkAt(len(p.ids) - 1)
+}
+
+// DKG generates the key share of the participant and the joint public key of the chain, which it returns
+func (p *Participant) DKG() (curves.Point, error) {
+	p.jointPkProofs = nil
+
+	// step 1: commit to the pk proof
+	sk, pkProof, pkCommitment, _, err := dkg.PkComProve(p.curve, p.proofSessionId(p.id, pkLabel))
+	if err != nil {
+		return nil, err
+	}
+	commitments, err := p.exchange(protocol.CetSm2Dkg, "1", pkCommitment)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 2: de-com and verify the pk proofs
+	pkPayload, err := pkProof.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	payloads, err := p.exchange(protocol.CetSm2Dkg, "2", pkPayload)
+	if err != nil {
+		return nil, err
+	}
+	pkProofs := map[uint32]*schnorr.Proof{p.id: pkProof}
+	batch := make([]*schnorr.Proof, 0, len(p.ids)-1)
+	batchCommitments := make([]schnorr.Commitment, 0, len(p.ids)-1)
+	sessionIds := make([][]byte, 0, len(p.ids)-1)
+	for pos, id := range p.ids {
+		if id == p.id {
+			continue
+		}
+		proof := &schnorr.Proof{}
+		if err := proof.UnmarshalBinary(payloads[id]); err != nil {
+			return nil, &ds.HopError{Hop: pos, Party: id, Err: err}
+		}
+		pkProofs[id] = proof
+		batch = append(batch, proof)
+		batchCommitments = append(batchCommitments, commitments[id])
+		sessionIds = append(sessionIds, p.proofSessionId(id, pkLabel))
+	}
+	if dkg.PkDeComBatchVerify(p.curve, batch, batchCommitments, sessionIds) != nil {
+		// the proofs are verified one by one only to find the culprit when the batch fails
+		for pos, id := range p.ids {
+			if id == p.id {
+				continue
+			}
+			if err := dkg.PkDeComVerify(p.curve, pkProofs[id], commitments[id], p.proofSessionId(id, pkLabel)); err != nil {
+				return nil, &ds.HopError{Hop: pos, Party: id, Err: err}
+			}
+		}
+	}
+	p.sk, p.pkProofs = sk, pkProofs
+
+	// step 3: pass the running joint pk along the chain, then the last party sends the whole chain to everyone
+	jointPkProofs := make(map[uint32]*chaumpedersen.Proof, len(p.ids)-1)
+	if p.pos > 0 {
+		msg, err := p.receive(p.ids[p.pos-1], protocol.CetSm2Dkg, "3")
+		if err != nil {
+			return nil, err
+		}
+		if jointPkProofs, err = p.verifyJointPks(msg, p.pos); err != nil {
+			return nil, err
+		}
+		p.jointPkProofs = jointPkProofs
+		proof, _, err := dkg.JointPkCompProve(p.curve, sk, p.jointPkAt(p.pos-1), p.proofSessionId(p.id, jointPkLabel))
+		if err != nil {
+			return nil, err
+		}
+		jointPkProofs[p.id] = proof
+	}
+	chain, err := encodeJointPks(jointPkProofs)
+	if err != nil {
+		return nil, err
+	}
+	last := len(p.ids) - 1
+	if p.pos < last {
+		if err = p.send(p.ids[p.pos+1], protocol.CetSm2Dkg, "3", chain); err != nil {
+			return nil, err
+		}
+		msg, err := p.receive(p.ids[last], protocol.CetSm2Dkg, "4")
+		if err != nil {
+			return nil, err
+		}
+		if jointPkProofs, err = p.verifyJointPks(msg, len(p.ids)); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, id := range p.ids[:last] {
+			if err = p.send(id, protocol.CetSm2Dkg, "4", chain); err != nil {
+				return nil, err
+			}
+		}
+	}
+	p.jointPkProofs = jointPkProofs
+	return p.JointPk(), nil
+}
+
+// Sign signs message together with the other parties of the chain
+func (p *Participant) Sign(message []byte) (*Signature, error) {
+	if p.JointPk() == nil {
+		return nil, fmt.Errorf("the DKG has not been run")
+	}
+
+	// step 1: commit to the nonce proof
+	k, nonceProof, nonceCommitment, _, err := ds.NonceComProve(p.curve, p.basePoint(p.pos), p.proofSessionId(p.id, nonceLabel))
+	if err != nil {
+		return nil, err
+	}
+	commitments, err := p.exchange(protocol.CetSm2Sign, "1", nonceCommitment)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 2: de-com and verify the nonce proofs, then compute r
+	noncePayload, err := nonceProof.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	payloads, err := p.exchange(protocol.CetSm2Sign, "2", noncePayload)
+	if err != nil {
+		return nil, err
+	}
+	Rs := make([]curves.Point, len(p.ids))
+	for pos, id := range p.ids {
+		if id == p.id {
+			Rs[pos] = nonceProof.Statement
+			continue
+		}
+		proof := &schnorr.Proof{}
+		if err := proof.UnmarshalBinary(payloads[id]); err != nil {
+			return nil, &ds.HopError{Hop: pos, Party: id, Err: err}
+		}
+		if err := ds.NonceDeComVerify(p.curve, p.basePoint(pos), proof, commitments[id], p.proofSessionId(id, nonceLabel)); err != nil {
+			return nil, &ds.HopError{Hop: pos, Party: id, Err: err}
+		}
+		Rs[pos] = proof.Statement
+	}
+	// runningRs[pos] is the sum of the nonces up to the hop pos
+	runningRs := make([]curves.Point, len(p.ids))
+	runningRs[0] = Rs[0]
+	for pos := 1; pos < len(p.ids); pos++ {
+		runningRs[pos] = runningRs[pos-1].Add(Rs[pos])
+	}
+	jointPk := p.JointPk()
+	r, err := ds.RPartComp(p.curve, runningRs[len(p.ids)-1], jointPk, p.signerId, message)
+	if err != nil {
+		return nil, err
+	}
+
+	// step 3: pass the running s along the chain, then the last party sends the final s to everyone
+	s := r
+	if p.pos > 0 {
+		if s, err = p.receiveS(p.pos-1, "3", r, runningRs); err != nil {
+			return nil, err
+		}
+	}
+	s, err = ds.SPartComp(p.curve, r, s, p.sk, k, nil, runningRs[p.pos], p.jointPkAt(p.pos))
+	if err != nil {
+		return nil, &ds.HopError{Hop: p.pos, Party: p.id, Err: err}
+	}
+	last := len(p.ids) - 1
+	if p.pos < last {
+		if err = p.send(p.ids[p.pos+1], protocol.CetSm2Sign, "3", broadcast(s.Bytes())); err != nil {
+			return nil, err
+		}
+		if s, err = p.receiveS(last, "4", r, runningRs); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, id := range p.ids[:last] {
+			if err = p.send(id, protocol.CetSm2Sign, "4", broadcast(s.Bytes())); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err = verify.Verify(p.curve, nil, jointPk, p.signerId, message, r, s); err != nil {
+		return nil, err
+	}
+	return &Signature{R: r, S: s}, nil
+}
+
+// proofSessionId returns the session id of the proof labeled label that the party sender produces
+func (p *Participant) proofSessionId(sender uint32, label string) []byte {
+	return parties.ProofSessionId(p.sessionId, sender, label)
+}
+
+// basePoint returns the generator that the party at position pos of the chain uses for its nonce,
+// which is the joint pk output by the former hop
+func (p *Participant) basePoint(pos int) curves.Point {
+	if pos == 0 {
+		return p.curve.NewGeneratorPoint()
+	}
+	return p.jointPkAt(pos - 1)
+}
+
+// jointPkAt returns the running joint pk output by the hop pos
+func (p *Participant) jointPkAt(pos int) curves.Point {
+	if pos == 0 {
+		return p.pkProofs[p.ids[0]].Statement
+	}
+	return p.jointPkProofs[p.ids[pos]].Statement2
+}
+
+// verifyJointPks decodes and verifies the proofs of the hops 1 to n-1 of the chain of joint pks
+func (p *Participant) verifyJointPks(msg *protocol.Message, n int) (map[uint32]*chaumpedersen.Proof, error) {
+	if len(msg.Payloads) != n-1 {
+		return nil, fmt.Errorf("expected %d joint pk proofs, got %d", n-1, len(msg.Payloads))
+	}
+	proofs := make(map[uint32]*chaumpedersen.Proof, len(p.ids)-1)
+	formerJointPk := p.pkProofs[p.ids[0]].Statement
+	for pos := 1; pos < n; pos++ {
+		id := p.ids[pos]
+		payload, ok := msg.Payloads[payloadKey(id)]
+		if !ok {
+			return nil, &ds.HopError{Hop: pos, Party: id, Err: fmt.Errorf("missing joint pk proof")}
+		}
+		proof := &chaumpedersen.Proof{}
+		if err := proof.UnmarshalBinary(payload); err != nil {
+			return nil, &ds.HopError{Hop: pos, Party: id, Err: err}
+		}
+		if err := dkg.JointPkVerify(p.curve, formerJointPk, proof, p.proofSessionId(id, jointPkLabel)); err != nil {
+			return nil, &ds.HopError{Hop: pos, Party: id, Err: err}
+		}
+		// the joint pk must be extended with the sk behind the committed pk
+		if !proof.Statement1.Equal(p.pkProofs[id].Statement) {
+			return nil, &ds.HopError{Hop: pos, Party: id, Err: fmt.Errorf("joint pk proof is not bound to the pk")}
+		}
+		proofs[id] = proof
+		formerJointPk = proof.Statement2
+	}
+	return proofs, nil
+}
+
+// receiveS decodes the running s sent by the party at position pos and checks it against the public values
+func (p *Participant) receiveS(pos int, round string, r curves.Scalar, runningRs []curves.Point) (curves.Scalar, error) {
+	id := p.ids[pos]
+	msg, err := p.receive(id, protocol.CetSm2Sign, round)
+	if err != nil {
+		return nil, err
+	}
+	s, err := decodeScalar(p.curve, msg.Payloads[broadcastKey])
+	if err != nil {
+		return nil, &ds.HopError{Hop: pos, Party: id, Err: err}
+	}
+	if err = ds.SPartVerify(p.curve, r, s, nil, runningRs[pos], p.jointPkAt(pos)); err != nil {
+		return nil, &ds.HopError{Hop: pos, Party: id, Err: err}
+	}
+	return s, nil
+}
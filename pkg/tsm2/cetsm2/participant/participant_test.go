@@ -0,0 +1,276 @@
+package participant
+
+import (
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/protocol"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/dkg"
+	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/ds"
+	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/verify"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+)
+
+// network delivers messages between in-process parties, one queue per ordered pair
+type network struct {
+	mu     sync.Mutex
+	queues map[[2]uint32]chan *protocol.Message
+	closed chan struct{}
+	// tamper, if set, may replace the messages sent by a party
+	tamper func(from, to uint32, msg *protocol.Message) *protocol.Message
+}
+
+func newNetwork() *network {
+	return &network{queues: map[[2]uint32]chan *protocol.Message{}, closed: make(chan struct{})}
+}
+
+func (n *network) queue(from, to uint32) chan *protocol.Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	q, ok := n.queues[[2]uint32{from, to}]
+	if !ok {
+		q = make(chan *protocol.Message, 16)
+		n.queues[[2]uint32{from, to}] = q
+	}
+	return q
+}
+
+// close makes every pending and future Receive fail, so that the parties behind a faulty hop stop
+func (n *network) close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	select {
+	case <-n.closed:
+	default:
+		close(n.closed)
+	}
+}
+
+type endpoint struct {
+	*network
+	id uint32
+}
+
+func (e *endpoint) Send(to uint32, msg *protocol.Message) error {
+	if e.tamper != nil {
+		msg = e.tamper(e.id, to, msg)
+	}
+	e.queue(e.id, to) <- msg
+	return nil
+}
+
+func (e *endpoint) Receive(from uint32) (*protocol.Message, error) {
+	select {
+	case msg := <-e.queue(from, e.id):
+		return msg, nil
+	case <-e.closed:
+		return nil, fmt.Errorf("network closed")
+	}
+}
+
+var testSessionId = []byte("cetsm2 participant test")
+
+func newParticipants(t *testing.T, curve *curves.Curve, ids []uint32, net *network) map[uint32]*Participant {
+	participants := make(map[uint32]*Participant, len(ids))
+	for _, id := range ids {
+		p, err := NewParticipant(curve, id, ids, nil, &endpoint{network: net, id: id}, testSessionId)
+		require.NoError(t, err)
+		participants[id] = p
+	}
+	return participants
+}
+
+// runAll runs f for every participant concurrently and closes the network as soon as one of them fails
+func runAll[T any](participants map[uint32]*Participant, net *network, f func(*Participant) (T, error)) (map[uint32]T, map[uint32]error) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	outputs := make(map[uint32]T, len(participants))
+	errs := make(map[uint32]error)
+	for id, p := range participants {
+		wg.Add(1)
+		go func(id uint32, p *Participant) {
+			defer wg.Done()
+			output, err := f(p)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[id] = err
+				net.close()
+				return
+			}
+			outputs[id] = output
+		}(id, p)
+	}
+	wg.Wait()
+	return outputs, errs
+}
+
+func runDKG(t *testing.T, participants map[uint32]*Participant, net *network) curves.Point {
+	jointPks, errs := runAll(participants, net, (*Participant).DKG)
+	require.Empty(t, errs)
+	var jointPk curves.Point
+	for _, pk := range jointPks {
+		if jointPk == nil {
+			jointPk = pk
+		}
+		require.True(t, jointPk.Equal(pk))
+	}
+	return jointPk
+}
+
+func runSign(participants map[uint32]*Participant, net *network, message []byte) (map[uint32]*Signature, map[uint32]error) {
+	return runAll(participants, net, func(p *Participant) (*Signature, error) { return p.Sign(message) })
+}
+
+// hopError returns the fault that the honest parties agree on
+func hopError(t *testing.T, errs map[uint32]error) *ds.HopError {
+	var found *ds.HopError
+	for _, err := range errs {
+		var hopErr *ds.HopError
+		if !errors.As(err, &hopErr) {
+			continue
+		}
+		if found != nil {
+			require.Equal(t, found.Party, hopErr.Party)
+		}
+		found = hopErr
+	}
+	require.NotNil(t, found)
+	return found
+}
+
+func without(ids []uint32, culprit uint32) []uint32 {
+	var remaining []uint32
+	for _, id := range ids {
+		if id != culprit {
+			remaining = append(remaining, id)
+		}
+	}
+	return remaining
+}
+
+func TestNewParticipant(t *testing.T) {
+	curve := curves.SM2()
+	transport := &endpoint{network: newNetwork(), id: 1}
+	_, err := NewParticipant(nil, 1, []uint32{1, 2}, nil, transport, testSessionId)
+	require.Error(t, err)
+	_, err = NewParticipant(curve, 1, []uint32{1, 2}, nil, nil, testSessionId)
+	require.Error(t, err)
+	_, err = NewParticipant(curve, 1, []uint32{1}, nil, transport, testSessionId)
+	require.Error(t, err)
+	_, err = NewParticipant(curve, 3, []uint32{1, 2}, nil, transport, testSessionId)
+	require.Error(t, err)
+
+	_, err = NewParticipant(curve, 1, []uint32{1, 2}, nil, transport, nil)
+	require.Error(t, err)
+
+	p, err := NewParticipant(curve, 1, []uint32{1, 2}, nil, transport, testSessionId)
+	require.NoError(t, err)
+	require.Nil(t, p.JointPk())
+	_, err = p.Sign([]byte("test message"))
+	require.Error(t, err)
+}
+
+func TestParticipants(t *testing.T) {
+	message := []byte("test message")
+	for _, curve := range []*curves.Curve{curves.SM2(), curves.K256()} {
+		for _, ids := range [][]uint32{{1, 2}, {9, 4, 6}, {3, 10, 7, 12, 5}} {
+			net := newNetwork()
+			participants := newParticipants(t, curve, ids, net)
+			jointPk := runDKG(t, participants, net)
+
+			signatures, errs := runSign(participants, net, message)
+			require.Empty(t, errs)
+			for _, signature := range signatures {
+				require.NoError(t, verify.Verify(curve, nil, jointPk, sm2.DefaultSignerId, message, signature.R, signature.S))
+				pk, standard := verify.ToStandard(curve, jointPk, signature.R, signature.S)
+				require.NoError(t, sm2.Verify(curve, pk, sm2.DefaultSignerId, message, standard))
+			}
+		}
+	}
+}
+
+func TestFaultyHopIsExcluded(t *testing.T) {
+	curve := curves.SM2()
+	ids := []uint32{3, 10, 7, 12}
+	message := []byte("test message")
+	net := newNetwork()
+	participants := newParticipants(t, curve, ids, net)
+	runDKG(t, participants, net)
+
+	// party 10 sends a wrong running s to the next hop
+	net.tamper = func(from, to uint32, msg *protocol.Message) *protocol.Message {
+		if from != 10 || msg.Protocol != protocol.CetSm2Sign || msg.Metadata[roundKey] != "3" {
+			return msg
+		}
+		s, err := curve.Scalar.SetBytes(msg.Payloads[broadcastKey])
+		require.NoError(t, err)
+		return &protocol.Message{
+			Protocol: msg.Protocol,
+			Version:  msg.Version,
+			Payloads: broadcast(s.Add(curve.Scalar.One()).Bytes()),
+			Metadata: msg.Metadata,
+		}
+	}
+	_, errs := runSign(participants, net, message)
+	hopErr := hopError(t, errs)
+	require.Equal(t, uint32(10), hopErr.Party)
+	require.Equal(t, 1, hopErr.Hop)
+
+	// the chain is rebuilt without the faulty party
+	remaining := without(ids, hopErr.Party)
+	net = newNetwork()
+	participants = newParticipants(t, curve, remaining, net)
+	jointPk := runDKG(t, participants, net)
+	signatures, errs := runSign(participants, net, message)
+	require.Empty(t, errs)
+	for _, signature := range signatures {
+		require.NoError(t, verify.Verify(curve, nil, jointPk, sm2.DefaultSignerId, message, signature.R, signature.S))
+	}
+}
+
+func TestFaultyJointPkHop(t *testing.T) {
+	curve := curves.K256()
+	ids := []uint32{1, 2, 3}
+	net := newNetwork()
+	participants := newParticipants(t, curve, ids, net)
+
+	// party 2 extends the joint pk with a secret other than the one behind its committed pk
+	net.tamper = func(from, to uint32, msg *protocol.Message) *protocol.Message {
+		if from != 2 || msg.Protocol != protocol.CetSm2Dkg || msg.Metadata[roundKey] != "3" {
+			return msg
+		}
+		proof, _, err := dkg.JointPkCompProve(curve, curve.Scalar.Random(crand.Reader), participants[2].jointPkAt(0), participants[2].proofSessionId(2, jointPkLabel))
+		require.NoError(t, err)
+		payloads, err := encodeJointPks(map[uint32]*chaumpedersen.Proof{2: proof})
+		require.NoError(t, err)
+		return &protocol.Message{Protocol: msg.Protocol, Version: msg.Version, Payloads: payloads, Metadata: msg.Metadata}
+	}
+	_, errs := runAll(participants, net, (*Participant).DKG)
+	hopErr := hopError(t, errs)
+	require.Equal(t, uint32(2), hopErr.Party)
+	require.Equal(t, 1, hopErr.Hop)
+}
+
+func TestProofsAreBoundToTheSession(t *testing.T) {
+	curve := curves.SM2()
+	ids := []uint32{1, 2, 3}
+	net := newNetwork()
+	participants := newParticipants(t, curve, ids, net)
+
+	// party 3 runs in another session, so its proofs do not verify in this one
+	var err error
+	participants[3], err = NewParticipant(curve, 3, ids, nil, &endpoint{network: net, id: 3}, []byte("another session"))
+	require.NoError(t, err)
+	_, errs := runAll(participants, net, (*Participant).DKG)
+	delete(errs, 3)
+	hopErr := hopError(t, errs)
+	require.Equal(t, uint32(3), hopErr.Party)
+}
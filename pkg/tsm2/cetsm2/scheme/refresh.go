@@ -0,0 +1,149 @@
+package scheme
+
+import (
+	"fmt"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/dkg"
+	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+)
+
+// Refresh re-randomizes every sk_i while keeping the joint pk fixed.
+// It runs RefreshStep1, then DKGStep2 to DKGStep3B to prove the new chain of joint pks, and finally RefreshStep2.
+func (scheme *Scheme) Refresh() error {
+	steps := []func() error{scheme.RefreshStep1, scheme.DKGStep2, scheme.DKGStep3A, scheme.DKGStep3B, scheme.RefreshStep2}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefreshStep1 multiplies the sk_i by blindings whose product is one and commits to proofs of the new pks.
+// Each party but the last commits to its blinding rho, which it then sends to the next party only, and proves that
+// its new pk is its former pk times its own rho over the one it received.
+func (scheme *Scheme) RefreshStep1() error {
+	if scheme.jointPkProofs[scheme.ids[scheme.n-1]] == nil {
+		return fmt.Errorf("the DKG has not been run")
+	}
+	scheme.formerJointPk = scheme.jointPk()
+	for _, id := range scheme.ids {
+		scheme.formerPks[id] = scheme.pkProofs[id].Statement
+	}
+
+	// draw the blindings and commit to proofs of them
+	rhos := make(map[uint32]curves.Scalar, scheme.n)
+	rhoCommitments := make(map[uint32]schnorr.Commitment, scheme.n)
+	for _, id := range scheme.ids[:scheme.n-1] {
+		rho, rhoProof, rhoCommitment, rhoProofSessionId, err := dkg.RhoComProve(scheme.curve, nil)
+		if err != nil {
+			return err
+		}
+		rhos[id] = rho
+		scheme.rhoProofs[id] = rhoProof
+		rhoCommitments[id] = rhoCommitment
+		scheme.rhoProofSessionIds[id] = rhoProofSessionId
+	}
+
+	// de-com and verify the rho proofs
+	/****************************************
+	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
+	****************************************/
+	for _, numParty := range scheme.ids {
+		var others []uint32
+		proofs := make([]*schnorr.Proof, 0, scheme.n-1)
+		commitments := make([]schnorr.Commitment, 0, scheme.n-1)
+		sessionIds := make([][]byte, 0, scheme.n-1)
+		for _, id := range scheme.ids[:scheme.n-1] {
+			if id == numParty {
+				continue
+			}
+			others = append(others, id)
+			proofs = append(proofs, scheme.rhoProofs[id])
+			commitments = append(commitments, rhoCommitments[id])
+			sessionIds = append(sessionIds, scheme.rhoProofSessionIds[id])
+		}
+		if dkg.RhoDeComBatchVerify(scheme.curve, proofs, commitments, sessionIds) == nil {
+			continue
+		}
+		// the proofs are verified one by one only to find the culprit when the batch fails
+		for _, id := range others {
+			err := dkg.RhoDeComVerify(scheme.curve, scheme.rhoProofs[id], rhoCommitments[id], scheme.rhoProofSessionIds[id])
+			if err != nil {
+				return fmt.Errorf("invalid proof of rho from party %d: %w", id, err)
+			}
+		}
+	}
+
+	// re-randomize the sks with the own rho and the one received from the former party
+	for pos, id := range scheme.ids {
+		var rho, formerRho curves.Scalar
+		if pos < scheme.n-1 {
+			rho = rhos[id]
+		}
+		if pos > 0 {
+			formerRho = rhos[scheme.ids[pos-1]]
+			if err := dkg.RhoCheck(scheme.curve, formerRho, scheme.rhoProofs[scheme.ids[pos-1]]); err != nil {
+				return fmt.Errorf("party %d sent a wrong rho: %w", scheme.ids[pos-1], err)
+			}
+		}
+		sk, err := dkg.SkRerandomize(scheme.sks[id], rho, formerRho)
+		if err != nil {
+			return err
+		}
+		pkProof, pkCommitment, pkProofSessionId, err := dkg.PkReComProve(scheme.curve, sk, nil)
+		if err != nil {
+			return err
+		}
+		rhoReProof, formerRhoReProof, skReProofSessionId, err := dkg.SkReProve(scheme.curve, scheme.sks[id], rho, formerRho, sk, nil)
+		if err != nil {
+			return err
+		}
+		scheme.sks[id] = sk
+		scheme.pkProofs[id] = pkProof
+		scheme.pkCommitments[id] = pkCommitment
+		scheme.pkProofSessionIds[id] = pkProofSessionId
+		scheme.rhoReProofs[id] = rhoReProof
+		scheme.formerRhoReProofs[id] = formerRhoReProof
+		scheme.skReProofSessionIds[id] = skReProofSessionId
+	}
+	return nil
+}
+
+// RefreshStep2 checks that every new pk is consistent with the former pk and the blindings, and that the
+// re-randomized chain of joint pks still ends in the former joint pk.
+func (scheme *Scheme) RefreshStep2() error {
+	/****************************************
+	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
+	****************************************/
+	for _, numParty := range scheme.ids {
+		for pos, id := range scheme.ids {
+			if id == numParty {
+				continue
+			}
+			var Rho, FormerRho curves.Point
+			if pos < scheme.n-1 {
+				Rho = scheme.rhoProofs[id].Statement
+			}
+			if pos > 0 {
+				FormerRho = scheme.rhoProofs[scheme.ids[pos-1]].Statement
+			}
+			err := dkg.SkReVerify(scheme.curve, scheme.formerPks[id], scheme.pkProofs[id].Statement, Rho, FormerRho,
+				scheme.rhoReProofs[id], scheme.formerRhoReProofs[id], scheme.skReProofSessionIds[id])
+			if err != nil {
+				return fmt.Errorf("party %d did not refresh its sk correctly: %w", id, err)
+			}
+		}
+	}
+
+	/****************************************
+	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
+	****************************************/
+	for range scheme.ids {
+		if !scheme.jointPk().Equal(scheme.formerJointPk) {
+			return fmt.Errorf("the refresh changed the joint pk")
+		}
+	}
+	return nil
+}
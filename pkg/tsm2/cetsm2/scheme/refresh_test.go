@@ -0,0 +1,86 @@
+package scheme
+
+import (
+	crand "crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/dkg"
+)
+
+func runDKG(t *testing.T, scheme *Scheme) {
+	require.NoError(t, scheme.DKGStep1(), "failed in step 1 of DKG")
+	require.NoError(t, scheme.DKGStep2(), "failed in step 2 of DKG")
+	require.NoError(t, scheme.DKGStep3A(), "failed in step 3A of DKG")
+	require.NoError(t, scheme.DKGStep3B(), "failed in step 3B of DKG")
+}
+
+func runDS(t *testing.T, scheme *Scheme) {
+	require.NoError(t, scheme.DSStep1(), "failed in step 1 of DS")
+	require.NoError(t, scheme.DSStep2A(), "failed in step 2A of DS")
+	r, err := scheme.DSStep2B()
+	require.NoError(t, err, "failed in step 2B of DS")
+	s, err := scheme.DSStep3A(r)
+	require.NoError(t, err, "failed in step 3A of DS")
+	require.NoError(t, scheme.DSStep3B(r, s), "failed in step 3B of DS")
+}
+
+func TestRefresh(t *testing.T) {
+	for _, curveInit := range []*curves.Curve{curves.SM2(), curves.K256()} {
+		scheme, err := NewScheme(curveInit, []uint32{4, 1, 9, 3}, []byte("test message"), nil)
+		require.NoError(t, err)
+		require.Error(t, scheme.Refresh(), "the DKG has not been run")
+
+		runDKG(t, scheme)
+		runDS(t, scheme)
+
+		jointPk := scheme.jointPk()
+		sks := make(map[uint32]curves.Scalar, scheme.n)
+		for id, sk := range scheme.sks {
+			sks[id] = sk
+		}
+
+		for round := 0; round < 2; round++ {
+			require.NoError(t, scheme.Refresh())
+			require.True(t, scheme.jointPk().Equal(jointPk))
+			for id, sk := range scheme.sks {
+				require.NotEqual(t, 0, sk.Cmp(sks[id]), "sk of party %d was not refreshed", id)
+				sks[id] = sk
+			}
+			runDS(t, scheme)
+		}
+	}
+}
+
+func TestRefreshRejectsWrongKey(t *testing.T) {
+	scheme, err := NewScheme(curves.SM2(), []uint32{1, 2, 3}, []byte("test message"), nil)
+	require.NoError(t, err)
+	runDKG(t, scheme)
+
+	require.NoError(t, scheme.RefreshStep1())
+	// a party that does not apply the blinding of its predecessor changes the joint pk
+	scheme.sks[2] = scheme.sks[2].Double()
+	scheme.pkProofs[2], scheme.pkCommitments[2], scheme.pkProofSessionIds[2], err = dkg.PkReComProve(scheme.curve, scheme.sks[2], nil)
+	require.NoError(t, err)
+	require.NoError(t, scheme.DKGStep2())
+	require.NoError(t, scheme.DKGStep3A())
+	require.NoError(t, scheme.DKGStep3B())
+	require.ErrorContains(t, scheme.RefreshStep2(), "party 2")
+}
+
+func TestRefreshRejectsWrongRho(t *testing.T) {
+	scheme, err := NewScheme(curves.SM2(), []uint32{1, 2, 3}, []byte("test message"), nil)
+	require.NoError(t, err)
+	runDKG(t, scheme)
+
+	require.NoError(t, scheme.RefreshStep1())
+	// a party whose published rho is not the one it blinded with is caught, even though the joint pk is unchanged
+	scheme.rhoProofs[2], _, scheme.rhoProofSessionIds[2], err = dkg.PkReComProve(scheme.curve, scheme.curve.Scalar.Random(crand.Reader), nil)
+	require.NoError(t, err)
+	require.NoError(t, scheme.DKGStep2())
+	require.NoError(t, scheme.DKGStep3A())
+	require.NoError(t, scheme.DKGStep3B())
+	require.ErrorContains(t, scheme.RefreshStep2(), "party 2")
+}
@@ -2,68 +2,109 @@ package scheme
 
 import (
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
 	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/dkg"
 	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/ds"
 	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/verify"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
 	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
 	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
 )
 
-const num = 50
-
 type Scheme struct {
 	curve *curves.Curve
 
-	n int
+	n   int
+	ids []uint32
+
+	message  []byte
+	signerId []byte
 
-	message []byte
+	sks               map[uint32]curves.Scalar
+	pkProofs          map[uint32]*schnorr.Proof
+	pkCommitments     map[uint32]schnorr.Commitment
+	pkProofSessionIds map[uint32]schnorr.SessionId
 
-	sks               [num]curves.Scalar
-	pkProofs          [num]*schnorr.Proof
-	pkCommitments     [num]schnorr.Commitment
-	pkProofSessionIds [num]schnorr.SessionId
+	jointPkProofs          map[uint32]*chaumpedersen.Proof
+	jointPkProofSessionIds map[uint32]chaumpedersen.SessionId
+	formerJointPk          curves.Point
 
-	jointPkProofs          [num]*chaumpedersen.Proof
-	jointPkProofSessionIds [num]chaumpedersen.SessionId
+	formerPks           map[uint32]curves.Point
+	rhoProofs           map[uint32]*schnorr.Proof
+	rhoProofSessionIds  map[uint32]schnorr.SessionId
+	rhoReProofs         map[uint32]*chaumpedersen.Proof
+	formerRhoReProofs   map[uint32]*chaumpedersen.Proof
+	skReProofSessionIds map[uint32]chaumpedersen.SessionId
 
-	nonce                [num]curves.Scalar
-	nonceProofs          [num]*schnorr.Proof
-	nonceCommitments     [num]schnorr.Commitment
-	nonceProofSessionIds [num]schnorr.SessionId
+	nonce                map[uint32]curves.Scalar
+	nonceProofs          map[uint32]*schnorr.Proof
+	nonceCommitments     map[uint32]schnorr.Commitment
+	nonceProofSessionIds map[uint32]schnorr.SessionId
 }
 
-func NewScheme(curve *curves.Curve, n int, message []byte,
-	sks [num]curves.Scalar, pkProofs [num]*schnorr.Proof, pkCommitments [num]schnorr.Commitment, pkProofSessionIds [num]schnorr.SessionId,
-	jointPkProofs [num]*chaumpedersen.Proof, jointPkProofSessionIds [num]chaumpedersen.SessionId,
-	nonce [num]curves.Scalar, nonceProofs [num]*schnorr.Proof, nonceCommitments [num]schnorr.Commitment, nonceProofSessionIds [num]schnorr.SessionId) *Scheme {
+// NewScheme creates a scheme in which the parties with the given identifiers sign message on behalf of signerId.
+// The joint public key is built along the parties in the order of ids.
+// A nil signerId selects the default distinguishing identifier.
+func NewScheme(curve *curves.Curve, ids []uint32, message []byte, signerId []byte) (*Scheme, error) {
+	if err := parties.Validate(ids, 2); err != nil {
+		return nil, err
+	}
+	if signerId == nil {
+		signerId = sm2.DefaultSignerId
+	}
 	return &Scheme{
 		curve:                  curve,
-		n:                      n,
+		n:                      len(ids),
+		ids:                    ids,
 		message:                message,
-		sks:                    sks,
-		pkProofs:               pkProofs,
-		pkCommitments:          pkCommitments,
-		pkProofSessionIds:      pkProofSessionIds,
-		jointPkProofs:          jointPkProofs,
-		jointPkProofSessionIds: jointPkProofSessionIds,
-		nonce:                  nonce,
-		nonceProofs:            nonceProofs,
-		nonceCommitments:       nonceCommitments,
-		nonceProofSessionIds:   nonceProofSessionIds,
+		signerId:               signerId,
+		sks:                    make(map[uint32]curves.Scalar, len(ids)),
+		pkProofs:               make(map[uint32]*schnorr.Proof, len(ids)),
+		pkCommitments:          make(map[uint32]schnorr.Commitment, len(ids)),
+		pkProofSessionIds:      make(map[uint32]schnorr.SessionId, len(ids)),
+		jointPkProofs:          make(map[uint32]*chaumpedersen.Proof, len(ids)),
+		jointPkProofSessionIds: make(map[uint32]chaumpedersen.SessionId, len(ids)),
+		formerPks:              make(map[uint32]curves.Point, len(ids)),
+		rhoProofs:              make(map[uint32]*schnorr.Proof, len(ids)),
+		rhoProofSessionIds:     make(map[uint32]schnorr.SessionId, len(ids)),
+		rhoReProofs:            make(map[uint32]*chaumpedersen.Proof, len(ids)),
+		formerRhoReProofs:      make(map[uint32]*chaumpedersen.Proof, len(ids)),
+		skReProofSessionIds:    make(map[uint32]chaumpedersen.SessionId, len(ids)),
+		nonce:                  make(map[uint32]curves.Scalar, len(ids)),
+		nonceProofs:            make(map[uint32]*schnorr.Proof, len(ids)),
+		nonceCommitments:       make(map[uint32]schnorr.Commitment, len(ids)),
+		nonceProofSessionIds:   make(map[uint32]schnorr.SessionId, len(ids)),
+	}, nil
+}
+
+// basePoint returns the generator that the party at position pos of the chain uses for its nonce
+func (scheme *Scheme) basePoint(pos int) curves.Point {
+	switch pos {
+	case 0:
+		return scheme.curve.NewGeneratorPoint()
+	case 1:
+		return scheme.pkProofs[scheme.ids[0]].Statement
+	default:
+		return scheme.jointPkProofs[scheme.ids[pos-1]].Statement2
 	}
 }
 
+// jointPk returns the joint public key output by the last party of the chain
+func (scheme *Scheme) jointPk() curves.Point {
+	return scheme.jointPkProofs[scheme.ids[scheme.n-1]].Statement2
+}
+
 func (scheme *Scheme) DKGStep1() error {
 	// generate pks and proofs
-	for id := 1; id <= scheme.n; id++ {
-		sk, pkProof, pkCommitment, pkProofSessionId, err := dkg.PkComProve(scheme.curve)
+	for _, id := range scheme.ids {
+		sk, pkProof, pkCommitment, pkProofSessionId, err := dkg.PkComProve(scheme.curve, nil)
 		if err != nil {
 			return err
 		}
-		scheme.sks[id-1] = sk
-		scheme.pkProofs[id-1] = pkProof
-		scheme.pkCommitments[id-1] = pkCommitment
-		scheme.pkProofSessionIds[id-1] = pkProofSessionId
+		scheme.sks[id] = sk
+		scheme.pkProofs[id] = pkProof
+		scheme.pkCommitments[id] = pkCommitment
+		scheme.pkProofSessionIds[id] = pkProofSessionId
 	}
 	return nil
 }
@@ -73,15 +114,20 @@ func (scheme *Scheme) DKGStep2() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for id := 1; id <= scheme.n; id++ {
+	for _, numParty := range scheme.ids {
+		proofs := make([]*schnorr.Proof, 0, scheme.n-1)
+		commitments := make([]schnorr.Commitment, 0, scheme.n-1)
+		sessionIds := make([][]byte, 0, scheme.n-1)
+		for _, id := range scheme.ids {
 			if id == numParty {
 				continue
 			}
-			err := dkg.PkDeComVerify(scheme.curve, scheme.pkProofs[id-1], scheme.pkCommitments[id-1], scheme.pkProofSessionIds[id-1])
-			if err != nil {
-				return err
-			}
+			proofs = append(proofs, scheme.pkProofs[id])
+			commitments = append(commitments, scheme.pkCommitments[id])
+			sessionIds = append(sessionIds, scheme.pkProofSessionIds[id])
+		}
+		if err := dkg.PkDeComBatchVerify(scheme.curve, proofs, commitments, sessionIds); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -89,22 +135,14 @@ func (scheme *Scheme) DKGStep2() error {
 
 func (scheme *Scheme) DKGStep3A() error {
 	// generate (mid-)joint pks
-	for id := 2; id <= scheme.n; id++ {
-		if id == 2 {
-			jointPkProof, jointPkProofSessionId, err := dkg.JointPkCompProve(scheme.curve, scheme.sks[id-1], scheme.pkProofs[id-2].Statement)
-			if err != nil {
-				return err
-			}
-			scheme.jointPkProofs[id-1] = jointPkProof
-			scheme.jointPkProofSessionIds[id-1] = jointPkProofSessionId
-			continue
-		}
-		jointPkProof, jointPkProofSessionId, err := dkg.JointPkCompProve(scheme.curve, scheme.sks[id-1], scheme.jointPkProofs[id-2].Statement2)
+	for pos := 1; pos < scheme.n; pos++ {
+		id := scheme.ids[pos]
+		jointPkProof, jointPkProofSessionId, err := dkg.JointPkCompProve(scheme.curve, scheme.sks[id], scheme.basePoint(pos), nil)
 		if err != nil {
 			return err
 		}
-		scheme.jointPkProofs[id-1] = jointPkProof
-		scheme.jointPkProofSessionIds[id-1] = jointPkProofSessionId
+		scheme.jointPkProofs[id] = jointPkProof
+		scheme.jointPkProofSessionIds[id] = jointPkProofSessionId
 	}
 	return nil
 }
@@ -114,19 +152,13 @@ func (scheme *Scheme) DKGStep3B() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for id := 2; id <= scheme.n; id++ {
+	for _, numParty := range scheme.ids {
+		for pos := 1; pos < scheme.n; pos++ {
+			id := scheme.ids[pos]
 			if id == numParty {
 				continue
 			}
-			if id == 2 {
-				err := dkg.JointPkVerify(scheme.curve, scheme.pkProofs[id-2].Statement, scheme.jointPkProofs[id-1], scheme.jointPkProofSessionIds[id-1])
-				if err != nil {
-					return err
-				}
-				continue
-			}
-			err := dkg.JointPkVerify(scheme.curve, scheme.jointPkProofs[id-2].Statement2, scheme.jointPkProofs[id-1], scheme.jointPkProofSessionIds[id-1])
+			err := dkg.JointPkVerify(scheme.curve, scheme.basePoint(pos), scheme.jointPkProofs[id], scheme.jointPkProofSessionIds[id])
 			if err != nil {
 				return err
 			}
@@ -137,23 +169,15 @@ func (scheme *Scheme) DKGStep3B() error {
 
 func (scheme *Scheme) DSStep1() error {
 	// generate nonce and proofs
-	var basePoint curves.Point
-	for id := 1; id <= scheme.n; id++ {
-		if id == 1 {
-			basePoint = scheme.curve.NewGeneratorPoint()
-		} else if id == 2 {
-			basePoint = scheme.pkProofs[0].Statement
-		} else {
-			basePoint = scheme.jointPkProofs[id-2].Statement2
-		}
-		k, nonceProof, nonceCommitment, nonceSessionId, err := ds.NonceComProve(scheme.curve, basePoint)
+	for pos, id := range scheme.ids {
+		k, nonceProof, nonceCommitment, nonceSessionId, err := ds.NonceComProve(scheme.curve, scheme.basePoint(pos), nil)
 		if err != nil {
 			return err
 		}
-		scheme.nonce[id-1] = k
-		scheme.nonceProofs[id-1] = nonceProof
-		scheme.nonceCommitments[id-1] = nonceCommitment
-		scheme.nonceProofSessionIds[id-1] = nonceSessionId
+		scheme.nonce[id] = k
+		scheme.nonceProofs[id] = nonceProof
+		scheme.nonceCommitments[id] = nonceCommitment
+		scheme.nonceProofSessionIds[id] = nonceSessionId
 	}
 	return nil
 }
@@ -163,20 +187,12 @@ func (scheme *Scheme) DSStep2A() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	var basePoint curves.Point
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for id := 1; id <= scheme.n; id++ {
+	for _, numParty := range scheme.ids {
+		for pos, id := range scheme.ids {
 			if id == numParty {
 				continue
 			}
-			if id == 1 {
-				basePoint = scheme.curve.NewGeneratorPoint()
-			} else if id == 2 {
-				basePoint = scheme.pkProofs[0].Statement
-			} else {
-				basePoint = scheme.jointPkProofs[id-2].Statement2
-			}
-			err := ds.NonceDeComVerify(scheme.curve, basePoint, scheme.nonceProofs[id-1], scheme.nonceCommitments[id-1], scheme.nonceProofSessionIds[id-1])
+			err := ds.NonceDeComVerify(scheme.curve, scheme.basePoint(pos), scheme.nonceProofs[id], scheme.nonceCommitments[id], scheme.nonceProofSessionIds[id])
 			if err != nil {
 				return err
 			}
@@ -185,37 +201,46 @@ func (scheme *Scheme) DSStep2A() error {
 	return nil
 }
 
-func (scheme *Scheme) DSStep2B() curves.Scalar {
+func (scheme *Scheme) DSStep2B() (curves.Scalar, error) {
 	// compute r-part of signature
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
 	var r curves.Scalar
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		R := scheme.nonceProofs[0].Statement
-		for id := 2; id <= scheme.n; id++ {
-			R = R.Add(scheme.nonceProofs[id-1].Statement)
+	var err error
+	for range scheme.ids {
+		R := scheme.nonceProofs[scheme.ids[0]].Statement
+		for _, id := range scheme.ids[1:] {
+			R = R.Add(scheme.nonceProofs[id].Statement)
+		}
+		r, err = ds.RPartComp(scheme.curve, R, scheme.jointPk(), scheme.signerId, scheme.message)
+		if err != nil {
+			return nil, err
 		}
-		r = ds.RPartComp(scheme.curve, R, scheme.message)
 	}
-	return r
+	return r, nil
 }
 
-func (scheme *Scheme) DSStep3A(r curves.Scalar) curves.Scalar {
+func (scheme *Scheme) DSStep3A(r curves.Scalar) (curves.Scalar, error) {
 	// compute s-part of signature
 	s := r
-	for id := 1; id <= scheme.n; id++ {
-		RId := scheme.nonceProofs[0].Statement
-		for j := 2; j <= id; j++ {
-			RId = RId.Add(scheme.nonceProofs[j-1].Statement)
-		}
-		if id == 1 {
-			s = ds.SPartComp(scheme.curve, r, s, scheme.sks[id-1], scheme.nonce[id-1], nil, RId, scheme.pkProofs[0].Statement)
+	var RId curves.Point
+	for pos, id := range scheme.ids {
+		var currentJointPk curves.Point
+		if pos == 0 {
+			RId = scheme.nonceProofs[id].Statement
+			currentJointPk = scheme.pkProofs[id].Statement
 		} else {
-			s = ds.SPartComp(scheme.curve, r, s, scheme.sks[id-1], scheme.nonce[id-1], nil, RId, scheme.jointPkProofs[id-1].Statement2)
+			RId = RId.Add(scheme.nonceProofs[id].Statement)
+			currentJointPk = scheme.jointPkProofs[id].Statement2
+		}
+		var err error
+		s, err = ds.SPartComp(scheme.curve, r, s, scheme.sks[id], scheme.nonce[id], nil, RId, currentJointPk)
+		if err != nil {
+			return nil, &ds.HopError{Hop: pos, Party: id, Err: err}
 		}
 	}
-	return s
+	return s, nil
 }
 
 func (scheme *Scheme) DSStep3B(r curves.Scalar, s curves.Scalar) error {
@@ -223,8 +248,8 @@ func (scheme *Scheme) DSStep3B(r curves.Scalar, s curves.Scalar) error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		err := verify.Verify(scheme.curve, nil, scheme.jointPkProofs[scheme.n-1].Statement2, scheme.message, r, s)
+	for range scheme.ids {
+		err := verify.Verify(scheme.curve, nil, scheme.jointPk(), scheme.signerId, scheme.message, r, s)
 		if err != nil {
 			return err
 		}
@@ -4,8 +4,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
-	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
-	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -13,28 +12,11 @@ import (
 func BenchmarkDKG(b *testing.B) {
 	curveInit := curves.K256()
 
-	nInit := num
-
 	str := "test message test message test message test message test message test message test message test message test message test message "
 	messageInit := []byte(str)
 
-	var sksInit [num]curves.Scalar
-	var pkProofsInit [num]*schnorr.Proof
-	var pkCommitmentsInit [num]schnorr.Commitment
-	var pkProofSessionIdsInit [num]schnorr.SessionId
-
-	var jointPkProofsInit [num]*chaumpedersen.Proof
-	var jointPkProofSessionIdsInit [num]chaumpedersen.SessionId
-
-	var nonceInit [num]curves.Scalar
-	var nonceProofsInit [num]*schnorr.Proof
-	var nonceCommitmentsInit [num]schnorr.Commitment
-	var nonceProofSessionIdsInit [num]schnorr.SessionId
-
-	scheme := NewScheme(curveInit, nInit, messageInit, sksInit, pkProofsInit, pkCommitmentsInit, pkProofSessionIdsInit,
-		jointPkProofsInit, jointPkProofSessionIdsInit, nonceInit, nonceProofsInit, nonceCommitmentsInit, nonceProofSessionIdsInit)
-
-	var err error
+	scheme, err := NewScheme(curveInit, parties.Range(50), messageInit, nil)
+	require.NoError(b, err)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -55,28 +37,12 @@ func BenchmarkDKG(b *testing.B) {
 func BenchmarkDS(b *testing.B) {
 	curveInit := curves.K256()
 
-	nInit := num
-
 	str := "test message test message test message test message test message test message test message test message test message test message "
 	messageInit := []byte(str)
 
-	var sksInit [num]curves.Scalar
-	var pkProofsInit [num]*schnorr.Proof
-	var pkCommitmentsInit [num]schnorr.Commitment
-	var pkProofSessionIdsInit [num]schnorr.SessionId
+	scheme, err := NewScheme(curveInit, parties.Range(50), messageInit, nil)
+	require.NoError(b, err)
 
-	var jointPkProofsInit [num]*chaumpedersen.Proof
-	var jointPkProofSessionIdsInit [num]chaumpedersen.SessionId
-
-	var nonceInit [num]curves.Scalar
-	var nonceProofsInit [num]*schnorr.Proof
-	var nonceCommitmentsInit [num]schnorr.Commitment
-	var nonceProofSessionIdsInit [num]schnorr.SessionId
-
-	scheme := NewScheme(curveInit, nInit, messageInit, sksInit, pkProofsInit, pkCommitmentsInit, pkProofSessionIdsInit,
-		jointPkProofsInit, jointPkProofSessionIdsInit, nonceInit, nonceProofsInit, nonceCommitmentsInit, nonceProofSessionIdsInit)
-
-	var err error
 	err = scheme.DKGStep1()
 	require.NoError(b, err, fmt.Sprintf("failed in step 1 of DKG"))
 
@@ -101,9 +67,11 @@ func BenchmarkDS(b *testing.B) {
 		err = scheme.DSStep2A()
 		require.NoError(b, err, fmt.Sprintf("failed in step 2A of DS"))
 
-		r = scheme.DSStep2B()
+		r, err = scheme.DSStep2B()
+		require.NoError(b, err, fmt.Sprintf("failed in step 2B of DS"))
 
-		s = scheme.DSStep3A(r)
+		s, err = scheme.DSStep3A(r)
+		require.NoError(b, err, fmt.Sprintf("failed in step 3A of DS"))
 
 		err = scheme.DSStep3B(r, s)
 		require.NoError(b, err, fmt.Sprintf("failed in step 3B of DS"))
@@ -122,3 +90,27 @@ func BenchmarkMul(b *testing.B) {
 		P.Mul(x)
 	}
 }
+
+func TestScheme(t *testing.T) {
+	curveInit := curves.SM2()
+	_, err := NewScheme(curveInit, []uint32{1}, nil, nil)
+	require.Error(t, err)
+
+	for _, ids := range [][]uint32{{1, 2}, {9, 4, 6}, {3, 10, 7, 12, 5}} {
+		scheme, err := NewScheme(curveInit, ids, []byte("test message"), nil)
+		require.NoError(t, err)
+
+		require.NoError(t, scheme.DKGStep1(), "failed in step 1 of DKG")
+		require.NoError(t, scheme.DKGStep2(), "failed in step 2 of DKG")
+		require.NoError(t, scheme.DKGStep3A(), "failed in step 3A of DKG")
+		require.NoError(t, scheme.DKGStep3B(), "failed in step 3B of DKG")
+
+		require.NoError(t, scheme.DSStep1(), "failed in step 1 of DS")
+		require.NoError(t, scheme.DSStep2A(), "failed in step 2A of DS")
+		r, err := scheme.DSStep2B()
+		require.NoError(t, err, "failed in step 2B of DS")
+		s, err := scheme.DSStep3A(r)
+		require.NoError(t, err, "failed in step 3A of DS")
+		require.NoError(t, scheme.DSStep3B(r, s), "failed in step 3B of DS")
+	}
+}
@@ -3,22 +3,25 @@ package verify
 import (
 	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
-	"math/big"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
 )
 
-func Verify(curve *curves.Curve, basePoint curves.Point, pk curves.Point, message []byte, r curves.Scalar, s curves.Scalar) error {
+// Verify checks a signature in the CRT form, as crtsm2/verify.Verify does
+func Verify(curve *curves.Curve, basePoint curves.Point, pk curves.Point, signerId []byte, message []byte, r curves.Scalar, s curves.Scalar) error {
 	if basePoint == nil {
 		basePoint = curve.NewGeneratorPoint()
 	}
 
 	R := pk.Mul(s).Sub(basePoint.Mul(r))
 
-	h := curve.Scalar.Hash(message)
+	h, err := sm2.Digest(curve, signerId, pk.Sub(basePoint), message)
+	if err != nil {
+		return err
+	}
 
-	RAffine := R.ToAffineCompressed()
-	rx, err := curve.Scalar.SetBigInt(new(big.Int).SetBytes(RAffine[1 : 1+(len(RAffine)>>1)]))
+	rx, err := sm2.XCoordinate(curve, R)
 	if err != nil {
-		panic("when computing x-coordinate of R")
+		return err
 	}
 
 	if r.Cmp(rx.Add(h)) != 0 {
@@ -27,3 +30,8 @@ func Verify(curve *curves.Curve, basePoint curves.Point, pk curves.Point, messag
 
 	return nil
 }
+
+// ToStandard is crtsm2/verify.ToStandard
+func ToStandard(curve *curves.Curve, pk curves.Point, r curves.Scalar, s curves.Scalar) (curves.Point, *sm2.Signature) {
+	return pk.Sub(curve.NewGeneratorPoint()), &sm2.Signature{R: r, S: s.Sub(r)}
+}
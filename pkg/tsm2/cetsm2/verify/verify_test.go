@@ -3,6 +3,7 @@ package verify
 import (
 	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
 	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/dkg"
 	"github.com/coinbase/kryptology/pkg/tsm2/cetsm2/ds"
 	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
@@ -15,6 +16,7 @@ func TestDSOverMultipleCurves(t *testing.T) {
 	curveInstances := []*curves.Curve{
 		curves.K256(),
 		curves.P256(),
+		curves.SM2(),
 	}
 
 	const n = 50
@@ -34,11 +36,12 @@ func TestDSOverMultipleCurves(t *testing.T) {
 
 	str := "test message"
 	message := []byte(str)
+	signerId := []byte("ALICE123@YAHOO.COM")
 
 	for i, curve := range curveInstances {
 		// generate pks and proofs
 		for id := 1; id <= n; id++ {
-			sk, pkProof, commitment, sessionId, err := dkg.PkComProve(curve)
+			sk, pkProof, commitment, sessionId, err := dkg.PkComProve(curve, nil)
 			require.NoError(t, err, fmt.Sprintf("failed in curve %d when generating pk with comproof for party %d", i, id))
 			sks[id-1] = sk
 			pkProofs[id-1] = pkProof
@@ -55,13 +58,13 @@ func TestDSOverMultipleCurves(t *testing.T) {
 		// generate (mid-)joint pks
 		for id := 2; id <= n; id++ {
 			if id == 2 {
-				jointPkProof, sessionId, err := dkg.JointPkCompProve(curve, sks[id-1], pkProofs[id-2].Statement)
+				jointPkProof, sessionId, err := dkg.JointPkCompProve(curve, sks[id-1], pkProofs[id-2].Statement, nil)
 				require.NoError(t, err, fmt.Sprintf("failed in curve %d when party %d computing joint pk with proof", i, id))
 				jointPkProofs[id-1] = jointPkProof
 				jointPkProofSessionIds[id-1] = sessionId
 				continue
 			}
-			jointPkProof, sessionId, err := dkg.JointPkCompProve(curve, sks[id-1], jointPkProofs[id-2].Statement2)
+			jointPkProof, sessionId, err := dkg.JointPkCompProve(curve, sks[id-1], jointPkProofs[id-2].Statement2, nil)
 			require.NoError(t, err, fmt.Sprintf("failed in curve %d when party %d computing joint pk with proof", i, id))
 			jointPkProofs[id-1] = jointPkProof
 			jointPkProofSessionIds[id-1] = sessionId
@@ -88,7 +91,7 @@ func TestDSOverMultipleCurves(t *testing.T) {
 			} else {
 				basePoint = jointPkProofs[id-2].Statement2
 			}
-			k, nonceProof, commitment, sessionId, err := ds.NonceComProve(curve, basePoint)
+			k, nonceProof, commitment, sessionId, err := ds.NonceComProve(curve, basePoint, nil)
 			require.NoError(t, err, fmt.Sprintf("faied in curve %d when party %d generating nonce", i, id))
 			nonces[id-1] = k
 			nonceProofs[id-1] = nonceProof
@@ -115,7 +118,8 @@ func TestDSOverMultipleCurves(t *testing.T) {
 			R = R.Add(nonceProofs[id-1].Statement)
 		}
 
-		r := ds.RPartComp(curve, R, message)
+		r, err := ds.RPartComp(curve, R, jointPkProofs[n-1].Statement2, signerId, message)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d when computing the r part", i))
 
 		// compute s-part of signature
 		s := r
@@ -125,16 +129,25 @@ func TestDSOverMultipleCurves(t *testing.T) {
 				RId = RId.Add(nonceProofs[j-1].Statement)
 			}
 			if id == 1 {
-				s = ds.SPartComp(curve, r, s, sks[id-1], nonces[id-1], nil, RId, pkProofs[0].Statement)
+				s, err = ds.SPartComp(curve, r, s, sks[id-1], nonces[id-1], nil, RId, pkProofs[0].Statement)
 			} else {
-				s = ds.SPartComp(curve, r, s, sks[id-1], nonces[id-1], nil, RId, jointPkProofs[id-1].Statement2)
+				s, err = ds.SPartComp(curve, r, s, sks[id-1], nonces[id-1], nil, RId, jointPkProofs[id-1].Statement2)
 			}
+			require.NoError(t, err, fmt.Sprintf("failed in computing the s part of party %d", id))
 		}
 		/*******************************************
 		BACK TO THE PURE VERIFICATION ALGORITHM TEST
 		*******************************************/
 
-		err := Verify(curve, nil, jointPkProofs[n-1].Statement2, message, r, s)
+		err = Verify(curve, nil, jointPkProofs[n-1].Statement2, signerId, message, r, s)
 		require.NoError(t, err, fmt.Sprintf("failed in curve %d when verify the signature", i))
+
+		err = Verify(curve, nil, jointPkProofs[n-1].Statement2, nil, message, r, s)
+		require.Error(t, err, fmt.Sprintf("failed in curve %d when verifying under another signer id", i))
+
+		// the signature must also pass the standard GB/T 32918 verification
+		pk, sig := ToStandard(curve, jointPkProofs[n-1].Statement2, r, s)
+		err = sm2.Verify(curve, pk, signerId, message, sig)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d when verify the standard signature", i))
 	}
 }
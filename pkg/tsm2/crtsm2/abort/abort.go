@@ -0,0 +1,252 @@
+// Package abort reports which CRT-SM2 party sent an invalid proof.
+//
+// An Error names the culprit together with the evidence of the failed check: the proof, its commitment and session
+// id, and the public inputs that the verifier was given. Any other party can re-run the check with Evidence.Confirm
+// before excluding the culprit, rather than trusting the accuser. Confirm takes the confirming party's own view of the
+// check, i.e. the proof that it received from the culprit over the broadcast channel and the session id and public
+// inputs that it derived itself, so an accuser can neither forge a proof of an honest party nor re-check an honest
+// proof against other inputs.
+package abort
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/decrypt"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/dkg"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/ds"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/exchange"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+	"github.com/coinbase/kryptology/pkg/zkp/reg"
+	"github.com/coinbase/kryptology/pkg/zkp/rre"
+	"github.com/coinbase/kryptology/pkg/zkp/rspdl"
+	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+)
+
+// Check names the verification function that failed
+type Check string
+
+// The public inputs of each check, in the order in which they are stored in Evidence.Inputs, follow the name
+const (
+	DkgPkDeCom    Check = "dkg.PkDeComVerify"            // none
+	DkgREG        Check = "dkg.REGVerify"                // T
+	DkgRSPDL      Check = "dkg.RSPDLVerify"              // T, U, V, X
+	DkgSigmaREG   Check = "dkg.SigmaREGVerify"           // T
+	DkgRREDeCom   Check = "dkg.RREDeComVerify"           // T, U, V
+	DkgDDHDeCom   Check = "dkg.DDHDeComVerify"           // P, UPrime
+	DkgSigmaDDH   Check = "dkg.SigmaDDHVerify"           // P, T
+	DkgDDHKey     Check = "dkg.DDHKeyVerify"             // T_i
+	DkgSigmaRev   Check = "dkg.SigmaRevealVerify"        // U_sigma_i, V_sigma_i, sigma_i P
+	DsNonceDeCom  Check = "ds.NonceDeComVerify"          // none
+	DsSRanCTGamma Check = "ds.SRanCTGammaVerify"         // T, U, V, R
+	DsDeltaEG     Check = "ds.DeltaEGVerify"             // T
+	DsREDeCom     Check = "ds.REDeComVerify"             // T, U, V
+	DsDDHDeCom    Check = "ds.DDHDeComVerify"            // P, UPrime
+	DsDDHKey      Check = "ds.DDHKeyVerify"              // T_i
+	DecPartial    Check = "decrypt.PartialDecryptVerify" // C1, Q_i
+	ExAgree       Check = "exchange.PartialAgreeVerify"  // W, Q_i + x̄ R_i
+)
+
+// numInputs is the number of public inputs of each check
+var numInputs = map[Check]int{
+	DkgPkDeCom:    0,
+	DkgREG:        1,
+	DkgRSPDL:      4,
+	DkgSigmaREG:   1,
+	DkgRREDeCom:   3,
+	DkgDDHDeCom:   2,
+	DkgSigmaDDH:   2,
+	DkgDDHKey:     1,
+	DkgSigmaRev:   3,
+	DsNonceDeCom:  0,
+	DsSRanCTGamma: 4,
+	DsDeltaEG:     1,
+	DsREDeCom:     3,
+	DsDDHDeCom:    2,
+	DsDDHKey:      1,
+	DecPartial:    2,
+	ExAgree:       2,
+}
+
+// Evidence is the public transcript of a failed check
+type Evidence struct {
+	Check Check
+	// Proof is the proof sent by the culprit, e.g. a *reg.Proof for DkgREG
+	Proof interface{}
+	// Commitment is only set for the checks that open a commitment
+	Commitment []byte
+	SessionId  []byte
+	Inputs     []curves.Point
+}
+
+// Error reports that Culprit sent a proof that failed a check in Phase
+type Error struct {
+	Culprit  uint32
+	Phase    string
+	Evidence *Evidence
+	Err      error
+}
+
+// New returns an Error blaming culprit for the failed check recorded in evidence
+func New(culprit uint32, phase string, evidence *Evidence, err error) *Error {
+	return &Error{
+		Culprit:  culprit,
+		Phase:    phase,
+		Evidence: evidence,
+		Err:      err,
+	}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("party %d failed %s in %s: %v", e.Culprit, e.Evidence.Check, e.Phase, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Confirm re-runs the check on the view of the confirming party and reports whether it fails again, which upholds the
+// accusation. The view holds the proof and commitment that the confirming party received from the culprit, and the
+// session id and public inputs that it derived itself. It returns an error if the evidence or the view is malformed,
+// or if the evidence is about another proof than the one the culprit sent.
+func (e *Evidence) Confirm(curve *curves.Curve, view *Evidence) (bool, error) {
+	if view == nil || view.Check != e.Check {
+		return false, fmt.Errorf("the view is not of check %s", e.Check)
+	}
+	if err := view.validate(); err != nil {
+		return false, err
+	}
+	if e.Proof == nil {
+		return false, fmt.Errorf("missing proof of %s", e.Check)
+	}
+	same, err := sameProof(e.Proof, view.Proof)
+	if err != nil {
+		return false, err
+	}
+	if !same || !bytes.Equal(e.Commitment, view.Commitment) {
+		return false, fmt.Errorf("the evidence is not about the proof that the culprit sent")
+	}
+	failed, err := view.recheck(curve)
+	if err != nil {
+		return false, err
+	}
+	return failed, nil
+}
+
+// sameProof reports whether two proofs have the same encoding
+func sameProof(proof, other interface{}) (bool, error) {
+	marshaler, ok := proof.(encoding.BinaryMarshaler)
+	if !ok {
+		return false, fmt.Errorf("cannot encode a proof of type %T", proof)
+	}
+	otherMarshaler, ok := other.(encoding.BinaryMarshaler)
+	if !ok {
+		return false, fmt.Errorf("cannot encode a proof of type %T", other)
+	}
+	data, err := marshaler.MarshalBinary()
+	if err != nil {
+		return false, err
+	}
+	otherData, err := otherMarshaler.MarshalBinary()
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(data, otherData), nil
+}
+
+// validate checks that the evidence has a proof and the public inputs of its check
+func (e *Evidence) validate() error {
+	n, ok := numInputs[e.Check]
+	if !ok {
+		return fmt.Errorf("unknown check %s", e.Check)
+	}
+	if len(e.Inputs) != n {
+		return fmt.Errorf("%s takes %d inputs, got %d", e.Check, n, len(e.Inputs))
+	}
+	for _, input := range e.Inputs {
+		if input == nil {
+			return fmt.Errorf("missing input of %s", e.Check)
+		}
+	}
+	if e.Proof == nil {
+		return fmt.Errorf("missing proof of %s", e.Check)
+	}
+	return nil
+}
+
+// recheck runs the check of the evidence and reports whether it fails
+func (e *Evidence) recheck(curve *curves.Curve) (bool, error) {
+	var err error
+	in := e.Inputs
+	switch e.Check {
+	case DkgPkDeCom, DsNonceDeCom:
+		proof, ok := e.Proof.(*schnorr.Proof)
+		if !ok {
+			return false, fmt.Errorf("%s takes a schnorr proof", e.Check)
+		}
+		if e.Check == DkgPkDeCom {
+			err = dkg.PkDeComVerify(curve, proof, e.Commitment, e.SessionId)
+		} else {
+			err = ds.NonceDeComVerify(curve, proof, e.Commitment, e.SessionId)
+		}
+	case DkgREG, DkgSigmaREG, DsDeltaEG:
+		proof, ok := e.Proof.(*reg.Proof)
+		if !ok {
+			return false, fmt.Errorf("%s takes a reg proof", e.Check)
+		}
+		switch e.Check {
+		case DkgREG:
+			err = dkg.REGVerify(curve, in[0], proof, e.SessionId)
+		case DkgSigmaREG:
+			err = dkg.SigmaREGVerify(curve, in[0], proof, e.SessionId)
+		default:
+			err = ds.DeltaEGVerify(curve, in[0], proof, e.SessionId)
+		}
+	case DkgRSPDL, DsSRanCTGamma:
+		proof, ok := e.Proof.(*rspdl.Proof)
+		if !ok {
+			return false, fmt.Errorf("%s takes an rspdl proof", e.Check)
+		}
+		if e.Check == DkgRSPDL {
+			err = dkg.RSPDLVerify(curve, in[0], proof, in[1], in[2], in[3], e.SessionId)
+		} else {
+			err = ds.SRanCTGammaVerify(curve, in[0], proof, in[1], in[2], in[3], e.SessionId)
+		}
+	case DkgRREDeCom, DsREDeCom:
+		proof, ok := e.Proof.(*rre.Proof)
+		if !ok {
+			return false, fmt.Errorf("%s takes an rre proof", e.Check)
+		}
+		if e.Check == DkgRREDeCom {
+			err = dkg.RREDeComVerify(curve, proof, e.Commitment, e.SessionId, in[0], in[1], in[2])
+		} else {
+			err = ds.REDeComVerify(curve, proof, e.Commitment, e.SessionId, in[0], in[1], in[2])
+		}
+	case DkgDDHDeCom, DkgSigmaDDH, DkgDDHKey, DkgSigmaRev, DsDDHDeCom, DsDDHKey, DecPartial, ExAgree:
+		proof, ok := e.Proof.(*chaumpedersen.Proof)
+		if !ok {
+			return false, fmt.Errorf("%s takes a chaum-pedersen proof", e.Check)
+		}
+		switch e.Check {
+		case DkgDDHDeCom:
+			err = dkg.DDHDeComVerify(curve, proof, e.Commitment, e.SessionId, in[0], in[1])
+		case DkgSigmaDDH:
+			err = dkg.SigmaDDHVerify(curve, proof, e.SessionId, in[0], in[1])
+		case DkgDDHKey:
+			err = dkg.DDHKeyVerify(proof, in[0])
+		case DkgSigmaRev:
+			err = dkg.SigmaRevealVerify(proof, in[0], in[1], in[2])
+		case DsDDHKey:
+			err = ds.DDHKeyVerify(proof, in[0])
+		case DecPartial:
+			err = decrypt.PartialDecryptVerify(curve, in[0], in[1], proof, e.SessionId)
+		case ExAgree:
+			err = exchange.PartialAgreeVerify(curve, in[0], in[1], proof, e.SessionId)
+		default:
+			err = ds.DDHDeComVerify(curve, proof, e.Commitment, e.SessionId, in[0], in[1])
+		}
+	}
+	return err != nil, nil
+}
@@ -0,0 +1,108 @@
+package abort
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/dkg"
+	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+)
+
+func TestConfirm(t *testing.T) {
+	curve := curves.SM2()
+	_, TProof, _, _, err := dkg.PkComProve(curve, nil)
+	require.NoError(t, err)
+	T := TProof.Statement
+
+	_, _, proof, sessionId, err := dkg.REGProve(curve, T, nil)
+	require.NoError(t, err)
+	bad := *proof
+	bad.A = bad.A.Double()
+	evidence := &Evidence{
+		Check:     DkgREG,
+		Proof:     &bad,
+		SessionId: sessionId,
+		Inputs:    []curves.Point{T},
+	}
+
+	// the confirming party received the same invalid proof
+	confirmed, err := evidence.Confirm(curve, &Evidence{Check: DkgREG, Proof: &bad, SessionId: sessionId, Inputs: []curves.Point{T}})
+	require.NoError(t, err)
+	require.True(t, confirmed)
+
+	// an honest proof is not confirmed, even if the accuser checked it against another key
+	evidence = &Evidence{Check: DkgREG, Proof: proof, SessionId: sessionId, Inputs: []curves.Point{T.Double()}}
+	confirmed, err = evidence.Confirm(curve, &Evidence{Check: DkgREG, Proof: proof, SessionId: sessionId, Inputs: []curves.Point{T}})
+	require.NoError(t, err)
+	require.False(t, confirmed)
+
+	// nor is an accusation about a proof that the culprit did not send
+	evidence = &Evidence{Check: DkgREG, Proof: &bad, SessionId: sessionId, Inputs: []curves.Point{T}}
+	_, err = evidence.Confirm(curve, &Evidence{Check: DkgREG, Proof: proof, SessionId: sessionId, Inputs: []curves.Point{T}})
+	require.Error(t, err)
+}
+
+func TestConfirmDeCom(t *testing.T) {
+	curve := curves.K256()
+	_, proof, commitment, sessionId, err := dkg.PkComProve(curve, nil)
+	require.NoError(t, err)
+	view := &Evidence{
+		Check:      DkgPkDeCom,
+		Proof:      proof,
+		Commitment: commitment,
+		SessionId:  sessionId,
+	}
+	evidence := *view
+	confirmed, err := evidence.Confirm(curve, view)
+	require.NoError(t, err)
+	require.False(t, confirmed)
+
+	// an accuser cannot open the commitment of the culprit to another proof
+	evidence.Proof = &schnorr.Proof{C: proof.C, S: proof.S.Add(curve.Scalar.One()), Statement: proof.Statement, K: proof.K}
+	_, err = evidence.Confirm(curve, view)
+	require.Error(t, err)
+
+	view.Proof = evidence.Proof
+	confirmed, err = evidence.Confirm(curve, view)
+	require.NoError(t, err)
+	require.True(t, confirmed)
+}
+
+func TestConfirmMalformed(t *testing.T) {
+	curve := curves.SM2()
+	_, proof, _, sessionId, err := dkg.PkComProve(curve, nil)
+	require.NoError(t, err)
+	G := curve.NewGeneratorPoint()
+
+	for _, evidence := range []*Evidence{
+		{Check: "dkg.Unknown", Proof: proof, SessionId: sessionId},
+		{Check: DkgREG, Proof: proof, SessionId: sessionId, Inputs: []curves.Point{G}},
+		{Check: DkgREG, SessionId: sessionId, Inputs: []curves.Point{G}},
+		{Check: DkgRSPDL, Proof: proof, SessionId: sessionId, Inputs: []curves.Point{G}},
+		{Check: DkgSigmaDDH, Proof: proof, SessionId: sessionId, Inputs: []curves.Point{G, nil}},
+	} {
+		_, err := evidence.Confirm(curve, evidence)
+		require.Error(t, err, "check %s", evidence.Check)
+	}
+
+	evidence := &Evidence{Check: DkgPkDeCom, Proof: proof, SessionId: sessionId}
+	_, err = evidence.Confirm(curve, nil)
+	require.Error(t, err)
+	_, err = evidence.Confirm(curve, &Evidence{Check: DsNonceDeCom, Proof: proof, SessionId: sessionId})
+	require.Error(t, err)
+}
+
+func TestError(t *testing.T) {
+	cause := fmt.Errorf("invalid proof")
+	err := fmt.Errorf("wrapped: %w", New(7, "DSPhase2", &Evidence{Check: DsNonceDeCom}, cause))
+	var abortErr *Error
+	require.True(t, errors.As(err, &abortErr))
+	require.Equal(t, uint32(7), abortErr.Culprit)
+	require.Equal(t, "DSPhase2", abortErr.Phase)
+	require.ErrorIs(t, err, cause)
+	require.Contains(t, err.Error(), "party 7 failed ds.NonceDeComVerify in DSPhase2")
+}
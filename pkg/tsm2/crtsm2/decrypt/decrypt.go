@@ -0,0 +1,61 @@
+// Package decrypt holds the steps of the distributed decryption of SM2 ciphertexts encrypted to the published public
+// key Q - G of CRT-SM2, where Q = sum(x_i * G) and the SM2 private key is x - 1.
+//
+// Every holder of x_i contributes D_i = x_i * C1 together with a Chaum-Pedersen proof that log_G(Q_i) = log_C1(D_i),
+// and anyone can recover the plaintext from sum(D_i) - C1 = (x - 1) * C1 once the contributions have been verified.
+package decrypt
+
+import (
+	"fmt"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/ot/base/simplest"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+)
+
+// PartialDecryptProve computes the contribution of the holder of x_i to the decryption of a ciphertext with the
+// ephemeral key C1. Q_i is stored in Statement1 of the proof and D_i in Statement2.
+func PartialDecryptProve(curve *curves.Curve, C1 curves.Point, xi curves.Scalar) (*chaumpedersen.Proof, chaumpedersen.SessionId, error) {
+	uniqueSessionId := [simplest.DigestSize]byte{}
+	partialProofSessionId := uniqueSessionId[:]
+	partialProver, err := chaumpedersen.NewProver(curve, nil, C1, partialProofSessionId)
+	if err != nil {
+		return nil, nil, err
+	}
+	partialProof, err := partialProver.Prove(xi)
+	if err != nil {
+		return nil, nil, err
+	}
+	return partialProof, partialProofSessionId, nil
+}
+
+// PartialDecryptVerify checks the contribution of the party whose share of Q is Qi
+func PartialDecryptVerify(curve *curves.Curve, C1 curves.Point, Qi curves.Point, partialProof *chaumpedersen.Proof, partialProofSessionId chaumpedersen.SessionId) error {
+	if partialProof == nil || partialProof.Statement1 == nil || partialProof.Statement2 == nil {
+		return fmt.Errorf("missing partial decryption")
+	}
+	if err := chaumpedersen.Verify(partialProof, curve, nil, C1, partialProofSessionId); err != nil {
+		return err
+	}
+	if !partialProof.Statement1.Equal(Qi) {
+		return fmt.Errorf("partial decryption is not bound to the share of Q")
+	}
+	return nil
+}
+
+// Combine adds up the verified contributions D_i, subtracts C1 and recovers the plaintext of ct, which must match C3
+func Combine(ct *sm2.Ciphertext, Ds []curves.Point) ([]byte, error) {
+	if len(Ds) == 0 {
+		return nil, fmt.Errorf("no partial decryptions")
+	}
+	D := Ds[0]
+	for _, Di := range Ds[1:] {
+		D = D.Add(Di)
+	}
+	D = D.Sub(ct.C1)
+	if D.IsIdentity() {
+		return nil, fmt.Errorf("the partial decryptions add up to C1")
+	}
+	return sm2.DecryptWithPoint(ct, D)
+}
@@ -2,8 +2,8 @@ package dkg
 
 import (
 	"crypto/rand"
+	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
-	"github.com/coinbase/kryptology/pkg/ot/base/simplest"
 	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/sign_offline"
 	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
 	"github.com/coinbase/kryptology/pkg/zkp/reg"
@@ -16,11 +16,9 @@ import (
 
 // pk is stored in the Statement of pkProof
 
-func PkComProve(curve *curves.Curve) (curves.Scalar, *schnorr.Proof, schnorr.Commitment, schnorr.SessionId, error) {
+func PkComProve(curve *curves.Curve, pkProofSessionId []byte) (curves.Scalar, *schnorr.Proof, schnorr.Commitment, schnorr.SessionId, error) {
 	sk := curve.Scalar.Random(rand.Reader)
 
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	pkProofSessionId := uniqueSessionId[:]
 	pkProver := schnorr.NewProver(curve, nil, pkProofSessionId)
 	pkProof, pkCommitment, err := pkProver.ProveCommit(sk)
 
@@ -31,31 +29,34 @@ func PkDeComVerify(curve *curves.Curve, pkProof *schnorr.Proof, pkCommitment sch
 	return schnorr.DecommitVerify(pkProof, pkCommitment, curve, nil, pkProofSessionId)
 }
 
+// PkDeComBatchVerify is PkDeComVerify for the proofs of all the peers at once
+func PkDeComBatchVerify(curve *curves.Curve, pkProofs []*schnorr.Proof, pkCommitments []schnorr.Commitment, pkProofSessionIds [][]byte) error {
+	return schnorr.DecommitBatchVerify(pkProofs, pkCommitments, curve, nil, pkProofSessionIds)
+}
+
 // functions for phase 2
 
-func REGProve(curve *curves.Curve, T curves.Point) (curves.Scalar, *reg.Proof, reg.SessionId, error) {
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	regProofSessionId := uniqueSessionId[:]
+// REGProve draws gamma and encrypts it under T. Besides gamma it returns the randomness of the encryption, which a
+// threshold scheme needs to link the encryption to its Feldman commitment to gamma.
+func REGProve(curve *curves.Curve, T curves.Point, regProofSessionId []byte) (curves.Scalar, curves.Scalar, *reg.Proof, reg.SessionId, error) {
 	regProver, err := reg.NewProver(curve, nil, T, regProofSessionId)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 	gamma := curve.Scalar.Random(rand.Reader)
 	rGamma := curve.Scalar.Random(rand.Reader)
 	regProof, err := regProver.Prove(gamma, rGamma)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
-	return gamma, regProof, regProofSessionId, nil
+	return gamma, rGamma, regProof, regProofSessionId, nil
 }
 
 func REGVerify(curve *curves.Curve, T curves.Point, regProof *reg.Proof, regProofSessionId reg.SessionId) error {
 	return reg.Verify(regProof, curve, nil, T, regProofSessionId)
 }
 
-func RSPDLProve(curve *curves.Curve, T curves.Point, U curves.Point, V curves.Point, X curves.Point, x curves.Scalar) (*rspdl.Proof, rspdl.SessionId, error) {
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	rspdlProofSessionId := uniqueSessionId[:]
+func RSPDLProve(curve *curves.Curve, T curves.Point, U curves.Point, V curves.Point, X curves.Point, x curves.Scalar, rspdlProofSessionId []byte) (*rspdl.Proof, rspdl.SessionId, error) {
 	rspdlProver, err := rspdl.NewProver(curve, nil, T, U, V, X, rspdlProofSessionId)
 	if err != nil {
 		return nil, nil, err
@@ -74,6 +75,7 @@ func RSPDLVerify(curve *curves.Curve, T curves.Point, rspdlProof *rspdl.Proof, U
 
 // functions for phase 3
 
+// MtASimu runs both ends of an MtA on x and gamma in-process. The networked participant exchanges the messages instead.
 func MtASimu[A any, B any](curve *curves.Curve, gamma curves.Scalar, x curves.Scalar, sender sign_offline.MTASender[A, B], receiver sign_offline.MTAReceiver[A, B]) (curves.Scalar, curves.Scalar) {
 	a := receiver.Init(x)
 	alpha, b := sender.Update(gamma, a)
@@ -82,9 +84,7 @@ func MtASimu[A any, B any](curve *curves.Curve, gamma curves.Scalar, x curves.Sc
 	return alpha, beta
 }
 
-func SigmaREGProve(curve *curves.Curve, T curves.Point, sigma curves.Scalar) (curves.Scalar, *reg.Proof, reg.SessionId, error) {
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	sigmaRegProofSessionId := uniqueSessionId[:]
+func SigmaREGProve(curve *curves.Curve, T curves.Point, sigma curves.Scalar, sigmaRegProofSessionId []byte) (curves.Scalar, *reg.Proof, reg.SessionId, error) {
 	sigmaRegProver, err := reg.NewProver(curve, nil, T, sigmaRegProofSessionId)
 	if err != nil {
 		return nil, nil, nil, err
@@ -104,9 +104,7 @@ func SigmaREGVerify(curve *curves.Curve, T curves.Point, sigmaRegProof *reg.Proo
 
 // functions for phase 4
 
-func RREComProve(curve *curves.Curve, T curves.Point, U curves.Point, V curves.Point) (*rre.Proof, rre.Commitment, rre.SessionId, error) {
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	rreProofSessionId := uniqueSessionId[:]
+func RREComProve(curve *curves.Curve, T curves.Point, U curves.Point, V curves.Point, rreProofSessionId []byte) (*rre.Proof, rre.Commitment, rre.SessionId, error) {
 	rreProver, err := rre.NewProver(curve, nil, T, U, V, rreProofSessionId)
 	if err != nil {
 		return nil, nil, nil, err
@@ -126,12 +124,10 @@ func RREDeComVerify(curve *curves.Curve, rreProof *rre.Proof, rreCommitment rre.
 	return rre.DeComVerify(rreProof, rreCommitment, curve, nil, T, U, V, rreProofSessionId)
 }
 
-func DDHComProve(curve *curves.Curve, P curves.Point, UPrime curves.Point, Ti curves.Point, UiPrime curves.Point, di curves.Scalar) (*chaumpedersen.Proof, chaumpedersen.Commitment, chaumpedersen.SessionId, error) {
+func DDHComProve(curve *curves.Curve, P curves.Point, UPrime curves.Point, Ti curves.Point, UiPrime curves.Point, di curves.Scalar, ddhProofSessionId []byte) (*chaumpedersen.Proof, chaumpedersen.Commitment, chaumpedersen.SessionId, error) {
 	if P == nil {
 		P = curve.NewGeneratorPoint()
 	}
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	ddhProofSessionId := uniqueSessionId[:]
 	ddhProver, err := chaumpedersen.NewProver(curve, P, UPrime, ddhProofSessionId)
 	if err != nil {
 		return nil, nil, nil, err
@@ -152,12 +148,18 @@ func DDHDeComVerify(curve *curves.Curve, ddhProof *chaumpedersen.Proof, ddhCommi
 	return chaumpedersen.DeComVerify(ddhProof, ddhCommitment, curve, P, UPrime, ddhProofSessionId)
 }
 
-func SigmaDDHProve(curve *curves.Curve, P curves.Point, T curves.Point, USigma curves.Point, VSigmaPrime curves.Point, rSigma curves.Scalar) (*chaumpedersen.Proof, chaumpedersen.SessionId, error) {
+// DDHDeComBatchVerify is DDHDeComVerify for the proofs of all the peers at once
+func DDHDeComBatchVerify(curve *curves.Curve, ddhProofs []*chaumpedersen.Proof, ddhCommitments []chaumpedersen.Commitment, ddhProofSessionIds [][]byte, P curves.Point, UPrime curves.Point) error {
+	if P == nil {
+		P = curve.NewGeneratorPoint()
+	}
+	return chaumpedersen.DeComBatchVerify(ddhProofs, ddhCommitments, curve, P, UPrime, ddhProofSessionIds)
+}
+
+func SigmaDDHProve(curve *curves.Curve, P curves.Point, T curves.Point, USigma curves.Point, VSigmaPrime curves.Point, rSigma curves.Scalar, ddhProofSessionId []byte) (*chaumpedersen.Proof, chaumpedersen.SessionId, error) {
 	if P == nil {
 		P = curve.NewGeneratorPoint()
 	}
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	ddhProofSessionId := uniqueSessionId[:]
 	ddhProver, err := chaumpedersen.NewProver(curve, P, T, ddhProofSessionId)
 	if err != nil {
 		return nil, nil, err
@@ -177,3 +179,104 @@ func SigmaDDHVerify(curve *curves.Curve, ddhProof *chaumpedersen.Proof, ddhProof
 	}
 	return chaumpedersen.Verify(ddhProof, curve, P, T, ddhProofSessionId)
 }
+
+// DDHKeyVerify checks that the partial decryption in ddhProof was made with the decryption key share behind Ti
+func DDHKeyVerify(ddhProof *chaumpedersen.Proof, Ti curves.Point) error {
+	if ddhProof == nil || ddhProof.Statement1 == nil || !ddhProof.Statement1.Equal(Ti) {
+		return fmt.Errorf("partial decryption is not under the encryption key share")
+	}
+	return nil
+}
+
+// SigmaRevealVerify checks that the revealed sigma_i, given as SigmaI = sigma_i * P, is the plaintext of the
+// encryption (USigma, VSigma) that ddhProof opens
+func SigmaRevealVerify(ddhProof *chaumpedersen.Proof, USigma curves.Point, VSigma curves.Point, SigmaI curves.Point) error {
+	if ddhProof == nil || ddhProof.Statement1 == nil || ddhProof.Statement2 == nil {
+		return fmt.Errorf("missing proof")
+	}
+	if !ddhProof.Statement1.Equal(USigma) || !SigmaI.Equal(VSigma.Sub(ddhProof.Statement2)) {
+		return fmt.Errorf("revealed value is not the plaintext of its encryption")
+	}
+	return nil
+}
+
+// functions for refresh
+
+// ZeroSharesProve splits zero into one random share per party
+// the public counterpart of each share is stored in the Statement of its proof
+func ZeroSharesProve(curve *curves.Curve, ids []uint32, zeroShareProofSessionId []byte) (map[uint32]curves.Scalar, map[uint32]*schnorr.Proof, schnorr.SessionId, error) {
+	prover := schnorr.NewProver(curve, nil, zeroShareProofSessionId)
+
+	shares := make(map[uint32]curves.Scalar, len(ids))
+	proofs := make(map[uint32]*schnorr.Proof, len(ids))
+	sum := curve.Scalar.Zero()
+	for k, id := range ids {
+		share := curve.Scalar.Random(rand.Reader)
+		if k == len(ids)-1 {
+			share = sum.Neg()
+		}
+		sum = sum.Add(share)
+		proof, err := prover.Prove(share)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		shares[id] = share
+		proofs[id] = proof
+	}
+	return shares, proofs, zeroShareProofSessionId, nil
+}
+
+func ZeroSharesVerify(curve *curves.Curve, proofs map[uint32]*schnorr.Proof, zeroShareProofSessionId schnorr.SessionId) error {
+	batch := make([]*schnorr.Proof, 0, len(proofs))
+	sessionIds := make([][]byte, 0, len(proofs))
+	for _, proof := range proofs {
+		batch = append(batch, proof)
+		sessionIds = append(sessionIds, zeroShareProofSessionId)
+	}
+	if err := schnorr.BatchVerify(batch, curve, nil, sessionIds); err != nil {
+		return err
+	}
+	sum := curve.NewIdentityPoint()
+	for _, proof := range batch {
+		sum = sum.Add(proof.Statement)
+	}
+	if !sum.IsIdentity() {
+		return fmt.Errorf("shares do not add up to zero")
+	}
+	return nil
+}
+
+// PkReComProve commits to a proof of the refreshed sk, whose pk is stored in the Statement of pkProof
+func PkReComProve(curve *curves.Curve, sk curves.Scalar, pkProofSessionId []byte) (*schnorr.Proof, schnorr.Commitment, schnorr.SessionId, error) {
+	pkProver := schnorr.NewProver(curve, nil, pkProofSessionId)
+	pkProof, pkCommitment, err := pkProver.ProveCommit(sk)
+
+	return pkProof, pkCommitment, pkProofSessionId, err
+}
+
+// functions for threshold sharing
+
+// GammaLinkProve proves that the Feldman commitment Gamma = gamma*P opens the encryption (A, B) = (r*P, gamma*P + r*T)
+// of gamma, i.e. that A and B - Gamma have the same discrete log r with respect to P and T
+func GammaLinkProve(curve *curves.Curve, T curves.Point, Gamma curves.Point, regProof *reg.Proof, rGamma curves.Scalar, linkProofSessionId []byte) (*chaumpedersen.Proof, chaumpedersen.SessionId, error) {
+	linkProver, err := chaumpedersen.NewProver(curve, curve.NewGeneratorPoint(), T, linkProofSessionId)
+	if err != nil {
+		return nil, nil, err
+	}
+	linkProof, err := linkProver.ProveWithStatement(regProof.A, regProof.B.Sub(Gamma), rGamma)
+	if err != nil {
+		return nil, nil, err
+	}
+	return linkProof, linkProofSessionId, nil
+}
+
+// GammaLinkVerify checks that linkProof ties the Feldman commitment Gamma to the encryption in regProof
+func GammaLinkVerify(curve *curves.Curve, T curves.Point, Gamma curves.Point, regProof *reg.Proof, linkProof *chaumpedersen.Proof, linkProofSessionId chaumpedersen.SessionId) error {
+	if linkProof == nil || linkProof.Statement1 == nil || linkProof.Statement2 == nil {
+		return fmt.Errorf("missing proof")
+	}
+	if !linkProof.Statement1.Equal(regProof.A) || !linkProof.Statement2.Equal(regProof.B.Sub(Gamma)) {
+		return fmt.Errorf("proof is not about the encryption of gamma")
+	}
+	return chaumpedersen.Verify(linkProof, curve, curve.NewGeneratorPoint(), T, linkProofSessionId)
+}
@@ -2,8 +2,8 @@ package ds
 
 import (
 	"crypto/rand"
+	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
-	"github.com/coinbase/kryptology/pkg/ot/base/simplest"
 	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/sign_offline"
 	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
 	"github.com/coinbase/kryptology/pkg/zkp/reg"
@@ -14,11 +14,9 @@ import (
 
 // functions for phase 1
 
-func NonceComProve(curve *curves.Curve) (curves.Scalar, *schnorr.Proof, schnorr.Commitment, schnorr.SessionId, error) {
+func NonceComProve(curve *curves.Curve, kProofSessionId []byte) (curves.Scalar, *schnorr.Proof, schnorr.Commitment, schnorr.SessionId, error) {
 	k := curve.Scalar.Random(rand.Reader)
 
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	kProofSessionId := uniqueSessionId[:]
 	kProver := schnorr.NewProver(curve, nil, kProofSessionId)
 	kProof, kCommitment, err := kProver.ProveCommit(k)
 
@@ -29,11 +27,14 @@ func NonceDeComVerify(curve *curves.Curve, kProof *schnorr.Proof, kCommitment sc
 	return schnorr.DecommitVerify(kProof, kCommitment, curve, nil, kProofSessionId)
 }
 
+// NonceDeComBatchVerify is NonceDeComVerify for the proofs of all the peers at once
+func NonceDeComBatchVerify(curve *curves.Curve, kProofs []*schnorr.Proof, kCommitments []schnorr.Commitment, kProofSessionIds [][]byte) error {
+	return schnorr.DecommitBatchVerify(kProofs, kCommitments, curve, nil, kProofSessionIds)
+}
+
 // functions for phase 2
 
-func SRanCTGammaProve(curve *curves.Curve, T curves.Point, U curves.Point, V curves.Point, R curves.Point, k curves.Scalar) (*rspdl.Proof, rspdl.SessionId, error) {
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	sRanProofSessionId := uniqueSessionId[:]
+func SRanCTGammaProve(curve *curves.Curve, T curves.Point, U curves.Point, V curves.Point, R curves.Point, k curves.Scalar, sRanProofSessionId []byte) (*rspdl.Proof, rspdl.SessionId, error) {
 	sRanProver, err := rspdl.NewProver(curve, nil, T, U, V, R, sRanProofSessionId)
 	if err != nil {
 		return nil, nil, err
@@ -54,6 +55,7 @@ func SRanCTGammaVerify(curve *curves.Curve, T curves.Point, sRanCTGammaProof *rs
 
 // functions for phase 3
 
+// MtASimu multiplies k and gamma with both ends of the MtA in the same process, as the simulated schemes do
 func MtASimu[A any, B any](curve *curves.Curve, gamma curves.Scalar, k curves.Scalar, sender sign_offline.MTASender[A, B], receiver sign_offline.MTAReceiver[A, B]) (curves.Scalar, curves.Scalar) {
 	a := receiver.Init(k)
 	mu, b := sender.Update(gamma, a)
@@ -62,9 +64,7 @@ func MtASimu[A any, B any](curve *curves.Curve, gamma curves.Scalar, k curves.Sc
 	return mu, nu
 }
 
-func DeltaEGProve(curve *curves.Curve, T curves.Point, delta curves.Scalar) (*reg.Proof, reg.SessionId, error) {
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	deltaEGProofSessionId := uniqueSessionId[:]
+func DeltaEGProve(curve *curves.Curve, T curves.Point, delta curves.Scalar, deltaEGProofSessionId []byte) (*reg.Proof, reg.SessionId, error) {
 	deltaEGProver, err := reg.NewProver(curve, nil, T, deltaEGProofSessionId)
 	if err != nil {
 		return nil, nil, err
@@ -84,9 +84,7 @@ func DeltaEGVerify(curve *curves.Curve, T curves.Point, deltaEGProof *reg.Proof,
 
 // functions for phase 4
 
-func REComProve(curve *curves.Curve, T curves.Point, U curves.Point, V curves.Point) (*rre.Proof, rre.Commitment, rre.SessionId, error) {
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	rreProofSessionId := uniqueSessionId[:]
+func REComProve(curve *curves.Curve, T curves.Point, U curves.Point, V curves.Point, rreProofSessionId []byte) (*rre.Proof, rre.Commitment, rre.SessionId, error) {
 	rreProver, err := rre.NewProver(curve, nil, T, U, V, rreProofSessionId)
 	if err != nil {
 		return nil, nil, nil, err
@@ -106,12 +104,10 @@ func REDeComVerify(curve *curves.Curve, rreProof *rre.Proof, rreCommitment rre.C
 	return rre.DeComVerify(rreProof, rreCommitment, curve, nil, T, U, V, rreProofSessionId)
 }
 
-func DDHComProve(curve *curves.Curve, P curves.Point, UPrime curves.Point, Ti curves.Point, UiPrime curves.Point, di curves.Scalar) (*chaumpedersen.Proof, chaumpedersen.Commitment, chaumpedersen.SessionId, error) {
+func DDHComProve(curve *curves.Curve, P curves.Point, UPrime curves.Point, Ti curves.Point, UiPrime curves.Point, di curves.Scalar, ddhProofSessionId []byte) (*chaumpedersen.Proof, chaumpedersen.Commitment, chaumpedersen.SessionId, error) {
 	if P == nil {
 		P = curve.NewGeneratorPoint()
 	}
-	uniqueSessionId := [simplest.DigestSize]byte{}
-	ddhProofSessionId := uniqueSessionId[:]
 	ddhProver, err := chaumpedersen.NewProver(curve, P, UPrime, ddhProofSessionId)
 	if err != nil {
 		return nil, nil, nil, err
@@ -131,3 +127,19 @@ func DDHDeComVerify(curve *curves.Curve, ddhProof *chaumpedersen.Proof, ddhCommi
 	}
 	return chaumpedersen.DeComVerify(ddhProof, ddhCommitment, curve, P, UPrime, ddhProofSessionId)
 }
+
+// DDHDeComBatchVerify is DDHDeComVerify for the proofs of all the peers at once
+func DDHDeComBatchVerify(curve *curves.Curve, ddhProofs []*chaumpedersen.Proof, ddhCommitments []chaumpedersen.Commitment, ddhProofSessionIds [][]byte, P curves.Point, UPrime curves.Point) error {
+	if P == nil {
+		P = curve.NewGeneratorPoint()
+	}
+	return chaumpedersen.DeComBatchVerify(ddhProofs, ddhCommitments, curve, P, UPrime, ddhProofSessionIds)
+}
+
+// DDHKeyVerify checks that the partial decryption in ddhProof was made with the decryption key share behind Ti
+func DDHKeyVerify(ddhProof *chaumpedersen.Proof, Ti curves.Point) error {
+	if ddhProof == nil || ddhProof.Statement1 == nil || !ddhProof.Statement1.Equal(Ti) {
+		return fmt.Errorf("partial decryption is not under the encryption key share")
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Package exchange holds the steps of an SM2 key exchange from GB/T 32918.3 in which one side is the CRT-SM2 key
+// Q = sum(x_i * G), whose standard static key is d = x - 1.
+//
+// The parties draw the ephemeral key R = sum(r_i * G) with ds.NonceComProve. Given the base W = P + x̄' R' computed
+// from the static and ephemeral keys of the peer, every party contributes V_i = t_i * W with t_i = x_i + x̄ r_i,
+// together with a Chaum-Pedersen proof that log_G(Q_i + x̄ R_i) = log_W(V_i). The shared point
+// (d + x̄ r) * W is then sum(V_i) - W.
+package exchange
+
+import (
+	"fmt"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/ot/base/simplest"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+)
+
+// PartialAgreeProve computes the contribution of the holder of x_i and r_i to the shared point.
+// Q_i + x̄ R_i is stored in Statement1 of the proof and V_i in Statement2.
+func PartialAgreeProve(curve *curves.Curve, W curves.Point, xBar curves.Scalar, xi curves.Scalar, ri curves.Scalar) (*chaumpedersen.Proof, chaumpedersen.SessionId, error) {
+	uniqueSessionId := [simplest.DigestSize]byte{}
+	agreeProofSessionId := uniqueSessionId[:]
+	agreeProver, err := chaumpedersen.NewProver(curve, nil, W, agreeProofSessionId)
+	if err != nil {
+		return nil, nil, err
+	}
+	agreeProof, err := agreeProver.Prove(xi.Add(xBar.Mul(ri)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return agreeProof, agreeProofSessionId, nil
+}
+
+// PartialAgreeKey computes t_i * G = Q_i + x̄ R_i, which a contribution is checked against
+func PartialAgreeKey(xBar curves.Scalar, Qi curves.Point, Ri curves.Point) curves.Point {
+	return Qi.Add(Ri.Mul(xBar))
+}
+
+// PartialAgreeVerify checks the contribution of the party whose key t_i * G is Ti
+func PartialAgreeVerify(curve *curves.Curve, W curves.Point, Ti curves.Point, agreeProof *chaumpedersen.Proof, agreeProofSessionId chaumpedersen.SessionId) error {
+	if agreeProof == nil || agreeProof.Statement1 == nil || agreeProof.Statement2 == nil {
+		return fmt.Errorf("missing partial agreement")
+	}
+	if err := chaumpedersen.Verify(agreeProof, curve, nil, W, agreeProofSessionId); err != nil {
+		return err
+	}
+	if !agreeProof.Statement1.Equal(Ti) {
+		return fmt.Errorf("partial agreement is not bound to the shares of Q and R")
+	}
+	return nil
+}
+
+// Combine adds up the verified contributions V_i and removes W, which turns x into the standard key d = x - 1
+func Combine(W curves.Point, Vs []curves.Point) (curves.Point, error) {
+	if len(Vs) == 0 {
+		return nil, fmt.Errorf("no partial agreements")
+	}
+	V := Vs[0]
+	for _, Vi := range Vs[1:] {
+		V = V.Add(Vi)
+	}
+	V = V.Sub(W)
+	if V.IsIdentity() {
+		return nil, fmt.Errorf("key exchange failed")
+	}
+	return V, nil
+}
@@ -3,15 +3,21 @@ package lnr18
 import (
 	"github.com/coinbase/kryptology/pkg/core"
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/ot/base/simplest"
+	"github.com/coinbase/kryptology/pkg/ot/extension/kos"
+	"github.com/coinbase/kryptology/pkg/ot/ottest"
 	"github.com/coinbase/kryptology/pkg/paillier"
+	mta_ot "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/ot"
 	mta_paillier "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/paillier"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
 
 func BenchmarkDKGPaillier(b *testing.B) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(50))
+	require.NoError(b, err)
 	str := "test message test message test message test message test message test message test message test message test message test message "
 	scheme.message = []byte(str)
 
@@ -37,7 +43,8 @@ func BenchmarkDKGPaillier(b *testing.B) {
 
 func BenchmarkDSPaillier(b *testing.B) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(50))
+	require.NoError(b, err)
 	str := "test message test message test message test message test message test message test message test message test message test message "
 	scheme.message = []byte(str)
 
@@ -53,7 +60,7 @@ func BenchmarkDSPaillier(b *testing.B) {
 	scheme.mtaSender = sender
 	scheme.mtaReceiver = receiver
 
-	err := scheme.DKGPhase1()
+	err = scheme.DKGPhase1()
 	require.NoError(b, err, "failed in Phase 1 of DKG")
 
 	b.ResetTimer()
@@ -84,3 +91,30 @@ func BenchmarkDSPaillier(b *testing.B) {
 		require.NoError(b, err, "failed in Phase 8 of DS")
 	}
 }
+
+func TestSchemeOT(t *testing.T) {
+	curveInit := curves.K256()
+	_, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit, []uint32{3, 3})
+	require.Error(t, err)
+
+	scheme, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit, []uint32{3, 8, 21})
+	require.NoError(t, err)
+	scheme.message = []byte("test message")
+
+	uniqueSessionId := [simplest.DigestSize]byte{}
+	baseOtSenderOutput, baseOtReceiverOutput, err := ottest.RunSimplestOT(curveInit, kos.Kappa, uniqueSessionId)
+	require.NoError(t, err)
+	scheme.mtaSender, err = mta_ot.NewSender(baseOtReceiverOutput, curveInit, uniqueSessionId)
+	require.NoError(t, err)
+	scheme.mtaReceiver, err = mta_ot.NewReceiver(baseOtSenderOutput, curveInit, uniqueSessionId)
+	require.NoError(t, err)
+
+	require.NoError(t, scheme.DKGPhase1(), "failed in Phase 1 of DKG")
+	phases := []func() error{
+		scheme.DSPhase1, scheme.DSPhase2, scheme.DSPhase3, scheme.DSPhase4,
+		scheme.DSPhase5, scheme.DSPhase6, scheme.DSPhase7, scheme.DSPhase8,
+	}
+	for i, phase := range phases {
+		require.NoError(t, phase(), "failed in Phase %d of DS", i+1)
+	}
+}
@@ -0,0 +1,145 @@
+package participant
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/abort"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/dkg"
+	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+)
+
+// DKGPhase1Commitments is broadcast by every party in the first round of the DKG
+type DKGPhase1Commitments struct {
+	QCommitment schnorr.Commitment
+	TCommitment schnorr.Commitment
+}
+
+// DKGPhase1Proofs opens the commitments of the first round
+type DKGPhase1Proofs struct {
+	QProof *schnorr.Proof
+	TProof *schnorr.Proof
+}
+
+const (
+	dkgPhase1Commit = iota
+	dkgPhase1Decommit
+	dkgPhase1Finalize
+)
+
+// labels of the DKG proofs, which separate their session ids
+const (
+	dkgQLabel = "DKG Q"
+	dkgTLabel = "DKG T"
+)
+
+type dkgState struct {
+	round int
+
+	QProof       *schnorr.Proof
+	TProof       *schnorr.Proof
+	QCommitments map[uint32]schnorr.Commitment
+	TCommitments map[uint32]schnorr.Commitment
+}
+
+func (p *Participant[A, B]) dkgRound(round int) error {
+	if round == dkgPhase1Commit {
+		if p.dkg != nil || p.Q != nil {
+			return fmt.Errorf("DKG has already been started")
+		}
+		p.dkg = &dkgState{}
+		return nil
+	}
+	if p.dkg == nil || p.dkg.round != round {
+		return fmt.Errorf("DKG round %d called out of order", round)
+	}
+	return nil
+}
+
+// DKGPhase1Commit samples the ECDSA key share x_i and the ElGamal key share d_i and commits to the proofs of knowledge
+// of Q_i = x_i * P and T_i = d_i * P. The output is broadcast.
+func (p *Participant[A, B]) DKGPhase1Commit() (*DKGPhase1Commitments, error) {
+	if err := p.dkgRound(dkgPhase1Commit); err != nil {
+		return nil, err
+	}
+	x, QProof, QCommitment, _, err := dkg.PkComProve(p.curve, p.proofSessionId(p.id, dkgQLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "proving knowledge of the key share")
+	}
+	d, TProof, TCommitment, _, err := dkg.PkComProve(p.curve, p.proofSessionId(p.id, dkgTLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "proving knowledge of the encryption key share")
+	}
+	p.x, p.d = x, d
+	p.dkg.QProof, p.dkg.TProof = QProof, TProof
+	p.dkg.round = dkgPhase1Decommit
+	return &DKGPhase1Commitments{QCommitment: QCommitment, TCommitment: TCommitment}, nil
+}
+
+// DKGPhase1Decommit stores the peers' commitments and opens our own. The output is broadcast.
+func (p *Participant[A, B]) DKGPhase1Decommit(commitments map[uint32]*DKGPhase1Commitments) (*DKGPhase1Proofs, error) {
+	if err := p.dkgRound(dkgPhase1Decommit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, commitments); err != nil {
+		return nil, err
+	}
+	p.dkg.QCommitments = make(map[uint32]schnorr.Commitment, len(p.peers))
+	p.dkg.TCommitments = make(map[uint32]schnorr.Commitment, len(p.peers))
+	for peer, commitment := range commitments {
+		if commitment == nil {
+			return nil, fmt.Errorf("missing commitments of party %d", peer)
+		}
+		p.dkg.QCommitments[peer] = commitment.QCommitment
+		p.dkg.TCommitments[peer] = commitment.TCommitment
+	}
+	p.dkg.round = dkgPhase1Finalize
+	return &DKGPhase1Proofs{QProof: p.dkg.QProof, TProof: p.dkg.TProof}, nil
+}
+
+// DKGPhase1Finalize verifies the opened proofs and computes Q and T, which completes the DKG
+func (p *Participant[A, B]) DKGPhase1Finalize(proofs map[uint32]*DKGPhase1Proofs) (*DKGOutput, error) {
+	if err := p.dkgRound(dkgPhase1Finalize); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, proofs); err != nil {
+		return nil, err
+	}
+	QProofs := map[uint32]*schnorr.Proof{p.id: p.dkg.QProof}
+	TProofs := map[uint32]*schnorr.Proof{p.id: p.dkg.TProof}
+	Q, T := p.dkg.QProof.Statement, p.dkg.TProof.Statement
+	for peer, proof := range proofs {
+		if proof == nil || proof.QProof == nil || proof.TProof == nil {
+			return nil, fmt.Errorf("missing proofs of party %d", peer)
+		}
+		QSessionId := p.proofSessionId(peer, dkgQLabel)
+		if err := dkg.PkDeComVerify(p.curve, proof.QProof, p.dkg.QCommitments[peer], QSessionId); err != nil {
+			return nil, abort.New(peer, "DKGPhase1Finalize", &abort.Evidence{
+				Check:      abort.DkgPkDeCom,
+				Proof:      proof.QProof,
+				Commitment: p.dkg.QCommitments[peer],
+				SessionId:  QSessionId,
+			}, errors.Wrap(err, "verifying key share"))
+		}
+		TSessionId := p.proofSessionId(peer, dkgTLabel)
+		if err := dkg.PkDeComVerify(p.curve, proof.TProof, p.dkg.TCommitments[peer], TSessionId); err != nil {
+			return nil, abort.New(peer, "DKGPhase1Finalize", &abort.Evidence{
+				Check:      abort.DkgPkDeCom,
+				Proof:      proof.TProof,
+				Commitment: p.dkg.TCommitments[peer],
+				SessionId:  TSessionId,
+			}, errors.Wrap(err, "verifying encryption key share"))
+		}
+		QProofs[peer], TProofs[peer] = proof.QProof, proof.TProof
+		Q = Q.Add(proof.QProof.Statement)
+		T = T.Add(proof.TProof.Statement)
+	}
+	if Q.IsIdentity() {
+		return nil, fmt.Errorf("the public key is the identity")
+	}
+	p.QProofs, p.TProofs = QProofs, TProofs
+	p.Q, p.T = Q, T
+	p.dkg = nil
+	return p.DKGOutput(), nil
+}
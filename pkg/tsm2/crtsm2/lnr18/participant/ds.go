@@ -0,0 +1,783 @@
+package participant
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/abort"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/dkg"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/ds"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+	"github.com/coinbase/kryptology/pkg/zkp/reg"
+	"github.com/coinbase/kryptology/pkg/zkp/rre"
+	"github.com/coinbase/kryptology/pkg/zkp/rspdl"
+	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+)
+
+// DSPhase2Relations re-randomizes the encryption of gamma with the key share x_i and with the nonce share k_i
+type DSPhase2Relations struct {
+	XGammaProof *rspdl.Proof
+	KGammaProof *rspdl.Proof
+}
+
+// DSPhase5Commitments commits to the re-randomizations of the encryptions (U, V) of x*gamma - sigma and
+// (A, B) of k*gamma - delta
+type DSPhase5Commitments struct {
+	UVCommitment rre.Commitment
+	ABCommitment rre.Commitment
+}
+
+// DSPhase5Rerandomizations opens the commitments to the re-randomizations
+type DSPhase5Rerandomizations struct {
+	UVProof *rre.Proof
+	ABProof *rre.Proof
+}
+
+// DSPhase5DDHCommitments commits to the partial decryptions of (U', V') and (A', B')
+type DSPhase5DDHCommitments struct {
+	UVCommitment chaumpedersen.Commitment
+	ABCommitment chaumpedersen.Commitment
+}
+
+// DSPhase5DDHProofs opens the commitments to the partial decryptions
+type DSPhase5DDHProofs struct {
+	UVProof *chaumpedersen.Proof
+	ABProof *chaumpedersen.Proof
+}
+
+// DSPhase6DeltaReveal reveals delta_i together with the proof that it is the plaintext of the party's delta ciphertext
+type DSPhase6DeltaReveal struct {
+	Delta         curves.Scalar
+	DeltaDDHProof *chaumpedersen.Proof
+}
+
+const (
+	dsPhase1Commit = iota
+	dsPhase1Decommit
+	dsPhase2Encrypt
+	dsPhase2Relate
+	dsPhase3MtAInit
+	dsPhase3MtAUpdate
+	dsPhase3MtAMultiply
+	dsPhase4MtAInit
+	dsPhase4MtAUpdate
+	dsPhase4MtAMultiply
+	dsPhase5Rerandomize
+	dsPhase5RerandomizeDecommit
+	dsPhase5DDHCommit
+	dsPhase5DDHDecommit
+	dsPhase6RevealDelta
+	dsPhase6PartialSign
+	dsPhase6Finalize
+)
+
+// labels of the DS proofs, which separate their session ids
+const (
+	dsNonceLabel         = "DS nonce"
+	dsGammaLabel         = "DS gamma"
+	dsXGammaLabel        = "DS x*gamma"
+	dsKGammaLabel        = "DS k*gamma"
+	dsSigmaLabel         = "DS sigma"
+	dsDeltaLabel         = "DS delta"
+	dsUVRerandomizeLabel = "DS (U, V) re-randomization"
+	dsABRerandomizeLabel = "DS (A, B) re-randomization"
+	dsUVDecryptLabel     = "DS (U', V') partial decryption"
+	dsABDecryptLabel     = "DS (A', B') partial decryption"
+	dsDeltaRevealLabel   = "DS delta reveal"
+)
+
+type signState[A any, B any] struct {
+	round     int
+	digest    []byte
+	sessionId []byte
+
+	k            curves.Scalar
+	kProof       *schnorr.Proof
+	kCommitment  schnorr.Commitment
+	kCommitments map[uint32]schnorr.Commitment
+	kProofs      map[uint32]*schnorr.Proof
+	R            curves.Point
+	r            curves.Scalar
+	v            int
+
+	gamma         curves.Scalar
+	gammaRegProof *reg.Proof
+	UGamma        curves.Point
+	VGamma        curves.Point
+	relations     *DSPhase2Relations
+	UXGamma       curves.Point
+	VXGamma       curves.Point
+	AKGamma       curves.Point
+	BKGamma       curves.Point
+
+	alphas         map[uint32]curves.Scalar
+	sigma          curves.Scalar
+	sigmaEGProof   *reg.Proof
+	U              curves.Point
+	V              curves.Point
+	mus            map[uint32]curves.Scalar
+	delta          curves.Scalar
+	rDelta         curves.Scalar
+	deltaRegProof  *reg.Proof
+	deltaRegProofs map[uint32]*reg.Proof
+	A              curves.Point
+	B              curves.Point
+
+	rerandomizations *DSPhase5Rerandomizations
+	reCommitments    map[uint32]*DSPhase5Commitments
+	UPrime           curves.Point
+	VPrime           curves.Point
+	APrime           curves.Point
+	BPrime           curves.Point
+
+	ddhProofs      *DSPhase5DDHProofs
+	ddhCommitments map[uint32]*DSPhase5DDHCommitments
+
+	s curves.Scalar
+}
+
+func (p *Participant[A, B]) signRound(round int) error {
+	if round == dsPhase1Commit {
+		if p.DKGOutput() == nil {
+			return fmt.Errorf("DKG has not completed")
+		}
+		p.sign = &signState[A, B]{}
+		return nil
+	}
+	if p.sign == nil || p.sign.round != round {
+		return fmt.Errorf("DS round %d called out of order", round)
+	}
+	return nil
+}
+
+// signProofSessionId returns the session id of the DS proof labeled label that the party sender produces
+func (p *Participant[A, B]) signProofSessionId(sender uint32, label string) []byte {
+	return parties.ProofSessionId(p.sign.sessionId, sender, label)
+}
+
+// DSPhase1Commit starts signing digest, the hash of the message computed by the caller: it samples the nonce share k_i
+// and commits to the proof of knowledge of R_i = k_i * P. Starting a new signature abandons any signature in progress.
+// The output is broadcast.
+func (p *Participant[A, B]) DSPhase1Commit(digest []byte) (schnorr.Commitment, error) {
+	if len(digest) == 0 {
+		return nil, fmt.Errorf("digest is empty")
+	}
+	if err := p.signRound(dsPhase1Commit); err != nil {
+		return nil, err
+	}
+	k, kProof, kCommitment, _, err := ds.NonceComProve(p.curve, p.proofSessionId(p.id, dsNonceLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "proving knowledge of the nonce share")
+	}
+	p.sign.digest = append([]byte{}, digest...)
+	p.sign.k, p.sign.kProof, p.sign.kCommitment = k, kProof, kCommitment
+	p.sign.round = dsPhase1Decommit
+	return kCommitment, nil
+}
+
+// DSPhase1Decommit stores the peers' commitments and opens our own. The output is broadcast.
+func (p *Participant[A, B]) DSPhase1Decommit(kCommitments map[uint32]schnorr.Commitment) (*schnorr.Proof, error) {
+	if err := p.signRound(dsPhase1Decommit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, kCommitments); err != nil {
+		return nil, err
+	}
+	p.sign.kCommitments = kCommitments
+	contributions := map[uint32][]byte{p.id: p.sign.kCommitment}
+	for peer, commitment := range kCommitments {
+		contributions[peer] = commitment
+	}
+	// the commitments to the nonce proofs are fresh for every signature, hence so is the session id derived from them
+	p.sign.sessionId = parties.SubSessionId(p.sessionId, "LNR18 DS", contributions)
+	p.sign.round = dsPhase2Encrypt
+	return p.sign.kProof, nil
+}
+
+// DSPhase2Encrypt verifies the opened nonce proofs, computes R and r = x(R) mod n, then samples gamma_i and encrypts
+// it under T. The output is broadcast.
+func (p *Participant[A, B]) DSPhase2Encrypt(kProofs map[uint32]*schnorr.Proof) (*reg.Proof, error) {
+	if err := p.signRound(dsPhase2Encrypt); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, kProofs); err != nil {
+		return nil, err
+	}
+	p.sign.R = p.sign.kProof.Statement
+	for peer, proof := range kProofs {
+		if proof == nil {
+			return nil, fmt.Errorf("missing nonce proof of party %d", peer)
+		}
+		sessionId := p.proofSessionId(peer, dsNonceLabel)
+		if err := ds.NonceDeComVerify(p.curve, proof, p.sign.kCommitments[peer], sessionId); err != nil {
+			return nil, abort.New(peer, "DSPhase2Encrypt", &abort.Evidence{
+				Check:      abort.DsNonceDeCom,
+				Proof:      proof,
+				Commitment: p.sign.kCommitments[peer],
+				SessionId:  sessionId,
+			}, errors.Wrap(err, "verifying nonce share"))
+		}
+		p.sign.R = p.sign.R.Add(proof.Statement)
+	}
+	p.sign.kProofs = kProofs
+	if p.sign.R.IsIdentity() {
+		return nil, fmt.Errorf("R is the identity")
+	}
+	r, v, err := p.signatureR(p.sign.R)
+	if err != nil {
+		return nil, err
+	}
+	p.sign.r, p.sign.v = r, v
+
+	gamma, _, gammaRegProof, _, err := dkg.REGProve(p.curve, p.T, p.signProofSessionId(p.id, dsGammaLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypting gamma")
+	}
+	p.sign.gamma = gamma
+	p.sign.gammaRegProof = gammaRegProof
+	p.sign.round = dsPhase2Relate
+	return gammaRegProof, nil
+}
+
+// DSPhase2Relate verifies the peers' encryptions of gamma_j, computes the encryption of gamma = sum(gamma_j) and
+// re-randomizes it with x_i and with k_i. The output is broadcast.
+func (p *Participant[A, B]) DSPhase2Relate(gammaRegProofs map[uint32]*reg.Proof) (*DSPhase2Relations, error) {
+	if err := p.signRound(dsPhase2Relate); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, gammaRegProofs); err != nil {
+		return nil, err
+	}
+	for peer, proof := range gammaRegProofs {
+		if proof == nil {
+			return nil, fmt.Errorf("missing gamma encryption of party %d", peer)
+		}
+		sessionId := p.signProofSessionId(peer, dsGammaLabel)
+		if err := dkg.REGVerify(p.curve, p.T, proof, sessionId); err != nil {
+			return nil, abort.New(peer, "DSPhase2Relate", &abort.Evidence{
+				Check:     abort.DkgREG,
+				Proof:     proof,
+				SessionId: sessionId,
+				Inputs:    []curves.Point{p.T},
+			}, errors.Wrap(err, "verifying gamma encryption"))
+		}
+	}
+	p.sign.UGamma, p.sign.VGamma = sumRegProofs(p.sign.gammaRegProof, gammaRegProofs)
+
+	xGammaProof, _, err := dkg.RSPDLProve(p.curve, p.T, p.sign.UGamma, p.sign.VGamma, p.QProofs[p.id].Statement, p.x, p.signProofSessionId(p.id, dsXGammaLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "re-randomizing the encryption of gamma with x")
+	}
+	kGammaProof, _, err := ds.SRanCTGammaProve(p.curve, p.T, p.sign.UGamma, p.sign.VGamma, p.sign.kProof.Statement, p.sign.k, p.signProofSessionId(p.id, dsKGammaLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "re-randomizing the encryption of gamma with k")
+	}
+	p.sign.relations = &DSPhase2Relations{XGammaProof: xGammaProof, KGammaProof: kGammaProof}
+	p.sign.round = dsPhase3MtAInit
+	return p.sign.relations, nil
+}
+
+// DSPhase3MtAInit verifies the peers' re-randomizations, computes the encryptions of x*gamma and k*gamma and starts
+// one MtA per peer on x_i. The output is point-to-point, keyed by the recipient.
+func (p *Participant[A, B]) DSPhase3MtAInit(relations map[uint32]*DSPhase2Relations) (map[uint32]A, error) {
+	if err := p.signRound(dsPhase3MtAInit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, relations); err != nil {
+		return nil, err
+	}
+	p.sign.UXGamma, p.sign.VXGamma = p.sign.relations.XGammaProof.APrime, p.sign.relations.XGammaProof.BPrime
+	p.sign.AKGamma, p.sign.BKGamma = p.sign.relations.KGammaProof.APrime, p.sign.relations.KGammaProof.BPrime
+	for peer, relation := range relations {
+		if relation == nil || relation.XGammaProof == nil || relation.KGammaProof == nil {
+			return nil, fmt.Errorf("missing re-randomizations of party %d", peer)
+		}
+		Qi := p.QProofs[peer].Statement
+		xGammaSessionId := p.signProofSessionId(peer, dsXGammaLabel)
+		if err := dkg.RSPDLVerify(p.curve, p.T, relation.XGammaProof, p.sign.UGamma, p.sign.VGamma, Qi, xGammaSessionId); err != nil {
+			return nil, abort.New(peer, "DSPhase3MtAInit", &abort.Evidence{
+				Check:     abort.DkgRSPDL,
+				Proof:     relation.XGammaProof,
+				SessionId: xGammaSessionId,
+				Inputs:    []curves.Point{p.T, p.sign.UGamma, p.sign.VGamma, Qi},
+			}, errors.Wrap(err, "verifying re-randomization with x"))
+		}
+		Ri := p.sign.kProofs[peer].Statement
+		kGammaSessionId := p.signProofSessionId(peer, dsKGammaLabel)
+		if err := ds.SRanCTGammaVerify(p.curve, p.T, relation.KGammaProof, p.sign.UGamma, p.sign.VGamma, Ri, kGammaSessionId); err != nil {
+			return nil, abort.New(peer, "DSPhase3MtAInit", &abort.Evidence{
+				Check:     abort.DsSRanCTGamma,
+				Proof:     relation.KGammaProof,
+				SessionId: kGammaSessionId,
+				Inputs:    []curves.Point{p.T, p.sign.UGamma, p.sign.VGamma, Ri},
+			}, errors.Wrap(err, "verifying re-randomization with k"))
+		}
+		p.sign.UXGamma = p.sign.UXGamma.Add(relation.XGammaProof.APrime)
+		p.sign.VXGamma = p.sign.VXGamma.Add(relation.XGammaProof.BPrime)
+		p.sign.AKGamma = p.sign.AKGamma.Add(relation.KGammaProof.APrime)
+		p.sign.BKGamma = p.sign.BKGamma.Add(relation.KGammaProof.BPrime)
+	}
+
+	output := make(map[uint32]A, len(p.peers))
+	for _, peer := range p.peers {
+		output[peer] = p.mtaReceivers[peer].Init(p.x)
+	}
+	p.sign.round = dsPhase3MtAUpdate
+	return output, nil
+}
+
+// DSPhase3MtAUpdate answers every peer's MtA on gamma_i. The output is point-to-point, keyed by the recipient.
+func (p *Participant[A, B]) DSPhase3MtAUpdate(mtaInits map[uint32]A) (map[uint32]B, error) {
+	if err := p.signRound(dsPhase3MtAUpdate); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, mtaInits); err != nil {
+		return nil, err
+	}
+	p.sign.alphas = make(map[uint32]curves.Scalar, len(p.peers))
+	output := make(map[uint32]B, len(p.peers))
+	for _, peer := range p.peers {
+		p.sign.alphas[peer], output[peer] = p.mtaSenders[peer].Update(p.sign.gamma, mtaInits[peer])
+	}
+	p.sign.round = dsPhase3MtAMultiply
+	return output, nil
+}
+
+// DSPhase3MtAMultiply completes the MtAs, computes the additive share sigma_i of x*gamma and encrypts it under T.
+// The output is broadcast.
+func (p *Participant[A, B]) DSPhase3MtAMultiply(mtaUpdates map[uint32]B) (*reg.Proof, error) {
+	if err := p.signRound(dsPhase3MtAMultiply); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, mtaUpdates); err != nil {
+		return nil, err
+	}
+	sigma := p.sign.gamma.Mul(p.x)
+	for _, peer := range p.peers {
+		beta := p.mtaReceivers[peer].Multiply(mtaUpdates[peer])
+		sigma = sigma.Add(p.sign.alphas[peer]).Add(beta)
+	}
+	p.sign.sigma = sigma
+
+	sigmaEGProof, _, err := ds.DeltaEGProve(p.curve, p.T, sigma, p.signProofSessionId(p.id, dsSigmaLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypting sigma")
+	}
+	p.sign.sigmaEGProof = sigmaEGProof
+	p.sign.round = dsPhase4MtAInit
+	return sigmaEGProof, nil
+}
+
+// DSPhase4MtAInit verifies the peers' encryptions of sigma_j, computes the encryption (U, V) of x*gamma - sigma and
+// starts one MtA per peer on k_i. The output is point-to-point, keyed by the recipient.
+func (p *Participant[A, B]) DSPhase4MtAInit(sigmaEGProofs map[uint32]*reg.Proof) (map[uint32]A, error) {
+	if err := p.signRound(dsPhase4MtAInit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, sigmaEGProofs); err != nil {
+		return nil, err
+	}
+	for peer, proof := range sigmaEGProofs {
+		if proof == nil {
+			return nil, fmt.Errorf("missing sigma encryption of party %d", peer)
+		}
+		sessionId := p.signProofSessionId(peer, dsSigmaLabel)
+		if err := ds.DeltaEGVerify(p.curve, p.T, proof, sessionId); err != nil {
+			return nil, abort.New(peer, "DSPhase4MtAInit", &abort.Evidence{
+				Check:     abort.DsDeltaEG,
+				Proof:     proof,
+				SessionId: sessionId,
+				Inputs:    []curves.Point{p.T},
+			}, errors.Wrap(err, "verifying sigma encryption"))
+		}
+	}
+	USigma, VSigma := sumRegProofs(p.sign.sigmaEGProof, sigmaEGProofs)
+	p.sign.U = p.sign.UXGamma.Sub(USigma)
+	p.sign.V = p.sign.VXGamma.Sub(VSigma)
+
+	output := make(map[uint32]A, len(p.peers))
+	for _, peer := range p.peers {
+		output[peer] = p.mtaReceivers[peer].Init(p.sign.k)
+	}
+	p.sign.round = dsPhase4MtAUpdate
+	return output, nil
+}
+
+// DSPhase4MtAUpdate answers every peer's MtA on gamma_i. The output is point-to-point, keyed by the recipient.
+func (p *Participant[A, B]) DSPhase4MtAUpdate(mtaInits map[uint32]A) (map[uint32]B, error) {
+	if err := p.signRound(dsPhase4MtAUpdate); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, mtaInits); err != nil {
+		return nil, err
+	}
+	p.sign.mus = make(map[uint32]curves.Scalar, len(p.peers))
+	output := make(map[uint32]B, len(p.peers))
+	for _, peer := range p.peers {
+		p.sign.mus[peer], output[peer] = p.mtaSenders[peer].Update(p.sign.gamma, mtaInits[peer])
+	}
+	p.sign.round = dsPhase4MtAMultiply
+	return output, nil
+}
+
+// DSPhase4MtAMultiply completes the MtAs, computes the additive share delta_i of k*gamma and encrypts it under T.
+// The output is broadcast.
+func (p *Participant[A, B]) DSPhase4MtAMultiply(mtaUpdates map[uint32]B) (*reg.Proof, error) {
+	if err := p.signRound(dsPhase4MtAMultiply); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, mtaUpdates); err != nil {
+		return nil, err
+	}
+	delta := p.sign.gamma.Mul(p.sign.k)
+	for _, peer := range p.peers {
+		nu := p.mtaReceivers[peer].Multiply(mtaUpdates[peer])
+		delta = delta.Add(p.sign.mus[peer]).Add(nu)
+	}
+	p.sign.delta = delta
+
+	rDelta, deltaRegProof, _, err := dkg.SigmaREGProve(p.curve, p.T, delta, p.signProofSessionId(p.id, dsDeltaLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypting delta")
+	}
+	p.sign.rDelta = rDelta
+	p.sign.deltaRegProof = deltaRegProof
+	p.sign.round = dsPhase5Rerandomize
+	return deltaRegProof, nil
+}
+
+// DSPhase5Rerandomize verifies the peers' encryptions of delta_j, computes the encryption (A, B) of k*gamma - delta
+// and commits to re-randomizations of (U, V) and (A, B). The output is broadcast.
+func (p *Participant[A, B]) DSPhase5Rerandomize(deltaRegProofs map[uint32]*reg.Proof) (*DSPhase5Commitments, error) {
+	if err := p.signRound(dsPhase5Rerandomize); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, deltaRegProofs); err != nil {
+		return nil, err
+	}
+	for peer, proof := range deltaRegProofs {
+		if proof == nil {
+			return nil, fmt.Errorf("missing delta encryption of party %d", peer)
+		}
+		sessionId := p.signProofSessionId(peer, dsDeltaLabel)
+		if err := dkg.SigmaREGVerify(p.curve, p.T, proof, sessionId); err != nil {
+			return nil, abort.New(peer, "DSPhase5Rerandomize", &abort.Evidence{
+				Check:     abort.DkgSigmaREG,
+				Proof:     proof,
+				SessionId: sessionId,
+				Inputs:    []curves.Point{p.T},
+			}, errors.Wrap(err, "verifying delta encryption"))
+		}
+	}
+	p.sign.deltaRegProofs = deltaRegProofs
+	ADelta, BDelta := sumRegProofs(p.sign.deltaRegProof, deltaRegProofs)
+	p.sign.A = p.sign.AKGamma.Sub(ADelta)
+	p.sign.B = p.sign.BKGamma.Sub(BDelta)
+
+	uvProof, uvCommitment, _, err := dkg.RREComProve(p.curve, p.T, p.sign.U, p.sign.V, p.signProofSessionId(p.id, dsUVRerandomizeLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "re-randomizing (U, V)")
+	}
+	abProof, abCommitment, _, err := ds.REComProve(p.curve, p.T, p.sign.A, p.sign.B, p.signProofSessionId(p.id, dsABRerandomizeLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "re-randomizing (A, B)")
+	}
+	p.sign.rerandomizations = &DSPhase5Rerandomizations{UVProof: uvProof, ABProof: abProof}
+	p.sign.round = dsPhase5RerandomizeDecommit
+	return &DSPhase5Commitments{UVCommitment: uvCommitment, ABCommitment: abCommitment}, nil
+}
+
+// DSPhase5RerandomizeDecommit stores the peers' commitments and opens our re-randomizations. The output is broadcast.
+func (p *Participant[A, B]) DSPhase5RerandomizeDecommit(reCommitments map[uint32]*DSPhase5Commitments) (*DSPhase5Rerandomizations, error) {
+	if err := p.signRound(dsPhase5RerandomizeDecommit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, reCommitments); err != nil {
+		return nil, err
+	}
+	for peer, commitment := range reCommitments {
+		if commitment == nil {
+			return nil, fmt.Errorf("missing commitments of party %d", peer)
+		}
+	}
+	p.sign.reCommitments = reCommitments
+	p.sign.round = dsPhase5DDHCommit
+	return p.sign.rerandomizations, nil
+}
+
+// DSPhase5DDHCommit verifies the peers' re-randomizations, computes (U', V') and (A', B') and commits to the proofs
+// that U'_i = d_i * U' and A'_i = d_i * A' are our partial decryptions. The output is broadcast.
+func (p *Participant[A, B]) DSPhase5DDHCommit(rerandomizations map[uint32]*DSPhase5Rerandomizations) (*DSPhase5DDHCommitments, error) {
+	if err := p.signRound(dsPhase5DDHCommit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, rerandomizations); err != nil {
+		return nil, err
+	}
+	own := p.sign.rerandomizations
+	p.sign.UPrime, p.sign.VPrime = own.UVProof.APrime, own.UVProof.BPrime
+	p.sign.APrime, p.sign.BPrime = own.ABProof.APrime, own.ABProof.BPrime
+	for peer, rerandomization := range rerandomizations {
+		if rerandomization == nil || rerandomization.UVProof == nil || rerandomization.ABProof == nil {
+			return nil, fmt.Errorf("missing re-randomizations of party %d", peer)
+		}
+		commitments := p.sign.reCommitments[peer]
+		uvSessionId := p.signProofSessionId(peer, dsUVRerandomizeLabel)
+		if err := dkg.RREDeComVerify(p.curve, rerandomization.UVProof, commitments.UVCommitment, uvSessionId, p.T, p.sign.U, p.sign.V); err != nil {
+			return nil, abort.New(peer, "DSPhase5DDHCommit", &abort.Evidence{
+				Check:      abort.DkgRREDeCom,
+				Proof:      rerandomization.UVProof,
+				Commitment: commitments.UVCommitment,
+				SessionId:  uvSessionId,
+				Inputs:     []curves.Point{p.T, p.sign.U, p.sign.V},
+			}, errors.Wrap(err, "verifying re-randomization of (U, V)"))
+		}
+		abSessionId := p.signProofSessionId(peer, dsABRerandomizeLabel)
+		if err := ds.REDeComVerify(p.curve, rerandomization.ABProof, commitments.ABCommitment, abSessionId, p.T, p.sign.A, p.sign.B); err != nil {
+			return nil, abort.New(peer, "DSPhase5DDHCommit", &abort.Evidence{
+				Check:      abort.DsREDeCom,
+				Proof:      rerandomization.ABProof,
+				Commitment: commitments.ABCommitment,
+				SessionId:  abSessionId,
+				Inputs:     []curves.Point{p.T, p.sign.A, p.sign.B},
+			}, errors.Wrap(err, "verifying re-randomization of (A, B)"))
+		}
+		p.sign.UPrime = p.sign.UPrime.Add(rerandomization.UVProof.APrime)
+		p.sign.VPrime = p.sign.VPrime.Add(rerandomization.UVProof.BPrime)
+		p.sign.APrime = p.sign.APrime.Add(rerandomization.ABProof.APrime)
+		p.sign.BPrime = p.sign.BPrime.Add(rerandomization.ABProof.BPrime)
+	}
+
+	Ti := p.TProofs[p.id].Statement
+	uvProof, uvCommitment, _, err := dkg.DDHComProve(p.curve, p.P, p.sign.UPrime, Ti, p.sign.UPrime.Mul(p.d), p.d, p.signProofSessionId(p.id, dsUVDecryptLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "proving partial decryption of (U', V')")
+	}
+	abProof, abCommitment, _, err := ds.DDHComProve(p.curve, p.P, p.sign.APrime, Ti, p.sign.APrime.Mul(p.d), p.d, p.signProofSessionId(p.id, dsABDecryptLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "proving partial decryption of (A', B')")
+	}
+	p.sign.ddhProofs = &DSPhase5DDHProofs{UVProof: uvProof, ABProof: abProof}
+	p.sign.round = dsPhase5DDHDecommit
+	return &DSPhase5DDHCommitments{UVCommitment: uvCommitment, ABCommitment: abCommitment}, nil
+}
+
+// DSPhase5DDHDecommit stores the peers' commitments and opens our partial decryptions. The output is broadcast.
+func (p *Participant[A, B]) DSPhase5DDHDecommit(ddhCommitments map[uint32]*DSPhase5DDHCommitments) (*DSPhase5DDHProofs, error) {
+	if err := p.signRound(dsPhase5DDHDecommit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, ddhCommitments); err != nil {
+		return nil, err
+	}
+	for peer, commitment := range ddhCommitments {
+		if commitment == nil {
+			return nil, fmt.Errorf("missing commitments of party %d", peer)
+		}
+	}
+	p.sign.ddhCommitments = ddhCommitments
+	p.sign.round = dsPhase6RevealDelta
+	return p.sign.ddhProofs, nil
+}
+
+// DSPhase6RevealDelta checks that (U', V') and (A', B') decrypt to zero, i.e. that sum(sigma_j) = x*gamma and
+// sum(delta_j) = k*gamma, and then reveals delta_i with a proof that it is the plaintext of our delta ciphertext.
+// The output is broadcast.
+func (p *Participant[A, B]) DSPhase6RevealDelta(ddhProofs map[uint32]*DSPhase5DDHProofs) (*DSPhase6DeltaReveal, error) {
+	if err := p.signRound(dsPhase6RevealDelta); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, ddhProofs); err != nil {
+		return nil, err
+	}
+	sumUPrimes := p.sign.ddhProofs.UVProof.Statement2
+	sumAPrimes := p.sign.ddhProofs.ABProof.Statement2
+	for peer, proofs := range ddhProofs {
+		if proofs == nil || proofs.UVProof == nil || proofs.ABProof == nil {
+			return nil, fmt.Errorf("missing partial decryptions of party %d", peer)
+		}
+		commitments := p.sign.ddhCommitments[peer]
+		uvSessionId := p.signProofSessionId(peer, dsUVDecryptLabel)
+		if err := dkg.DDHDeComVerify(p.curve, proofs.UVProof, commitments.UVCommitment, uvSessionId, p.P, p.sign.UPrime); err != nil {
+			return nil, abort.New(peer, "DSPhase6RevealDelta", &abort.Evidence{
+				Check:      abort.DkgDDHDeCom,
+				Proof:      proofs.UVProof,
+				Commitment: commitments.UVCommitment,
+				SessionId:  uvSessionId,
+				Inputs:     []curves.Point{p.P, p.sign.UPrime},
+			}, errors.Wrap(err, "verifying partial decryption of (U', V')"))
+		}
+		abSessionId := p.signProofSessionId(peer, dsABDecryptLabel)
+		if err := ds.DDHDeComVerify(p.curve, proofs.ABProof, commitments.ABCommitment, abSessionId, p.P, p.sign.APrime); err != nil {
+			return nil, abort.New(peer, "DSPhase6RevealDelta", &abort.Evidence{
+				Check:      abort.DsDDHDeCom,
+				Proof:      proofs.ABProof,
+				Commitment: commitments.ABCommitment,
+				SessionId:  abSessionId,
+				Inputs:     []curves.Point{p.P, p.sign.APrime},
+			}, errors.Wrap(err, "verifying partial decryption of (A', B')"))
+		}
+		Ti := p.TProofs[peer].Statement
+		if err := dkg.DDHKeyVerify(proofs.UVProof, Ti); err != nil {
+			return nil, abort.New(peer, "DSPhase6RevealDelta", &abort.Evidence{
+				Check:      abort.DkgDDHKey,
+				Proof:      proofs.UVProof,
+				Commitment: commitments.UVCommitment,
+				SessionId:  uvSessionId,
+				Inputs:     []curves.Point{Ti},
+			}, err)
+		}
+		if err := ds.DDHKeyVerify(proofs.ABProof, Ti); err != nil {
+			return nil, abort.New(peer, "DSPhase6RevealDelta", &abort.Evidence{
+				Check:      abort.DsDDHKey,
+				Proof:      proofs.ABProof,
+				Commitment: commitments.ABCommitment,
+				SessionId:  abSessionId,
+				Inputs:     []curves.Point{Ti},
+			}, err)
+		}
+		sumUPrimes = sumUPrimes.Add(proofs.UVProof.Statement2)
+		sumAPrimes = sumAPrimes.Add(proofs.ABProof.Statement2)
+	}
+	if !sumUPrimes.Equal(p.sign.VPrime) {
+		return nil, fmt.Errorf("failed when verifying sum of U'_i")
+	}
+	if !sumAPrimes.Equal(p.sign.BPrime) {
+		return nil, fmt.Errorf("failed when verifying DDH relation")
+	}
+
+	BDeltaPrime := p.sign.deltaRegProof.B.Sub(p.P.Mul(p.sign.delta))
+	deltaDDHProof, _, err := dkg.SigmaDDHProve(p.curve, p.P, p.T, p.sign.deltaRegProof.A, BDeltaPrime, p.sign.rDelta, p.signProofSessionId(p.id, dsDeltaRevealLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "proving the encryption of delta")
+	}
+	p.sign.round = dsPhase6PartialSign
+	return &DSPhase6DeltaReveal{Delta: p.sign.delta, DeltaDDHProof: deltaDDHProof}, nil
+}
+
+// DSPhase6PartialSign verifies the revealed delta_j, computes delta = k*gamma and the partial signature
+// s_i = delta^-1 * (h * gamma_i + r * sigma_i), where h is the digest reduced as crypto/ecdsa does.
+// The output is broadcast.
+func (p *Participant[A, B]) DSPhase6PartialSign(reveals map[uint32]*DSPhase6DeltaReveal) (curves.Scalar, error) {
+	if err := p.signRound(dsPhase6PartialSign); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, reveals); err != nil {
+		return nil, err
+	}
+	delta := p.sign.delta
+	for peer, reveal := range reveals {
+		if reveal == nil || reveal.Delta == nil || reveal.DeltaDDHProof == nil {
+			return nil, fmt.Errorf("missing delta of party %d", peer)
+		}
+		deltaRegProof := p.sign.deltaRegProofs[peer]
+		sessionId := p.signProofSessionId(peer, dsDeltaRevealLabel)
+		if err := dkg.SigmaDDHVerify(p.curve, reveal.DeltaDDHProof, sessionId, p.P, p.T); err != nil {
+			return nil, abort.New(peer, "DSPhase6PartialSign", &abort.Evidence{
+				Check:     abort.DkgSigmaDDH,
+				Proof:     reveal.DeltaDDHProof,
+				SessionId: sessionId,
+				Inputs:    []curves.Point{p.P, p.T},
+			}, errors.Wrap(err, "verifying delta"))
+		}
+		DeltaI := p.P.Mul(reveal.Delta)
+		if err := dkg.SigmaRevealVerify(reveal.DeltaDDHProof, deltaRegProof.A, deltaRegProof.B, DeltaI); err != nil {
+			return nil, abort.New(peer, "DSPhase6PartialSign", &abort.Evidence{
+				Check:     abort.DkgSigmaRev,
+				Proof:     reveal.DeltaDDHProof,
+				SessionId: sessionId,
+				Inputs:    []curves.Point{deltaRegProof.A, deltaRegProof.B, DeltaI},
+			}, errors.Wrap(err, "verifying delta"))
+		}
+		delta = delta.Add(reveal.Delta)
+	}
+	deltaInvert, err := delta.Invert()
+	if err != nil {
+		return nil, fmt.Errorf("failed in computing the inverse of delta")
+	}
+
+	h, err := p.digestToScalar(p.sign.digest)
+	if err != nil {
+		return nil, err
+	}
+	s := deltaInvert.Mul(h.Mul(p.sign.gamma).Add(p.sign.r.Mul(p.sign.sigma)))
+	p.sign.s = s
+	p.sign.round = dsPhase6Finalize
+	return s, nil
+}
+
+// DSPhase6Finalize adds up the partial signatures, normalizes s to the lower half of the range and verifies the final
+// signature against the digest with crypto/ecdsa
+func (p *Participant[A, B]) DSPhase6Finalize(ss map[uint32]curves.Scalar) (*curves.EcdsaSignature, error) {
+	if err := p.signRound(dsPhase6Finalize); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p, ss); err != nil {
+		return nil, err
+	}
+	s := p.sign.s
+	for peer, si := range ss {
+		if si == nil {
+			return nil, fmt.Errorf("missing partial signature of party %d", peer)
+		}
+		s = s.Add(si)
+	}
+	if s.IsZero() {
+		return nil, fmt.Errorf("s is zero")
+	}
+
+	// Only produce low-S signatures, which secp256k1 verifiers require; negating s flips the parity of R's y-coordinate
+	v := p.sign.v
+	halfN := new(big.Int).Rsh(p.ec.Params().N, 1)
+	if s.BigInt().Cmp(halfN) == 1 {
+		s = s.Neg()
+		v ^= 1
+	}
+	signature := &curves.EcdsaSignature{V: v, R: p.sign.r.BigInt(), S: s.BigInt()}
+	if !ecdsa.Verify(p.PublicKey(), p.sign.digest, signature.R, signature.S) {
+		return nil, fmt.Errorf("signature is not valid")
+	}
+	p.sign = nil
+	return signature, nil
+}
+
+// signatureR computes r = x(R) mod n and the recovery id of R: bit 0 is the parity of its y-coordinate and bit 1 is
+// set if its x-coordinate is at least n
+func (p *Participant[A, B]) signatureR(R curves.Point) (curves.Scalar, int, error) {
+	compressed := R.ToAffineCompressed()
+	x := new(big.Int).SetBytes(compressed[1:])
+	v := int(compressed[0] & 1)
+	n := p.ec.Params().N
+	if x.Cmp(n) >= 0 {
+		v |= 2
+	}
+	r, err := p.curve.Scalar.SetBigInt(x.Mod(x, n))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed when computing x-coordinate of R")
+	}
+	if r.IsZero() {
+		return nil, 0, fmt.Errorf("r is zero")
+	}
+	return r, v, nil
+}
+
+// digestToScalar converts a digest to a scalar the same way as crypto/ecdsa: it keeps the leftmost bits of the digest
+// up to the bit length of n and reduces the result mod n
+func (p *Participant[A, B]) digestToScalar(digest []byte) (curves.Scalar, error) {
+	n := p.ec.Params().N
+	orderBits := n.BitLen()
+	orderBytes := (orderBits + 7) / 8
+	if len(digest) > orderBytes {
+		digest = digest[:orderBytes]
+	}
+	h := new(big.Int).SetBytes(digest)
+	if excess := len(digest)*8 - orderBits; excess > 0 {
+		h.Rsh(h, uint(excess))
+	}
+	return p.curve.Scalar.SetBigInt(h.Mod(h, n))
+}
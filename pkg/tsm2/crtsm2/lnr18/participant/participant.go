@@ -0,0 +1,171 @@
+// Package participant runs one party of the Lindell-Nof-Ranellucci threshold ECDSA scheme.
+//
+// Unlike lnr18.Scheme, which simulates every party inside a single struct for benchmarking, a Participant only ever
+// holds its own secrets. The DKG and DS phases are split into rounds; each round consumes the messages the peers
+// produced in the previous round, keyed by the sender's identifier, and emits either a single broadcast message or one
+// point-to-point message per peer. The iterators in protocol.go wrap the rounds into the core/protocol.Iterator pattern
+// so that the parties can run in separate processes.
+//
+// Signing takes a digest that the caller computed, e.g. with SHA-256, and outputs a low-S curves.EcdsaSignature with a
+// recovery id that crypto/ecdsa and secp256k1 verifiers accept.
+package participant
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/sign_offline"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
+	"github.com/coinbase/kryptology/pkg/zkp/reg"
+	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+)
+
+// Participant is a single party of the LNR18 scheme.
+// It owns one MtA sender and one MtA receiver per peer: the sender multiplies our gamma with the peer's secret and the
+// receiver multiplies our secret with the peer's gamma. The peer must hold the matching receiver and sender.
+type Participant[A any, B any] struct {
+	curve     *curves.Curve
+	ec        elliptic.Curve
+	P         curves.Point
+	sessionId []byte
+
+	id    uint32
+	peers []uint32
+
+	mtaSenders   map[uint32]sign_offline.MTASender[A, B]
+	mtaReceivers map[uint32]sign_offline.MTAReceiver[A, B]
+
+	// own secrets
+	x curves.Scalar
+	d curves.Scalar
+
+	// public values
+	QProofs map[uint32]*schnorr.Proof
+	TProofs map[uint32]*schnorr.Proof
+	Q       curves.Point
+	T       curves.Point
+
+	dkg  *dkgState
+	sign *signState[A, B]
+}
+
+// DKGOutput is the public result of the DKG
+type DKGOutput struct {
+	// Q is the joint ECDSA public key
+	Q curves.Point
+	// T is the joint ElGamal encryption key
+	T curves.Point
+}
+
+// NewParticipant creates the party id of a session among ids.
+// mtaSenders and mtaReceivers hold the pairwise MtA instances keyed by peer identifier; their setup happens out of band.
+// sessionId must be the same for all parties and unique to the session; the session ids of the proofs are derived from
+// it. The curve must be one that crypto/ecdsa can verify on, such as K256 or P256.
+func NewParticipant[A any, B any](curve *curves.Curve, id uint32, ids []uint32, mtaSenders map[uint32]sign_offline.MTASender[A, B], mtaReceivers map[uint32]sign_offline.MTAReceiver[A, B], sessionId []byte) (*Participant[A, B], error) {
+	if curve == nil {
+		return nil, fmt.Errorf("curve is nil")
+	}
+	if len(sessionId) == 0 {
+		return nil, fmt.Errorf("session id is empty")
+	}
+	ec, err := curve.ToEllipticCurve()
+	if err != nil {
+		return nil, err
+	}
+	if err := parties.Validate(ids, 2); err != nil {
+		return nil, err
+	}
+	var peers []uint32
+	isParty := false
+	for _, other := range ids {
+		if other == id {
+			isParty = true
+			continue
+		}
+		if mtaSenders[other] == nil || mtaReceivers[other] == nil {
+			return nil, fmt.Errorf("missing MtA instances for peer %d", other)
+		}
+		peers = append(peers, other)
+	}
+	if !isParty {
+		return nil, fmt.Errorf("party %d is not one of the participants", id)
+	}
+	return &Participant[A, B]{
+		curve:        curve,
+		ec:           ec,
+		P:            curve.NewGeneratorPoint(),
+		sessionId:    append([]byte{}, sessionId...),
+		id:           id,
+		peers:        peers,
+		mtaSenders:   mtaSenders,
+		mtaReceivers: mtaReceivers,
+	}, nil
+}
+
+// Id returns the identifier of the participant
+func (p *Participant[A, B]) Id() uint32 {
+	return p.id
+}
+
+// Peers returns the identifiers of the other participants
+func (p *Participant[A, B]) Peers() []uint32 {
+	return append([]uint32{}, p.peers...)
+}
+
+// proofSessionId returns the session id of the DKG proof labeled label that the party sender produces
+func (p *Participant[A, B]) proofSessionId(sender uint32, label string) []byte {
+	return parties.ProofSessionId(p.sessionId, sender, label)
+}
+
+// DKGOutput returns the public result of the DKG, or nil if the DKG has not completed
+func (p *Participant[A, B]) DKGOutput() *DKGOutput {
+	if p.Q == nil || p.dkg != nil {
+		return nil
+	}
+	return &DKGOutput{
+		Q: p.Q,
+		T: p.T,
+	}
+}
+
+// PublicKey returns the joint public key as a crypto/ecdsa key, or nil if the DKG has not completed
+func (p *Participant[A, B]) PublicKey() *ecdsa.PublicKey {
+	if p.DKGOutput() == nil {
+		return nil
+	}
+	x, y := affine(p.Q)
+	return &ecdsa.PublicKey{Curve: p.ec, X: x, Y: y}
+}
+
+// affine returns the affine coordinates of a point that is not the identity
+func affine(point curves.Point) (*big.Int, *big.Int) {
+	uncompressed := point.ToAffineUncompressed()
+	size := (len(uncompressed) - 1) >> 1
+	return new(big.Int).SetBytes(uncompressed[1 : 1+size]), new(big.Int).SetBytes(uncompressed[1+size:])
+}
+
+// checkInputs makes sure that there is exactly one message from every peer
+func checkInputs[A any, B any, M any](p *Participant[A, B], inputs map[uint32]M) error {
+	if len(inputs) != len(p.peers) {
+		return fmt.Errorf("expected messages from %d peers, got %d", len(p.peers), len(inputs))
+	}
+	for _, peer := range p.peers {
+		if _, ok := inputs[peer]; !ok {
+			return fmt.Errorf("missing message from party %d", peer)
+		}
+	}
+	return nil
+}
+
+// sumRegProofs adds up the ElGamal ciphertexts of our own and the peers' encryption proofs
+func sumRegProofs(own *reg.Proof, others map[uint32]*reg.Proof) (curves.Point, curves.Point) {
+	U, V := own.A, own.B
+	for _, proof := range others {
+		U = U.Add(proof.A)
+		V = V.Add(proof.B)
+	}
+	return U, V
+}
@@ -0,0 +1,374 @@
+package participant
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/protocol"
+	"github.com/coinbase/kryptology/pkg/ot/base/simplest"
+	"github.com/coinbase/kryptology/pkg/ot/extension/kos"
+	"github.com/coinbase/kryptology/pkg/ot/ottest"
+	mta_ot "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/ot"
+	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/sign_offline"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/abort"
+)
+
+type testParticipant = Participant[*mta_ot.Round1Output, *mta_ot.Round2Output]
+
+// newParticipants runs the OT setup of every ordered pair (i, j), in which i multiplies its gamma with j's secret
+var testSessionId = []byte("lnr18 participant test")
+
+func newParticipants(t *testing.T, curve *curves.Curve, ids []uint32) map[uint32]*testParticipant {
+	senders := make(map[uint32]map[uint32]sign_offline.MTASender[*mta_ot.Round1Output, *mta_ot.Round2Output])
+	receivers := make(map[uint32]map[uint32]sign_offline.MTAReceiver[*mta_ot.Round1Output, *mta_ot.Round2Output])
+	for _, id := range ids {
+		senders[id] = make(map[uint32]sign_offline.MTASender[*mta_ot.Round1Output, *mta_ot.Round2Output])
+		receivers[id] = make(map[uint32]sign_offline.MTAReceiver[*mta_ot.Round1Output, *mta_ot.Round2Output])
+	}
+	for _, i := range ids {
+		for _, j := range ids {
+			if i == j {
+				continue
+			}
+			uniqueSessionId := [simplest.DigestSize]byte{}
+			copy(uniqueSessionId[:], strconv.Itoa(int(i))+"->"+strconv.Itoa(int(j)))
+			baseOtSenderOutput, baseOtReceiverOutput, err := ottest.RunSimplestOT(curve, kos.Kappa, uniqueSessionId)
+			require.NoError(t, err)
+			sender, err := mta_ot.NewSender(baseOtReceiverOutput, curve, uniqueSessionId)
+			require.NoError(t, err)
+			receiver, err := mta_ot.NewReceiver(baseOtSenderOutput, curve, uniqueSessionId)
+			require.NoError(t, err)
+			senders[i][j] = sender
+			receivers[j][i] = receiver
+		}
+	}
+	participants := make(map[uint32]*testParticipant, len(ids))
+	for _, id := range ids {
+		p, err := NewParticipant(curve, id, ids, senders[id], receivers[id], testSessionId)
+		require.NoError(t, err)
+		participants[id] = p
+	}
+	return participants
+}
+
+func collect[T any](to uint32, outputs map[uint32]T) map[uint32]T {
+	inputs := make(map[uint32]T, len(outputs)-1)
+	for from, output := range outputs {
+		if from != to {
+			inputs[from] = output
+		}
+	}
+	return inputs
+}
+
+func collectDirect[T any](to uint32, outputs map[uint32]map[uint32]T) map[uint32]T {
+	inputs := make(map[uint32]T, len(outputs)-1)
+	for from, output := range outputs {
+		if from != to {
+			inputs[from] = output[to]
+		}
+	}
+	return inputs
+}
+
+// runRound calls round on every participant with what its peers emitted in the previous round
+func runRound[In any, Out any](t *testing.T, participants map[uint32]*testParticipant, inputs func(uint32) In, round func(*testParticipant, In) (Out, error)) map[uint32]Out {
+	outputs := make(map[uint32]Out, len(participants))
+	for id, p := range participants {
+		output, err := round(p, inputs(id))
+		require.NoError(t, err)
+		outputs[id] = output
+	}
+	return outputs
+}
+
+func broadcast[T any](outputs map[uint32]T) func(uint32) map[uint32]T {
+	return func(id uint32) map[uint32]T { return collect(id, outputs) }
+}
+
+func direct[T any](outputs map[uint32]map[uint32]T) func(uint32) map[uint32]T {
+	return func(id uint32) map[uint32]T { return collectDirect(id, outputs) }
+}
+
+func runDKG(t *testing.T, participants map[uint32]*testParticipant) map[uint32]*DKGOutput {
+	none := func(uint32) struct{} { return struct{}{} }
+	r1 := runRound(t, participants, none, func(p *testParticipant, _ struct{}) (*DKGPhase1Commitments, error) { return p.DKGPhase1Commit() })
+	r2 := runRound(t, participants, broadcast(r1), (*testParticipant).DKGPhase1Decommit)
+	return runRound(t, participants, broadcast(r2), (*testParticipant).DKGPhase1Finalize)
+}
+
+// runDSUntilReveal runs the DS on digest up to the reveal of delta
+func runDSUntilReveal(t *testing.T, participants map[uint32]*testParticipant, digest []byte) map[uint32]*DSPhase6DeltaReveal {
+	start := func(uint32) []byte { return digest }
+	r1 := runRound(t, participants, start, (*testParticipant).DSPhase1Commit)
+	r2 := runRound(t, participants, broadcast(r1), (*testParticipant).DSPhase1Decommit)
+	r3 := runRound(t, participants, broadcast(r2), (*testParticipant).DSPhase2Encrypt)
+	r4 := runRound(t, participants, broadcast(r3), (*testParticipant).DSPhase2Relate)
+	r5 := runRound(t, participants, broadcast(r4), (*testParticipant).DSPhase3MtAInit)
+	r6 := runRound(t, participants, direct(r5), (*testParticipant).DSPhase3MtAUpdate)
+	r7 := runRound(t, participants, direct(r6), (*testParticipant).DSPhase3MtAMultiply)
+	r8 := runRound(t, participants, broadcast(r7), (*testParticipant).DSPhase4MtAInit)
+	r9 := runRound(t, participants, direct(r8), (*testParticipant).DSPhase4MtAUpdate)
+	r10 := runRound(t, participants, direct(r9), (*testParticipant).DSPhase4MtAMultiply)
+	r11 := runRound(t, participants, broadcast(r10), (*testParticipant).DSPhase5Rerandomize)
+	r12 := runRound(t, participants, broadcast(r11), (*testParticipant).DSPhase5RerandomizeDecommit)
+	r13 := runRound(t, participants, broadcast(r12), (*testParticipant).DSPhase5DDHCommit)
+	r14 := runRound(t, participants, broadcast(r13), (*testParticipant).DSPhase5DDHDecommit)
+	return runRound(t, participants, broadcast(r14), (*testParticipant).DSPhase6RevealDelta)
+}
+
+func runDS(t *testing.T, participants map[uint32]*testParticipant, digest []byte) map[uint32]*curves.EcdsaSignature {
+	r15 := runDSUntilReveal(t, participants, digest)
+	r16 := runRound(t, participants, broadcast(r15), (*testParticipant).DSPhase6PartialSign)
+	return runRound(t, participants, broadcast(r16), (*testParticipant).DSPhase6Finalize)
+}
+
+func affineKey(t *testing.T, Q curves.Point) (*big.Int, *big.Int) {
+	uncompressed := Q.ToAffineUncompressed()
+	require.Len(t, uncompressed, 65)
+	return new(big.Int).SetBytes(uncompressed[1:33]), new(big.Int).SetBytes(uncompressed[33:])
+}
+
+func TestParticipantsP256(t *testing.T) {
+	participants := newParticipants(t, curves.P256(), []uint32{1, 2, 5})
+	outputs := runDKG(t, participants)
+	x, y := affineKey(t, outputs[1].Q)
+	pk := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+	for _, output := range outputs {
+		require.True(t, output.Q.Equal(outputs[1].Q))
+	}
+	require.Zero(t, participants[2].PublicKey().X.Cmp(x))
+	require.Zero(t, participants[2].PublicKey().Y.Cmp(y))
+
+	// the same key signs several digests, including one longer than the order
+	sha256Digest := sha256.Sum256([]byte("message digest"))
+	sha512Digest := sha512.Sum512([]byte("another message"))
+	for _, digest := range [][]byte{sha256Digest[:], sha512Digest[:]} {
+		signatures := runDS(t, participants, digest)
+		for _, signature := range signatures {
+			require.True(t, ecdsa.Verify(pk, digest, signature.R, signature.S))
+			require.Equal(t, signatures[1], signature)
+		}
+	}
+}
+
+func TestParticipantsK256(t *testing.T) {
+	participants := newParticipants(t, curves.K256(), []uint32{3, 4, 7, 9})
+	outputs := runDKG(t, participants)
+	pk, err := btcec.ParsePubKey(outputs[3].Q.ToAffineCompressed(), btcec.S256())
+	require.NoError(t, err)
+	halfN := new(big.Int).Rsh(btcec.S256().N, 1)
+
+	for _, message := range []string{"message digest", "another message", "a third message"} {
+		digest := sha256.Sum256([]byte(message))
+		signatures := runDS(t, participants, digest[:])
+		for _, signature := range signatures {
+			require.True(t, (&btcec.Signature{R: signature.R, S: signature.S}).Verify(digest[:], pk))
+			require.True(t, signature.S.Cmp(halfN) <= 0)
+
+			// the recovery id gives back the public key
+			compact := make([]byte, 65)
+			compact[0] = byte(27 + signature.V)
+			signature.R.FillBytes(compact[1:33])
+			signature.S.FillBytes(compact[33:])
+			recovered, _, err := btcec.RecoverCompact(btcec.S256(), compact, digest[:])
+			require.NoError(t, err)
+			require.True(t, recovered.IsEqual(pk))
+		}
+	}
+}
+
+func TestNewParticipantValidation(t *testing.T) {
+	curve := curves.K256()
+	participants := newParticipants(t, curve, []uint32{1, 2})
+	p := participants[1]
+
+	_, err := NewParticipant(curve, 1, []uint32{1}, p.mtaSenders, p.mtaReceivers, testSessionId)
+	require.Error(t, err)
+	_, err = NewParticipant(curve, 3, []uint32{1, 2}, p.mtaSenders, p.mtaReceivers, testSessionId)
+	require.Error(t, err)
+	_, err = NewParticipant(curve, 1, []uint32{1, 2, 3}, p.mtaSenders, p.mtaReceivers, testSessionId)
+	require.Error(t, err)
+	// crypto/ecdsa cannot verify on this curve
+	_, err = NewParticipant(curves.ED25519(), 1, []uint32{1, 2}, p.mtaSenders, p.mtaReceivers, testSessionId)
+	require.Error(t, err)
+	_, err = NewParticipant(curve, 1, []uint32{1, 2}, p.mtaSenders, p.mtaReceivers, nil)
+	require.Error(t, err)
+}
+
+func TestParticipantRoundOrder(t *testing.T) {
+	participants := newParticipants(t, curves.P256(), []uint32{1, 2})
+	p := participants[1]
+	digest := sha256.Sum256([]byte("message"))
+
+	// signing needs a completed DKG
+	_, err := p.DSPhase1Commit(digest[:])
+	require.Error(t, err)
+	require.Nil(t, p.PublicKey())
+
+	_, err = p.DKGPhase1Finalize(nil)
+	require.Error(t, err)
+	runDKG(t, participants)
+	_, err = p.DKGPhase1Commit()
+	require.Error(t, err)
+
+	_, err = p.DSPhase1Commit(nil)
+	require.Error(t, err)
+	_, err = p.DSPhase2Encrypt(nil)
+	require.Error(t, err)
+	_, err = p.DSPhase1Commit(digest[:])
+	require.NoError(t, err)
+	// a message from every peer is required
+	_, err = p.DSPhase1Decommit(nil)
+	require.Error(t, err)
+}
+
+func TestParticipantRejectsTamperedDelta(t *testing.T) {
+	curve := curves.K256()
+	participants := newParticipants(t, curve, []uint32{1, 2, 3})
+	runDKG(t, participants)
+	digest := sha256.Sum256([]byte("message"))
+	reveals := runDSUntilReveal(t, participants, digest[:])
+
+	reveals[3].Delta = reveals[3].Delta.Add(curve.Scalar.One())
+	_, err := participants[1].DSPhase6PartialSign(collect(1, reveals))
+	require.Error(t, err)
+}
+
+func TestParticipantIdentifiesCulprit(t *testing.T) {
+	curve := curves.P256()
+	participants := newParticipants(t, curve, []uint32{1, 2, 3})
+	runDKG(t, participants)
+	digest := sha256.Sum256([]byte("message"))
+	start := func(uint32) []byte { return digest[:] }
+	r1 := runRound(t, participants, start, (*testParticipant).DSPhase1Commit)
+	r2 := runRound(t, participants, broadcast(r1), (*testParticipant).DSPhase1Decommit)
+	r3 := runRound(t, participants, broadcast(r2), (*testParticipant).DSPhase2Encrypt)
+	r4 := runRound(t, participants, broadcast(r3), (*testParticipant).DSPhase2Relate)
+
+	// party 2 re-randomizes the encryption of gamma with a nonce that is not bound to R_2
+	relations := *r4[2]
+	relations.KGammaProof = r4[1].KGammaProof
+	r4[2] = &relations
+	_, err := participants[3].DSPhase3MtAInit(collect(3, r4))
+	var abortErr *abort.Error
+	require.True(t, errors.As(err, &abortErr))
+	require.Equal(t, uint32(2), abortErr.Culprit)
+	require.Equal(t, "DSPhase3MtAInit", abortErr.Phase)
+	require.Equal(t, abort.DsSRanCTGamma, abortErr.Evidence.Check)
+
+	// party 1 confirms with the proof it received from party 2 and its own inputs
+	view := participants[1]
+	confirmed, err := abortErr.Evidence.Confirm(curve, &abort.Evidence{
+		Check:     abort.DsSRanCTGamma,
+		Proof:     r4[2].KGammaProof,
+		SessionId: view.signProofSessionId(2, dsKGammaLabel),
+		Inputs:    []curves.Point{view.T, view.sign.UGamma, view.sign.VGamma, view.sign.kProofs[2].Statement},
+	})
+	require.NoError(t, err)
+	require.True(t, confirmed)
+}
+
+// route delivers every broadcast payload to all the other parties and every direct payload to its recipient,
+// keyed by the sender's identifier
+func route(t *testing.T, outputs map[uint32]*protocol.Message) map[uint32]*protocol.Message {
+	inputs := make(map[uint32]*protocol.Message, len(outputs))
+	for id := range outputs {
+		inputs[id] = &protocol.Message{Version: protocol.Version1, Payloads: map[string][]byte{}}
+	}
+	for from, output := range outputs {
+		if output == nil {
+			continue
+		}
+		sender := strconv.FormatUint(uint64(from), 10)
+		for key, payload := range output.Payloads {
+			if key == broadcastKey {
+				for to := range outputs {
+					if to != from {
+						inputs[to].Payloads[sender] = payload
+					}
+				}
+				continue
+			}
+			to, err := strconv.ParseUint(key, 10, 32)
+			require.NoError(t, err)
+			inputs[uint32(to)].Payloads[sender] = payload
+		}
+	}
+	return inputs
+}
+
+func runIteratedProtocol(t *testing.T, parties map[uint32]protocol.Iterator) {
+	var inputs map[uint32]*protocol.Message
+	for {
+		outputs := make(map[uint32]*protocol.Message, len(parties))
+		finished := 0
+		for id, party := range parties {
+			var input *protocol.Message
+			if inputs != nil {
+				input = inputs[id]
+			}
+			output, err := party.Next(input)
+			if err == protocol.ErrProtocolFinished {
+				finished++
+				continue
+			}
+			require.NoError(t, err)
+			outputs[id] = output
+		}
+		if finished == len(parties) {
+			return
+		}
+		require.Zero(t, finished, "parties finished at different rounds")
+		inputs = route(t, outputs)
+	}
+}
+
+func TestParticipantProtocol(t *testing.T) {
+	curve := curves.K256()
+	ids := []uint32{1, 2, 3}
+	participants := newParticipants(t, curve, ids)
+
+	dkgs := make(map[uint32]protocol.Iterator, len(ids))
+	for id, p := range participants {
+		dkgs[id] = NewDkg(p, protocol.Version1)
+	}
+	runIteratedProtocol(t, dkgs)
+	var Q curves.Point
+	for _, dkg := range dkgs {
+		result, err := dkg.Result(protocol.Version1)
+		require.NoError(t, err)
+		output, err := DecodeDkgOutput(result)
+		require.NoError(t, err)
+		if Q == nil {
+			Q = output.Q
+		}
+		require.True(t, output.Q.Equal(Q))
+	}
+	pk, err := btcec.ParsePubKey(Q.ToAffineCompressed(), btcec.S256())
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("message digest"))
+	signs := make(map[uint32]protocol.Iterator, len(ids))
+	for id, p := range participants {
+		signs[id] = NewSign(p, digest[:], protocol.Version1)
+	}
+	runIteratedProtocol(t, signs)
+	for _, sign := range signs {
+		result, err := sign.Result(protocol.Version1)
+		require.NoError(t, err)
+		signature, err := DecodeSignature(result)
+		require.NoError(t, err)
+		require.True(t, (&btcec.Signature{R: signature.R, S: signature.S}).Verify(digest[:], pk))
+	}
+}
@@ -0,0 +1,184 @@
+package participant
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/protocol"
+)
+
+// Basic protocol interface implementation that calls the next step func in a pre-defined list
+type protoStepper struct {
+	steps []func(input *protocol.Message) (*protocol.Message, error)
+	step  int
+}
+
+// Next runs the next step in the protocol and reports errors or increments the step index
+func (p *protoStepper) Next(input *protocol.Message) (*protocol.Message, error) {
+	if p.complete() {
+		return nil, protocol.ErrProtocolFinished
+	}
+
+	// Run the current protocol step and report any errors
+	output, err := p.steps[p.step](input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Increment the step index and report success
+	p.step++
+	return output, nil
+}
+
+// Reports true if the step index exceeds the number of steps
+func (p *protoStepper) complete() bool { return p.step >= len(p.steps) }
+
+// Dkg runs the DKG of a single participant and satisfies the protocol iterator interface.
+//
+// The first call to Next takes no input. Each following call takes the messages that the peers emitted in the previous
+// round, with the payloads keyed by the decimal identifier of the sender. Outputs are keyed by "broadcast" for messages
+// to every peer, or by the decimal identifier of the recipient for point-to-point messages. The last round emits nothing.
+type Dkg[A any, B any] struct {
+	protoStepper
+	*Participant[A, B]
+}
+
+// Sign runs the DS of a single participant and satisfies the protocol iterator interface.
+// It follows the same message conventions as Dkg.
+type Sign[A any, B any] struct {
+	protoStepper
+	*Participant[A, B]
+	signature *curves.EcdsaSignature
+}
+
+var (
+	// Static type assertions
+	_ protocol.Iterator = &Dkg[any, any]{}
+	_ protocol.Iterator = &Sign[any, any]{}
+)
+
+// broadcastRound wraps a round that consumes and produces broadcast messages
+func broadcastRound[In any, Out any](peers []uint32, protocolName string, round string, version uint, f func(map[uint32]In) (Out, error)) func(*protocol.Message) (*protocol.Message, error) {
+	return func(input *protocol.Message) (*protocol.Message, error) {
+		inputs, err := decodeRoundInputs[In](input, peers)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		output, err := f(inputs)
+		if err != nil {
+			return nil, err
+		}
+		return encodeBroadcast(output, protocolName, round, version)
+	}
+}
+
+// directRound wraps a round that consumes messages of type In and produces point-to-point messages
+func directRound[In any, Out any](peers []uint32, protocolName string, round string, version uint, f func(map[uint32]In) (map[uint32]Out, error)) func(*protocol.Message) (*protocol.Message, error) {
+	return func(input *protocol.Message) (*protocol.Message, error) {
+		inputs, err := decodeRoundInputs[In](input, peers)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		output, err := f(inputs)
+		if err != nil {
+			return nil, err
+		}
+		return encodeDirect(output, protocolName, round, version)
+	}
+}
+
+// NewDkg creates a new protocol that runs the DKG as participant
+func NewDkg[A any, B any](participant *Participant[A, B], version uint) *Dkg[A, B] {
+	p := &Dkg[A, B]{Participant: participant}
+	peers := participant.peers
+	name := protocol.Lnr18Dkg
+	p.steps = []func(*protocol.Message) (*protocol.Message, error){
+		func(*protocol.Message) (*protocol.Message, error) {
+			output, err := p.DKGPhase1Commit()
+			if err != nil {
+				return nil, err
+			}
+			return encodeBroadcast(output, name, "1", version)
+		},
+		broadcastRound(peers, name, "2", version, p.DKGPhase1Decommit),
+		func(input *protocol.Message) (*protocol.Message, error) {
+			inputs, err := decodeRoundInputs[*DKGPhase1Proofs](input, peers)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if _, err = p.DKGPhase1Finalize(inputs); err != nil {
+				return nil, err
+			}
+			return nil, nil
+		},
+	}
+	return p
+}
+
+// Result returns the encoded public output of the DKG.
+// The secret state stays in the participant, which can then be used to create Sign protocols.
+func (p *Dkg[A, B]) Result(version uint) (*protocol.Message, error) {
+	// Sanity check
+	if !p.complete() {
+		return nil, nil
+	}
+	if p.Participant == nil {
+		return nil, protocol.ErrNotInitialized
+	}
+	return EncodeDkgOutput(p.DKGOutput(), version)
+}
+
+// NewSign creates a new protocol that signs digest as participant, which must have completed the DKG
+func NewSign[A any, B any](participant *Participant[A, B], digest []byte, version uint) *Sign[A, B] {
+	p := &Sign[A, B]{Participant: participant}
+	peers := participant.peers
+	name := protocol.Lnr18Sign
+	p.steps = []func(*protocol.Message) (*protocol.Message, error){
+		func(*protocol.Message) (*protocol.Message, error) {
+			output, err := p.DSPhase1Commit(digest)
+			if err != nil {
+				return nil, err
+			}
+			return encodeBroadcast(output, name, "1", version)
+		},
+		broadcastRound(peers, name, "2", version, p.DSPhase1Decommit),
+		broadcastRound(peers, name, "3", version, p.DSPhase2Encrypt),
+		broadcastRound(peers, name, "4", version, p.DSPhase2Relate),
+		directRound(peers, name, "5", version, p.DSPhase3MtAInit),
+		directRound(peers, name, "6", version, p.DSPhase3MtAUpdate),
+		broadcastRound(peers, name, "7", version, p.DSPhase3MtAMultiply),
+		directRound(peers, name, "8", version, p.DSPhase4MtAInit),
+		directRound(peers, name, "9", version, p.DSPhase4MtAUpdate),
+		broadcastRound(peers, name, "10", version, p.DSPhase4MtAMultiply),
+		broadcastRound(peers, name, "11", version, p.DSPhase5Rerandomize),
+		broadcastRound(peers, name, "12", version, p.DSPhase5RerandomizeDecommit),
+		broadcastRound(peers, name, "13", version, p.DSPhase5DDHCommit),
+		broadcastRound(peers, name, "14", version, p.DSPhase5DDHDecommit),
+		broadcastRound(peers, name, "15", version, p.DSPhase6RevealDelta),
+		broadcastRound(peers, name, "16", version, p.DSPhase6PartialSign),
+		func(input *protocol.Message) (*protocol.Message, error) {
+			inputs, err := decodeRoundInputs[curves.Scalar](input, peers)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			p.signature, err = p.DSPhase6Finalize(inputs)
+			if err != nil {
+				return nil, err
+			}
+			return nil, nil
+		},
+	}
+	return p
+}
+
+// Result returns the encoded signature
+func (p *Sign[A, B]) Result(version uint) (*protocol.Message, error) {
+	// Sanity check
+	if !p.complete() {
+		return nil, nil
+	}
+	if p.signature == nil {
+		return nil, protocol.ErrNotInitialized
+	}
+	return EncodeSignature(p.signature, version)
+}
@@ -0,0 +1,163 @@
+// Package mta sets up the pairwise multiplicative-to-additive conversions of the CRT-SM2 parties.
+//
+// Every ordered pair (i, j) of parties owns its own MtA instance, in which i is the sender, who inputs gamma_i, and j is
+// the receiver, who inputs its secret. Before the first multiplication the two ends run a setup over a fixed number of
+// rounds of point-to-point messages: the base OTs of the KOS extension for the OT backend, or the exchange of the
+// Paillier and ring-Pedersen parameters with their proofs for the Paillier backend. The receiver end moves first and the
+// ends then alternate, so that in every round exactly one end acts on the message the other end emitted in the previous
+// round. The final round only consumes a message. The session id of each instance is derived from a session id shared
+// by all parties and the identifiers of the pair.
+package mta
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/ot/base/simplest"
+	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/sign_offline"
+)
+
+// Setup is one end of the setup of an MtA instance
+type Setup interface {
+	// Next runs the next round on the message that the other end emitted in the previous round, which is nil in the
+	// first round and in the rounds in which this end only waits. It returns the message for the other end, if any.
+	Next(input []byte) ([]byte, error)
+}
+
+// SenderSetup is the end of the setup that produces the MtA sender
+type SenderSetup[A any, B any] interface {
+	Setup
+	// Sender returns the MtA sender, or nil until the setup has completed
+	Sender() sign_offline.MTASender[A, B]
+}
+
+// ReceiverSetup is the end of the setup that produces the MtA receiver
+type ReceiverSetup[A any, B any] interface {
+	Setup
+	// Receiver returns the MtA receiver, or nil until the setup has completed
+	Receiver() sign_offline.MTAReceiver[A, B]
+}
+
+// Backend creates the setups of the MtA instances of one party, whose messages are A and B
+type Backend[A any, B any] interface {
+	// Rounds is the number of rounds of the setup
+	Rounds() int
+	NewSenderSetup(uniqueSessionId [simplest.DigestSize]byte) (SenderSetup[A, B], error)
+	NewReceiverSetup(uniqueSessionId [simplest.DigestSize]byte) (ReceiverSetup[A, B], error)
+}
+
+// SessionId derives the session id of the MtA instance in which sender multiplies its gamma with receiver's secret
+func SessionId(sessionId []byte, sender uint32, receiver uint32) [simplest.DigestSize]byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte("CRT-SM2 MtA"))
+	_, _ = h.Write(sessionId)
+	ids := make([]byte, 8)
+	binary.BigEndian.PutUint32(ids[:4], sender)
+	binary.BigEndian.PutUint32(ids[4:], receiver)
+	_, _ = h.Write(ids)
+	uniqueSessionId := [simplest.DigestSize]byte{}
+	copy(uniqueSessionId[:], h.Sum(nil))
+	return uniqueSessionId
+}
+
+// Run executes the setup of the instance in which the party sender multiplies its gamma with the secret of the party
+// receiver in-process, as the simulated schemes do. Each end uses the backend of its own party.
+func Run[A any, B any](senderBackend Backend[A, B], receiverBackend Backend[A, B], sessionId []byte, sender uint32, receiver uint32) (sign_offline.MTASender[A, B], sign_offline.MTAReceiver[A, B], error) {
+	if senderBackend.Rounds() != receiverBackend.Rounds() {
+		return nil, nil, fmt.Errorf("the backends of parties %d and %d do not match", sender, receiver)
+	}
+	uniqueSessionId := SessionId(sessionId, sender, receiver)
+	senderSetup, err := senderBackend.NewSenderSetup(uniqueSessionId)
+	if err != nil {
+		return nil, nil, err
+	}
+	receiverSetup, err := receiverBackend.NewReceiverSetup(uniqueSessionId)
+	if err != nil {
+		return nil, nil, err
+	}
+	var toSender, toReceiver []byte
+	for round := 0; round < senderBackend.Rounds(); round++ {
+		fromReceiver, err := receiverSetup.Next(toReceiver)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "receiver %d in round %d of the MtA setup", receiver, round+1)
+		}
+		fromSender, err := senderSetup.Next(toSender)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "sender %d in round %d of the MtA setup", sender, round+1)
+		}
+		toSender, toReceiver = fromReceiver, fromSender
+	}
+	if senderSetup.Sender() == nil || receiverSetup.Receiver() == nil {
+		return nil, nil, fmt.Errorf("the MtA setup of parties %d and %d did not complete", sender, receiver)
+	}
+	return senderSetup.Sender(), receiverSetup.Receiver(), nil
+}
+
+// sessionBinder is implemented by the MtAs whose multiplications must be bound to the session they run in, like the
+// OT-based MtA, whose cOT pads repeat unless every multiplication has its own session id
+type sessionBinder interface {
+	SetSessionId(sessionId []byte)
+}
+
+// Init starts a multiplication on the receiver's input in the session sessionId, reporting a failure of the backend as
+// an error. The sender must answer with Update under the same session id.
+func Init[A any, B any](receiver sign_offline.MTAReceiver[A, B], sessionId []byte, x curves.Scalar) (a A, err error) {
+	defer recoverError(&err)
+	if binder, ok := receiver.(sessionBinder); ok {
+		binder.SetSessionId(sessionId)
+	}
+	return receiver.Init(x), nil
+}
+
+// Update answers the receiver's message with the sender's input in the session sessionId, reporting a failed proof as
+// an error
+func Update[A any, B any](sender sign_offline.MTASender[A, B], sessionId []byte, gamma curves.Scalar, a A) (alpha curves.Scalar, b B, err error) {
+	defer recoverError(&err)
+	if binder, ok := sender.(sessionBinder); ok {
+		binder.SetSessionId(sessionId)
+	}
+	alpha, b = sender.Update(gamma, a)
+	return alpha, b, nil
+}
+
+// Multiply completes the multiplication on the receiver's side, reporting a failed proof or check as an error
+func Multiply[A any, B any](receiver sign_offline.MTAReceiver[A, B], b B) (beta curves.Scalar, err error) {
+	defer recoverError(&err)
+	return receiver.Multiply(b), nil
+}
+
+// recoverError turns the panics with which the MtA implementations reject their inputs into an error
+func recoverError(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("%v", r)
+	}
+}
+
+// stepper runs one end of a setup. The nil steps are the rounds in which the end waits for the other.
+type stepper struct {
+	steps []func(input []byte) ([]byte, error)
+	round int
+}
+
+func (s *stepper) Next(input []byte) (output []byte, err error) {
+	if s.round >= len(s.steps) {
+		return nil, fmt.Errorf("the setup has finished")
+	}
+	step := s.steps[s.round]
+	s.round++
+	if step == nil {
+		if input != nil {
+			return nil, fmt.Errorf("unexpected message in round %d", s.round)
+		}
+		return nil, nil
+	}
+	if input == nil && s.round > 1 {
+		return nil, fmt.Errorf("missing message in round %d", s.round)
+	}
+	defer recoverError(&err)
+	return step(input)
+}
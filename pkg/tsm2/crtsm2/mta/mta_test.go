@@ -0,0 +1,148 @@
+package mta
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core"
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/paillier"
+	mta_ot "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/ot"
+	mta_paillier "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/paillier"
+	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/sign_offline"
+)
+
+var sessionId = []byte("test session")
+
+func newPaillierBackend(t *testing.T, curve *curves.Curve) *Paillier {
+	p, err := core.GenerateSafePrime(paillier.PaillierPrimeBits)
+	require.NoError(t, err)
+	q, err := core.GenerateSafePrime(paillier.PaillierPrimeBits)
+	require.NoError(t, err)
+	backend, err := NewPaillier(curve, p, q)
+	require.NoError(t, err)
+	return backend
+}
+
+// multiply checks that the instance shares the product of random inputs, twice to cover reuse across phases
+func multiply[A any, B any](t *testing.T, curve *curves.Curve, sender sign_offline.MTASender[A, B], receiver sign_offline.MTAReceiver[A, B]) {
+	for i := 0; i < 2; i++ {
+		gamma := curve.Scalar.Random(crand.Reader)
+		x := curve.Scalar.Random(crand.Reader)
+		multiplicationSessionId := []byte(fmt.Sprintf("multiplication %d", i))
+		a, err := Init(receiver, multiplicationSessionId, x)
+		require.NoError(t, err)
+		alpha, b, err := Update(sender, multiplicationSessionId, gamma, a)
+		require.NoError(t, err)
+		beta, err := Multiply(receiver, b)
+		require.NoError(t, err)
+		require.Equal(t, 0, alpha.Add(beta).Cmp(gamma.Mul(x)))
+	}
+}
+
+func TestRunOT(t *testing.T) {
+	for _, curve := range []*curves.Curve{curves.K256(), curves.SM2()} {
+		backend := NewOT(curve)
+		sender, receiver, err := Run[*mta_ot.Round1Output, *mta_ot.Round2Output](backend, backend, sessionId, 1, 2)
+		require.NoError(t, err)
+		multiply(t, curve, sender, receiver)
+	}
+}
+
+func TestOTMultiplicationsResynchronize(t *testing.T) {
+	curve := curves.SM2()
+	backend := NewOT(curve)
+	sender, receiver, err := Run[*mta_ot.Round1Output, *mta_ot.Round2Output](backend, backend, sessionId, 1, 2)
+	require.NoError(t, err)
+
+	// a session aborted after the receiver started its multiplication leaves the two ends one multiplication apart
+	_, err = Init(receiver, []byte("aborted session"), curve.Scalar.Random(crand.Reader))
+	require.NoError(t, err)
+
+	// the ends agree again as soon as both bind the next session
+	gamma := curve.Scalar.Random(crand.Reader)
+	x := curve.Scalar.Random(crand.Reader)
+	a, err := Init(receiver, []byte("next session"), x)
+	require.NoError(t, err)
+	alpha, b, err := Update(sender, []byte("next session"), gamma, a)
+	require.NoError(t, err)
+	beta, err := Multiply(receiver, b)
+	require.NoError(t, err)
+	require.Equal(t, 0, alpha.Add(beta).Cmp(gamma.Mul(x)))
+
+	// a sender in another session rejects the receiver's consistency check
+	a, err = Init(receiver, []byte("session of the receiver"), x)
+	require.NoError(t, err)
+	_, _, err = Update(sender, []byte("session of the sender"), gamma, a)
+	require.Error(t, err)
+}
+
+func TestRunPaillier(t *testing.T) {
+	curve := curves.SM2()
+	senderBackend := newPaillierBackend(t, curve)
+	receiverBackend := newPaillierBackend(t, curve)
+	sender, receiver, err := Run[*mta_paillier.Round1Output, *mta_paillier.Round2Output](senderBackend, receiverBackend, sessionId, 1, 2)
+	require.NoError(t, err)
+	multiply(t, curve, sender, receiver)
+
+	// a range proof that the sender's transcript does not expect is reported as an error rather than a panic
+	_, err = Init(receiver, sessionId, curve.Scalar.Random(crand.Reader))
+	require.NoError(t, err)
+	a, err := Init(receiver, sessionId, curve.Scalar.Random(crand.Reader))
+	require.NoError(t, err)
+	_, _, err = Update(sender, sessionId, curve.Scalar.Random(crand.Reader), a)
+	require.Error(t, err)
+
+	_, err = NewPaillier(curves.ED25519(), big.NewInt(5), big.NewInt(7))
+	require.Error(t, err)
+	_, err = NewPaillier(curve, nil, big.NewInt(7))
+	require.Error(t, err)
+}
+
+func TestSetupRejectsMismatchedSessions(t *testing.T) {
+	curve := curves.K256()
+	backend := NewOT(curve)
+	senderSetup, err := backend.NewSenderSetup(SessionId(sessionId, 1, 2))
+	require.NoError(t, err)
+	receiverSetup, err := backend.NewReceiverSetup(SessionId(sessionId, 2, 1))
+	require.NoError(t, err)
+
+	// the receiver's proof of its base OT key is bound to the other session id
+	toSender, err := receiverSetup.Next(nil)
+	require.NoError(t, err)
+	_, err = senderSetup.Next(nil)
+	require.NoError(t, err)
+	_, err = senderSetup.Next(toSender)
+	require.Error(t, err)
+}
+
+func TestSetupRejectsMalformedMessages(t *testing.T) {
+	curve := curves.K256()
+	backend := NewOT(curve)
+	uniqueSessionId := SessionId(sessionId, 1, 2)
+	senderSetup, err := backend.NewSenderSetup(uniqueSessionId)
+	require.NoError(t, err)
+
+	// the sender waits in the first round and needs a message in the second
+	_, err = senderSetup.Next([]byte("early"))
+	require.Error(t, err)
+	senderSetup, err = backend.NewSenderSetup(uniqueSessionId)
+	require.NoError(t, err)
+	_, err = senderSetup.Next(nil)
+	require.NoError(t, err)
+	_, err = senderSetup.Next(nil)
+	require.Error(t, err)
+	_, err = senderSetup.Next([]byte("garbage"))
+	require.Error(t, err)
+	require.Nil(t, senderSetup.Sender())
+}
+
+func TestSessionId(t *testing.T) {
+	require.NotEqual(t, SessionId(sessionId, 1, 2), SessionId(sessionId, 2, 1))
+	require.NotEqual(t, SessionId(sessionId, 1, 2), SessionId([]byte("other session"), 1, 2))
+	require.Equal(t, SessionId(sessionId, 1, 2), SessionId(sessionId, 1, 2))
+}
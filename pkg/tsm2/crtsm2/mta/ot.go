@@ -0,0 +1,164 @@
+package mta
+
+import (
+	"fmt"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/ot/base/simplest"
+	"github.com/coinbase/kryptology/pkg/ot/extension/kos"
+	mta_ot "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/ot"
+	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/sign_offline"
+	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+)
+
+// OT is the backend of the OT-based MtA of DKLs over the KOS extension. The setup runs the verified simplest OT with
+// kos.Kappa base OTs, in which the MtA receiver is the OT sender.
+type OT struct {
+	curve *curves.Curve
+}
+
+var _ Backend[*mta_ot.Round1Output, *mta_ot.Round2Output] = &OT{}
+
+// NewOT creates the OT backend on curve
+func NewOT(curve *curves.Curve) *OT {
+	return &OT{curve: curve}
+}
+
+// Rounds is the number of messages of the simplest OT plus the final verification
+func (backend *OT) Rounds() int {
+	return 6
+}
+
+type otSenderSetup struct {
+	stepper
+	sender *mta_ot.Sender
+}
+
+type otReceiverSetup struct {
+	stepper
+	receiver *mta_ot.Receiver
+}
+
+// NewSenderSetup runs the base OT as the OT receiver
+func (backend *OT) NewSenderSetup(uniqueSessionId [simplest.DigestSize]byte) (SenderSetup[*mta_ot.Round1Output, *mta_ot.Round2Output], error) {
+	ot, err := simplest.NewReceiver(backend.curve, kos.Kappa, uniqueSessionId)
+	if err != nil {
+		return nil, err
+	}
+	setup := &otSenderSetup{}
+	setup.steps = []func([]byte) ([]byte, error){
+		nil,
+		func(input []byte) ([]byte, error) {
+			proof, err := decode[*schnorr.Proof](input)
+			if err != nil {
+				return nil, err
+			}
+			if proof == nil || proof.Statement == nil {
+				return nil, fmt.Errorf("missing base OT public key")
+			}
+			maskedChoices, err := ot.Round2VerifySchnorrAndPadTransfer(proof)
+			if err != nil {
+				return nil, err
+			}
+			return encode(maskedChoices)
+		},
+		nil,
+		func(input []byte) ([]byte, error) {
+			challenges, err := decode[[]simplest.OtChallenge](input)
+			if err != nil {
+				return nil, err
+			}
+			if len(challenges) != kos.Kappa {
+				return nil, fmt.Errorf("expected %d challenges, got %d", kos.Kappa, len(challenges))
+			}
+			responses, err := ot.Round4RespondToChallenge(challenges)
+			if err != nil {
+				return nil, err
+			}
+			return encode(responses)
+		},
+		nil,
+		func(input []byte) ([]byte, error) {
+			openings, err := decode[[]simplest.ChallengeOpening](input)
+			if err != nil {
+				return nil, err
+			}
+			if len(openings) != kos.Kappa {
+				return nil, fmt.Errorf("expected %d openings, got %d", kos.Kappa, len(openings))
+			}
+			if err = ot.Round6Verify(openings); err != nil {
+				return nil, err
+			}
+			setup.sender, err = mta_ot.NewSender(ot.Output, backend.curve, uniqueSessionId)
+			return nil, err
+		},
+	}
+	return setup, nil
+}
+
+// NewReceiverSetup runs the base OT as the OT sender
+func (backend *OT) NewReceiverSetup(uniqueSessionId [simplest.DigestSize]byte) (ReceiverSetup[*mta_ot.Round1Output, *mta_ot.Round2Output], error) {
+	ot, err := simplest.NewSender(backend.curve, kos.Kappa, uniqueSessionId)
+	if err != nil {
+		return nil, err
+	}
+	setup := &otReceiverSetup{}
+	setup.steps = []func([]byte) ([]byte, error){
+		func([]byte) ([]byte, error) {
+			proof, err := ot.Round1ComputeAndZkpToPublicKey()
+			if err != nil {
+				return nil, err
+			}
+			return encode(proof)
+		},
+		nil,
+		func(input []byte) ([]byte, error) {
+			maskedChoices, err := decode[[]simplest.ReceiversMaskedChoices](input)
+			if err != nil {
+				return nil, err
+			}
+			if len(maskedChoices) != kos.Kappa {
+				return nil, fmt.Errorf("expected %d masked choices, got %d", kos.Kappa, len(maskedChoices))
+			}
+			challenges, err := ot.Round3PadTransfer(maskedChoices)
+			if err != nil {
+				return nil, err
+			}
+			return encode(challenges)
+		},
+		nil,
+		func(input []byte) ([]byte, error) {
+			responses, err := decode[[]simplest.OtChallengeResponse](input)
+			if err != nil {
+				return nil, err
+			}
+			if len(responses) != kos.Kappa {
+				return nil, fmt.Errorf("expected %d responses, got %d", kos.Kappa, len(responses))
+			}
+			openings, err := ot.Round5Verify(responses)
+			if err != nil {
+				return nil, err
+			}
+			if setup.receiver, err = mta_ot.NewReceiver(ot.Output, backend.curve, uniqueSessionId); err != nil {
+				return nil, err
+			}
+			return encode(openings)
+		},
+		nil,
+	}
+	return setup, nil
+}
+
+func (setup *otSenderSetup) Sender() sign_offline.MTASender[*mta_ot.Round1Output, *mta_ot.Round2Output] {
+	if setup.sender == nil {
+		return nil
+	}
+	return setup.sender
+}
+
+func (setup *otReceiverSetup) Receiver() sign_offline.MTAReceiver[*mta_ot.Round1Output, *mta_ot.Round2Output] {
+	if setup.receiver == nil {
+		return nil
+	}
+	return setup.receiver
+}
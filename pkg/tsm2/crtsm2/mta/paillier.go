@@ -0,0 +1,122 @@
+package mta
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/ot/base/simplest"
+	mta_paillier "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/paillier"
+	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/sign_offline"
+)
+
+// Paillier is the backend of the Paillier-based MtA, whose messages carry the r_pwr and r_affran range proofs.
+// In the setup each end publishes its Paillier modulus with ring-Pedersen parameters and proves them well formed.
+type Paillier struct {
+	curve *curves.Curve
+	p     *big.Int
+	q     *big.Int
+}
+
+var _ Backend[*mta_paillier.Round1Output, *mta_paillier.Round2Output] = &Paillier{}
+
+// NewPaillier creates the Paillier backend of a party whose Paillier key is made of the safe primes p and q.
+// The same key serves every instance of the party.
+func NewPaillier(curve *curves.Curve, p *big.Int, q *big.Int) (*Paillier, error) {
+	if _, err := curve.ToEllipticCurve(); err != nil {
+		return nil, err
+	}
+	if p == nil || q == nil {
+		return nil, fmt.Errorf("missing Paillier primes")
+	}
+	return &Paillier{curve: curve, p: p, q: q}, nil
+}
+
+// Rounds is the number of setup messages plus the final verification
+func (backend *Paillier) Rounds() int {
+	return 3
+}
+
+// paillierSetupMessage carries the parameters of one end and their proofs
+type paillierSetupMessage struct {
+	Statement *mta_paillier.SetupStatement
+	Proof     *mta_paillier.SetupProof
+}
+
+func decodeSetupMessage(input []byte) (*paillierSetupMessage, error) {
+	message, err := decode[*paillierSetupMessage](input)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil || message.Statement == nil || message.Proof == nil {
+		return nil, fmt.Errorf("missing setup parameters")
+	}
+	return message, nil
+}
+
+type paillierSenderSetup struct {
+	stepper
+	sender *mta_paillier.Sender
+	done   bool
+}
+
+type paillierReceiverSetup struct {
+	stepper
+	receiver *mta_paillier.Receiver
+	done     bool
+}
+
+// NewSenderSetup verifies the receiver's parameters and answers with its own
+func (backend *Paillier) NewSenderSetup(uniqueSessionId [simplest.DigestSize]byte) (SenderSetup[*mta_paillier.Round1Output, *mta_paillier.Round2Output], error) {
+	setup := &paillierSenderSetup{sender: mta_paillier.NewSenderWithSessionId(backend.curve, backend.p, backend.q, uniqueSessionId[:])}
+	setup.steps = []func([]byte) ([]byte, error){
+		nil,
+		func(input []byte) ([]byte, error) {
+			message, err := decodeSetupMessage(input)
+			if err != nil {
+				return nil, err
+			}
+			statement, proof := setup.sender.SetupUpdate(*message.Statement, *message.Proof)
+			setup.done = true
+			return encode(&paillierSetupMessage{Statement: &statement, Proof: &proof})
+		},
+		nil,
+	}
+	return setup, nil
+}
+
+// NewReceiverSetup publishes the receiver's parameters and verifies the sender's
+func (backend *Paillier) NewReceiverSetup(uniqueSessionId [simplest.DigestSize]byte) (ReceiverSetup[*mta_paillier.Round1Output, *mta_paillier.Round2Output], error) {
+	setup := &paillierReceiverSetup{receiver: mta_paillier.NewReceiverWithSessionId(backend.curve, backend.p, backend.q, uniqueSessionId[:])}
+	setup.steps = []func([]byte) ([]byte, error){
+		func([]byte) ([]byte, error) {
+			statement, proof := setup.receiver.SetupInit()
+			return encode(&paillierSetupMessage{Statement: &statement, Proof: &proof})
+		},
+		nil,
+		func(input []byte) ([]byte, error) {
+			message, err := decodeSetupMessage(input)
+			if err != nil {
+				return nil, err
+			}
+			setup.receiver.SetupDone(*message.Statement, *message.Proof)
+			setup.done = true
+			return nil, nil
+		},
+	}
+	return setup, nil
+}
+
+func (setup *paillierSenderSetup) Sender() sign_offline.MTASender[*mta_paillier.Round1Output, *mta_paillier.Round2Output] {
+	if !setup.done {
+		return nil
+	}
+	return setup.sender
+}
+
+func (setup *paillierReceiverSetup) Receiver() sign_offline.MTAReceiver[*mta_paillier.Round1Output, *mta_paillier.Round2Output] {
+	if !setup.done {
+		return nil
+	}
+	return setup.receiver
+}
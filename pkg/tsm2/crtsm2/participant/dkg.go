@@ -0,0 +1,566 @@
+package participant
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/abort"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/dkg"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/mta"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+	"github.com/coinbase/kryptology/pkg/zkp/reg"
+	"github.com/coinbase/kryptology/pkg/zkp/rre"
+	"github.com/coinbase/kryptology/pkg/zkp/rspdl"
+	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+)
+
+// DKGPhase1Commitments is broadcast by every party in the first round of the DKG
+type DKGPhase1Commitments struct {
+	QCommitment schnorr.Commitment
+	TCommitment schnorr.Commitment
+}
+
+// DKGPhase1Proofs opens the commitments of the first round
+type DKGPhase1Proofs struct {
+	QProof *schnorr.Proof
+	TProof *schnorr.Proof
+}
+
+// DKGPhase4SigmaReveal reveals sigma_i together with the proof that it is the plaintext of the party's sigma ciphertext
+type DKGPhase4SigmaReveal struct {
+	Sigma         curves.Scalar
+	SigmaDDHProof *chaumpedersen.Proof
+}
+
+const (
+	dkgPhase1Commit = iota
+	dkgPhase1Decommit
+	dkgPhase2Encrypt
+	dkgPhase2Relate
+	dkgPhase3MtAInit
+	dkgPhase3MtAUpdate
+	dkgPhase3MtAMultiply
+	dkgPhase4Rerandomize
+	dkgPhase4RerandomizeDecommit
+	dkgPhase4DDHCommit
+	dkgPhase4DDHDecommit
+	dkgPhase4RevealSigma
+	dkgPhase4Finalize
+	dkgPhase5Deal
+	dkgPhase5Share
+	dkgPhase5Finalize
+	dkgDone
+)
+
+// labels of the DKG proofs and multiplications, which separate their session ids
+const (
+	dkgQLabel           = "DKG Q"
+	dkgTLabel           = "DKG T"
+	dkgGammaLabel       = "DKG gamma"
+	dkgXGammaLabel      = "DKG x*gamma"
+	dkgSigmaLabel       = "DKG sigma"
+	dkgRerandomizeLabel = "DKG re-randomization"
+	dkgDecryptLabel     = "DKG partial decryption"
+	dkgSigmaRevealLabel = "DKG sigma reveal"
+	dkgGammaLinkLabel   = "DKG gamma link"
+	dkgMtALabel         = "DKG MtA"
+)
+
+type dkgState[A any, B any] struct {
+	round int
+
+	QProof       *schnorr.Proof
+	TProof       *schnorr.Proof
+	QCommitments map[uint32]schnorr.Commitment
+	TCommitments map[uint32]schnorr.Commitment
+
+	rGamma           curves.Scalar
+	gammaRegProof    *reg.Proof
+	gammaRegProofs   map[uint32]*reg.Proof
+	xGammaRspdlProof *rspdl.Proof
+	UXGamma          curves.Point
+	VXGamma          curves.Point
+
+	alphas map[uint32]curves.Scalar
+
+	sigmaRegProof  *reg.Proof
+	sigmaRegProofs map[uint32]*reg.Proof
+
+	U              curves.Point
+	V              curves.Point
+	rreProof       *rre.Proof
+	rreCommitments map[uint32]rre.Commitment
+
+	UPrime         curves.Point
+	VPrime         curves.Point
+	ddhProof       *chaumpedersen.Proof
+	ddhCommitments map[uint32]chaumpedersen.Commitment
+
+	// the rounds of DKGPhase5, which only threshold participants run
+	sigma        curves.Scalar
+	dealtShares  map[uint32]*DKGPhase5Shares
+	dealing      *DKGPhase5Commitments
+	peerDealings map[uint32]*DKGPhase5Commitments
+}
+
+func (p *Participant[A, B]) dkgRound(round int) error {
+	if round == dkgPhase1Commit {
+		if p.dkg != nil || p.sigma != nil {
+			return fmt.Errorf("DKG has already been started")
+		}
+		if p.mtaSetup != nil {
+			return fmt.Errorf("the MtA setup has not completed")
+		}
+		p.dkg = &dkgState[A, B]{}
+		return nil
+	}
+	if p.dkg == nil || p.dkg.round != round {
+		return fmt.Errorf("DKG round %d called out of order", round)
+	}
+	return nil
+}
+
+// DKGPhase1Commit samples the SM2 key share x_i and the ElGamal key share d_i and commits to the proofs of knowledge of
+// Q_i = x_i * P and T_i = d_i * P. The output is broadcast.
+func (p *Participant[A, B]) DKGPhase1Commit() (*DKGPhase1Commitments, error) {
+	if err := p.dkgRound(dkgPhase1Commit); err != nil {
+		return nil, err
+	}
+	x, QProof, QCommitment, _, err := dkg.PkComProve(p.curve, p.proofSessionId(p.id, dkgQLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "proving knowledge of the key share")
+	}
+	d, TProof, TCommitment, _, err := dkg.PkComProve(p.curve, p.proofSessionId(p.id, dkgTLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "proving knowledge of the encryption key share")
+	}
+	p.x, p.d = x, d
+	p.dkg.QProof, p.dkg.TProof = QProof, TProof
+	p.dkg.round = dkgPhase1Decommit
+	return &DKGPhase1Commitments{QCommitment: QCommitment, TCommitment: TCommitment}, nil
+}
+
+// DKGPhase1Decommit stores the peers' commitments and opens our own. The output is broadcast.
+func (p *Participant[A, B]) DKGPhase1Decommit(commitments map[uint32]*DKGPhase1Commitments) (*DKGPhase1Proofs, error) {
+	if err := p.dkgRound(dkgPhase1Decommit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, commitments); err != nil {
+		return nil, err
+	}
+	p.dkg.QCommitments = make(map[uint32]schnorr.Commitment, len(p.peers))
+	p.dkg.TCommitments = make(map[uint32]schnorr.Commitment, len(p.peers))
+	for peer, commitment := range commitments {
+		if commitment == nil {
+			return nil, fmt.Errorf("missing commitments of party %d", peer)
+		}
+		p.dkg.QCommitments[peer] = commitment.QCommitment
+		p.dkg.TCommitments[peer] = commitment.TCommitment
+	}
+	p.dkg.round = dkgPhase2Encrypt
+	return &DKGPhase1Proofs{QProof: p.dkg.QProof, TProof: p.dkg.TProof}, nil
+}
+
+// DKGPhase2Encrypt verifies the opened proofs, computes Q and T, then samples gamma_i and encrypts it under T.
+// The output is broadcast.
+func (p *Participant[A, B]) DKGPhase2Encrypt(proofs map[uint32]*DKGPhase1Proofs) (*reg.Proof, error) {
+	if err := p.dkgRound(dkgPhase2Encrypt); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, proofs); err != nil {
+		return nil, err
+	}
+	p.QProofs = map[uint32]*schnorr.Proof{p.id: p.dkg.QProof}
+	p.TProofs = map[uint32]*schnorr.Proof{p.id: p.dkg.TProof}
+	p.Q, p.T = p.dkg.QProof.Statement, p.dkg.TProof.Statement
+	batch := make([]*schnorr.Proof, 0, 2*len(p.peers))
+	commitments := make([]schnorr.Commitment, 0, 2*len(p.peers))
+	sessionIds := make([][]byte, 0, 2*len(p.peers))
+	for _, peer := range p.peers {
+		proof := proofs[peer]
+		if proof == nil || proof.QProof == nil || proof.TProof == nil {
+			return nil, fmt.Errorf("missing proofs of party %d", peer)
+		}
+		batch = append(batch, proof.QProof, proof.TProof)
+		commitments = append(commitments, p.dkg.QCommitments[peer], p.dkg.TCommitments[peer])
+		sessionIds = append(sessionIds, p.proofSessionId(peer, dkgQLabel), p.proofSessionId(peer, dkgTLabel))
+	}
+	// the proofs are verified one by one only to find the culprit when the batch fails
+	batched := dkg.PkDeComBatchVerify(p.curve, batch, commitments, sessionIds) == nil
+	for peer, proof := range proofs {
+		if !batched {
+			QSessionId := p.proofSessionId(peer, dkgQLabel)
+			if err := dkg.PkDeComVerify(p.curve, proof.QProof, p.dkg.QCommitments[peer], QSessionId); err != nil {
+				return nil, abort.New(peer, "DKGPhase2Encrypt", &abort.Evidence{
+					Check:      abort.DkgPkDeCom,
+					Proof:      proof.QProof,
+					Commitment: p.dkg.QCommitments[peer],
+					SessionId:  QSessionId,
+				}, errors.Wrap(err, "verifying key share"))
+			}
+			TSessionId := p.proofSessionId(peer, dkgTLabel)
+			if err := dkg.PkDeComVerify(p.curve, proof.TProof, p.dkg.TCommitments[peer], TSessionId); err != nil {
+				return nil, abort.New(peer, "DKGPhase2Encrypt", &abort.Evidence{
+					Check:      abort.DkgPkDeCom,
+					Proof:      proof.TProof,
+					Commitment: p.dkg.TCommitments[peer],
+					SessionId:  TSessionId,
+				}, errors.Wrap(err, "verifying encryption key share"))
+			}
+		}
+		p.QProofs[peer], p.TProofs[peer] = proof.QProof, proof.TProof
+		p.Q = p.Q.Add(proof.QProof.Statement)
+		p.T = p.T.Add(proof.TProof.Statement)
+	}
+
+	gamma, rGamma, gammaRegProof, _, err := dkg.REGProve(p.curve, p.T, p.proofSessionId(p.id, dkgGammaLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypting gamma")
+	}
+	p.gamma = gamma
+	p.dkg.rGamma = rGamma
+	p.dkg.gammaRegProof = gammaRegProof
+	p.dkg.round = dkgPhase2Relate
+	return gammaRegProof, nil
+}
+
+// DKGPhase2Relate verifies the peers' encryptions of gamma_j, computes the encryption of gamma = sum(gamma_j) and
+// re-randomizes it with x_i. The output is broadcast.
+func (p *Participant[A, B]) DKGPhase2Relate(gammaRegProofs map[uint32]*reg.Proof) (*rspdl.Proof, error) {
+	if err := p.dkgRound(dkgPhase2Relate); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, gammaRegProofs); err != nil {
+		return nil, err
+	}
+	for peer, proof := range gammaRegProofs {
+		if proof == nil {
+			return nil, fmt.Errorf("missing gamma encryption of party %d", peer)
+		}
+		sessionId := p.proofSessionId(peer, dkgGammaLabel)
+		if err := dkg.REGVerify(p.curve, p.T, proof, sessionId); err != nil {
+			return nil, abort.New(peer, "DKGPhase2Relate", &abort.Evidence{
+				Check:     abort.DkgREG,
+				Proof:     proof,
+				SessionId: sessionId,
+				Inputs:    []curves.Point{p.T},
+			}, errors.Wrap(err, "verifying gamma encryption"))
+		}
+	}
+	p.dkg.gammaRegProofs = gammaRegProofs
+	p.UGamma, p.VGamma = sumRegProofs(p.dkg.gammaRegProof, gammaRegProofs)
+
+	rspdlProof, _, err := dkg.RSPDLProve(p.curve, p.T, p.UGamma, p.VGamma, p.QProofs[p.id].Statement, p.x, p.proofSessionId(p.id, dkgXGammaLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "re-randomizing the encryption of gamma")
+	}
+	p.dkg.xGammaRspdlProof = rspdlProof
+	p.dkg.round = dkgPhase3MtAInit
+	return rspdlProof, nil
+}
+
+// DKGPhase3MtAInit verifies the peers' re-randomizations, computes the encryption of x*gamma and starts one MtA per
+// peer on x_i. The output is point-to-point, keyed by the recipient.
+func (p *Participant[A, B]) DKGPhase3MtAInit(rspdlProofs map[uint32]*rspdl.Proof) (map[uint32]A, error) {
+	if err := p.dkgRound(dkgPhase3MtAInit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, rspdlProofs); err != nil {
+		return nil, err
+	}
+	p.dkg.UXGamma = p.dkg.xGammaRspdlProof.APrime
+	p.dkg.VXGamma = p.dkg.xGammaRspdlProof.BPrime
+	for peer, proof := range rspdlProofs {
+		if proof == nil {
+			return nil, fmt.Errorf("missing re-randomization of party %d", peer)
+		}
+		sessionId := p.proofSessionId(peer, dkgXGammaLabel)
+		if err := dkg.RSPDLVerify(p.curve, p.T, proof, p.UGamma, p.VGamma, p.QProofs[peer].Statement, sessionId); err != nil {
+			return nil, abort.New(peer, "DKGPhase3MtAInit", &abort.Evidence{
+				Check:     abort.DkgRSPDL,
+				Proof:     proof,
+				SessionId: sessionId,
+				Inputs:    []curves.Point{p.T, p.UGamma, p.VGamma, p.QProofs[peer].Statement},
+			}, errors.Wrap(err, "verifying re-randomization"))
+		}
+		p.dkg.UXGamma = p.dkg.UXGamma.Add(proof.APrime)
+		p.dkg.VXGamma = p.dkg.VXGamma.Add(proof.BPrime)
+	}
+
+	output := make(map[uint32]A, len(p.peers))
+	for _, peer := range p.peers {
+		a, err := mta.Init(p.mtaReceivers[peer], p.proofSessionId(peer, dkgMtALabel), p.x)
+		if err != nil {
+			return nil, errors.Wrapf(err, "starting the MtA with party %d", peer)
+		}
+		output[peer] = a
+	}
+	p.dkg.round = dkgPhase3MtAUpdate
+	return output, nil
+}
+
+// DKGPhase3MtAUpdate answers every peer's MtA on gamma_i. The output is point-to-point, keyed by the recipient.
+func (p *Participant[A, B]) DKGPhase3MtAUpdate(mtaInits map[uint32]A) (map[uint32]B, error) {
+	if err := p.dkgRound(dkgPhase3MtAUpdate); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, mtaInits); err != nil {
+		return nil, err
+	}
+	p.dkg.alphas = make(map[uint32]curves.Scalar, len(p.peers))
+	output := make(map[uint32]B, len(p.peers))
+	for _, peer := range p.peers {
+		alpha, b, err := mta.Update(p.mtaSenders[peer], p.proofSessionId(p.id, dkgMtALabel), p.gamma, mtaInits[peer])
+		if err != nil {
+			return nil, errors.Wrapf(err, "answering the MtA of party %d", peer)
+		}
+		p.dkg.alphas[peer], output[peer] = alpha, b
+	}
+	p.dkg.round = dkgPhase3MtAMultiply
+	return output, nil
+}
+
+// DKGPhase3MtAMultiply completes the MtAs, computes the additive share sigma_i of x*gamma and encrypts it under T.
+// The output is broadcast.
+func (p *Participant[A, B]) DKGPhase3MtAMultiply(mtaUpdates map[uint32]B) (*reg.Proof, error) {
+	if err := p.dkgRound(dkgPhase3MtAMultiply); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, mtaUpdates); err != nil {
+		return nil, err
+	}
+	sigma := p.gamma.Mul(p.x)
+	for _, peer := range p.peers {
+		beta, err := mta.Multiply(p.mtaReceivers[peer], mtaUpdates[peer])
+		if err != nil {
+			return nil, errors.Wrapf(err, "completing the MtA with party %d", peer)
+		}
+		sigma = sigma.Add(p.dkg.alphas[peer]).Add(beta)
+	}
+	p.sigmaI = sigma
+
+	rSigma, sigmaRegProof, _, err := dkg.SigmaREGProve(p.curve, p.T, p.sigmaI, p.proofSessionId(p.id, dkgSigmaLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypting sigma")
+	}
+	p.rSigma = rSigma
+	p.dkg.sigmaRegProof = sigmaRegProof
+	p.dkg.round = dkgPhase4Rerandomize
+	return sigmaRegProof, nil
+}
+
+// DKGPhase4Rerandomize verifies the peers' encryptions of sigma_j, computes the encryption (U, V) of
+// x*gamma - sigma and commits to a re-randomization of it. The output is broadcast.
+func (p *Participant[A, B]) DKGPhase4Rerandomize(sigmaRegProofs map[uint32]*reg.Proof) (rre.Commitment, error) {
+	if err := p.dkgRound(dkgPhase4Rerandomize); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, sigmaRegProofs); err != nil {
+		return nil, err
+	}
+	for peer, proof := range sigmaRegProofs {
+		if proof == nil {
+			return nil, fmt.Errorf("missing sigma encryption of party %d", peer)
+		}
+		sessionId := p.proofSessionId(peer, dkgSigmaLabel)
+		if err := dkg.SigmaREGVerify(p.curve, p.T, proof, sessionId); err != nil {
+			return nil, abort.New(peer, "DKGPhase4Rerandomize", &abort.Evidence{
+				Check:     abort.DkgSigmaREG,
+				Proof:     proof,
+				SessionId: sessionId,
+				Inputs:    []curves.Point{p.T},
+			}, errors.Wrap(err, "verifying sigma encryption"))
+		}
+	}
+	p.dkg.sigmaRegProofs = sigmaRegProofs
+	USigma, VSigma := sumRegProofs(p.dkg.sigmaRegProof, sigmaRegProofs)
+	p.dkg.U = p.dkg.UXGamma.Sub(USigma)
+	p.dkg.V = p.dkg.VXGamma.Sub(VSigma)
+
+	rreProof, rreCommitment, _, err := dkg.RREComProve(p.curve, p.T, p.dkg.U, p.dkg.V, p.proofSessionId(p.id, dkgRerandomizeLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "re-randomizing (U, V)")
+	}
+	p.dkg.rreProof = rreProof
+	p.dkg.round = dkgPhase4RerandomizeDecommit
+	return rreCommitment, nil
+}
+
+// DKGPhase4RerandomizeDecommit stores the peers' commitments and opens our re-randomization. The output is broadcast.
+func (p *Participant[A, B]) DKGPhase4RerandomizeDecommit(rreCommitments map[uint32]rre.Commitment) (*rre.Proof, error) {
+	if err := p.dkgRound(dkgPhase4RerandomizeDecommit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, rreCommitments); err != nil {
+		return nil, err
+	}
+	p.dkg.rreCommitments = rreCommitments
+	p.dkg.round = dkgPhase4DDHCommit
+	return p.dkg.rreProof, nil
+}
+
+// DKGPhase4DDHCommit verifies the peers' re-randomizations, computes (U', V') and commits to the proof that
+// U'_i = d_i * U' is our partial decryption. The output is broadcast.
+func (p *Participant[A, B]) DKGPhase4DDHCommit(rreProofs map[uint32]*rre.Proof) (chaumpedersen.Commitment, error) {
+	if err := p.dkgRound(dkgPhase4DDHCommit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, rreProofs); err != nil {
+		return nil, err
+	}
+	p.dkg.UPrime, p.dkg.VPrime = p.dkg.rreProof.APrime, p.dkg.rreProof.BPrime
+	for peer, proof := range rreProofs {
+		if proof == nil {
+			return nil, fmt.Errorf("missing re-randomization of party %d", peer)
+		}
+		sessionId := p.proofSessionId(peer, dkgRerandomizeLabel)
+		if err := dkg.RREDeComVerify(p.curve, proof, p.dkg.rreCommitments[peer], sessionId, p.T, p.dkg.U, p.dkg.V); err != nil {
+			return nil, abort.New(peer, "DKGPhase4DDHCommit", &abort.Evidence{
+				Check:      abort.DkgRREDeCom,
+				Proof:      proof,
+				Commitment: p.dkg.rreCommitments[peer],
+				SessionId:  sessionId,
+				Inputs:     []curves.Point{p.T, p.dkg.U, p.dkg.V},
+			}, errors.Wrap(err, "verifying re-randomization"))
+		}
+		p.dkg.UPrime = p.dkg.UPrime.Add(proof.APrime)
+		p.dkg.VPrime = p.dkg.VPrime.Add(proof.BPrime)
+	}
+
+	UPrimeI := p.dkg.UPrime.Mul(p.d)
+	ddhProof, ddhCommitment, _, err := dkg.DDHComProve(p.curve, p.P, p.dkg.UPrime, p.TProofs[p.id].Statement, UPrimeI, p.d, p.proofSessionId(p.id, dkgDecryptLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "proving partial decryption")
+	}
+	p.dkg.ddhProof = ddhProof
+	p.dkg.round = dkgPhase4DDHDecommit
+	return ddhCommitment, nil
+}
+
+// DKGPhase4DDHDecommit stores the peers' commitments and opens our partial decryption. The output is broadcast.
+func (p *Participant[A, B]) DKGPhase4DDHDecommit(ddhCommitments map[uint32]chaumpedersen.Commitment) (*chaumpedersen.Proof, error) {
+	if err := p.dkgRound(dkgPhase4DDHDecommit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, ddhCommitments); err != nil {
+		return nil, err
+	}
+	p.dkg.ddhCommitments = ddhCommitments
+	p.dkg.round = dkgPhase4RevealSigma
+	return p.dkg.ddhProof, nil
+}
+
+// DKGPhase4RevealSigma checks that (U', V') decrypts to zero, i.e. that sum(sigma_j) = x*gamma, and then reveals
+// sigma_i with a proof that it is the plaintext of our sigma ciphertext. The output is broadcast.
+func (p *Participant[A, B]) DKGPhase4RevealSigma(ddhProofs map[uint32]*chaumpedersen.Proof) (*DKGPhase4SigmaReveal, error) {
+	if err := p.dkgRound(dkgPhase4RevealSigma); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, ddhProofs); err != nil {
+		return nil, err
+	}
+	sumUPrimes := p.dkg.ddhProof.Statement2
+	batch := make([]*chaumpedersen.Proof, 0, len(p.peers))
+	commitments := make([]chaumpedersen.Commitment, 0, len(p.peers))
+	sessionIds := make([][]byte, 0, len(p.peers))
+	for _, peer := range p.peers {
+		if ddhProofs[peer] == nil {
+			return nil, fmt.Errorf("missing partial decryption of party %d", peer)
+		}
+		batch = append(batch, ddhProofs[peer])
+		commitments = append(commitments, p.dkg.ddhCommitments[peer])
+		sessionIds = append(sessionIds, p.proofSessionId(peer, dkgDecryptLabel))
+	}
+	// the proofs are verified one by one only to find the culprit when the batch fails
+	batched := dkg.DDHDeComBatchVerify(p.curve, batch, commitments, sessionIds, p.P, p.dkg.UPrime) == nil
+	for peer, proof := range ddhProofs {
+		sessionId := p.proofSessionId(peer, dkgDecryptLabel)
+		if !batched {
+			if err := dkg.DDHDeComVerify(p.curve, proof, p.dkg.ddhCommitments[peer], sessionId, p.P, p.dkg.UPrime); err != nil {
+				return nil, abort.New(peer, "DKGPhase4RevealSigma", &abort.Evidence{
+					Check:      abort.DkgDDHDeCom,
+					Proof:      proof,
+					Commitment: p.dkg.ddhCommitments[peer],
+					SessionId:  sessionId,
+					Inputs:     []curves.Point{p.P, p.dkg.UPrime},
+				}, errors.Wrap(err, "verifying partial decryption"))
+			}
+		}
+		if err := dkg.DDHKeyVerify(proof, p.TProofs[peer].Statement); err != nil {
+			return nil, abort.New(peer, "DKGPhase4RevealSigma", &abort.Evidence{
+				Check:      abort.DkgDDHKey,
+				Proof:      proof,
+				Commitment: p.dkg.ddhCommitments[peer],
+				SessionId:  sessionId,
+				Inputs:     []curves.Point{p.TProofs[peer].Statement},
+			}, err)
+		}
+		sumUPrimes = sumUPrimes.Add(proof.Statement2)
+	}
+	if !sumUPrimes.Equal(p.dkg.VPrime) {
+		return nil, fmt.Errorf("failed when verifying sum of U'_i")
+	}
+
+	VSigmaPrime := p.dkg.sigmaRegProof.B.Sub(p.P.Mul(p.sigmaI))
+	sigmaDDHProof, _, err := dkg.SigmaDDHProve(p.curve, p.P, p.T, p.dkg.sigmaRegProof.A, VSigmaPrime, p.rSigma, p.proofSessionId(p.id, dkgSigmaRevealLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "proving the encryption of sigma")
+	}
+	p.dkg.round = dkgPhase4Finalize
+	return &DKGPhase4SigmaReveal{Sigma: p.sigmaI, SigmaDDHProof: sigmaDDHProof}, nil
+}
+
+// DKGPhase4Finalize verifies the revealed sigma_j and computes sigma = x*gamma, which completes the DKG.
+// A threshold participant continues with DKGPhase5Deal instead, and the output is nil.
+func (p *Participant[A, B]) DKGPhase4Finalize(reveals map[uint32]*DKGPhase4SigmaReveal) (*DKGOutput, error) {
+	if err := p.dkgRound(dkgPhase4Finalize); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, reveals); err != nil {
+		return nil, err
+	}
+	sigma := p.sigmaI
+	for peer, reveal := range reveals {
+		if reveal == nil || reveal.Sigma == nil || reveal.SigmaDDHProof == nil {
+			return nil, fmt.Errorf("missing sigma of party %d", peer)
+		}
+		sigmaRegProof := p.dkg.sigmaRegProofs[peer]
+		sessionId := p.proofSessionId(peer, dkgSigmaRevealLabel)
+		if err := dkg.SigmaDDHVerify(p.curve, reveal.SigmaDDHProof, sessionId, p.P, p.T); err != nil {
+			return nil, abort.New(peer, "DKGPhase4Finalize", &abort.Evidence{
+				Check:     abort.DkgSigmaDDH,
+				Proof:     reveal.SigmaDDHProof,
+				SessionId: sessionId,
+				Inputs:    []curves.Point{p.P, p.T},
+			}, errors.Wrap(err, "verifying sigma"))
+		}
+		SigmaI := p.P.Mul(reveal.Sigma)
+		if err := dkg.SigmaRevealVerify(reveal.SigmaDDHProof, sigmaRegProof.A, sigmaRegProof.B, SigmaI); err != nil {
+			return nil, abort.New(peer, "DKGPhase4Finalize", &abort.Evidence{
+				Check:     abort.DkgSigmaRev,
+				Proof:     reveal.SigmaDDHProof,
+				SessionId: sessionId,
+				Inputs:    []curves.Point{sigmaRegProof.A, sigmaRegProof.B, SigmaI},
+			}, errors.Wrap(err, "verifying sigma"))
+		}
+		sigma = sigma.Add(reveal.Sigma)
+	}
+	if sigma.IsZero() {
+		return nil, fmt.Errorf("sigma is zero")
+	}
+	if p.t > 0 {
+		p.dkg.sigma = sigma
+		p.dkg.round = dkgPhase5Deal
+		return nil, nil
+	}
+	p.sigma = sigma
+	p.signers, p.signGamma, p.signD = p.peers, p.gamma, p.d
+	p.signTs = make(map[uint32]curves.Point, len(p.TProofs))
+	for id, proof := range p.TProofs {
+		p.signTs[id] = proof.Statement
+	}
+	p.dkg = nil
+	return p.DKGOutput(), nil
+}
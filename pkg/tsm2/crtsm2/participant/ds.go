@@ -0,0 +1,474 @@
+package participant
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/abort"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/ds"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/mta"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/verify"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+	"github.com/coinbase/kryptology/pkg/zkp/reg"
+	"github.com/coinbase/kryptology/pkg/zkp/rre"
+	"github.com/coinbase/kryptology/pkg/zkp/rspdl"
+	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+)
+
+const (
+	dsPhase1Commit = iota
+	dsPhase1Decommit
+	dsPhase2
+	dsPhase3MtAInit
+	dsPhase3MtAUpdate
+	dsPhase3MtAMultiply
+	dsPhase4Rerandomize
+	dsPhase4RerandomizeDecommit
+	dsPhase4DDHCommit
+	dsPhase4DDHDecommit
+	dsPhase5PartialSign
+	dsPhase5Finalize
+)
+
+// labels of the DS proofs and multiplications, which separate their session ids
+const (
+	dsNonceLabel       = "DS nonce"
+	dsKGammaLabel      = "DS k*gamma"
+	dsDeltaLabel       = "DS delta"
+	dsRerandomizeLabel = "DS re-randomization"
+	dsDecryptLabel     = "DS partial decryption"
+	dsMtALabel         = "DS MtA"
+)
+
+type signState[A any, B any] struct {
+	round     int
+	message   []byte
+	sessionId []byte
+
+	k            curves.Scalar
+	kProof       *schnorr.Proof
+	kCommitment  schnorr.Commitment
+	kCommitments map[uint32]schnorr.Commitment
+	kProofs      map[uint32]*schnorr.Proof
+	R            curves.Point
+	rx           curves.Scalar
+
+	sRanCTGammaProof *rspdl.Proof
+	AKGamma          curves.Point
+	BKGamma          curves.Point
+
+	mus   map[uint32]curves.Scalar
+	delta curves.Scalar
+
+	deltaEGProof *reg.Proof
+	A            curves.Point
+	B            curves.Point
+
+	abREProof       *rre.Proof
+	abRECommitments map[uint32]rre.Commitment
+	APrime          curves.Point
+	BPrime          curves.Point
+
+	abDDHProof       *chaumpedersen.Proof
+	abDDHCommitments map[uint32]chaumpedersen.Commitment
+
+	r curves.Scalar
+	s curves.Scalar
+}
+
+func (p *Participant[A, B]) signRound(round int) error {
+	if round == dsPhase1Commit {
+		if p.sigma == nil {
+			return fmt.Errorf("DKG has not completed")
+		}
+		if p.signGamma == nil {
+			return fmt.Errorf("no signers selected")
+		}
+		p.sign = &signState[A, B]{}
+		return nil
+	}
+	if p.sign == nil || p.sign.round != round {
+		return fmt.Errorf("DS round %d called out of order", round)
+	}
+	return nil
+}
+
+// signProofSessionId returns the session id of the DS proof labeled label that the party sender produces
+func (p *Participant[A, B]) signProofSessionId(sender uint32, label string) []byte {
+	return parties.ProofSessionId(p.sign.sessionId, sender, label)
+}
+
+// DSPhase1Commit starts signing message: it samples the nonce share k_i and commits to the proof of knowledge of
+// R_i = k_i * P. Starting a new signature abandons any signature in progress. The output is broadcast.
+// After a threshold DKG, only the parties selected with SetSigners take part, and the DS rounds use their shares of
+// gamma and d weighted by their Lagrange coefficients.
+func (p *Participant[A, B]) DSPhase1Commit(message []byte) (schnorr.Commitment, error) {
+	if err := p.signRound(dsPhase1Commit); err != nil {
+		return nil, err
+	}
+	k, kProof, kCommitment, _, err := ds.NonceComProve(p.curve, p.proofSessionId(p.id, dsNonceLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "proving knowledge of the nonce share")
+	}
+	p.sign.message = message
+	p.sign.k, p.sign.kProof, p.sign.kCommitment = k, kProof, kCommitment
+	p.sign.round = dsPhase1Decommit
+	return kCommitment, nil
+}
+
+// DSPhase1Decommit stores the peers' commitments and opens our own. The output is broadcast.
+func (p *Participant[A, B]) DSPhase1Decommit(kCommitments map[uint32]schnorr.Commitment) (*schnorr.Proof, error) {
+	if err := p.signRound(dsPhase1Decommit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.signers, kCommitments); err != nil {
+		return nil, err
+	}
+	p.sign.kCommitments = kCommitments
+	contributions := map[uint32][]byte{p.id: p.sign.kCommitment}
+	for peer, commitment := range kCommitments {
+		contributions[peer] = commitment
+	}
+	// the commitments to the nonce proofs are fresh for every signature, hence so is the session id derived from them
+	p.sign.sessionId = parties.SubSessionId(p.sessionId, "CRT-SM2 DS", contributions)
+	p.sign.round = dsPhase2
+	return p.sign.kProof, nil
+}
+
+// DSPhase2 verifies the opened nonce proofs, computes R and its x-coordinate and re-randomizes the encryption of gamma
+// with k_i. The output is broadcast.
+func (p *Participant[A, B]) DSPhase2(kProofs map[uint32]*schnorr.Proof) (*rspdl.Proof, error) {
+	if err := p.signRound(dsPhase2); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.signers, kProofs); err != nil {
+		return nil, err
+	}
+	p.sign.R = p.sign.kProof.Statement
+	batch := make([]*schnorr.Proof, 0, len(p.signers))
+	commitments := make([]schnorr.Commitment, 0, len(p.signers))
+	sessionIds := make([][]byte, 0, len(p.signers))
+	for _, peer := range p.signers {
+		if kProofs[peer] == nil {
+			return nil, fmt.Errorf("missing nonce proof of party %d", peer)
+		}
+		batch = append(batch, kProofs[peer])
+		commitments = append(commitments, p.sign.kCommitments[peer])
+		sessionIds = append(sessionIds, p.proofSessionId(peer, dsNonceLabel))
+	}
+	// the proofs are verified one by one only to find the culprit when the batch fails
+	batched := ds.NonceDeComBatchVerify(p.curve, batch, commitments, sessionIds) == nil
+	for peer, proof := range kProofs {
+		if !batched {
+			sessionId := p.proofSessionId(peer, dsNonceLabel)
+			if err := ds.NonceDeComVerify(p.curve, proof, p.sign.kCommitments[peer], sessionId); err != nil {
+				return nil, abort.New(peer, "DSPhase2", &abort.Evidence{
+					Check:      abort.DsNonceDeCom,
+					Proof:      proof,
+					Commitment: p.sign.kCommitments[peer],
+					SessionId:  sessionId,
+				}, errors.Wrap(err, "verifying nonce share"))
+			}
+		}
+		p.sign.R = p.sign.R.Add(proof.Statement)
+	}
+	p.sign.kProofs = kProofs
+	if p.sign.R.IsIdentity() {
+		return nil, fmt.Errorf("R is the identity")
+	}
+	rx, err := sm2.XCoordinate(p.curve, p.sign.R)
+	if err != nil {
+		return nil, fmt.Errorf("failed when computing x-coordinate of R")
+	}
+	p.sign.rx = rx
+
+	sRanCTGammaProof, _, err := ds.SRanCTGammaProve(p.curve, p.T, p.UGamma, p.VGamma, p.sign.kProof.Statement, p.sign.k, p.signProofSessionId(p.id, dsKGammaLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "re-randomizing the encryption of gamma")
+	}
+	p.sign.sRanCTGammaProof = sRanCTGammaProof
+	p.sign.round = dsPhase3MtAInit
+	return sRanCTGammaProof, nil
+}
+
+// DSPhase3MtAInit verifies the peers' re-randomizations, computes the encryption of k*gamma and starts one MtA per
+// peer on k_i. The output is point-to-point, keyed by the recipient.
+func (p *Participant[A, B]) DSPhase3MtAInit(sRanCTGammaProofs map[uint32]*rspdl.Proof) (map[uint32]A, error) {
+	if err := p.signRound(dsPhase3MtAInit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.signers, sRanCTGammaProofs); err != nil {
+		return nil, err
+	}
+	p.sign.AKGamma = p.sign.sRanCTGammaProof.APrime
+	p.sign.BKGamma = p.sign.sRanCTGammaProof.BPrime
+	for peer, proof := range sRanCTGammaProofs {
+		if proof == nil {
+			return nil, fmt.Errorf("missing re-randomization of party %d", peer)
+		}
+		sessionId := p.signProofSessionId(peer, dsKGammaLabel)
+		if err := ds.SRanCTGammaVerify(p.curve, p.T, proof, p.UGamma, p.VGamma, p.sign.kProofs[peer].Statement, sessionId); err != nil {
+			return nil, abort.New(peer, "DSPhase3MtAInit", &abort.Evidence{
+				Check:     abort.DsSRanCTGamma,
+				Proof:     proof,
+				SessionId: sessionId,
+				Inputs:    []curves.Point{p.T, p.UGamma, p.VGamma, p.sign.kProofs[peer].Statement},
+			}, errors.Wrap(err, "verifying re-randomization"))
+		}
+		p.sign.AKGamma = p.sign.AKGamma.Add(proof.APrime)
+		p.sign.BKGamma = p.sign.BKGamma.Add(proof.BPrime)
+	}
+
+	output := make(map[uint32]A, len(p.signers))
+	for _, peer := range p.signers {
+		a, err := mta.Init(p.mtaReceivers[peer], p.signProofSessionId(peer, dsMtALabel), p.sign.k)
+		if err != nil {
+			return nil, errors.Wrapf(err, "starting the MtA with party %d", peer)
+		}
+		output[peer] = a
+	}
+	p.sign.round = dsPhase3MtAUpdate
+	return output, nil
+}
+
+// DSPhase3MtAUpdate answers every peer's MtA on gamma_i. The output is point-to-point, keyed by the recipient.
+func (p *Participant[A, B]) DSPhase3MtAUpdate(mtaInits map[uint32]A) (map[uint32]B, error) {
+	if err := p.signRound(dsPhase3MtAUpdate); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.signers, mtaInits); err != nil {
+		return nil, err
+	}
+	p.sign.mus = make(map[uint32]curves.Scalar, len(p.signers))
+	output := make(map[uint32]B, len(p.signers))
+	for _, peer := range p.signers {
+		mu, b, err := mta.Update(p.mtaSenders[peer], p.signProofSessionId(p.id, dsMtALabel), p.signGamma, mtaInits[peer])
+		if err != nil {
+			return nil, errors.Wrapf(err, "answering the MtA of party %d", peer)
+		}
+		p.sign.mus[peer], output[peer] = mu, b
+	}
+	p.sign.round = dsPhase3MtAMultiply
+	return output, nil
+}
+
+// DSPhase3MtAMultiply completes the MtAs, computes the additive share delta_i of k*gamma and encrypts it under T.
+// The output is broadcast.
+func (p *Participant[A, B]) DSPhase3MtAMultiply(mtaUpdates map[uint32]B) (*reg.Proof, error) {
+	if err := p.signRound(dsPhase3MtAMultiply); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.signers, mtaUpdates); err != nil {
+		return nil, err
+	}
+	delta := p.signGamma.Mul(p.sign.k)
+	for _, peer := range p.signers {
+		nu, err := mta.Multiply(p.mtaReceivers[peer], mtaUpdates[peer])
+		if err != nil {
+			return nil, errors.Wrapf(err, "completing the MtA with party %d", peer)
+		}
+		delta = delta.Add(p.sign.mus[peer].Add(nu))
+	}
+	p.sign.delta = delta
+
+	deltaEGProof, _, err := ds.DeltaEGProve(p.curve, p.T, delta, p.signProofSessionId(p.id, dsDeltaLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypting delta")
+	}
+	p.sign.deltaEGProof = deltaEGProof
+	p.sign.round = dsPhase4Rerandomize
+	return deltaEGProof, nil
+}
+
+// DSPhase4Rerandomize verifies the peers' encryptions of delta_j, computes the encryption (A, B) of
+// k*gamma - delta and commits to a re-randomization of it. The output is broadcast.
+func (p *Participant[A, B]) DSPhase4Rerandomize(deltaEGProofs map[uint32]*reg.Proof) (rre.Commitment, error) {
+	if err := p.signRound(dsPhase4Rerandomize); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.signers, deltaEGProofs); err != nil {
+		return nil, err
+	}
+	for peer, proof := range deltaEGProofs {
+		if proof == nil {
+			return nil, fmt.Errorf("missing delta encryption of party %d", peer)
+		}
+		sessionId := p.signProofSessionId(peer, dsDeltaLabel)
+		if err := ds.DeltaEGVerify(p.curve, p.T, proof, sessionId); err != nil {
+			return nil, abort.New(peer, "DSPhase4Rerandomize", &abort.Evidence{
+				Check:     abort.DsDeltaEG,
+				Proof:     proof,
+				SessionId: sessionId,
+				Inputs:    []curves.Point{p.T},
+			}, errors.Wrap(err, "verifying delta encryption"))
+		}
+	}
+	ADelta, BDelta := sumRegProofs(p.sign.deltaEGProof, deltaEGProofs)
+	p.sign.A = p.sign.AKGamma.Sub(ADelta)
+	p.sign.B = p.sign.BKGamma.Sub(BDelta)
+
+	reProof, reCommitment, _, err := ds.REComProve(p.curve, p.T, p.sign.A, p.sign.B, p.signProofSessionId(p.id, dsRerandomizeLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "re-randomizing (A, B)")
+	}
+	p.sign.abREProof = reProof
+	p.sign.round = dsPhase4RerandomizeDecommit
+	return reCommitment, nil
+}
+
+// DSPhase4RerandomizeDecommit stores the peers' commitments and opens our re-randomization. The output is broadcast.
+func (p *Participant[A, B]) DSPhase4RerandomizeDecommit(reCommitments map[uint32]rre.Commitment) (*rre.Proof, error) {
+	if err := p.signRound(dsPhase4RerandomizeDecommit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.signers, reCommitments); err != nil {
+		return nil, err
+	}
+	p.sign.abRECommitments = reCommitments
+	p.sign.round = dsPhase4DDHCommit
+	return p.sign.abREProof, nil
+}
+
+// DSPhase4DDHCommit verifies the peers' re-randomizations, computes (A', B') and commits to the proof that
+// A'_i = d_i * A' is our partial decryption. The output is broadcast.
+func (p *Participant[A, B]) DSPhase4DDHCommit(reProofs map[uint32]*rre.Proof) (chaumpedersen.Commitment, error) {
+	if err := p.signRound(dsPhase4DDHCommit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.signers, reProofs); err != nil {
+		return nil, err
+	}
+	p.sign.APrime, p.sign.BPrime = p.sign.abREProof.APrime, p.sign.abREProof.BPrime
+	for peer, proof := range reProofs {
+		if proof == nil {
+			return nil, fmt.Errorf("missing re-randomization of party %d", peer)
+		}
+		sessionId := p.signProofSessionId(peer, dsRerandomizeLabel)
+		if err := ds.REDeComVerify(p.curve, proof, p.sign.abRECommitments[peer], sessionId, p.T, p.sign.A, p.sign.B); err != nil {
+			return nil, abort.New(peer, "DSPhase4DDHCommit", &abort.Evidence{
+				Check:      abort.DsREDeCom,
+				Proof:      proof,
+				Commitment: p.sign.abRECommitments[peer],
+				SessionId:  sessionId,
+				Inputs:     []curves.Point{p.T, p.sign.A, p.sign.B},
+			}, errors.Wrap(err, "verifying re-randomization"))
+		}
+		p.sign.APrime = p.sign.APrime.Add(proof.APrime)
+		p.sign.BPrime = p.sign.BPrime.Add(proof.BPrime)
+	}
+
+	APrimeI := p.sign.APrime.Mul(p.signD)
+	ddhProof, ddhCommitment, _, err := ds.DDHComProve(p.curve, p.P, p.sign.APrime, p.signTs[p.id], APrimeI, p.signD, p.signProofSessionId(p.id, dsDecryptLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "proving partial decryption")
+	}
+	p.sign.abDDHProof = ddhProof
+	p.sign.round = dsPhase4DDHDecommit
+	return ddhCommitment, nil
+}
+
+// DSPhase4DDHDecommit stores the peers' commitments and opens our partial decryption. The output is broadcast.
+func (p *Participant[A, B]) DSPhase4DDHDecommit(ddhCommitments map[uint32]chaumpedersen.Commitment) (*chaumpedersen.Proof, error) {
+	if err := p.signRound(dsPhase4DDHDecommit); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.signers, ddhCommitments); err != nil {
+		return nil, err
+	}
+	p.sign.abDDHCommitments = ddhCommitments
+	p.sign.round = dsPhase5PartialSign
+	return p.sign.abDDHProof, nil
+}
+
+// DSPhase5PartialSign checks that (A', B') decrypts to zero, i.e. that sum(delta_j) = k*gamma, and computes the
+// partial signature s_i = sigma^-1 * (gamma_i * r + delta_i). The output is broadcast.
+func (p *Participant[A, B]) DSPhase5PartialSign(ddhProofs map[uint32]*chaumpedersen.Proof) (curves.Scalar, error) {
+	if err := p.signRound(dsPhase5PartialSign); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.signers, ddhProofs); err != nil {
+		return nil, err
+	}
+	sumAPrimes := p.sign.abDDHProof.Statement2
+	batch := make([]*chaumpedersen.Proof, 0, len(p.signers))
+	commitments := make([]chaumpedersen.Commitment, 0, len(p.signers))
+	sessionIds := make([][]byte, 0, len(p.signers))
+	for _, peer := range p.signers {
+		if ddhProofs[peer] == nil {
+			return nil, fmt.Errorf("missing partial decryption of party %d", peer)
+		}
+		batch = append(batch, ddhProofs[peer])
+		commitments = append(commitments, p.sign.abDDHCommitments[peer])
+		sessionIds = append(sessionIds, p.signProofSessionId(peer, dsDecryptLabel))
+	}
+	// the proofs are verified one by one only to find the culprit when the batch fails
+	batched := ds.DDHDeComBatchVerify(p.curve, batch, commitments, sessionIds, p.P, p.sign.APrime) == nil
+	for peer, proof := range ddhProofs {
+		sessionId := p.signProofSessionId(peer, dsDecryptLabel)
+		if !batched {
+			if err := ds.DDHDeComVerify(p.curve, proof, p.sign.abDDHCommitments[peer], sessionId, p.P, p.sign.APrime); err != nil {
+				return nil, abort.New(peer, "DSPhase5PartialSign", &abort.Evidence{
+					Check:      abort.DsDDHDeCom,
+					Proof:      proof,
+					Commitment: p.sign.abDDHCommitments[peer],
+					SessionId:  sessionId,
+					Inputs:     []curves.Point{p.P, p.sign.APrime},
+				}, errors.Wrap(err, "verifying partial decryption"))
+			}
+		}
+		if err := ds.DDHKeyVerify(proof, p.signTs[peer]); err != nil {
+			return nil, abort.New(peer, "DSPhase5PartialSign", &abort.Evidence{
+				Check:      abort.DsDDHKey,
+				Proof:      proof,
+				Commitment: p.sign.abDDHCommitments[peer],
+				SessionId:  sessionId,
+				Inputs:     []curves.Point{p.signTs[peer]},
+			}, err)
+		}
+		sumAPrimes = sumAPrimes.Add(proof.Statement2)
+	}
+	if !sumAPrimes.Equal(p.sign.BPrime) {
+		return nil, fmt.Errorf("failed when verifying DDH relation")
+	}
+
+	h, err := sm2.Digest(p.curve, p.signerId, p.Q.Sub(p.P), p.sign.message)
+	if err != nil {
+		return nil, err
+	}
+	p.sign.r = p.sign.rx.Add(h)
+	sigmaInvert, err := p.sigma.Invert()
+	if err != nil {
+		return nil, fmt.Errorf("failed in computing the inverse of sigma")
+	}
+	s := sigmaInvert.Mul(p.signGamma.Mul(p.sign.r).Add(p.sign.delta))
+	p.sign.s = s
+	p.sign.round = dsPhase5Finalize
+	return s, nil
+}
+
+// DSPhase5Finalize adds up the partial signatures and verifies the final signature
+func (p *Participant[A, B]) DSPhase5Finalize(ss map[uint32]curves.Scalar) (*Signature, error) {
+	if err := p.signRound(dsPhase5Finalize); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.signers, ss); err != nil {
+		return nil, err
+	}
+	s := p.sign.s
+	for peer, si := range ss {
+		if si == nil {
+			return nil, fmt.Errorf("missing partial signature of party %d", peer)
+		}
+		s = s.Add(si)
+	}
+	if err := verify.Verify(p.curve, p.P, p.Q, p.signerId, p.sign.message, p.sign.r, s); err != nil {
+		return nil, err
+	}
+	signature := &Signature{R: p.sign.r, S: s}
+	p.sign = nil
+	return signature, nil
+}
@@ -0,0 +1,211 @@
+// Package participant runs one party of the CRT-SM2 threshold signature scheme.
+//
+// Unlike scheme.Scheme, which simulates every party inside a single struct, a Participant only ever holds its own
+// secrets. The DKG and DS phases are split into rounds; each round consumes the messages the peers produced in the
+// previous round, keyed by the sender's identifier, and emits either a single broadcast message or one point-to-point
+// message per peer. The iterators in protocol.go wrap the rounds into the core/protocol.Iterator pattern so that the
+// parties can run in separate processes.
+//
+// By default every party signs. A participant on which SetThreshold is called runs the rounds of DKGPhase5 as well,
+// which deal Feldman shares of its secrets, so that any t+1 parties selected with SetSigners can sign.
+package participant
+
+import (
+	"fmt"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/sign_offline"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/mta"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
+	"github.com/coinbase/kryptology/pkg/zkp/reg"
+	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+)
+
+// Participant is a single party of the CRT-SM2 scheme.
+// It owns one MtA sender and one MtA receiver per peer: the sender multiplies our gamma with the peer's secret and the
+// receiver multiplies our secret with the peer's gamma. The peer must hold the matching receiver and sender.
+type Participant[A any, B any] struct {
+	curve     *curves.Curve
+	P         curves.Point
+	signerId  []byte
+	sessionId []byte
+
+	id    uint32
+	peers []uint32
+
+	mtaSenders   map[uint32]sign_offline.MTASender[A, B]
+	mtaReceivers map[uint32]sign_offline.MTAReceiver[A, B]
+	mtaSetup     *mtaSetupState[A, B]
+
+	// own secrets, which are Shamir shares after a threshold DKG
+	x      curves.Scalar
+	d      curves.Scalar
+	gamma  curves.Scalar
+	sigmaI curves.Scalar
+	rSigma curves.Scalar
+
+	// public values
+	QProofs map[uint32]*schnorr.Proof
+	TProofs map[uint32]*schnorr.Proof
+	Q       curves.Point
+	T       curves.Point
+	UGamma  curves.Point
+	VGamma  curves.Point
+	sigma   curves.Scalar
+
+	// threshold sharing, see SetThreshold
+	t       int
+	QShares map[uint32]curves.Point
+	TShares map[uint32]curves.Point
+
+	// the peers that sign with us, our weighted shares of gamma and d and the public keys of the weighted shares of d,
+	// see SetSigners
+	signers   []uint32
+	signGamma curves.Scalar
+	signD     curves.Scalar
+	signTs    map[uint32]curves.Point
+
+	dkg  *dkgState[A, B]
+	sign *signState[A, B]
+}
+
+// DKGOutput is the public result of the DKG
+type DKGOutput struct {
+	// Q is the joint public key in the CRT form, as taken by verify.Verify
+	Q curves.Point
+	// PublicKey is the standard SM2 public key Q - P
+	PublicKey curves.Point
+	// T is the joint ElGamal encryption key
+	T curves.Point
+}
+
+// Signature is a signature in the CRT form checked by verify.Verify.
+// verify.ToStandard converts it to a standard SM2 signature.
+type Signature struct {
+	R curves.Scalar
+	S curves.Scalar
+}
+
+// NewParticipant creates the party id of a session among ids.
+// mtaSenders and mtaReceivers hold the pairwise MtA instances keyed by peer identifier; their setup happens out of band.
+// sessionId must be the same for all parties and unique to the session; the session ids of the proofs are derived from
+// it. A nil signerId selects the default distinguishing identifier.
+func NewParticipant[A any, B any](curve *curves.Curve, id uint32, ids []uint32, signerId []byte, mtaSenders map[uint32]sign_offline.MTASender[A, B], mtaReceivers map[uint32]sign_offline.MTAReceiver[A, B], sessionId []byte) (*Participant[A, B], error) {
+	p, err := newParticipant[A, B](curve, id, ids, signerId, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	for _, peer := range p.peers {
+		if mtaSenders[peer] == nil || mtaReceivers[peer] == nil {
+			return nil, fmt.Errorf("missing MtA instances for peer %d", peer)
+		}
+	}
+	p.mtaSenders, p.mtaReceivers = mtaSenders, mtaReceivers
+	return p, nil
+}
+
+// NewParticipantWithBackend creates the party id of a session among ids whose pairwise MtA instances are set up with
+// backend in the first rounds of the DKG. sessionId must be the same for all parties and unique to the session; the
+// session ids of the instances and of the proofs are derived from it. A nil signerId selects the default distinguishing
+// identifier.
+func NewParticipantWithBackend[A any, B any](curve *curves.Curve, id uint32, ids []uint32, signerId []byte, backend mta.Backend[A, B], sessionId []byte) (*Participant[A, B], error) {
+	if backend == nil {
+		return nil, fmt.Errorf("backend is nil")
+	}
+	p, err := newParticipant[A, B](curve, id, ids, signerId, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if p.mtaSetup, err = newMtASetupState(backend, sessionId, id, p.peers); err != nil {
+		return nil, err
+	}
+	p.mtaSenders = make(map[uint32]sign_offline.MTASender[A, B], len(p.peers))
+	p.mtaReceivers = make(map[uint32]sign_offline.MTAReceiver[A, B], len(p.peers))
+	return p, nil
+}
+
+func newParticipant[A any, B any](curve *curves.Curve, id uint32, ids []uint32, signerId []byte, sessionId []byte) (*Participant[A, B], error) {
+	if curve == nil {
+		return nil, fmt.Errorf("curve is nil")
+	}
+	if len(sessionId) == 0 {
+		return nil, fmt.Errorf("session id is empty")
+	}
+	if err := parties.Validate(ids, 2); err != nil {
+		return nil, err
+	}
+	var peers []uint32
+	isParty := false
+	for _, other := range ids {
+		if other == id {
+			isParty = true
+			continue
+		}
+		peers = append(peers, other)
+	}
+	if !isParty {
+		return nil, fmt.Errorf("party %d is not one of the participants", id)
+	}
+	if signerId == nil {
+		signerId = sm2.DefaultSignerId
+	}
+	return &Participant[A, B]{
+		curve:     curve,
+		P:         curve.NewGeneratorPoint(),
+		signerId:  signerId,
+		sessionId: append([]byte{}, sessionId...),
+		id:        id,
+		peers:     peers,
+	}, nil
+}
+
+// Id returns the identifier of the participant
+func (p *Participant[A, B]) Id() uint32 {
+	return p.id
+}
+
+// Peers returns the identifiers of the other participants
+func (p *Participant[A, B]) Peers() []uint32 {
+	return append([]uint32{}, p.peers...)
+}
+
+// DKGOutput returns the public result of the DKG, or nil if the DKG has not completed
+func (p *Participant[A, B]) DKGOutput() *DKGOutput {
+	if p.sigma == nil {
+		return nil
+	}
+	return &DKGOutput{
+		Q:         p.Q,
+		PublicKey: p.Q.Sub(p.P),
+		T:         p.T,
+	}
+}
+
+// proofSessionId returns the session id of the DKG proof labeled label that the party sender produces
+func (p *Participant[A, B]) proofSessionId(sender uint32, label string) []byte {
+	return parties.ProofSessionId(p.sessionId, sender, label)
+}
+
+// checkInputs makes sure that there is exactly one message from every one of peers
+func checkInputs[M any](peers []uint32, inputs map[uint32]M) error {
+	if len(inputs) != len(peers) {
+		return fmt.Errorf("expected messages from %d peers, got %d", len(peers), len(inputs))
+	}
+	for _, peer := range peers {
+		if _, ok := inputs[peer]; !ok {
+			return fmt.Errorf("missing message from party %d", peer)
+		}
+	}
+	return nil
+}
+
+// sumRegProofs adds up the ElGamal ciphertexts of our own and the peers' encryption proofs
+func sumRegProofs(own *reg.Proof, others map[uint32]*reg.Proof) (curves.Point, curves.Point) {
+	U, V := own.A, own.B
+	for _, proof := range others {
+		U = U.Add(proof.A)
+		V = V.Add(proof.B)
+	}
+	return U, V
+}
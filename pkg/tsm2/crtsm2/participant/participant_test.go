@@ -0,0 +1,479 @@
+package participant
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core"
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/protocol"
+	"github.com/coinbase/kryptology/pkg/ot/base/simplest"
+	"github.com/coinbase/kryptology/pkg/ot/extension/kos"
+	"github.com/coinbase/kryptology/pkg/ot/ottest"
+	"github.com/coinbase/kryptology/pkg/paillier"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	mta_ot "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/ot"
+	mta_paillier "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/paillier"
+	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/sign_offline"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/abort"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/mta"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/verify"
+)
+
+type testParticipant = Participant[*mta_ot.Round1Output, *mta_ot.Round2Output]
+
+// newParticipants runs the OT setup of every ordered pair (i, j), in which i multiplies its gamma with j's secret
+var testSessionId = []byte("crtsm2 participant test")
+
+func newParticipants(t *testing.T, curve *curves.Curve, ids []uint32, signerId []byte) map[uint32]*testParticipant {
+	senders := make(map[uint32]map[uint32]sign_offline.MTASender[*mta_ot.Round1Output, *mta_ot.Round2Output])
+	receivers := make(map[uint32]map[uint32]sign_offline.MTAReceiver[*mta_ot.Round1Output, *mta_ot.Round2Output])
+	for _, id := range ids {
+		senders[id] = make(map[uint32]sign_offline.MTASender[*mta_ot.Round1Output, *mta_ot.Round2Output])
+		receivers[id] = make(map[uint32]sign_offline.MTAReceiver[*mta_ot.Round1Output, *mta_ot.Round2Output])
+	}
+	for _, i := range ids {
+		for _, j := range ids {
+			if i == j {
+				continue
+			}
+			uniqueSessionId := [simplest.DigestSize]byte{}
+			copy(uniqueSessionId[:], strconv.Itoa(int(i))+"->"+strconv.Itoa(int(j)))
+			baseOtSenderOutput, baseOtReceiverOutput, err := ottest.RunSimplestOT(curve, kos.Kappa, uniqueSessionId)
+			require.NoError(t, err)
+			sender, err := mta_ot.NewSender(baseOtReceiverOutput, curve, uniqueSessionId)
+			require.NoError(t, err)
+			receiver, err := mta_ot.NewReceiver(baseOtSenderOutput, curve, uniqueSessionId)
+			require.NoError(t, err)
+			senders[i][j] = sender
+			receivers[j][i] = receiver
+		}
+	}
+	participants := make(map[uint32]*testParticipant, len(ids))
+	for _, id := range ids {
+		p, err := NewParticipant(curve, id, ids, signerId, senders[id], receivers[id], testSessionId)
+		require.NoError(t, err)
+		participants[id] = p
+	}
+	return participants
+}
+
+func collect[T any](to uint32, outputs map[uint32]T) map[uint32]T {
+	inputs := make(map[uint32]T, len(outputs)-1)
+	for from, output := range outputs {
+		if from != to {
+			inputs[from] = output
+		}
+	}
+	return inputs
+}
+
+func collectDirect[T any](to uint32, outputs map[uint32]map[uint32]T) map[uint32]T {
+	inputs := make(map[uint32]T, len(outputs)-1)
+	for from, output := range outputs {
+		if from != to {
+			inputs[from] = output[to]
+		}
+	}
+	return inputs
+}
+
+// runRound calls round on every participant with what its peers emitted in the previous round
+func runRound[In any, Out any](t *testing.T, participants map[uint32]*testParticipant, inputs func(uint32) In, round func(*testParticipant, In) (Out, error)) map[uint32]Out {
+	outputs := make(map[uint32]Out, len(participants))
+	for id, p := range participants {
+		output, err := round(p, inputs(id))
+		require.NoError(t, err)
+		outputs[id] = output
+	}
+	return outputs
+}
+
+func broadcast[T any](t *testing.T, outputs map[uint32]T) func(uint32) map[uint32]T {
+	return func(id uint32) map[uint32]T { return collect(id, outputs) }
+}
+
+func direct[T any](t *testing.T, outputs map[uint32]map[uint32]T) func(uint32) map[uint32]T {
+	return func(id uint32) map[uint32]T { return collectDirect(id, outputs) }
+}
+
+func runDKG(t *testing.T, participants map[uint32]*testParticipant) map[uint32]*DKGOutput {
+	none := func(uint32) struct{} { return struct{}{} }
+	r1 := runRound(t, participants, none, func(p *testParticipant, _ struct{}) (*DKGPhase1Commitments, error) { return p.DKGPhase1Commit() })
+	r2 := runRound(t, participants, broadcast(t, r1), (*testParticipant).DKGPhase1Decommit)
+	r3 := runRound(t, participants, broadcast(t, r2), (*testParticipant).DKGPhase2Encrypt)
+	r4 := runRound(t, participants, broadcast(t, r3), (*testParticipant).DKGPhase2Relate)
+	r5 := runRound(t, participants, broadcast(t, r4), (*testParticipant).DKGPhase3MtAInit)
+	r6 := runRound(t, participants, direct(t, r5), (*testParticipant).DKGPhase3MtAUpdate)
+	r7 := runRound(t, participants, direct(t, r6), (*testParticipant).DKGPhase3MtAMultiply)
+	r8 := runRound(t, participants, broadcast(t, r7), (*testParticipant).DKGPhase4Rerandomize)
+	r9 := runRound(t, participants, broadcast(t, r8), (*testParticipant).DKGPhase4RerandomizeDecommit)
+	r10 := runRound(t, participants, broadcast(t, r9), (*testParticipant).DKGPhase4DDHCommit)
+	r11 := runRound(t, participants, broadcast(t, r10), (*testParticipant).DKGPhase4DDHDecommit)
+	r12 := runRound(t, participants, broadcast(t, r11), (*testParticipant).DKGPhase4RevealSigma)
+	return runRound(t, participants, broadcast(t, r12), (*testParticipant).DKGPhase4Finalize)
+}
+
+func runDS(t *testing.T, participants map[uint32]*testParticipant, message []byte) map[uint32]*Signature {
+	start := func(uint32) []byte { return message }
+	r1 := runRound(t, participants, start, (*testParticipant).DSPhase1Commit)
+	r2 := runRound(t, participants, broadcast(t, r1), (*testParticipant).DSPhase1Decommit)
+	r3 := runRound(t, participants, broadcast(t, r2), (*testParticipant).DSPhase2)
+	r4 := runRound(t, participants, broadcast(t, r3), (*testParticipant).DSPhase3MtAInit)
+	r5 := runRound(t, participants, direct(t, r4), (*testParticipant).DSPhase3MtAUpdate)
+	r6 := runRound(t, participants, direct(t, r5), (*testParticipant).DSPhase3MtAMultiply)
+	r7 := runRound(t, participants, broadcast(t, r6), (*testParticipant).DSPhase4Rerandomize)
+	r8 := runRound(t, participants, broadcast(t, r7), (*testParticipant).DSPhase4RerandomizeDecommit)
+	r9 := runRound(t, participants, broadcast(t, r8), (*testParticipant).DSPhase4DDHCommit)
+	r10 := runRound(t, participants, broadcast(t, r9), (*testParticipant).DSPhase4DDHDecommit)
+	r11 := runRound(t, participants, broadcast(t, r10), (*testParticipant).DSPhase5PartialSign)
+	return runRound(t, participants, broadcast(t, r11), (*testParticipant).DSPhase5Finalize)
+}
+
+func TestParticipantsDKGAndDS(t *testing.T) {
+	for _, curve := range []*curves.Curve{curves.SM2(), curves.K256()} {
+		ids := []uint32{1, 2, 5}
+		signerId := []byte("ALICE123@YAHOO.COM")
+		participants := newParticipants(t, curve, ids, signerId)
+
+		outputs := runDKG(t, participants)
+		Q := outputs[1].Q
+		for _, output := range outputs {
+			require.True(t, output.Q.Equal(Q))
+			require.True(t, output.PublicKey.Equal(Q.Sub(curve.NewGeneratorPoint())))
+		}
+
+		// the same key signs several messages
+		for _, message := range []string{"message digest", "another message"} {
+			signatures := runDS(t, participants, []byte(message))
+			for _, signature := range signatures {
+				require.NoError(t, verify.Verify(curve, nil, Q, signerId, []byte(message), signature.R, signature.S))
+				pk, standard := verify.ToStandard(curve, Q, signature.R, signature.S)
+				require.NoError(t, sm2.Verify(curve, pk, signerId, []byte(message), standard))
+			}
+		}
+	}
+}
+
+func TestNewParticipantValidation(t *testing.T) {
+	curve := curves.SM2()
+	participants := newParticipants(t, curve, []uint32{1, 2}, nil)
+	p := participants[1]
+
+	_, err := NewParticipant(curve, 1, []uint32{1}, nil, p.mtaSenders, p.mtaReceivers, testSessionId)
+	require.Error(t, err)
+	_, err = NewParticipant(curve, 3, []uint32{1, 2}, nil, p.mtaSenders, p.mtaReceivers, testSessionId)
+	require.Error(t, err)
+	_, err = NewParticipant(curve, 1, []uint32{1, 2, 2}, nil, p.mtaSenders, p.mtaReceivers, testSessionId)
+	require.Error(t, err)
+	_, err = NewParticipant(curve, 1, []uint32{0, 1, 2}, nil, p.mtaSenders, p.mtaReceivers, testSessionId)
+	require.Error(t, err)
+	_, err = NewParticipant(curve, 1, []uint32{1, 2, 3}, nil, p.mtaSenders, p.mtaReceivers, testSessionId)
+	require.Error(t, err)
+	_, err = NewParticipant(curve, 1, []uint32{1, 2}, nil, p.mtaSenders, p.mtaReceivers, nil)
+	require.Error(t, err)
+}
+
+func TestParticipantRoundOrder(t *testing.T) {
+	participants := newParticipants(t, curves.SM2(), []uint32{1, 2}, nil)
+	p := participants[1]
+
+	// signing needs a completed DKG
+	_, err := p.DSPhase1Commit([]byte("message"))
+	require.Error(t, err)
+
+	_, err = p.DKGPhase2Encrypt(nil)
+	require.Error(t, err)
+	_, err = p.DKGPhase1Commit()
+	require.NoError(t, err)
+	_, err = p.DKGPhase1Commit()
+	require.Error(t, err)
+	// a message from every peer is required
+	_, err = p.DKGPhase1Decommit(map[uint32]*DKGPhase1Commitments{})
+	require.Error(t, err)
+}
+
+func TestParticipantRejectsTamperedSigma(t *testing.T) {
+	curve := curves.K256()
+	participants := newParticipants(t, curve, []uint32{1, 2, 3}, nil)
+	none := func(uint32) struct{} { return struct{}{} }
+	r1 := runRound(t, participants, none, func(p *testParticipant, _ struct{}) (*DKGPhase1Commitments, error) { return p.DKGPhase1Commit() })
+	r2 := runRound(t, participants, broadcast(t, r1), (*testParticipant).DKGPhase1Decommit)
+	r3 := runRound(t, participants, broadcast(t, r2), (*testParticipant).DKGPhase2Encrypt)
+	r4 := runRound(t, participants, broadcast(t, r3), (*testParticipant).DKGPhase2Relate)
+	r5 := runRound(t, participants, broadcast(t, r4), (*testParticipant).DKGPhase3MtAInit)
+	r6 := runRound(t, participants, direct(t, r5), (*testParticipant).DKGPhase3MtAUpdate)
+	r7 := runRound(t, participants, direct(t, r6), (*testParticipant).DKGPhase3MtAMultiply)
+	r8 := runRound(t, participants, broadcast(t, r7), (*testParticipant).DKGPhase4Rerandomize)
+	r9 := runRound(t, participants, broadcast(t, r8), (*testParticipant).DKGPhase4RerandomizeDecommit)
+	r10 := runRound(t, participants, broadcast(t, r9), (*testParticipant).DKGPhase4DDHCommit)
+	r11 := runRound(t, participants, broadcast(t, r10), (*testParticipant).DKGPhase4DDHDecommit)
+	r12 := runRound(t, participants, broadcast(t, r11), (*testParticipant).DKGPhase4RevealSigma)
+
+	r12[3].Sigma = r12[3].Sigma.Add(curve.Scalar.One())
+	_, err := participants[1].DKGPhase4Finalize(collect(1, r12))
+	var abortErr *abort.Error
+	require.True(t, errors.As(err, &abortErr))
+	require.Equal(t, uint32(3), abortErr.Culprit)
+	require.Equal(t, abort.DkgSigmaRev, abortErr.Evidence.Check)
+
+	// party 2 received the same sigma and confirms the accusation
+	confirmed, err := abortErr.Evidence.Confirm(curve, &abort.Evidence{
+		Check:     abort.DkgSigmaRev,
+		Proof:     r12[3].SigmaDDHProof,
+		SessionId: participants[2].proofSessionId(3, dkgSigmaRevealLabel),
+		Inputs:    []curves.Point{r7[3].A, r7[3].B, curve.ScalarBaseMult(r12[3].Sigma)},
+	})
+	require.NoError(t, err)
+	require.True(t, confirmed)
+}
+
+func TestParticipantIdentifiesCulprit(t *testing.T) {
+	curve := curves.SM2()
+	participants := newParticipants(t, curve, []uint32{1, 2, 3}, nil)
+	none := func(uint32) struct{} { return struct{}{} }
+	r1 := runRound(t, participants, none, func(p *testParticipant, _ struct{}) (*DKGPhase1Commitments, error) { return p.DKGPhase1Commit() })
+	r2 := runRound(t, participants, broadcast(t, r1), (*testParticipant).DKGPhase1Decommit)
+	r3 := runRound(t, participants, broadcast(t, r2), (*testParticipant).DKGPhase2Encrypt)
+
+	// party 2 sends a gamma encryption proof that does not verify
+	proof := *r3[2]
+	proof.A = proof.A.Double()
+	r3[2] = &proof
+	_, err := participants[3].DKGPhase2Relate(collect(3, r3))
+	var abortErr *abort.Error
+	require.True(t, errors.As(err, &abortErr))
+	require.Equal(t, uint32(2), abortErr.Culprit)
+	require.Equal(t, "DKGPhase2Relate", abortErr.Phase)
+	require.Equal(t, abort.DkgREG, abortErr.Evidence.Check)
+
+	// any other party re-checks the proof it received itself instead of trusting party 3
+	confirmed, err := abortErr.Evidence.Confirm(curve, &abort.Evidence{
+		Check:     abort.DkgREG,
+		Proof:     r3[2],
+		SessionId: participants[1].proofSessionId(2, dkgGammaLabel),
+		Inputs:    []curves.Point{participants[1].T},
+	})
+	require.NoError(t, err)
+	require.True(t, confirmed)
+
+	// party 3 cannot frame party 1 by checking its honest proof against another key
+	framing := &abort.Evidence{
+		Check:     abort.DkgREG,
+		Proof:     r3[1],
+		SessionId: participants[3].proofSessionId(1, dkgGammaLabel),
+		Inputs:    []curves.Point{participants[3].T.Double()},
+	}
+	confirmed, err = framing.Confirm(curve, &abort.Evidence{
+		Check:     abort.DkgREG,
+		Proof:     r3[1],
+		SessionId: participants[2].proofSessionId(1, dkgGammaLabel),
+		Inputs:    []curves.Point{participants[2].T},
+	})
+	require.NoError(t, err)
+	require.False(t, confirmed)
+}
+
+func TestParticipantRejectsReplayedProof(t *testing.T) {
+	curve := curves.SM2()
+	participants := newParticipants(t, curve, []uint32{1, 2, 3}, nil)
+	none := func(uint32) struct{} { return struct{}{} }
+	r1 := runRound(t, participants, none, func(p *testParticipant, _ struct{}) (*DKGPhase1Commitments, error) { return p.DKGPhase1Commit() })
+	r2 := runRound(t, participants, broadcast(t, r1), (*testParticipant).DKGPhase1Decommit)
+	r3 := runRound(t, participants, broadcast(t, r2), (*testParticipant).DKGPhase2Encrypt)
+
+	// party 2 passes off the gamma encryption of party 1 as its own
+	r3[2] = r3[1]
+	_, err := participants[3].DKGPhase2Relate(collect(3, r3))
+	var abortErr *abort.Error
+	require.True(t, errors.As(err, &abortErr))
+	require.Equal(t, uint32(2), abortErr.Culprit)
+}
+
+// route delivers every broadcast payload to all the other parties and every direct payload to its recipient,
+// keyed by the sender's identifier
+func route(t *testing.T, outputs map[uint32]*protocol.Message) map[uint32]*protocol.Message {
+	inputs := make(map[uint32]*protocol.Message, len(outputs))
+	for id := range outputs {
+		inputs[id] = &protocol.Message{Version: protocol.Version1, Payloads: map[string][]byte{}}
+	}
+	for from, output := range outputs {
+		if output == nil {
+			continue
+		}
+		sender := strconv.FormatUint(uint64(from), 10)
+		for key, payload := range output.Payloads {
+			if key == broadcastKey {
+				for to := range outputs {
+					if to != from {
+						inputs[to].Payloads[sender] = payload
+					}
+				}
+				continue
+			}
+			to, err := strconv.ParseUint(key, 10, 32)
+			require.NoError(t, err)
+			inputs[uint32(to)].Payloads[sender] = payload
+		}
+	}
+	return inputs
+}
+
+func runIteratedProtocol(t *testing.T, parties map[uint32]protocol.Iterator) {
+	var inputs map[uint32]*protocol.Message
+	for {
+		outputs := make(map[uint32]*protocol.Message, len(parties))
+		finished := 0
+		for id, party := range parties {
+			var input *protocol.Message
+			if inputs != nil {
+				input = inputs[id]
+			}
+			output, err := party.Next(input)
+			if err == protocol.ErrProtocolFinished {
+				finished++
+				continue
+			}
+			require.NoError(t, err)
+			outputs[id] = output
+		}
+		if finished == len(parties) {
+			return
+		}
+		require.Zero(t, finished, "parties finished at different rounds")
+		inputs = route(t, outputs)
+	}
+}
+
+func TestParticipantProtocol(t *testing.T) {
+	curve := curves.SM2()
+	runProtocols(t, curve, newParticipants(t, curve, []uint32{1, 2, 3}, nil))
+}
+
+// runProtocols runs the DKG and DS iterators of every participant and checks the signature
+func runProtocols[A any, B any](t *testing.T, curve *curves.Curve, participants map[uint32]*Participant[A, B]) {
+	dkgs := make(map[uint32]protocol.Iterator, len(participants))
+	for id, p := range participants {
+		dkgs[id] = NewDkg(p, protocol.Version1)
+	}
+	runIteratedProtocol(t, dkgs)
+	var Q curves.Point
+	for _, dkg := range dkgs {
+		result, err := dkg.Result(protocol.Version1)
+		require.NoError(t, err)
+		output, err := DecodeDkgOutput(result)
+		require.NoError(t, err)
+		if Q == nil {
+			Q = output.Q
+		}
+		require.True(t, output.Q.Equal(Q))
+	}
+
+	message := []byte("message digest")
+	signs := make(map[uint32]protocol.Iterator, len(participants))
+	for id, p := range participants {
+		signs[id] = NewSign(p, message, protocol.Version1)
+	}
+	runIteratedProtocol(t, signs)
+	for _, sign := range signs {
+		result, err := sign.Result(protocol.Version1)
+		require.NoError(t, err)
+		signature, err := DecodeSignature(result)
+		require.NoError(t, err)
+		require.NoError(t, verify.Verify(curve, nil, Q, sm2.DefaultSignerId, message, signature.R, signature.S))
+	}
+}
+
+// newBackendParticipants creates participants that set up their MtA instances with the backend of each party
+func newBackendParticipants[A any, B any](t *testing.T, curve *curves.Curve, backends map[uint32]mta.Backend[A, B]) map[uint32]*Participant[A, B] {
+	var ids []uint32
+	for id := range backends {
+		ids = append(ids, id)
+	}
+	participants := make(map[uint32]*Participant[A, B], len(ids))
+	for _, id := range ids {
+		p, err := NewParticipantWithBackend(curve, id, ids, nil, backends[id], []byte("test session"))
+		require.NoError(t, err)
+		participants[id] = p
+	}
+	return participants
+}
+
+func TestParticipantProtocolWithOTBackend(t *testing.T) {
+	for _, curve := range []*curves.Curve{curves.SM2(), curves.K256()} {
+		backend := mta.NewOT(curve)
+		backends := map[uint32]mta.Backend[*mta_ot.Round1Output, *mta_ot.Round2Output]{1: backend, 2: backend, 4: backend}
+		runProtocols(t, curve, newBackendParticipants(t, curve, backends))
+	}
+}
+
+func TestParticipantProtocolWithPaillierBackend(t *testing.T) {
+	curve := curves.SM2()
+	backends := make(map[uint32]mta.Backend[*mta_paillier.Round1Output, *mta_paillier.Round2Output])
+	for _, id := range []uint32{1, 2} {
+		p, err := core.GenerateSafePrime(paillier.PaillierPrimeBits)
+		require.NoError(t, err)
+		q, err := core.GenerateSafePrime(paillier.PaillierPrimeBits)
+		require.NoError(t, err)
+		backends[id], err = mta.NewPaillier(curve, p, q)
+		require.NoError(t, err)
+	}
+	runProtocols(t, curve, newBackendParticipants(t, curve, backends))
+}
+
+func TestParticipantMtASetup(t *testing.T) {
+	curve := curves.SM2()
+	backend := mta.NewOT(curve)
+	participants := newBackendParticipants(t, curve, map[uint32]mta.Backend[*mta_ot.Round1Output, *mta_ot.Round2Output]{1: backend, 2: backend})
+
+	// the DKG waits for the setup
+	_, err := participants[1].DKGPhase1Commit()
+	require.Error(t, err)
+	_, err = NewParticipantWithBackend[*mta_ot.Round1Output, *mta_ot.Round2Output](curve, 1, []uint32{1, 2}, nil, nil, nil)
+	require.Error(t, err)
+
+	outputs := make(map[uint32]map[uint32]*MtASetupMessage)
+	for id, p := range participants {
+		require.Equal(t, backend.Rounds(), p.MtASetupRounds())
+		outputs[id], err = p.MtASetup(nil)
+		require.NoError(t, err)
+	}
+	for round := 1; round < backend.Rounds(); round++ {
+		next := make(map[uint32]map[uint32]*MtASetupMessage)
+		for id, p := range participants {
+			next[id], err = p.MtASetup(collectDirect(id, outputs))
+			require.NoError(t, err)
+		}
+		outputs = next
+	}
+	for _, p := range participants {
+		require.Zero(t, p.MtASetupRounds())
+		require.Nil(t, outputs[p.id])
+		_, err = p.MtASetup(nil)
+		require.Error(t, err)
+	}
+	runDKG(t, participants)
+}
+
+func TestParticipantMtASetupRejectsMismatchedSessions(t *testing.T) {
+	curve := curves.SM2()
+	backend := mta.NewOT(curve)
+	ids := []uint32{1, 2}
+	participants := make(map[uint32]*testParticipant)
+	for _, id := range ids {
+		p, err := NewParticipantWithBackend[*mta_ot.Round1Output, *mta_ot.Round2Output](curve, id, ids, nil, backend, []byte{byte(id)})
+		require.NoError(t, err)
+		participants[id] = p
+	}
+	outputs := make(map[uint32]map[uint32]*MtASetupMessage)
+	for id, p := range participants {
+		var err error
+		outputs[id], err = p.MtASetup(nil)
+		require.NoError(t, err)
+	}
+	_, err := participants[1].MtASetup(collectDirect(1, outputs))
+	require.Error(t, err)
+}
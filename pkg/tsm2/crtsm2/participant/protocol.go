@@ -0,0 +1,286 @@
+package participant
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/protocol"
+	"github.com/coinbase/kryptology/pkg/zkp/rspdl"
+)
+
+// Basic protocol interface implementation that calls the next step func in a pre-defined list
+type protoStepper struct {
+	steps []func(input *protocol.Message) (*protocol.Message, error)
+	step  int
+}
+
+// Next runs the next step in the protocol and reports errors or increments the step index
+func (p *protoStepper) Next(input *protocol.Message) (*protocol.Message, error) {
+	if p.complete() {
+		return nil, protocol.ErrProtocolFinished
+	}
+
+	// Run the current protocol step and report any errors
+	output, err := p.steps[p.step](input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Increment the step index and report success
+	p.step++
+	return output, nil
+}
+
+// Reports true if the step index exceeds the number of steps
+func (p *protoStepper) complete() bool { return p.step >= len(p.steps) }
+
+// Dkg runs the DKG of a single participant and satisfies the protocol iterator interface.
+//
+// The first call to Next takes no input. Each following call takes the messages that the peers emitted in the previous
+// round, with the payloads keyed by the decimal identifier of the sender. Outputs are keyed by "broadcast" for messages
+// to every peer, or by the decimal identifier of the recipient for point-to-point messages. The last round emits nothing.
+type Dkg[A any, B any] struct {
+	protoStepper
+	*Participant[A, B]
+}
+
+// Sign runs the DS of a single participant and satisfies the protocol iterator interface.
+// It follows the same message conventions as Dkg.
+type Sign[A any, B any] struct {
+	protoStepper
+	*Participant[A, B]
+	signature *Signature
+}
+
+var (
+	// Static type assertions
+	_ protocol.Iterator = &Dkg[any, any]{}
+	_ protocol.Iterator = &Sign[any, any]{}
+)
+
+// broadcastRound wraps a round that consumes and produces broadcast messages
+func broadcastRound[In any, Out any](peers []uint32, protocolName string, round string, version uint, f func(map[uint32]In) (Out, error)) func(*protocol.Message) (*protocol.Message, error) {
+	return func(input *protocol.Message) (*protocol.Message, error) {
+		inputs, err := decodeRoundInputs[In](input, peers)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		output, err := f(inputs)
+		if err != nil {
+			return nil, err
+		}
+		return encodeBroadcast(output, protocolName, round, version)
+	}
+}
+
+// directRound wraps a round that consumes messages of type In and produces point-to-point messages
+func directRound[In any, Out any](peers []uint32, protocolName string, round string, version uint, f func(map[uint32]In) (map[uint32]Out, error)) func(*protocol.Message) (*protocol.Message, error) {
+	return func(input *protocol.Message) (*protocol.Message, error) {
+		inputs, err := decodeRoundInputs[In](input, peers)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		output, err := f(inputs)
+		if err != nil {
+			return nil, err
+		}
+		return encodeDirect(output, protocolName, round, version)
+	}
+}
+
+// NewDkg creates a new protocol that runs the DKG as participant.
+// A participant created with a backend first runs the rounds of the MtA setup; the final one is merged into the first
+// round of the DKG, so that the first call to Next still takes no input. A participant on which SetThreshold was called
+// ends with the rounds of DKGPhase5, the first of which is merged into the last round of DKGPhase4.
+func NewDkg[A any, B any](participant *Participant[A, B], version uint) *Dkg[A, B] {
+	p := &Dkg[A, B]{Participant: participant}
+	peers := participant.peers
+	name := protocol.CrtSm2Dkg
+	setupRounds := participant.MtASetupRounds()
+	for round := 1; round < setupRounds; round++ {
+		label := "setup-" + strconv.Itoa(round)
+		if round == 1 {
+			p.steps = append(p.steps, func(*protocol.Message) (*protocol.Message, error) {
+				output, err := p.MtASetup(nil)
+				if err != nil {
+					return nil, err
+				}
+				return encodeDirect(output, name, label, version)
+			})
+			continue
+		}
+		p.steps = append(p.steps, directRound(peers, name, label, version, p.MtASetup))
+	}
+	p.steps = append(p.steps, []func(*protocol.Message) (*protocol.Message, error){
+		func(input *protocol.Message) (*protocol.Message, error) {
+			if setupRounds > 0 {
+				var inputs map[uint32]*MtASetupMessage
+				if setupRounds > 1 {
+					var err error
+					if inputs, err = decodeRoundInputs[*MtASetupMessage](input, peers); err != nil {
+						return nil, errors.WithStack(err)
+					}
+				}
+				if _, err := p.MtASetup(inputs); err != nil {
+					return nil, err
+				}
+			}
+			output, err := p.DKGPhase1Commit()
+			if err != nil {
+				return nil, err
+			}
+			return encodeBroadcast(output, name, "1", version)
+		},
+		broadcastRound(peers, name, "2", version, p.DKGPhase1Decommit),
+		broadcastRound(peers, name, "3", version, p.DKGPhase2Encrypt),
+		broadcastRound(peers, name, "4", version, p.DKGPhase2Relate),
+		func(input *protocol.Message) (*protocol.Message, error) {
+			inputs, err := decodeRoundInputs[*rspdl.Proof](input, peers)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			output, err := p.DKGPhase3MtAInit(inputs)
+			if err != nil {
+				return nil, err
+			}
+			return encodeDirect(output, name, "5", version)
+		},
+		func(input *protocol.Message) (*protocol.Message, error) {
+			inputs, err := decodeRoundInputs[A](input, peers)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			output, err := p.DKGPhase3MtAUpdate(inputs)
+			if err != nil {
+				return nil, err
+			}
+			return encodeDirect(output, name, "6", version)
+		},
+		broadcastRound(peers, name, "7", version, p.DKGPhase3MtAMultiply),
+		broadcastRound(peers, name, "8", version, p.DKGPhase4Rerandomize),
+		broadcastRound(peers, name, "9", version, p.DKGPhase4RerandomizeDecommit),
+		broadcastRound(peers, name, "10", version, p.DKGPhase4DDHCommit),
+		broadcastRound(peers, name, "11", version, p.DKGPhase4DDHDecommit),
+		broadcastRound(peers, name, "12", version, p.DKGPhase4RevealSigma),
+		func(input *protocol.Message) (*protocol.Message, error) {
+			inputs, err := decodeRoundInputs[*DKGPhase4SigmaReveal](input, peers)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if _, err = p.DKGPhase4Finalize(inputs); err != nil {
+				return nil, err
+			}
+			if participant.t == 0 {
+				return nil, nil
+			}
+			output, err := p.DKGPhase5Deal()
+			if err != nil {
+				return nil, err
+			}
+			return encodeBroadcast(output, name, "deal-1", version)
+		},
+	}...)
+	if participant.t == 0 {
+		return p
+	}
+	p.steps = append(p.steps, []func(*protocol.Message) (*protocol.Message, error){
+		directRound(peers, name, "deal-2", version, p.DKGPhase5Share),
+		func(input *protocol.Message) (*protocol.Message, error) {
+			inputs, err := decodeRoundInputs[*DKGPhase5Shares](input, peers)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if _, err = p.DKGPhase5Finalize(inputs); err != nil {
+				return nil, err
+			}
+			return nil, nil
+		},
+	}...)
+	return p
+}
+
+// Result returns the encoded public output of the DKG.
+// The secret state stays in the participant, which can then be used to create Sign protocols.
+func (p *Dkg[A, B]) Result(version uint) (*protocol.Message, error) {
+	// Sanity check
+	if !p.complete() {
+		return nil, nil
+	}
+	if p.Participant == nil {
+		return nil, protocol.ErrNotInitialized
+	}
+	return EncodeDkgOutput(p.DKGOutput(), version)
+}
+
+// NewSign creates a new protocol that signs message as participant, which must have completed the DKG.
+// After a threshold DKG, the signers must be selected with SetSigners first; only they exchange messages.
+func NewSign[A any, B any](participant *Participant[A, B], message []byte, version uint) *Sign[A, B] {
+	p := &Sign[A, B]{Participant: participant}
+	peers := participant.signers
+	name := protocol.CrtSm2Sign
+	p.steps = []func(*protocol.Message) (*protocol.Message, error){
+		func(*protocol.Message) (*protocol.Message, error) {
+			output, err := p.DSPhase1Commit(message)
+			if err != nil {
+				return nil, err
+			}
+			return encodeBroadcast(output, name, "1", version)
+		},
+		broadcastRound(peers, name, "2", version, p.DSPhase1Decommit),
+		broadcastRound(peers, name, "3", version, p.DSPhase2),
+		func(input *protocol.Message) (*protocol.Message, error) {
+			inputs, err := decodeRoundInputs[*rspdl.Proof](input, peers)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			output, err := p.DSPhase3MtAInit(inputs)
+			if err != nil {
+				return nil, err
+			}
+			return encodeDirect(output, name, "4", version)
+		},
+		func(input *protocol.Message) (*protocol.Message, error) {
+			inputs, err := decodeRoundInputs[A](input, peers)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			output, err := p.DSPhase3MtAUpdate(inputs)
+			if err != nil {
+				return nil, err
+			}
+			return encodeDirect(output, name, "5", version)
+		},
+		broadcastRound(peers, name, "6", version, p.DSPhase3MtAMultiply),
+		broadcastRound(peers, name, "7", version, p.DSPhase4Rerandomize),
+		broadcastRound(peers, name, "8", version, p.DSPhase4RerandomizeDecommit),
+		broadcastRound(peers, name, "9", version, p.DSPhase4DDHCommit),
+		broadcastRound(peers, name, "10", version, p.DSPhase4DDHDecommit),
+		broadcastRound(peers, name, "11", version, p.DSPhase5PartialSign),
+		func(input *protocol.Message) (*protocol.Message, error) {
+			inputs, err := decodeRoundInputs[curves.Scalar](input, peers)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			p.signature, err = p.DSPhase5Finalize(inputs)
+			if err != nil {
+				return nil, err
+			}
+			return nil, nil
+		},
+	}
+	return p
+}
+
+// Result returns the encoded signature
+func (p *Sign[A, B]) Result(version uint) (*protocol.Message, error) {
+	// Sanity check
+	if !p.complete() {
+		return nil, nil
+	}
+	if p.signature == nil {
+		return nil, protocol.ErrNotInitialized
+	}
+	return EncodeSignature(p.signature, version)
+}
@@ -0,0 +1,138 @@
+package participant
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/protocol"
+)
+
+const broadcastKey = "broadcast"
+
+// envelope lets gob carry values whose static type is an interface, such as curves.Scalar
+type envelope[T any] struct {
+	Value T
+}
+
+func newProtocolMessage(protocolName string, payloads map[string][]byte, round string, version uint) *protocol.Message {
+	return &protocol.Message{
+		Protocol: protocolName,
+		Version:  version,
+		Payloads: payloads,
+		Metadata: map[string]string{"round": round},
+	}
+}
+
+func registerTypes() {
+	gob.Register(&curves.ScalarK256{})
+	gob.Register(&curves.PointK256{})
+	gob.Register(&curves.ScalarP256{})
+	gob.Register(&curves.PointP256{})
+	gob.Register(&curves.ScalarSM2{})
+	gob.Register(&curves.PointSM2{})
+}
+
+func encodePayload[T any](value T) ([]byte, error) {
+	buf := bytes.NewBuffer([]byte{})
+	enc := gob.NewEncoder(buf)
+	if err := enc.Encode(&envelope[T]{Value: value}); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return buf.Bytes(), nil
+}
+
+func decodePayload[T any](payload []byte) (T, error) {
+	decoded := &envelope[T]{}
+	dec := gob.NewDecoder(bytes.NewBuffer(payload))
+	if err := dec.Decode(decoded); err != nil {
+		return decoded.Value, errors.WithStack(err)
+	}
+	return decoded.Value, nil
+}
+
+func encodeBroadcast[T any](value T, protocolName string, round string, version uint) (*protocol.Message, error) {
+	if version != protocol.Version1 {
+		return nil, errors.New("only version 1 is supported")
+	}
+	registerTypes()
+	payload, err := encodePayload(value)
+	if err != nil {
+		return nil, err
+	}
+	return newProtocolMessage(protocolName, map[string][]byte{broadcastKey: payload}, round, version), nil
+}
+
+func encodeDirect[T any](values map[uint32]T, protocolName string, round string, version uint) (*protocol.Message, error) {
+	if version != protocol.Version1 {
+		return nil, errors.New("only version 1 is supported")
+	}
+	registerTypes()
+	payloads := make(map[string][]byte, len(values))
+	for recipient, value := range values {
+		payload, err := encodePayload(value)
+		if err != nil {
+			return nil, err
+		}
+		payloads[strconv.FormatUint(uint64(recipient), 10)] = payload
+	}
+	return newProtocolMessage(protocolName, payloads, round, version), nil
+}
+
+// decodeRoundInputs decodes the payload of every peer, keyed by the sender's identifier
+func decodeRoundInputs[T any](m *protocol.Message, peers []uint32) (map[uint32]T, error) {
+	if m == nil {
+		return nil, errors.New("missing round input")
+	}
+	if m.Version != protocol.Version1 {
+		return nil, errors.New("only version 1 is supported")
+	}
+	registerTypes()
+	inputs := make(map[uint32]T, len(peers))
+	for _, peer := range peers {
+		payload, ok := m.Payloads[strconv.FormatUint(uint64(peer), 10)]
+		if !ok {
+			return nil, errors.Errorf("missing message from party %d", peer)
+		}
+		value, err := decodePayload[T](payload)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding message from party %d", peer)
+		}
+		inputs[peer] = value
+	}
+	return inputs, nil
+}
+
+// EncodeDkgOutput serializes the public DKG output based on the protocol version.
+func EncodeDkgOutput(output *DKGOutput, version uint) (*protocol.Message, error) {
+	if output == nil {
+		return nil, protocol.ErrNotInitialized
+	}
+	return encodeBroadcast(output, protocol.CrtSm2Dkg, "output", version)
+}
+
+// DecodeDkgOutput deserializes the public DKG output.
+func DecodeDkgOutput(m *protocol.Message) (*DKGOutput, error) {
+	if m.Version != protocol.Version1 {
+		return nil, errors.New("only version 1 is supported")
+	}
+	registerTypes()
+	return decodePayload[*DKGOutput](m.Payloads[broadcastKey])
+}
+
+// EncodeSignature serializes the signature based on the protocol version.
+func EncodeSignature(signature *Signature, version uint) (*protocol.Message, error) {
+	return encodeBroadcast(signature, protocol.CrtSm2Sign, "signature", version)
+}
+
+// DecodeSignature deserializes the signature.
+func DecodeSignature(m *protocol.Message) (*Signature, error) {
+	if m.Version != protocol.Version1 {
+		return nil, errors.New("only version 1 is supported")
+	}
+	registerTypes()
+	return decodePayload[*Signature](m.Payloads[broadcastKey])
+}
@@ -0,0 +1,107 @@
+package participant
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/mta"
+)
+
+// MtASetupMessage carries the setup messages of the two MtA instances between a pair of parties.
+// ToSender goes to the recipient's end of the instance in which the recipient is the sender, ToReceiver to the other.
+type MtASetupMessage struct {
+	ToSender   []byte
+	ToReceiver []byte
+}
+
+// mtaSetupState holds our ends of the setups with every peer
+type mtaSetupState[A any, B any] struct {
+	round          int
+	rounds         int
+	senderSetups   map[uint32]mta.SenderSetup[A, B]
+	receiverSetups map[uint32]mta.ReceiverSetup[A, B]
+}
+
+func newMtASetupState[A any, B any](backend mta.Backend[A, B], sessionId []byte, id uint32, peers []uint32) (*mtaSetupState[A, B], error) {
+	state := &mtaSetupState[A, B]{
+		rounds:         backend.Rounds(),
+		senderSetups:   make(map[uint32]mta.SenderSetup[A, B], len(peers)),
+		receiverSetups: make(map[uint32]mta.ReceiverSetup[A, B], len(peers)),
+	}
+	for _, peer := range peers {
+		senderSetup, err := backend.NewSenderSetup(mta.SessionId(sessionId, id, peer))
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating the MtA setup with party %d", peer)
+		}
+		receiverSetup, err := backend.NewReceiverSetup(mta.SessionId(sessionId, peer, id))
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating the MtA setup with party %d", peer)
+		}
+		state.senderSetups[peer] = senderSetup
+		state.receiverSetups[peer] = receiverSetup
+	}
+	return state, nil
+}
+
+// MtASetupRounds is the number of MtA setup rounds that are left before the DKG can start
+func (p *Participant[A, B]) MtASetupRounds() int {
+	if p.mtaSetup == nil {
+		return 0
+	}
+	return p.mtaSetup.rounds - p.mtaSetup.round
+}
+
+// MtASetup runs the next round of the setups of the MtA instances with every peer. The first round takes no input, as
+// does DKGPhase1Commit. The output is point-to-point, keyed by the recipient, and is nil after the final round, which
+// installs the MtA instances.
+func (p *Participant[A, B]) MtASetup(setupMessages map[uint32]*MtASetupMessage) (map[uint32]*MtASetupMessage, error) {
+	if p.mtaSetup == nil {
+		return nil, fmt.Errorf("no MtA setup is pending")
+	}
+	state := p.mtaSetup
+	if state.round == 0 {
+		if len(setupMessages) != 0 {
+			return nil, fmt.Errorf("the first round of the MtA setup takes no input")
+		}
+		setupMessages = make(map[uint32]*MtASetupMessage, len(p.peers))
+		for _, peer := range p.peers {
+			setupMessages[peer] = &MtASetupMessage{}
+		}
+	}
+	if err := checkInputs(p.peers, setupMessages); err != nil {
+		return nil, err
+	}
+
+	output := make(map[uint32]*MtASetupMessage, len(p.peers))
+	for _, peer := range p.peers {
+		input := setupMessages[peer]
+		if input == nil {
+			return nil, fmt.Errorf("missing MtA setup message of party %d", peer)
+		}
+		toReceiver, err := state.senderSetups[peer].Next(input.ToSender)
+		if err != nil {
+			return nil, errors.Wrapf(err, "MtA setup as the sender of party %d", peer)
+		}
+		toSender, err := state.receiverSetups[peer].Next(input.ToReceiver)
+		if err != nil {
+			return nil, errors.Wrapf(err, "MtA setup as the receiver of party %d", peer)
+		}
+		output[peer] = &MtASetupMessage{ToSender: toSender, ToReceiver: toReceiver}
+	}
+	state.round++
+	if state.round < state.rounds {
+		return output, nil
+	}
+
+	// install the instances once both ends of every setup have completed
+	for _, peer := range p.peers {
+		sender, receiver := state.senderSetups[peer].Sender(), state.receiverSetups[peer].Receiver()
+		if sender == nil || receiver == nil {
+			return nil, fmt.Errorf("the MtA setup with party %d did not complete", peer)
+		}
+		p.mtaSenders[peer], p.mtaReceivers[peer] = sender, receiver
+	}
+	p.mtaSetup = nil
+	return nil, nil
+}
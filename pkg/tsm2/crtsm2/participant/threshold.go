@@ -0,0 +1,304 @@
+package participant
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/sharing"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/dkg"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+)
+
+// maxShareId is the largest identifier that pkg/sharing can use as an evaluation point
+const maxShareId = 255
+
+// DKGPhase5Commitments is broadcast by every party of a threshold DKG. It holds the Feldman commitments to the
+// polynomials that share x_i, gamma_i and d_i, and the proof that the commitment to gamma_i opens the encryption of
+// gamma_i from DKGPhase2Encrypt.
+type DKGPhase5Commitments struct {
+	XVerifier      *sharing.FeldmanVerifier
+	GammaVerifier  *sharing.FeldmanVerifier
+	DVerifier      *sharing.FeldmanVerifier
+	GammaLinkProof *chaumpedersen.Proof
+}
+
+// DKGPhase5Shares holds the shares of x_i, gamma_i and d_i that a party of a threshold DKG deals to a peer
+type DKGPhase5Shares struct {
+	X     *sharing.ShamirShare
+	Gamma *sharing.ShamirShare
+	D     *sharing.ShamirShare
+}
+
+// SetThreshold makes the participant run a threshold DKG in which any t+1 parties can sign. Every party must call it
+// with the same t before the DKG starts. The identifiers are the evaluation points of the Shamir shares, so they must
+// not exceed 255.
+func (p *Participant[A, B]) SetThreshold(t int) error {
+	if p.dkg != nil || p.sigma != nil {
+		return fmt.Errorf("DKG has already been started")
+	}
+	if t < 1 || t > len(p.peers) {
+		return fmt.Errorf("threshold must be between 1 and %d, got %d", len(p.peers), t)
+	}
+	for _, id := range p.ids() {
+		if id > maxShareId {
+			return fmt.Errorf("party identifier %d exceeds %d", id, maxShareId)
+		}
+	}
+	p.t = t
+	return nil
+}
+
+// DKGPhase5Deal splits x_i, gamma_i and d_i into Feldman shares and proves that the commitment to gamma_i opens our
+// encryption of gamma_i. The output is broadcast.
+func (p *Participant[A, B]) DKGPhase5Deal() (*DKGPhase5Commitments, error) {
+	if err := p.dkgRound(dkgPhase5Deal); err != nil {
+		return nil, err
+	}
+	feldman, err := sharing.NewFeldman(uint32(p.t+1), p.shareLimit(), p.curve)
+	if err != nil {
+		return nil, err
+	}
+	xVerifier, xShares, err := feldman.Split(p.x, rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "sharing the key share")
+	}
+	gammaVerifier, gammaShares, err := feldman.Split(p.gamma, rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "sharing gamma")
+	}
+	dVerifier, dShares, err := feldman.Split(p.d, rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "sharing the encryption key share")
+	}
+	linkProof, _, err := dkg.GammaLinkProve(p.curve, p.T, gammaVerifier.Commitments[0], p.dkg.gammaRegProof, p.dkg.rGamma, p.proofSessionId(p.id, dkgGammaLinkLabel))
+	if err != nil {
+		return nil, errors.Wrap(err, "linking gamma to its encryption")
+	}
+
+	p.dkg.dealtShares = make(map[uint32]*DKGPhase5Shares, len(p.peers)+1)
+	for _, id := range p.ids() {
+		p.dkg.dealtShares[id] = &DKGPhase5Shares{X: xShares[id-1], Gamma: gammaShares[id-1], D: dShares[id-1]}
+	}
+	p.dkg.dealing = &DKGPhase5Commitments{
+		XVerifier:      xVerifier,
+		GammaVerifier:  gammaVerifier,
+		DVerifier:      dVerifier,
+		GammaLinkProof: linkProof,
+	}
+	p.dkg.round = dkgPhase5Share
+	return p.dkg.dealing, nil
+}
+
+// DKGPhase5Share checks that every peer shared its Q_j, its encrypted gamma_j and its T_j, and then sends the peers
+// their shares. The output is point-to-point, keyed by the recipient.
+func (p *Participant[A, B]) DKGPhase5Share(dealings map[uint32]*DKGPhase5Commitments) (map[uint32]*DKGPhase5Shares, error) {
+	if err := p.dkgRound(dkgPhase5Share); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, dealings); err != nil {
+		return nil, err
+	}
+	for peer, dealing := range dealings {
+		if dealing == nil || dealing.GammaLinkProof == nil {
+			return nil, fmt.Errorf("missing dealing of party %d", peer)
+		}
+		for _, verifier := range []*sharing.FeldmanVerifier{dealing.XVerifier, dealing.GammaVerifier, dealing.DVerifier} {
+			if err := p.checkVerifier(verifier); err != nil {
+				return nil, errors.Wrapf(err, "dealing of party %d", peer)
+			}
+		}
+		if !dealing.XVerifier.Commitments[0].Equal(p.QProofs[peer].Statement) {
+			return nil, fmt.Errorf("party %d did not share its key share", peer)
+		}
+		sessionId := p.proofSessionId(peer, dkgGammaLinkLabel)
+		if err := dkg.GammaLinkVerify(p.curve, p.T, dealing.GammaVerifier.Commitments[0], p.dkg.gammaRegProofs[peer], dealing.GammaLinkProof, sessionId); err != nil {
+			return nil, errors.Wrapf(err, "party %d did not share the gamma it encrypted", peer)
+		}
+		if !dealing.DVerifier.Commitments[0].Equal(p.TProofs[peer].Statement) {
+			return nil, fmt.Errorf("party %d did not share its encryption key share", peer)
+		}
+	}
+	p.dkg.peerDealings = dealings
+
+	output := make(map[uint32]*DKGPhase5Shares, len(p.peers))
+	for _, peer := range p.peers {
+		output[peer] = p.dkg.dealtShares[peer]
+	}
+	p.dkg.round = dkgPhase5Finalize
+	return output, nil
+}
+
+// DKGPhase5Finalize verifies the shares that the peers dealt to us, adds them up into our shares of x, gamma and d and
+// computes the public keys of the shares of x and d of every party, which completes the threshold DKG
+func (p *Participant[A, B]) DKGPhase5Finalize(shares map[uint32]*DKGPhase5Shares) (*DKGOutput, error) {
+	if err := p.dkgRound(dkgPhase5Finalize); err != nil {
+		return nil, err
+	}
+	if err := checkInputs(p.peers, shares); err != nil {
+		return nil, err
+	}
+	own := p.dkg.dealtShares[p.id]
+	x, err := p.curve.Scalar.SetBytes(own.X.Value)
+	if err != nil {
+		return nil, err
+	}
+	gamma, err := p.curve.Scalar.SetBytes(own.Gamma.Value)
+	if err != nil {
+		return nil, err
+	}
+	d, err := p.curve.Scalar.SetBytes(own.D.Value)
+	if err != nil {
+		return nil, err
+	}
+	for peer, share := range shares {
+		if share == nil || share.X == nil || share.Gamma == nil || share.D == nil {
+			return nil, fmt.Errorf("missing shares of party %d", peer)
+		}
+		dealing := p.dkg.peerDealings[peer]
+		values := make([]curves.Scalar, 3)
+		for i, pair := range []struct {
+			share    *sharing.ShamirShare
+			verifier *sharing.FeldmanVerifier
+			name     string
+		}{{share.X, dealing.XVerifier, "x"}, {share.Gamma, dealing.GammaVerifier, "gamma"}, {share.D, dealing.DVerifier, "d"}} {
+			if pair.share.Id != p.id {
+				return nil, fmt.Errorf("party %d sent the share of %s of party %d", peer, pair.name, pair.share.Id)
+			}
+			if err := pair.verifier.Verify(pair.share); err != nil {
+				return nil, fmt.Errorf("invalid share of %s from party %d", pair.name, peer)
+			}
+			if values[i], err = p.curve.Scalar.SetBytes(pair.share.Value); err != nil {
+				return nil, err
+			}
+		}
+		x, gamma, d = x.Add(values[0]), gamma.Add(values[1]), d.Add(values[2])
+	}
+
+	dealings := map[uint32]*DKGPhase5Commitments{p.id: p.dkg.dealing}
+	for peer, dealing := range p.dkg.peerDealings {
+		dealings[peer] = dealing
+	}
+	p.QShares = make(map[uint32]curves.Point, len(dealings))
+	p.TShares = make(map[uint32]curves.Point, len(dealings))
+	for _, j := range p.ids() {
+		QShare := p.curve.NewIdentityPoint()
+		TShare := p.curve.NewIdentityPoint()
+		for _, dealing := range dealings {
+			QShare = QShare.Add(evaluateCommitments(p.curve, dealing.XVerifier, j))
+			TShare = TShare.Add(evaluateCommitments(p.curve, dealing.DVerifier, j))
+		}
+		p.QShares[j], p.TShares[j] = QShare, TShare
+	}
+
+	p.x, p.gamma, p.d = x, gamma, d
+	p.sigma = p.dkg.sigma
+	p.dkg = nil
+	return p.DKGOutput(), nil
+}
+
+// SetSigners selects the parties that take part in the following signatures, which abandons any signature in progress.
+// Without a threshold every party must sign, whereas after a threshold DKG at least t+1 parties must sign, and their
+// shares of gamma and d are weighted by their Lagrange coefficients. Every signer must select the same parties.
+func (p *Participant[A, B]) SetSigners(signers []uint32) error {
+	if p.sigma == nil {
+		return fmt.Errorf("DKG has not completed")
+	}
+	min := p.t + 1
+	if p.t == 0 {
+		min = len(p.peers) + 1
+	}
+	if len(signers) < min {
+		return fmt.Errorf("at least %d signers are required, got %d", min, len(signers))
+	}
+	seen := make(map[uint32]bool, len(signers))
+	var peers []uint32
+	for _, id := range signers {
+		if seen[id] {
+			return fmt.Errorf("duplicate signer %d", id)
+		}
+		seen[id] = true
+		if id == p.id {
+			continue
+		}
+		if _, ok := p.QProofs[id]; !ok {
+			return fmt.Errorf("unknown party %d", id)
+		}
+		peers = append(peers, id)
+	}
+	if !seen[p.id] {
+		return fmt.Errorf("party %d is not one of the signers", p.id)
+	}
+	p.sign = nil
+
+	// without threshold sharing every party signs with its additive shares
+	if p.t == 0 {
+		p.signers = peers
+		return nil
+	}
+
+	shamir, err := sharing.NewShamir(uint32(p.t+1), p.shareLimit(), p.curve)
+	if err != nil {
+		return err
+	}
+	lambdas, err := shamir.LagrangeCoeffs(signers)
+	if err != nil {
+		return err
+	}
+	p.signTs = make(map[uint32]curves.Point, len(signers))
+	for _, id := range signers {
+		p.signTs[id] = p.TShares[id].Mul(lambdas[id])
+	}
+	p.signGamma = lambdas[p.id].Mul(p.gamma)
+	p.signD = lambdas[p.id].Mul(p.d)
+	p.signers = peers
+	return nil
+}
+
+// Signers returns the identifiers of the peers that take part in the following signatures
+func (p *Participant[A, B]) Signers() []uint32 {
+	return append([]uint32{}, p.signers...)
+}
+
+// checkVerifier makes sure that a dealer committed to a polynomial of degree t
+func (p *Participant[A, B]) checkVerifier(verifier *sharing.FeldmanVerifier) error {
+	if verifier == nil || len(verifier.Commitments) != p.t+1 {
+		return fmt.Errorf("expected %d Feldman commitments", p.t+1)
+	}
+	for _, commitment := range verifier.Commitments {
+		if commitment == nil || commitment.CurveName() != p.curve.Name {
+			return fmt.Errorf("invalid Feldman commitment")
+		}
+	}
+	return nil
+}
+
+// ids returns the identifiers of every party, including ours
+func (p *Participant[A, B]) ids() []uint32 {
+	return append([]uint32{p.id}, p.peers...)
+}
+
+// shareLimit is the number of shares to split into, which is the largest identifier
+func (p *Participant[A, B]) shareLimit() uint32 {
+	limit := p.id
+	for _, id := range p.peers {
+		if id > limit {
+			limit = id
+		}
+	}
+	return limit
+}
+
+// evaluateCommitments returns the public counterpart of the share of id, i.e. sum(C_k * id^k)
+func evaluateCommitments(curve *curves.Curve, verifier *sharing.FeldmanVerifier, id uint32) curves.Point {
+	x := curve.Scalar.New(int(id))
+	power := curve.Scalar.One()
+	result := verifier.Commitments[0]
+	for _, commitment := range verifier.Commitments[1:] {
+		power = power.Mul(x)
+		result = result.Add(commitment.Mul(power))
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+package participant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/core/protocol"
+	"github.com/coinbase/kryptology/pkg/sharing"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/verify"
+)
+
+func runThresholdDKG(t *testing.T, participants map[uint32]*testParticipant, threshold int) map[uint32]*DKGOutput {
+	for _, p := range participants {
+		require.NoError(t, p.SetThreshold(threshold))
+	}
+	for _, output := range runDKG(t, participants) {
+		require.Nil(t, output, "the threshold DKG continues with DKGPhase5")
+	}
+	none := func(uint32) struct{} { return struct{}{} }
+	r1 := runRound(t, participants, none, func(p *testParticipant, _ struct{}) (*DKGPhase5Commitments, error) { return p.DKGPhase5Deal() })
+	r2 := runRound(t, participants, broadcast(t, r1), (*testParticipant).DKGPhase5Share)
+	return runRound(t, participants, direct(t, r2), (*testParticipant).DKGPhase5Finalize)
+}
+
+// runThresholdDS lets signers, and only them, sign message
+func runThresholdDS(t *testing.T, participants map[uint32]*testParticipant, signers []uint32, message []byte) map[uint32]*Signature {
+	signing := make(map[uint32]*testParticipant, len(signers))
+	for _, id := range signers {
+		require.NoError(t, participants[id].SetSigners(signers))
+		signing[id] = participants[id]
+	}
+	return runDS(t, signing, message)
+}
+
+func TestParticipantsThresholdDKGAndDS(t *testing.T) {
+	for _, curve := range []*curves.Curve{curves.SM2(), curves.K256()} {
+		ids := []uint32{2, 5, 7, 11, 12}
+		participants := newParticipants(t, curve, ids, nil)
+		outputs := runThresholdDKG(t, participants, 2)
+		Q := outputs[2].Q
+		for _, output := range outputs {
+			require.True(t, output.Q.Equal(Q))
+		}
+
+		// every party ends up with shares of x and d that match the public key shares that all parties computed
+		P := curve.NewGeneratorPoint()
+		for id, p := range participants {
+			require.True(t, P.Mul(p.x).Equal(p.QShares[id]))
+			require.True(t, P.Mul(p.d).Equal(p.TShares[id]))
+			for _, other := range participants {
+				require.True(t, other.QShares[id].Equal(p.QShares[id]))
+				require.True(t, other.TShares[id].Equal(p.TShares[id]))
+			}
+		}
+
+		for i, signers := range [][]uint32{{2, 7, 12}, {11, 5, 7}, {2, 5, 7, 11}} {
+			message := []byte{byte(i)}
+			for _, signature := range runThresholdDS(t, participants, signers, message) {
+				pk, standard := verify.ToStandard(curve, Q, signature.R, signature.S)
+				require.NoError(t, sm2.Verify(curve, pk, sm2.DefaultSignerId, message, standard))
+			}
+		}
+	}
+}
+
+func TestParticipantSetThreshold(t *testing.T) {
+	curve := curves.SM2()
+	participants := newParticipants(t, curve, []uint32{1, 2, 3, 4}, nil)
+	p := participants[1]
+	require.Error(t, p.SetThreshold(0))
+	require.Error(t, p.SetThreshold(4))
+	require.Error(t, p.SetSigners([]uint32{1, 2}), "the DKG has not completed")
+
+	wide := newParticipants(t, curve, []uint32{1, 256}, nil)
+	require.Error(t, wide[1].SetThreshold(1))
+
+	runThresholdDKG(t, participants, 1)
+	require.Error(t, p.SetThreshold(1), "the DKG has already run")
+	_, err := p.DSPhase1Commit([]byte("message"))
+	require.Error(t, err, "no signers selected")
+	require.Error(t, p.SetSigners([]uint32{1}))
+	require.Error(t, p.SetSigners([]uint32{1, 1}))
+	require.Error(t, p.SetSigners([]uint32{1, 9}))
+	require.Error(t, p.SetSigners([]uint32{2, 3}), "the participant must sign")
+	runThresholdDS(t, participants, []uint32{4, 1}, []byte("test message"))
+}
+
+func TestParticipantSetSignersWithoutThreshold(t *testing.T) {
+	participants := newParticipants(t, curves.SM2(), []uint32{1, 2, 3}, nil)
+	runDKG(t, participants)
+	require.Error(t, participants[1].SetSigners([]uint32{1, 2}), "every party must sign")
+	runThresholdDS(t, participants, []uint32{3, 1, 2}, []byte("test message"))
+}
+
+func TestParticipantThresholdRejectsBadDealing(t *testing.T) {
+	curve := curves.SM2()
+	participants := newParticipants(t, curve, []uint32{1, 2, 3}, nil)
+	for _, p := range participants {
+		require.NoError(t, p.SetThreshold(1))
+	}
+	runDKG(t, participants)
+	none := func(uint32) struct{} { return struct{}{} }
+	r1 := runRound(t, participants, none, func(p *testParticipant, _ struct{}) (*DKGPhase5Commitments, error) { return p.DKGPhase5Deal() })
+
+	// a dealer sharing something other than its ElGamal key is caught
+	forged := *r1[2]
+	forged.DVerifier = r1[2].XVerifier
+	_, err := participants[1].DKGPhase5Share(map[uint32]*DKGPhase5Commitments{2: &forged, 3: r1[3]})
+	require.Error(t, err)
+
+	// as is a dealer of a polynomial of the wrong degree
+	forged = *r1[2]
+	forged.XVerifier = &sharing.FeldmanVerifier{Commitments: r1[2].XVerifier.Commitments[:1]}
+	_, err = participants[1].DKGPhase5Share(map[uint32]*DKGPhase5Commitments{2: &forged, 3: r1[3]})
+	require.Error(t, err)
+}
+
+func TestParticipantThresholdRejectsBadShare(t *testing.T) {
+	curve := curves.K256()
+	participants := newParticipants(t, curve, []uint32{1, 2, 3}, nil)
+	for _, p := range participants {
+		require.NoError(t, p.SetThreshold(1))
+	}
+	runDKG(t, participants)
+	none := func(uint32) struct{} { return struct{}{} }
+	r1 := runRound(t, participants, none, func(p *testParticipant, _ struct{}) (*DKGPhase5Commitments, error) { return p.DKGPhase5Deal() })
+	r2 := runRound(t, participants, broadcast(t, r1), (*testParticipant).DKGPhase5Share)
+
+	// party 2 sends party 1 a share of gamma that does not match its commitments
+	shares := collectDirect(1, r2)
+	tampered := *shares[2]
+	tampered.Gamma = r2[2][3].Gamma
+	shares[2] = &tampered
+	_, err := participants[1].DKGPhase5Finalize(shares)
+	require.Error(t, err)
+}
+
+func TestParticipantThresholdProtocol(t *testing.T) {
+	curve := curves.SM2()
+	participants := newParticipants(t, curve, []uint32{1, 2, 3, 4}, nil)
+	dkgs := make(map[uint32]protocol.Iterator, len(participants))
+	for id, p := range participants {
+		require.NoError(t, p.SetThreshold(1))
+		dkgs[id] = NewDkg(p, protocol.Version1)
+	}
+	runIteratedProtocol(t, dkgs)
+	result, err := dkgs[1].Result(protocol.Version1)
+	require.NoError(t, err)
+	output, err := DecodeDkgOutput(result)
+	require.NoError(t, err)
+
+	message := []byte("message digest")
+	signers := []uint32{2, 4}
+	signs := make(map[uint32]protocol.Iterator, len(signers))
+	for _, id := range signers {
+		require.NoError(t, participants[id].SetSigners(signers))
+		signs[id] = NewSign(participants[id], message, protocol.Version1)
+	}
+	runIteratedProtocol(t, signs)
+	for _, sign := range signs {
+		result, err := sign.Result(protocol.Version1)
+		require.NoError(t, err)
+		signature, err := DecodeSignature(result)
+		require.NoError(t, err)
+		require.NoError(t, verify.Verify(curve, nil, output.Q, sm2.DefaultSignerId, message, signature.R, signature.S))
+	}
+}
@@ -0,0 +1,75 @@
+package scheme
+
+import (
+	"fmt"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/abort"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/decrypt"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+)
+
+// Decrypt decrypts a ciphertext that sm2.Encrypt produced for the published public key Q - G.
+// Every selected signer contributes x_i * C1 with a proof that it used its share of Q, so an n-of-n scheme needs all
+// n parties and a threshold scheme the t+1 or more parties passed to SetSigners. A party that sends a wrong
+// contribution is reported in an *abort.Error. An n-of-n scheme only needs DKGPhase1, a threshold scheme also needs
+// DKGPhase5, and both keep working after a refresh, which leaves Q fixed.
+// The contributions add up to x * C1, and C1 is subtracted from their sum to decrypt with the SM2 private key x - 1.
+func (scheme *Scheme[A, B]) Decrypt(ct *sm2.Ciphertext) ([]byte, error) {
+	if scheme.Q == nil {
+		return nil, fmt.Errorf("the DKG has not been run")
+	}
+	if scheme.signers == nil {
+		return nil, fmt.Errorf("no signers selected")
+	}
+	if ct == nil || ct.C1 == nil || ct.C1.IsIdentity() || !ct.C1.IsOnCurve() {
+		return nil, fmt.Errorf("invalid ciphertext")
+	}
+
+	// compute D_i = x_i * C1 and its proof
+	partialProofs := make(map[uint32]*chaumpedersen.Proof, len(scheme.signers))
+	partialProofSessionIds := make(map[uint32]chaumpedersen.SessionId, len(scheme.signers))
+	for _, id := range scheme.signers {
+		partialProof, partialProofSessionId, err := decrypt.PartialDecryptProve(scheme.curve, ct.C1, scheme.signX(id))
+		if err != nil {
+			return nil, err
+		}
+		partialProofs[id] = partialProof
+		partialProofSessionIds[id] = partialProofSessionId
+	}
+
+	return scheme.combinePartialDecryptions(ct, partialProofs, partialProofSessionIds)
+}
+
+// combinePartialDecryptions verifies the contributions of every party and recovers the plaintext from their sum
+func (scheme *Scheme[A, B]) combinePartialDecryptions(ct *sm2.Ciphertext, partialProofs map[uint32]*chaumpedersen.Proof, partialProofSessionIds map[uint32]chaumpedersen.SessionId) ([]byte, error) {
+	// verify the partial decryptions
+	/****************************************
+	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
+	****************************************/
+	for _, numParty := range scheme.signers {
+		for _, id := range scheme.signers {
+			if id == numParty {
+				continue
+			}
+			Qi := scheme.signQ(id)
+			err := decrypt.PartialDecryptVerify(scheme.curve, ct.C1, Qi, partialProofs[id], partialProofSessionIds[id])
+			if err != nil {
+				return nil, abort.New(id, "Decrypt", &abort.Evidence{
+					Check:     abort.DecPartial,
+					Proof:     partialProofs[id],
+					SessionId: partialProofSessionIds[id],
+					Inputs:    []curves.Point{ct.C1, Qi},
+				}, err)
+			}
+		}
+	}
+
+	// recover the plaintext and check it against C3
+	Ds := make([]curves.Point, 0, len(scheme.signers))
+	for _, id := range scheme.signers {
+		Ds = append(Ds, partialProofs[id].Statement2)
+	}
+	return decrypt.Combine(ct, Ds)
+}
@@ -0,0 +1,97 @@
+package scheme
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	mta_ot "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/ot"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/abort"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/decrypt"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+)
+
+func TestDecrypt(t *testing.T) {
+	plaintext := []byte("custody backup")
+	for _, curve := range []*curves.Curve{curves.SM2(), curves.K256()} {
+		scheme, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curve, []uint32{3, 1, 8}, nil)
+		require.NoError(t, err)
+		_, err = scheme.Decrypt(&sm2.Ciphertext{C1: curve.NewGeneratorPoint()})
+		require.Error(t, err, "the DKG has not been run")
+		require.NoError(t, scheme.DKGPhase1())
+
+		ct, err := sm2.Encrypt(curve, scheme.Q.Sub(scheme.P), plaintext)
+		require.NoError(t, err)
+		decrypted, err := scheme.Decrypt(ct)
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+
+		// x - 1 = sum(x_i) - 1 is an ordinary SM2 decryption key for Q - G
+		x := curve.Scalar.One().Neg()
+		for _, xi := range scheme.xs {
+			x = x.Add(xi)
+		}
+		decrypted, err = sm2.Decrypt(curve, x, ct)
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+
+		// a ciphertext that does not match C3 is rejected
+		c2 := append([]byte{}, ct.C2...)
+		c2[0] ^= 1
+		_, err = scheme.Decrypt(&sm2.Ciphertext{C1: ct.C1, C3: ct.C3, C2: c2})
+		require.Error(t, err)
+	}
+}
+
+func TestDecryptAfterRefresh(t *testing.T) {
+	curve := curves.SM2()
+	scheme, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curve, []uint32{1, 2}, nil)
+	require.NoError(t, err)
+	setOTMtA(t, scheme)
+	for i, phase := range []func() error{scheme.DKGPhase1, scheme.DKGPhase2, scheme.DKGPhase3, scheme.DKGPhase4} {
+		require.NoError(t, phase(), "failed in Phase %d of DKG", i+1)
+	}
+	ct, err := sm2.Encrypt(curve, scheme.Q.Sub(scheme.P), []byte("before refresh"))
+	require.NoError(t, err)
+	require.NoError(t, scheme.Refresh())
+	decrypted, err := scheme.Decrypt(ct)
+	require.NoError(t, err)
+	require.Equal(t, []byte("before refresh"), decrypted)
+}
+
+func TestDecryptIdentifiesCulprit(t *testing.T) {
+	curve := curves.SM2()
+	scheme, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curve, []uint32{3, 1, 8}, nil)
+	require.NoError(t, err)
+	require.NoError(t, scheme.DKGPhase1())
+	ct, err := sm2.Encrypt(curve, scheme.Q.Sub(scheme.P), []byte("custody backup"))
+	require.NoError(t, err)
+
+	partialProofs := make(map[uint32]*chaumpedersen.Proof, scheme.n)
+	partialProofSessionIds := make(map[uint32]chaumpedersen.SessionId, scheme.n)
+	for _, id := range scheme.ids {
+		partialProofs[id], partialProofSessionIds[id], err = decrypt.PartialDecryptProve(curve, ct.C1, scheme.xs[id])
+		require.NoError(t, err)
+	}
+	// party 1 contributes with a key other than its share of Q, with a proof that is valid for that key
+	partialProofs[1], _, err = decrypt.PartialDecryptProve(curve, ct.C1, curve.Scalar.Random(rand.Reader))
+	require.NoError(t, err)
+
+	_, err = scheme.combinePartialDecryptions(ct, partialProofs, partialProofSessionIds)
+	var abortErr *abort.Error
+	require.True(t, errors.As(err, &abortErr))
+	require.Equal(t, uint32(1), abortErr.Culprit)
+	require.Equal(t, abort.DecPartial, abortErr.Evidence.Check)
+	confirmed, err := abortErr.Evidence.Confirm(curve, &abort.Evidence{
+		Check:     abort.DecPartial,
+		Proof:     partialProofs[1],
+		SessionId: partialProofSessionIds[1],
+		Inputs:    []curves.Point{ct.C1, scheme.QProofs[1].Statement},
+	})
+	require.NoError(t, err)
+	require.True(t, confirmed)
+}
@@ -0,0 +1,225 @@
+package scheme
+
+import (
+	"fmt"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/abort"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/ds"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/exchange"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+)
+
+// KeyExchange is the side of an SM2 key exchange from GB/T 32918.3 that is held by the parties of a scheme.
+// Its static public key is the standard SM2 public key Q - G and its identifier is the signerId of the scheme, so the
+// peer can run sm2.KeyExchange against it. Like Decrypt, it is run by the selected signers.
+type KeyExchange[A any, B any] struct {
+	scheme    *Scheme[A, B]
+	initiator bool
+	signers   []uint32
+
+	z      []byte
+	peerPk curves.Point
+	peerZ  []byte
+
+	rs               map[uint32]curves.Scalar
+	RProofs          map[uint32]*schnorr.Proof
+	RCommitments     map[uint32]schnorr.Commitment
+	RProofSessionIds map[uint32]schnorr.SessionId
+	R                curves.Point
+
+	transcript *sm2.ExchangeTranscript
+	shared     curves.Point
+}
+
+// NewKeyExchange starts a key exchange with the peer whose static public key is peerPk, in which every party draws
+// its share of the ephemeral key. A nil peerId selects the default distinguishing identifier.
+func (scheme *Scheme[A, B]) NewKeyExchange(peerPk curves.Point, peerId []byte, initiator bool) (*KeyExchange[A, B], error) {
+	if scheme.Q == nil {
+		return nil, fmt.Errorf("the DKG has not been run")
+	}
+	if scheme.signers == nil {
+		return nil, fmt.Errorf("no signers selected")
+	}
+	if peerId == nil {
+		peerId = sm2.DefaultSignerId
+	}
+	z, err := sm2.ZA(scheme.curve, scheme.signerId, scheme.Q.Sub(scheme.P))
+	if err != nil {
+		return nil, err
+	}
+	peerZ, err := sm2.ZA(scheme.curve, peerId, peerPk)
+	if err != nil {
+		return nil, err
+	}
+	ke := &KeyExchange[A, B]{
+		scheme:           scheme,
+		initiator:        initiator,
+		z:                z,
+		peerPk:           peerPk,
+		peerZ:            peerZ,
+		signers:          scheme.signers,
+		rs:               make(map[uint32]curves.Scalar, len(scheme.signers)),
+		RProofs:          make(map[uint32]*schnorr.Proof, len(scheme.signers)),
+		RCommitments:     make(map[uint32]schnorr.Commitment, len(scheme.signers)),
+		RProofSessionIds: make(map[uint32]schnorr.SessionId, len(scheme.signers)),
+	}
+
+	// generate the shares of the ephemeral key and proofs
+	for _, id := range ke.signers {
+		r, RProof, RCommitment, RProofSessionId, err := ds.NonceComProve(scheme.curve, nil)
+		if err != nil {
+			return nil, err
+		}
+		ke.rs[id] = r
+		ke.RProofs[id] = RProof
+		ke.RCommitments[id] = RCommitment
+		ke.RProofSessionIds[id] = RProofSessionId
+	}
+
+	// de-com and verify R proofs
+	/****************************************
+	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
+	****************************************/
+	for _, numParty := range ke.signers {
+		for _, id := range ke.signers {
+			if id == numParty {
+				continue
+			}
+			err := ds.NonceDeComVerify(scheme.curve, ke.RProofs[id], ke.RCommitments[id], ke.RProofSessionIds[id])
+			if err != nil {
+				return nil, abort.New(id, "NewKeyExchange", &abort.Evidence{
+					Check:      abort.DsNonceDeCom,
+					Proof:      ke.RProofs[id],
+					Commitment: ke.RCommitments[id],
+					SessionId:  ke.RProofSessionIds[id],
+				}, err)
+			}
+		}
+	}
+
+	// compute R
+	ke.R = ke.RProofs[ke.signers[0]].Statement
+	for _, id := range ke.signers[1:] {
+		ke.R = ke.R.Add(ke.RProofs[id].Statement)
+	}
+	if ke.R.IsIdentity() {
+		return nil, fmt.Errorf("the ephemeral key is the identity")
+	}
+	return ke, nil
+}
+
+// EphemeralKey returns R_A for the initiator or R_B for the responder, which is sent to the peer
+func (ke *KeyExchange[A, B]) EphemeralKey() curves.Point {
+	return ke.R
+}
+
+// Agree computes the shared key of keyLen bytes from the ephemeral key of the peer.
+// The shares of the ephemeral secret are erased, so Agree can only be called once.
+func (ke *KeyExchange[A, B]) Agree(peerR curves.Point, keyLen int) ([]byte, error) {
+	if ke.rs == nil {
+		return nil, fmt.Errorf("the key exchange has completed")
+	}
+	scheme := ke.scheme
+	if !sameSigners(ke.signers, scheme.signers) {
+		return nil, fmt.Errorf("the signers changed during the key exchange")
+	}
+	W, err := sm2.ExchangeBase(scheme.curve, ke.peerPk, peerR)
+	if err != nil {
+		return nil, err
+	}
+	xBar, err := sm2.ExchangeXBar(scheme.curve, ke.R)
+	if err != nil {
+		return nil, err
+	}
+
+	// compute V_i = (x_i + xBar * r_i) * W and its proof
+	agreeProofs := make(map[uint32]*chaumpedersen.Proof, len(ke.signers))
+	agreeProofSessionIds := make(map[uint32]chaumpedersen.SessionId, len(ke.signers))
+	for _, id := range ke.signers {
+		agreeProof, agreeProofSessionId, err := exchange.PartialAgreeProve(scheme.curve, W, xBar, scheme.signX(id), ke.rs[id])
+		if err != nil {
+			return nil, err
+		}
+		agreeProofs[id] = agreeProof
+		agreeProofSessionIds[id] = agreeProofSessionId
+	}
+	ke.rs = nil
+
+	shared, err := ke.combinePartialAgreements(W, xBar, agreeProofs, agreeProofSessionIds)
+	if err != nil {
+		return nil, err
+	}
+	ke.shared = shared
+	ke.transcript = sm2.NewExchangeTranscript(ke.initiator, ke.z, ke.R, ke.peerZ, peerR)
+	return ke.transcript.Key(shared, keyLen), nil
+}
+
+// Confirmation returns the optional confirmation that this side sends after Agree, S_B for the responder and
+// S_A for the initiator
+func (ke *KeyExchange[A, B]) Confirmation() ([]byte, error) {
+	if ke.shared == nil {
+		return nil, fmt.Errorf("the key exchange has not completed")
+	}
+	if ke.initiator {
+		return ke.transcript.InitiatorConfirmation(ke.shared), nil
+	}
+	return ke.transcript.ResponderConfirmation(ke.shared), nil
+}
+
+// VerifyConfirmation checks the confirmation sent by the peer after Agree
+func (ke *KeyExchange[A, B]) VerifyConfirmation(peerConfirmation []byte) error {
+	if ke.shared == nil {
+		return fmt.Errorf("the key exchange has not completed")
+	}
+	return ke.transcript.VerifyConfirmation(!ke.initiator, ke.shared, peerConfirmation)
+}
+
+// combinePartialAgreements verifies the contributions of every party and returns the shared point
+func (ke *KeyExchange[A, B]) combinePartialAgreements(W curves.Point, xBar curves.Scalar, agreeProofs map[uint32]*chaumpedersen.Proof, agreeProofSessionIds map[uint32]chaumpedersen.SessionId) (curves.Point, error) {
+	scheme := ke.scheme
+
+	// verify the partial agreements
+	/****************************************
+	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
+	****************************************/
+	for _, numParty := range ke.signers {
+		for _, id := range ke.signers {
+			if id == numParty {
+				continue
+			}
+			Ti := exchange.PartialAgreeKey(xBar, scheme.signQ(id), ke.RProofs[id].Statement)
+			err := exchange.PartialAgreeVerify(scheme.curve, W, Ti, agreeProofs[id], agreeProofSessionIds[id])
+			if err != nil {
+				return nil, abort.New(id, "Agree", &abort.Evidence{
+					Check:     abort.ExAgree,
+					Proof:     agreeProofs[id],
+					SessionId: agreeProofSessionIds[id],
+					Inputs:    []curves.Point{W, Ti},
+				}, err)
+			}
+		}
+	}
+
+	// compute the shared point
+	Vs := make([]curves.Point, 0, len(ke.signers))
+	for _, id := range ke.signers {
+		Vs = append(Vs, agreeProofs[id].Statement2)
+	}
+	return exchange.Combine(W, Vs)
+}
+
+// sameSigners reports whether two selections of signers are the same
+func sameSigners(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package scheme
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	mta_ot "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/ot"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/abort"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/exchange"
+	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
+)
+
+func TestKeyExchange(t *testing.T) {
+	for _, curve := range []*curves.Curve{curves.SM2(), curves.K256()} {
+		for _, initiator := range []bool{true, false} {
+			scheme, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curve, []uint32{3, 1, 8}, []byte("custody"))
+			require.NoError(t, err)
+			skPeer, pkPeer := sm2.NewKeys(curve)
+			_, err = scheme.NewKeyExchange(pkPeer, nil, initiator)
+			require.Error(t, err, "the DKG has not been run")
+			require.NoError(t, scheme.DKGPhase1())
+
+			ke, err := scheme.NewKeyExchange(pkPeer, nil, initiator)
+			require.NoError(t, err)
+			peer, err := sm2.NewKeyExchange(curve, skPeer, nil, scheme.Q.Sub(scheme.P), []byte("custody"), !initiator)
+			require.NoError(t, err)
+
+			key, err := ke.Agree(peer.EphemeralKey(), 32)
+			require.NoError(t, err)
+			peerKey, err := peer.Agree(ke.EphemeralKey(), 32)
+			require.NoError(t, err)
+			require.Equal(t, peerKey, key)
+
+			confirmation, err := ke.Confirmation()
+			require.NoError(t, err)
+			require.NoError(t, peer.VerifyConfirmation(confirmation))
+			peerConfirmation, err := peer.Confirmation()
+			require.NoError(t, err)
+			require.NoError(t, ke.VerifyConfirmation(peerConfirmation))
+
+			_, err = ke.Agree(peer.EphemeralKey(), 32)
+			require.Error(t, err, "the ephemeral key has been used")
+		}
+	}
+}
+
+func TestKeyExchangeIdentifiesCulprit(t *testing.T) {
+	curve := curves.SM2()
+	scheme, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curve, []uint32{3, 1, 8}, nil)
+	require.NoError(t, err)
+	require.NoError(t, scheme.DKGPhase1())
+	_, pkPeer := sm2.NewKeys(curve)
+	ke, err := scheme.NewKeyExchange(pkPeer, nil, true)
+	require.NoError(t, err)
+
+	_, peerR := sm2.NewKeys(curve)
+	W, err := sm2.ExchangeBase(curve, pkPeer, peerR)
+	require.NoError(t, err)
+	xBar, err := sm2.ExchangeXBar(curve, ke.R)
+	require.NoError(t, err)
+	agreeProofs := make(map[uint32]*chaumpedersen.Proof, scheme.n)
+	agreeProofSessionIds := make(map[uint32]chaumpedersen.SessionId, scheme.n)
+	for _, id := range scheme.ids {
+		agreeProofs[id], agreeProofSessionIds[id], err = exchange.PartialAgreeProve(curve, W, xBar, scheme.xs[id], ke.rs[id])
+		require.NoError(t, err)
+	}
+	// party 8 contributes with a share of the ephemeral key other than the one it committed to
+	agreeProofs[8], _, err = exchange.PartialAgreeProve(curve, W, xBar, scheme.xs[8], curve.Scalar.Random(rand.Reader))
+	require.NoError(t, err)
+
+	_, err = ke.combinePartialAgreements(W, xBar, agreeProofs, agreeProofSessionIds)
+	var abortErr *abort.Error
+	require.True(t, errors.As(err, &abortErr))
+	require.Equal(t, uint32(8), abortErr.Culprit)
+	require.Equal(t, abort.ExAgree, abortErr.Evidence.Check)
+	confirmed, err := abortErr.Evidence.Confirm(curve, &abort.Evidence{
+		Check:     abort.ExAgree,
+		Proof:     agreeProofs[8],
+		SessionId: agreeProofSessionIds[8],
+		Inputs:    []curves.Point{W, exchange.PartialAgreeKey(xBar, scheme.QProofs[8].Statement, ke.RProofs[8].Statement)},
+	})
+	require.NoError(t, err)
+	require.True(t, confirmed)
+}
@@ -0,0 +1,202 @@
+package scheme
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+)
+
+// presignatureVersion is the version of the binary encoding of a Presignature
+const presignatureVersion = 1
+
+// Presignature is the message independent output of DSPhase1 to DSPhase4: the x-coordinate of R together with the
+// shares of gamma and delta of every signer. It can be computed ahead of time and is consumed by Sign, after which
+// signing a message takes a single round. A presignature must never be used twice, since two signatures with the
+// same nonce reveal the key, so Sign only uses it once the caller's PresignatureStore has recorded it as consumed.
+type Presignature struct {
+	epoch   uint64
+	signers []uint32
+	rx      curves.Scalar
+	gammas  map[uint32]curves.Scalar
+	deltas  map[uint32]curves.Scalar
+}
+
+// PresignatureStore records which presignatures have been consumed. It is kept by the caller, e.g. in the database
+// that also holds the encoded presignatures, so that a presignature restored from a backup or a copy is not used
+// again after a restart.
+type PresignatureStore interface {
+	// MarkUsed records that the presignature with the given identifier is consumed. It must fail if the identifier
+	// has been recorded before, and only return once the record is persisted.
+	MarkUsed(id []byte) error
+}
+
+// Presign runs DSPhase1 to DSPhase4 with the selected signers and returns the resulting presignature
+func (scheme *Scheme[A, B]) Presign() (*Presignature, error) {
+	if scheme.sigma == nil {
+		return nil, fmt.Errorf("the DKG has not been run")
+	}
+	for _, phase := range []func() error{scheme.DSPhase1, scheme.DSPhase2, scheme.DSPhase3, scheme.DSPhase4} {
+		if err := phase(); err != nil {
+			return nil, err
+		}
+	}
+	return scheme.presignature()
+}
+
+// Sign signs message with pre after marking it as used in store. The presignature is consumed even if signing fails.
+func (scheme *Scheme[A, B]) Sign(store PresignatureStore, pre *Presignature, message []byte) error {
+	if store == nil {
+		return fmt.Errorf("missing presignature store")
+	}
+	if pre == nil {
+		return fmt.Errorf("missing presignature")
+	}
+	if err := store.MarkUsed(pre.Id()); err != nil {
+		return fmt.Errorf("presignature cannot be consumed: %w", err)
+	}
+	if pre.epoch != scheme.epoch {
+		return fmt.Errorf("presignature predates the current key")
+	}
+	scheme.message = message
+	return scheme.finalize(pre)
+}
+
+// Id identifies the presignature by the epoch of the key and the x-coordinate of its nonce, which is the same for
+// every copy of it
+func (pre *Presignature) Id() []byte {
+	id := binary.BigEndian.AppendUint64(nil, pre.epoch)
+	return append(id, pre.rx.Bytes()...)
+}
+
+// MarshalBinary encodes the presignature. The encoding holds the secret shares of every signer.
+func (pre *Presignature) MarshalBinary() ([]byte, error) {
+	if pre.rx == nil {
+		return nil, fmt.Errorf("empty presignature")
+	}
+	buf := bytes.NewBuffer([]byte{presignatureVersion})
+	writeBytes(buf, []byte(pre.rx.Point().CurveName()))
+	_ = binary.Write(buf, binary.BigEndian, pre.epoch)
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(pre.signers)))
+	writeBytes(buf, pre.rx.Bytes())
+	for _, i := range pre.signers {
+		_ = binary.Write(buf, binary.BigEndian, i)
+		writeBytes(buf, pre.gammas[i].Bytes())
+		writeBytes(buf, pre.deltas[i].Bytes())
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a presignature encoded by MarshalBinary
+func (pre *Presignature) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewReader(data)
+	version, err := buf.ReadByte()
+	if err != nil {
+		return err
+	}
+	if version != presignatureVersion {
+		return fmt.Errorf("unsupported presignature version %d", version)
+	}
+	name, err := readBytes(buf)
+	if err != nil {
+		return err
+	}
+	curve := curves.GetCurveByName(string(name))
+	if curve == nil {
+		return fmt.Errorf("unknown curve %s", name)
+	}
+	var epoch uint64
+	var n uint32
+	if err := binary.Read(buf, binary.BigEndian, &epoch); err != nil {
+		return err
+	}
+	if err := binary.Read(buf, binary.BigEndian, &n); err != nil {
+		return err
+	}
+	if int(n) > buf.Len() {
+		return fmt.Errorf("invalid number of signers %d", n)
+	}
+	rx, err := readScalar(buf, curve)
+	if err != nil {
+		return err
+	}
+	decoded := Presignature{
+		epoch:   epoch,
+		signers: make([]uint32, 0, n),
+		rx:      rx,
+		gammas:  make(map[uint32]curves.Scalar, n),
+		deltas:  make(map[uint32]curves.Scalar, n),
+	}
+	for k := uint32(0); k < n; k++ {
+		var i uint32
+		if err := binary.Read(buf, binary.BigEndian, &i); err != nil {
+			return err
+		}
+		if _, ok := decoded.gammas[i]; ok {
+			return fmt.Errorf("duplicate signer %d", i)
+		}
+		if decoded.gammas[i], err = readScalar(buf, curve); err != nil {
+			return err
+		}
+		if decoded.deltas[i], err = readScalar(buf, curve); err != nil {
+			return err
+		}
+		decoded.signers = append(decoded.signers, i)
+	}
+	if buf.Len() != 0 {
+		return fmt.Errorf("trailing data after presignature")
+	}
+	*pre = decoded
+	return nil
+}
+
+// writeBytes writes b with a length prefix
+func writeBytes(w io.Writer, b []byte) {
+	_ = binary.Write(w, binary.BigEndian, uint32(len(b)))
+	_, _ = w.Write(b)
+}
+
+// readBytes reads a length-prefixed byte string
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+	if int(n) > r.Len() {
+		return nil, fmt.Errorf("truncated presignature")
+	}
+	b := make([]byte, n)
+	_, err := io.ReadFull(r, b)
+	return b, err
+}
+
+// readScalar reads a length-prefixed scalar of curve
+func readScalar(r *bytes.Reader, curve *curves.Curve) (curves.Scalar, error) {
+	b, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	return curve.Scalar.SetBytes(b)
+}
+
+// presignature takes the output of the last DSPhase4, so that the nonce cannot be handed out again
+func (scheme *Scheme[A, B]) presignature() (*Presignature, error) {
+	if scheme.rx == nil {
+		return nil, fmt.Errorf("no fresh nonce, run DSPhase1 to DSPhase4 first")
+	}
+	pre := &Presignature{
+		epoch:   scheme.epoch,
+		signers: append([]uint32(nil), scheme.signers...),
+		rx:      scheme.rx,
+		gammas:  make(map[uint32]curves.Scalar, len(scheme.signers)),
+		deltas:  make(map[uint32]curves.Scalar, len(scheme.signers)),
+	}
+	for _, i := range scheme.signers {
+		pre.gammas[i] = scheme.signGamma(i)
+		pre.deltas[i] = scheme.deltas[i]
+	}
+	scheme.rx = nil
+	return pre, nil
+}
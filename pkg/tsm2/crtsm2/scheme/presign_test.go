@@ -0,0 +1,114 @@
+package scheme
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	mta_ot "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/ot"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/verify"
+)
+
+// memoryStore records the consumed presignatures in memory, whereas a deployment persists them
+type memoryStore map[string]bool
+
+func (store memoryStore) MarkUsed(id []byte) error {
+	if store[string(id)] {
+		return fmt.Errorf("presignature has already been used")
+	}
+	store[string(id)] = true
+	return nil
+}
+
+func requireSigned(t *testing.T, scheme *Scheme[*mta_ot.Round1Output, *mta_ot.Round2Output], store memoryStore, pre *Presignature, message []byte) {
+	require.NoError(t, scheme.Sign(store, pre, message))
+	require.True(t, store[string(pre.Id())])
+	pk, sig := verify.ToStandard(scheme.curve, scheme.Q, scheme.r, scheme.s)
+	require.NoError(t, sm2.Verify(scheme.curve, pk, scheme.signerId, message, sig))
+}
+
+func TestPresign(t *testing.T) {
+	scheme, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curves.SM2(), []uint32{1, 2, 3}, nil)
+	require.NoError(t, err)
+	setOTMtA(t, scheme)
+	_, err = scheme.Presign()
+	require.Error(t, err, "the DKG has not been run")
+	for _, phase := range []func() error{scheme.DKGPhase1, scheme.DKGPhase2, scheme.DKGPhase3, scheme.DKGPhase4} {
+		require.NoError(t, phase())
+	}
+
+	first, err := scheme.Presign()
+	require.NoError(t, err)
+	second, err := scheme.Presign()
+	require.NoError(t, err)
+	require.NotEqual(t, first.Id(), second.Id())
+
+	// the nonce of the last presignature is not available to DSPhase5
+	require.Error(t, scheme.DSPhase5())
+
+	// presignatures can be consumed in any order, but only once
+	store := memoryStore{}
+	requireSigned(t, scheme, store, second, []byte("first message"))
+	requireSigned(t, scheme, store, first, []byte("second message"))
+	require.Error(t, scheme.Sign(store, first, []byte("third message")))
+	require.Error(t, scheme.Sign(store, nil, []byte("third message")))
+	require.Error(t, scheme.Sign(nil, first, []byte("third message")))
+}
+
+func TestPresignEncoding(t *testing.T) {
+	scheme := newThresholdScheme(t, curves.SM2(), []uint32{1, 2, 3}, 1)
+	runThresholdDKG(t, scheme)
+	require.NoError(t, scheme.SetSigners([]uint32{3, 1}))
+	pre, err := scheme.Presign()
+	require.NoError(t, err)
+
+	data, err := pre.MarshalBinary()
+	require.NoError(t, err)
+	restored := &Presignature{}
+	require.NoError(t, restored.UnmarshalBinary(data))
+	require.Equal(t, pre.Id(), restored.Id())
+	require.Equal(t, pre.signers, restored.signers)
+
+	// the restored copy signs, and the store stops the original from being used again
+	store := memoryStore{}
+	requireSigned(t, scheme, store, restored, []byte("test message"))
+	require.Error(t, scheme.Sign(store, pre, []byte("another message")))
+
+	for _, corrupted := range [][]byte{nil, {presignatureVersion + 1}, data[:len(data)-1], append(data, 0)} {
+		require.Error(t, (&Presignature{}).UnmarshalBinary(corrupted))
+	}
+}
+
+func TestPresignThreshold(t *testing.T) {
+	scheme := newThresholdScheme(t, curves.K256(), []uint32{1, 2, 3, 4}, 1)
+	runThresholdDKG(t, scheme)
+
+	require.NoError(t, scheme.SetSigners([]uint32{1, 3}))
+	first, err := scheme.Presign()
+	require.NoError(t, err)
+	require.NoError(t, scheme.SetSigners([]uint32{2, 4}))
+	second, err := scheme.Presign()
+	require.NoError(t, err)
+
+	// a presignature keeps the weighted shares of its own signers
+	store := memoryStore{}
+	requireSigned(t, scheme, store, first, []byte("first message"))
+	requireSigned(t, scheme, store, second, []byte("second message"))
+}
+
+func TestPresignStale(t *testing.T) {
+	scheme := newThresholdScheme(t, curves.SM2(), []uint32{1, 2, 3}, 1)
+	runThresholdDKG(t, scheme)
+	require.NoError(t, scheme.SetSigners([]uint32{1, 2}))
+	pre, err := scheme.Presign()
+	require.NoError(t, err)
+
+	// a refresh changes sigma, so presignatures made before it are rejected
+	require.NoError(t, scheme.Refresh())
+	store := memoryStore{}
+	require.Error(t, scheme.Sign(store, pre, []byte("test message")))
+	require.True(t, store[string(pre.Id())])
+}
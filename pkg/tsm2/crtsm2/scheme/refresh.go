@@ -0,0 +1,138 @@
+package scheme
+
+import (
+	"fmt"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/abort"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/dkg"
+	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
+)
+
+// Refresh re-randomizes every key share while keeping Q fixed.
+// It runs RefreshPhase1, then DKGPhase2 to DKGPhase4 to draw a fresh gamma and encrypt gamma and sigma
+// under the new ElGamal key, and finally DKGPhase5 to deal new shares in a threshold scheme.
+func (scheme *Scheme[A, B]) Refresh() error {
+	phases := []func() error{scheme.RefreshPhase1, scheme.DKGPhase2, scheme.DKGPhase3, scheme.DKGPhase4}
+	if scheme.t != 0 {
+		phases = append(phases, scheme.DKGPhase5)
+	}
+	for _, phase := range phases {
+		if err := phase(); err != nil {
+			return err
+		}
+	}
+	if scheme.t != 0 {
+		// the Lagrange-weighted shares of the previous signers are stale
+		scheme.signers = nil
+	}
+	return nil
+}
+
+// RefreshPhase1 adds a sharing of zero to the shares x_i, so that Q stays the same, and draws a fresh ElGamal key.
+func (scheme *Scheme[A, B]) RefreshPhase1() error {
+	if scheme.Q == nil {
+		return fmt.Errorf("the DKG has not been run")
+	}
+
+	// split zero
+	zeroShares := make(map[uint32]map[uint32]curves.Scalar, scheme.n)
+	zeroShareProofs := make(map[uint32]map[uint32]*schnorr.Proof, scheme.n)
+	zeroShareProofSessionIds := make(map[uint32]schnorr.SessionId, scheme.n)
+	for _, i := range scheme.ids {
+		shares, proofs, sessionId, err := dkg.ZeroSharesProve(scheme.curve, scheme.ids, nil)
+		if err != nil {
+			return err
+		}
+		zeroShares[i] = shares
+		zeroShareProofs[i] = proofs
+		zeroShareProofSessionIds[i] = sessionId
+	}
+
+	// verify the sharings of zero and the received shares
+	/****************************************
+	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
+	****************************************/
+	for _, numParty := range scheme.ids {
+		for _, i := range scheme.ids {
+			if i == numParty {
+				continue
+			}
+			err := dkg.ZeroSharesVerify(scheme.curve, zeroShareProofs[i], zeroShareProofSessionIds[i])
+			if err != nil {
+				return fmt.Errorf("invalid sharing of zero from party %d: %w", i, err)
+			}
+			if !scheme.P.Mul(zeroShares[i][numParty]).Equal(zeroShareProofs[i][numParty].Statement) {
+				return fmt.Errorf("invalid share of zero from party %d to party %d", i, numParty)
+			}
+		}
+	}
+
+	// compute the refreshed Q_i
+	/****************************************
+	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
+	****************************************/
+	Qs := make(map[uint32]curves.Point, scheme.n)
+	for _, j := range scheme.ids {
+		Qs[j] = scheme.QProofs[j].Statement
+		for _, i := range scheme.ids {
+			Qs[j] = Qs[j].Add(zeroShareProofs[i][j].Statement)
+		}
+	}
+
+	// refresh x_i and prove knowledge of it
+	for _, j := range scheme.ids {
+		x := scheme.xs[j]
+		for _, i := range scheme.ids {
+			x = x.Add(zeroShares[i][j])
+		}
+		QProof, QCommitment, QProofSessionId, err := dkg.PkReComProve(scheme.curve, x, nil)
+		if err != nil {
+			return err
+		}
+		scheme.xs[j] = x
+		scheme.QProofs[j] = QProof
+		scheme.QCommitments[j] = QCommitment
+		scheme.QProofSessionIds[j] = QProofSessionId
+	}
+
+	// de-com and verify the refreshed Q proofs
+	/****************************************
+	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
+	****************************************/
+	for _, numParty := range scheme.ids {
+		for _, id := range scheme.ids {
+			if id == numParty {
+				continue
+			}
+			err := dkg.PkDeComVerify(scheme.curve, scheme.QProofs[id], scheme.QCommitments[id], scheme.QProofSessionIds[id])
+			if err != nil {
+				return abort.New(id, "RefreshPhase1", &abort.Evidence{
+					Check:      abort.DkgPkDeCom,
+					Proof:      scheme.QProofs[id],
+					Commitment: scheme.QCommitments[id],
+					SessionId:  scheme.QProofSessionIds[id],
+				}, err)
+			}
+			if !scheme.QProofs[id].Statement.Equal(Qs[id]) {
+				return fmt.Errorf("party %d did not refresh its share correctly", id)
+			}
+		}
+	}
+
+	// check that Q is unchanged
+	/****************************************
+	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
+	****************************************/
+	for range scheme.ids {
+		Q := scheme.QProofs[scheme.ids[0]].Statement
+		for _, id := range scheme.ids[1:] {
+			Q = Q.Add(scheme.QProofs[id].Statement)
+		}
+		if !Q.Equal(scheme.Q) {
+			return fmt.Errorf("the refresh changed Q")
+		}
+	}
+
+	return scheme.elGamalKeyGen("RefreshPhase1")
+}
@@ -0,0 +1,89 @@
+package scheme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	mta_ot "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/ot"
+)
+
+// refreshedState is the part of the key material that a refresh must change
+type refreshedState struct {
+	xs     map[uint32]curves.Scalar
+	T      curves.Point
+	UGamma curves.Point
+	USigma curves.Point
+}
+
+func snapshot(scheme *Scheme[*mta_ot.Round1Output, *mta_ot.Round2Output]) *refreshedState {
+	state := &refreshedState{
+		xs:     make(map[uint32]curves.Scalar, scheme.n),
+		T:      scheme.T,
+		UGamma: scheme.UGamma,
+		USigma: scheme.USigma,
+	}
+	for id, x := range scheme.xs {
+		state.xs[id] = x
+	}
+	return state
+}
+
+func requireRefreshed(t *testing.T, scheme *Scheme[*mta_ot.Round1Output, *mta_ot.Round2Output], Q curves.Point, before *refreshedState) {
+	require.True(t, scheme.Q.Equal(Q))
+	for id, x := range scheme.xs {
+		require.NotEqual(t, 0, x.Cmp(before.xs[id]), "x of party %d was not refreshed", id)
+	}
+	require.False(t, scheme.T.Equal(before.T))
+	require.False(t, scheme.UGamma.Equal(before.UGamma))
+	require.False(t, scheme.USigma.Equal(before.USigma))
+}
+
+func TestRefresh(t *testing.T) {
+	scheme, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curves.SM2(), []uint32{3, 1, 8}, nil)
+	require.NoError(t, err)
+	setOTMtA(t, scheme)
+	require.Error(t, scheme.Refresh(), "the DKG has not been run")
+
+	for i, phase := range []func() error{scheme.DKGPhase1, scheme.DKGPhase2, scheme.DKGPhase3, scheme.DKGPhase4} {
+		require.NoError(t, phase(), "failed in Phase %d of DKG", i+1)
+	}
+	runThresholdDS(t, scheme, scheme.ids, []byte("before refresh"))
+
+	Q := scheme.Q
+	for round := 0; round < 2; round++ {
+		before := snapshot(scheme)
+		require.NoError(t, scheme.Refresh())
+		requireRefreshed(t, scheme, Q, before)
+		runThresholdDS(t, scheme, scheme.ids, []byte("after refresh"))
+	}
+}
+
+func TestRefreshThreshold(t *testing.T) {
+	scheme := newThresholdScheme(t, curves.SM2(), []uint32{1, 2, 3, 4}, 1)
+	runThresholdDKG(t, scheme)
+	runThresholdDS(t, scheme, []uint32{1, 3}, []byte("before refresh"))
+
+	Q := scheme.Q
+	before := snapshot(scheme)
+	require.NoError(t, scheme.Refresh())
+	requireRefreshed(t, scheme, Q, before)
+	require.Error(t, scheme.DSPhase1(), "the signers must be selected again")
+
+	runThresholdDS(t, scheme, []uint32{2, 4}, []byte("after refresh"))
+	runThresholdDS(t, scheme, []uint32{1, 3}, []byte("after refresh"))
+}
+
+func TestRefreshRejectsWrongShare(t *testing.T) {
+	scheme, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curves.SM2(), []uint32{1, 2, 3}, nil)
+	require.NoError(t, err)
+	setOTMtA(t, scheme)
+	for _, phase := range []func() error{scheme.DKGPhase1, scheme.DKGPhase2, scheme.DKGPhase3, scheme.DKGPhase4} {
+		require.NoError(t, phase())
+	}
+
+	// a share of x that drifts away from the sharing of zero is caught by its proof
+	scheme.xs[2] = scheme.xs[2].Add(scheme.curve.Scalar.One())
+	require.Error(t, scheme.RefreshPhase1())
+}
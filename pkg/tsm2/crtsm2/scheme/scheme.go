@@ -3,20 +3,21 @@ package scheme
 import (
 	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/sharing"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
 	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/sign_offline"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/abort"
 	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/dkg"
 	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/ds"
 	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/verify"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
 	"github.com/coinbase/kryptology/pkg/zkp/chaumpedersen"
 	"github.com/coinbase/kryptology/pkg/zkp/reg"
 	"github.com/coinbase/kryptology/pkg/zkp/rre"
 	"github.com/coinbase/kryptology/pkg/zkp/rspdl"
 	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
-	"math/big"
 )
 
-const num = 2
-
 type MTAReceiver[A any, B any] interface {
 	Init(curves.Scalar) A
 	Multiply(B) curves.Scalar
@@ -30,89 +31,117 @@ type Scheme[A any, B any] struct {
 	curve *curves.Curve
 	P     curves.Point
 
-	n int
+	n   int
+	ids []uint32
 
-	message []byte
+	message  []byte
+	signerId []byte
 
-	xs               [num]curves.Scalar
-	QProofs          [num]*schnorr.Proof
-	QCommitments     [num]schnorr.Commitment
-	QProofSessionIds [num]schnorr.SessionId
+	xs               map[uint32]curves.Scalar
+	QProofs          map[uint32]*schnorr.Proof
+	QCommitments     map[uint32]schnorr.Commitment
+	QProofSessionIds map[uint32]schnorr.SessionId
 	Q                curves.Point
 
-	ds               [num]curves.Scalar
-	TProofs          [num]*schnorr.Proof
-	TCommitments     [num]schnorr.Commitment
-	TProofSessionIds [num]schnorr.Commitment
+	ds               map[uint32]curves.Scalar
+	TProofs          map[uint32]*schnorr.Proof
+	TCommitments     map[uint32]schnorr.Commitment
+	TProofSessionIds map[uint32]schnorr.Commitment
 	T                curves.Point
 
-	gammas                     [num]curves.Scalar
-	xGammas                    [num]curves.Scalar
-	gammaRegProofs             [num]*reg.Proof
-	gammaRegProofSessionIds    [num]reg.SessionId
+	gammas                     map[uint32]curves.Scalar
+	rGammas                    map[uint32]curves.Scalar
+	xGammas                    map[uint32]curves.Scalar
+	gammaRegProofs             map[uint32]*reg.Proof
+	gammaRegProofSessionIds    map[uint32]reg.SessionId
 	UGamma                     curves.Point
 	VGamma                     curves.Point
-	xGammaRspdlProofs          [num]*rspdl.Proof
-	xGammaRspdlProofSessionIds [num]rspdl.SessionId
+	xGammaRspdlProofs          map[uint32]*rspdl.Proof
+	xGammaRspdlProofSessionIds map[uint32]rspdl.SessionId
 	UXGamma                    curves.Point
 	VXGamma                    curves.Point
 
-	alphas       [num][num]curves.Scalar
-	betas        [num][num]curves.Scalar
-	mtaSenders   [num][num]sign_offline.MTASender[A, B]
-	mtaReceivers [num][num]sign_offline.MTAReceiver[A, B]
+	alphas       map[uint32]map[uint32]curves.Scalar
+	betas        map[uint32]map[uint32]curves.Scalar
+	mtaSenders   map[uint32]map[uint32]sign_offline.MTASender[A, B]
+	mtaReceivers map[uint32]map[uint32]sign_offline.MTAReceiver[A, B]
 
-	sigmas                  [num]curves.Scalar
-	rSigmas                 [num]curves.Scalar
-	sigmaRegProofs          [num]*reg.Proof
-	sigmaRegProofSessionIds [num]reg.SessionId
+	sigmas                  map[uint32]curves.Scalar
+	rSigmas                 map[uint32]curves.Scalar
+	sigmaRegProofs          map[uint32]*reg.Proof
+	sigmaRegProofSessionIds map[uint32]reg.SessionId
 	USigma                  curves.Point
 	VSigma                  curves.Point
 
 	U curves.Point
 	V curves.Point
 
-	rreProofs          [num]*rre.Proof
-	rreCommitments     [num]rre.Commitment
-	rreProofSessionIds [num]rre.SessionId
+	rreProofs          map[uint32]*rre.Proof
+	rreCommitments     map[uint32]rre.Commitment
+	rreProofSessionIds map[uint32]rre.SessionId
 
 	UPrime  curves.Point
 	VPrime  curves.Point
-	UPrimes [num]curves.Point
+	UPrimes map[uint32]curves.Point
 
-	ddhProofs          [num]*chaumpedersen.Proof
-	ddhCommitments     [num]chaumpedersen.Commitment
-	ddhProofSessionIds [num]chaumpedersen.SessionId
+	ddhProofs          map[uint32]*chaumpedersen.Proof
+	ddhCommitments     map[uint32]chaumpedersen.Commitment
+	ddhProofSessionIds map[uint32]chaumpedersen.SessionId
 
-	VSigmaPrimes            [num]curves.Point
-	sigmaDDHProofs          [num]*chaumpedersen.Proof
-	sigmaDDHProofSessionIds [num]chaumpedersen.SessionId
+	VSigmaPrimes            map[uint32]curves.Point
+	sigmaDDHProofs          map[uint32]*chaumpedersen.Proof
+	sigmaDDHProofSessionIds map[uint32]chaumpedersen.SessionId
 
 	sigma curves.Scalar
+	// epoch counts the runs of DKGPhase4, presignatures from an earlier epoch were made for a stale sigma
+	epoch uint64
+
+	/*
+		structures for threshold key sharing
+	*/
+
+	t                        int
+	xVerifiers               map[uint32]*sharing.FeldmanVerifier
+	gammaVerifiers           map[uint32]*sharing.FeldmanVerifier
+	gammaLinkProofs          map[uint32]*chaumpedersen.Proof
+	gammaLinkProofSessionIds map[uint32]chaumpedersen.SessionId
+	dVerifiers               map[uint32]*sharing.FeldmanVerifier
+	xShares                  map[uint32]curves.Scalar
+	gammaShares              map[uint32]curves.Scalar
+	dShares                  map[uint32]curves.Scalar
+	QShares                  map[uint32]curves.Point
+	TShares                  map[uint32]curves.Point
 
 	/*
 		structures for signing
 	*/
 
-	ks               [num]curves.Scalar
-	kProofs          [num]*schnorr.Proof
-	kCommitments     [num]schnorr.Commitment
-	kProofSessionIds [num]schnorr.Commitment
+	signers    []uint32
+	signXs     map[uint32]curves.Scalar
+	signGammas map[uint32]curves.Scalar
+	signDs     map[uint32]curves.Scalar
+	signQs     map[uint32]curves.Point
+	signTs     map[uint32]curves.Point
+
+	ks               map[uint32]curves.Scalar
+	kProofs          map[uint32]*schnorr.Proof
+	kCommitments     map[uint32]schnorr.Commitment
+	kProofSessionIds map[uint32]schnorr.Commitment
 
 	R  curves.Point
 	rx curves.Scalar
 
-	sRanCTGammaProofs          [num]*rspdl.Proof
-	sRanCTGammaProofSessionIds [num]rspdl.SessionId
+	sRanCTGammaProofs          map[uint32]*rspdl.Proof
+	sRanCTGammaProofSessionIds map[uint32]rspdl.SessionId
 	AKGamma                    curves.Point
 	BKGamma                    curves.Point
 
-	mus    [num][num]curves.Scalar
-	nus    [num][num]curves.Scalar
-	deltas [num]curves.Scalar
+	mus    map[uint32]map[uint32]curves.Scalar
+	nus    map[uint32]map[uint32]curves.Scalar
+	deltas map[uint32]curves.Scalar
 
-	deltaEGProofs          [num]*reg.Proof
-	deltaEGProofSessionIds [num]reg.SessionId
+	deltaEGProofs          map[uint32]*reg.Proof
+	deltaEGProofSessionIds map[uint32]reg.SessionId
 
 	ADelta curves.Point
 	BDelta curves.Point
@@ -120,57 +149,165 @@ type Scheme[A any, B any] struct {
 	A curves.Point
 	B curves.Point
 
-	abREProofs          [num]*rre.Proof
-	abRECommitments     [num]rre.Commitment
-	abREProofSessionIds [num]rre.SessionId
+	abREProofs          map[uint32]*rre.Proof
+	abRECommitments     map[uint32]rre.Commitment
+	abREProofSessionIds map[uint32]rre.SessionId
 
-	abDDHProofs          [num]*chaumpedersen.Proof
-	abDDHCommitments     [num]chaumpedersen.Commitment
-	abDDHProofSessionIds [num]chaumpedersen.SessionId
+	abDDHProofs          map[uint32]*chaumpedersen.Proof
+	abDDHCommitments     map[uint32]chaumpedersen.Commitment
+	abDDHProofSessionIds map[uint32]chaumpedersen.SessionId
 
 	APrime curves.Point
 	BPrime curves.Point
 
-	APrimes [num]curves.Point
+	APrimes map[uint32]curves.Point
 
 	r  curves.Scalar
-	ss [num]curves.Scalar
+	ss map[uint32]curves.Scalar
 	s  curves.Scalar
 }
 
-func NewScheme[A any, B any](curve *curves.Curve) *Scheme[A, B] {
+// NewScheme creates a scheme run by the parties with the given identifiers whose signatures are bound to signerId.
+// A nil signerId selects the default distinguishing identifier.
+func NewScheme[A any, B any](curve *curves.Curve, ids []uint32, signerId []byte) (*Scheme[A, B], error) {
+	if err := parties.Validate(ids, 2); err != nil {
+		return nil, err
+	}
+	if signerId == nil {
+		signerId = sm2.DefaultSignerId
+	}
 	return &Scheme[A, B]{
-		curve: curve,
-		n:     num,
-		P:     curve.NewGeneratorPoint(),
+		curve:    curve,
+		n:        len(ids),
+		ids:      ids,
+		P:        curve.NewGeneratorPoint(),
+		signerId: signerId,
+		signers:  ids,
+
+		xs:                         make(map[uint32]curves.Scalar, len(ids)),
+		QProofs:                    make(map[uint32]*schnorr.Proof, len(ids)),
+		QCommitments:               make(map[uint32]schnorr.Commitment, len(ids)),
+		QProofSessionIds:           make(map[uint32]schnorr.SessionId, len(ids)),
+		ds:                         make(map[uint32]curves.Scalar, len(ids)),
+		TProofs:                    make(map[uint32]*schnorr.Proof, len(ids)),
+		TCommitments:               make(map[uint32]schnorr.Commitment, len(ids)),
+		TProofSessionIds:           make(map[uint32]schnorr.Commitment, len(ids)),
+		gammas:                     make(map[uint32]curves.Scalar, len(ids)),
+		rGammas:                    make(map[uint32]curves.Scalar, len(ids)),
+		xGammas:                    make(map[uint32]curves.Scalar, len(ids)),
+		gammaRegProofs:             make(map[uint32]*reg.Proof, len(ids)),
+		gammaRegProofSessionIds:    make(map[uint32]reg.SessionId, len(ids)),
+		xGammaRspdlProofs:          make(map[uint32]*rspdl.Proof, len(ids)),
+		xGammaRspdlProofSessionIds: make(map[uint32]rspdl.SessionId, len(ids)),
+		alphas:                     pairwise[curves.Scalar](ids),
+		betas:                      pairwise[curves.Scalar](ids),
+		mtaSenders:                 pairwise[sign_offline.MTASender[A, B]](ids),
+		mtaReceivers:               pairwise[sign_offline.MTAReceiver[A, B]](ids),
+		sigmas:                     make(map[uint32]curves.Scalar, len(ids)),
+		rSigmas:                    make(map[uint32]curves.Scalar, len(ids)),
+		sigmaRegProofs:             make(map[uint32]*reg.Proof, len(ids)),
+		sigmaRegProofSessionIds:    make(map[uint32]reg.SessionId, len(ids)),
+		rreProofs:                  make(map[uint32]*rre.Proof, len(ids)),
+		rreCommitments:             make(map[uint32]rre.Commitment, len(ids)),
+		rreProofSessionIds:         make(map[uint32]rre.SessionId, len(ids)),
+		UPrimes:                    make(map[uint32]curves.Point, len(ids)),
+		ddhProofs:                  make(map[uint32]*chaumpedersen.Proof, len(ids)),
+		ddhCommitments:             make(map[uint32]chaumpedersen.Commitment, len(ids)),
+		ddhProofSessionIds:         make(map[uint32]chaumpedersen.SessionId, len(ids)),
+		VSigmaPrimes:               make(map[uint32]curves.Point, len(ids)),
+		sigmaDDHProofs:             make(map[uint32]*chaumpedersen.Proof, len(ids)),
+		sigmaDDHProofSessionIds:    make(map[uint32]chaumpedersen.SessionId, len(ids)),
+		ks:                         make(map[uint32]curves.Scalar, len(ids)),
+		kProofs:                    make(map[uint32]*schnorr.Proof, len(ids)),
+		kCommitments:               make(map[uint32]schnorr.Commitment, len(ids)),
+		kProofSessionIds:           make(map[uint32]schnorr.Commitment, len(ids)),
+		sRanCTGammaProofs:          make(map[uint32]*rspdl.Proof, len(ids)),
+		sRanCTGammaProofSessionIds: make(map[uint32]rspdl.SessionId, len(ids)),
+		mus:                        pairwise[curves.Scalar](ids),
+		nus:                        pairwise[curves.Scalar](ids),
+		deltas:                     make(map[uint32]curves.Scalar, len(ids)),
+		deltaEGProofs:              make(map[uint32]*reg.Proof, len(ids)),
+		deltaEGProofSessionIds:     make(map[uint32]reg.SessionId, len(ids)),
+		abREProofs:                 make(map[uint32]*rre.Proof, len(ids)),
+		abRECommitments:            make(map[uint32]rre.Commitment, len(ids)),
+		abREProofSessionIds:        make(map[uint32]rre.SessionId, len(ids)),
+		abDDHProofs:                make(map[uint32]*chaumpedersen.Proof, len(ids)),
+		abDDHCommitments:           make(map[uint32]chaumpedersen.Commitment, len(ids)),
+		abDDHProofSessionIds:       make(map[uint32]chaumpedersen.SessionId, len(ids)),
+		APrimes:                    make(map[uint32]curves.Point, len(ids)),
+		ss:                         make(map[uint32]curves.Scalar, len(ids)),
+		xVerifiers:                 make(map[uint32]*sharing.FeldmanVerifier, len(ids)),
+		gammaVerifiers:             make(map[uint32]*sharing.FeldmanVerifier, len(ids)),
+		gammaLinkProofs:            make(map[uint32]*chaumpedersen.Proof, len(ids)),
+		gammaLinkProofSessionIds:   make(map[uint32]chaumpedersen.SessionId, len(ids)),
+		dVerifiers:                 make(map[uint32]*sharing.FeldmanVerifier, len(ids)),
+		xShares:                    make(map[uint32]curves.Scalar, len(ids)),
+		gammaShares:                make(map[uint32]curves.Scalar, len(ids)),
+		dShares:                    make(map[uint32]curves.Scalar, len(ids)),
+		QShares:                    make(map[uint32]curves.Point, len(ids)),
+		TShares:                    make(map[uint32]curves.Point, len(ids)),
+		signXs:                     make(map[uint32]curves.Scalar, len(ids)),
+		signGammas:                 make(map[uint32]curves.Scalar, len(ids)),
+		signDs:                     make(map[uint32]curves.Scalar, len(ids)),
+		signQs:                     make(map[uint32]curves.Point, len(ids)),
+		signTs:                     make(map[uint32]curves.Point, len(ids)),
+	}, nil
+}
+
+// SetMtA installs the MtA instances used between the parties i and j in both directions
+func (scheme *Scheme[A, B]) SetMtA(i, j uint32, sender sign_offline.MTASender[A, B], receiver sign_offline.MTAReceiver[A, B]) error {
+	if i > j {
+		i, j = j, i
+	}
+	if _, ok := scheme.mtaSenders[i]; !ok {
+		return fmt.Errorf("unknown party %d", i)
 	}
+	if _, ok := scheme.mtaSenders[j]; !ok || i == j {
+		return fmt.Errorf("invalid peer %d", j)
+	}
+	scheme.mtaSenders[i][j] = sender
+	scheme.mtaReceivers[i][j] = receiver
+	return nil
+}
+
+func pairwise[T any](ids []uint32) map[uint32]map[uint32]T {
+	result := make(map[uint32]map[uint32]T, len(ids))
+	for _, id := range ids {
+		result[id] = make(map[uint32]T, len(ids))
+	}
+	return result
 }
 
 func (scheme *Scheme[A, B]) DKGPhase1() error {
 	// generate pks for SM2 and proofs
-	for id := 1; id <= scheme.n; id++ {
-		x, QProof, QCommitment, QProofSessionId, err := dkg.PkComProve(scheme.curve)
+	for _, id := range scheme.ids {
+		x, QProof, QCommitment, QProofSessionId, err := dkg.PkComProve(scheme.curve, nil)
 		if err != nil {
 			return err
 		}
-		scheme.xs[id-1] = x
-		scheme.QProofs[id-1] = QProof
-		scheme.QCommitments[id-1] = QCommitment
-		scheme.QProofSessionIds[id-1] = QProofSessionId
+		scheme.xs[id] = x
+		scheme.QProofs[id] = QProof
+		scheme.QCommitments[id] = QCommitment
+		scheme.QProofSessionIds[id] = QProofSessionId
 	}
 
 	// de-com and verify Q proofs
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for id := 1; id <= scheme.n; id++ {
+	for _, numParty := range scheme.ids {
+		for _, id := range scheme.ids {
 			if id == numParty {
 				continue
 			}
-			err := dkg.PkDeComVerify(scheme.curve, scheme.QProofs[id-1], scheme.QCommitments[id-1], scheme.QProofSessionIds[id-1])
+			err := dkg.PkDeComVerify(scheme.curve, scheme.QProofs[id], scheme.QCommitments[id], scheme.QProofSessionIds[id])
 			if err != nil {
-				return err
+				return abort.New(id, "DKGPhase1", &abort.Evidence{
+					Check:      abort.DkgPkDeCom,
+					Proof:      scheme.QProofs[id],
+					Commitment: scheme.QCommitments[id],
+					SessionId:  scheme.QProofSessionIds[id],
+				}, err)
 			}
 		}
 	}
@@ -179,37 +316,48 @@ func (scheme *Scheme[A, B]) DKGPhase1() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		scheme.Q = scheme.QProofs[0].Statement
-		for id := 2; id <= scheme.n; id++ {
-			scheme.Q = scheme.Q.Add(scheme.QProofs[id-1].Statement)
+	for range scheme.ids {
+		scheme.Q = scheme.QProofs[scheme.ids[0]].Statement
+		for _, id := range scheme.ids[1:] {
+			scheme.Q = scheme.Q.Add(scheme.QProofs[id].Statement)
 		}
 	}
 
+	return scheme.elGamalKeyGen("DKGPhase1")
+}
+
+// elGamalKeyGen draws the ElGamal key T=sum(T_i), of which party i holds d_i
+// phase names the calling phase in error reports
+func (scheme *Scheme[A, B]) elGamalKeyGen(phase string) error {
 	// generate pks for ElGamal and proofs
-	for id := 1; id <= scheme.n; id++ {
-		d, TProof, TCommitment, TProofSessionId, err := dkg.PkComProve(scheme.curve)
+	for _, id := range scheme.ids {
+		d, TProof, TCommitment, TProofSessionId, err := dkg.PkComProve(scheme.curve, nil)
 		if err != nil {
 			return err
 		}
-		scheme.ds[id-1] = d
-		scheme.TProofs[id-1] = TProof
-		scheme.TCommitments[id-1] = TCommitment
-		scheme.TProofSessionIds[id-1] = TProofSessionId
+		scheme.ds[id] = d
+		scheme.TProofs[id] = TProof
+		scheme.TCommitments[id] = TCommitment
+		scheme.TProofSessionIds[id] = TProofSessionId
 	}
 
 	// de-com and verify T proofs
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for id := 1; id <= scheme.n; id++ {
+	for _, numParty := range scheme.ids {
+		for _, id := range scheme.ids {
 			if id == numParty {
 				continue
 			}
-			err := dkg.PkDeComVerify(scheme.curve, scheme.TProofs[id-1], scheme.TCommitments[id-1], scheme.TProofSessionIds[id-1])
+			err := dkg.PkDeComVerify(scheme.curve, scheme.TProofs[id], scheme.TCommitments[id], scheme.TProofSessionIds[id])
 			if err != nil {
-				return err
+				return abort.New(id, phase, &abort.Evidence{
+					Check:      abort.DkgPkDeCom,
+					Proof:      scheme.TProofs[id],
+					Commitment: scheme.TCommitments[id],
+					SessionId:  scheme.TProofSessionIds[id],
+				}, err)
 			}
 		}
 	}
@@ -218,10 +366,10 @@ func (scheme *Scheme[A, B]) DKGPhase1() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		scheme.T = scheme.TProofs[0].Statement
-		for id := 2; id <= scheme.n; id++ {
-			scheme.T = scheme.T.Add(scheme.TProofs[id-1].Statement)
+	for range scheme.ids {
+		scheme.T = scheme.TProofs[scheme.ids[0]].Statement
+		for _, id := range scheme.ids[1:] {
+			scheme.T = scheme.T.Add(scheme.TProofs[id].Statement)
 		}
 	}
 
@@ -230,28 +378,34 @@ func (scheme *Scheme[A, B]) DKGPhase1() error {
 
 func (scheme *Scheme[A, B]) DKGPhase2() error {
 	// encrypt gamma
-	for id := 1; id <= scheme.n; id++ {
-		gamma, regProof, regProofSessionId, err := dkg.REGProve(scheme.curve, scheme.T)
+	for _, id := range scheme.ids {
+		gamma, rGamma, regProof, regProofSessionId, err := dkg.REGProve(scheme.curve, scheme.T, nil)
 		if err != nil {
 			return err
 		}
-		scheme.gammas[id-1] = gamma
-		scheme.gammaRegProofs[id-1] = regProof
-		scheme.gammaRegProofSessionIds[id-1] = regProofSessionId
+		scheme.gammas[id] = gamma
+		scheme.rGammas[id] = rGamma
+		scheme.gammaRegProofs[id] = regProof
+		scheme.gammaRegProofSessionIds[id] = regProofSessionId
 	}
 
 	// verify proofs of encryption of gammas
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for id := 1; id <= scheme.n; id++ {
+	for _, numParty := range scheme.ids {
+		for _, id := range scheme.ids {
 			if id == numParty {
 				continue
 			}
-			err := dkg.REGVerify(scheme.curve, scheme.T, scheme.gammaRegProofs[id-1], scheme.gammaRegProofSessionIds[id-1])
+			err := dkg.REGVerify(scheme.curve, scheme.T, scheme.gammaRegProofs[id], scheme.gammaRegProofSessionIds[id])
 			if err != nil {
-				return err
+				return abort.New(id, "DKGPhase2", &abort.Evidence{
+					Check:     abort.DkgREG,
+					Proof:     scheme.gammaRegProofs[id],
+					SessionId: scheme.gammaRegProofSessionIds[id],
+					Inputs:    []curves.Point{scheme.T},
+				}, err)
 			}
 		}
 	}
@@ -260,37 +414,42 @@ func (scheme *Scheme[A, B]) DKGPhase2() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		scheme.UGamma = scheme.gammaRegProofs[0].A
-		scheme.VGamma = scheme.gammaRegProofs[0].B
-		for id := 2; id <= scheme.n; id++ {
-			scheme.UGamma = scheme.UGamma.Add(scheme.gammaRegProofs[id-1].A)
-			scheme.VGamma = scheme.VGamma.Add(scheme.gammaRegProofs[id-1].B)
+	for range scheme.ids {
+		scheme.UGamma = scheme.gammaRegProofs[scheme.ids[0]].A
+		scheme.VGamma = scheme.gammaRegProofs[scheme.ids[0]].B
+		for _, id := range scheme.ids[1:] {
+			scheme.UGamma = scheme.UGamma.Add(scheme.gammaRegProofs[id].A)
+			scheme.VGamma = scheme.VGamma.Add(scheme.gammaRegProofs[id].B)
 		}
 	}
 
 	// compute sp-dl relations and proofs
-	for id := 1; id <= scheme.n; id++ {
-		rspdlProof, rspdlProofSessionId, err := dkg.RSPDLProve(scheme.curve, scheme.T, scheme.UGamma, scheme.VGamma, scheme.QProofs[id-1].Statement, scheme.xs[id-1])
+	for _, id := range scheme.ids {
+		rspdlProof, rspdlProofSessionId, err := dkg.RSPDLProve(scheme.curve, scheme.T, scheme.UGamma, scheme.VGamma, scheme.QProofs[id].Statement, scheme.xs[id], nil)
 		if err != nil {
 			return err
 		}
-		scheme.xGammaRspdlProofs[id-1] = rspdlProof
-		scheme.xGammaRspdlProofSessionIds[id-1] = rspdlProofSessionId
+		scheme.xGammaRspdlProofs[id] = rspdlProof
+		scheme.xGammaRspdlProofSessionIds[id] = rspdlProofSessionId
 	}
 
 	// verify proofs of sp-dl relations
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for id := 1; id <= scheme.n; id++ {
+	for _, numParty := range scheme.ids {
+		for _, id := range scheme.ids {
 			if id == numParty {
 				continue
 			}
-			err := dkg.RSPDLVerify(scheme.curve, scheme.T, scheme.xGammaRspdlProofs[id-1], scheme.UGamma, scheme.VGamma, scheme.QProofs[id-1].Statement, scheme.xGammaRspdlProofSessionIds[id-1])
+			err := dkg.RSPDLVerify(scheme.curve, scheme.T, scheme.xGammaRspdlProofs[id], scheme.UGamma, scheme.VGamma, scheme.QProofs[id].Statement, scheme.xGammaRspdlProofSessionIds[id])
 			if err != nil {
-				return err
+				return abort.New(id, "DKGPhase2", &abort.Evidence{
+					Check:     abort.DkgRSPDL,
+					Proof:     scheme.xGammaRspdlProofs[id],
+					SessionId: scheme.xGammaRspdlProofSessionIds[id],
+					Inputs:    []curves.Point{scheme.T, scheme.UGamma, scheme.VGamma, scheme.QProofs[id].Statement},
+				}, err)
 			}
 		}
 	}
@@ -299,12 +458,12 @@ func (scheme *Scheme[A, B]) DKGPhase2() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		scheme.UXGamma = scheme.xGammaRspdlProofs[0].APrime
-		scheme.VXGamma = scheme.xGammaRspdlProofs[0].BPrime
-		for id := 2; id <= scheme.n; id++ {
-			scheme.UXGamma = scheme.UXGamma.Add(scheme.xGammaRspdlProofs[id-1].APrime)
-			scheme.VXGamma = scheme.VXGamma.Add(scheme.xGammaRspdlProofs[id-1].BPrime)
+	for range scheme.ids {
+		scheme.UXGamma = scheme.xGammaRspdlProofs[scheme.ids[0]].APrime
+		scheme.VXGamma = scheme.xGammaRspdlProofs[scheme.ids[0]].BPrime
+		for _, id := range scheme.ids[1:] {
+			scheme.UXGamma = scheme.UXGamma.Add(scheme.xGammaRspdlProofs[id].APrime)
+			scheme.VXGamma = scheme.VXGamma.Add(scheme.xGammaRspdlProofs[id].BPrime)
 		}
 	}
 
@@ -313,60 +472,65 @@ func (scheme *Scheme[A, B]) DKGPhase2() error {
 
 func (scheme *Scheme[A, B]) DKGPhase3() error {
 	// invoke MtA
-	for i := 1; i <= scheme.n; i++ {
-		for j := 1; j <= scheme.n; j++ {
+	for _, i := range scheme.ids {
+		for _, j := range scheme.ids {
 			if i == j {
 				continue
 			}
 			var alpha, beta curves.Scalar
 			if i < j {
-				alpha, beta = dkg.MtASimu(scheme.curve, scheme.gammas[i-1], scheme.xs[j-1], scheme.mtaSenders[i-1][j-1], scheme.mtaReceivers[i-1][j-1])
+				alpha, beta = dkg.MtASimu(scheme.curve, scheme.gammas[i], scheme.xs[j], scheme.mtaSenders[i][j], scheme.mtaReceivers[i][j])
 			} else {
-				alpha, beta = dkg.MtASimu(scheme.curve, scheme.gammas[i-1], scheme.xs[j-1], scheme.mtaSenders[j-1][i-1], scheme.mtaReceivers[j-1][i-1])
+				alpha, beta = dkg.MtASimu(scheme.curve, scheme.gammas[i], scheme.xs[j], scheme.mtaSenders[j][i], scheme.mtaReceivers[j][i])
 			}
-			scheme.alphas[i-1][j-1] = alpha
-			scheme.betas[j-1][i-1] = beta
-			if alpha.Add(beta).Cmp(scheme.gammas[i-1].Mul(scheme.xs[j-1])) != 0 {
+			scheme.alphas[i][j] = alpha
+			scheme.betas[j][i] = beta
+			if alpha.Add(beta).Cmp(scheme.gammas[i].Mul(scheme.xs[j])) != 0 {
 				return fmt.Errorf("failed in MtA")
 			}
 		}
 	}
 
 	// compute sigma
-	for i := 1; i <= scheme.n; i++ {
-		sigma := scheme.gammas[i-1].Mul(scheme.xs[i-1])
-		for j := 1; j <= scheme.n; j++ {
+	for _, i := range scheme.ids {
+		sigma := scheme.gammas[i].Mul(scheme.xs[i])
+		for _, j := range scheme.ids {
 			if i == j {
 				continue
 			}
-			sigma = sigma.Add(scheme.alphas[i-1][j-1]).Add(scheme.betas[i-1][j-1])
+			sigma = sigma.Add(scheme.alphas[i][j]).Add(scheme.betas[i][j])
 		}
-		scheme.sigmas[i-1] = sigma
+		scheme.sigmas[i] = sigma
 	}
 
 	// encrypt sigma and generate proof
-	for i := 1; i <= scheme.n; i++ {
-		rSigma, sigmaRegProof, sigmaRegProofSessionId, err := dkg.SigmaREGProve(scheme.curve, scheme.T, scheme.sigmas[i-1])
+	for _, i := range scheme.ids {
+		rSigma, sigmaRegProof, sigmaRegProofSessionId, err := dkg.SigmaREGProve(scheme.curve, scheme.T, scheme.sigmas[i], nil)
 		if err != nil {
 			return err
 		}
-		scheme.rSigmas[i-1] = rSigma
-		scheme.sigmaRegProofs[i-1] = sigmaRegProof
-		scheme.sigmaRegProofSessionIds[i-1] = sigmaRegProofSessionId
+		scheme.rSigmas[i] = rSigma
+		scheme.sigmaRegProofs[i] = sigmaRegProof
+		scheme.sigmaRegProofSessionIds[i] = sigmaRegProofSessionId
 	}
 
 	// verify proof of sigma's encryption
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for i := 1; i <= scheme.n; i++ {
+	for _, numParty := range scheme.ids {
+		for _, i := range scheme.ids {
 			if i == numParty {
 				continue
 			}
-			err := dkg.SigmaREGVerify(scheme.curve, scheme.T, scheme.sigmaRegProofs[i-1], scheme.sigmaRegProofSessionIds[i-1])
+			err := dkg.SigmaREGVerify(scheme.curve, scheme.T, scheme.sigmaRegProofs[i], scheme.sigmaRegProofSessionIds[i])
 			if err != nil {
-				return err
+				return abort.New(i, "DKGPhase3", &abort.Evidence{
+					Check:     abort.DkgSigmaREG,
+					Proof:     scheme.sigmaRegProofs[i],
+					SessionId: scheme.sigmaRegProofSessionIds[i],
+					Inputs:    []curves.Point{scheme.T},
+				}, err)
 			}
 		}
 	}
@@ -375,12 +539,12 @@ func (scheme *Scheme[A, B]) DKGPhase3() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		scheme.USigma = scheme.sigmaRegProofs[0].A
-		scheme.VSigma = scheme.sigmaRegProofs[0].B
-		for i := 2; i <= scheme.n; i++ {
-			scheme.USigma = scheme.USigma.Add(scheme.sigmaRegProofs[i-1].A)
-			scheme.VSigma = scheme.VSigma.Add(scheme.sigmaRegProofs[i-1].B)
+	for range scheme.ids {
+		scheme.USigma = scheme.sigmaRegProofs[scheme.ids[0]].A
+		scheme.VSigma = scheme.sigmaRegProofs[scheme.ids[0]].B
+		for _, i := range scheme.ids[1:] {
+			scheme.USigma = scheme.USigma.Add(scheme.sigmaRegProofs[i].A)
+			scheme.VSigma = scheme.VSigma.Add(scheme.sigmaRegProofs[i].B)
 		}
 	}
 
@@ -392,7 +556,7 @@ func (scheme *Scheme[A, B]) DKGPhase4() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
+	for range scheme.ids {
 		scheme.U = scheme.UXGamma.Sub(scheme.USigma)
 		scheme.V = scheme.VXGamma.Sub(scheme.VSigma)
 	}
@@ -402,28 +566,34 @@ func (scheme *Scheme[A, B]) DKGPhase4() error {
 	*/
 
 	// re-randomize, generate proof and commitment
-	for id := 1; id <= scheme.n; id++ {
-		rreProof, rreCommitment, rreProofSessionId, err := dkg.RREComProve(scheme.curve, scheme.T, scheme.U, scheme.V)
+	for _, id := range scheme.ids {
+		rreProof, rreCommitment, rreProofSessionId, err := dkg.RREComProve(scheme.curve, scheme.T, scheme.U, scheme.V, nil)
 		if err != nil {
 			return err
 		}
-		scheme.rreProofs[id-1] = rreProof
-		scheme.rreCommitments[id-1] = rreCommitment
-		scheme.rreProofSessionIds[id-1] = rreProofSessionId
+		scheme.rreProofs[id] = rreProof
+		scheme.rreCommitments[id] = rreCommitment
+		scheme.rreProofSessionIds[id] = rreProofSessionId
 	}
 
 	// de-com, verify proof of re-randomization
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for id := 1; id <= scheme.n; id++ {
+	for _, numParty := range scheme.ids {
+		for _, id := range scheme.ids {
 			if id == numParty {
 				continue
 			}
-			err := dkg.RREDeComVerify(scheme.curve, scheme.rreProofs[id-1], scheme.rreCommitments[id-1], scheme.rreProofSessionIds[id-1], scheme.T, scheme.U, scheme.V)
+			err := dkg.RREDeComVerify(scheme.curve, scheme.rreProofs[id], scheme.rreCommitments[id], scheme.rreProofSessionIds[id], scheme.T, scheme.U, scheme.V)
 			if err != nil {
-				return err
+				return abort.New(id, "DKGPhase4", &abort.Evidence{
+					Check:      abort.DkgRREDeCom,
+					Proof:      scheme.rreProofs[id],
+					Commitment: scheme.rreCommitments[id],
+					SessionId:  scheme.rreProofSessionIds[id],
+					Inputs:     []curves.Point{scheme.T, scheme.U, scheme.V},
+				}, err)
 			}
 		}
 	}
@@ -432,43 +602,49 @@ func (scheme *Scheme[A, B]) DKGPhase4() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		scheme.UPrime = scheme.rreProofs[0].APrime
-		scheme.VPrime = scheme.rreProofs[0].BPrime
-		for id := 2; id <= scheme.n; id++ {
-			scheme.UPrime = scheme.UPrime.Add(scheme.rreProofs[id-1].APrime)
-			scheme.VPrime = scheme.VPrime.Add(scheme.rreProofs[id-1].BPrime)
+	for range scheme.ids {
+		scheme.UPrime = scheme.rreProofs[scheme.ids[0]].APrime
+		scheme.VPrime = scheme.rreProofs[scheme.ids[0]].BPrime
+		for _, id := range scheme.ids[1:] {
+			scheme.UPrime = scheme.UPrime.Add(scheme.rreProofs[id].APrime)
+			scheme.VPrime = scheme.VPrime.Add(scheme.rreProofs[id].BPrime)
 		}
 	}
 
 	// compute U'_i
-	for id := 1; id <= scheme.n; id++ {
-		scheme.UPrimes[id-1] = scheme.UPrime.Mul(scheme.ds[id-1])
+	for _, id := range scheme.ids {
+		scheme.UPrimes[id] = scheme.UPrime.Mul(scheme.ds[id])
 	}
 
 	// generate DDH proof and commitment
-	for id := 1; id <= scheme.n; id++ {
-		ddhProof, ddhCommitment, ddhProofSessionId, err := dkg.DDHComProve(scheme.curve, nil, scheme.UPrime, scheme.TProofs[id-1].Statement, scheme.UPrimes[id-1], scheme.ds[id-1])
+	for _, id := range scheme.ids {
+		ddhProof, ddhCommitment, ddhProofSessionId, err := dkg.DDHComProve(scheme.curve, nil, scheme.UPrime, scheme.TProofs[id].Statement, scheme.UPrimes[id], scheme.ds[id], nil)
 		if err != nil {
 			return err
 		}
-		scheme.ddhProofs[id-1] = ddhProof
-		scheme.ddhCommitments[id-1] = ddhCommitment
-		scheme.ddhProofSessionIds[id-1] = ddhProofSessionId
+		scheme.ddhProofs[id] = ddhProof
+		scheme.ddhCommitments[id] = ddhCommitment
+		scheme.ddhProofSessionIds[id] = ddhProofSessionId
 	}
 
 	// de-com, verify proof of ddh
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for id := 1; id <= scheme.n; id++ {
+	for _, numParty := range scheme.ids {
+		for _, id := range scheme.ids {
 			if id == numParty {
 				continue
 			}
-			err := dkg.DDHDeComVerify(scheme.curve, scheme.ddhProofs[id-1], scheme.ddhCommitments[id-1], scheme.ddhProofSessionIds[id-1], nil, scheme.UPrime)
+			err := dkg.DDHDeComVerify(scheme.curve, scheme.ddhProofs[id], scheme.ddhCommitments[id], scheme.ddhProofSessionIds[id], nil, scheme.UPrime)
 			if err != nil {
-				return err
+				return abort.New(id, "DKGPhase4", &abort.Evidence{
+					Check:      abort.DkgDDHDeCom,
+					Proof:      scheme.ddhProofs[id],
+					Commitment: scheme.ddhCommitments[id],
+					SessionId:  scheme.ddhProofSessionIds[id],
+					Inputs:     []curves.Point{scheme.P, scheme.UPrime},
+				}, err)
 			}
 		}
 	}
@@ -477,10 +653,10 @@ func (scheme *Scheme[A, B]) DKGPhase4() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		sumUiPrime := scheme.UPrimes[0]
-		for id := 2; id <= scheme.n; id++ {
-			sumUiPrime = sumUiPrime.Add(scheme.UPrimes[id-1])
+	for range scheme.ids {
+		sumUiPrime := scheme.UPrimes[scheme.ids[0]]
+		for _, id := range scheme.ids[1:] {
+			sumUiPrime = sumUiPrime.Add(scheme.UPrimes[id])
 		}
 		if !sumUiPrime.Equal(scheme.VPrime) {
 			return fmt.Errorf("failed when verifying sum of U'_i")
@@ -492,28 +668,33 @@ func (scheme *Scheme[A, B]) DKGPhase4() error {
 	*/
 
 	// compute VSigmaPrimes and DDH proofs
-	for id := 1; id <= scheme.n; id++ {
-		scheme.VSigmaPrimes[id-1] = scheme.sigmaRegProofs[id-1].B.Sub(scheme.P.Mul(scheme.sigmas[id-1]))
-		sigmaDDHProof, sigmaDDHProofSessionId, err := dkg.SigmaDDHProve(scheme.curve, scheme.P, scheme.T, scheme.sigmaRegProofs[id-1].A, scheme.VSigmaPrimes[id-1], scheme.rSigmas[id-1])
+	for _, id := range scheme.ids {
+		scheme.VSigmaPrimes[id] = scheme.sigmaRegProofs[id].B.Sub(scheme.P.Mul(scheme.sigmas[id]))
+		sigmaDDHProof, sigmaDDHProofSessionId, err := dkg.SigmaDDHProve(scheme.curve, scheme.P, scheme.T, scheme.sigmaRegProofs[id].A, scheme.VSigmaPrimes[id], scheme.rSigmas[id], nil)
 		if err != nil {
 			return err
 		}
-		scheme.sigmaDDHProofs[id-1] = sigmaDDHProof
-		scheme.sigmaDDHProofSessionIds[id-1] = sigmaDDHProofSessionId
+		scheme.sigmaDDHProofs[id] = sigmaDDHProof
+		scheme.sigmaDDHProofSessionIds[id] = sigmaDDHProofSessionId
 	}
 
 	// verify DDH proofs
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for id := 1; id <= scheme.n; id++ {
+	for _, numParty := range scheme.ids {
+		for _, id := range scheme.ids {
 			if id == numParty {
 				continue
 			}
-			err := dkg.SigmaDDHVerify(scheme.curve, scheme.sigmaDDHProofs[id-1], scheme.sigmaDDHProofSessionIds[id-1], scheme.P, scheme.T)
+			err := dkg.SigmaDDHVerify(scheme.curve, scheme.sigmaDDHProofs[id], scheme.sigmaDDHProofSessionIds[id], scheme.P, scheme.T)
 			if err != nil {
-				return err
+				return abort.New(id, "DKGPhase4", &abort.Evidence{
+					Check:     abort.DkgSigmaDDH,
+					Proof:     scheme.sigmaDDHProofs[id],
+					SessionId: scheme.sigmaDDHProofSessionIds[id],
+					Inputs:    []curves.Point{scheme.P, scheme.T},
+				}, err)
 			}
 		}
 	}
@@ -522,13 +703,20 @@ func (scheme *Scheme[A, B]) DKGPhase4() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for id := 1; id <= scheme.n; id++ {
+	for _, numParty := range scheme.ids {
+		for _, id := range scheme.ids {
 			if id == numParty {
 				continue
 			}
-			if !scheme.P.Mul(scheme.sigmas[id-1]).Equal(scheme.sigmaRegProofs[id-1].B.Sub(scheme.sigmaDDHProofs[id-1].Statement2)) {
-				return fmt.Errorf("failed when verifying the validation of sigma")
+			SigmaI := scheme.P.Mul(scheme.sigmas[id])
+			err := dkg.SigmaRevealVerify(scheme.sigmaDDHProofs[id], scheme.sigmaRegProofs[id].A, scheme.sigmaRegProofs[id].B, SigmaI)
+			if err != nil {
+				return abort.New(id, "DKGPhase4", &abort.Evidence{
+					Check:     abort.DkgSigmaRev,
+					Proof:     scheme.sigmaDDHProofs[id],
+					SessionId: scheme.sigmaDDHProofSessionIds[id],
+					Inputs:    []curves.Point{scheme.sigmaRegProofs[id].A, scheme.sigmaRegProofs[id].B, SigmaI},
+				}, err)
 			}
 		}
 	}
@@ -537,41 +725,51 @@ func (scheme *Scheme[A, B]) DKGPhase4() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		scheme.sigma = scheme.sigmas[0]
-		for id := 2; id <= scheme.n; id++ {
-			scheme.sigma = scheme.sigma.Add(scheme.sigmas[id-1])
+	for range scheme.ids {
+		scheme.sigma = scheme.sigmas[scheme.ids[0]]
+		for _, id := range scheme.ids[1:] {
+			scheme.sigma = scheme.sigma.Add(scheme.sigmas[id])
 		}
 	}
+	scheme.epoch++
 
 	return nil
 }
 
 func (scheme *Scheme[A, B]) DSPhase1() error {
+	if scheme.signers == nil {
+		return fmt.Errorf("no signers selected")
+	}
+
 	// generate k_i, compute R_i and proof
-	for i := 1; i <= scheme.n; i++ {
-		ki, kiProof, kiCommitment, kiProofSessionId, err := ds.NonceComProve(scheme.curve)
+	for _, i := range scheme.signers {
+		ki, kiProof, kiCommitment, kiProofSessionId, err := ds.NonceComProve(scheme.curve, nil)
 		if err != nil {
 			return err
 		}
-		scheme.ks[i-1] = ki
-		scheme.kProofs[i-1] = kiProof
-		scheme.kCommitments[i-1] = kiCommitment
-		scheme.kProofSessionIds[i-1] = kiProofSessionId
+		scheme.ks[i] = ki
+		scheme.kProofs[i] = kiProof
+		scheme.kCommitments[i] = kiCommitment
+		scheme.kProofSessionIds[i] = kiProofSessionId
 	}
 
 	// verify proof of k_i
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
-		for i := 1; i <= scheme.n; i++ {
+	for _, party := range scheme.signers {
+		for _, i := range scheme.signers {
 			if i == party {
 				continue
 			}
-			err := ds.NonceDeComVerify(scheme.curve, scheme.kProofs[i-1], scheme.kCommitments[i-1], scheme.kProofSessionIds[i-1])
+			err := ds.NonceDeComVerify(scheme.curve, scheme.kProofs[i], scheme.kCommitments[i], scheme.kProofSessionIds[i])
 			if err != nil {
-				return err
+				return abort.New(i, "DSPhase1", &abort.Evidence{
+					Check:      abort.DsNonceDeCom,
+					Proof:      scheme.kProofs[i],
+					Commitment: scheme.kCommitments[i],
+					SessionId:  scheme.kProofSessionIds[i],
+				}, err)
 			}
 		}
 	}
@@ -580,14 +778,13 @@ func (scheme *Scheme[A, B]) DSPhase1() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
+	for range scheme.signers {
 		var err error
-		scheme.R = scheme.kProofs[0].Statement
-		for i := 2; i <= scheme.n; i++ {
-			scheme.R = scheme.R.Add(scheme.kProofs[i-1].Statement)
+		scheme.R = scheme.kProofs[scheme.signers[0]].Statement
+		for _, i := range scheme.signers[1:] {
+			scheme.R = scheme.R.Add(scheme.kProofs[i].Statement)
 		}
-		RAffine := scheme.R.ToAffineCompressed()
-		scheme.rx, err = scheme.curve.Scalar.SetBigInt(new(big.Int).SetBytes(RAffine[1 : 1+(len(RAffine)>>1)]))
+		scheme.rx, err = sm2.XCoordinate(scheme.curve, scheme.R)
 		if err != nil {
 			return fmt.Errorf("failed when computing x-coordinate of R")
 		}
@@ -598,27 +795,32 @@ func (scheme *Scheme[A, B]) DSPhase1() error {
 
 func (scheme *Scheme[A, B]) DSPhase2() error {
 	// re-randomize the ciphertext of gamma
-	for i := 1; i <= scheme.n; i++ {
-		sRanCTGammaProof, sRanCTGammaSessionId, err := ds.SRanCTGammaProve(scheme.curve, scheme.T, scheme.UGamma, scheme.VGamma, scheme.kProofs[i-1].Statement, scheme.ks[i-1])
+	for _, i := range scheme.signers {
+		sRanCTGammaProof, sRanCTGammaSessionId, err := ds.SRanCTGammaProve(scheme.curve, scheme.T, scheme.UGamma, scheme.VGamma, scheme.kProofs[i].Statement, scheme.ks[i], nil)
 		if err != nil {
 			return err
 		}
-		scheme.sRanCTGammaProofs[i-1] = sRanCTGammaProof
-		scheme.sRanCTGammaProofSessionIds[i-1] = sRanCTGammaSessionId
+		scheme.sRanCTGammaProofs[i] = sRanCTGammaProof
+		scheme.sRanCTGammaProofSessionIds[i] = sRanCTGammaSessionId
 	}
 
 	// verify the re-randomization to the ciphertext of gamma
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
-		for i := 1; i <= scheme.n; i++ {
+	for _, party := range scheme.signers {
+		for _, i := range scheme.signers {
 			if i == party {
 				continue
 			}
-			err := ds.SRanCTGammaVerify(scheme.curve, scheme.T, scheme.sRanCTGammaProofs[i-1], scheme.UGamma, scheme.VGamma, scheme.kProofs[i-1].Statement, scheme.sRanCTGammaProofSessionIds[i-1])
+			err := ds.SRanCTGammaVerify(scheme.curve, scheme.T, scheme.sRanCTGammaProofs[i], scheme.UGamma, scheme.VGamma, scheme.kProofs[i].Statement, scheme.sRanCTGammaProofSessionIds[i])
 			if err != nil {
-				return err
+				return abort.New(i, "DSPhase2", &abort.Evidence{
+					Check:     abort.DsSRanCTGamma,
+					Proof:     scheme.sRanCTGammaProofs[i],
+					SessionId: scheme.sRanCTGammaProofSessionIds[i],
+					Inputs:    []curves.Point{scheme.T, scheme.UGamma, scheme.VGamma, scheme.kProofs[i].Statement},
+				}, err)
 			}
 		}
 	}
@@ -627,12 +829,12 @@ func (scheme *Scheme[A, B]) DSPhase2() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
-		scheme.AKGamma = scheme.sRanCTGammaProofs[0].APrime
-		scheme.BKGamma = scheme.sRanCTGammaProofs[0].BPrime
-		for i := 2; i <= scheme.n; i++ {
-			scheme.AKGamma = scheme.AKGamma.Add(scheme.sRanCTGammaProofs[i-1].APrime)
-			scheme.BKGamma = scheme.BKGamma.Add(scheme.sRanCTGammaProofs[i-1].BPrime)
+	for range scheme.signers {
+		scheme.AKGamma = scheme.sRanCTGammaProofs[scheme.signers[0]].APrime
+		scheme.BKGamma = scheme.sRanCTGammaProofs[scheme.signers[0]].BPrime
+		for _, i := range scheme.signers[1:] {
+			scheme.AKGamma = scheme.AKGamma.Add(scheme.sRanCTGammaProofs[i].APrime)
+			scheme.BKGamma = scheme.BKGamma.Add(scheme.sRanCTGammaProofs[i].BPrime)
 		}
 	}
 
@@ -641,56 +843,61 @@ func (scheme *Scheme[A, B]) DSPhase2() error {
 
 func (scheme *Scheme[A, B]) DSPhase3() error {
 	// invoke MtA
-	for i := 1; i <= scheme.n; i++ {
-		for j := 1; j <= scheme.n; j++ {
+	for _, i := range scheme.signers {
+		for _, j := range scheme.signers {
 			if i == j {
 				continue
 			}
 			var mu, nu curves.Scalar
 			if i < j {
-				mu, nu = ds.MtASimu(scheme.curve, scheme.gammas[i-1], scheme.ks[j-1], scheme.mtaSenders[i-1][j-1], scheme.mtaReceivers[i-1][j-1])
+				mu, nu = ds.MtASimu(scheme.curve, scheme.signGamma(i), scheme.ks[j], scheme.mtaSenders[i][j], scheme.mtaReceivers[i][j])
 			} else {
-				mu, nu = ds.MtASimu(scheme.curve, scheme.gammas[i-1], scheme.ks[j-1], scheme.mtaSenders[j-1][i-1], scheme.mtaReceivers[j-1][i-1])
+				mu, nu = ds.MtASimu(scheme.curve, scheme.signGamma(i), scheme.ks[j], scheme.mtaSenders[j][i], scheme.mtaReceivers[j][i])
 			}
-			scheme.mus[i-1][j-1] = mu
-			scheme.nus[j-1][i-1] = nu
+			scheme.mus[i][j] = mu
+			scheme.nus[j][i] = nu
 		}
 	}
 
 	// compute delta_i
-	for i := 1; i <= scheme.n; i++ {
-		delta_i := scheme.gammas[i-1].Mul(scheme.ks[i-1])
-		for j := 1; j <= scheme.n; j++ {
+	for _, i := range scheme.signers {
+		delta_i := scheme.signGamma(i).Mul(scheme.ks[i])
+		for _, j := range scheme.signers {
 			if i == j {
 				continue
 			}
-			delta_i = delta_i.Add(scheme.mus[i-1][j-1].Add(scheme.nus[i-1][j-1]))
+			delta_i = delta_i.Add(scheme.mus[i][j].Add(scheme.nus[i][j]))
 		}
-		scheme.deltas[i-1] = delta_i
+		scheme.deltas[i] = delta_i
 	}
 
 	// encrypt delta_i and generate proof
-	for i := 1; i <= scheme.n; i++ {
-		deltaEGProof, deltaEGProofSessionId, err := ds.DeltaEGProve(scheme.curve, scheme.T, scheme.deltas[i-1])
+	for _, i := range scheme.signers {
+		deltaEGProof, deltaEGProofSessionId, err := ds.DeltaEGProve(scheme.curve, scheme.T, scheme.deltas[i], nil)
 		if err != nil {
 			return err
 		}
-		scheme.deltaEGProofs[i-1] = deltaEGProof
-		scheme.deltaEGProofSessionIds[i-1] = deltaEGProofSessionId
+		scheme.deltaEGProofs[i] = deltaEGProof
+		scheme.deltaEGProofSessionIds[i] = deltaEGProofSessionId
 	}
 
 	// verify proof of delta_i's encryption
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
-		for i := 1; i <= scheme.n; i++ {
+	for _, party := range scheme.signers {
+		for _, i := range scheme.signers {
 			if i == party {
 				continue
 			}
-			err := ds.DeltaEGVerify(scheme.curve, scheme.T, scheme.deltaEGProofs[i-1], scheme.deltaEGProofSessionIds[i-1])
+			err := ds.DeltaEGVerify(scheme.curve, scheme.T, scheme.deltaEGProofs[i], scheme.deltaEGProofSessionIds[i])
 			if err != nil {
-				return err
+				return abort.New(i, "DSPhase3", &abort.Evidence{
+					Check:     abort.DsDeltaEG,
+					Proof:     scheme.deltaEGProofs[i],
+					SessionId: scheme.deltaEGProofSessionIds[i],
+					Inputs:    []curves.Point{scheme.T},
+				}, err)
 			}
 		}
 	}
@@ -699,12 +906,12 @@ func (scheme *Scheme[A, B]) DSPhase3() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
-		scheme.ADelta = scheme.deltaEGProofs[0].A
-		scheme.BDelta = scheme.deltaEGProofs[0].B
-		for i := 2; i <= scheme.n; i++ {
-			scheme.ADelta = scheme.ADelta.Add(scheme.deltaEGProofs[i-1].A)
-			scheme.BDelta = scheme.BDelta.Add(scheme.deltaEGProofs[i-1].B)
+	for range scheme.signers {
+		scheme.ADelta = scheme.deltaEGProofs[scheme.signers[0]].A
+		scheme.BDelta = scheme.deltaEGProofs[scheme.signers[0]].B
+		for _, i := range scheme.signers[1:] {
+			scheme.ADelta = scheme.ADelta.Add(scheme.deltaEGProofs[i].A)
+			scheme.BDelta = scheme.BDelta.Add(scheme.deltaEGProofs[i].B)
 		}
 	}
 
@@ -716,7 +923,7 @@ func (scheme *Scheme[A, B]) DSPhase4() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
+	for range scheme.signers {
 		scheme.A = scheme.AKGamma.Sub(scheme.ADelta)
 		scheme.B = scheme.BKGamma.Sub(scheme.BDelta)
 	}
@@ -726,28 +933,34 @@ func (scheme *Scheme[A, B]) DSPhase4() error {
 	*/
 
 	// re-randomize, generate proof and commitment
-	for i := 1; i <= scheme.n; i++ {
-		reProof, reCommitment, reProofSessionId, err := ds.REComProve(scheme.curve, scheme.T, scheme.A, scheme.B)
+	for _, i := range scheme.signers {
+		reProof, reCommitment, reProofSessionId, err := ds.REComProve(scheme.curve, scheme.T, scheme.A, scheme.B, nil)
 		if err != nil {
 			return err
 		}
-		scheme.abREProofs[i-1] = reProof
-		scheme.abRECommitments[i-1] = reCommitment
-		scheme.abREProofSessionIds[i-1] = reProofSessionId
+		scheme.abREProofs[i] = reProof
+		scheme.abRECommitments[i] = reCommitment
+		scheme.abREProofSessionIds[i] = reProofSessionId
 	}
 
 	// de-com, verify proof of re-randomization
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
-		for i := 1; i <= scheme.n; i++ {
+	for _, party := range scheme.signers {
+		for _, i := range scheme.signers {
 			if i == party {
 				continue
 			}
-			err := ds.REDeComVerify(scheme.curve, scheme.abREProofs[i-1], scheme.abRECommitments[i-1], scheme.abREProofSessionIds[i-1], scheme.T, scheme.A, scheme.B)
+			err := ds.REDeComVerify(scheme.curve, scheme.abREProofs[i], scheme.abRECommitments[i], scheme.abREProofSessionIds[i], scheme.T, scheme.A, scheme.B)
 			if err != nil {
-				return err
+				return abort.New(i, "DSPhase4", &abort.Evidence{
+					Check:      abort.DsREDeCom,
+					Proof:      scheme.abREProofs[i],
+					Commitment: scheme.abRECommitments[i],
+					SessionId:  scheme.abREProofSessionIds[i],
+					Inputs:     []curves.Point{scheme.T, scheme.A, scheme.B},
+				}, err)
 			}
 		}
 	}
@@ -756,43 +969,49 @@ func (scheme *Scheme[A, B]) DSPhase4() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
-		scheme.APrime = scheme.abREProofs[0].APrime
-		scheme.BPrime = scheme.abREProofs[0].BPrime
-		for i := 2; i <= scheme.n; i++ {
-			scheme.APrime = scheme.APrime.Add(scheme.abREProofs[i-1].APrime)
-			scheme.BPrime = scheme.BPrime.Add(scheme.abREProofs[i-1].BPrime)
+	for range scheme.signers {
+		scheme.APrime = scheme.abREProofs[scheme.signers[0]].APrime
+		scheme.BPrime = scheme.abREProofs[scheme.signers[0]].BPrime
+		for _, i := range scheme.signers[1:] {
+			scheme.APrime = scheme.APrime.Add(scheme.abREProofs[i].APrime)
+			scheme.BPrime = scheme.BPrime.Add(scheme.abREProofs[i].BPrime)
 		}
 	}
 
 	// compute A'_i
-	for i := 1; i <= scheme.n; i++ {
-		scheme.APrimes[i-1] = scheme.APrime.Mul(scheme.ds[i-1])
+	for _, i := range scheme.signers {
+		scheme.APrimes[i] = scheme.APrime.Mul(scheme.signD(i))
 	}
 
 	// generate DDH proof and commitment
-	for i := 1; i <= scheme.n; i++ {
-		ddhProof, ddhCommitment, ddhProofSessionId, err := ds.DDHComProve(scheme.curve, nil, scheme.APrime, scheme.TProofs[i-1].Statement, scheme.APrimes[i-1], scheme.ds[i-1])
+	for _, i := range scheme.signers {
+		ddhProof, ddhCommitment, ddhProofSessionId, err := ds.DDHComProve(scheme.curve, nil, scheme.APrime, scheme.signT(i), scheme.APrimes[i], scheme.signD(i), nil)
 		if err != nil {
 			return err
 		}
-		scheme.abDDHProofs[i-1] = ddhProof
-		scheme.abDDHCommitments[i-1] = ddhCommitment
-		scheme.abDDHProofSessionIds[i-1] = ddhProofSessionId
+		scheme.abDDHProofs[i] = ddhProof
+		scheme.abDDHCommitments[i] = ddhCommitment
+		scheme.abDDHProofSessionIds[i] = ddhProofSessionId
 	}
 
 	// de-com, verify proof of DDH
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
-		for i := 1; i <= scheme.n; i++ {
+	for _, party := range scheme.signers {
+		for _, i := range scheme.signers {
 			if i == party {
 				continue
 			}
-			err := ds.DDHDeComVerify(scheme.curve, scheme.abDDHProofs[i-1], scheme.abDDHCommitments[i-1], scheme.abDDHProofSessionIds[i-1], nil, scheme.APrime)
+			err := ds.DDHDeComVerify(scheme.curve, scheme.abDDHProofs[i], scheme.abDDHCommitments[i], scheme.abDDHProofSessionIds[i], nil, scheme.APrime)
 			if err != nil {
-				return err
+				return abort.New(i, "DSPhase4", &abort.Evidence{
+					Check:      abort.DsDDHDeCom,
+					Proof:      scheme.abDDHProofs[i],
+					Commitment: scheme.abDDHCommitments[i],
+					SessionId:  scheme.abDDHProofSessionIds[i],
+					Inputs:     []curves.Point{scheme.P, scheme.APrime},
+				}, err)
 			}
 		}
 	}
@@ -801,10 +1020,10 @@ func (scheme *Scheme[A, B]) DSPhase4() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
-		sumAPrimes := scheme.APrimes[0]
-		for i := 2; i <= scheme.n; i++ {
-			sumAPrimes = sumAPrimes.Add(scheme.APrimes[i-1])
+	for range scheme.signers {
+		sumAPrimes := scheme.APrimes[scheme.signers[0]]
+		for _, i := range scheme.signers[1:] {
+			sumAPrimes = sumAPrimes.Add(scheme.APrimes[i])
 		}
 		if !sumAPrimes.Equal(scheme.BPrime) {
 			return fmt.Errorf("failed when verifying DDH relation")
@@ -819,32 +1038,44 @@ func (scheme *Scheme[A, B]) DSPhase4() error {
 }
 
 func (scheme *Scheme[A, B]) DSPhase5() error {
+	pre, err := scheme.presignature()
+	if err != nil {
+		return err
+	}
+	return scheme.finalize(pre)
+}
+
+// finalize runs the online phase on pre, i.e. it computes r and the partial signatures s_i and adds them up
+func (scheme *Scheme[A, B]) finalize(pre *Presignature) error {
 	// compute r
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
-		h := scheme.curve.Scalar.Hash(scheme.message)
-		scheme.r = scheme.rx.Add(h)
+	for range pre.signers {
+		h, err := sm2.Digest(scheme.curve, scheme.signerId, scheme.Q.Sub(scheme.P), scheme.message)
+		if err != nil {
+			return err
+		}
+		scheme.r = pre.rx.Add(h)
 	}
 
 	// compute s_i
-	for i := 1; i <= scheme.n; i++ {
+	for _, i := range pre.signers {
 		sigmaInvert, err := scheme.sigma.Invert()
 		if err != nil {
 			return fmt.Errorf("failed in computing the inverse of sigma")
 		}
-		scheme.ss[i-1] = sigmaInvert.Mul(scheme.gammas[i-1].Mul(scheme.r).Add(scheme.deltas[i-1]))
+		scheme.ss[i] = sigmaInvert.Mul(pre.gammas[i].Mul(scheme.r).Add(pre.deltas[i]))
 	}
 
 	// compute s
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
-		scheme.s = scheme.ss[0]
-		for i := 2; i <= scheme.n; i++ {
-			scheme.s = scheme.s.Add(scheme.ss[i-1])
+	for range pre.signers {
+		scheme.s = scheme.ss[pre.signers[0]]
+		for _, i := range pre.signers[1:] {
+			scheme.s = scheme.s.Add(scheme.ss[i])
 		}
 	}
 
@@ -852,8 +1083,8 @@ func (scheme *Scheme[A, B]) DSPhase5() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for party := 1; party <= scheme.n; party++ {
-		err := verify.Verify(scheme.curve, nil, scheme.Q, scheme.message, scheme.r, scheme.s)
+	for range pre.signers {
+		err := verify.Verify(scheme.curve, nil, scheme.Q, scheme.signerId, scheme.message, scheme.r, scheme.s)
 		if err != nil {
 			return err
 		}
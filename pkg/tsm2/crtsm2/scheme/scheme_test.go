@@ -1,25 +1,50 @@
 package scheme
 
 import (
+	"crypto/rand"
+	"errors"
+
 	"github.com/coinbase/kryptology/pkg/core"
 	"github.com/coinbase/kryptology/pkg/core/curves"
 	"github.com/coinbase/kryptology/pkg/elgamalexp"
 	"github.com/coinbase/kryptology/pkg/paillier"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
 	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/paillier"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/abort"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/verify"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
 
+func TestNewScheme(t *testing.T) {
+	curveInit := curves.K256()
+	_, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, []uint32{1}, nil)
+	require.Error(t, err)
+	_, err = NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, []uint32{1, 0}, nil)
+	require.Error(t, err)
+	_, err = NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, []uint32{2, 2}, nil)
+	require.Error(t, err)
+
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, []uint32{4, 9, 12}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, scheme.n)
+	require.NoError(t, scheme.SetMtA(12, 4, nil, nil))
+	require.Error(t, scheme.SetMtA(4, 4, nil, nil))
+	require.Error(t, scheme.SetMtA(4, 5, nil, nil))
+}
+
 func TestScheme_DKGPhase1(t *testing.T) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(2), nil)
+	require.NoError(t, err)
 
-	err := scheme.DKGPhase1()
+	err = scheme.DKGPhase1()
 	require.NoError(t, err, "failed in Phase 1 of DKG")
 
-	d := scheme.ds[0]
-	for id := 2; id <= scheme.n; id++ {
-		d = d.Add(scheme.ds[id-1])
+	d := scheme.ds[scheme.ids[0]]
+	for _, id := range scheme.ids[1:] {
+		d = d.Add(scheme.ds[id])
 	}
 	if !scheme.T.Equal(scheme.P.Mul(d)) {
 		panic("T")
@@ -28,34 +53,35 @@ func TestScheme_DKGPhase1(t *testing.T) {
 
 func TestScheme_DKGPhase2(t *testing.T) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(2), nil)
+	require.NoError(t, err)
 
-	err := scheme.DKGPhase1()
+	err = scheme.DKGPhase1()
 	require.NoError(t, err, "failed in Phase 1 of DKG")
 
 	err = scheme.DKGPhase2()
 	require.NoError(t, err, "failed in Phase 2 of DKG")
 	// TO VERIFY TWO CIPHERTEXTS
-	d := scheme.ds[0]
-	for id := 2; id <= scheme.n; id++ {
-		d = d.Add(scheme.ds[id-1])
+	d := scheme.ds[scheme.ids[0]]
+	for _, id := range scheme.ids[1:] {
+		d = d.Add(scheme.ds[id])
 	}
 	semiDecryptor := elgamalexp.NewSemiDecryptor(scheme.curve, nil, scheme.T, d)
 	// verify the correctness of gamma's ciphertext
 	ctGamma := elgamalexp.NewCiphertext(scheme.UGamma, scheme.VGamma)
 	semiGamma := semiDecryptor.SemiDecrypt(ctGamma)
-	gamma := scheme.gammas[0]
-	for id := 2; id <= scheme.n; id++ {
-		gamma = gamma.Add(scheme.gammas[id-1])
+	gamma := scheme.gammas[scheme.ids[0]]
+	for _, id := range scheme.ids[1:] {
+		gamma = gamma.Add(scheme.gammas[id])
 	}
 	err = elgamalexp.Compare(scheme.curve, nil, gamma, semiGamma)
 	require.NoError(t, err, "failed when generating encryption of gamma")
 	// verify the correctness of xGamma's ciphertext
 	ctXGamma := elgamalexp.NewCiphertext(scheme.UXGamma, scheme.VXGamma)
 	semiXGamma := semiDecryptor.SemiDecrypt(ctXGamma)
-	x := scheme.xs[0]
-	for id := 2; id <= scheme.n; id++ {
-		x = x.Add(scheme.xs[id-1])
+	x := scheme.xs[scheme.ids[0]]
+	for _, id := range scheme.ids[1:] {
+		x = x.Add(scheme.xs[id])
 	}
 	err = elgamalexp.Compare(scheme.curve, nil, x.Mul(gamma), semiXGamma)
 	require.NoError(t, err, "failed when generating encryption of xGamma")
@@ -63,14 +89,15 @@ func TestScheme_DKGPhase2(t *testing.T) {
 
 func TestScheme_DKGPhase3(t *testing.T) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(2), nil)
+	require.NoError(t, err)
 	var p, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var q, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var p0, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var q0, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	t.Log("safe primes generated")
-	for i := 1; i <= scheme.n; i++ {
-		for j := 1; j <= scheme.n; j++ {
+	for _, i := range scheme.ids {
+		for _, j := range scheme.ids {
 			if i == j {
 				continue
 			}
@@ -79,13 +106,13 @@ func TestScheme_DKGPhase3(t *testing.T) {
 			setup1Statement, setup1Proof := receiver.SetupInit()
 			setup2Statement, setup2Proof := sender.SetupUpdate(setup1Statement, setup1Proof)
 			receiver.SetupDone(setup2Statement, setup2Proof)
-			scheme.mtaSenders[i-1][j-1] = sender
-			scheme.mtaReceivers[i-1][j-1] = receiver
+			scheme.mtaSenders[i][j] = sender
+			scheme.mtaReceivers[i][j] = receiver
 			t.Logf("MtA between party %d and party %d initiated", i, j)
 		}
 	}
 
-	err := scheme.DKGPhase1()
+	err = scheme.DKGPhase1()
 	require.NoError(t, err, "failed in Phase 1 of DKG")
 
 	err = scheme.DKGPhase2()
@@ -95,28 +122,28 @@ func TestScheme_DKGPhase3(t *testing.T) {
 	require.NoError(t, err, "failed in Phase 3 of DKG")
 
 	// verify encryption of sigma=sum(sigmas)
-	d := scheme.ds[0]
-	for id := 2; id <= scheme.n; id++ {
-		d = d.Add(scheme.ds[id-1])
+	d := scheme.ds[scheme.ids[0]]
+	for _, id := range scheme.ids[1:] {
+		d = d.Add(scheme.ds[id])
 	}
 	semiDecryptor := elgamalexp.NewSemiDecryptor(scheme.curve, nil, scheme.T, d)
 	ctSigma := elgamalexp.NewCiphertext(scheme.USigma, scheme.VSigma)
 	semiSigma := semiDecryptor.SemiDecrypt(ctSigma)
-	sigma := scheme.sigmas[0]
-	for id := 2; id <= scheme.n; id++ {
-		sigma = sigma.Add(scheme.sigmas[id-1])
+	sigma := scheme.sigmas[scheme.ids[0]]
+	for _, id := range scheme.ids[1:] {
+		sigma = sigma.Add(scheme.sigmas[id])
 	}
 	err = elgamalexp.Compare(scheme.curve, nil, sigma, semiSigma)
 	require.NoError(t, err, "failed when generating encryption of sigma")
 
 	// verify sum(sigmas) = xr
-	x := scheme.xs[0]
-	for id := 2; id <= scheme.n; id++ {
-		x = x.Add(scheme.xs[id-1])
+	x := scheme.xs[scheme.ids[0]]
+	for _, id := range scheme.ids[1:] {
+		x = x.Add(scheme.xs[id])
 	}
-	gamma := scheme.gammas[0]
-	for id := 2; id <= scheme.n; id++ {
-		gamma = gamma.Add(scheme.gammas[id-1])
+	gamma := scheme.gammas[scheme.ids[0]]
+	for _, id := range scheme.ids[1:] {
+		gamma = gamma.Add(scheme.gammas[id])
 	}
 	if sigma.Cmp(x.Mul(gamma)) != 0 {
 		panic("sum of sigmas is not equal to xr")
@@ -125,14 +152,15 @@ func TestScheme_DKGPhase3(t *testing.T) {
 
 func TestScheme_DKGPhase4(t *testing.T) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(2), nil)
+	require.NoError(t, err)
 	var p, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var q, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var p0, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var q0, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	t.Log("safe primes generated")
-	for i := 1; i <= scheme.n; i++ {
-		for j := 1; j <= scheme.n; j++ {
+	for _, i := range scheme.ids {
+		for _, j := range scheme.ids {
 			if i == j {
 				continue
 			}
@@ -141,13 +169,13 @@ func TestScheme_DKGPhase4(t *testing.T) {
 			setup1Statement, setup1Proof := receiver.SetupInit()
 			setup2Statement, setup2Proof := sender.SetupUpdate(setup1Statement, setup1Proof)
 			receiver.SetupDone(setup2Statement, setup2Proof)
-			scheme.mtaSenders[i-1][j-1] = sender
-			scheme.mtaReceivers[i-1][j-1] = receiver
+			scheme.mtaSenders[i][j] = sender
+			scheme.mtaReceivers[i][j] = receiver
 			t.Logf("MtA between party %d and party %d initiated", i, j)
 		}
 	}
 
-	err := scheme.DKGPhase1()
+	err = scheme.DKGPhase1()
 	require.NoError(t, err, "failed in Phase 1 of DKG")
 
 	err = scheme.DKGPhase2()
@@ -162,17 +190,19 @@ func TestScheme_DKGPhase4(t *testing.T) {
 
 func TestScheme_DSPhase1(t *testing.T) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(2), nil)
+	require.NoError(t, err)
 
-	err := scheme.DSPhase1()
+	err = scheme.DSPhase1()
 	require.NoError(t, err, "failed in Phase 1 of DS")
 }
 
 func TestScheme_DSPhase2(t *testing.T) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(2), nil)
+	require.NoError(t, err)
 
-	err := scheme.DKGPhase1()
+	err = scheme.DKGPhase1()
 	require.NoError(t, err, "failed in Phase 1 of DKG")
 
 	err = scheme.DKGPhase2()
@@ -185,17 +215,17 @@ func TestScheme_DSPhase2(t *testing.T) {
 	require.NoError(t, err, "failed in Phase 2 of DS")
 
 	// verify the correctness of encryption of k*gamma
-	k := scheme.ks[0]
-	for i := 2; i <= scheme.n; i++ {
-		k = k.Add(scheme.ks[i-1])
+	k := scheme.ks[scheme.ids[0]]
+	for _, i := range scheme.ids[1:] {
+		k = k.Add(scheme.ks[i])
 	}
-	gamma := scheme.gammas[0]
-	for i := 2; i <= scheme.n; i++ {
-		gamma = gamma.Add(scheme.gammas[i-1])
+	gamma := scheme.gammas[scheme.ids[0]]
+	for _, i := range scheme.ids[1:] {
+		gamma = gamma.Add(scheme.gammas[i])
 	}
-	d := scheme.ds[0]
-	for i := 2; i <= scheme.n; i++ {
-		d = d.Add(scheme.ds[i-1])
+	d := scheme.ds[scheme.ids[0]]
+	for _, i := range scheme.ids[1:] {
+		d = d.Add(scheme.ds[i])
 	}
 	semiDecryptor := elgamalexp.NewSemiDecryptor(scheme.curve, nil, scheme.T, d)
 	ctKGamma := elgamalexp.NewCiphertext(scheme.AKGamma, scheme.BKGamma)
@@ -204,16 +234,44 @@ func TestScheme_DSPhase2(t *testing.T) {
 	require.NoError(t, err, "failed in generating the ciphertext of k*gamma")
 }
 
+func TestScheme_DSPhase2IdentifiesCulprit(t *testing.T) {
+	curveInit := curves.K256()
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, []uint32{3, 5, 8}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, scheme.DKGPhase1(), "failed in Phase 1 of DKG")
+	require.NoError(t, scheme.DKGPhase2(), "failed in Phase 2 of DKG")
+	require.NoError(t, scheme.DSPhase1(), "failed in Phase 1 of DS")
+
+	// party 5 re-randomizes with a nonce other than the one it committed to
+	scheme.ks[5] = scheme.curve.Scalar.Random(rand.Reader)
+	err = scheme.DSPhase2()
+	var abortErr *abort.Error
+	require.True(t, errors.As(err, &abortErr))
+	require.Equal(t, uint32(5), abortErr.Culprit)
+	require.Equal(t, "DSPhase2", abortErr.Phase)
+	require.Equal(t, abort.DsSRanCTGamma, abortErr.Evidence.Check)
+	confirmed, err := abortErr.Evidence.Confirm(scheme.curve, &abort.Evidence{
+		Check:     abort.DsSRanCTGamma,
+		Proof:     scheme.sRanCTGammaProofs[5],
+		SessionId: scheme.sRanCTGammaProofSessionIds[5],
+		Inputs:    []curves.Point{scheme.T, scheme.UGamma, scheme.VGamma, scheme.kProofs[5].Statement},
+	})
+	require.NoError(t, err)
+	require.True(t, confirmed)
+}
+
 func TestScheme_DSPhase3(t *testing.T) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(2), nil)
+	require.NoError(t, err)
 	var p, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var q, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var p0, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var q0, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	t.Log("safe primes generated")
-	for i := 1; i <= scheme.n; i++ {
-		for j := 1; j <= scheme.n; j++ {
+	for _, i := range scheme.ids {
+		for _, j := range scheme.ids {
 			if i == j {
 				continue
 			}
@@ -222,13 +280,13 @@ func TestScheme_DSPhase3(t *testing.T) {
 			setup1Statement, setup1Proof := receiver.SetupInit()
 			setup2Statement, setup2Proof := sender.SetupUpdate(setup1Statement, setup1Proof)
 			receiver.SetupDone(setup2Statement, setup2Proof)
-			scheme.mtaSenders[i-1][j-1] = sender
-			scheme.mtaReceivers[i-1][j-1] = receiver
+			scheme.mtaSenders[i][j] = sender
+			scheme.mtaReceivers[i][j] = receiver
 			t.Logf("MtA between party %d and party %d initiated", i, j)
 		}
 	}
 
-	err := scheme.DKGPhase1()
+	err = scheme.DKGPhase1()
 	require.NoError(t, err, "failed in Phase 1 of DKG")
 
 	err = scheme.DKGPhase2()
@@ -250,13 +308,13 @@ func TestScheme_DSPhase3(t *testing.T) {
 	require.NoError(t, err, "failed in Phase 3 of DS")
 
 	// verify the correctness of encryption of sum(delta_i)
-	delta := scheme.deltas[0]
-	for i := 2; i <= scheme.n; i++ {
-		delta = delta.Add(scheme.deltas[i-1])
+	delta := scheme.deltas[scheme.ids[0]]
+	for _, i := range scheme.ids[1:] {
+		delta = delta.Add(scheme.deltas[i])
 	}
-	d := scheme.ds[0]
-	for i := 2; i <= scheme.n; i++ {
-		d = d.Add(scheme.ds[i-1])
+	d := scheme.ds[scheme.ids[0]]
+	for _, i := range scheme.ids[1:] {
+		d = d.Add(scheme.ds[i])
 	}
 	semiDecryptor := elgamalexp.NewSemiDecryptor(scheme.curve, nil, scheme.T, d)
 	ctDelta := elgamalexp.NewCiphertext(scheme.ADelta, scheme.BDelta)
@@ -265,13 +323,13 @@ func TestScheme_DSPhase3(t *testing.T) {
 	require.NoError(t, err, "failed in generating the ciphertext of delta")
 
 	// verify sum(delta_i) = k*gamma
-	k := scheme.ks[0]
-	for i := 2; i <= scheme.n; i++ {
-		k = k.Add(scheme.ks[i-1])
+	k := scheme.ks[scheme.ids[0]]
+	for _, i := range scheme.ids[1:] {
+		k = k.Add(scheme.ks[i])
 	}
-	gamma := scheme.gammas[0]
-	for i := 2; i <= scheme.n; i++ {
-		gamma = gamma.Add(scheme.gammas[i-1])
+	gamma := scheme.gammas[scheme.ids[0]]
+	for _, i := range scheme.ids[1:] {
+		gamma = gamma.Add(scheme.gammas[i])
 	}
 	if delta.Cmp(k.Mul(gamma)) != 0 {
 		panic("delta is not equal to k*gamma")
@@ -280,14 +338,15 @@ func TestScheme_DSPhase3(t *testing.T) {
 
 func TestScheme_DSPhase4(t *testing.T) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(2), nil)
+	require.NoError(t, err)
 	var p, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var q, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var p0, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var q0, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	t.Log("safe primes generated")
-	for i := 1; i <= scheme.n; i++ {
-		for j := 1; j <= scheme.n; j++ {
+	for _, i := range scheme.ids {
+		for _, j := range scheme.ids {
 			if i == j {
 				continue
 			}
@@ -296,13 +355,13 @@ func TestScheme_DSPhase4(t *testing.T) {
 			setup1Statement, setup1Proof := receiver.SetupInit()
 			setup2Statement, setup2Proof := sender.SetupUpdate(setup1Statement, setup1Proof)
 			receiver.SetupDone(setup2Statement, setup2Proof)
-			scheme.mtaSenders[i-1][j-1] = sender
-			scheme.mtaReceivers[i-1][j-1] = receiver
+			scheme.mtaSenders[i][j] = sender
+			scheme.mtaReceivers[i][j] = receiver
 			t.Logf("MtA between party %d and party %d initiated", i, j)
 		}
 	}
 
-	err := scheme.DKGPhase1()
+	err = scheme.DKGPhase1()
 	require.NoError(t, err, "failed in Phase 1 of DKG")
 
 	err = scheme.DKGPhase2()
@@ -328,8 +387,10 @@ func TestScheme_DSPhase4(t *testing.T) {
 }
 
 func TestScheme_DSPhase5(t *testing.T) {
-	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	curveInit := curves.SM2()
+	signerId := []byte("ALICE123@YAHOO.COM")
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, []uint32{3, 7}, signerId)
+	require.NoError(t, err)
 	str := "test message"
 	scheme.message = []byte(str)
 	var p, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
@@ -337,8 +398,8 @@ func TestScheme_DSPhase5(t *testing.T) {
 	var p0, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var q0, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	t.Log("safe primes generated")
-	for i := 1; i <= scheme.n; i++ {
-		for j := 1; j <= scheme.n; j++ {
+	for _, i := range scheme.ids {
+		for _, j := range scheme.ids {
 			if i == j {
 				continue
 			}
@@ -347,13 +408,13 @@ func TestScheme_DSPhase5(t *testing.T) {
 			setup1Statement, setup1Proof := receiver.SetupInit()
 			setup2Statement, setup2Proof := sender.SetupUpdate(setup1Statement, setup1Proof)
 			receiver.SetupDone(setup2Statement, setup2Proof)
-			scheme.mtaSenders[i-1][j-1] = sender
-			scheme.mtaReceivers[i-1][j-1] = receiver
+			scheme.mtaSenders[i][j] = sender
+			scheme.mtaReceivers[i][j] = receiver
 			t.Logf("MtA between party %d and party %d initiated", i, j)
 		}
 	}
 
-	err := scheme.DKGPhase1()
+	err = scheme.DKGPhase1()
 	require.NoError(t, err, "failed in Phase 1 of DKG")
 
 	err = scheme.DKGPhase2()
@@ -378,12 +439,20 @@ func TestScheme_DSPhase5(t *testing.T) {
 	require.NoError(t, err, "failed in Phase 4 of DS")
 
 	err = scheme.DSPhase5()
-	require.NoError(t, err, "failed in Phase 4 of DS")
+	require.NoError(t, err, "failed in Phase 5 of DS")
+
+	// the threshold signature must pass the standard GB/T 32918 verification
+	pk, sig := verify.ToStandard(scheme.curve, scheme.Q, scheme.r, scheme.s)
+	err = sm2.Verify(scheme.curve, pk, signerId, scheme.message, sig)
+	require.NoError(t, err, "failed in standard verification")
+	_, err = sig.MarshalDER()
+	require.NoError(t, err, "failed in DER encoding")
 }
 
 func BenchmarkDKGPaillier(b *testing.B) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(2), nil)
+	require.NoError(b, err)
 	str := "test message test message test message test message test message test message test message test message test message test message "
 	scheme.message = []byte(str)
 
@@ -396,10 +465,13 @@ func BenchmarkDKGPaillier(b *testing.B) {
 	setup1Statement, setup1Proof := receiver.SetupInit()
 	setup2Statement, setup2Proof := sender.SetupUpdate(setup1Statement, setup1Proof)
 	receiver.SetupDone(setup2Statement, setup2Proof)
-	for i := 1; i <= scheme.n; i++ {
-		for j := i + 1; j <= scheme.n; j++ {
-			scheme.mtaSenders[i-1][j-1] = sender
-			scheme.mtaReceivers[i-1][j-1] = receiver
+	for _, i := range scheme.ids {
+		for _, j := range scheme.ids {
+			if j <= i {
+				continue
+			}
+			scheme.mtaSenders[i][j] = sender
+			scheme.mtaReceivers[i][j] = receiver
 			b.Logf("MtA between party %d and party %d initiated", i, j)
 		}
 	}
@@ -423,7 +495,8 @@ func BenchmarkDKGPaillier(b *testing.B) {
 
 func BenchmarkDSPaillier(b *testing.B) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(2), nil)
+	require.NoError(b, err)
 	str := "test message test message test message test message test message test message test message test message test message test message "
 	scheme.message = []byte(str)
 
@@ -436,15 +509,18 @@ func BenchmarkDSPaillier(b *testing.B) {
 	setup1Statement, setup1Proof := receiver.SetupInit()
 	setup2Statement, setup2Proof := sender.SetupUpdate(setup1Statement, setup1Proof)
 	receiver.SetupDone(setup2Statement, setup2Proof)
-	for i := 1; i <= scheme.n; i++ {
-		for j := i + 1; j <= scheme.n; j++ {
-			scheme.mtaSenders[i-1][j-1] = sender
-			scheme.mtaReceivers[i-1][j-1] = receiver
+	for _, i := range scheme.ids {
+		for _, j := range scheme.ids {
+			if j <= i {
+				continue
+			}
+			scheme.mtaSenders[i][j] = sender
+			scheme.mtaReceivers[i][j] = receiver
 			b.Logf("MtA between party %d and party %d initiated", i, j)
 		}
 	}
 
-	err := scheme.DKGPhase1()
+	err = scheme.DKGPhase1()
 	require.NoError(b, err, "failed in Phase 1 of DKG")
 
 	err = scheme.DKGPhase2()
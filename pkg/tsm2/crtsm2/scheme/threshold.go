@@ -0,0 +1,285 @@
+package scheme
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/sharing"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/dkg"
+)
+
+// maxShareId is the largest identifier that pkg/sharing can use as an evaluation point
+const maxShareId = 255
+
+// NewThresholdScheme creates a scheme run by the parties with the given identifiers in which any t+1 of them can sign.
+// The identifiers are the evaluation points of the Shamir shares, so they must not exceed 255.
+// A nil signerId selects the default distinguishing identifier.
+func NewThresholdScheme[A any, B any](curve *curves.Curve, ids []uint32, t int, signerId []byte) (*Scheme[A, B], error) {
+	scheme, err := NewScheme[A, B](curve, ids, signerId)
+	if err != nil {
+		return nil, err
+	}
+	if t < 1 || t >= len(ids) {
+		return nil, fmt.Errorf("threshold must be between 1 and %d, got %d", len(ids)-1, t)
+	}
+	for _, id := range ids {
+		if id > maxShareId {
+			return nil, fmt.Errorf("party identifier %d exceeds %d", id, maxShareId)
+		}
+	}
+	scheme.t = t
+	scheme.signers = nil
+	return scheme, nil
+}
+
+// DKGPhase5 deals Feldman shares of every x_i, gamma_i and d_i, after which any t+1 parties jointly hold x, gamma
+// and d. Each dealer also proves that its commitment to gamma_i opens the encryption of gamma_i from DKGPhase2.
+// It only applies to threshold schemes and runs after DKGPhase4.
+func (scheme *Scheme[A, B]) DKGPhase5() error {
+	if scheme.t == 0 {
+		return fmt.Errorf("not a threshold scheme")
+	}
+	feldman, err := sharing.NewFeldman(uint32(scheme.t+1), scheme.shareLimit(), scheme.curve)
+	if err != nil {
+		return err
+	}
+
+	// split x_i, gamma_i and d_i, the shares are indexed by dealer and then by recipient
+	xShares := pairwise[*sharing.ShamirShare](scheme.ids)
+	gammaShares := pairwise[*sharing.ShamirShare](scheme.ids)
+	dShares := pairwise[*sharing.ShamirShare](scheme.ids)
+	for _, i := range scheme.ids {
+		xVerifier, shares, err := feldman.Split(scheme.xs[i], rand.Reader)
+		if err != nil {
+			return err
+		}
+		scheme.xVerifiers[i] = xVerifier
+		for _, j := range scheme.ids {
+			xShares[i][j] = shares[j-1]
+		}
+
+		gammaVerifier, shares, err := feldman.Split(scheme.gammas[i], rand.Reader)
+		if err != nil {
+			return err
+		}
+		scheme.gammaVerifiers[i] = gammaVerifier
+		for _, j := range scheme.ids {
+			gammaShares[i][j] = shares[j-1]
+		}
+		linkProof, linkProofSessionId, err := dkg.GammaLinkProve(scheme.curve, scheme.T, gammaVerifier.Commitments[0], scheme.gammaRegProofs[i], scheme.rGammas[i], nil)
+		if err != nil {
+			return err
+		}
+		scheme.gammaLinkProofs[i] = linkProof
+		scheme.gammaLinkProofSessionIds[i] = linkProofSessionId
+
+		dVerifier, shares, err := feldman.Split(scheme.ds[i], rand.Reader)
+		if err != nil {
+			return err
+		}
+		scheme.dVerifiers[i] = dVerifier
+		for _, j := range scheme.ids {
+			dShares[i][j] = shares[j-1]
+		}
+	}
+
+	// verify the shares dealt by the other parties
+	/****************************************
+	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
+	****************************************/
+	for _, j := range scheme.ids {
+		for _, i := range scheme.ids {
+			if i == j {
+				continue
+			}
+			if !scheme.xVerifiers[i].Commitments[0].Equal(scheme.QProofs[i].Statement) {
+				return fmt.Errorf("party %d did not share its key share", i)
+			}
+			err := dkg.GammaLinkVerify(scheme.curve, scheme.T, scheme.gammaVerifiers[i].Commitments[0], scheme.gammaRegProofs[i], scheme.gammaLinkProofs[i], scheme.gammaLinkProofSessionIds[i])
+			if err != nil {
+				return fmt.Errorf("party %d did not share the gamma it encrypted: %w", i, err)
+			}
+			if !scheme.dVerifiers[i].Commitments[0].Equal(scheme.TProofs[i].Statement) {
+				return fmt.Errorf("party %d did not share its ElGamal key", i)
+			}
+			if err := scheme.xVerifiers[i].Verify(xShares[i][j]); err != nil {
+				return fmt.Errorf("invalid share of x from party %d to party %d", i, j)
+			}
+			if err := scheme.gammaVerifiers[i].Verify(gammaShares[i][j]); err != nil {
+				return fmt.Errorf("invalid share of gamma from party %d to party %d", i, j)
+			}
+			if err := scheme.dVerifiers[i].Verify(dShares[i][j]); err != nil {
+				return fmt.Errorf("invalid share of d from party %d to party %d", i, j)
+			}
+		}
+	}
+
+	// add up the received shares
+	for _, j := range scheme.ids {
+		var err error
+		if scheme.xShares[j], err = scheme.sumShares(xShares, j); err != nil {
+			return err
+		}
+		if scheme.gammaShares[j], err = scheme.sumShares(gammaShares, j); err != nil {
+			return err
+		}
+		if scheme.dShares[j], err = scheme.sumShares(dShares, j); err != nil {
+			return err
+		}
+	}
+
+	// compute the public keys of the shares of x and d
+	/****************************************
+	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
+	****************************************/
+	for range scheme.ids {
+		for _, j := range scheme.ids {
+			QShare := scheme.curve.NewIdentityPoint()
+			TShare := scheme.curve.NewIdentityPoint()
+			for _, i := range scheme.ids {
+				QShare = QShare.Add(evaluateCommitments(scheme.curve, scheme.xVerifiers[i], j))
+				TShare = TShare.Add(evaluateCommitments(scheme.curve, scheme.dVerifiers[i], j))
+			}
+			scheme.QShares[j] = QShare
+			scheme.TShares[j] = TShare
+		}
+	}
+
+	return nil
+}
+
+// sumShares adds up the shares that every dealer sent to party j
+func (scheme *Scheme[A, B]) sumShares(shares map[uint32]map[uint32]*sharing.ShamirShare, j uint32) (curves.Scalar, error) {
+	sum := scheme.curve.Scalar.Zero()
+	for _, i := range scheme.ids {
+		share, err := scheme.curve.Scalar.SetBytes(shares[i][j].Value)
+		if err != nil {
+			return nil, err
+		}
+		sum = sum.Add(share)
+	}
+	return sum, nil
+}
+
+// SetSigners selects the parties that take part in the following signing sessions.
+// An n-of-n scheme needs every party, whereas a threshold scheme needs at least t+1 of them,
+// whose shares of x, gamma and d are weighted by their Lagrange coefficients.
+func (scheme *Scheme[A, B]) SetSigners(signers []uint32) error {
+	min := scheme.t + 1
+	if scheme.t == 0 {
+		min = scheme.n
+	}
+	if len(signers) < min {
+		return fmt.Errorf("at least %d signers are required, got %d", min, len(signers))
+	}
+	seen := make(map[uint32]bool, len(signers))
+	for _, id := range signers {
+		if seen[id] {
+			return fmt.Errorf("duplicate signer %d", id)
+		}
+		seen[id] = true
+		if !scheme.isParty(id) {
+			return fmt.Errorf("unknown party %d", id)
+		}
+	}
+
+	// without threshold sharing every party signs with its additive shares
+	if scheme.t == 0 {
+		scheme.signers = signers
+		return nil
+	}
+
+	if len(scheme.gammaShares) == 0 {
+		return fmt.Errorf("shares have not been dealt")
+	}
+	shamir, err := sharing.NewShamir(uint32(scheme.t+1), scheme.shareLimit(), scheme.curve)
+	if err != nil {
+		return err
+	}
+	lambdas, err := shamir.LagrangeCoeffs(signers)
+	if err != nil {
+		return err
+	}
+	for _, id := range signers {
+		scheme.signXs[id] = lambdas[id].Mul(scheme.xShares[id])
+		scheme.signQs[id] = scheme.QShares[id].Mul(lambdas[id])
+		scheme.signGammas[id] = lambdas[id].Mul(scheme.gammaShares[id])
+		scheme.signDs[id] = lambdas[id].Mul(scheme.dShares[id])
+		scheme.signTs[id] = scheme.TShares[id].Mul(lambdas[id])
+	}
+	scheme.signers = signers
+	return nil
+}
+
+func (scheme *Scheme[A, B]) isParty(id uint32) bool {
+	for _, other := range scheme.ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+// shareLimit is the number of shares to split into, which is the largest identifier
+func (scheme *Scheme[A, B]) shareLimit() uint32 {
+	limit := scheme.ids[0]
+	for _, id := range scheme.ids[1:] {
+		if id > limit {
+			limit = id
+		}
+	}
+	return limit
+}
+
+// evaluateCommitments returns the public counterpart of the share of id, i.e. sum(C_k * id^k)
+func evaluateCommitments(curve *curves.Curve, verifier *sharing.FeldmanVerifier, id uint32) curves.Point {
+	x := curve.Scalar.New(int(id))
+	power := curve.Scalar.One()
+	result := verifier.Commitments[0]
+	for _, commitment := range verifier.Commitments[1:] {
+		power = power.Mul(x)
+		result = result.Add(commitment.Mul(power))
+	}
+	return result
+}
+
+// signX returns the share of the signing key that signer i uses
+func (scheme *Scheme[A, B]) signX(i uint32) curves.Scalar {
+	if scheme.t == 0 {
+		return scheme.xs[i]
+	}
+	return scheme.signXs[i]
+}
+
+// signQ returns the public key of the share of the signing key of signer i
+func (scheme *Scheme[A, B]) signQ(i uint32) curves.Point {
+	if scheme.t == 0 {
+		return scheme.QProofs[i].Statement
+	}
+	return scheme.signQs[i]
+}
+
+// signGamma returns the share of gamma that signer i uses
+func (scheme *Scheme[A, B]) signGamma(i uint32) curves.Scalar {
+	if scheme.t == 0 {
+		return scheme.gammas[i]
+	}
+	return scheme.signGammas[i]
+}
+
+// signD returns the share of the ElGamal decryption key that signer i uses
+func (scheme *Scheme[A, B]) signD(i uint32) curves.Scalar {
+	if scheme.t == 0 {
+		return scheme.ds[i]
+	}
+	return scheme.signDs[i]
+}
+
+// signT returns the public key of the share of the ElGamal decryption key of signer i
+func (scheme *Scheme[A, B]) signT(i uint32) curves.Point {
+	if scheme.t == 0 {
+		return scheme.TProofs[i].Statement
+	}
+	return scheme.signTs[i]
+}
@@ -0,0 +1,160 @@
+package scheme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/ot/base/simplest"
+	"github.com/coinbase/kryptology/pkg/ot/extension/kos"
+	"github.com/coinbase/kryptology/pkg/ot/ottest"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
+	mta_ot "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/ot"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/verify"
+)
+
+func newThresholdScheme(t *testing.T, curve *curves.Curve, ids []uint32, threshold int) *Scheme[*mta_ot.Round1Output, *mta_ot.Round2Output] {
+	scheme, err := NewThresholdScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curve, ids, threshold, nil)
+	require.NoError(t, err)
+	setOTMtA(t, scheme)
+	return scheme
+}
+
+// setOTMtA sets up an OT-based MtA between every pair of parties
+func setOTMtA(t *testing.T, scheme *Scheme[*mta_ot.Round1Output, *mta_ot.Round2Output]) {
+	curve := scheme.curve
+	for _, i := range scheme.ids {
+		for _, j := range scheme.ids {
+			if i >= j {
+				continue
+			}
+			uniqueSessionId := [simplest.DigestSize]byte{byte(i), byte(j)}
+			baseOtSenderOutput, baseOtReceiverOutput, err := ottest.RunSimplestOT(curve, kos.Kappa, uniqueSessionId)
+			require.NoError(t, err)
+			sender, err := mta_ot.NewSender(baseOtReceiverOutput, curve, uniqueSessionId)
+			require.NoError(t, err)
+			receiver, err := mta_ot.NewReceiver(baseOtSenderOutput, curve, uniqueSessionId)
+			require.NoError(t, err)
+			require.NoError(t, scheme.SetMtA(i, j, sender, receiver))
+		}
+	}
+}
+
+func runThresholdDKG(t *testing.T, scheme *Scheme[*mta_ot.Round1Output, *mta_ot.Round2Output]) {
+	phases := []func() error{scheme.DKGPhase1, scheme.DKGPhase2, scheme.DKGPhase3, scheme.DKGPhase4, scheme.DKGPhase5}
+	for i, phase := range phases {
+		require.NoError(t, phase(), "failed in Phase %d of DKG", i+1)
+	}
+}
+
+func runThresholdDS(t *testing.T, scheme *Scheme[*mta_ot.Round1Output, *mta_ot.Round2Output], signers []uint32, message []byte) {
+	require.NoError(t, scheme.SetSigners(signers))
+	scheme.message = message
+	phases := []func() error{scheme.DSPhase1, scheme.DSPhase2, scheme.DSPhase3, scheme.DSPhase4, scheme.DSPhase5}
+	for i, phase := range phases {
+		require.NoError(t, phase(), "failed in Phase %d of DS", i+1)
+	}
+	pk, sig := verify.ToStandard(scheme.curve, scheme.Q, scheme.r, scheme.s)
+	require.NoError(t, sm2.Verify(scheme.curve, pk, scheme.signerId, message, sig))
+}
+
+func TestNewThresholdScheme(t *testing.T) {
+	curveInit := curves.SM2()
+	_, err := NewThresholdScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit, []uint32{1, 2, 3}, 0, nil)
+	require.Error(t, err)
+	_, err = NewThresholdScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit, []uint32{1, 2, 3}, 3, nil)
+	require.Error(t, err)
+	_, err = NewThresholdScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit, []uint32{1, 256}, 1, nil)
+	require.Error(t, err)
+	_, err = NewThresholdScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit, []uint32{1, 1, 2}, 1, nil)
+	require.Error(t, err)
+
+	scheme, err := NewThresholdScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit, []uint32{1, 2, 3}, 2, nil)
+	require.NoError(t, err)
+	require.Error(t, scheme.SetSigners([]uint32{1, 2, 3}), "shares have not been dealt yet")
+	require.Error(t, scheme.DSPhase1(), "no signers selected")
+
+	nOfN, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit, []uint32{1, 2, 3}, nil)
+	require.NoError(t, err)
+	require.Error(t, nOfN.DKGPhase5())
+	require.Error(t, nOfN.SetSigners([]uint32{1, 2}), "every party must sign")
+}
+
+func TestThresholdScheme(t *testing.T) {
+	for _, curveInit := range []*curves.Curve{curves.SM2(), curves.K256()} {
+		scheme := newThresholdScheme(t, curveInit, []uint32{2, 5, 7, 11, 12}, 2)
+		runThresholdDKG(t, scheme)
+
+		// every party ends up with shares of x and d that match the public key shares
+		for _, id := range scheme.ids {
+			require.True(t, scheme.P.Mul(scheme.xShares[id]).Equal(scheme.QShares[id]))
+			require.True(t, scheme.P.Mul(scheme.dShares[id]).Equal(scheme.TShares[id]))
+		}
+
+		runThresholdDS(t, scheme, []uint32{2, 7, 12}, []byte("first message"))
+		runThresholdDS(t, scheme, []uint32{11, 5, 7}, []byte("second message"))
+		runThresholdDS(t, scheme, []uint32{2, 5, 7, 11}, []byte("third message"))
+	}
+}
+
+func TestThresholdSchemeSetSigners(t *testing.T) {
+	scheme := newThresholdScheme(t, curves.SM2(), []uint32{1, 2, 3, 4}, 1)
+	runThresholdDKG(t, scheme)
+
+	require.Error(t, scheme.SetSigners([]uint32{3}))
+	require.Error(t, scheme.SetSigners([]uint32{3, 3}))
+	require.Error(t, scheme.SetSigners([]uint32{3, 9}))
+	runThresholdDS(t, scheme, []uint32{4, 1}, []byte("test message"))
+}
+
+func TestThresholdSchemeRejectsBadShare(t *testing.T) {
+	scheme := newThresholdScheme(t, curves.SM2(), []uint32{1, 2, 3}, 1)
+	for _, phase := range []func() error{scheme.DKGPhase1, scheme.DKGPhase2, scheme.DKGPhase3, scheme.DKGPhase4} {
+		require.NoError(t, phase())
+	}
+
+	// a dealer sharing something other than its ElGamal key is caught
+	scheme.ds[2] = scheme.ds[2].Add(scheme.curve.Scalar.One())
+	require.Error(t, scheme.DKGPhase5())
+}
+
+func TestThresholdSchemeRejectsUnlinkedGamma(t *testing.T) {
+	scheme := newThresholdScheme(t, curves.SM2(), []uint32{1, 2, 3}, 1)
+	for _, phase := range []func() error{scheme.DKGPhase1, scheme.DKGPhase2, scheme.DKGPhase3, scheme.DKGPhase4} {
+		require.NoError(t, phase())
+	}
+
+	// a dealer sharing a gamma other than the one it encrypted is caught
+	scheme.gammas[3] = scheme.gammas[3].Add(scheme.curve.Scalar.One())
+	require.Error(t, scheme.DKGPhase5())
+}
+
+func TestThresholdSchemeRejectsBadKeyShare(t *testing.T) {
+	scheme := newThresholdScheme(t, curves.SM2(), []uint32{1, 2, 3}, 1)
+	for _, phase := range []func() error{scheme.DKGPhase1, scheme.DKGPhase2, scheme.DKGPhase3, scheme.DKGPhase4} {
+		require.NoError(t, phase())
+	}
+
+	// a dealer sharing something other than its share of x is caught
+	scheme.xs[1] = scheme.xs[1].Add(scheme.curve.Scalar.One())
+	require.Error(t, scheme.DKGPhase5())
+}
+
+func TestThresholdDecrypt(t *testing.T) {
+	curve := curves.SM2()
+	scheme := newThresholdScheme(t, curve, []uint32{1, 2, 3, 4}, 2)
+	runThresholdDKG(t, scheme)
+
+	ct, err := sm2.Encrypt(curve, scheme.Q.Sub(scheme.P), []byte("custody backup"))
+	require.NoError(t, err)
+	_, err = scheme.Decrypt(ct)
+	require.Error(t, err, "no signers selected")
+
+	for _, signers := range [][]uint32{{1, 2, 3}, {4, 2, 3}, {1, 2, 3, 4}} {
+		require.NoError(t, scheme.SetSigners(signers))
+		decrypted, err := scheme.Decrypt(ct)
+		require.NoError(t, err)
+		require.Equal(t, []byte("custody backup"), decrypted)
+	}
+}
@@ -9,13 +9,17 @@ import (
 	"github.com/coinbase/kryptology/pkg/paillier"
 	mta_ot "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/ot"
 	mta_paillier "github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/mta/paillier"
+	"github.com/coinbase/kryptology/pkg/tecdsa/2ecdsa/sign_offline"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/mta"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
 
 func BenchmarkDKGPaillier(b *testing.B) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(50), nil)
+	require.NoError(b, err)
 	str := "test message test message test message test message test message test message test message test message test message test message "
 	scheme.message = []byte(str)
 
@@ -28,8 +32,7 @@ func BenchmarkDKGPaillier(b *testing.B) {
 	setup1Statement, setup1Proof := receiver.SetupInit()
 	setup2Statement, setup2Proof := sender.SetupUpdate(setup1Statement, setup1Proof)
 	receiver.SetupDone(setup2Statement, setup2Proof)
-	scheme.mtaSender = sender
-	scheme.mtaReceiver = receiver
+	shareMtA[*mta_paillier.Round1Output, *mta_paillier.Round2Output](b, scheme, sender, receiver)
 
 	b.ResetTimer()
 
@@ -50,7 +53,8 @@ func BenchmarkDKGPaillier(b *testing.B) {
 
 func BenchmarkDSPaillier(b *testing.B) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, parties.Range(50), nil)
+	require.NoError(b, err)
 	str := "test message test message test message test message test message test message test message test message test message test message "
 	scheme.message = []byte(str)
 
@@ -63,10 +67,9 @@ func BenchmarkDSPaillier(b *testing.B) {
 	setup1Statement, setup1Proof := receiver.SetupInit()
 	setup2Statement, setup2Proof := sender.SetupUpdate(setup1Statement, setup1Proof)
 	receiver.SetupDone(setup2Statement, setup2Proof)
-	scheme.mtaSender = sender
-	scheme.mtaReceiver = receiver
+	shareMtA[*mta_paillier.Round1Output, *mta_paillier.Round2Output](b, scheme, sender, receiver)
 
-	err := scheme.DKGPhase1()
+	err = scheme.DKGPhase1()
 	require.NoError(b, err, "failed in Phase 1 of DKG")
 
 	err = scheme.DKGPhase2()
@@ -100,26 +103,28 @@ func BenchmarkDSPaillier(b *testing.B) {
 
 func BenchmarkMtAInitPaillier(b *testing.B) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit)
-	str := "test message test message test message test message test message test message test message test message test message test message "
-	scheme.message = []byte(str)
-
-	b.ResetTimer()
 
 	var p, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var q, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var p0, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
 	var q0, _ = core.GenerateSafePrime(paillier.PaillierPrimeBits)
-	var sender = mta_paillier.NewSender(scheme.curve, p, q)
-	var receiver = mta_paillier.NewReceiver(scheme.curve, p0, q0)
-	setup1Statement, setup1Proof := receiver.SetupInit()
-	setup2Statement, setup2Proof := sender.SetupUpdate(setup1Statement, setup1Proof)
-	receiver.SetupDone(setup2Statement, setup2Proof)
+	senderBackend, err := mta.NewPaillier(curveInit, p, q)
+	require.NoError(b, err)
+	receiverBackend, err := mta.NewPaillier(curveInit, p0, q0)
+	require.NoError(b, err)
+
+	b.ResetTimer()
+
+	for k := 0; k < b.N; k++ {
+		_, _, err = mta.Run[*mta_paillier.Round1Output, *mta_paillier.Round2Output](senderBackend, receiverBackend, nil, 1, 2)
+		require.NoError(b, err)
+	}
 }
 
 func BenchmarkDKGOT(b *testing.B) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit, parties.Range(50), nil)
+	require.NoError(b, err)
 	str := "test message test message test message test message test message test message test message test message test message test message "
 	scheme.message = []byte(str)
 
@@ -129,8 +134,7 @@ func BenchmarkDKGOT(b *testing.B) {
 
 	sender, _ := mta_ot.NewSender(baseOtReceiverOutput, curveInit, uniqueSessionId)
 	receiver, _ := mta_ot.NewReceiver(baseOtSenderOutput, curveInit, uniqueSessionId)
-	scheme.mtaSender = sender
-	scheme.mtaReceiver = receiver
+	shareMtA[*mta_ot.Round1Output, *mta_ot.Round2Output](b, scheme, sender, receiver)
 
 	b.ResetTimer()
 
@@ -151,7 +155,8 @@ func BenchmarkDKGOT(b *testing.B) {
 
 func BenchmarkDSOT(b *testing.B) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit)
+	scheme, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit, parties.Range(50), nil)
+	require.NoError(b, err)
 	str := "test message test message test message test message test message test message test message test message test message test message "
 	scheme.message = []byte(str)
 
@@ -161,10 +166,9 @@ func BenchmarkDSOT(b *testing.B) {
 
 	sender, _ := mta_ot.NewSender(baseOtReceiverOutput, curveInit, uniqueSessionId)
 	receiver, _ := mta_ot.NewReceiver(baseOtSenderOutput, curveInit, uniqueSessionId)
-	scheme.mtaSender = sender
-	scheme.mtaReceiver = receiver
+	shareMtA[*mta_ot.Round1Output, *mta_ot.Round2Output](b, scheme, sender, receiver)
 
-	err := scheme.DKGPhase1()
+	err = scheme.DKGPhase1()
 	require.NoError(b, err, "failed in Phase 1 of DKG")
 
 	err = scheme.DKGPhase2()
@@ -198,20 +202,79 @@ func BenchmarkDSOT(b *testing.B) {
 
 func BenchmarkMtAInitOT(b *testing.B) {
 	curveInit := curves.K256()
-	scheme := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit)
-	str := "test message test message test message test message test message test message test message test message test message test message "
-	scheme.message = []byte(str)
+	backend := mta.NewOT(curveInit)
 
 	b.ResetTimer()
 
 	for k := 0; k < b.N; k++ {
-		uniqueSessionId := [simplest.DigestSize]byte{}
+		_, _, err := mta.Run[*mta_ot.Round1Output, *mta_ot.Round2Output](backend, backend, nil, 1, 2)
+		require.NoError(b, err)
+	}
+}
+
+func TestSchemeOT(t *testing.T) {
+	curveInit := curves.SM2()
+	_, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit, []uint32{7}, nil)
+	require.Error(t, err)
 
-		baseOtSenderOutput, baseOtReceiverOutput, _ := ottest.RunSimplestOT(curveInit, kos.Kappa, uniqueSessionId)
+	scheme, err := NewScheme[*mta_ot.Round1Output, *mta_ot.Round2Output](curveInit, []uint32{2, 5, 11}, nil)
+	require.NoError(t, err)
+	scheme.message = []byte("test message")
+
+	backend := mta.NewOT(curveInit)
+	backends := map[uint32]mta.Backend[*mta_ot.Round1Output, *mta_ot.Round2Output]{2: backend, 5: backend, 11: backend}
+	require.NoError(t, scheme.SetupMtA(backends, []byte("test session")))
+	require.Error(t, scheme.SetMtA(2, 2, nil, nil))
+	require.Error(t, scheme.SetMtA(3, 2, nil, nil))
+
+	for i, phase := range []func() error{scheme.DKGPhase1, scheme.DKGPhase2, scheme.DKGPhase3, scheme.DKGPhase4} {
+		require.NoError(t, phase(), "failed in Phase %d of DKG", i+1)
+	}
+	for i, phase := range []func() error{scheme.DSPhase1, scheme.DSPhase2, scheme.DSPhase3, scheme.DSPhase4, scheme.DSPhase5} {
+		require.NoError(t, phase(), "failed in Phase %d of DS", i+1)
+	}
+}
+
+func TestSchemePaillier(t *testing.T) {
+	curveInit := curves.SM2()
+	ids := []uint32{1, 2}
+	scheme, err := NewScheme[*mta_paillier.Round1Output, *mta_paillier.Round2Output](curveInit, ids, nil)
+	require.NoError(t, err)
+	scheme.message = []byte("test message")
+
+	// a missing instance is reported rather than dereferenced
+	require.NoError(t, scheme.DKGPhase1())
+	require.NoError(t, scheme.DKGPhase2())
+	require.Error(t, scheme.DKGPhase3())
+
+	backends := make(map[uint32]mta.Backend[*mta_paillier.Round1Output, *mta_paillier.Round2Output])
+	require.Error(t, scheme.SetupMtA(backends, nil))
+	for _, id := range ids {
+		p, err := core.GenerateSafePrime(paillier.PaillierPrimeBits)
+		require.NoError(t, err)
+		q, err := core.GenerateSafePrime(paillier.PaillierPrimeBits)
+		require.NoError(t, err)
+		backends[id], err = mta.NewPaillier(curveInit, p, q)
+		require.NoError(t, err)
+	}
+	require.NoError(t, scheme.SetupMtA(backends, []byte("test session")))
+
+	for i, phase := range []func() error{scheme.DKGPhase1, scheme.DKGPhase2, scheme.DKGPhase3, scheme.DKGPhase4} {
+		require.NoError(t, phase(), "failed in Phase %d of DKG", i+1)
+	}
+	for i, phase := range []func() error{scheme.DSPhase1, scheme.DSPhase2, scheme.DSPhase3, scheme.DSPhase4, scheme.DSPhase5} {
+		require.NoError(t, phase(), "failed in Phase %d of DS", i+1)
+	}
+}
 
-		sender, _ := mta_ot.NewSender(baseOtReceiverOutput, curveInit, uniqueSessionId)
-		receiver, _ := mta_ot.NewReceiver(baseOtSenderOutput, curveInit, uniqueSessionId)
-		scheme.mtaSender = sender
-		scheme.mtaReceiver = receiver
+// shareMtA installs one instance for every pair of parties. The benchmarks measure the phases rather than the
+// per-pair setup, which is covered by BenchmarkMtAInitPaillier and BenchmarkMtAInitOT.
+func shareMtA[A any, B any](b *testing.B, scheme *Scheme[A, B], sender sign_offline.MTASender[A, B], receiver sign_offline.MTAReceiver[A, B]) {
+	for _, i := range scheme.ids {
+		for _, j := range scheme.ids {
+			if i != j {
+				require.NoError(b, scheme.SetMtA(i, j, sender, receiver))
+			}
+		}
 	}
 }
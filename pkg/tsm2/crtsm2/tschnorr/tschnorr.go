@@ -4,69 +4,111 @@ import (
 	"github.com/coinbase/kryptology/pkg/core/curves"
 	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/dkg"
 	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/verify"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
 	"github.com/coinbase/kryptology/pkg/zkp/schnorr"
 )
 
-const num = 50
-
 type Scheme struct {
 	curve *curves.Curve
 	P     curves.Point
 
-	n int
+	n   int
+	ids []uint32
 
 	message []byte
 
-	xs               [num]curves.Scalar
-	QProofs          [num]*schnorr.Proof
-	QCommitments     [num]schnorr.Commitment
-	QProofSessionIds [num]schnorr.SessionId
+	xs               map[uint32]curves.Scalar
+	QProofs          map[uint32]*schnorr.Proof
+	QCommitments     map[uint32]schnorr.Commitment
+	QProofSessionIds map[uint32]schnorr.SessionId
 	Q                curves.Point
 
-	ks               [num]curves.Scalar
-	RProofs          [num]*schnorr.Proof
-	RCommitments     [num]schnorr.Commitment
-	RProofSessionIds [num]schnorr.SessionId
+	ks               map[uint32]curves.Scalar
+	RProofs          map[uint32]*schnorr.Proof
+	RCommitments     map[uint32]schnorr.Commitment
+	RProofSessionIds map[uint32]schnorr.SessionId
 	R                curves.Point
 
-	ss [num]curves.Scalar
+	ss map[uint32]curves.Scalar
 
 	s curves.Scalar
 
 	e curves.Scalar
+
+	// bip340 selects BIP-340 signatures: x-only keys, even-Y Q and R, and the "BIP0340/challenge" tagged hash
+	bip340    bool
+	signature []byte
 }
 
-func NewScheme(curve *curves.Curve) *Scheme {
+// NewScheme creates a scheme run by the parties with the given identifiers
+func NewScheme(curve *curves.Curve, ids []uint32) (*Scheme, error) {
+	if err := parties.Validate(ids, 2); err != nil {
+		return nil, err
+	}
 	return &Scheme{
 		curve: curve,
-		n:     num,
+		n:     len(ids),
+		ids:   ids,
 		P:     curve.NewGeneratorPoint(),
+
+		xs:               make(map[uint32]curves.Scalar, len(ids)),
+		QProofs:          make(map[uint32]*schnorr.Proof, len(ids)),
+		QCommitments:     make(map[uint32]schnorr.Commitment, len(ids)),
+		QProofSessionIds: make(map[uint32]schnorr.SessionId, len(ids)),
+		ks:               make(map[uint32]curves.Scalar, len(ids)),
+		RProofs:          make(map[uint32]*schnorr.Proof, len(ids)),
+		RCommitments:     make(map[uint32]schnorr.Commitment, len(ids)),
+		RProofSessionIds: make(map[uint32]schnorr.SessionId, len(ids)),
+		ss:               make(map[uint32]curves.Scalar, len(ids)),
+	}, nil
+}
+
+// NewBIP340Scheme creates a scheme on K256 whose signatures are 64-byte R.x || s signatures as defined by BIP-340
+func NewBIP340Scheme(ids []uint32) (*Scheme, error) {
+	scheme, err := NewScheme(curves.K256(), ids)
+	if err != nil {
+		return nil, err
 	}
+	scheme.bip340 = true
+	return scheme, nil
+}
+
+// XOnlyPublicKey returns the 32-byte BIP-340 public key, which is only set for BIP-340 schemes after the DKG
+func (scheme *Scheme) XOnlyPublicKey() []byte {
+	if !scheme.bip340 || scheme.Q == nil {
+		return nil
+	}
+	return verify.XOnly(scheme.Q)
+}
+
+// Signature returns the 64-byte BIP-340 signature, which is only set for BIP-340 schemes after the DS
+func (scheme *Scheme) Signature() []byte {
+	return scheme.signature
 }
 
 func (scheme *Scheme) DKG() error {
 	// generate pks for T-Schnorr and proofs
-	for id := 1; id <= scheme.n; id++ {
-		x, QProof, QCommitment, QProofSessionId, err := dkg.PkComProve(scheme.curve)
+	for _, id := range scheme.ids {
+		x, QProof, QCommitment, QProofSessionId, err := dkg.PkComProve(scheme.curve, nil)
 		if err != nil {
 			return err
 		}
-		scheme.xs[id-1] = x
-		scheme.QProofs[id-1] = QProof
-		scheme.QCommitments[id-1] = QCommitment
-		scheme.QProofSessionIds[id-1] = QProofSessionId
+		scheme.xs[id] = x
+		scheme.QProofs[id] = QProof
+		scheme.QCommitments[id] = QCommitment
+		scheme.QProofSessionIds[id] = QProofSessionId
 	}
 
 	// de-com and verify Q proofs
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for id := 1; id <= scheme.n; id++ {
+	for _, numParty := range scheme.ids {
+		for _, id := range scheme.ids {
 			if id == numParty {
 				continue
 			}
-			err := dkg.PkDeComVerify(scheme.curve, scheme.QProofs[id-1], scheme.QCommitments[id-1], scheme.QProofSessionIds[id-1])
+			err := dkg.PkDeComVerify(scheme.curve, scheme.QProofs[id], scheme.QCommitments[id], scheme.QProofSessionIds[id])
 			if err != nil {
 				return err
 			}
@@ -77,39 +119,56 @@ func (scheme *Scheme) DKG() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		scheme.Q = scheme.QProofs[0].Statement
-		for id := 2; id <= scheme.n; id++ {
-			scheme.Q = scheme.Q.Add(scheme.QProofs[id-1].Statement)
+	for range scheme.ids {
+		scheme.Q = scheme.QProofs[scheme.ids[0]].Statement
+		for _, id := range scheme.ids[1:] {
+			scheme.Q = scheme.Q.Add(scheme.QProofs[id].Statement)
 		}
 	}
 
+	if scheme.bip340 {
+		scheme.liftQ()
+	}
+
 	return nil
 }
 
+// liftQ makes the y-coordinate of Q even, as BIP-340 keys are x-only: if it is odd, every party negates its share and
+// the public shares are negated along with them
+func (scheme *Scheme) liftQ() {
+	if verify.HasEvenY(scheme.Q) {
+		return
+	}
+	for _, id := range scheme.ids {
+		scheme.xs[id] = scheme.xs[id].Neg()
+		scheme.QProofs[id].Statement = scheme.QProofs[id].Statement.Neg()
+	}
+	scheme.Q = scheme.Q.Neg()
+}
+
 func (scheme *Scheme) DS() error {
 	// generate Rs and proofs
-	for id := 1; id <= scheme.n; id++ {
-		k, RProof, RCommitment, RProofSessionId, err := dkg.PkComProve(scheme.curve)
+	for _, id := range scheme.ids {
+		k, RProof, RCommitment, RProofSessionId, err := dkg.PkComProve(scheme.curve, nil)
 		if err != nil {
 			return err
 		}
-		scheme.ks[id-1] = k
-		scheme.RProofs[id-1] = RProof
-		scheme.RCommitments[id-1] = RCommitment
-		scheme.RProofSessionIds[id-1] = RProofSessionId
+		scheme.ks[id] = k
+		scheme.RProofs[id] = RProof
+		scheme.RCommitments[id] = RCommitment
+		scheme.RProofSessionIds[id] = RProofSessionId
 	}
 
 	// de-com and verify R proofs
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		for id := 1; id <= scheme.n; id++ {
+	for _, numParty := range scheme.ids {
+		for _, id := range scheme.ids {
 			if id == numParty {
 				continue
 			}
-			err := dkg.PkDeComVerify(scheme.curve, scheme.RProofs[id-1], scheme.RCommitments[id-1], scheme.RProofSessionIds[id-1])
+			err := dkg.PkDeComVerify(scheme.curve, scheme.RProofs[id], scheme.RCommitments[id], scheme.RProofSessionIds[id])
 			if err != nil {
 				return err
 			}
@@ -120,27 +179,31 @@ func (scheme *Scheme) DS() error {
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		scheme.R = scheme.RProofs[0].Statement
-		for id := 2; id <= scheme.n; id++ {
-			scheme.R = scheme.R.Add(scheme.RProofs[id-1].Statement)
+	for range scheme.ids {
+		scheme.R = scheme.RProofs[scheme.ids[0]].Statement
+		for _, id := range scheme.ids[1:] {
+			scheme.R = scheme.R.Add(scheme.RProofs[id].Statement)
 		}
 	}
 
+	if scheme.bip340 {
+		return scheme.bip340DS()
+	}
+
 	// compute e and si
-	for id := 1; id <= scheme.n; id++ {
+	for _, id := range scheme.ids {
 		scheme.e = scheme.curve.Scalar.Hash(append(append(scheme.R.ToAffineCompressed(), scheme.Q.ToAffineCompressed()...), scheme.message...))
-		scheme.ss[id-1] = scheme.ks[id-1].Sub(scheme.xs[id-1].Mul(scheme.e))
+		scheme.ss[id] = scheme.ks[id].Sub(scheme.xs[id].Mul(scheme.e))
 	}
 
 	// compute s and verify the signature
 	/****************************************
 	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
 	****************************************/
-	for numParty := 1; numParty <= scheme.n; numParty++ {
-		scheme.s = scheme.ss[0]
-		for id := 2; id <= scheme.n; id++ {
-			scheme.s = scheme.s.Add(scheme.ss[id-1])
+	for range scheme.ids {
+		scheme.s = scheme.ss[scheme.ids[0]]
+		for _, id := range scheme.ids[1:] {
+			scheme.s = scheme.s.Add(scheme.ss[id])
 		}
 		err := verify.SchnorrVerify(scheme.curve, nil, scheme.Q, scheme.message, scheme.e, scheme.s)
 		if err != nil {
@@ -150,3 +213,39 @@ func (scheme *Scheme) DS() error {
 
 	return nil
 }
+
+// bip340DS finishes the DS once R is known, producing a BIP-340 signature
+func (scheme *Scheme) bip340DS() error {
+	// normalize R to an even y-coordinate in the same way as Q
+	if !verify.HasEvenY(scheme.R) {
+		for _, id := range scheme.ids {
+			scheme.ks[id] = scheme.ks[id].Neg()
+			scheme.RProofs[id].Statement = scheme.RProofs[id].Statement.Neg()
+		}
+		scheme.R = scheme.R.Neg()
+	}
+
+	// compute e and si = ki + e * xi
+	for _, id := range scheme.ids {
+		scheme.e = verify.BIP340Challenge(verify.XOnly(scheme.R), verify.XOnly(scheme.Q), scheme.message)
+		scheme.ss[id] = scheme.ks[id].Add(scheme.e.Mul(scheme.xs[id]))
+	}
+
+	// compute s and verify the signature
+	/****************************************
+	EACH PARTY WILL DO THIS SIMILAR PROCEDURE
+	****************************************/
+	for range scheme.ids {
+		scheme.s = scheme.ss[scheme.ids[0]]
+		for _, id := range scheme.ids[1:] {
+			scheme.s = scheme.s.Add(scheme.ss[id])
+		}
+		scheme.signature = append(verify.XOnly(scheme.R), scheme.s.Bytes()...)
+		err := verify.BIP340Verify(verify.XOnly(scheme.Q), scheme.message, scheme.signature)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
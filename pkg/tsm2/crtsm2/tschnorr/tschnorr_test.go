@@ -1,32 +1,89 @@
 package tschnorr
 
 import (
+	"fmt"
+
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/tsm2/crtsm2/verify"
+	"github.com/coinbase/kryptology/pkg/tsm2/parties"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
 
+func TestScheme(t *testing.T) {
+	for _, ids := range [][]uint32{{1, 2, 3}, {2, 4, 6, 8, 10, 12, 14}} {
+		scheme, err := NewScheme(curves.K256(), ids)
+		require.NoError(t, err)
+		scheme.message = []byte("test message")
+		require.NoError(t, scheme.DKG())
+		require.NoError(t, scheme.DS())
+	}
+
+	_, err := NewScheme(curves.K256(), []uint32{1})
+	require.Error(t, err)
+	_, err = NewScheme(curves.K256(), []uint32{1, 2, 2})
+	require.Error(t, err)
+}
+
+func TestBIP340Scheme(t *testing.T) {
+	for _, ids := range [][]uint32{{1, 2}, {1, 2, 3}, {2, 4, 6, 8, 10, 12, 14}} {
+		scheme, err := NewBIP340Scheme(ids)
+		require.NoError(t, err)
+		require.NoError(t, scheme.DKG())
+		require.True(t, verify.HasEvenY(scheme.Q))
+		pk := scheme.XOnlyPublicKey()
+		require.Len(t, pk, 32)
+
+		requireSharesMatch(t, scheme)
+
+		// sign enough times that both parities of R occur
+		for i := 0; i < 8; i++ {
+			scheme.message = []byte(fmt.Sprintf("test message %d", i))
+			require.NoError(t, scheme.DS())
+			require.True(t, verify.HasEvenY(scheme.R))
+			signature := scheme.Signature()
+			require.Len(t, signature, verify.BIP340SignatureSize)
+			require.NoError(t, verify.BIP340Verify(pk, scheme.message, signature))
+			require.Error(t, verify.BIP340Verify(pk, []byte("another message"), signature))
+		}
+	}
+
+	_, err := NewBIP340Scheme([]uint32{1})
+	require.Error(t, err)
+
+	// the plain scheme has no x-only key or BIP-340 signature
+	scheme, err := NewScheme(curves.K256(), []uint32{1, 2})
+	require.NoError(t, err)
+	scheme.message = []byte("test message")
+	require.NoError(t, scheme.DKG())
+	require.NoError(t, scheme.DS())
+	require.Nil(t, scheme.XOnlyPublicKey())
+	require.Nil(t, scheme.Signature())
+}
+
 func BenchmarkDKG(b *testing.B) {
 	curveInit := curves.K256()
-	scheme := NewScheme(curveInit)
+	scheme, err := NewScheme(curveInit, parties.Range(50))
+	require.NoError(b, err)
 	str := "test message test message test message test message test message test message test message test message test message test message "
 	scheme.message = []byte(str)
 
 	b.ResetTimer()
 
 	for k := 0; k < b.N; k++ {
-		err := scheme.DKG()
+		err = scheme.DKG()
 		require.NoError(b, err, "failed in DKG")
 	}
 }
 
 func BenchmarkDS(b *testing.B) {
 	curveInit := curves.K256()
-	scheme := NewScheme(curveInit)
+	scheme, err := NewScheme(curveInit, parties.Range(50))
+	require.NoError(b, err)
 	str := "test message test message test message test message test message test message test message test message test message test message "
 	scheme.message = []byte(str)
 
-	err := scheme.DKG()
+	err = scheme.DKG()
 	require.NoError(b, err, "failed in DKG")
 
 	b.ResetTimer()
@@ -36,3 +93,38 @@ func BenchmarkDS(b *testing.B) {
 		require.NoError(b, err, "failed in DS")
 	}
 }
+
+func TestBIP340SchemeOddQ(t *testing.T) {
+	ids := []uint32{1, 2, 3}
+	scheme, err := NewScheme(curves.K256(), ids)
+	require.NoError(t, err)
+	// run plain DKGs until Q has an odd y-coordinate, which happens half of the time
+	for i := 0; i < 128; i++ {
+		require.NoError(t, scheme.DKG())
+		if !verify.HasEvenY(scheme.Q) {
+			break
+		}
+	}
+	require.False(t, verify.HasEvenY(scheme.Q))
+	oddQ := scheme.Q
+
+	scheme.bip340 = true
+	scheme.liftQ()
+	require.True(t, scheme.Q.Equal(oddQ.Neg()))
+	requireSharesMatch(t, scheme)
+
+	scheme.message = []byte("test message")
+	require.NoError(t, scheme.DS())
+	require.NoError(t, verify.BIP340Verify(scheme.XOnlyPublicKey(), scheme.message, scheme.Signature()))
+}
+
+// requireSharesMatch checks that every share matches its published public share and that the shares sum to the secret
+// key of Q
+func requireSharesMatch(t *testing.T, scheme *Scheme) {
+	x := scheme.curve.Scalar.Zero()
+	for _, id := range scheme.ids {
+		require.True(t, scheme.curve.ScalarBaseMult(scheme.xs[id]).Equal(scheme.QProofs[id].Statement))
+		x = x.Add(scheme.xs[id])
+	}
+	require.True(t, scheme.curve.ScalarBaseMult(x).Equal(scheme.Q))
+}
@@ -0,0 +1,93 @@
+package verify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+)
+
+// BIP340SignatureSize is the size of a BIP-340 signature R.x || s
+const BIP340SignatureSize = 64
+
+var (
+	// the field prime and group order of secp256k1
+	bip340P, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+	bip340N, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+)
+
+// TaggedHash computes SHA256(SHA256(tag) || SHA256(tag) || data) as defined by BIP-340
+func TaggedHash(tag string, data ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	_, _ = h.Write(tagHash[:])
+	_, _ = h.Write(tagHash[:])
+	for _, d := range data {
+		_, _ = h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// XOnly returns the 32-byte x-coordinate of a K256 point, which is how BIP-340 encodes public keys and nonces
+func XOnly(point curves.Point) []byte {
+	return point.ToAffineCompressed()[1:]
+}
+
+// HasEvenY reports whether the y-coordinate of a K256 point is even
+func HasEvenY(point curves.Point) bool {
+	return point.ToAffineCompressed()[0] == 0x02
+}
+
+// LiftX returns the K256 point with the given x-coordinate and an even y-coordinate
+func LiftX(x []byte) (curves.Point, error) {
+	if len(x) != 32 || new(big.Int).SetBytes(x).Cmp(bip340P) >= 0 {
+		return nil, fmt.Errorf("invalid x-coordinate")
+	}
+	point, err := curves.K256().Point.FromAffineCompressed(append([]byte{0x02}, x...))
+	if err != nil || point.IsIdentity() {
+		return nil, fmt.Errorf("x-coordinate is not on the curve")
+	}
+	return point, nil
+}
+
+// BIP340Challenge computes e = int(TaggedHash("BIP0340/challenge", R.x || P.x || m)) mod n
+func BIP340Challenge(rx []byte, pk []byte, message []byte) curves.Scalar {
+	e := new(big.Int).SetBytes(TaggedHash("BIP0340/challenge", rx, pk, message))
+	challenge, _ := curves.K256().Scalar.SetBigInt(e.Mod(e, bip340N))
+	return challenge
+}
+
+// BIP340Verify checks a 64-byte BIP-340 signature R.x || s over message under the x-only public key pk,
+// i.e. that s * G - e * P is the point with even y-coordinate whose x-coordinate is R.x
+func BIP340Verify(pk []byte, message []byte, signature []byte) error {
+	if len(signature) != BIP340SignatureSize {
+		return fmt.Errorf("invalid signature length")
+	}
+	P, err := LiftX(pk)
+	if err != nil {
+		return err
+	}
+	rx, sBytes := signature[:32], signature[32:]
+	if new(big.Int).SetBytes(rx).Cmp(bip340P) >= 0 {
+		return fmt.Errorf("r is not a field element")
+	}
+	s := new(big.Int).SetBytes(sBytes)
+	if s.Cmp(bip340N) >= 0 {
+		return fmt.Errorf("s is not a scalar")
+	}
+	curve := curves.K256()
+	sScalar, err := curve.Scalar.SetBigInt(s)
+	if err != nil {
+		return err
+	}
+	e := BIP340Challenge(rx, pk, message)
+
+	R := curve.ScalarBaseMult(sScalar).Sub(P.Mul(e))
+	if R.IsIdentity() || !HasEvenY(R) || !bytes.Equal(XOnly(R), rx) {
+		return fmt.Errorf("verification failed")
+	}
+
+	return nil
+}
@@ -3,22 +3,28 @@ package verify
 import (
 	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/signatures/sm2"
 	"math/big"
 )
 
-func Verify(curve *curves.Curve, basePoint curves.Point, pk curves.Point, message []byte, r curves.Scalar, s curves.Scalar) error {
+// Verify checks a signature in the CRT form, where pk = xG and s = (k + r) / x.
+// The message representative is the GB/T 32918 digest SM3(Z_A || M) computed for
+// the standard public key pk - G and the signer's distinguishing identifier.
+func Verify(curve *curves.Curve, basePoint curves.Point, pk curves.Point, signerId []byte, message []byte, r curves.Scalar, s curves.Scalar) error {
 	if basePoint == nil {
 		basePoint = curve.NewGeneratorPoint()
 	}
 
 	R := pk.Mul(s).Sub(basePoint.Mul(r))
 
-	h := curve.Scalar.Hash(message)
+	h, err := sm2.Digest(curve, signerId, pk.Sub(basePoint), message)
+	if err != nil {
+		return err
+	}
 
-	RAffine := R.ToAffineCompressed()
-	rx, err := curve.Scalar.SetBigInt(new(big.Int).SetBytes(RAffine[1 : 1+(len(RAffine)>>1)]))
+	rx, err := sm2.XCoordinate(curve, R)
 	if err != nil {
-		panic("when computing x-coordinate of R")
+		return err
 	}
 
 	if r.Cmp(rx.Add(h)) != 0 {
@@ -28,6 +34,12 @@ func Verify(curve *curves.Curve, basePoint curves.Point, pk curves.Point, messag
 	return nil
 }
 
+// ToStandard converts a public key and a signature in the CRT form into the
+// GB/T 32918 form so they can be checked by any standard SM2 verifier
+func ToStandard(curve *curves.Curve, pk curves.Point, r curves.Scalar, s curves.Scalar) (curves.Point, *sm2.Signature) {
+	return pk.Sub(curve.NewGeneratorPoint()), &sm2.Signature{R: r, S: s.Sub(r)}
+}
+
 func ECDSAVerify(curve *curves.Curve, basePoint curves.Point, pk curves.Point, message []byte, r curves.Scalar, s curves.Scalar) error {
 	if basePoint == nil {
 		basePoint = curve.NewGeneratorPoint()
@@ -0,0 +1,78 @@
+// Package parties validates the party identifiers shared by the tsm2 schemes.
+// Every scheme addresses its parties by a non-zero identifier rather than by position,
+// so that the same binary can serve any number of parties.
+package parties
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Validate checks that ids holds at least min distinct, non-zero identifiers
+func Validate(ids []uint32, min int) error {
+	if len(ids) < min {
+		return fmt.Errorf("at least %d parties are required, got %d", min, len(ids))
+	}
+	seen := make(map[uint32]bool, len(ids))
+	for _, id := range ids {
+		if id == 0 {
+			return fmt.Errorf("party identifiers must be non-zero")
+		}
+		if seen[id] {
+			return fmt.Errorf("duplicate party identifier %d", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// Range returns the identifiers 1, ..., n
+func Range(n int) []uint32 {
+	ids := make([]uint32, n)
+	for i := range ids {
+		ids[i] = uint32(i + 1)
+	}
+	return ids
+}
+
+// ProofSessionId derives the session id of the proof labeled label that the party sender produces in the session
+// sessionId. sessionId must be the same for all parties and unique to the session; binding the sender and the label as
+// well keeps a proof from being replayed in another session, by another party or in place of another proof.
+func ProofSessionId(sessionId []byte, sender uint32, label string) []byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte("tsm2 proof"))
+	writeBytes(h, sessionId)
+	_ = binary.Write(h, binary.BigEndian, sender)
+	_, _ = h.Write([]byte(label))
+	return h.Sum(nil)
+}
+
+// SubSessionId derives the session id of a sub-session, such as one signature under a key generated in the session
+// sessionId, from the contribution of every party, keyed by identifier. The contributions are typically the parties'
+// first commitments of the sub-session, which makes the id fresh as long as one party is honest.
+func SubSessionId(sessionId []byte, label string, contributions map[uint32][]byte) []byte {
+	ids := make([]uint32, 0, len(contributions))
+	for id := range contributions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	h := sha3.New256()
+	_, _ = h.Write([]byte("tsm2 sub-session"))
+	writeBytes(h, sessionId)
+	writeBytes(h, []byte(label))
+	for _, id := range ids {
+		_ = binary.Write(h, binary.BigEndian, id)
+		writeBytes(h, contributions[id])
+	}
+	return h.Sum(nil)
+}
+
+// writeBytes writes b prefixed with its length, which keeps adjacent variable-length values apart
+func writeBytes(w io.Writer, b []byte) {
+	_ = binary.Write(w, binary.BigEndian, uint64(len(b)))
+	_, _ = w.Write(b)
+}
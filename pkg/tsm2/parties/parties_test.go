@@ -0,0 +1,44 @@
+package parties
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate([]uint32{1, 2, 3}, 2))
+	require.NoError(t, Validate([]uint32{7, 3}, 2))
+	require.Error(t, Validate([]uint32{1}, 2))
+	require.Error(t, Validate(nil, 1))
+	require.Error(t, Validate([]uint32{1, 0}, 2))
+	require.Error(t, Validate([]uint32{1, 2, 1}, 2))
+}
+
+func TestRange(t *testing.T) {
+	require.Equal(t, []uint32{1, 2, 3}, Range(3))
+	require.Empty(t, Range(0))
+	require.NoError(t, Validate(Range(7), 7))
+}
+
+func TestProofSessionId(t *testing.T) {
+	sessionId := []byte("session")
+	id := ProofSessionId(sessionId, 1, "Q")
+	require.Len(t, id, 32)
+	require.Equal(t, id, ProofSessionId(sessionId, 1, "Q"))
+	require.NotEqual(t, id, ProofSessionId([]byte("other session"), 1, "Q"))
+	require.NotEqual(t, id, ProofSessionId(sessionId, 2, "Q"))
+	require.NotEqual(t, id, ProofSessionId(sessionId, 1, "T"))
+}
+
+func TestSubSessionId(t *testing.T) {
+	sessionId := []byte("session")
+	contributions := map[uint32][]byte{1: []byte("a"), 2: []byte("b")}
+	id := SubSessionId(sessionId, "sign", contributions)
+	require.Len(t, id, 32)
+	require.Equal(t, id, SubSessionId(sessionId, "sign", map[uint32][]byte{2: []byte("b"), 1: []byte("a")}))
+	require.NotEqual(t, id, SubSessionId([]byte("other session"), "sign", contributions))
+	require.NotEqual(t, id, SubSessionId(sessionId, "refresh", contributions))
+	require.NotEqual(t, id, SubSessionId(sessionId, "sign", map[uint32][]byte{1: []byte("a"), 2: []byte("c")}))
+	require.NotEqual(t, id, SubSessionId(sessionId, "sign", map[uint32][]byte{1: []byte("ab"), 2: nil}))
+}
@@ -1,10 +1,12 @@
 package chaumpedersen
 
 import (
-	"crypto/rand"
 	"crypto/subtle"
 	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/zkp/internal/codec"
+	"github.com/coinbase/kryptology/pkg/zkp/sigma"
+	"github.com/gtank/merlin"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/sha3"
 )
@@ -12,18 +14,27 @@ import (
 type Commitment = []byte
 type SessionId = []byte
 
+// protocolLabel separates the chaum-pedersen proofs from the other proofs in a transcript
+const protocolLabel = "Coinbase_ZKP_ChaumPedersen"
+
 type Prover struct {
 	curve           *curves.Curve
 	basePoint1      curves.Point
 	basePoint2      curves.Point
 	uniqueSessionId []byte
+	transcript      *merlin.Transcript
 }
 
+// Proof is the (c, s) proof that Statement1 and Statement2 have the same discrete log with respect to the base points.
+// K1 and K2 are the commitments of the prover: Verify recomputes them, but BatchVerify uses them when they are set, so
+// the encodings carry them.
 type Proof struct {
 	C          curves.Scalar
 	S          curves.Scalar
 	Statement1 curves.Point
 	Statement2 curves.Point
+	K1         curves.Point
+	K2         curves.Point
 }
 
 // we allow basePoint1 to be nil, in which case it is auto-assigned to be the group's default generator
@@ -44,93 +55,38 @@ func NewProver(curve *curves.Curve, basePoint1 curves.Point, basePoint2 curves.P
 	}, nil
 }
 
-func (p *Prover) Prove(x curves.Scalar) (*Proof, error) {
-	var err error
-	result := &Proof{}
-	result.Statement1 = p.basePoint1.Mul(x)
-	result.Statement2 = p.basePoint2.Mul(x)
-
-	// commit
-	k := p.curve.Scalar.Random(rand.Reader)
-	K1 := p.basePoint1.Mul(k)
-	K2 := p.basePoint2.Mul(k)
-
-	// challenge
-	hash := sha3.New256()
-	if _, err = hash.Write(p.uniqueSessionId); err != nil {
-		return nil, errors.Wrap(err, "writing salt to hash in chaum-pedersen proof")
-	}
-	if _, err = hash.Write(p.basePoint1.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing basePoint1 to hash in chaum-pedersen proof")
-	}
-	if _, err = hash.Write(p.basePoint2.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing basePoint2 to hash in chaum-pedersen proof")
-	}
-	if _, err = hash.Write(result.Statement1.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing statement1 to hash in chaum-pedersen proof")
-	}
-	if _, err = hash.Write(result.Statement2.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing statement2 to hash in chaum-pedersen proof")
-	}
-	if _, err = hash.Write(K1.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing point K1 to hash in chaum-pedersen proof")
-	}
-	if _, err = hash.Write(K2.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing point K2 to hash in chaum-pedersen proof")
-	}
-	result.C, err = p.curve.Scalar.SetBytes(hash.Sum(nil))
+// NewProverWithTranscript is NewProver with the challenges drawn from the transcript, which advances with each proof,
+// so the verifier must check the proofs in the same order with VerifyWithTranscript
+func NewProverWithTranscript(curve *curves.Curve, basePoint1 curves.Point, basePoint2 curves.Point, transcript *merlin.Transcript) (*Prover, error) {
+	prover, err := NewProver(curve, basePoint1, basePoint2, nil)
 	if err != nil {
-		return nil, errors.Wrap(err, "generating challenge in chaum-pedersen proof")
+		return nil, err
 	}
+	prover.transcript = transcript
+	return prover, nil
+}
 
-	// response
-	result.S = result.C.Mul(x).Add(k)
-
-	return result, nil
+func (p *Prover) Prove(x curves.Scalar) (*Proof, error) {
+	return p.ProveWithStatement(p.basePoint1.Mul(x), p.basePoint2.Mul(x), x)
 }
 
 func (p *Prover) ProveWithStatement(statement1 curves.Point, statement2 curves.Point, x curves.Scalar) (*Proof, error) {
-	var err error
 	result := &Proof{}
 	result.Statement1 = statement1
 	result.Statement2 = statement2
-
-	// commit
-	k := p.curve.Scalar.Random(rand.Reader)
-	K1 := p.basePoint1.Mul(k)
-	K2 := p.basePoint2.Mul(k)
-
-	// challenge
-	hash := sha3.New256()
-	if _, err = hash.Write(p.uniqueSessionId); err != nil {
-		return nil, errors.Wrap(err, "writing salt to hash in chaum-pedersen proof")
-	}
-	if _, err = hash.Write(p.basePoint1.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing basePoint1 to hash in chaum-pedersen proof")
-	}
-	if _, err = hash.Write(p.basePoint2.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing basePoint2 to hash in chaum-pedersen proof")
-	}
-	if _, err = hash.Write(result.Statement1.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing statement1 to hash in chaum-pedersen proof")
-	}
-	if _, err = hash.Write(result.Statement2.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing statement2 to hash in chaum-pedersen proof")
-	}
-	if _, err = hash.Write(K1.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing point K1 to hash in chaum-pedersen proof")
-	}
-	if _, err = hash.Write(K2.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing point K2 to hash in chaum-pedersen proof")
+	relation := statement(p.basePoint1, p.basePoint2, statement1, statement2)
+	var proof *sigma.Proof
+	var err error
+	if p.transcript != nil {
+		proof, err = sigma.ProveTranscript(p.curve, protocolLabel, relation, []curves.Scalar{x}, p.transcript)
+	} else {
+		proof, err = sigma.Prove(p.curve, relation, []curves.Scalar{x}, p.uniqueSessionId)
 	}
-	result.C, err = p.curve.Scalar.SetBytes(hash.Sum(nil))
 	if err != nil {
-		return nil, errors.Wrap(err, "generating challenge in chaum-pedersen proof")
+		return nil, errors.Wrap(err, "chaum-pedersen proof")
 	}
-
-	// response
-	result.S = result.C.Mul(x).Add(k)
-
+	result.C, result.S = proof.C, proof.Responses[0]
+	result.K1, result.K2 = proof.Commitments[0], proof.Commitments[1]
 	return result, nil
 }
 
@@ -138,45 +94,75 @@ func (p *Prover) ProveWithStatement(statement1 curves.Point, statement2 curves.P
 // we do NOT allow basePoint2 to be nil
 
 func Verify(proof *Proof, curve *curves.Curve, basePoint1 curves.Point, basePoint2 curves.Point, uniqueSessionId []byte) error {
+	return verify(proof, curve, basePoint1, basePoint2, func(relation *sigma.Relation, proof *sigma.Proof) error {
+		return sigma.Verify(curve, relation, proof, uniqueSessionId)
+	})
+}
+
+// BatchVerify verifies proofs[i] for uniqueSessionIds[i], all with respect to the same base points, with a single
+// multi-scalar multiplication for the proofs that carry K1 and K2. If the batch fails, it verifies the proofs one at a
+// time and reports the index of the first invalid one.
+func BatchVerify(proofs []*Proof, curve *curves.Curve, basePoint1 curves.Point, basePoint2 curves.Point, uniqueSessionIds [][]byte) error {
 	if basePoint1 == nil {
 		basePoint1 = curve.NewGeneratorPoint()
 	}
 	if basePoint2 == nil {
 		return fmt.Errorf("base point 2 missing")
 	}
-	var err error
+	statements := make([]*sigma.Relation, len(proofs))
+	sigmaProofs := make([]*sigma.Proof, len(proofs))
+	for i, proof := range proofs {
+		if proof == nil || proof.Statement1 == nil || proof.Statement2 == nil {
+			return fmt.Errorf("chaum-pedersen proof %d missing", i)
+		}
+		statements[i] = statement(basePoint1, basePoint2, proof.Statement1, proof.Statement2)
+		sigmaProofs[i] = &sigma.Proof{C: proof.C, Responses: []curves.Scalar{proof.S}}
+		if proof.K1 != nil && proof.K2 != nil {
+			sigmaProofs[i].Commitments = []curves.Point{proof.K1, proof.K2}
+		}
+	}
+	if err := sigma.BatchVerify(curve, statements, sigmaProofs, uniqueSessionIds); err != nil {
+		return errors.Wrap(err, "chaum-pedersen batch verification failed")
+	}
+	return nil
+}
 
-	K1 := basePoint1.Mul(proof.S).Add(proof.Statement1.Mul(proof.C.Neg()))
-	K2 := basePoint2.Mul(proof.S).Add(proof.Statement2.Mul(proof.C.Neg()))
+// VerifyWithTranscript verifies a proof of a prover created with NewProverWithTranscript, advancing the transcript in
+// the same way
+func VerifyWithTranscript(proof *Proof, curve *curves.Curve, basePoint1 curves.Point, basePoint2 curves.Point, transcript *merlin.Transcript) error {
+	return verify(proof, curve, basePoint1, basePoint2, func(relation *sigma.Relation, proof *sigma.Proof) error {
+		return sigma.VerifyTranscript(curve, protocolLabel, relation, proof, transcript)
+	})
+}
 
-	hash := sha3.New256()
-	if _, err = hash.Write(uniqueSessionId); err != nil {
-		return errors.Wrap(err, "writing salt to hash in chaum-pedersen verification")
-	}
-	if _, err = hash.Write(basePoint1.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing basePoint1 to hash in chaum-pedersen verification")
-	}
-	if _, err = hash.Write(basePoint2.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing basePoint2 to hash in chaum-pedersen verification")
-	}
-	if _, err = hash.Write(proof.Statement1.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing statement1 to hash in chaum-pedersen verification")
-	}
-	if _, err = hash.Write(proof.Statement2.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing statement2 to hash in chaum-pedersen verification")
+func verify(proof *Proof, curve *curves.Curve, basePoint1 curves.Point, basePoint2 curves.Point, check func(*sigma.Relation, *sigma.Proof) error) error {
+	if basePoint1 == nil {
+		basePoint1 = curve.NewGeneratorPoint()
 	}
-	if _, err = hash.Write(K1.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing point K1 to hash in chaum-pedersen verification")
+	if basePoint2 == nil {
+		return fmt.Errorf("base point 2 missing")
 	}
-	if _, err = hash.Write(K2.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing point K2 to hash in chaum-pedersen verification")
+	if proof == nil || proof.Statement1 == nil || proof.Statement2 == nil {
+		return fmt.Errorf("chaum-pedersen proof missing")
 	}
-	if subtle.ConstantTimeCompare(proof.C.Bytes(), hash.Sum(nil)) != 1 {
-		return fmt.Errorf("chaum-pedersen verification failed")
+	err := check(statement(basePoint1, basePoint2, proof.Statement1, proof.Statement2), &sigma.Proof{C: proof.C, Responses: []curves.Scalar{proof.S}})
+	if err != nil {
+		return errors.Wrap(err, "chaum-pedersen verification failed")
 	}
 	return nil
 }
 
+// statement is the equality of the discrete logs of statement1 and statement2 with respect to their base points
+func statement(basePoint1 curves.Point, basePoint2 curves.Point, statement1 curves.Point, statement2 curves.Point) *sigma.Relation {
+	return &sigma.Relation{
+		Equations: []*sigma.Equation{
+			{Image: statement1, Terms: []sigma.Term{{Base: basePoint1, Witness: 0}}},
+			{Image: statement2, Terms: []sigma.Term{{Base: basePoint2, Witness: 0}}},
+		},
+		Labels: []string{"base point 1", "base point 2", "statement 1", "statement 2"},
+	}
+}
+
 func (p *Prover) ComProve(x curves.Scalar) (*Proof, Commitment, error) {
 	proof, err := p.Prove(x)
 	if err != nil {
@@ -224,6 +210,39 @@ func (p *Prover) ComProveWithStatement(statement1 curves.Point, statement2 curve
 }
 
 func DeComVerify(proof *Proof, commitment Commitment, curve *curves.Curve, basePoint1 curves.Point, basePoint2 curves.Point, uniqueSessionId []byte) error {
+	if err := decommit(proof, commitment); err != nil {
+		return err
+	}
+	return Verify(proof, curve, basePoint1, basePoint2, uniqueSessionId)
+}
+
+// DeComBatchVerify is DeComVerify for many proofs: it checks that proofs[i] opens commitments[i] and then verifies the
+// proofs with BatchVerify
+func DeComBatchVerify(proofs []*Proof, commitments []Commitment, curve *curves.Curve, basePoint1 curves.Point, basePoint2 curves.Point, uniqueSessionIds [][]byte) error {
+	if len(commitments) != len(proofs) {
+		return fmt.Errorf("expected %d commitments, got %d", len(proofs), len(commitments))
+	}
+	for i, proof := range proofs {
+		if proof == nil || proof.C == nil || proof.S == nil || proof.Statement1 == nil || proof.Statement2 == nil {
+			return fmt.Errorf("chaum-pedersen proof %d missing", i)
+		}
+		if err := decommit(proof, commitments[i]); err != nil {
+			return errors.Wrapf(err, "proof %d", i)
+		}
+	}
+	return BatchVerify(proofs, curve, basePoint1, basePoint2, uniqueSessionIds)
+}
+
+// DeComVerifyWithTranscript is DeComVerify for a prover created with NewProverWithTranscript
+func DeComVerifyWithTranscript(proof *Proof, commitment Commitment, curve *curves.Curve, basePoint1 curves.Point, basePoint2 curves.Point, transcript *merlin.Transcript) error {
+	if err := decommit(proof, commitment); err != nil {
+		return err
+	}
+	return VerifyWithTranscript(proof, curve, basePoint1, basePoint2, transcript)
+}
+
+// decommit checks that the proof opens the commitment
+func decommit(proof *Proof, commitment Commitment) error {
 	hash := sha3.New256()
 	if _, err := hash.Write(proof.C.Bytes()); err != nil {
 		return err
@@ -240,5 +259,45 @@ func DeComVerify(proof *Proof, commitment Commitment, curve *curves.Curve, baseP
 	if subtle.ConstantTimeCompare(hash.Sum(nil), commitment) != 1 {
 		return fmt.Errorf("initial hash decommitment failed")
 	}
-	return Verify(proof, curve, basePoint1, basePoint2, uniqueSessionId)
+	return nil
+}
+
+// proofFields names the fields of a Proof in its encodings
+var proofFields = codec.Fields{
+	Scalars: []string{"c", "s"},
+	Points:  []string{"statement1", "statement2", "k1", "k2"},
+}
+
+// MarshalBinary encodes the proof as the curve name, a version byte, the scalars and the compressed points
+func (proof *Proof) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinary(proofFields, []curves.Scalar{proof.C, proof.S}, []curves.Point{proof.Statement1, proof.Statement2, proof.K1, proof.K2})
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary, rejecting non-canonical scalars and identity or invalid points
+func (proof *Proof) UnmarshalBinary(data []byte) error {
+	_, scalars, points, err := codec.UnmarshalBinary(proofFields, data)
+	if err != nil {
+		return err
+	}
+	proof.C, proof.S = scalars[0], scalars[1]
+	proof.Statement1, proof.Statement2 = points[0], points[1]
+	proof.K1, proof.K2 = points[2], points[3]
+	return nil
+}
+
+// MarshalJSON encodes the proof as a JSON object holding the version, the curve name and the hex encoded fields
+func (proof *Proof) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON(proofFields, []curves.Scalar{proof.C, proof.S}, []curves.Point{proof.Statement1, proof.Statement2, proof.K1, proof.K2})
+}
+
+// UnmarshalJSON decodes the output of MarshalJSON with the same checks as UnmarshalBinary
+func (proof *Proof) UnmarshalJSON(data []byte) error {
+	_, scalars, points, err := codec.UnmarshalJSON(proofFields, data)
+	if err != nil {
+		return err
+	}
+	proof.C, proof.S = scalars[0], scalars[1]
+	proof.Statement1, proof.Statement2 = points[0], points[1]
+	proof.K1, proof.K2 = points[2], points[3]
+	return nil
 }
@@ -2,10 +2,13 @@ package chaumpedersen
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/zkp/zkptest"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/sha3"
+	"strings"
 	"testing"
 )
 
@@ -48,3 +51,116 @@ func TestComZKPOverMultipleCurves(t *testing.T) {
 		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
 	}
 }
+
+func TestProofEncoding(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+		curves.SM2(),
+	}
+	for i, curve := range curveInstances {
+		uniqueSessionId := sha3.New256().Sum([]byte("random seed"))
+		u := curve.Point.Random(rand.Reader)
+		v := curve.Point.Random(rand.Reader)
+		prover, _ := NewProver(curve, u, v, uniqueSessionId)
+		proof, err := prover.Prove(curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+
+		data, err := proof.MarshalBinary()
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		require.True(t, strings.HasPrefix(string(data), curve.Name+":"))
+		decoded := &Proof{}
+		require.NoError(t, decoded.UnmarshalBinary(data), fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, Verify(decoded, curve, u, v, uniqueSessionId), fmt.Sprintf("failed in curve %d", i))
+		require.Error(t, decoded.UnmarshalBinary(data[:len(data)-1]))
+
+		data, err = json.Marshal(proof)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		decoded = &Proof{}
+		require.NoError(t, json.Unmarshal(data, decoded), fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, Verify(decoded, curve, u, v, uniqueSessionId), fmt.Sprintf("failed in curve %d", i))
+	}
+}
+
+func TestZKPWithTranscript(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+	}
+	for i, curve := range curveInstances {
+		u := curve.Point.Random(rand.Reader)
+		v := curve.Point.Random(rand.Reader)
+		prover, err := NewProverWithTranscript(curve, u, v, zkptest.NewTranscript("protocol"))
+		require.NoError(t, err)
+		proof, commitment, err := prover.ComProve(curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+
+		require.NoError(t, DeComVerifyWithTranscript(proof, commitment, curve, u, v, zkptest.NewTranscript("protocol")), fmt.Sprintf("failed in curve %d", i))
+		require.Error(t, VerifyWithTranscript(proof, curve, v, u, zkptest.NewTranscript("protocol")))
+		require.Error(t, VerifyWithTranscript(proof, curve, u, v, zkptest.NewTranscript("other protocol")))
+	}
+	_, err := NewProverWithTranscript(curves.K256(), nil, nil, zkptest.NewTranscript("protocol"))
+	require.Error(t, err)
+}
+
+func TestBatchVerify(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+	}
+	for i, curve := range curveInstances {
+		u := curve.Point.Random(rand.Reader)
+		v := curve.Point.Random(rand.Reader)
+		proofs := make([]*Proof, 20)
+		sessionIds := make([][]byte, len(proofs))
+		for j := range proofs {
+			sessionIds[j] = sha3.New256().Sum([]byte(fmt.Sprintf("random seed %d", j)))
+			prover, err := NewProver(curve, u, v, sessionIds[j])
+			require.NoError(t, err)
+			proofs[j], err = prover.Prove(curve.Scalar.Random(rand.Reader))
+			require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		}
+		require.NoError(t, BatchVerify(proofs, curve, u, v, sessionIds), fmt.Sprintf("failed in curve %d", i))
+		require.Error(t, BatchVerify(proofs, curve, v, u, sessionIds))
+		require.Error(t, BatchVerify(proofs, curve, u, nil, sessionIds))
+
+		// decoded proofs keep K1 and K2 and stay in the batch
+		data, err := proofs[3].MarshalJSON()
+		require.NoError(t, err)
+		decoded := &Proof{}
+		require.NoError(t, decoded.UnmarshalJSON(data))
+		require.True(t, decoded.K1.Equal(proofs[3].K1))
+		require.True(t, decoded.K2.Equal(proofs[3].K2))
+		proofs[3] = decoded
+		require.NoError(t, BatchVerify(proofs, curve, u, v, sessionIds), fmt.Sprintf("failed in curve %d", i))
+
+		// a statement whose discrete logs differ
+		proofs[5].Statement2 = proofs[5].Statement2.Add(v)
+		err = BatchVerify(proofs, curve, u, v, sessionIds)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "proof 5")
+	}
+}
+
+func TestDeComBatchVerify(t *testing.T) {
+	curve := curves.K256()
+	u := curve.Point.Random(rand.Reader)
+	v := curve.Point.Random(rand.Reader)
+	proofs := make([]*Proof, 10)
+	commitments := make([]Commitment, len(proofs))
+	sessionIds := make([][]byte, len(proofs))
+	for i := range proofs {
+		sessionIds[i] = sha3.New256().Sum([]byte(fmt.Sprintf("random seed %d", i)))
+		prover, err := NewProver(curve, u, v, sessionIds[i])
+		require.NoError(t, err)
+		proofs[i], commitments[i], err = prover.ComProve(curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err)
+	}
+	require.NoError(t, DeComBatchVerify(proofs, commitments, curve, u, v, sessionIds))
+	require.Error(t, DeComBatchVerify(proofs, commitments, curve, v, u, sessionIds))
+
+	commitments[6] = commitments[7]
+	err := DeComBatchVerify(proofs, commitments, curve, u, v, sessionIds)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "proof 6")
+}
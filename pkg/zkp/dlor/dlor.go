@@ -0,0 +1,140 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package dlor proves knowledge of the discrete log of one of several public keys without revealing which one, as the
+// OR composition of schnorr proofs of Cramer, Damgård and Schoenmakers
+// https://link.springer.com/content/pdf/10.1007/3-540-48658-5_19.pdf. A proof for n keys has 2n scalars.
+package dlor
+
+import (
+	"fmt"
+
+	"github.com/gtank/merlin"
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/zkp/sigma"
+)
+
+// protocolLabel separates the discrete log OR proofs from the other proofs in a transcript
+const protocolLabel = "Coinbase_ZKP_DiscreteLogOr"
+
+type Prover struct {
+	curve           *curves.Curve
+	basePoint       curves.Point
+	uniqueSessionId []byte
+	transcript      *merlin.Transcript
+}
+
+// Proof is the challenge C and the responses of the sigma protocol: the challenges of all the public keys but the last,
+// followed by one response for each public key
+type Proof struct {
+	C         curves.Scalar
+	Responses []curves.Scalar
+}
+
+// NewProver returns a prover whose proofs are bound to the session id. A nil basePoint is the generator of the curve.
+func NewProver(curve *curves.Curve, basePoint curves.Point, uniqueSessionId []byte) *Prover {
+	if basePoint == nil {
+		basePoint = curve.NewGeneratorPoint()
+	}
+	return &Prover{
+		curve:           curve,
+		basePoint:       basePoint,
+		uniqueSessionId: uniqueSessionId,
+	}
+}
+
+// NewProverWithTranscript is NewProver with the challenges drawn from the transcript, which advances with each proof,
+// so the verifier must check the proofs in the same order with VerifyWithTranscript
+func NewProverWithTranscript(curve *curves.Curve, basePoint curves.Point, transcript *merlin.Transcript) *Prover {
+	prover := NewProver(curve, basePoint, nil)
+	prover.transcript = transcript
+	return prover
+}
+
+// Prove proves knowledge of the discrete log of one of the public keys, given the secret x of publicKeys[index]
+func (p *Prover) Prove(publicKeys []curves.Point, index int, x curves.Scalar) (*Proof, error) {
+	if err := checkPublicKeys(publicKeys); err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(publicKeys) {
+		return nil, fmt.Errorf("index %d out of the %d public keys", index, len(publicKeys))
+	}
+	if x == nil || !p.basePoint.Mul(x).Equal(publicKeys[index]) {
+		return nil, fmt.Errorf("the secret is not the discrete log of public key %d", index)
+	}
+	witness := make([]curves.Scalar, len(publicKeys))
+	witness[index] = x
+
+	var proof *sigma.Proof
+	var err error
+	if p.transcript != nil {
+		proof, err = sigma.ProveTranscript(p.curve, protocolLabel, statement(p.basePoint, publicKeys), witness, p.transcript)
+	} else {
+		proof, err = sigma.Prove(p.curve, statement(p.basePoint, publicKeys), witness, p.uniqueSessionId)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "discrete log OR proof")
+	}
+	return &Proof{C: proof.C, Responses: proof.Responses}, nil
+}
+
+// Verify checks that the prover knows the discrete log of one of the public keys, bound to the session id
+func Verify(proof *Proof, curve *curves.Curve, basePoint curves.Point, publicKeys []curves.Point, uniqueSessionId []byte) error {
+	return verify(proof, curve, basePoint, publicKeys, func(statement sigma.Statement, proof *sigma.Proof) error {
+		return sigma.Verify(curve, statement, proof, uniqueSessionId)
+	})
+}
+
+// VerifyWithTranscript verifies a proof of a prover created with NewProverWithTranscript, advancing the transcript in
+// the same way
+func VerifyWithTranscript(proof *Proof, curve *curves.Curve, basePoint curves.Point, publicKeys []curves.Point, transcript *merlin.Transcript) error {
+	return verify(proof, curve, basePoint, publicKeys, func(statement sigma.Statement, proof *sigma.Proof) error {
+		return sigma.VerifyTranscript(curve, protocolLabel, statement, proof, transcript)
+	})
+}
+
+func verify(proof *Proof, curve *curves.Curve, basePoint curves.Point, publicKeys []curves.Point, check func(sigma.Statement, *sigma.Proof) error) error {
+	if basePoint == nil {
+		basePoint = curve.NewGeneratorPoint()
+	}
+	if err := checkPublicKeys(publicKeys); err != nil {
+		return err
+	}
+	if proof == nil || proof.C == nil || len(proof.Responses) != 2*len(publicKeys)-1 {
+		return fmt.Errorf("discrete log OR proof missing")
+	}
+	if err := check(statement(basePoint, publicKeys), &sigma.Proof{C: proof.C, Responses: proof.Responses}); err != nil {
+		return errors.Wrap(err, "discrete log OR verification failed")
+	}
+	return nil
+}
+
+// statement is the knowledge of the discrete log x_i (witness i) of one of the public keys P_i = x_i*basePoint
+func statement(basePoint curves.Point, publicKeys []curves.Point) sigma.Statement {
+	branches := make([]sigma.Statement, len(publicKeys))
+	for i, publicKey := range publicKeys {
+		branches[i] = &sigma.Relation{
+			Equations:  []*sigma.Equation{{Image: publicKey, Terms: []sigma.Term{{Base: basePoint, Witness: i}}}},
+			Transcript: []curves.Point{basePoint, publicKey},
+			Labels:     []string{"base point", "public key"},
+		}
+	}
+	return sigma.Or(branches...)
+}
+
+func checkPublicKeys(publicKeys []curves.Point) error {
+	if len(publicKeys) == 0 {
+		return fmt.Errorf("public keys missing")
+	}
+	for i, publicKey := range publicKeys {
+		if publicKey == nil {
+			return fmt.Errorf("public key %d missing", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package dlor
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/gtank/merlin"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+)
+
+func TestZKPOverMultipleCurves(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+	}
+	for i, curve := range curveInstances {
+		uniqueSessionId := sha3.New256().Sum([]byte("random seed"))
+		publicKeys, secrets := keys(curve, 5)
+		for index := range publicKeys {
+			proof, err := NewProver(curve, nil, uniqueSessionId).Prove(publicKeys, index, secrets[index])
+			require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+			require.NoError(t, Verify(proof, curve, nil, publicKeys, uniqueSessionId), fmt.Sprintf("failed in curve %d", i))
+
+			require.Error(t, Verify(proof, curve, nil, publicKeys, []byte("other session")))
+			require.Error(t, Verify(proof, curve, nil, publicKeys[1:], uniqueSessionId))
+			other, _ := keys(curve, 5)
+			require.Error(t, Verify(proof, curve, nil, other, uniqueSessionId))
+		}
+
+		// a single key is a schnorr proof
+		proof, err := NewProver(curve, nil, uniqueSessionId).Prove(publicKeys[:1], 0, secrets[0])
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, Verify(proof, curve, nil, publicKeys[:1], uniqueSessionId), fmt.Sprintf("failed in curve %d", i))
+	}
+}
+
+func TestProveWithoutSecret(t *testing.T) {
+	curve := curves.K256()
+	publicKeys, secrets := keys(curve, 3)
+	prover := NewProver(curve, nil, []byte("session"))
+	_, err := prover.Prove(publicKeys, 0, secrets[1])
+	require.Error(t, err)
+	_, err = prover.Prove(publicKeys, 3, secrets[1])
+	require.Error(t, err)
+	_, err = prover.Prove(nil, 0, secrets[1])
+	require.Error(t, err)
+	require.Error(t, Verify(nil, curve, nil, publicKeys, []byte("session")))
+	require.Error(t, Verify(&Proof{}, curve, nil, publicKeys, []byte("session")))
+}
+
+func TestZKPWithTranscript(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+		curves.ED25519(),
+	}
+	for i, curve := range curveInstances {
+		publicKeys, secrets := keys(curve, 4)
+		proof, err := NewProverWithTranscript(curve, nil, merlin.NewTranscript("protocol")).Prove(publicKeys, 2, secrets[2])
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, VerifyWithTranscript(proof, curve, nil, publicKeys, merlin.NewTranscript("protocol")), fmt.Sprintf("failed in curve %d", i))
+		require.Error(t, VerifyWithTranscript(proof, curve, nil, publicKeys, merlin.NewTranscript("other protocol")))
+	}
+}
+
+func keys(curve *curves.Curve, n int) ([]curves.Point, []curves.Scalar) {
+	publicKeys := make([]curves.Point, n)
+	secrets := make([]curves.Scalar, n)
+	for i := range secrets {
+		secrets[i] = curve.Scalar.Random(rand.Reader)
+		publicKeys[i] = curve.ScalarBaseMult(secrets[i])
+	}
+	return publicKeys, secrets
+}
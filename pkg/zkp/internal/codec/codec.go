@@ -0,0 +1,232 @@
+// Package codec implements the wire format shared by the proofs in pkg/zkp.
+//
+// The binary encoding follows core/curves: the curve name and a colon, then a version byte, the scalars in their
+// canonical form and the points in compressed form, each in the order given by Fields.
+// The JSON encoding is an object holding the version, the curve name and the hex encoding of every field.
+// Decoding is strict: the curve must be known, every scalar must be canonical and every point must be a canonical
+// encoding of a point on the curve other than the identity.
+package codec
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+)
+
+// Version is the current version of the encodings
+const Version = 1
+
+const (
+	versionKey = "version"
+	curveKey   = "curve"
+)
+
+// Fields names the scalars and the points of a proof in the order of their encoding
+type Fields struct {
+	Scalars []string
+	Points  []string
+}
+
+// MarshalBinary encodes the scalars and points, which must all belong to the same curve
+func MarshalBinary(fields Fields, scalars []curves.Scalar, points []curves.Point) ([]byte, error) {
+	curve, err := check(fields, scalars, points)
+	if err != nil {
+		return nil, err
+	}
+	output := append([]byte(curve.Name), ':', Version)
+	for _, s := range scalars {
+		output = append(output, s.Bytes()...)
+	}
+	for _, p := range points {
+		output = append(output, p.ToAffineCompressed()...)
+	}
+	return output, nil
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary and returns the curve along with the scalars and points
+func UnmarshalBinary(fields Fields, input []byte) (*curves.Curve, []curves.Scalar, []curves.Point, error) {
+	i := bytes.IndexByte(input, ':')
+	if i < 0 {
+		return nil, nil, nil, fmt.Errorf("missing curve name")
+	}
+	curve := curves.GetCurveByName(string(input[:i]))
+	if curve == nil {
+		return nil, nil, nil, fmt.Errorf("unrecognized curve")
+	}
+	input = input[i+1:]
+	if len(input) == 0 || input[0] != Version {
+		return nil, nil, nil, fmt.Errorf("unsupported version")
+	}
+	input = input[1:]
+
+	scalarLen, pointLen := sizes(curve)
+	if len(input) != len(fields.Scalars)*scalarLen+len(fields.Points)*pointLen {
+		return nil, nil, nil, fmt.Errorf("invalid length")
+	}
+	scalars := make([]curves.Scalar, len(fields.Scalars))
+	for j, name := range fields.Scalars {
+		s, err := decodeScalar(curve, input[:scalarLen])
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		scalars[j] = s
+		input = input[scalarLen:]
+	}
+	points := make([]curves.Point, len(fields.Points))
+	for j, name := range fields.Points {
+		p, err := decodePoint(curve, input[:pointLen])
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		points[j] = p
+		input = input[pointLen:]
+	}
+	return curve, scalars, points, nil
+}
+
+// MarshalJSON encodes the scalars and points, which must all belong to the same curve
+func MarshalJSON(fields Fields, scalars []curves.Scalar, points []curves.Point) ([]byte, error) {
+	curve, err := check(fields, scalars, points)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{}, 2+len(scalars)+len(points))
+	m[versionKey] = Version
+	m[curveKey] = curve.Name
+	for j, name := range fields.Scalars {
+		m[name] = hex.EncodeToString(scalars[j].Bytes())
+	}
+	for j, name := range fields.Points {
+		m[name] = hex.EncodeToString(points[j].ToAffineCompressed())
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON decodes the output of MarshalJSON and returns the curve along with the scalars and points.
+// Unknown and missing fields are rejected.
+func UnmarshalJSON(fields Fields, input []byte) (*curves.Curve, []curves.Scalar, []curves.Point, error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(input, &m); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(m) != 2+len(fields.Scalars)+len(fields.Points) {
+		return nil, nil, nil, fmt.Errorf("expected %d fields, got %d", 2+len(fields.Scalars)+len(fields.Points), len(m))
+	}
+	var version int
+	if err := json.Unmarshal(m[versionKey], &version); err != nil || version != Version {
+		return nil, nil, nil, fmt.Errorf("unsupported version")
+	}
+	var name string
+	if err := json.Unmarshal(m[curveKey], &name); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid curve name")
+	}
+	curve := curves.GetCurveByName(name)
+	if curve == nil {
+		return nil, nil, nil, fmt.Errorf("unrecognized curve")
+	}
+
+	scalarLen, pointLen := sizes(curve)
+	scalars := make([]curves.Scalar, len(fields.Scalars))
+	for j, name := range fields.Scalars {
+		data, err := decodeHex(m[name], scalarLen)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if scalars[j], err = decodeScalar(curve, data); err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	points := make([]curves.Point, len(fields.Points))
+	for j, name := range fields.Points {
+		data, err := decodeHex(m[name], pointLen)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if points[j], err = decodePoint(curve, data); err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return curve, scalars, points, nil
+}
+
+// check returns the curve of the points after making sure that nothing is missing or from another curve
+func check(fields Fields, scalars []curves.Scalar, points []curves.Point) (*curves.Curve, error) {
+	if len(scalars) != len(fields.Scalars) || len(points) != len(fields.Points) || len(points) == 0 {
+		return nil, fmt.Errorf("incorrect number of fields")
+	}
+	for j, p := range points {
+		if p == nil {
+			return nil, fmt.Errorf("missing %s", fields.Points[j])
+		}
+	}
+	curve := curves.GetCurveByName(points[0].CurveName())
+	if curve == nil {
+		return nil, fmt.Errorf("unrecognized curve")
+	}
+	scalarLen, _ := sizes(curve)
+	for j, p := range points {
+		if p.CurveName() != curve.Name {
+			return nil, fmt.Errorf("%s is not on %s", fields.Points[j], curve.Name)
+		}
+	}
+	for j, s := range scalars {
+		if s == nil {
+			return nil, fmt.Errorf("missing %s", fields.Scalars[j])
+		}
+		if len(s.Bytes()) != scalarLen {
+			return nil, fmt.Errorf("%s is not a scalar of %s", fields.Scalars[j], curve.Name)
+		}
+	}
+	return curve, nil
+}
+
+// sizes returns the lengths of the encodings of a scalar and a compressed point of curve
+func sizes(curve *curves.Curve) (int, int) {
+	return len(curve.Scalar.Zero().Bytes()), len(curve.Point.Generator().ToAffineCompressed())
+}
+
+func decodeHex(raw json.RawMessage, size int) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("missing or not a string")
+	}
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != size {
+		return nil, fmt.Errorf("invalid length")
+	}
+	return data, nil
+}
+
+func decodeScalar(curve *curves.Curve, data []byte) (curves.Scalar, error) {
+	s, err := curve.Scalar.SetBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(s.Bytes(), data) {
+		return nil, fmt.Errorf("non-canonical scalar")
+	}
+	return s, nil
+}
+
+func decodePoint(curve *curves.Curve, data []byte) (curves.Point, error) {
+	p, err := curve.Point.FromAffineCompressed(data)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsOnCurve() {
+		return nil, fmt.Errorf("point not on the curve")
+	}
+	if p.IsIdentity() {
+		return nil, fmt.Errorf("identity point")
+	}
+	if !bytes.Equal(p.ToAffineCompressed(), data) {
+		return nil, fmt.Errorf("non-canonical point")
+	}
+	return p, nil
+}
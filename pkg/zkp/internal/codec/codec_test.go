@@ -0,0 +1,119 @@
+package codec
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+)
+
+var testFields = Fields{Scalars: []string{"s"}, Points: []string{"p", "q"}}
+
+func TestRoundTrip(t *testing.T) {
+	for _, curve := range []*curves.Curve{curves.K256(), curves.P256(), curves.SM2(), curves.ED25519(), curves.BLS12381G2()} {
+		s := curve.Scalar.Random(rand.Reader)
+		p := curve.Point.Random(rand.Reader)
+		q := curve.Point.Random(rand.Reader)
+
+		data, err := MarshalBinary(testFields, []curves.Scalar{s}, []curves.Point{p, q})
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(data, []byte(curve.Name+":")))
+		decodedCurve, scalars, points, err := UnmarshalBinary(testFields, data)
+		require.NoError(t, err, curve.Name)
+		require.Equal(t, curve.Name, decodedCurve.Name)
+		require.Zero(t, s.Cmp(scalars[0]))
+		require.True(t, p.Equal(points[0]))
+		require.True(t, q.Equal(points[1]))
+
+		data, err = MarshalJSON(testFields, []curves.Scalar{s}, []curves.Point{p, q})
+		require.NoError(t, err)
+		decodedCurve, scalars, points, err = UnmarshalJSON(testFields, data)
+		require.NoError(t, err, curve.Name)
+		require.Equal(t, curve.Name, decodedCurve.Name)
+		require.Zero(t, s.Cmp(scalars[0]))
+		require.True(t, p.Equal(points[0]))
+		require.True(t, q.Equal(points[1]))
+	}
+}
+
+func TestMarshalRejects(t *testing.T) {
+	curve := curves.K256()
+	s := curve.Scalar.Random(rand.Reader)
+	p := curve.Point.Random(rand.Reader)
+
+	_, err := MarshalBinary(testFields, []curves.Scalar{s}, []curves.Point{p})
+	require.Error(t, err)
+	_, err = MarshalBinary(testFields, []curves.Scalar{nil}, []curves.Point{p, p})
+	require.Error(t, err)
+	_, err = MarshalBinary(testFields, []curves.Scalar{s}, []curves.Point{p, nil})
+	require.Error(t, err)
+	_, err = MarshalJSON(testFields, []curves.Scalar{s}, []curves.Point{p, curves.P256().Point.Generator()})
+	require.Error(t, err)
+}
+
+func TestUnmarshalBinaryRejects(t *testing.T) {
+	curve := curves.K256()
+	s := curve.Scalar.Random(rand.Reader)
+	p := curve.Point.Random(rand.Reader)
+	data, err := MarshalBinary(testFields, []curves.Scalar{s}, []curves.Point{p, p})
+	require.NoError(t, err)
+	prefix := len(curve.Name) + 2
+	scalarLen, pointLen := sizes(curve)
+
+	tamper := func(f func([]byte) []byte) []byte {
+		return f(append([]byte(nil), data...))
+	}
+	for name, input := range map[string][]byte{
+		"no curve":      data[len(curve.Name)+1:],
+		"unknown curve": append([]byte("K257"), data[len(curve.Name):]...),
+		"version":       tamper(func(b []byte) []byte { b[prefix-1] = Version + 1; return b }),
+		"truncated":     data[:len(data)-1],
+		"extended":      append(append([]byte(nil), data...), 0),
+		"scalar":        tamper(func(b []byte) []byte { copy(b[prefix:], bytes.Repeat([]byte{0xff}, scalarLen)); return b }),
+		"identity": tamper(func(b []byte) []byte {
+			copy(b[prefix+scalarLen:], curve.Point.Identity().ToAffineCompressed())
+			return b
+		}),
+		"off curve": tamper(func(b []byte) []byte { b[prefix+scalarLen+pointLen-1] ^= 1; b[prefix+scalarLen] = 5; return b }),
+	} {
+		_, _, _, err := UnmarshalBinary(testFields, input)
+		require.Error(t, err, name)
+	}
+}
+
+func TestUnmarshalJSONRejects(t *testing.T) {
+	curve := curves.SM2()
+	s := curve.Scalar.Random(rand.Reader)
+	p := curve.Point.Random(rand.Reader)
+	data, err := MarshalJSON(testFields, []curves.Scalar{s}, []curves.Point{p, p})
+	require.NoError(t, err)
+
+	tamper := func(f func(map[string]interface{})) []byte {
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &m))
+		f(m)
+		output, err := json.Marshal(m)
+		require.NoError(t, err)
+		return output
+	}
+	for name, input := range map[string][]byte{
+		"not an object": []byte(`"proof"`),
+		"version":       tamper(func(m map[string]interface{}) { m[versionKey] = Version + 1 }),
+		"curve":         tamper(func(m map[string]interface{}) { m[curveKey] = "K257" }),
+		"missing":       tamper(func(m map[string]interface{}) { delete(m, "q") }),
+		"unknown":       tamper(func(m map[string]interface{}) { m["r"] = m["q"] }),
+		"renamed":       tamper(func(m map[string]interface{}) { m["r"] = m["q"]; delete(m, "q") }),
+		"not hex":       tamper(func(m map[string]interface{}) { m["s"] = "zz" }),
+		"short":         tamper(func(m map[string]interface{}) { m["s"] = m["s"].(string)[2:] }),
+		"scalar":        tamper(func(m map[string]interface{}) { m["s"] = string(bytes.Repeat([]byte("f"), 64)) }),
+		"identity":      tamper(func(m map[string]interface{}) { m["p"] = string(bytes.Repeat([]byte("0"), 66)) }),
+		"not a string":  tamper(func(m map[string]interface{}) { m["p"] = 1 }),
+	} {
+		_, _, _, err := UnmarshalJSON(testFields, input)
+		require.Error(t, err, name)
+	}
+}
@@ -1,20 +1,25 @@
 package reg
 
 import (
-	"crypto/rand"
 	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/zkp/internal/codec"
+	"github.com/coinbase/kryptology/pkg/zkp/sigma"
+	"github.com/gtank/merlin"
 	"github.com/pkg/errors"
-	"golang.org/x/crypto/sha3"
 )
 
 type SessionId = []byte
 
+// protocolLabel separates the elgamal encryption relation proofs from the other proofs in a transcript
+const protocolLabel = "Coinbase_ZKP_ElGamalEncryption"
+
 type Prover struct {
 	curve           *curves.Curve
 	basePoint       curves.Point
 	ek              curves.Point
 	uniqueSessionId []byte
+	transcript      *merlin.Transcript
 }
 
 type Proof struct {
@@ -41,99 +46,126 @@ func NewProver(curve *curves.Curve, basePoint curves.Point, ek curves.Point, uni
 	}, nil
 }
 
+// NewProverWithTranscript is NewProver with the challenges drawn from the transcript, which advances with each proof,
+// so the verifier must check the proofs in the same order with VerifyWithTranscript
+func NewProverWithTranscript(curve *curves.Curve, basePoint curves.Point, ek curves.Point, transcript *merlin.Transcript) (*Prover, error) {
+	prover, err := NewProver(curve, basePoint, ek, nil)
+	if err != nil {
+		return nil, err
+	}
+	prover.transcript = transcript
+	return prover, nil
+}
+
 func (p *Prover) Prove(m curves.Scalar, r curves.Scalar) (*Proof, error) {
-	var err error
 	result := &Proof{}
 
 	// compute statement
 	result.A = p.basePoint.Mul(r)
 	result.B = p.ek.Mul(r).Add(p.basePoint.Mul(m))
 
-	// compute commitment
-	alpha := p.curve.Scalar.Random(rand.Reader)
-	beta := p.curve.Scalar.Random(rand.Reader)
-	Y1 := p.basePoint.Mul(beta)
-	Y2 := p.ek.Mul(beta).Add(p.basePoint.Mul(alpha))
-
-	// compute challenge
-	hash := sha3.New256()
-	if _, err = hash.Write(p.uniqueSessionId); err != nil {
-		return nil, errors.Wrap(err, "writing salt to hash in elgamal encryption relation proof")
-	}
-	if _, err = hash.Write(p.basePoint.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing base point to hash in elgamal encryption relation proof")
-	}
-	if _, err = hash.Write(p.ek.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing encryption key to hash in elgamal encryption relation proof")
-	}
-	if _, err = hash.Write(result.A.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing A (first part of statement) to hash in elgamal encryption relation proof")
-	}
-	if _, err = hash.Write(result.B.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing B (second part of statement) to hash in elgamal encryption relation proof")
-	}
-	if _, err = hash.Write(Y1.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing point Y1 to hash in elgamal encryption relation proof")
-	}
-	if _, err = hash.Write(Y2.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing point Y2 to hash in elgamal encryption relation proof")
+	relation := statement(p.basePoint, p.ek, result.A, result.B)
+	var proof *sigma.Proof
+	var err error
+	if p.transcript != nil {
+		proof, err = sigma.ProveTranscript(p.curve, protocolLabel, relation, []curves.Scalar{r, m}, p.transcript)
+	} else {
+		proof, err = sigma.Prove(p.curve, relation, []curves.Scalar{r, m}, p.uniqueSessionId)
 	}
-	result.e, err = p.curve.Scalar.SetBytes(hash.Sum(nil))
 	if err != nil {
-		return nil, errors.Wrap(err, "generating challenge in elgamal encryption relation proof")
+		return nil, errors.Wrap(err, "elgamal encryption relation proof")
 	}
-
-	// compute response
-	result.z1 = beta.Add(result.e.Mul(r))
-	result.z2 = alpha.Add(result.e.Mul(m))
-
+	result.e, result.z1, result.z2 = proof.C, proof.Responses[0], proof.Responses[1]
 	return result, nil
 }
 
 func Verify(proof *Proof, curve *curves.Curve, basePoint curves.Point, ek curves.Point, uniqueSessionId []byte) error {
+	return verify(proof, curve, basePoint, ek, func(relation *sigma.Relation, proof *sigma.Proof) error {
+		return sigma.Verify(curve, relation, proof, uniqueSessionId)
+	})
+}
+
+// VerifyWithTranscript verifies a proof of a prover created with NewProverWithTranscript, advancing the transcript in
+// the same way
+func VerifyWithTranscript(proof *Proof, curve *curves.Curve, basePoint curves.Point, ek curves.Point, transcript *merlin.Transcript) error {
+	return verify(proof, curve, basePoint, ek, func(relation *sigma.Relation, proof *sigma.Proof) error {
+		return sigma.VerifyTranscript(curve, protocolLabel, relation, proof, transcript)
+	})
+}
+
+func verify(proof *Proof, curve *curves.Curve, basePoint curves.Point, ek curves.Point, check func(*sigma.Relation, *sigma.Proof) error) error {
 	if basePoint == nil {
 		basePoint = curve.NewGeneratorPoint()
 	}
 	if ek == nil {
 		return fmt.Errorf("encryption key missing")
 	}
-	var err error
-
-	// compute commitment
-	Y1 := basePoint.Mul(proof.z1).Sub(proof.A.Mul(proof.e))
-	Y2 := ek.Mul(proof.z1).Add(basePoint.Mul(proof.z2)).Sub(proof.B.Mul(proof.e))
-
-	// compute challenge
-	hash := sha3.New256()
-	if _, err = hash.Write(uniqueSessionId); err != nil {
-		return errors.Wrap(err, "writing salt to hash in elgamal encryption relation verification")
-	}
-	if _, err = hash.Write(basePoint.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing base point to hash in elgamal encryption relation verification")
-	}
-	if _, err = hash.Write(ek.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing encryption key to hash in elgamal encryption relation verification")
-	}
-	if _, err = hash.Write(proof.A.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing A (first part of statement) to hash in elgamal encryption relation verification")
+	if proof == nil || proof.A == nil || proof.B == nil {
+		return fmt.Errorf("elgamal encryption relation proof missing")
 	}
-	if _, err = hash.Write(proof.B.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing B (second part of statement) to hash in elgamal encryption relation verification")
-	}
-	if _, err = hash.Write(Y1.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing point Y1 to hash in elgamal encryption relation verification")
+	err := check(statement(basePoint, ek, proof.A, proof.B), &sigma.Proof{C: proof.e, Responses: []curves.Scalar{proof.z1, proof.z2}})
+	if err != nil {
+		return errors.Wrap(err, "elgamal encryption relation verification failed")
 	}
-	if _, err = hash.Write(Y2.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing point Y2 to hash in elgamal encryption relation verification")
+	return nil
+}
+
+// statement is the knowledge of the randomness r (witness 0) and the message m (witness 1) of the elgamal encryption
+// (A, B) = (r*basePoint, r*ek + m*basePoint)
+func statement(basePoint curves.Point, ek curves.Point, A curves.Point, B curves.Point) *sigma.Relation {
+	return &sigma.Relation{
+		Equations: []*sigma.Equation{
+			{Image: A, Terms: []sigma.Term{{Base: basePoint, Witness: 0}}},
+			{Image: B, Terms: []sigma.Term{{Base: ek, Witness: 0}, {Base: basePoint, Witness: 1}}},
+		},
+		Labels: []string{"base point", "encryption key", "A", "B"},
 	}
-	e, err := curve.Scalar.SetBytes(hash.Sum(nil))
+}
+
+// proofFields names the fields of a Proof in its encodings
+var proofFields = codec.Fields{
+	Scalars: []string{"e", "z1", "z2"},
+	Points:  []string{"a", "b"},
+}
+
+// MarshalBinary encodes the proof as the curve name, a version byte, the scalars and the compressed points
+func (proof *Proof) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinary(proofFields, []curves.Scalar{proof.e, proof.z1, proof.z2}, []curves.Point{proof.A, proof.B})
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary, rejecting non-canonical scalars and identity or invalid points
+func (proof *Proof) UnmarshalBinary(data []byte) error {
+	_, scalars, points, err := codec.UnmarshalBinary(proofFields, data)
 	if err != nil {
-		return errors.Wrap(err, "generating challenge in elgamal encryption relation verification")
+		return err
 	}
+	proof.e, proof.z1, proof.z2 = scalars[0], scalars[1], scalars[2]
+	proof.A, proof.B = points[0], points[1]
+	return nil
+}
+
+// MarshalJSON encodes the proof as a JSON object holding the version, the curve name and the hex encoded fields
+func (proof *Proof) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON(proofFields, []curves.Scalar{proof.e, proof.z1, proof.z2}, []curves.Point{proof.A, proof.B})
+}
 
-	// compare challenge
-	if e.Cmp(proof.e) != 0 {
-		return fmt.Errorf("elgamal encryption relation verification failed")
+// UnmarshalJSON decodes the output of MarshalJSON with the same checks as UnmarshalBinary
+func (proof *Proof) UnmarshalJSON(data []byte) error {
+	_, scalars, points, err := codec.UnmarshalJSON(proofFields, data)
+	if err != nil {
+		return err
 	}
+	proof.e, proof.z1, proof.z2 = scalars[0], scalars[1], scalars[2]
+	proof.A, proof.B = points[0], points[1]
 	return nil
 }
+
+// GobEncode lets the proof travel inside gob encoded protocol messages, using the encoding of MarshalBinary
+func (proof *Proof) GobEncode() ([]byte, error) {
+	return proof.MarshalBinary()
+}
+
+// GobDecode is the inverse of GobEncode
+func (proof *Proof) GobDecode(data []byte) error {
+	return proof.UnmarshalBinary(data)
+}
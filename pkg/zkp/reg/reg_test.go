@@ -1,11 +1,16 @@
 package reg
 
 import (
+	"bytes"
 	"crypto/rand"
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/zkp/zkptest"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/sha3"
+	"strings"
 	"testing"
 )
 
@@ -29,3 +34,87 @@ func TestZKPOverMultipleCurves(t *testing.T) {
 		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
 	}
 }
+
+func TestProofGobRoundTrip(t *testing.T) {
+	gob.Register(&curves.ScalarK256{})
+	gob.Register(&curves.PointK256{})
+	gob.Register(&curves.ScalarP256{})
+	gob.Register(&curves.PointP256{})
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+	}
+	for i, curve := range curveInstances {
+		basePoint := curve.Point.Random(rand.Reader)
+		ek := curve.Point.Random(rand.Reader)
+		uniqueSessionId := sha3.New256().Sum([]byte("random seed"))
+		prover, _ := NewProver(curve, basePoint, ek, uniqueSessionId)
+		proof, err := prover.Prove(curve.Scalar.Random(rand.Reader), curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+
+		buf := bytes.NewBuffer([]byte{})
+		require.NoError(t, gob.NewEncoder(buf).Encode(proof))
+		gobData, err := proof.GobEncode()
+		require.NoError(t, err)
+		binaryData, err := proof.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, binaryData, gobData)
+		decoded := &Proof{}
+		require.NoError(t, gob.NewDecoder(buf).Decode(decoded))
+		err = Verify(decoded, curve, basePoint, ek, uniqueSessionId)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+	}
+}
+
+func TestProofEncoding(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+		curves.SM2(),
+	}
+	for i, curve := range curveInstances {
+		uniqueSessionId := sha3.New256().Sum([]byte("random seed"))
+		basePoint := curve.Point.Random(rand.Reader)
+		ek := curve.Point.Random(rand.Reader)
+		prover, _ := NewProver(curve, basePoint, ek, uniqueSessionId)
+		proof, err := prover.Prove(curve.Scalar.Random(rand.Reader), curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+
+		data, err := proof.MarshalBinary()
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		require.True(t, strings.HasPrefix(string(data), curve.Name+":"))
+		decoded := &Proof{}
+		require.NoError(t, decoded.UnmarshalBinary(data), fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, Verify(decoded, curve, basePoint, ek, uniqueSessionId), fmt.Sprintf("failed in curve %d", i))
+		require.Error(t, decoded.UnmarshalBinary(data[:len(data)-1]))
+
+		data, err = json.Marshal(proof)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		decoded = &Proof{}
+		require.NoError(t, json.Unmarshal(data, decoded), fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, Verify(decoded, curve, basePoint, ek, uniqueSessionId), fmt.Sprintf("failed in curve %d", i))
+	}
+}
+
+func TestZKPWithTranscript(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+	}
+	for i, curve := range curveInstances {
+		basePoint := curve.Point.Random(rand.Reader)
+		ek := curve.Point.Random(rand.Reader)
+		// two proofs chained on one transcript
+		proverTranscript, verifierTranscript := zkptest.NewTranscript("protocol"), zkptest.NewTranscript("protocol")
+		prover, err := NewProverWithTranscript(curve, basePoint, ek, proverTranscript)
+		require.NoError(t, err)
+		first, err := prover.Prove(curve.Scalar.Random(rand.Reader), curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		second, err := prover.Prove(curve.Scalar.Random(rand.Reader), curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+
+		require.Error(t, VerifyWithTranscript(second, curve, basePoint, ek, zkptest.NewTranscript("protocol")))
+		require.NoError(t, VerifyWithTranscript(first, curve, basePoint, ek, verifierTranscript), fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, VerifyWithTranscript(second, curve, basePoint, ek, verifierTranscript), fmt.Sprintf("failed in curve %d", i))
+	}
+}
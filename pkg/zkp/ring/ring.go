@@ -0,0 +1,142 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package ring implements linkable ring signatures: a member of a ring of public keys signs a message without revealing
+// which member it is, but two signatures by the same member share its key image I = x*H(P), where x is its secret and
+// H hashes its public key P to a point.
+//
+// The signature proves the statement of LSAG https://eprint.iacr.org/2004/027.pdf, that for some i the signer knows
+// x_i with P_i = x_i*G and I = x_i*H(P_i), with the same OR composition as package dlor, bound to the ring, the key
+// image and the message. It has 2n scalars for a ring of n keys, against n+1 for LSAG and CLSAG, in exchange for reusing the
+// sigma protocol engine. It works over any curve; on curves with a cofactor, key images are compared after clearing
+// it, so that a signer cannot unlink its signatures by adding a small order point to its key image.
+package ring
+
+import (
+	"fmt"
+
+	"github.com/gtank/merlin"
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/zkp/sigma"
+)
+
+// protocolLabel separates the ring signatures from the other proofs in a transcript
+const protocolLabel = "Coinbase_LinkableRingSignature"
+
+// Signature is the key image of the signer and the proof that it belongs to one of the keys of the ring
+type Signature struct {
+	KeyImage  curves.Point
+	C         curves.Scalar
+	Responses []curves.Scalar
+}
+
+// KeyImage returns the key image x*H(P) of the secret x of the public key P, which all its signatures share
+func KeyImage(curve *curves.Curve, x curves.Scalar) curves.Point {
+	return hashToPoint(curve, curve.ScalarBaseMult(x)).Mul(x)
+}
+
+// Sign signs the message with the secret x of one of the public keys of the ring
+func Sign(curve *curves.Curve, ring []curves.Point, x curves.Scalar, message []byte) (*Signature, error) {
+	if err := checkRing(ring); err != nil {
+		return nil, err
+	}
+	if x == nil {
+		return nil, fmt.Errorf("secret missing")
+	}
+	publicKey := curve.ScalarBaseMult(x)
+	index := -1
+	for i, member := range ring {
+		if member.Equal(publicKey) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("the signer is not in the ring")
+	}
+	witness := make([]curves.Scalar, len(ring))
+	witness[index] = x
+
+	keyImage := KeyImage(curve, x)
+	proof, err := sigma.ProveTranscript(curve, protocolLabel, statement(curve, ring, keyImage), witness, transcript(message))
+	if err != nil {
+		return nil, errors.Wrap(err, "ring signature")
+	}
+	return &Signature{KeyImage: keyImage, C: proof.C, Responses: proof.Responses}, nil
+}
+
+// Verify checks that the signature of the message is by a member of the ring
+func Verify(curve *curves.Curve, ring []curves.Point, message []byte, signature *Signature) error {
+	if err := checkRing(ring); err != nil {
+		return err
+	}
+	if signature == nil || signature.KeyImage == nil || signature.C == nil || len(signature.Responses) != 2*len(ring)-1 {
+		return fmt.Errorf("ring signature missing")
+	}
+	if clearCofactor(signature.KeyImage).IsIdentity() {
+		return fmt.Errorf("invalid key image")
+	}
+	proof := &sigma.Proof{C: signature.C, Responses: signature.Responses}
+	if err := sigma.VerifyTranscript(curve, protocolLabel, statement(curve, ring, signature.KeyImage), proof, transcript(message)); err != nil {
+		return errors.Wrap(err, "ring signature verification failed")
+	}
+	return nil
+}
+
+// Linked reports whether two valid signatures were made with the same secret
+func Linked(a, b *Signature) bool {
+	return clearCofactor(a.KeyImage).Equal(clearCofactor(b.KeyImage))
+}
+
+// statement is the knowledge of the secret x_i (witness i) of one of the public keys of the ring such that
+// P_i = x_i*G and I = x_i*H(P_i)
+func statement(curve *curves.Curve, ring []curves.Point, keyImage curves.Point) sigma.Statement {
+	g := curve.NewGeneratorPoint()
+	branches := make([]sigma.Statement, len(ring))
+	for i, publicKey := range ring {
+		h := hashToPoint(curve, publicKey)
+		branches[i] = &sigma.Relation{
+			Equations: []*sigma.Equation{
+				{Image: publicKey, Terms: []sigma.Term{{Base: g, Witness: i}}},
+				{Image: keyImage, Terms: []sigma.Term{{Base: h, Witness: i}}},
+			},
+			Transcript: []curves.Point{publicKey, keyImage},
+			Labels:     []string{"public key", "key image"},
+		}
+	}
+	return sigma.Or(branches...)
+}
+
+// transcript returns the transcript of a signature of the message
+func transcript(message []byte) *merlin.Transcript {
+	t := merlin.NewTranscript(protocolLabel)
+	t.AppendMessage([]byte("message"), message)
+	return t
+}
+
+// hashToPoint is H, the hash of a public key to a point whose discrete log nobody knows
+func hashToPoint(curve *curves.Curve, publicKey curves.Point) curves.Point {
+	return curve.Point.Hash(append([]byte(protocolLabel), publicKey.ToAffineCompressed()...))
+}
+
+// clearCofactor multiplies the point by 8, the cofactor of ed25519, which is a bijection on the curves of prime order
+func clearCofactor(point curves.Point) curves.Point {
+	return point.Double().Double().Double()
+}
+
+func checkRing(ring []curves.Point) error {
+	if len(ring) == 0 {
+		return fmt.Errorf("ring missing")
+	}
+	for i, publicKey := range ring {
+		if publicKey == nil {
+			return fmt.Errorf("public key %d missing", i)
+		}
+	}
+	return nil
+}
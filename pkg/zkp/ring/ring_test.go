@@ -0,0 +1,84 @@
+package ring
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+)
+
+func TestRingSignatureOverMultipleCurves(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+		curves.ED25519(),
+	}
+	message := []byte("attestation")
+	for i, curve := range curveInstances {
+		ring, secrets := keys(curve, 6)
+		signature, err := Sign(curve, ring, secrets[4], message)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, Verify(curve, ring, message, signature), fmt.Sprintf("failed in curve %d", i))
+		require.True(t, signature.KeyImage.Equal(KeyImage(curve, secrets[4])))
+
+		require.Error(t, Verify(curve, ring, []byte("other message"), signature))
+		require.Error(t, Verify(curve, ring[:5], message, signature))
+		other, _ := keys(curve, 6)
+		require.Error(t, Verify(curve, other, message, signature))
+
+		// the same signer is linked, even in another ring, and another signer is not
+		again, err := Sign(curve, append(other, ring[4]), secrets[4], []byte("another message"))
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		require.True(t, Linked(signature, again))
+		someoneElse, err := Sign(curve, ring, secrets[1], message)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		require.False(t, Linked(signature, someoneElse))
+
+		// the key image of another signer does not verify
+		forged := *signature
+		forged.KeyImage = someoneElse.KeyImage
+		require.Error(t, Verify(curve, ring, message, &forged))
+	}
+}
+
+func TestSmallOrderKeyImage(t *testing.T) {
+	curve := curves.ED25519()
+	ring, secrets := keys(curve, 3)
+	signature, err := Sign(curve, ring, secrets[0], []byte("message"))
+	require.NoError(t, err)
+
+	// a point of order 2, (0, -1)
+	torsion, err := curve.Point.FromAffineCompressed(append(append([]byte{0xec}, bytes.Repeat([]byte{0xff}, 30)...), 0x7f))
+	require.NoError(t, err)
+	require.True(t, torsion.Double().IsIdentity())
+	shifted := *signature
+	shifted.KeyImage = signature.KeyImage.Add(torsion)
+	require.True(t, Linked(signature, &shifted))
+
+	shifted.KeyImage = torsion
+	require.Error(t, Verify(curve, ring, []byte("message"), &shifted))
+}
+
+func TestSignOutsideTheRing(t *testing.T) {
+	curve := curves.K256()
+	ring, _ := keys(curve, 3)
+	_, err := Sign(curve, ring, curve.Scalar.Random(rand.Reader), []byte("message"))
+	require.Error(t, err)
+	_, err = Sign(curve, nil, curve.Scalar.Random(rand.Reader), []byte("message"))
+	require.Error(t, err)
+	require.Error(t, Verify(curve, ring, []byte("message"), nil))
+}
+
+func keys(curve *curves.Curve, n int) ([]curves.Point, []curves.Scalar) {
+	ring := make([]curves.Point, n)
+	secrets := make([]curves.Scalar, n)
+	for i := range secrets {
+		secrets[i] = curve.Scalar.Random(rand.Reader)
+		ring[i] = curve.ScalarBaseMult(secrets[i])
+	}
+	return ring, secrets
+}
@@ -1,10 +1,12 @@
 package rre
 
 import (
-	"crypto/rand"
 	"crypto/subtle"
 	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/zkp/internal/codec"
+	"github.com/coinbase/kryptology/pkg/zkp/sigma"
+	"github.com/gtank/merlin"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/sha3"
 )
@@ -12,6 +14,9 @@ import (
 type Commitment = []byte
 type SessionId = []byte
 
+// protocolLabel separates the re-randomization relation proofs from the other proofs in a transcript
+const protocolLabel = "Coinbase_ZKP_ReRandomization"
+
 type Prover struct {
 	curve           *curves.Curve
 	basePoint       curves.Point
@@ -19,6 +24,7 @@ type Prover struct {
 	A               curves.Point
 	B               curves.Point
 	uniqueSessionId []byte
+	transcript      *merlin.Transcript
 }
 
 type Proof struct {
@@ -53,62 +59,54 @@ func NewProver(curve *curves.Curve, basePoint curves.Point, ek curves.Point, A c
 	}, nil
 }
 
+// NewProverWithTranscript is NewProver with the challenges drawn from the transcript, which advances with each proof,
+// so the verifier must check the proofs in the same order with VerifyWithTranscript
+func NewProverWithTranscript(curve *curves.Curve, basePoint curves.Point, ek curves.Point, A curves.Point, B curves.Point, transcript *merlin.Transcript) (*Prover, error) {
+	prover, err := NewProver(curve, basePoint, ek, A, B, nil)
+	if err != nil {
+		return nil, err
+	}
+	prover.transcript = transcript
+	return prover, nil
+}
+
 func (p *Prover) Prove(s curves.Scalar, r curves.Scalar) (*Proof, error) {
-	var err error
 	result := &Proof{}
 
 	// compute statement
 	result.APrime = p.A.Mul(s).Add(p.basePoint.Mul(r))
 	result.BPrime = p.B.Mul(s).Add(p.ek.Mul(r))
 
-	// compute commitment
-	alpha := p.curve.Scalar.Random(rand.Reader)
-	beta := p.curve.Scalar.Random(rand.Reader)
-	Y1 := p.A.Mul(beta).Add(p.basePoint.Mul(alpha))
-	Y2 := p.B.Mul(beta).Add(p.ek.Mul(alpha))
-
-	// compute challenge
-	hash := sha3.New256()
-	if _, err = hash.Write(p.uniqueSessionId); err != nil {
-		return nil, errors.Wrap(err, "writing salt to hash in re-randomization relation proof")
-	}
-	if _, err = hash.Write(p.basePoint.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing base point to hash in re-randomization relation proof")
-	}
-	if _, err = hash.Write(p.ek.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing encryption key to hash in re-randomization relation proof")
-	}
-	if _, err = hash.Write(p.A.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing A to hash in re-randomization relation proof")
-	}
-	if _, err = hash.Write(p.B.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing B to hash in re-randomization relation proof")
-	}
-	if _, err = hash.Write(result.APrime.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing A' (first part of statement) to hash in re-randomization relation proof")
-	}
-	if _, err = hash.Write(result.BPrime.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing B' (second part of statement) to hash in re-randomization relation proof")
-	}
-	if _, err = hash.Write(Y1.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing point Y1 to hash in re-randomization relation proof")
-	}
-	if _, err = hash.Write(Y2.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing point Y2 to hash in re-randomization relation proof")
+	relation := statement(p.basePoint, p.ek, p.A, p.B, result.APrime, result.BPrime)
+	var proof *sigma.Proof
+	var err error
+	if p.transcript != nil {
+		proof, err = sigma.ProveTranscript(p.curve, protocolLabel, relation, []curves.Scalar{s, r}, p.transcript)
+	} else {
+		proof, err = sigma.Prove(p.curve, relation, []curves.Scalar{s, r}, p.uniqueSessionId)
 	}
-	result.e, err = p.curve.Scalar.SetBytes(hash.Sum(nil))
 	if err != nil {
-		return nil, errors.Wrap(err, "generating challenge in re-randomization relation proof")
+		return nil, errors.Wrap(err, "re-randomization relation proof")
 	}
-
-	// compute response
-	result.z1 = beta.Add(result.e.Mul(s))
-	result.z2 = alpha.Add(result.e.Mul(r))
-
+	result.e, result.z1, result.z2 = proof.C, proof.Responses[0], proof.Responses[1]
 	return result, nil
 }
 
 func Verify(proof *Proof, curve *curves.Curve, basePoint curves.Point, ek curves.Point, A curves.Point, B curves.Point, uniqueSessionId []byte) error {
+	return verify(proof, curve, basePoint, ek, A, B, func(relation *sigma.Relation, proof *sigma.Proof) error {
+		return sigma.Verify(curve, relation, proof, uniqueSessionId)
+	})
+}
+
+// VerifyWithTranscript verifies a proof of a prover created with NewProverWithTranscript, advancing the transcript in
+// the same way
+func VerifyWithTranscript(proof *Proof, curve *curves.Curve, basePoint curves.Point, ek curves.Point, A curves.Point, B curves.Point, transcript *merlin.Transcript) error {
+	return verify(proof, curve, basePoint, ek, A, B, func(relation *sigma.Relation, proof *sigma.Proof) error {
+		return sigma.VerifyTranscript(curve, protocolLabel, relation, proof, transcript)
+	})
+}
+
+func verify(proof *Proof, curve *curves.Curve, basePoint curves.Point, ek curves.Point, A curves.Point, B curves.Point, check func(*sigma.Relation, *sigma.Proof) error) error {
 	if basePoint == nil {
 		basePoint = curve.NewGeneratorPoint()
 	}
@@ -121,51 +119,27 @@ func Verify(proof *Proof, curve *curves.Curve, basePoint curves.Point, ek curves
 	if B == nil {
 		return fmt.Errorf("point B missing")
 	}
-	var err error
-
-	// compute commitment
-	Y1 := A.Mul(proof.z1).Add(basePoint.Mul(proof.z2)).Sub(proof.APrime.Mul(proof.e))
-	Y2 := B.Mul(proof.z1).Add(ek.Mul(proof.z2)).Sub(proof.BPrime.Mul(proof.e))
-
-	// compute challenge
-	hash := sha3.New256()
-	if _, err = hash.Write(uniqueSessionId); err != nil {
-		return errors.Wrap(err, "writing salt to hash in re-randomization relation verification")
-	}
-	if _, err = hash.Write(basePoint.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing base point to hash in re-randomization relation verification")
-	}
-	if _, err = hash.Write(ek.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing encryption key to hash in re-randomization relation verification")
-	}
-	if _, err = hash.Write(A.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing A to hash in re-randomization relation verification")
-	}
-	if _, err = hash.Write(B.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing B to hash in re-randomization relation verification")
-	}
-	if _, err = hash.Write(proof.APrime.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing A' (first part of statement) to hash in re-randomization relation verification")
-	}
-	if _, err = hash.Write(proof.BPrime.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing B' (second part of statement) to hash in re-randomization relation verification")
-	}
-	if _, err = hash.Write(Y1.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing point Y1 to hash in re-randomization relation verification")
-	}
-	if _, err = hash.Write(Y2.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing point Y2 to hash in re-randomization relation verification")
+	if proof == nil || proof.APrime == nil || proof.BPrime == nil {
+		return fmt.Errorf("re-randomization relation proof missing")
 	}
-	e, err := curve.Scalar.SetBytes(hash.Sum(nil))
+	err := check(statement(basePoint, ek, A, B, proof.APrime, proof.BPrime), &sigma.Proof{C: proof.e, Responses: []curves.Scalar{proof.z1, proof.z2}})
 	if err != nil {
-		return errors.Wrap(err, "generating challenge in re-randomization relation verification")
+		return errors.Wrap(err, "re-randomization relation verification failed")
 	}
+	return nil
+}
 
-	// compare challenge
-	if e.Cmp(proof.e) != 0 {
-		return fmt.Errorf("re-randomization relation verification failed")
+// statement is the knowledge of the scalar s (witness 0) and the randomness r (witness 1) that re-randomize the
+// elgamal ciphertext (A, B) into (A', B') = (s*A + r*basePoint, s*B + r*ek)
+func statement(basePoint curves.Point, ek curves.Point, A curves.Point, B curves.Point, APrime curves.Point, BPrime curves.Point) *sigma.Relation {
+	return &sigma.Relation{
+		Equations: []*sigma.Equation{
+			{Image: APrime, Terms: []sigma.Term{{Base: A, Witness: 0}, {Base: basePoint, Witness: 1}}},
+			{Image: BPrime, Terms: []sigma.Term{{Base: B, Witness: 0}, {Base: ek, Witness: 1}}},
+		},
+		Transcript: []curves.Point{basePoint, ek, A, B, APrime, BPrime},
+		Labels:     []string{"base point", "encryption key", "A", "B", "A'", "B'"},
 	}
-	return nil
 }
 
 func (p *Prover) ComProve(s curves.Scalar, r curves.Scalar) (*Proof, Commitment, error) {
@@ -195,6 +169,22 @@ func (p *Prover) ComProve(s curves.Scalar, r curves.Scalar) (*Proof, Commitment,
 }
 
 func DeComVerify(proof *Proof, commitment Commitment, curve *curves.Curve, basePoint curves.Point, ek curves.Point, A curves.Point, B curves.Point, uniqueSessionId []byte) error {
+	if err := decommit(proof, commitment); err != nil {
+		return err
+	}
+	return Verify(proof, curve, basePoint, ek, A, B, uniqueSessionId)
+}
+
+// DeComVerifyWithTranscript is DeComVerify for a prover created with NewProverWithTranscript
+func DeComVerifyWithTranscript(proof *Proof, commitment Commitment, curve *curves.Curve, basePoint curves.Point, ek curves.Point, A curves.Point, B curves.Point, transcript *merlin.Transcript) error {
+	if err := decommit(proof, commitment); err != nil {
+		return err
+	}
+	return VerifyWithTranscript(proof, curve, basePoint, ek, A, B, transcript)
+}
+
+// decommit checks that the proof opens the commitment
+func decommit(proof *Proof, commitment Commitment) error {
 	hash := sha3.New256()
 	if _, err := hash.Write(proof.e.Bytes()); err != nil {
 		return err
@@ -214,5 +204,53 @@ func DeComVerify(proof *Proof, commitment Commitment, curve *curves.Curve, baseP
 	if subtle.ConstantTimeCompare(hash.Sum(nil), commitment) != 1 {
 		return fmt.Errorf("initial hash decommitment failed")
 	}
-	return Verify(proof, curve, basePoint, ek, A, B, uniqueSessionId)
+	return nil
+}
+
+// proofFields names the fields of a Proof in its encodings
+var proofFields = codec.Fields{
+	Scalars: []string{"e", "z1", "z2"},
+	Points:  []string{"aPrime", "bPrime"},
+}
+
+// MarshalBinary encodes the proof as the curve name, a version byte, the scalars and the compressed points
+func (proof *Proof) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinary(proofFields, []curves.Scalar{proof.e, proof.z1, proof.z2}, []curves.Point{proof.APrime, proof.BPrime})
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary, rejecting non-canonical scalars and identity or invalid points
+func (proof *Proof) UnmarshalBinary(data []byte) error {
+	_, scalars, points, err := codec.UnmarshalBinary(proofFields, data)
+	if err != nil {
+		return err
+	}
+	proof.e, proof.z1, proof.z2 = scalars[0], scalars[1], scalars[2]
+	proof.APrime, proof.BPrime = points[0], points[1]
+	return nil
+}
+
+// MarshalJSON encodes the proof as a JSON object holding the version, the curve name and the hex encoded fields
+func (proof *Proof) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON(proofFields, []curves.Scalar{proof.e, proof.z1, proof.z2}, []curves.Point{proof.APrime, proof.BPrime})
+}
+
+// UnmarshalJSON decodes the output of MarshalJSON with the same checks as UnmarshalBinary
+func (proof *Proof) UnmarshalJSON(data []byte) error {
+	_, scalars, points, err := codec.UnmarshalJSON(proofFields, data)
+	if err != nil {
+		return err
+	}
+	proof.e, proof.z1, proof.z2 = scalars[0], scalars[1], scalars[2]
+	proof.APrime, proof.BPrime = points[0], points[1]
+	return nil
+}
+
+// GobEncode lets the proof travel inside gob encoded protocol messages, using the encoding of MarshalBinary
+func (proof *Proof) GobEncode() ([]byte, error) {
+	return proof.MarshalBinary()
+}
+
+// GobDecode is the inverse of GobEncode
+func (proof *Proof) GobDecode(data []byte) error {
+	return proof.UnmarshalBinary(data)
 }
@@ -1,15 +1,19 @@
 package rspdl
 
 import (
-	"crypto/rand"
 	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/zkp/internal/codec"
+	"github.com/coinbase/kryptology/pkg/zkp/sigma"
+	"github.com/gtank/merlin"
 	"github.com/pkg/errors"
-	"golang.org/x/crypto/sha3"
 )
 
 type SessionId = []byte
 
+// protocolLabel separates the scalar product with discrete logarithm relation proofs from the other proofs in a transcript
+const protocolLabel = "Coinbase_ZKP_ScalarProductDiscreteLog"
+
 type Prover struct {
 	curve           *curves.Curve
 	basePoint       curves.Point
@@ -18,6 +22,7 @@ type Prover struct {
 	B               curves.Point
 	X               curves.Point
 	uniqueSessionId []byte
+	transcript      *merlin.Transcript
 }
 
 type Proof struct {
@@ -53,71 +58,59 @@ func NewProver(curve *curves.Curve, basePoint curves.Point, T curves.Point, A cu
 	}, nil
 }
 
+// NewProverWithTranscript is NewProver with the challenges drawn from the transcript, which advances with each proof,
+// so the verifier must check the proofs in the same order with VerifyWithTranscript
+func NewProverWithTranscript(curve *curves.Curve, basePoint curves.Point, T curves.Point, A curves.Point, B curves.Point, X curves.Point, transcript *merlin.Transcript) (*Prover, error) {
+	prover, err := NewProver(curve, basePoint, T, A, B, X, nil)
+	if err != nil {
+		return nil, err
+	}
+	prover.transcript = transcript
+	return prover, nil
+}
+
 func (p *Prover) Prove(x curves.Scalar, r curves.Scalar) (*Proof, error) {
-	var err error
 	result := &Proof{}
 
 	// compute statement
 	result.APrime = p.A.Mul(x).Add(p.basePoint.Mul(r))
 	result.BPrime = p.B.Mul(x).Add(p.T.Mul(r))
 
-	// compute commitment
-	alpha := p.curve.Scalar.Random(rand.Reader)
-	beta := p.curve.Scalar.Random(rand.Reader)
-	Y1 := p.basePoint.Mul(beta)
-	Y2 := p.A.Mul(beta).Add(p.basePoint.Mul(alpha))
-	Y3 := p.B.Mul(beta).Add(p.T.Mul(alpha))
-
-	// compute challenge
-	hash := sha3.New256()
-	if _, err = hash.Write(p.uniqueSessionId); err != nil {
-		return nil, errors.Wrap(err, "writing salt to hash in scalar product with discrete logarithm relation proof")
-	}
-	if _, err = hash.Write(p.basePoint.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing base point to hash in scalar product with discrete logarithm relation proof")
-	}
-	if _, err = hash.Write(p.A.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing A to hash in scalar product with discrete logarithm relation proof")
-	}
-	if _, err = hash.Write(p.B.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing B to hash in scalar product with discrete logarithm relation proof")
-	}
-	if _, err = hash.Write(p.X.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing X (first part of statement) to hash in scalar product with discrete logarithm relation proof")
-	}
-	if _, err = hash.Write(result.APrime.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing A' (second part of statement) to hash in scalar product with discrete logarithm relation proof")
-	}
-	if _, err = hash.Write(result.BPrime.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing B' (third part of statement) to hash in scalar product with discrete logarithm relation proof")
-	}
-	if _, err = hash.Write(Y1.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing point Y1 to hash in scalar product with discrete logarithm relation proof")
-	}
-	if _, err = hash.Write(Y2.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing point Y2 to hash in scalar product with discrete logarithm relation proof")
-	}
-	if _, err = hash.Write(Y3.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing point Y3 to hash in scalar product with discrete logarithm relation proof")
+	relation := statement(p.basePoint, p.T, p.A, p.B, p.X, result.APrime, result.BPrime)
+	var proof *sigma.Proof
+	var err error
+	if p.transcript != nil {
+		proof, err = sigma.ProveTranscript(p.curve, protocolLabel, relation, []curves.Scalar{x, r}, p.transcript)
+	} else {
+		proof, err = sigma.Prove(p.curve, legacy(relation), []curves.Scalar{x, r}, p.uniqueSessionId)
 	}
-	result.e, err = p.curve.Scalar.SetBytes(hash.Sum(nil))
 	if err != nil {
-		return nil, errors.Wrap(err, "generating challenge in scalar product with discrete logarithm relation proof")
+		return nil, errors.Wrap(err, "scalar product with discrete logarithm relation proof")
 	}
-
-	// compute response
-	result.z1 = beta.Add(result.e.Mul(x))
-	result.z2 = alpha.Add(result.e.Mul(r))
-
+	result.e, result.z1, result.z2 = proof.C, proof.Responses[0], proof.Responses[1]
 	return result, nil
 }
 
 func Verify(proof *Proof, curve *curves.Curve, basePoint curves.Point, T curves.Point, A curves.Point, B curves.Point, X curves.Point, uniqueSessionId []byte) error {
+	return verify(proof, curve, basePoint, T, A, B, X, func(relation *sigma.Relation, proof *sigma.Proof) error {
+		return sigma.Verify(curve, legacy(relation), proof, uniqueSessionId)
+	})
+}
+
+// VerifyWithTranscript verifies a proof of a prover created with NewProverWithTranscript, advancing the transcript in
+// the same way
+func VerifyWithTranscript(proof *Proof, curve *curves.Curve, basePoint curves.Point, T curves.Point, A curves.Point, B curves.Point, X curves.Point, transcript *merlin.Transcript) error {
+	return verify(proof, curve, basePoint, T, A, B, X, func(relation *sigma.Relation, proof *sigma.Proof) error {
+		return sigma.VerifyTranscript(curve, protocolLabel, relation, proof, transcript)
+	})
+}
+
+func verify(proof *Proof, curve *curves.Curve, basePoint curves.Point, T curves.Point, A curves.Point, B curves.Point, X curves.Point, check func(*sigma.Relation, *sigma.Proof) error) error {
 	if basePoint == nil {
 		basePoint = curve.NewGeneratorPoint()
 	}
 	if T == nil {
-		return fmt.Errorf("point A missing")
+		return fmt.Errorf("point T missing")
 	}
 	if A == nil {
 		return fmt.Errorf("point A missing")
@@ -128,53 +121,83 @@ func Verify(proof *Proof, curve *curves.Curve, basePoint curves.Point, T curves.
 	if X == nil {
 		return fmt.Errorf("point X missing")
 	}
-	var err error
-
-	// compute commitment
-	Y1 := basePoint.Mul(proof.z1).Sub(X.Mul(proof.e))
-	Y2 := A.Mul(proof.z1).Add(basePoint.Mul(proof.z2)).Sub(proof.APrime.Mul(proof.e))
-	Y3 := B.Mul(proof.z1).Add(T.Mul(proof.z2)).Sub(proof.BPrime.Mul(proof.e))
-
-	// compute challenge
-	hash := sha3.New256()
-	if _, err = hash.Write(uniqueSessionId); err != nil {
-		return errors.Wrap(err, "writing salt to hash in scalar product with discrete logarithm relation verification")
-	}
-	if _, err = hash.Write(basePoint.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing base point to hash in scalar product with discrete logarithm relation verification")
-	}
-	if _, err = hash.Write(A.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing A to hash in scalar product with discrete logarithm relation verification")
-	}
-	if _, err = hash.Write(B.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing B to hash in scalar product with discrete logarithm relation verification")
-	}
-	if _, err = hash.Write(X.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing X (first part of statement) to hash in scalar product with discrete logarithm relation verification")
+	if proof == nil || proof.APrime == nil || proof.BPrime == nil {
+		return fmt.Errorf("scalar product with discrete logarithm relation proof missing")
 	}
-	if _, err = hash.Write(proof.APrime.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing A' (second part of statement) to hash in scalar product with discrete logarithm relation verification")
-	}
-	if _, err = hash.Write(proof.BPrime.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing B' (third part of statement) to hash in scalar product with discrete logarithm relation verification")
-	}
-	if _, err = hash.Write(Y1.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing point Y1 to hash in scalar product with discrete logarithm relation verification")
+	err := check(statement(basePoint, T, A, B, X, proof.APrime, proof.BPrime), &sigma.Proof{C: proof.e, Responses: []curves.Scalar{proof.z1, proof.z2}})
+	if err != nil {
+		return errors.Wrap(err, "scalar product with discrete logarithm relation verification failed")
 	}
-	if _, err = hash.Write(Y2.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing point Y2 to hash in scalar product with discrete logarithm relation verification")
+	return nil
+}
+
+// statement is the knowledge of the discrete log x (witness 0) of X and of the randomness r (witness 1) such that
+// (A', B') = (x*A + r*basePoint, x*B + r*T)
+func statement(basePoint curves.Point, T curves.Point, A curves.Point, B curves.Point, X curves.Point, APrime curves.Point, BPrime curves.Point) *sigma.Relation {
+	return &sigma.Relation{
+		Equations: []*sigma.Equation{
+			{Image: X, Terms: []sigma.Term{{Base: basePoint, Witness: 0}}},
+			{Image: APrime, Terms: []sigma.Term{{Base: A, Witness: 0}, {Base: basePoint, Witness: 1}}},
+			{Image: BPrime, Terms: []sigma.Term{{Base: B, Witness: 0}, {Base: T, Witness: 1}}},
+		},
+		Transcript: []curves.Point{basePoint, T, A, B, X, APrime, BPrime},
+		Labels:     []string{"base point", "T", "A", "B", "X", "A'", "B'"},
 	}
-	if _, err = hash.Write(Y3.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing point Y3 to hash in scalar product with discrete logarithm relation verification")
+}
+
+// legacy returns the relation with the hash input that the session id proofs always had, which does not bind T
+func legacy(relation *sigma.Relation) *sigma.Relation {
+	t := relation.Transcript
+	return &sigma.Relation{
+		Equations:  relation.Equations,
+		Transcript: []curves.Point{t[0], t[2], t[3], t[4], t[5], t[6]},
 	}
-	e, err := curve.Scalar.SetBytes(hash.Sum(nil))
+}
+
+// proofFields names the fields of a Proof in its encodings
+var proofFields = codec.Fields{
+	Scalars: []string{"e", "z1", "z2"},
+	Points:  []string{"aPrime", "bPrime"},
+}
+
+// MarshalBinary encodes the proof as the curve name, a version byte, the scalars and the compressed points
+func (proof *Proof) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinary(proofFields, []curves.Scalar{proof.e, proof.z1, proof.z2}, []curves.Point{proof.APrime, proof.BPrime})
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary, rejecting non-canonical scalars and identity or invalid points
+func (proof *Proof) UnmarshalBinary(data []byte) error {
+	_, scalars, points, err := codec.UnmarshalBinary(proofFields, data)
 	if err != nil {
-		return errors.Wrap(err, "generating challenge in scalar product with discrete logarithm relation verification")
+		return err
 	}
+	proof.e, proof.z1, proof.z2 = scalars[0], scalars[1], scalars[2]
+	proof.APrime, proof.BPrime = points[0], points[1]
+	return nil
+}
 
-	// compare challenge
-	if e.Cmp(proof.e) != 0 {
-		return fmt.Errorf("scalar product with discrete logarithm relation verification failed")
+// MarshalJSON encodes the proof as a JSON object holding the version, the curve name and the hex encoded fields
+func (proof *Proof) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON(proofFields, []curves.Scalar{proof.e, proof.z1, proof.z2}, []curves.Point{proof.APrime, proof.BPrime})
+}
+
+// UnmarshalJSON decodes the output of MarshalJSON with the same checks as UnmarshalBinary
+func (proof *Proof) UnmarshalJSON(data []byte) error {
+	_, scalars, points, err := codec.UnmarshalJSON(proofFields, data)
+	if err != nil {
+		return err
 	}
+	proof.e, proof.z1, proof.z2 = scalars[0], scalars[1], scalars[2]
+	proof.APrime, proof.BPrime = points[0], points[1]
 	return nil
 }
+
+// GobEncode lets the proof travel inside gob encoded protocol messages, using the encoding of MarshalBinary
+func (proof *Proof) GobEncode() ([]byte, error) {
+	return proof.MarshalBinary()
+}
+
+// GobDecode is the inverse of GobEncode
+func (proof *Proof) GobDecode(data []byte) error {
+	return proof.UnmarshalBinary(data)
+}
@@ -1,11 +1,16 @@
 package rspdl
 
 import (
+	"bytes"
 	"crypto/rand"
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/zkp/zkptest"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/sha3"
+	"strings"
 	"testing"
 )
 
@@ -32,3 +37,96 @@ func TestZKPOverMultipleCurves(t *testing.T) {
 		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
 	}
 }
+
+func TestProofGobRoundTrip(t *testing.T) {
+	gob.Register(&curves.ScalarK256{})
+	gob.Register(&curves.PointK256{})
+	gob.Register(&curves.ScalarP256{})
+	gob.Register(&curves.PointP256{})
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+	}
+	for i, curve := range curveInstances {
+		basePoint := curve.Point.Random(rand.Reader)
+		T := curve.Point.Random(rand.Reader)
+		A := curve.Point.Random(rand.Reader)
+		B := curve.Point.Random(rand.Reader)
+		x := curve.Scalar.Random(rand.Reader)
+		X := basePoint.Mul(x)
+		uniqueSessionId := sha3.New256().Sum([]byte("random seed"))
+		prover, _ := NewProver(curve, basePoint, T, A, B, X, uniqueSessionId)
+		proof, err := prover.Prove(x, curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+
+		buf := bytes.NewBuffer([]byte{})
+		require.NoError(t, gob.NewEncoder(buf).Encode(proof))
+		gobData, err := proof.GobEncode()
+		require.NoError(t, err)
+		binaryData, err := proof.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, binaryData, gobData)
+		decoded := &Proof{}
+		require.NoError(t, gob.NewDecoder(buf).Decode(decoded))
+		err = Verify(decoded, curve, basePoint, T, A, B, X, uniqueSessionId)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+	}
+}
+
+func TestProofEncoding(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+		curves.SM2(),
+	}
+	for i, curve := range curveInstances {
+		uniqueSessionId := sha3.New256().Sum([]byte("random seed"))
+		basePoint := curve.Point.Random(rand.Reader)
+		T := curve.Point.Random(rand.Reader)
+		A := curve.Point.Random(rand.Reader)
+		B := curve.Point.Random(rand.Reader)
+		x := curve.Scalar.Random(rand.Reader)
+		X := basePoint.Mul(x)
+		prover, _ := NewProver(curve, basePoint, T, A, B, X, uniqueSessionId)
+		proof, err := prover.Prove(x, curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+
+		data, err := proof.MarshalBinary()
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		require.True(t, strings.HasPrefix(string(data), curve.Name+":"))
+		decoded := &Proof{}
+		require.NoError(t, decoded.UnmarshalBinary(data), fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, Verify(decoded, curve, basePoint, T, A, B, X, uniqueSessionId), fmt.Sprintf("failed in curve %d", i))
+		require.Error(t, decoded.UnmarshalBinary(data[:len(data)-1]))
+
+		data, err = json.Marshal(proof)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		decoded = &Proof{}
+		require.NoError(t, json.Unmarshal(data, decoded), fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, Verify(decoded, curve, basePoint, T, A, B, X, uniqueSessionId), fmt.Sprintf("failed in curve %d", i))
+	}
+}
+
+func TestZKPWithTranscript(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+	}
+	for i, curve := range curveInstances {
+		basePoint := curve.Point.Random(rand.Reader)
+		T := curve.Point.Random(rand.Reader)
+		A := curve.Point.Random(rand.Reader)
+		B := curve.Point.Random(rand.Reader)
+		x := curve.Scalar.Random(rand.Reader)
+		X := basePoint.Mul(x)
+		prover, err := NewProverWithTranscript(curve, basePoint, T, A, B, X, zkptest.NewTranscript("protocol"))
+		require.NoError(t, err)
+		proof, err := prover.Prove(x, curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+
+		require.NoError(t, VerifyWithTranscript(proof, curve, basePoint, T, A, B, X, zkptest.NewTranscript("protocol")), fmt.Sprintf("failed in curve %d", i))
+		require.Error(t, VerifyWithTranscript(proof, curve, basePoint, T, A, B, X, zkptest.NewTranscript("other protocol")))
+		// unlike the session id transcript, the merlin transcript binds T
+		require.Error(t, VerifyWithTranscript(proof, curve, basePoint, curve.Point.Random(rand.Reader), A, B, X, zkptest.NewTranscript("protocol")))
+	}
+}
@@ -9,31 +9,39 @@
 package schnorr
 
 import (
-	"crypto/rand"
 	"crypto/subtle"
 	"fmt"
 
+	"github.com/gtank/merlin"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/sha3"
 
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/zkp/internal/codec"
+	"github.com/coinbase/kryptology/pkg/zkp/sigma"
 )
 
 type Commitment = []byte
 type SessionId = []byte
 
+// protocolLabel separates the schnorr proofs from the other proofs in a transcript
+const protocolLabel = "Coinbase_ZKP_Schnorr"
+
 type Prover struct {
 	curve           *curves.Curve
 	basePoint       curves.Point
 	uniqueSessionId []byte
+	transcript      *merlin.Transcript
 }
 
 // Proof contains the (c, s) schnorr proof. `Statement` is the curve point you're proving knowledge of discrete log of,
-// with respect to the base point.
+// with respect to the base point. `K` is the commitment of the prover: Verify recomputes it, but BatchVerify uses it
+// when it is set, so the encodings carry it.
 type Proof struct {
 	C         curves.Scalar
 	S         curves.Scalar
 	Statement curves.Point
+	K         curves.Point
 }
 
 // NewProver generates a `Prover` object, ready to generate Schnorr proofs on any given point.
@@ -49,64 +57,100 @@ func NewProver(curve *curves.Curve, basepoint curves.Point, uniqueSessionId []by
 	}
 }
 
+// NewProverWithTranscript is NewProver with the challenges drawn from `transcript`, to which every proof appends its
+// labeled public inputs. The transcript advances with each proof, so the verifier must check the proofs in the same
+// order with VerifyWithTranscript.
+func NewProverWithTranscript(curve *curves.Curve, basepoint curves.Point, transcript *merlin.Transcript) *Prover {
+	prover := NewProver(curve, basepoint, nil)
+	prover.transcript = transcript
+	return prover
+}
+
 // Prove generates and returns a Schnorr proof, given the scalar witness `x`.
 // in the process, it will actually also construct the statement (just one curve mult in this case)
 func (p *Prover) Prove(x curves.Scalar) (*Proof, error) {
-	// assumes that params, and pub are already populated. populates the fields c and s...
-	var err error
 	result := &Proof{}
 	result.Statement = p.basePoint.Mul(x)
-	k := p.curve.Scalar.Random(rand.Reader)
-	random := p.basePoint.Mul(k)
-	hash := sha3.New256()
-	if _, err = hash.Write(p.uniqueSessionId); err != nil {
-		return nil, errors.Wrap(err, "writing salt to hash in schnorr prove")
-	}
-	if _, err = hash.Write(p.basePoint.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing basePoint to hash in schnorr prove")
-	}
-	if _, err = hash.Write(result.Statement.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing statement to hash in schnorr prove")
-	}
-	if _, err = hash.Write(random.ToAffineCompressed()); err != nil {
-		return nil, errors.Wrap(err, "writing point K to hash in schnorr prove")
-	}
-	result.C, err = p.curve.Scalar.SetBytes(hash.Sum(nil))
+	proof, err := p.prove(statement(p.basePoint, result.Statement), x)
 	if err != nil {
-		return nil, errors.Wrap(err, "writing point K to hash in schnorr prove")
+		return nil, errors.Wrap(err, "schnorr prove")
 	}
-	result.S = result.C.Mul(x).Add(k)
+	result.C, result.S, result.K = proof.C, proof.Responses[0], proof.Commitments[0]
 	return result, nil
 }
 
+// prove proves the statement with the transcript of the prover, or with its session id if it has none
+func (p *Prover) prove(statement *sigma.Relation, witness ...curves.Scalar) (*sigma.Proof, error) {
+	if p.transcript != nil {
+		return sigma.ProveTranscript(p.curve, protocolLabel, statement, witness, p.transcript)
+	}
+	return sigma.Prove(p.curve, statement, witness, p.uniqueSessionId)
+}
+
 // Verify verifies the `proof`, given the prover parameters `scalar` and `curve`.
 // As for the prover, we allow `basePoint == nil`, in this case, it's auto-assigned to be the group's default generator.
 func Verify(proof *Proof, curve *curves.Curve, basepoint curves.Point, uniqueSessionId []byte) error {
+	return verify(proof, curve, basepoint, func(statement *sigma.Relation, proof *sigma.Proof) error {
+		return sigma.Verify(curve, statement, proof, uniqueSessionId)
+	})
+}
+
+// BatchVerify verifies `proofs[i]` for `uniqueSessionIds[i]`, all with respect to the same base point, with a single
+// multi-scalar multiplication for the proofs that carry `K`. If the batch fails, it verifies the proofs one at a time
+// and reports the index of the first invalid one.
+func BatchVerify(proofs []*Proof, curve *curves.Curve, basepoint curves.Point, uniqueSessionIds [][]byte) error {
 	if basepoint == nil {
 		basepoint = curve.NewGeneratorPoint()
 	}
-	gs := basepoint.Mul(proof.S)
-	xc := proof.Statement.Mul(proof.C.Neg())
-	random := gs.Add(xc)
-	hash := sha3.New256()
-	if _, err := hash.Write(uniqueSessionId); err != nil {
-		return errors.Wrap(err, "writing salt to hash in schnorr verify")
+	statements := make([]*sigma.Relation, len(proofs))
+	sigmaProofs := make([]*sigma.Proof, len(proofs))
+	for i, proof := range proofs {
+		if proof == nil || proof.Statement == nil {
+			return fmt.Errorf("schnorr proof %d missing", i)
+		}
+		statements[i] = statement(basepoint, proof.Statement)
+		sigmaProofs[i] = &sigma.Proof{C: proof.C, Responses: []curves.Scalar{proof.S}}
+		if proof.K != nil {
+			sigmaProofs[i].Commitments = []curves.Point{proof.K}
+		}
 	}
-	if _, err := hash.Write(basepoint.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing basePoint to hash in schnorr verify")
+	if err := sigma.BatchVerify(curve, statements, sigmaProofs, uniqueSessionIds); err != nil {
+		return errors.Wrap(err, "schnorr batch verification failed")
 	}
-	if _, err := hash.Write(proof.Statement.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing statement to hash in schnorr verify")
+	return nil
+}
+
+// VerifyWithTranscript verifies a `proof` of a prover created with NewProverWithTranscript, advancing `transcript`
+// in the same way.
+func VerifyWithTranscript(proof *Proof, curve *curves.Curve, basepoint curves.Point, transcript *merlin.Transcript) error {
+	return verify(proof, curve, basepoint, func(statement *sigma.Relation, proof *sigma.Proof) error {
+		return sigma.VerifyTranscript(curve, protocolLabel, statement, proof, transcript)
+	})
+}
+
+func verify(proof *Proof, curve *curves.Curve, basepoint curves.Point, check func(*sigma.Relation, *sigma.Proof) error) error {
+	if basepoint == nil {
+		basepoint = curve.NewGeneratorPoint()
 	}
-	if _, err := hash.Write(random.ToAffineCompressed()); err != nil {
-		return errors.Wrap(err, "writing point K to hash in schnorr verify")
+	if proof == nil || proof.Statement == nil {
+		return fmt.Errorf("schnorr proof missing")
 	}
-	if subtle.ConstantTimeCompare(proof.C.Bytes(), hash.Sum(nil)) != 1 {
-		return fmt.Errorf("schnorr verification failed")
+	if err := check(statement(basepoint, proof.Statement), &sigma.Proof{C: proof.C, Responses: []curves.Scalar{proof.S}}); err != nil {
+		return errors.Wrap(err, "schnorr verification failed")
 	}
 	return nil
 }
 
+// statement is the knowledge of the discrete log of x with respect to the base point
+func statement(basePoint curves.Point, x curves.Point) *sigma.Relation {
+	return &sigma.Relation{
+		Equations: []*sigma.Equation{
+			{Image: x, Terms: []sigma.Term{{Base: basePoint, Witness: 0}}},
+		},
+		Labels: []string{"base point", "statement"},
+	}
+}
+
 // ProveCommit generates _and_ commits to a schnorr proof which is later revealed; see Functionality 7.
 // returns the Proof and Commitment.
 func (p *Prover) ProveCommit(x curves.Scalar) (*Proof, Commitment, error) {
@@ -130,6 +174,39 @@ func (p *Prover) ProveCommit(x curves.Scalar) (*Proof, Commitment, error) {
 // DecommitVerify receives a `Proof` and a `Commitment`; it first checks that the proof actually opens the commitment;
 // then it verifies the proof. returns and error if either on eof thse fail.
 func DecommitVerify(proof *Proof, commitment Commitment, curve *curves.Curve, basepoint curves.Point, uniqueSessionId []byte) error {
+	if err := decommit(proof, commitment); err != nil {
+		return err
+	}
+	return Verify(proof, curve, basepoint, uniqueSessionId)
+}
+
+// DecommitBatchVerify is DecommitVerify for many proofs: it checks that `proofs[i]` opens `commitments[i]` and then
+// verifies the proofs with BatchVerify
+func DecommitBatchVerify(proofs []*Proof, commitments []Commitment, curve *curves.Curve, basepoint curves.Point, uniqueSessionIds [][]byte) error {
+	if len(commitments) != len(proofs) {
+		return fmt.Errorf("expected %d commitments, got %d", len(proofs), len(commitments))
+	}
+	for i, proof := range proofs {
+		if proof == nil || proof.C == nil || proof.S == nil || proof.Statement == nil {
+			return fmt.Errorf("schnorr proof %d missing", i)
+		}
+		if err := decommit(proof, commitments[i]); err != nil {
+			return errors.Wrapf(err, "proof %d", i)
+		}
+	}
+	return BatchVerify(proofs, curve, basepoint, uniqueSessionIds)
+}
+
+// DecommitVerifyWithTranscript is DecommitVerify for a prover created with NewProverWithTranscript
+func DecommitVerifyWithTranscript(proof *Proof, commitment Commitment, curve *curves.Curve, basepoint curves.Point, transcript *merlin.Transcript) error {
+	if err := decommit(proof, commitment); err != nil {
+		return err
+	}
+	return VerifyWithTranscript(proof, curve, basepoint, transcript)
+}
+
+// decommit checks that the proof opens the commitment
+func decommit(proof *Proof, commitment Commitment) error {
 	hash := sha3.New256()
 	if _, err := hash.Write(proof.C.Bytes()); err != nil {
 		return err
@@ -143,5 +220,43 @@ func DecommitVerify(proof *Proof, commitment Commitment, curve *curves.Curve, ba
 	if subtle.ConstantTimeCompare(hash.Sum(nil), commitment) != 1 {
 		return fmt.Errorf("initial hash decommitment failed")
 	}
-	return Verify(proof, curve, basepoint, uniqueSessionId)
+	return nil
+}
+
+// proofFields names the fields of a Proof in its encodings
+var proofFields = codec.Fields{
+	Scalars: []string{"c", "s"},
+	Points:  []string{"statement", "k"},
+}
+
+// MarshalBinary encodes the proof as the curve name, a version byte, the scalars and the compressed points
+func (proof *Proof) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinary(proofFields, []curves.Scalar{proof.C, proof.S}, []curves.Point{proof.Statement, proof.K})
+}
+
+// UnmarshalBinary decodes the output of MarshalBinary, rejecting non-canonical scalars and identity or invalid points
+func (proof *Proof) UnmarshalBinary(data []byte) error {
+	_, scalars, points, err := codec.UnmarshalBinary(proofFields, data)
+	if err != nil {
+		return err
+	}
+	proof.C, proof.S = scalars[0], scalars[1]
+	proof.Statement, proof.K = points[0], points[1]
+	return nil
+}
+
+// MarshalJSON encodes the proof as a JSON object holding the version, the curve name and the hex encoded fields
+func (proof *Proof) MarshalJSON() ([]byte, error) {
+	return codec.MarshalJSON(proofFields, []curves.Scalar{proof.C, proof.S}, []curves.Point{proof.Statement, proof.K})
+}
+
+// UnmarshalJSON decodes the output of MarshalJSON with the same checks as UnmarshalBinary
+func (proof *Proof) UnmarshalJSON(data []byte) error {
+	_, scalars, points, err := codec.UnmarshalJSON(proofFields, data)
+	if err != nil {
+		return err
+	}
+	proof.C, proof.S = scalars[0], scalars[1]
+	proof.Statement, proof.K = points[0], points[1]
+	return nil
 }
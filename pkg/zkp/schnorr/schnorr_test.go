@@ -2,19 +2,24 @@ package schnorr
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/sha3"
 
 	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/zkp/zkptest"
 )
 
 func TestZKPOverMultipleCurves(t *testing.T) {
 	curveInstances := []*curves.Curve{
 		curves.K256(),
 		curves.P256(),
+		curves.P384(),
+		curves.P521(),
 		// TODO: the code fails on the following curves. Investigate if this is expected.
 		// curves.PALLAS(),
 		// curves.BLS12377G1(),
@@ -35,3 +40,130 @@ func TestZKPOverMultipleCurves(t *testing.T) {
 		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
 	}
 }
+
+func TestProofEncoding(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+		curves.SM2(),
+	}
+	for i, curve := range curveInstances {
+		uniqueSessionId := sha3.New256().Sum([]byte("random seed"))
+		prover := NewProver(curve, nil, uniqueSessionId)
+		proof, err := prover.Prove(curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+
+		data, err := proof.MarshalBinary()
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		require.True(t, strings.HasPrefix(string(data), curve.Name+":"))
+		decoded := &Proof{}
+		require.NoError(t, decoded.UnmarshalBinary(data), fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, Verify(decoded, curve, nil, uniqueSessionId), fmt.Sprintf("failed in curve %d", i))
+		require.Error(t, decoded.UnmarshalBinary(data[:len(data)-1]))
+
+		data, err = json.Marshal(proof)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		decoded = &Proof{}
+		require.NoError(t, json.Unmarshal(data, decoded), fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, Verify(decoded, curve, nil, uniqueSessionId), fmt.Sprintf("failed in curve %d", i))
+	}
+}
+
+func TestZKPWithTranscript(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+		curves.P384(),
+		curves.ED25519(),
+	}
+	for i, curve := range curveInstances {
+		prover := NewProverWithTranscript(curve, nil, zkptest.NewTranscript("protocol"))
+		proof, commitment, err := prover.ProveCommit(curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+
+		require.NoError(t, DecommitVerifyWithTranscript(proof, commitment, curve, nil, zkptest.NewTranscript("protocol")), fmt.Sprintf("failed in curve %d", i))
+		require.Error(t, VerifyWithTranscript(proof, curve, nil, zkptest.NewTranscript("other protocol")))
+		require.Error(t, Verify(proof, curve, nil, sha3.New256().Sum([]byte("random seed"))))
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	for i, curve := range []*curves.Curve{curves.K256(), curves.P256(), curves.SM2()} {
+		proofs, sessionIds := batch(t, curve, 20)
+		require.NoError(t, BatchVerify(proofs, curve, nil, sessionIds), fmt.Sprintf("failed in curve %d", i))
+
+		// decoded proofs keep K and stay in the batch
+		data, err := proofs[3].MarshalBinary()
+		require.NoError(t, err)
+		decoded := &Proof{}
+		require.NoError(t, decoded.UnmarshalBinary(data))
+		require.True(t, decoded.K.Equal(proofs[3].K))
+		proofs[3] = decoded
+		require.NoError(t, BatchVerify(proofs, curve, nil, sessionIds), fmt.Sprintf("failed in curve %d", i))
+
+		proofs[11].Statement = proofs[12].Statement
+		err = BatchVerify(proofs, curve, nil, sessionIds)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "proof 11")
+
+		proofs[11] = nil
+		require.Error(t, BatchVerify(proofs, curve, nil, sessionIds))
+	}
+}
+
+func TestDecommitBatchVerify(t *testing.T) {
+	curve := curves.SM2()
+	proofs := make([]*Proof, 10)
+	commitments := make([]Commitment, len(proofs))
+	sessionIds := make([][]byte, len(proofs))
+	for i := range proofs {
+		var err error
+		sessionIds[i] = sha3.New256().Sum([]byte(fmt.Sprintf("random seed %d", i)))
+		proofs[i], commitments[i], err = NewProver(curve, nil, sessionIds[i]).ProveCommit(curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err)
+	}
+	require.NoError(t, DecommitBatchVerify(proofs, commitments, curve, nil, sessionIds))
+	require.Error(t, DecommitBatchVerify(proofs, commitments[1:], curve, nil, sessionIds))
+
+	commitments[4], commitments[5] = commitments[5], commitments[4]
+	err := DecommitBatchVerify(proofs, commitments, curve, nil, sessionIds)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "proof 4")
+}
+
+// batch returns n proofs, each for its own session id
+func batch(t testing.TB, curve *curves.Curve, n int) ([]*Proof, [][]byte) {
+	proofs := make([]*Proof, n)
+	sessionIds := make([][]byte, n)
+	for i := range proofs {
+		sessionIds[i] = sha3.New256().Sum([]byte(fmt.Sprintf("random seed %d", i)))
+		proof, err := NewProver(curve, nil, sessionIds[i]).Prove(curve.Scalar.Random(rand.Reader))
+		require.NoError(t, err)
+		proofs[i] = proof
+	}
+	return proofs, sessionIds
+}
+
+func BenchmarkVerify50(b *testing.B) {
+	curve := curves.K256()
+	proofs, sessionIds := batch(b, curve, 50)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i, proof := range proofs {
+			if err := Verify(proof, curve, nil, sessionIds[i]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkBatchVerify50(b *testing.B) {
+	curve := curves.K256()
+	proofs, sessionIds := batch(b, curve, 50)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if err := BatchVerify(proofs, curve, nil, sessionIds); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package shuffle proves that a vector of elgamalexp ciphertexts is a permutation and re-randomization of another one,
+// without revealing the permutation, with the proof of shuffle of Terelius and Wikström
+// https://www.wikstrom.se/wp-content/uploads/tw10.pdf in the form that the Verificatum mix-net implements
+// https://www.verificatum.org/files/vmnv-3.1.0.pdf.
+//
+// The prover commits to the permutation matrix column by column, a_j = r_j*G + H_{pi^-1(j)}, and receives a random
+// vector e from the transcript. The matrix is a permutation matrix if its rows sum to one and the product of the
+// entries of e permuted by it is the product of the entries of e, which the prover shows with the chain of commitments
+// b_i = beta_i*G + e_{pi(i)}*b_{i-1}. Together with the equation between the inputs and outputs weighted by e, these
+// are linear relations that a single sigma.Relation proves.
+package shuffle
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/gtank/merlin"
+	"github.com/pkg/errors"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/elgamalexp"
+	"github.com/coinbase/kryptology/pkg/zkp/sigma"
+)
+
+// protocolLabel separates the shuffle proofs from the other proofs in a transcript
+const protocolLabel = "Coinbase_ZKP_Shuffle"
+
+// Prover shuffles ciphertexts encrypted under the encryption key T and proves it
+type Prover struct {
+	curve           *curves.Curve
+	basePoint       curves.Point
+	T               curves.Point
+	uniqueSessionId []byte
+	transcript      *merlin.Transcript
+}
+
+// Proof is the proof that the outputs of a shuffle are the re-randomized inputs in another order
+type Proof struct {
+	// PermutationCommitments are the commitments a_j to the columns of the permutation matrix
+	PermutationCommitments []curves.Point
+	// ChainCommitments are the commitments b_i to the partial products of the permuted challenges
+	ChainCommitments []curves.Point
+	// Sigma is the proof of the linear relations between the commitments and the ciphertexts
+	Sigma *sigma.Proof
+}
+
+// NewProver returns a prover whose proofs are bound to the session id. A nil basePoint is the generator of the curve.
+func NewProver(curve *curves.Curve, basePoint curves.Point, T curves.Point, uniqueSessionId []byte) (*Prover, error) {
+	if basePoint == nil {
+		basePoint = curve.NewGeneratorPoint()
+	}
+	if T == nil {
+		return nil, fmt.Errorf("encryption key missing")
+	}
+	return &Prover{
+		curve:           curve,
+		basePoint:       basePoint,
+		T:               T,
+		uniqueSessionId: uniqueSessionId,
+	}, nil
+}
+
+// NewProverWithTranscript is NewProver with the challenges drawn from the transcript, which advances with each proof,
+// so the verifier must check the proofs in the same order with VerifyWithTranscript
+func NewProverWithTranscript(curve *curves.Curve, basePoint curves.Point, T curves.Point, transcript *merlin.Transcript) (*Prover, error) {
+	if transcript == nil {
+		return nil, fmt.Errorf("transcript missing")
+	}
+	prover, err := NewProver(curve, basePoint, T, nil)
+	if err != nil {
+		return nil, err
+	}
+	prover.transcript = transcript
+	return prover, nil
+}
+
+// Shuffle permutes and re-randomizes the inputs at random and proves it
+func (p *Prover) Shuffle(inputs []*elgamalexp.Ciphertext) ([]*elgamalexp.Ciphertext, *Proof, error) {
+	permutation := make([]int, len(inputs))
+	for i := range permutation {
+		permutation[i] = i
+	}
+	// Fisher-Yates
+	for i := len(permutation) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "drawing the permutation")
+		}
+		permutation[i], permutation[j.Int64()] = permutation[j.Int64()], permutation[i]
+	}
+	randomness := make([]curves.Scalar, len(inputs))
+	for i := range randomness {
+		randomness[i] = p.curve.Scalar.Random(rand.Reader)
+	}
+	return p.Prove(inputs, permutation, randomness)
+}
+
+// Prove computes the outputs outputs[i] = inputs[permutation[i]] re-randomized with randomness[i] and proves that they
+// are a shuffle of the inputs
+func (p *Prover) Prove(inputs []*elgamalexp.Ciphertext, permutation []int, randomness []curves.Scalar) ([]*elgamalexp.Ciphertext, *Proof, error) {
+	n := len(inputs)
+	if n == 0 {
+		return nil, nil, fmt.Errorf("no ciphertexts to shuffle")
+	}
+	if len(permutation) != n || len(randomness) != n {
+		return nil, nil, fmt.Errorf("expected a permutation and randomness of %d elements", n)
+	}
+	if err := checkCiphertexts(inputs); err != nil {
+		return nil, nil, errors.Wrap(err, "inputs")
+	}
+	inverse := make([]int, n)
+	for i := range inverse {
+		inverse[i] = -1
+	}
+	for i, j := range permutation {
+		if j < 0 || j >= n || inverse[j] >= 0 {
+			return nil, nil, fmt.Errorf("invalid permutation")
+		}
+		if randomness[i] == nil {
+			return nil, nil, fmt.Errorf("randomness %d missing", i)
+		}
+		inverse[j] = i
+	}
+
+	encryptor := elgamalexp.NewEncryptor(p.curve, p.basePoint, p.T)
+	one := p.curve.Scalar.One()
+	outputs := make([]*elgamalexp.Ciphertext, n)
+	for i, j := range permutation {
+		outputs[i] = encryptor.ReRandomize(inputs[j], one, randomness[i])
+	}
+
+	transcript := p.transcript
+	if transcript == nil {
+		transcript = sessionTranscript(p.uniqueSessionId)
+	}
+	g := p.curve.NewGeneratorPoint()
+	generators := independentGenerators(p.curve, n)
+	proof := &Proof{
+		PermutationCommitments: make([]curves.Point, n),
+		ChainCommitments:       make([]curves.Point, n),
+	}
+	r := make([]curves.Scalar, n)
+	for j := range r {
+		r[j] = p.curve.Scalar.Random(rand.Reader)
+		proof.PermutationCommitments[j] = g.Mul(r[j]).Add(generators[1+inverse[j]])
+	}
+	e := challenges(p.curve, transcript, p.basePoint, p.T, inputs, outputs, proof.PermutationCommitments)
+
+	// witnesses, numbered as in statement
+	witness := make([]curves.Scalar, 2*n+4)
+	rSum, rBar, betaHat, rhoBar := p.curve.Scalar.Zero(), p.curve.Scalar.Zero(), p.curve.Scalar.Zero(), p.curve.Scalar.Zero()
+	previous := generators[0]
+	for i, j := range permutation {
+		witness[i] = e[j]
+		witness[n+i] = p.curve.Scalar.Random(rand.Reader)
+		proof.ChainCommitments[i] = g.Mul(witness[n+i]).Add(previous.Mul(e[j]))
+		previous = proof.ChainCommitments[i]
+		betaHat = witness[n+i].Add(e[j].Mul(betaHat))
+		rhoBar = rhoBar.Add(e[j].Mul(randomness[i]))
+	}
+	for j := range r {
+		rSum = rSum.Add(r[j])
+		rBar = rBar.Add(e[j].Mul(r[j]))
+	}
+	witness[2*n], witness[2*n+1], witness[2*n+2], witness[2*n+3] = rSum, rBar, betaHat, rhoBar
+
+	relation := statement(p.curve, p.basePoint, p.T, generators, inputs, outputs, proof, e)
+	var err error
+	proof.Sigma, err = sigma.ProveTranscript(p.curve, protocolLabel, relation, witness, transcript)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "shuffle proof")
+	}
+	return outputs, proof, nil
+}
+
+// Verify checks that the outputs are a shuffle of the inputs encrypted under T, bound to the session id
+func Verify(proof *Proof, curve *curves.Curve, basePoint curves.Point, T curves.Point, inputs []*elgamalexp.Ciphertext, outputs []*elgamalexp.Ciphertext, uniqueSessionId []byte) error {
+	return VerifyWithTranscript(proof, curve, basePoint, T, inputs, outputs, sessionTranscript(uniqueSessionId))
+}
+
+// VerifyWithTranscript verifies a proof of a prover created with NewProverWithTranscript, advancing the transcript in
+// the same way
+func VerifyWithTranscript(proof *Proof, curve *curves.Curve, basePoint curves.Point, T curves.Point, inputs []*elgamalexp.Ciphertext, outputs []*elgamalexp.Ciphertext, transcript *merlin.Transcript) error {
+	if basePoint == nil {
+		basePoint = curve.NewGeneratorPoint()
+	}
+	if T == nil {
+		return fmt.Errorf("encryption key missing")
+	}
+	if transcript == nil {
+		return fmt.Errorf("transcript missing")
+	}
+	n := len(inputs)
+	if n == 0 || len(outputs) != n {
+		return fmt.Errorf("expected as many outputs as inputs")
+	}
+	if err := checkCiphertexts(inputs); err != nil {
+		return errors.Wrap(err, "inputs")
+	}
+	if err := checkCiphertexts(outputs); err != nil {
+		return errors.Wrap(err, "outputs")
+	}
+	if proof == nil || proof.Sigma == nil || len(proof.PermutationCommitments) != n || len(proof.ChainCommitments) != n {
+		return fmt.Errorf("shuffle proof missing")
+	}
+	for i := 0; i < n; i++ {
+		if proof.PermutationCommitments[i] == nil || proof.ChainCommitments[i] == nil {
+			return fmt.Errorf("shuffle proof missing")
+		}
+	}
+
+	generators := independentGenerators(curve, n)
+	e := challenges(curve, transcript, basePoint, T, inputs, outputs, proof.PermutationCommitments)
+	relation := statement(curve, basePoint, T, generators, inputs, outputs, proof, e)
+	if err := sigma.VerifyTranscript(curve, protocolLabel, relation, proof.Sigma, transcript); err != nil {
+		return errors.Wrap(err, "shuffle verification failed")
+	}
+	return nil
+}
+
+// statement is the knowledge of the permuted challenges e'_i = e_{pi(i)} (witnesses 0 to n-1), of the randomness
+// beta_i of the chain commitments (witnesses n to 2n-1), of sum_j r_j (witness 2n), of sum_j e_j*r_j (witness 2n+1),
+// of the randomness of the last chain commitment (witness 2n+2) and of the combined re-randomization
+// rho = sum_i e'_i*rho_i (witness 2n+3) such that
+//
+//	sum_j a_j - sum_i H_i = (sum_j r_j)*G
+//	sum_j e_j*a_j = (sum_j e_j*r_j)*G + sum_i e'_i*H_i
+//	b_i = beta_i*G + e'_i*b_{i-1}, with b_0 = H_0
+//	b_n - (prod_j e_j)*H_0 = betaHat*G
+//	sum_j e_j*U_j = sum_i e'_i*U'_i - rho*basePoint
+//	sum_j e_j*V_j = sum_i e'_i*V'_i - rho*T
+func statement(curve *curves.Curve, basePoint curves.Point, T curves.Point, generators []curves.Point, inputs []*elgamalexp.Ciphertext, outputs []*elgamalexp.Ciphertext, proof *Proof, e []curves.Scalar) *sigma.Relation {
+	n := len(inputs)
+	g := curve.NewGeneratorPoint()
+	a, b := proof.PermutationCommitments, proof.ChainCommitments
+
+	rowSum := curve.NewIdentityPoint()
+	for j := 0; j < n; j++ {
+		rowSum = rowSum.Add(a[j]).Sub(generators[1+j])
+	}
+	product := curve.Scalar.One()
+	for j := 0; j < n; j++ {
+		product = product.Mul(e[j])
+	}
+	us, vs := make([]curves.Point, n), make([]curves.Point, n)
+	for j := 0; j < n; j++ {
+		us[j], vs[j] = inputs[j].U, inputs[j].V
+	}
+
+	permuted := &sigma.Equation{Image: sumOfProducts(curve, a, e), Terms: []sigma.Term{{Base: g, Witness: 2*n + 1}}}
+	u := &sigma.Equation{Image: sumOfProducts(curve, us, e), Terms: []sigma.Term{{Base: basePoint.Neg(), Witness: 2*n + 3}}}
+	v := &sigma.Equation{Image: sumOfProducts(curve, vs, e), Terms: []sigma.Term{{Base: T.Neg(), Witness: 2*n + 3}}}
+	equations := []*sigma.Equation{
+		{Image: rowSum, Terms: []sigma.Term{{Base: g, Witness: 2 * n}}},
+		permuted,
+	}
+	previous := generators[0]
+	for i := 0; i < n; i++ {
+		permuted.Terms = append(permuted.Terms, sigma.Term{Base: generators[1+i], Witness: i})
+		u.Terms = append(u.Terms, sigma.Term{Base: outputs[i].U, Witness: i})
+		v.Terms = append(v.Terms, sigma.Term{Base: outputs[i].V, Witness: i})
+		equations = append(equations, &sigma.Equation{Image: b[i], Terms: []sigma.Term{{Base: g, Witness: n + i}, {Base: previous, Witness: i}}})
+		previous = b[i]
+	}
+	equations = append(equations,
+		&sigma.Equation{Image: b[n-1].Sub(generators[0].Mul(product)), Terms: []sigma.Term{{Base: g, Witness: 2*n + 2}}},
+		u, v)
+
+	// the challenge binds the setup and the chain commitments, the rest was appended before e was drawn
+	transcript := append([]curves.Point{g, basePoint, T}, b...)
+	labels := make([]string, len(transcript))
+	labels[0], labels[1], labels[2] = "commitment base", "base point", "encryption key"
+	for i := 3; i < len(labels); i++ {
+		labels[i] = "chain commitment"
+	}
+	return &sigma.Relation{Equations: equations, Transcript: transcript, Labels: labels}
+}
+
+// challenges appends the ciphertexts and the permutation commitments to the transcript and draws the vector e
+func challenges(curve *curves.Curve, transcript *merlin.Transcript, basePoint curves.Point, T curves.Point, inputs []*elgamalexp.Ciphertext, outputs []*elgamalexp.Ciphertext, a []curves.Point) []curves.Scalar {
+	transcript.AppendMessage([]byte("dom-sep"), []byte(protocolLabel))
+	transcript.AppendMessage([]byte("curve"), []byte(curve.Name))
+	transcript.AppendMessage([]byte("base point"), basePoint.ToAffineCompressed())
+	transcript.AppendMessage([]byte("encryption key"), T.ToAffineCompressed())
+	for _, ct := range inputs {
+		transcript.AppendMessage([]byte("input U"), ct.U.ToAffineCompressed())
+		transcript.AppendMessage([]byte("input V"), ct.V.ToAffineCompressed())
+	}
+	for _, ct := range outputs {
+		transcript.AppendMessage([]byte("output U"), ct.U.ToAffineCompressed())
+		transcript.AppendMessage([]byte("output V"), ct.V.ToAffineCompressed())
+	}
+	for _, point := range a {
+		transcript.AppendMessage([]byte("permutation commitment"), point.ToAffineCompressed())
+	}
+	e := make([]curves.Scalar, len(a))
+	for j := range e {
+		// a 64 byte extraction is reduced with negligible bias
+		e[j], _ = curve.Scalar.SetBytesWide(transcript.ExtractBytes([]byte("e"), 64))
+	}
+	return e
+}
+
+// independentGenerators returns the generators H_0, ..., H_n hashed from the protocol label, whose discrete logs
+// with respect to each other and to G nobody knows
+func independentGenerators(curve *curves.Curve, n int) []curves.Point {
+	generators := make([]curves.Point, n+1)
+	for i := range generators {
+		generators[i] = curve.Point.Hash([]byte(fmt.Sprintf("%s generator %d", protocolLabel, i)))
+	}
+	return generators
+}
+
+// sessionTranscript returns the transcript of a proof bound to the session id
+func sessionTranscript(uniqueSessionId []byte) *merlin.Transcript {
+	transcript := merlin.NewTranscript(protocolLabel)
+	transcript.AppendMessage([]byte("session id"), uniqueSessionId)
+	return transcript
+}
+
+// sumOfProducts returns sum_i scalars[i]*points[i], with a multi-scalar multiplication when the curve has one
+func sumOfProducts(curve *curves.Curve, points []curves.Point, scalars []curves.Scalar) curves.Point {
+	if sum := curve.Point.SumOfProducts(points, scalars); sum != nil {
+		return sum
+	}
+	sum := curve.NewIdentityPoint()
+	for i, point := range points {
+		sum = sum.Add(point.Mul(scalars[i]))
+	}
+	return sum
+}
+
+func checkCiphertexts(ciphertexts []*elgamalexp.Ciphertext) error {
+	for i, ct := range ciphertexts {
+		if ct == nil || ct.U == nil || ct.V == nil {
+			return fmt.Errorf("ciphertext %d missing", i)
+		}
+	}
+	return nil
+}
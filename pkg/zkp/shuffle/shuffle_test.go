@@ -0,0 +1,114 @@
+package shuffle
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/gtank/merlin"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+	"github.com/coinbase/kryptology/pkg/elgamalexp"
+)
+
+func TestShuffleOverMultipleCurves(t *testing.T) {
+	curveInstances := []*curves.Curve{
+		curves.K256(),
+		curves.P256(),
+	}
+	for i, curve := range curveInstances {
+		uniqueSessionId := sha3.New256().Sum([]byte("random seed"))
+		basePoint := curve.Point.Random(rand.Reader)
+		T, d := elgamalexp.KeyGen(curve, basePoint)
+		inputs := ballots(curve, basePoint, T, 8)
+
+		prover, err := NewProver(curve, basePoint, T, uniqueSessionId)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		outputs, proof, err := prover.Shuffle(inputs)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, Verify(proof, curve, basePoint, T, inputs, outputs, uniqueSessionId), fmt.Sprintf("failed in curve %d", i))
+
+		// the outputs decrypt to the same messages
+		table, err := elgamalexp.NewDLogTable(curve, basePoint, 8)
+		require.NoError(t, err)
+		semiDecryptor := elgamalexp.NewSemiDecryptor(curve, basePoint, T, d)
+		seen := make(map[uint64]bool)
+		for _, output := range outputs {
+			m, err := table.Solve(semiDecryptor.SemiDecrypt(output))
+			require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+			seen[m] = true
+		}
+		require.Len(t, seen, 8)
+
+		// a prover can be reused
+		outputs2, proof2, err := prover.Shuffle(outputs)
+		require.NoError(t, err, fmt.Sprintf("failed in curve %d", i))
+		require.NoError(t, Verify(proof2, curve, basePoint, T, outputs, outputs2, uniqueSessionId), fmt.Sprintf("failed in curve %d", i))
+
+		require.Error(t, Verify(proof, curve, basePoint, T, inputs, outputs, []byte("other session")))
+		require.Error(t, Verify(proof, curve, basePoint, T, outputs, inputs, uniqueSessionId))
+		require.Error(t, Verify(proof2, curve, basePoint, T, inputs, outputs, uniqueSessionId))
+	}
+}
+
+func TestShuffleRejectsTampering(t *testing.T) {
+	curve := curves.K256()
+	uniqueSessionId := sha3.New256().Sum([]byte("random seed"))
+	T, _ := elgamalexp.KeyGen(curve, nil)
+	inputs := ballots(curve, nil, T, 5)
+	prover, err := NewProver(curve, nil, T, uniqueSessionId)
+	require.NoError(t, err)
+
+	// a fixed permutation, so that the outputs can be tampered with knowingly
+	randomness := make([]curves.Scalar, 5)
+	for i := range randomness {
+		randomness[i] = curve.Scalar.Random(rand.Reader)
+	}
+	outputs, proof, err := prover.Prove(inputs, []int{3, 0, 4, 1, 2}, randomness)
+	require.NoError(t, err)
+	require.NoError(t, Verify(proof, curve, nil, T, inputs, outputs, uniqueSessionId))
+
+	// replacing an output by a new encryption changes its message
+	tampered := append([]*elgamalexp.Ciphertext{}, outputs...)
+	tampered[1] = elgamalexp.NewEncryptor(curve, nil, T).Encrypt(curve.Scalar.New(100), curve.Scalar.Random(rand.Reader))
+	require.Error(t, Verify(proof, curve, nil, T, inputs, tampered, uniqueSessionId))
+
+	// swapping outputs after the fact
+	tampered = append([]*elgamalexp.Ciphertext{}, outputs...)
+	tampered[0], tampered[1] = tampered[1], tampered[0]
+	require.Error(t, Verify(proof, curve, nil, T, inputs, tampered, uniqueSessionId))
+
+	// duplicating an input is not a permutation
+	_, _, err = prover.Prove(inputs, []int{3, 3, 4, 1, 2}, randomness)
+	require.Error(t, err)
+
+	require.Error(t, Verify(proof, curve, nil, T, inputs, outputs[:4], uniqueSessionId))
+	require.Error(t, Verify(&Proof{}, curve, nil, T, inputs, outputs, uniqueSessionId))
+	require.Error(t, Verify(nil, curve, nil, T, inputs, outputs, uniqueSessionId))
+}
+
+func TestShuffleWithTranscript(t *testing.T) {
+	curve := curves.P256()
+	T, _ := elgamalexp.KeyGen(curve, nil)
+	inputs := ballots(curve, nil, T, 4)
+
+	prover, err := NewProverWithTranscript(curve, nil, T, merlin.NewTranscript("mix-net"))
+	require.NoError(t, err)
+	outputs, proof, err := prover.Shuffle(inputs)
+	require.NoError(t, err)
+
+	require.NoError(t, VerifyWithTranscript(proof, curve, nil, T, inputs, outputs, merlin.NewTranscript("mix-net")))
+	require.Error(t, VerifyWithTranscript(proof, curve, nil, T, inputs, outputs, merlin.NewTranscript("other protocol")))
+}
+
+// ballots encrypts the messages 0 to n-1
+func ballots(curve *curves.Curve, basePoint curves.Point, T curves.Point, n int) []*elgamalexp.Ciphertext {
+	encryptor := elgamalexp.NewEncryptor(curve, basePoint, T)
+	ciphertexts := make([]*elgamalexp.Ciphertext, n)
+	for i := range ciphertexts {
+		ciphertexts[i] = encryptor.Encrypt(curve.Scalar.New(i), curve.Scalar.Random(rand.Reader))
+	}
+	return ciphertexts
+}
@@ -0,0 +1,702 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package sigma implements non-interactive sigma protocols for linear relations between points, so that a new proof
+// is described by its equations instead of hand written commit, challenge and response code.
+//
+// A Relation is a set of equations Image = sum_i Base_i * w_i over secret scalars w_i, which are numbered across the
+// whole statement. Its equations hold together, with the same witnesses, so a Relation is the AND composition of its
+// equations. And and Or compose statements, the latter with the technique of Cramer, Damgård and Schoenmakers
+// https://link.springer.com/content/pdf/10.1007/3-540-48658-5_19.pdf, in which the prover simulates the branches it
+// does not know the witnesses of.
+//
+// The challenge is derived with Fiat-Shamir in one of two ways. Prove and Verify take the SHA3-256 digest of the
+// session id, the public points of the statement and the commitments, and read it with curve.Scalar.SetBytes like the
+// proofs of pkg/zkp always did. ProveTranscript and VerifyTranscript append a protocol label, the curve name, the
+// labeled public points and the commitments to a merlin transcript and extract the challenge from it, so that a proof
+// is bound to everything that the enclosing protocol appended before it and cannot be replayed in another protocol.
+package sigma
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/gtank/merlin"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+)
+
+// Term is a base point multiplied by the witness with the given index
+type Term struct {
+	Base    curves.Point
+	Witness int
+}
+
+// Equation states that Image is the sum of the terms
+type Equation struct {
+	Image curves.Point
+	Terms []Term
+}
+
+// Proof is the challenge and the responses of a statement, in the order in which the statement lists them.
+// A Relation has one response for each witness it uses, by increasing index. And concatenates the responses of its
+// parts. Or lists the challenges of all its branches but the last, followed by the responses of every branch.
+//
+// Commitments are the commitments of the prover. Verify recomputes them and ignores this field, so encodings can
+// leave them out, but BatchVerify needs them to combine the proofs of Relations.
+type Proof struct {
+	C           curves.Scalar
+	Responses   []curves.Scalar
+	Commitments []curves.Point
+}
+
+// Statement is a relation between public points and secret scalars whose knowledge a Proof shows.
+// It is implemented by Relation and by the compositions that And and Or return.
+type Statement interface {
+	// Public returns the points that the challenge binds, in order
+	Public() []curves.Point
+
+	// labels names the points of Public in a transcript
+	labels() []string
+	// check validates the statement for a witness vector of the given length
+	check(witnesses int) error
+	// known reports whether the prover knows enough of the witness to prove the statement
+	known(witness []curves.Scalar) bool
+	// commit returns the commitments of the statement and the function that answers a challenge
+	commit(curve *curves.Curve, witness []curves.Scalar) ([]curves.Point, responder)
+	// simulate returns commitments and responses that are valid for the challenge c
+	simulate(curve *curves.Curve, c curves.Scalar) ([]curves.Point, []curves.Scalar)
+	// recompute derives the commitments from the challenge and the responses, and returns the responses left over
+	recompute(c curves.Scalar, responses []curves.Scalar) ([]curves.Point, []curves.Scalar, error)
+}
+
+// responder answers a challenge once the commitments are fixed
+type responder func(c curves.Scalar) []curves.Scalar
+
+// oracle derives the challenge of a proof from the statement and the commitments
+type oracle func(statement Statement, commitments []curves.Point) (curves.Scalar, error)
+
+// Prove proves knowledge of the witness of the statement, bound to the session id. witness[i] is the scalar with
+// index i, or nil if the prover does not know it, which is only allowed in the branches of an Or that it simulates.
+// Prove does not check that the witness satisfies the statement.
+func Prove(curve *curves.Curve, statement Statement, witness []curves.Scalar, sessionId []byte) (*Proof, error) {
+	return prove(curve, statement, witness, sessionOracle(curve, sessionId))
+}
+
+// Verify checks the proof of the statement, bound to the session id
+func Verify(curve *curves.Curve, statement Statement, proof *Proof, sessionId []byte) error {
+	return verify(curve, statement, proof, sessionOracle(curve, sessionId))
+}
+
+// ProveTranscript is Prove with the challenge extracted from the transcript after appending the protocol label, the
+// public points and the commitments. The transcript advances, so the verifier must run VerifyTranscript on a
+// transcript in the same state.
+func ProveTranscript(curve *curves.Curve, label string, statement Statement, witness []curves.Scalar, transcript *merlin.Transcript) (*Proof, error) {
+	if transcript == nil {
+		return nil, fmt.Errorf("transcript missing")
+	}
+	return prove(curve, statement, witness, transcriptOracle(curve, label, transcript))
+}
+
+// VerifyTranscript checks a proof of ProveTranscript, advancing the transcript in the same way
+func VerifyTranscript(curve *curves.Curve, label string, statement Statement, proof *Proof, transcript *merlin.Transcript) error {
+	if transcript == nil {
+		return fmt.Errorf("transcript missing")
+	}
+	return verify(curve, statement, proof, transcriptOracle(curve, label, transcript))
+}
+
+func prove(curve *curves.Curve, statement Statement, witness []curves.Scalar, challenge oracle) (*Proof, error) {
+	if curve == nil || statement == nil {
+		return nil, fmt.Errorf("curve and statement must be set")
+	}
+	if err := statement.check(len(witness)); err != nil {
+		return nil, err
+	}
+	if !statement.known(witness) {
+		return nil, fmt.Errorf("the witness does not cover the statement")
+	}
+	commitments, respond := statement.commit(curve, witness)
+	c, err := challenge(statement, commitments)
+	if err != nil {
+		return nil, err
+	}
+	return &Proof{C: c, Responses: respond(c), Commitments: commitments}, nil
+}
+
+func verify(curve *curves.Curve, statement Statement, proof *Proof, challenge oracle) error {
+	if curve == nil || statement == nil {
+		return fmt.Errorf("curve and statement must be set")
+	}
+	if proof == nil || proof.C == nil {
+		return fmt.Errorf("proof missing")
+	}
+	for _, z := range proof.Responses {
+		if z == nil {
+			return fmt.Errorf("response missing")
+		}
+	}
+	if err := statement.check(-1); err != nil {
+		return err
+	}
+	commitments, rest, err := statement.recompute(proof.C, proof.Responses)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return fmt.Errorf("expected %d responses, got %d", len(proof.Responses)-len(rest), len(proof.Responses))
+	}
+	c, err := challenge(statement, commitments)
+	if err != nil {
+		return err
+	}
+	if c.Cmp(proof.C) != 0 {
+		return fmt.Errorf("sigma protocol verification failed")
+	}
+	return nil
+}
+
+// BatchVerify checks proofs[i] of statements[i] bound to sessionIds[i] like Verify, but with a single multi-scalar
+// multiplication for all of them: the equations of the proofs that carry their commitments are combined with random
+// weights, which holds for invalid proofs with negligible probability. The other proofs are verified one by one.
+// If the combination fails, BatchVerify verifies the proofs one by one to report the first invalid one.
+func BatchVerify(curve *curves.Curve, statements []*Relation, proofs []*Proof, sessionIds [][]byte) error {
+	if curve == nil {
+		return fmt.Errorf("curve must be set")
+	}
+	if len(statements) != len(proofs) || len(sessionIds) != len(proofs) {
+		return fmt.Errorf("expected as many statements and session ids as proofs")
+	}
+	// the distinct bases are added up once, since the proofs typically share them
+	var points []curves.Point
+	var scalars []curves.Scalar
+	bases := make(map[string]int)
+	add := func(p curves.Point, s curves.Scalar) {
+		points = append(points, p)
+		scalars = append(scalars, s)
+	}
+	addBase := func(p curves.Point, s curves.Scalar) {
+		key := string(p.ToAffineCompressed())
+		if i, ok := bases[key]; ok {
+			scalars[i] = scalars[i].Add(s)
+			return
+		}
+		bases[key] = len(points)
+		add(p, s)
+	}
+
+	var individually []int
+	for i, proof := range proofs {
+		statement := statements[i]
+		if statement == nil || !batchable(statement, proof) {
+			individually = append(individually, i)
+			continue
+		}
+		// the challenge must match the commitments that the proof carries
+		c, err := sessionOracle(curve, sessionIds[i])(statement, proof.Commitments)
+		if err != nil || c.Cmp(proof.C) != 0 {
+			individually = append(individually, i)
+			continue
+		}
+		z := make(map[int]curves.Scalar, len(proof.Responses))
+		for j, w := range statement.witnesses() {
+			z[w] = proof.Responses[j]
+		}
+		// rho * (sum_i Base_i * z_i - Image * c - Commitment) is the identity for every equation
+		for j, eq := range statement.Equations {
+			rho := curve.Scalar.Random(rand.Reader)
+			for _, term := range eq.Terms {
+				addBase(term.Base, rho.Mul(z[term.Witness]))
+			}
+			add(eq.Image, rho.Mul(proof.C).Neg())
+			add(proof.Commitments[j], rho.Neg())
+		}
+	}
+
+	if len(points) != 0 {
+		sum := curve.Point.SumOfProducts(points, scalars)
+		if sum == nil || !sum.IsIdentity() {
+			// find the invalid proof; curves without SumOfProducts return nil and end up here too
+			individually = individually[:0]
+			for i := range proofs {
+				individually = append(individually, i)
+			}
+		}
+	}
+	for _, i := range individually {
+		if statements[i] == nil {
+			return fmt.Errorf("statement %d missing", i)
+		}
+		if err := Verify(curve, statements[i], proofs[i], sessionIds[i]); err != nil {
+			return errors.Wrapf(err, "proof %d", i)
+		}
+	}
+	return nil
+}
+
+// batchable reports whether a proof of the relation is well formed and carries its commitments
+func batchable(statement *Relation, proof *Proof) bool {
+	if statement.check(-1) != nil || proof == nil || proof.C == nil {
+		return false
+	}
+	if len(proof.Responses) != len(statement.witnesses()) || len(proof.Commitments) != len(statement.Equations) {
+		return false
+	}
+	for _, z := range proof.Responses {
+		if z == nil {
+			return false
+		}
+	}
+	for _, k := range proof.Commitments {
+		if k == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// sessionOracle hashes the session id, the public points and the commitments into a scalar
+func sessionOracle(curve *curves.Curve, sessionId []byte) oracle {
+	// scalars wider than a sha3-256 digest draw their challenge from shake256 instead, so that it covers the field
+	width := len(curve.Scalar.Bytes())
+	return func(statement Statement, commitments []curves.Point) (curves.Scalar, error) {
+		digest, shake := sha3.New256(), sha3.NewShake256()
+		hash := io.Writer(digest)
+		if width > digest.Size() {
+			hash = shake
+		}
+		if _, err := hash.Write(sessionId); err != nil {
+			return nil, errors.Wrap(err, "writing salt to hash in sigma protocol")
+		}
+		points := append(append([]curves.Point{}, statement.Public()...), commitments...)
+		for _, p := range points {
+			if _, err := hash.Write(p.ToAffineCompressed()); err != nil {
+				return nil, errors.Wrap(err, "writing point to hash in sigma protocol")
+			}
+		}
+		if width <= digest.Size() {
+			return challengeFromBytes(curve.Scalar.SetBytes(digest.Sum(nil)))
+		}
+		wide := make([]byte, 2*width)
+		if _, err := shake.Read(wide); err != nil {
+			return nil, errors.Wrap(err, "reading challenge from hash in sigma protocol")
+		}
+		return challengeFromBytes(curve.Scalar.SetBytesWide(wide))
+	}
+}
+
+func challengeFromBytes(c curves.Scalar, err error) (curves.Scalar, error) {
+	if err != nil {
+		return nil, errors.Wrap(err, "generating challenge in sigma protocol")
+	}
+	return c, nil
+}
+
+// transcriptOracle appends the protocol label, the curve name, the labeled public points and the commitments to the
+// transcript and extracts the challenge from it
+func transcriptOracle(curve *curves.Curve, label string, transcript *merlin.Transcript) oracle {
+	return func(statement Statement, commitments []curves.Point) (curves.Scalar, error) {
+		transcript.AppendMessage([]byte("dom-sep"), []byte(label))
+		transcript.AppendMessage([]byte("curve"), []byte(curve.Name))
+		labels := statement.labels()
+		for i, p := range statement.Public() {
+			transcript.AppendMessage([]byte(labels[i]), p.ToAffineCompressed())
+		}
+		for _, p := range commitments {
+			transcript.AppendMessage([]byte("commitment"), p.ToAffineCompressed())
+		}
+		c, err := curve.Scalar.SetBytesWide(transcript.ExtractBytes([]byte("challenge"), 2*len(curve.Scalar.Bytes())))
+		if err != nil {
+			return nil, errors.Wrap(err, "generating challenge in sigma protocol")
+		}
+		return c, nil
+	}
+}
+
+// Relation is a set of equations over the same witnesses
+type Relation struct {
+	Equations []*Equation
+	// Transcript lists the public points that the challenge binds. When it is nil, they are the bases in order of
+	// first appearance followed by the images.
+	Transcript []curves.Point
+	// Labels names the points of Public when they are appended to a merlin transcript. When it is nil, they are all
+	// labeled "public".
+	Labels []string
+}
+
+// Public returns the points that the challenge binds, in order
+func (r *Relation) Public() []curves.Point {
+	if r.Transcript != nil {
+		return r.Transcript
+	}
+	var bases, images []curves.Point
+	for _, eq := range r.Equations {
+		for _, term := range eq.Terms {
+			seen := false
+			for _, b := range bases {
+				if b.Equal(term.Base) {
+					seen = true
+					break
+				}
+			}
+			if !seen {
+				bases = append(bases, term.Base)
+			}
+		}
+		images = append(images, eq.Image)
+	}
+	return append(bases, images...)
+}
+
+func (r *Relation) labels() []string {
+	if r.Labels != nil {
+		return r.Labels
+	}
+	labels := make([]string, len(r.Public()))
+	for i := range labels {
+		labels[i] = "public"
+	}
+	return labels
+}
+
+// witnesses returns the indices of the witnesses that the relation uses, in increasing order
+func (r *Relation) witnesses() []int {
+	used := make(map[int]bool)
+	var indices []int
+	for _, eq := range r.Equations {
+		for _, term := range eq.Terms {
+			if !used[term.Witness] {
+				used[term.Witness] = true
+				indices = append(indices, term.Witness)
+			}
+		}
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+func (r *Relation) check(witnesses int) error {
+	if len(r.Equations) == 0 {
+		return fmt.Errorf("relation without equations")
+	}
+	for i, eq := range r.Equations {
+		if eq == nil || eq.Image == nil {
+			return fmt.Errorf("image of equation %d missing", i)
+		}
+		if len(eq.Terms) == 0 {
+			return fmt.Errorf("equation %d has no terms", i)
+		}
+		for _, term := range eq.Terms {
+			if term.Base == nil {
+				return fmt.Errorf("base of equation %d missing", i)
+			}
+			if term.Witness < 0 || (witnesses >= 0 && term.Witness >= witnesses) {
+				return fmt.Errorf("equation %d uses the unknown witness %d", i, term.Witness)
+			}
+		}
+	}
+	for _, p := range r.Transcript {
+		if p == nil {
+			return fmt.Errorf("transcript point missing")
+		}
+	}
+	if r.Labels != nil && len(r.Labels) != len(r.Public()) {
+		return fmt.Errorf("expected %d labels, got %d", len(r.Public()), len(r.Labels))
+	}
+	return nil
+}
+
+func (r *Relation) known(witness []curves.Scalar) bool {
+	for _, i := range r.witnesses() {
+		if witness[i] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Relation) commit(curve *curves.Curve, witness []curves.Scalar) ([]curves.Point, responder) {
+	indices := r.witnesses()
+	nonces := make(map[int]curves.Scalar, len(indices))
+	for _, i := range indices {
+		nonces[i] = curve.Scalar.Random(rand.Reader)
+	}
+	commitments := make([]curves.Point, len(r.Equations))
+	for j, eq := range r.Equations {
+		commitments[j] = r.combine(eq, nonces)
+	}
+	return commitments, func(c curves.Scalar) []curves.Scalar {
+		responses := make([]curves.Scalar, len(indices))
+		for j, i := range indices {
+			responses[j] = c.Mul(witness[i]).Add(nonces[i])
+		}
+		return responses
+	}
+}
+
+func (r *Relation) simulate(curve *curves.Curve, c curves.Scalar) ([]curves.Point, []curves.Scalar) {
+	indices := r.witnesses()
+	responses := make([]curves.Scalar, len(indices))
+	for j := range indices {
+		responses[j] = curve.Scalar.Random(rand.Reader)
+	}
+	commitments, _, _ := r.recompute(c, responses)
+	return commitments, responses
+}
+
+func (r *Relation) recompute(c curves.Scalar, responses []curves.Scalar) ([]curves.Point, []curves.Scalar, error) {
+	indices := r.witnesses()
+	if len(responses) < len(indices) {
+		return nil, nil, fmt.Errorf("not enough responses")
+	}
+	z := make(map[int]curves.Scalar, len(indices))
+	for j, i := range indices {
+		z[i] = responses[j]
+	}
+	commitments := make([]curves.Point, len(r.Equations))
+	for j, eq := range r.Equations {
+		// sum_i Base_i * z_i - Image * c
+		commitments[j] = r.combine(eq, z).Sub(eq.Image.Mul(c))
+	}
+	return commitments, responses[len(indices):], nil
+}
+
+// combine returns the sum of the bases of the equation multiplied by the given scalars
+func (r *Relation) combine(eq *Equation, scalars map[int]curves.Scalar) curves.Point {
+	sum := eq.Image.Identity()
+	for _, term := range eq.Terms {
+		sum = sum.Add(term.Base.Mul(scalars[term.Witness]))
+	}
+	return sum
+}
+
+// and is the conjunction of statements
+type and struct {
+	parts []Statement
+}
+
+// And returns the statement that all the given statements hold. Witnesses that the parts share are only proven equal
+// within a Relation, so equations over the same witnesses belong in a single Relation.
+func And(statements ...Statement) Statement {
+	return &and{parts: statements}
+}
+
+// Public returns the points that the challenge binds, in order
+func (a *and) Public() []curves.Point {
+	return public(a.parts)
+}
+
+func (a *and) labels() []string {
+	return labels(a.parts)
+}
+
+func (a *and) check(witnesses int) error {
+	return check(a.parts, witnesses)
+}
+
+func (a *and) known(witness []curves.Scalar) bool {
+	for _, part := range a.parts {
+		if !part.known(witness) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *and) commit(curve *curves.Curve, witness []curves.Scalar) ([]curves.Point, responder) {
+	var commitments []curves.Point
+	responders := make([]responder, len(a.parts))
+	for i, part := range a.parts {
+		var partCommitments []curves.Point
+		partCommitments, responders[i] = part.commit(curve, witness)
+		commitments = append(commitments, partCommitments...)
+	}
+	return commitments, func(c curves.Scalar) []curves.Scalar {
+		var responses []curves.Scalar
+		for _, respond := range responders {
+			responses = append(responses, respond(c)...)
+		}
+		return responses
+	}
+}
+
+func (a *and) simulate(curve *curves.Curve, c curves.Scalar) ([]curves.Point, []curves.Scalar) {
+	var commitments []curves.Point
+	var responses []curves.Scalar
+	for _, part := range a.parts {
+		partCommitments, partResponses := part.simulate(curve, c)
+		commitments = append(commitments, partCommitments...)
+		responses = append(responses, partResponses...)
+	}
+	return commitments, responses
+}
+
+func (a *and) recompute(c curves.Scalar, responses []curves.Scalar) ([]curves.Point, []curves.Scalar, error) {
+	var commitments []curves.Point
+	for _, part := range a.parts {
+		partCommitments, rest, err := part.recompute(c, responses)
+		if err != nil {
+			return nil, nil, err
+		}
+		commitments = append(commitments, partCommitments...)
+		responses = rest
+	}
+	return commitments, responses, nil
+}
+
+// or is the disjunction of statements
+type or struct {
+	branches []Statement
+}
+
+// Or returns the statement that at least one of the given statements holds. The prover proves the first branch whose
+// witnesses it knows and simulates the others, and the proof does not reveal which branch is true.
+func Or(statements ...Statement) Statement {
+	return &or{branches: statements}
+}
+
+// Public returns the points that the challenge binds, in order
+func (o *or) Public() []curves.Point {
+	return public(o.branches)
+}
+
+func (o *or) labels() []string {
+	return labels(o.branches)
+}
+
+func (o *or) check(witnesses int) error {
+	return check(o.branches, witnesses)
+}
+
+func (o *or) known(witness []curves.Scalar) bool {
+	return o.real(witness) >= 0
+}
+
+// real returns the index of the first branch that the prover knows the witnesses of, or -1
+func (o *or) real(witness []curves.Scalar) int {
+	for i, branch := range o.branches {
+		if branch.known(witness) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (o *or) commit(curve *curves.Curve, witness []curves.Scalar) ([]curves.Point, responder) {
+	real := o.real(witness)
+	challenges := make([]curves.Scalar, len(o.branches))
+	responses := make([][]curves.Scalar, len(o.branches))
+	var commitments []curves.Point
+	var respondReal responder
+	for i, branch := range o.branches {
+		var branchCommitments []curves.Point
+		if i == real {
+			branchCommitments, respondReal = branch.commit(curve, witness)
+		} else {
+			challenges[i] = curve.Scalar.Random(rand.Reader)
+			branchCommitments, responses[i] = branch.simulate(curve, challenges[i])
+		}
+		commitments = append(commitments, branchCommitments...)
+	}
+	return commitments, func(c curves.Scalar) []curves.Scalar {
+		// the challenges of the branches add up to c
+		challenges[real] = c
+		for i, ci := range challenges {
+			if i != real {
+				challenges[real] = challenges[real].Sub(ci)
+			}
+		}
+		responses[real] = respondReal(challenges[real])
+		return o.responses(challenges, responses)
+	}
+}
+
+func (o *or) simulate(curve *curves.Curve, c curves.Scalar) ([]curves.Point, []curves.Scalar) {
+	challenges := make([]curves.Scalar, len(o.branches))
+	responses := make([][]curves.Scalar, len(o.branches))
+	last := len(o.branches) - 1
+	challenges[last] = c
+	for i := 0; i < last; i++ {
+		challenges[i] = curve.Scalar.Random(rand.Reader)
+		challenges[last] = challenges[last].Sub(challenges[i])
+	}
+	var commitments []curves.Point
+	for i, branch := range o.branches {
+		var branchCommitments []curves.Point
+		branchCommitments, responses[i] = branch.simulate(curve, challenges[i])
+		commitments = append(commitments, branchCommitments...)
+	}
+	return commitments, o.responses(challenges, responses)
+}
+
+// responses lists the challenges of all the branches but the last, followed by the responses of every branch
+func (o *or) responses(challenges []curves.Scalar, responses [][]curves.Scalar) []curves.Scalar {
+	result := append([]curves.Scalar{}, challenges[:len(challenges)-1]...)
+	for _, branchResponses := range responses {
+		result = append(result, branchResponses...)
+	}
+	return result
+}
+
+func (o *or) recompute(c curves.Scalar, responses []curves.Scalar) ([]curves.Point, []curves.Scalar, error) {
+	last := len(o.branches) - 1
+	if len(responses) < last {
+		return nil, nil, fmt.Errorf("not enough responses")
+	}
+	challenges := append(responses[:last:last], c)
+	for i := 0; i < last; i++ {
+		challenges[last] = challenges[last].Sub(challenges[i])
+	}
+	responses = responses[last:]
+	var commitments []curves.Point
+	for i, branch := range o.branches {
+		branchCommitments, rest, err := branch.recompute(challenges[i], responses)
+		if err != nil {
+			return nil, nil, err
+		}
+		commitments = append(commitments, branchCommitments...)
+		responses = rest
+	}
+	return commitments, responses, nil
+}
+
+// public concatenates the public points of the statements
+func public(statements []Statement) []curves.Point {
+	var points []curves.Point
+	for _, s := range statements {
+		points = append(points, s.Public()...)
+	}
+	return points
+}
+
+// labels concatenates the labels of the statements
+func labels(statements []Statement) []string {
+	var result []string
+	for _, s := range statements {
+		result = append(result, s.labels()...)
+	}
+	return result
+}
+
+// check validates every statement of a composition
+func check(statements []Statement, witnesses int) error {
+	if len(statements) == 0 {
+		return fmt.Errorf("composition of no statements")
+	}
+	for i, s := range statements {
+		if s == nil {
+			return fmt.Errorf("statement %d missing", i)
+		}
+		if err := s.check(witnesses); err != nil {
+			return errors.Wrapf(err, "statement %d", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+//
+// Copyright Coinbase, Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package sigma
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/gtank/merlin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coinbase/kryptology/pkg/core/curves"
+)
+
+var sessionId = []byte("sigma test session")
+
+// dlog is the knowledge of the discrete log with the given index of x with respect to g
+func dlog(g curves.Point, x curves.Point, witness int) *Relation {
+	return &Relation{Equations: []*Equation{{Image: x, Terms: []Term{{Base: g, Witness: witness}}}}}
+}
+
+func TestRelation(t *testing.T) {
+	for _, curve := range []*curves.Curve{curves.K256(), curves.P256()} {
+		g, h := curve.NewGeneratorPoint(), curve.Point.Random(rand.Reader)
+		m, r := curve.Scalar.Random(rand.Reader), curve.Scalar.Random(rand.Reader)
+		// a pedersen commitment and the equality of the discrete log of its blinding
+		statement := &Relation{Equations: []*Equation{
+			{Image: g.Mul(m).Add(h.Mul(r)), Terms: []Term{{Base: g, Witness: 0}, {Base: h, Witness: 1}}},
+			{Image: h.Mul(r), Terms: []Term{{Base: h, Witness: 1}}},
+		}}
+		witness := []curves.Scalar{m, r}
+
+		proof, err := Prove(curve, statement, witness, sessionId)
+		require.NoError(t, err, curve.Name)
+		require.Len(t, proof.Responses, 2)
+		require.NoError(t, Verify(curve, statement, proof, sessionId), curve.Name)
+
+		require.Error(t, Verify(curve, statement, proof, []byte("other session")))
+		require.Error(t, Verify(curve, statement, &Proof{C: proof.C, Responses: proof.Responses[:1]}, sessionId))
+		require.Error(t, Verify(curve, statement, &Proof{C: proof.C, Responses: append(proof.Responses, proof.C)}, sessionId))
+		tampered := &Proof{C: proof.C, Responses: []curves.Scalar{proof.Responses[1], proof.Responses[0]}}
+		require.Error(t, Verify(curve, statement, tampered, sessionId))
+
+		// a false statement does not verify
+		wrong := &Relation{Equations: []*Equation{statement.Equations[0], {Image: h.Mul(m), Terms: []Term{{Base: h, Witness: 1}}}}}
+		proof, err = Prove(curve, wrong, witness, sessionId)
+		require.NoError(t, err)
+		require.Error(t, Verify(curve, wrong, proof, sessionId))
+	}
+}
+
+func TestRelationTranscript(t *testing.T) {
+	curve := curves.K256()
+	g, h := curve.NewGeneratorPoint(), curve.Point.Random(rand.Reader)
+	x := curve.Scalar.Random(rand.Reader)
+	statement := &Relation{Equations: []*Equation{
+		{Image: g.Mul(x), Terms: []Term{{Base: g, Witness: 0}}},
+		{Image: h.Mul(x), Terms: []Term{{Base: h, Witness: 0}, {Base: g, Witness: 1}}},
+	}}
+	public := statement.Public()
+	require.Len(t, public, 4)
+	require.True(t, public[0].Equal(g))
+	require.True(t, public[1].Equal(h))
+	require.True(t, public[2].Equal(statement.Equations[0].Image))
+	require.True(t, public[3].Equal(statement.Equations[1].Image))
+
+	// a proof under one transcript does not verify under another
+	proof, err := Prove(curve, statement, []curves.Scalar{x, curve.Scalar.Zero()}, sessionId)
+	require.NoError(t, err)
+	require.NoError(t, Verify(curve, statement, proof, sessionId))
+	statement.Transcript = []curves.Point{h, g}
+	require.Error(t, Verify(curve, statement, proof, sessionId))
+}
+
+func TestAnd(t *testing.T) {
+	curve := curves.K256()
+	g := curve.NewGeneratorPoint()
+	x, y := curve.Scalar.Random(rand.Reader), curve.Scalar.Random(rand.Reader)
+	statement := And(dlog(g, g.Mul(x), 0), dlog(g, g.Mul(y), 1))
+
+	proof, err := Prove(curve, statement, []curves.Scalar{x, y}, sessionId)
+	require.NoError(t, err)
+	require.Len(t, proof.Responses, 2)
+	require.NoError(t, Verify(curve, statement, proof, sessionId))
+
+	_, err = Prove(curve, statement, []curves.Scalar{x, nil}, sessionId)
+	require.Error(t, err)
+	_, err = Prove(curve, statement, []curves.Scalar{x}, sessionId)
+	require.Error(t, err)
+}
+
+func TestOr(t *testing.T) {
+	curve := curves.K256()
+	g := curve.NewGeneratorPoint()
+	const branches = 4
+	secrets := make([]curves.Scalar, branches)
+	statements := make([]Statement, branches)
+	for i := range secrets {
+		secrets[i] = curve.Scalar.Random(rand.Reader)
+		statements[i] = dlog(g, g.Mul(secrets[i]), i)
+	}
+	statement := Or(statements...)
+
+	// the prover knows any one of the discrete logs
+	for i := range secrets {
+		witness := make([]curves.Scalar, branches)
+		witness[i] = secrets[i]
+		proof, err := Prove(curve, statement, witness, sessionId)
+		require.NoError(t, err, fmt.Sprintf("branch %d", i))
+		require.Len(t, proof.Responses, 2*branches-1)
+		require.NoError(t, Verify(curve, statement, proof, sessionId), fmt.Sprintf("branch %d", i))
+	}
+
+	_, err := Prove(curve, statement, make([]curves.Scalar, branches), sessionId)
+	require.Error(t, err)
+
+	// the prover cannot simulate every branch
+	witness := make([]curves.Scalar, branches)
+	witness[0] = curve.Scalar.Random(rand.Reader)
+	proof, err := Prove(curve, statement, witness, sessionId)
+	require.NoError(t, err)
+	require.Error(t, Verify(curve, statement, proof, sessionId))
+
+	// the branch challenges must add up to the challenge
+	witness[0] = secrets[0]
+	proof, err = Prove(curve, statement, witness, sessionId)
+	require.NoError(t, err)
+	proof.Responses[0] = proof.Responses[0].Add(curve.Scalar.One())
+	require.Error(t, Verify(curve, statement, proof, sessionId))
+}
+
+func TestComposition(t *testing.T) {
+	curve := curves.P256()
+	g, h := curve.NewGeneratorPoint(), curve.Point.Random(rand.Reader)
+	x, y, z := curve.Scalar.Random(rand.Reader), curve.Scalar.Random(rand.Reader), curve.Scalar.Random(rand.Reader)
+	// (x is the discrete log of X and of Y) AND (z is the discrete log of Z OR of W), knowing the one of Z only
+	equality := &Relation{Equations: []*Equation{
+		{Image: g.Mul(x), Terms: []Term{{Base: g, Witness: 0}}},
+		{Image: h.Mul(x), Terms: []Term{{Base: h, Witness: 0}}},
+	}}
+	either := Or(dlog(g, g.Mul(z), 1), dlog(h, h.Mul(y), 2))
+	statement := And(equality, either)
+	witness := []curves.Scalar{x, z, nil}
+
+	proof, err := Prove(curve, statement, witness, sessionId)
+	require.NoError(t, err)
+	require.NoError(t, Verify(curve, statement, proof, sessionId))
+	require.Len(t, statement.Public(), 2+2+2+2)
+
+	// the nested disjunction works as a branch too
+	statement = Or(And(dlog(g, g.Mul(y), 2), dlog(h, h.Mul(y), 2)), either)
+	proof, err = Prove(curve, statement, witness, sessionId)
+	require.NoError(t, err)
+	require.NoError(t, Verify(curve, statement, proof, sessionId))
+}
+
+func TestInvalidStatements(t *testing.T) {
+	curve := curves.K256()
+	g := curve.NewGeneratorPoint()
+	x := curve.Scalar.Random(rand.Reader)
+	witness := []curves.Scalar{x}
+	for i, statement := range []Statement{
+		&Relation{},
+		&Relation{Equations: []*Equation{{Image: g}}},
+		&Relation{Equations: []*Equation{{Terms: []Term{{Base: g}}}}},
+		&Relation{Equations: []*Equation{{Image: g, Terms: []Term{{Witness: 0}}}}},
+		&Relation{Equations: []*Equation{{Image: g, Terms: []Term{{Base: g, Witness: -1}}}}},
+		dlog(g, g, 1),
+		And(),
+		Or(dlog(g, g, 0), nil),
+	} {
+		_, err := Prove(curve, statement, witness, sessionId)
+		require.Error(t, err, fmt.Sprintf("statement %d", i))
+	}
+	_, err := Prove(curve, nil, witness, sessionId)
+	require.Error(t, err)
+	statement := dlog(g, g.Mul(x), 0)
+	require.Error(t, Verify(curve, statement, nil, sessionId))
+	require.Error(t, Verify(curve, statement, &Proof{C: x, Responses: []curves.Scalar{nil}}, sessionId))
+}
+
+func TestTranscript(t *testing.T) {
+	newTranscript := func() *merlin.Transcript {
+		transcript := merlin.NewTranscript("sigma test")
+		transcript.AppendMessage([]byte("session id"), sessionId)
+		return transcript
+	}
+	// the wide challenge also works on the curves whose scalars do not fit in a digest
+	for _, curve := range []*curves.Curve{curves.K256(), curves.P256(), curves.ED25519(), curves.BLS12381G1()} {
+		g, h := curve.NewGeneratorPoint(), curve.Point.Random(rand.Reader)
+		x := curve.Scalar.Random(rand.Reader)
+		statement := &Relation{
+			Equations: []*Equation{
+				{Image: g.Mul(x), Terms: []Term{{Base: g, Witness: 0}}},
+				{Image: h.Mul(x), Terms: []Term{{Base: h, Witness: 0}}},
+			},
+			Labels: []string{"g", "h", "X", "Y"},
+		}
+		witness := []curves.Scalar{x}
+
+		proof, err := ProveTranscript(curve, "test protocol", statement, witness, newTranscript())
+		require.NoError(t, err, curve.Name)
+		require.NoError(t, VerifyTranscript(curve, "test protocol", statement, proof, newTranscript()), curve.Name)
+
+		// the proof is bound to the protocol label and to the state of the transcript
+		require.Error(t, VerifyTranscript(curve, "other protocol", statement, proof, newTranscript()))
+		require.Error(t, VerifyTranscript(curve, "test protocol", statement, proof, merlin.NewTranscript("sigma test")))
+		require.Error(t, Verify(curve, statement, proof, sessionId))
+
+		// proofs chained on a transcript verify in the same order only
+		prover, verifier, reordered := newTranscript(), newTranscript(), newTranscript()
+		first, err := ProveTranscript(curve, "test protocol", statement, witness, prover)
+		require.NoError(t, err)
+		second, err := ProveTranscript(curve, "test protocol", statement, witness, prover)
+		require.NoError(t, err)
+		require.NoError(t, VerifyTranscript(curve, "test protocol", statement, first, verifier))
+		require.NoError(t, VerifyTranscript(curve, "test protocol", statement, second, verifier))
+		require.Error(t, VerifyTranscript(curve, "test protocol", statement, second, reordered))
+	}
+
+	curve := curves.K256()
+	g := curve.NewGeneratorPoint()
+	statement := &Relation{Equations: []*Equation{{Image: g, Terms: []Term{{Base: g}}}}, Labels: []string{"g"}}
+	_, err := ProveTranscript(curve, "test protocol", statement, []curves.Scalar{curve.Scalar.One()}, newTranscript())
+	require.Error(t, err)
+	statement.Labels = nil
+	_, err = ProveTranscript(curve, "test protocol", statement, []curves.Scalar{curve.Scalar.One()}, nil)
+	require.Error(t, err)
+}
+
+func TestBatchVerify(t *testing.T) {
+	curve := curves.K256()
+	g, h := curve.NewGeneratorPoint(), curve.Point.Random(rand.Reader)
+	const n = 10
+	statements := make([]*Relation, n)
+	proofs := make([]*Proof, n)
+	sessionIds := make([][]byte, n)
+	for i := range proofs {
+		x := curve.Scalar.Random(rand.Reader)
+		statements[i] = &Relation{Equations: []*Equation{
+			{Image: g.Mul(x), Terms: []Term{{Base: g, Witness: 0}}},
+			{Image: h.Mul(x), Terms: []Term{{Base: h, Witness: 0}}},
+		}}
+		sessionIds[i] = []byte(fmt.Sprintf("session %d", i))
+		var err error
+		proofs[i], err = Prove(curve, statements[i], []curves.Scalar{x}, sessionIds[i])
+		require.NoError(t, err)
+		require.Len(t, proofs[i].Commitments, 2)
+	}
+	require.NoError(t, BatchVerify(curve, statements, proofs, sessionIds))
+	require.NoError(t, BatchVerify(curve, nil, nil, nil))
+
+	// proofs without their commitments, or with wrong ones, are verified one by one
+	proofs[2] = &Proof{C: proofs[2].C, Responses: proofs[2].Responses}
+	proofs[3] = &Proof{C: proofs[3].C, Responses: proofs[3].Responses, Commitments: []curves.Point{g, h}}
+	require.NoError(t, BatchVerify(curve, statements, proofs, sessionIds))
+
+	// the first invalid proof is reported
+	valid := proofs[7]
+	proofs[7] = &Proof{C: valid.C, Responses: []curves.Scalar{valid.Responses[0].Add(curve.Scalar.One())}, Commitments: valid.Commitments}
+	err := BatchVerify(curve, statements, proofs, sessionIds)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "proof 7")
+	proofs[7] = valid
+
+	// the statements must match the proofs
+	statements[4], statements[5] = statements[5], statements[4]
+	err = BatchVerify(curve, statements, proofs, sessionIds)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "proof 4")
+	require.Error(t, BatchVerify(curve, statements, proofs, sessionIds[1:]))
+}
@@ -0,0 +1,19 @@
+// Package zkptest contains utilities to test the zkp packages. The main goal is to reduce the code duplication in the
+// tests of the proofs that draw their challenges from a merlin transcript.
+package zkptest
+
+import (
+	"github.com/gtank/merlin"
+	"golang.org/x/crypto/sha3"
+)
+
+// SessionId is the session id that NewTranscript appends to every transcript
+var SessionId = sha3.New256().Sum([]byte("random seed"))
+
+// NewTranscript is a utility function used _only_ during tests. It starts a transcript with the given protocol label
+// and appends SessionId to it, as a protocol would append its own session id before the first proof.
+func NewTranscript(label string) *merlin.Transcript {
+	transcript := merlin.NewTranscript(label)
+	transcript.AppendMessage([]byte("session id"), SessionId)
+	return transcript
+}